@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kadirpekel/hector/pkg/config"
+)
+
+// ConfigCmd groups commands that operate on configuration files.
+type ConfigCmd struct {
+	Diff ConfigDiffCmd `cmd:"" help:"Show agents, LLMs and tools added, removed or changed between two config files."`
+}
+
+// ConfigDiffCmd compares two configuration files.
+// Useful for reviewing a hot-reload change before saving it.
+type ConfigDiffCmd struct {
+	Old string `arg:"" name:"old" help:"Original configuration file." placeholder:"OLD"`
+	New string `arg:"" name:"new" help:"Updated configuration file." placeholder:"NEW"`
+
+	// Format specifies the output format
+	Format string `short:"f" help:"Output format: text, json." default:"text" enum:"text,json"`
+}
+
+// Run executes the config diff command.
+func (c *ConfigDiffCmd) Run(cli *CLI) error {
+	oldCfg, err := loadForDiff(c.Old)
+	if err != nil {
+		return err
+	}
+	newCfg, err := loadForDiff(c.New)
+	if err != nil {
+		return err
+	}
+
+	diff := config.Diff(oldCfg, newCfg)
+
+	if c.Format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(diff)
+	}
+
+	if !diff.Changed() {
+		fmt.Println("No changes to agents, llms or tools.")
+		return nil
+	}
+
+	section := ""
+	for _, ch := range diff.Changes {
+		if ch.Section != section {
+			section = ch.Section
+			fmt.Printf("%s:\n", section)
+		}
+		switch ch.Kind {
+		case config.ChangeAdded:
+			fmt.Printf("  + %s\n", ch.Name)
+		case config.ChangeRemoved:
+			fmt.Printf("  - %s\n", ch.Name)
+		default:
+			fmt.Printf("  ~ %s (%s)\n", ch.Name, strings.Join(ch.Fields, ", "))
+		}
+	}
+	return nil
+}
+
+// loadForDiff loads and validates a config file, with defaults applied so
+// that spelling out a default doesn't show up as a change.
+func loadForDiff(path string) (*config.Config, error) {
+	_ = config.LoadDotEnvForConfig(path)
+
+	cfg, loader, err := config.LoadConfigFile(context.Background(), path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", path, err)
+	}
+	if loader != nil {
+		loader.Close()
+	}
+	return cfg, nil
+}
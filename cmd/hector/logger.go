@@ -18,6 +18,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/kadirpekel/hector/pkg/config"
 	"github.com/kadirpekel/hector/pkg/logger"
@@ -33,6 +34,8 @@ const (
 	LogLevelEnvVar = "LOG_LEVEL"
 	// LogFormatEnvVar is the environment variable name for log format
 	LogFormatEnvVar = "LOG_FORMAT"
+	// LogSamplingEnvVar is the environment variable name for log sampling
+	LogSamplingEnvVar = "LOG_SAMPLING"
 	// DefaultLogFormat is the default log format
 	DefaultLogFormat = "simple"
 )
@@ -40,7 +43,7 @@ const (
 // initLoggerFromCLI initializes the logger from CLI flags and environment variables.
 // Priority: CLI flags > env vars > defaults
 // Returns: level string, file string, format string, cleanup function, error
-func initLoggerFromCLI(cliLogLevel, cliLogFile, cliLogFormat string) (string, string, string, func(), error) {
+func initLoggerFromCLI(cliLogLevel, cliLogFile, cliLogFormat string, cliLogSampling int) (string, string, string, func(), error) {
 	// Determine log level: CLI flag > env var > default
 	logLevel := cliLogLevel
 	if logLevel == "" {
@@ -65,6 +68,21 @@ func initLoggerFromCLI(cliLogLevel, cliLogFile, cliLogFormat string) (string, st
 		logFormat = DefaultLogFormat
 	}
 
+	// Determine log sampling: CLI flag > env var > default (off)
+	logSampling := cliLogSampling
+	if logSampling == 0 {
+		if env := os.Getenv(LogSamplingEnvVar); env != "" {
+			n, err := strconv.Atoi(env)
+			if err != nil {
+				return "", "", "", nil, fmt.Errorf("invalid %s: %w", LogSamplingEnvVar, err)
+			}
+			logSampling = n
+		}
+	}
+	if logSampling < 0 {
+		return "", "", "", nil, fmt.Errorf("invalid log sampling %d: must be non-negative", logSampling)
+	}
+
 	// Parse level
 	level, err := logger.ParseLevel(logLevel)
 	if err != nil {
@@ -87,7 +105,7 @@ func initLoggerFromCLI(cliLogLevel, cliLogFile, cliLogFormat string) (string, st
 	}
 
 	// Initialize logger
-	logger.Init(level, output, logFormat)
+	logger.Init(level, output, logFormat, logger.WithSampling(logSampling))
 
 	return logLevel, logFile, logFormat, cleanup, nil
 }
@@ -136,7 +154,7 @@ func initLoggerFromConfig(cfg *config.LoggerConfig) (string, string, string, fun
 	}
 
 	// Initialize logger
-	logger.Init(level, output, logFormat)
+	logger.Init(level, output, logFormat, logger.WithSampling(cfg.Sampling))
 
 	return logLevel, logFile, logFormat, cleanup, nil
 }
@@ -20,6 +20,7 @@
 //	hector serve --config config.yaml
 //	hector serve --provider anthropic --model claude-sonnet-4-20250514
 //	hector info --config config.yaml --agent assistant
+//	hector tools list
 package main
 
 import (
@@ -50,12 +51,16 @@ type CLI struct {
 	Serve    ServeCmd    `cmd:"" help:"Start the A2A server."`
 	Info     InfoCmd     `cmd:"" help:"Show agent information."`
 	Validate ValidateCmd `cmd:"" help:"Validate configuration file."`
+	Plan     PlanCmd     `cmd:"" help:"Show what the runtime would build from the config, without connecting to anything."`
 	Schema   SchemaCmd   `cmd:"" help:"Generate JSON Schema for config builder."`
-
-	Config    string `short:"c" help:"Path to config file." type:"path"`
-	LogLevel  string `help:"Log level (debug, info, warn, error)." default:"info"`
-	LogFile   string `help:"Log file path (empty = stderr)."`
-	LogFormat string `help:"Log format (simple, verbose, or custom)." default:"simple"`
+	Tools    ToolsCmd    `cmd:"" help:"List and describe built-in tools."`
+	Cfg      ConfigCmd   `cmd:"" name:"config" help:"Inspect and compare configuration files."`
+
+	Config      string `short:"c" help:"Path to config file." type:"path"`
+	LogLevel    string `help:"Log level (debug, info, warn, error)." default:"info"`
+	LogFile     string `help:"Log file path (empty = stderr)."`
+	LogFormat   string `help:"Log format (simple, verbose, or custom)." default:"simple"`
+	LogSampling int    `help:"Log only the first and then every Nth occurrence of each debug/info message (0 = off)."`
 }
 
 // marshalYAMLWithIndent marshals a value to YAML with explicit 2-space indentation.
@@ -89,7 +94,7 @@ func (c *VersionCmd) Run() error {
 // ServeCmd starts the A2A server.
 type ServeCmd struct {
 	// Zero-config options
-	Provider       string  `help:"LLM provider (anthropic, openai, gemini, ollama)."`
+	Provider       string  `help:"LLM provider (anthropic, openai, gemini, ollama, cohere, bedrock)."`
 	Model          string  `help:"Model name."`
 	APIKey         string  `name:"api-key" help:"API key (defaults to environment variable)."`
 	BaseURL        string  `name:"base-url" help:"Custom API base URL."`
@@ -123,7 +128,7 @@ type ServeCmd struct {
 	VectorAPIKey string `name:"vector-api-key" help:"Vector database API key (for pinecone, authenticated qdrant)." placeholder:"KEY"`
 
 	// Embedder options
-	EmbedderProvider string `name:"embedder-provider" help:"Embedder provider: openai, ollama, cohere (auto-detected: openai if available, else ollama)." placeholder:"PROVIDER"`
+	EmbedderProvider string `name:"embedder-provider" help:"Embedder provider: openai, ollama, cohere, voyage (auto-detected: openai if available, else ollama)." placeholder:"PROVIDER"`
 	EmbedderModel    string `name:"embedder-model" help:"Embedder model (auto-detected from provider)." placeholder:"MODEL"`
 	EmbedderURL      string `name:"embedder-url" help:"Embedder API base URL (for custom ollama/OpenAI-compatible endpoints)." placeholder:"URL"`
 
@@ -181,7 +186,7 @@ func (c *ServeCmd) Run(cli *CLI) error {
 	}
 
 	// Build runtime with session service
-	rt, err := runtime.New(cfg, runtime.WithSessionService(sessionSvc))
+	rt, err := runtime.New(cfg, runtime.WithSessionService(sessionSvc), runtime.WithDBPool(dbPool))
 	if err != nil {
 		return fmt.Errorf("failed to create runtime: %w", err)
 	}
@@ -196,6 +201,7 @@ func (c *ServeCmd) Run(cli *CLI) error {
 		}
 		executors[agentName] = server.NewExecutor(server.ExecutorConfig{
 			RunnerConfig: *runnerCfg,
+			StreamUsage:  cfg.Server.StreamUsage,
 		})
 	}
 
@@ -277,6 +283,7 @@ func (c *ServeCmd) Run(cli *CLI) error {
 				}
 				newExecutors[agentName] = server.NewExecutor(server.ExecutorConfig{
 					RunnerConfig: *runnerCfg,
+					StreamUsage:  newCfg.Server.StreamUsage,
 				})
 			}
 
@@ -301,10 +308,13 @@ func (c *ServeCmd) Run(cli *CLI) error {
 	greenColor := "\033[38;2;16;185;129m"
 	resetColor := "\033[0m"
 	fmt.Printf("\n%s🚀 Hector pkg server ready!%s\n", greenColor, resetColor)
-	fmt.Printf("   Web UI:      http://%s\n", srv.Address())
-	fmt.Printf("   Agent Card:  http://%s/.well-known/agent-card.json\n", srv.Address())
-	fmt.Printf("   Discovery:   http://%s/agents\n", srv.Address())
-	fmt.Printf("   Health:      http://%s/health\n", srv.Address())
+	if socket := cfg.Server.UnixSocket(); socket != "" {
+		fmt.Printf("   Socket:      %s\n", socket)
+	}
+	fmt.Printf("   Web UI:      %s\n", srv.URL())
+	fmt.Printf("   Agent Card:  %s/.well-known/agent-card.json\n", srv.URL())
+	fmt.Printf("   Discovery:   %s/agents\n", srv.URL())
+	fmt.Printf("   Health:      %s/health\n", srv.URL())
 	if cfg.Server.Transport == config.TransportGRPC {
 		fmt.Printf("   gRPC:        %s\n", srv.GRPCAddress())
 	}
@@ -334,7 +344,7 @@ func (c *ServeCmd) Run(cli *CLI) error {
 			fmt.Printf("   Tracing:     %s (%s)\n", cfg.Server.Observability.Tracing.Exporter, cfg.Server.Observability.Tracing.Endpoint)
 		}
 		if cfg.Server.Observability.Metrics.Enabled {
-			fmt.Printf("   Metrics:     http://%s/metrics\n", srv.Address())
+			fmt.Printf("   Metrics:     %s/metrics\n", srv.URL())
 		}
 	}
 
@@ -366,7 +376,7 @@ func (c *ServeCmd) Run(cli *CLI) error {
 
 	fmt.Println("\n   Agents (A2A JSON-RPC endpoints):")
 	for _, name := range cfg.ListAgents() {
-		fmt.Printf("     - http://%s/agents/%s\n", srv.Address(), name)
+		fmt.Printf("     - %s/agents/%s\n", srv.URL(), name)
 	}
 	fmt.Println("\nPress Ctrl+C to stop")
 
@@ -642,7 +652,7 @@ func printBanner() {
 }
 
 // shouldSkipBanner checks if command should skip banner
-// In pkg, "info", "validate", and "schema" commands skip banner (they're informational, not server)
+// In pkg, "info", "validate", "schema", and "tools" commands skip banner (they're informational, not server)
 func shouldSkipBanner(args []string) bool {
 	if len(args) < 2 {
 		return false
@@ -651,7 +661,7 @@ func shouldSkipBanner(args []string) bool {
 	// Check for informational commands
 	for _, arg := range args {
 		// Skip program name and flags, look for commands
-		if arg == "info" || arg == "validate" || arg == "schema" {
+		if arg == "info" || arg == "validate" || arg == "schema" || arg == "tools" {
 			return true
 		}
 	}
@@ -691,7 +701,7 @@ func main() {
 
 	// Initialize logger with CLI flags/env vars (before config loading)
 	// Config file logger settings will be applied later if no CLI/env overrides
-	_, _, _, cleanup, err := initLoggerFromCLI(cli.LogLevel, cli.LogFile, cli.LogFormat)
+	_, _, _, cleanup, err := initLoggerFromCLI(cli.LogLevel, cli.LogFile, cli.LogFormat, cli.LogSampling)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
 		os.Exit(1)
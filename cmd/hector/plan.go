@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kadirpekel/hector/pkg/config"
+	"github.com/kadirpekel/hector/pkg/runtime"
+)
+
+// PlanCmd prints what the runtime would build from the config, offline.
+type PlanCmd struct {
+	// Format specifies the output format
+	Format string `short:"f" help:"Output format: text, json." default:"text" enum:"text,json"`
+}
+
+// Run executes the plan command.
+func (c *PlanCmd) Run(cli *CLI) error {
+	if cli.Config == "" {
+		return fmt.Errorf("--config is required for plan command")
+	}
+
+	_ = config.LoadDotEnvForConfig(cli.Config)
+	cfg, loader, err := config.LoadConfigFile(context.Background(), cli.Config)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	defer loader.Close()
+
+	plan, err := runtime.Plan(cfg)
+	if err != nil {
+		return err
+	}
+
+	if c.Format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(plan)
+	}
+
+	printPlan(plan)
+	return nil
+}
+
+// printPlan prints the plan as an indented, human-readable listing.
+func printPlan(plan *runtime.BuildPlan) {
+	if len(plan.LLMs) > 0 {
+		fmt.Println("LLMs:")
+		for _, l := range plan.LLMs {
+			line := fmt.Sprintf("  %-20s %s/%s", l.Name, l.Provider, l.Model)
+			if l.Shadow != "" {
+				line += fmt.Sprintf(" (shadow: %s)", l.Shadow)
+			}
+			fmt.Println(line)
+		}
+	}
+
+	if len(plan.Embedders) > 0 {
+		fmt.Println("Embedders:")
+		for _, e := range plan.Embedders {
+			fmt.Printf("  %-20s %s/%s\n", e.Name, e.Provider, e.Model)
+		}
+	}
+
+	if len(plan.VectorStores) > 0 {
+		fmt.Println("Vector stores:")
+		for _, v := range plan.VectorStores {
+			fmt.Printf("  %-20s %s\n", v.Name, v.Type)
+		}
+	}
+
+	if len(plan.Tools) > 0 {
+		fmt.Println("Tools:")
+		for _, t := range plan.Tools {
+			line := fmt.Sprintf("  %-20s %s", t.Name, t.Type)
+			switch {
+			case t.Handler != "":
+				line += " " + t.Handler
+			case t.Server != "":
+				line += " " + t.Server
+			}
+			switch {
+			case t.Disabled:
+				line += " (disabled)"
+			case t.Lazy:
+				line += " (lazy: tools listed on first use)"
+			case len(t.Tools) > 0:
+				line += " [" + strings.Join(t.Tools, ", ") + "]"
+			}
+			if t.RequiresApproval {
+				line += " (requires approval)"
+			}
+			fmt.Println(line)
+		}
+	}
+
+	if len(plan.DocumentStores) > 0 {
+		fmt.Println("Document stores:")
+		for _, d := range plan.DocumentStores {
+			fmt.Printf("  %-20s %s -> vector_store=%s embedder=%s\n", d.Name, d.Source, d.VectorStore, d.Embedder)
+		}
+	}
+
+	fmt.Println("Agents (build order):")
+	for _, a := range plan.Agents {
+		line := fmt.Sprintf("  %-20s %s", a.Name, a.Type)
+		switch {
+		case a.LLM != "":
+			line += " (" + a.LLM + ")"
+		case a.URL != "":
+			line += " (" + a.URL + ")"
+		}
+		fmt.Println(line)
+
+		if len(a.Tools) > 0 {
+			names := make([]string, len(a.Tools))
+			for i, t := range a.Tools {
+				names[i] = t.Toolset
+				if t.Tool != "" {
+					names[i] += "/" + t.Tool
+				}
+			}
+			fmt.Printf("    tools:           %s\n", strings.Join(names, ", "))
+		}
+		if len(a.DocumentStores) > 0 {
+			fmt.Printf("    document_stores: %s\n", strings.Join(a.DocumentStores, ", "))
+		}
+		if len(a.SubAgents) > 0 {
+			fmt.Printf("    sub_agents:      %s\n", strings.Join(a.SubAgents, ", "))
+		}
+		if len(a.AgentTools) > 0 {
+			fmt.Printf("    agent_tools:     %s\n", strings.Join(a.AgentTools, ", "))
+		}
+	}
+}
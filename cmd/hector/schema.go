@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/invopop/jsonschema"
 	"github.com/kadirpekel/hector/pkg/config"
@@ -30,6 +31,9 @@ import (
 type SchemaCmd struct {
 	// Compact enables compact JSON output (no indentation)
 	Compact bool `short:"c" help:"Compact JSON output (no indentation)."`
+
+	// Type limits output to a single config struct (e.g. AgentConfig)
+	Type string `short:"t" help:"Emit the schema of a single config struct (e.g. AgentConfig, LLMConfig, ToolConfig)."`
 }
 
 // Run executes the schema generation command.
@@ -42,6 +46,10 @@ func (c *SchemaCmd) Run(cli *CLI) error {
 		DoNotReference: true,
 	}
 
+	if c.Type != "" {
+		return c.emitType(reflector)
+	}
+
 	// Generate schema from Config struct
 	schema := reflector.Reflect(&config.Config{})
 
@@ -75,7 +83,26 @@ func (c *SchemaCmd) Run(cli *CLI) error {
 		},
 	}
 
-	// Marshal to JSON and write to stdout
+	return c.encode(schema)
+}
+
+// emitType writes the schema of the single struct named by --type.
+func (c *SchemaCmd) emitType(reflector *jsonschema.Reflector) error {
+	v, ok := config.SchemaType(c.Type)
+	if !ok {
+		return fmt.Errorf("unknown config type %q (available: %s)", c.Type, strings.Join(config.SchemaTypeNames(), ", "))
+	}
+
+	schema := reflector.Reflect(v)
+	schema.ID = jsonschema.ID("https://hector.dev/schemas/" + c.Type + ".json")
+	schema.Title = c.Type
+	schema.Version = "http://json-schema.org/draft-07/schema#"
+
+	return c.encode(schema)
+}
+
+// encode marshals the schema to JSON on stdout.
+func (c *SchemaCmd) encode(schema *jsonschema.Schema) error {
 	encoder := json.NewEncoder(os.Stdout)
 	if !c.Compact {
 		encoder.SetIndent("", "  ")
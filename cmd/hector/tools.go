@@ -0,0 +1,203 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/kadirpekel/hector/pkg/config"
+	"github.com/kadirpekel/hector/pkg/runtime"
+	"github.com/kadirpekel/hector/pkg/tool"
+)
+
+// ToolsCmd groups commands for discovering built-in tools.
+type ToolsCmd struct {
+	List     ToolsListCmd     `cmd:"" help:"List built-in local tools (the ones --tools can enable)."`
+	Describe ToolsDescribeCmd `cmd:"" help:"Show details and argument schema for a built-in tool."`
+}
+
+// ToolsListCmd lists built-in local tools.
+type ToolsListCmd struct {
+	JSON bool `name:"json" help:"Output as JSON."`
+}
+
+// ToolsDescribeCmd describes a single built-in tool.
+type ToolsDescribeCmd struct {
+	Name string `arg:"" help:"Tool name."`
+	JSON bool   `name:"json" help:"Output as JSON."`
+}
+
+// builtinToolInfo describes a built-in tool for CLI output.
+type builtinToolInfo struct {
+	Name             string         `json:"name"`
+	Type             string         `json:"type"`
+	Description      string         `json:"description"`
+	RequiresApproval bool           `json:"requires_approval"`
+	Parameters       map[string]any `json:"parameters,omitempty"`
+}
+
+// Run executes the tools list command.
+func (c *ToolsListCmd) Run() error {
+	tools, err := builtinTools()
+	if err != nil {
+		return err
+	}
+
+	if c.JSON {
+		return printJSON(tools)
+	}
+
+	fmt.Println("Built-in tools:")
+	for _, t := range tools {
+		approval := ""
+		if t.RequiresApproval {
+			approval = " (requires approval)"
+		}
+		fmt.Printf("\n  %s%s\n", t.Name, approval)
+		fmt.Printf("    %s\n", t.Description)
+		if args := parameterNames(t.Parameters); len(args) > 0 {
+			fmt.Printf("    Arguments: %s\n", strings.Join(args, ", "))
+		}
+	}
+	fmt.Println("\n(* = required argument)")
+	fmt.Println("Enable with: hector serve --tools <name,...> or list them under an agent's tools in config.")
+	return nil
+}
+
+// Run executes the tools describe command.
+func (c *ToolsDescribeCmd) Run() error {
+	tools, err := builtinTools()
+	if err != nil {
+		return err
+	}
+
+	var found *builtinToolInfo
+	names := make([]string, 0, len(tools))
+	for i := range tools {
+		names = append(names, tools[i].Name)
+		if tools[i].Name == c.Name {
+			found = &tools[i]
+		}
+	}
+	if found == nil {
+		return fmt.Errorf("unknown tool %q (available: %s)", c.Name, strings.Join(names, ", "))
+	}
+
+	if c.JSON {
+		return printJSON(found)
+	}
+
+	fmt.Printf("Tool: %s\n", found.Name)
+	fmt.Printf("Type: %s\n", found.Type)
+	fmt.Printf("Requires approval: %v\n", found.RequiresApproval)
+	fmt.Printf("Description: %s\n", found.Description)
+
+	if len(found.Parameters) > 0 {
+		schema, err := json.MarshalIndent(found.Parameters, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode schema: %w", err)
+		}
+		fmt.Printf("\nArguments schema:\n%s\n", schema)
+	}
+	return nil
+}
+
+// builtinTools instantiates each built-in tool to read its definition.
+// Tool descriptions and schemas come from the tools themselves, so the output
+// always matches what the LLM sees.
+func builtinTools() ([]builtinToolInfo, error) {
+	configs := config.GetDefaultToolConfigs()
+
+	names := make([]string, 0, len(configs))
+	for name := range configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tools := make([]builtinToolInfo, 0, len(names))
+	for _, name := range names {
+		cfg := configs[name]
+		cfg.SetDefaults()
+
+		toolset, err := runtime.DefaultToolsetFactory(name, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create tool %q: %w", name, err)
+		}
+		resolved, err := toolset.Tools(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve tool %q: %w", name, err)
+		}
+
+		for _, t := range resolved {
+			def := tool.ToDefinition(t)
+			tools = append(tools, builtinToolInfo{
+				Name:             name,
+				Type:             string(cfg.Type),
+				Description:      def.Description,
+				RequiresApproval: t.RequiresApproval(),
+				Parameters:       def.Parameters,
+			})
+		}
+	}
+	return tools, nil
+}
+
+// parameterNames returns the argument names from a JSON schema,
+// marking required ones with an asterisk.
+func parameterNames(schema map[string]any) []string {
+	props, _ := schema["properties"].(map[string]any)
+	if len(props) == 0 {
+		return nil
+	}
+
+	required := make(map[string]bool)
+	switch req := schema["required"].(type) {
+	case []string:
+		for _, r := range req {
+			required[r] = true
+		}
+	case []any:
+		for _, r := range req {
+			if s, ok := r.(string); ok {
+				required[s] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(props))
+	for name := range props {
+		if required[name] {
+			names = append(names, name+"*")
+		} else {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func printJSON(v any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("failed to encode JSON: %w", err)
+	}
+	return nil
+}
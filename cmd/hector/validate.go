@@ -18,8 +18,12 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/kadirpekel/hector/pkg/config"
 	"gopkg.in/yaml.v3"
@@ -36,6 +40,12 @@ type ValidateCmd struct {
 
 	// PrintConfig prints the expanded configuration
 	PrintConfig bool `short:"p" name:"print-config" help:"Print the expanded configuration (with defaults applied and env vars resolved)."`
+
+	// Explain prints each effective setting with where its value came from
+	Explain bool `short:"e" help:"Print each effective setting with its source (file, env, secret, default)."`
+
+	// Strict fails on unknown config keys instead of warning about them
+	Strict bool `help:"Fail on unknown config keys (e.g. a misspelled temprature:) instead of warning."`
 }
 
 // Run executes the validate command.
@@ -47,10 +57,24 @@ func (c *ValidateCmd) Run(cli *CLI) error {
 	// pkg adaptation: Use config.LoadDotEnvForConfig
 	_ = config.LoadDotEnvForConfig(c.Config)
 
+	var opts []config.LoaderOption
+	if c.Strict {
+		opts = append(opts, config.WithStrict())
+	}
+
+	// --explain needs the loader to record provenance while resolving
+	if c.Explain {
+		_, prov, err := config.LoadConfigFileWithProvenance(ctx, c.Config, opts...)
+		if err != nil {
+			return printLoadError(c.Format, c.Config, err)
+		}
+		return printExplain(c.Format, c.Config, prov)
+	}
+
 	// Load configuration using pkg's config loader
 	// Legacy used config.LoadConfig with LoaderOptions
 	// pkg adaptation: Use config.LoadConfigFile which handles loading and validation
-	cfg, loader, err := config.LoadConfigFile(ctx, c.Config)
+	cfg, loader, err := config.LoadConfigFile(ctx, c.Config, opts...)
 	if err != nil {
 		return printLoadError(c.Format, c.Config, err)
 	}
@@ -82,6 +106,15 @@ type ValidationError struct {
 // printLoadError prints a configuration load error.
 // Ported line-by-line from legacy.
 func printLoadError(format, file string, err error) error {
+	var unknownErr *config.UnknownKeysError
+	if errors.As(err, &unknownErr) {
+		return printUnknownKeys(format, file, unknownErr)
+	}
+	var missingErr *config.MissingEnvError
+	if errors.As(err, &missingErr) {
+		return printMissingEnv(format, file, missingErr)
+	}
+
 	switch format {
 	case "json":
 		printJSONResult(false, file, []ValidationError{{Type: "load", Message: err.Error()}})
@@ -96,6 +129,54 @@ func printLoadError(format, file string, err error) error {
 	return fmt.Errorf("config load failed")
 }
 
+// printUnknownKeys prints the unknown keys found by --strict, one per line.
+func printUnknownKeys(format, file string, err *config.UnknownKeysError) error {
+	switch format {
+	case "json":
+		errs := make([]ValidationError, len(err.Keys))
+		for i, k := range err.Keys {
+			errs[i] = ValidationError{Type: "unknown_key", Message: k.String()}
+		}
+		printJSONResult(false, file, errs)
+	case "verbose":
+		fmt.Fprintf(os.Stderr, "Unknown Configuration Keys\n")
+		fmt.Fprintf(os.Stderr, "==========================\n\n")
+		fmt.Fprintf(os.Stderr, "File:    %s\n", file)
+		for _, k := range err.Keys {
+			fmt.Fprintf(os.Stderr, "Line %d:  %q is not a field of %s\n", k.Line, k.Key, k.Type)
+		}
+	default: // compact
+		for _, k := range err.Keys {
+			fmt.Fprintf(os.Stderr, "%s:%d: unknown key %q in %s\n", file, k.Line, k.Key, k.Type)
+		}
+	}
+	return fmt.Errorf("config has %d unknown key(s)", len(err.Keys))
+}
+
+// printMissingEnv prints the required ${VAR:?message} variables that are unset.
+func printMissingEnv(format, file string, err *config.MissingEnvError) error {
+	switch format {
+	case "json":
+		errs := make([]ValidationError, len(err.Vars))
+		for i, v := range err.Vars {
+			errs[i] = ValidationError{Type: "missing_env", Message: v.String()}
+		}
+		printJSONResult(false, file, errs)
+	case "verbose":
+		fmt.Fprintf(os.Stderr, "Missing Environment Variables\n")
+		fmt.Fprintf(os.Stderr, "=============================\n\n")
+		fmt.Fprintf(os.Stderr, "File:    %s\n", file)
+		for _, v := range err.Vars {
+			fmt.Fprintf(os.Stderr, "%-20s %s (used by %s)\n", v.Name, v.Message, v.Path)
+		}
+	default: // compact
+		for _, v := range err.Vars {
+			fmt.Fprintf(os.Stderr, "%s: %s: missing env var %s: %s\n", file, v.Path, v.Name, v.Message)
+		}
+	}
+	return fmt.Errorf("config has %d missing required env var(s)", len(err.Vars))
+}
+
 // printProcessError prints a configuration processing error.
 // Ported line-by-line from legacy.
 // Note: In pkg, processing errors are typically caught during LoadConfigFile,
@@ -158,6 +239,61 @@ func printExpandedConfig(format, file string, cfg *config.Config) error {
 	return nil
 }
 
+// explainValue formats a resolved value for display, masking secrets and
+// the credentials of URLs and DSNs.
+func explainValue(rv config.ResolvedValue) any {
+	s, isString := rv.Value.(string)
+	if !isString || s == "" {
+		return rv.Value
+	}
+	if rv.Sensitive {
+		return "********"
+	}
+	if u, err := url.Parse(s); err == nil && u.User != nil {
+		u.User = nil
+		return strings.Replace(u.String(), "://", "://********@", 1)
+	}
+	return s
+}
+
+// printExplain prints each effective setting with its value and source.
+func printExplain(format, file string, prov *config.Provenance) error {
+	values := prov.Values()
+
+	if format == "json" {
+		entries := make([]config.ResolvedValue, len(values))
+		for i, rv := range values {
+			rv.Value = explainValue(rv)
+			entries[i] = rv
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(map[string]any{"valid": true, "file": file, "settings": entries}); err != nil {
+			return fmt.Errorf("failed to encode settings as JSON: %w", err)
+		}
+		return nil
+	}
+
+	if format == "verbose" {
+		fmt.Fprintf(os.Stdout, "Effective Configuration\n")
+		fmt.Fprintf(os.Stdout, "=======================\n\n")
+		fmt.Fprintf(os.Stdout, "File:   %s\n", file)
+		fmt.Fprintf(os.Stdout, "Status: OK Valid\n\n")
+	} else {
+		fmt.Fprintf(os.Stdout, "%s: valid\n", file)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, rv := range values {
+		source := string(rv.Source)
+		if rv.Detail != "" {
+			source += " (" + rv.Detail + ")"
+		}
+		fmt.Fprintf(w, "%s\t%v\t%s\n", rv.Path, explainValue(rv), source)
+	}
+	return w.Flush()
+}
+
 // jsonOutput is the JSON output structure.
 // Ported from legacy.
 type jsonOutput struct {
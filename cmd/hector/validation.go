@@ -214,7 +214,7 @@ func ValidateStudioMode(args []string) error {
 // pkg adaptation: Updated skip commands for pkg command structure.
 func ShouldSkipValidation(args []string) bool {
 	// Skip validation for commands that don't support zero-config mode
-	skipCommands := []string{"validate", "version", "info", "help", "--help", "-h"}
+	skipCommands := []string{"validate", "version", "info", "tools", "help", "--help", "-h"}
 
 	for _, arg := range args {
 		for _, skipCmd := range skipCommands {
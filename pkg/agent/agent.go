@@ -16,8 +16,11 @@
 package agent
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"iter"
+	"time"
 
 	"github.com/a2aproject/a2a-go/a2a"
 )
@@ -78,6 +81,23 @@ type Checkpointable interface {
 	RestoreCheckpointState(state map[string]any) error
 }
 
+// Availability is an optional interface for agents that can be temporarily
+// unable to serve calls, such as a remote agent whose circuit breaker is
+// open. Parent agents route around unavailable sub-agents.
+type Availability interface {
+	// Available reports whether the agent currently accepts calls.
+	Available() bool
+}
+
+// IsAvailable reports whether a accepts calls. Agents that do not
+// implement Availability are always available.
+func IsAvailable(a Agent) bool {
+	if av, ok := a.(Availability); ok {
+		return av.Available()
+	}
+	return true
+}
+
 // Config is the configuration for creating a new custom Agent.
 type Config struct {
 	// Name must be a non-empty string, unique within the agent tree.
@@ -102,6 +122,11 @@ type Config struct {
 
 	// AfterAgentCallbacks are called after the agent completes its run.
 	AfterAgentCallbacks []AfterAgentCallback
+
+	// Timeout bounds each run of the agent, including its callbacks.
+	// A run that exceeds it is cancelled and ends with an ErrTimeout.
+	// Zero means no timeout.
+	Timeout time.Duration
 }
 
 // BeforeAgentCallback is called before the agent starts.
@@ -116,12 +141,14 @@ type AfterAgentCallback func(CallbackContext) (*a2a.Message, error)
 type AgentType string
 
 const (
-	TypeCustomAgent     AgentType = "custom"
-	TypeLLMAgent        AgentType = "llm"
-	TypeSequentialAgent AgentType = "sequential"
-	TypeParallelAgent   AgentType = "parallel"
-	TypeLoopAgent       AgentType = "loop"
-	TypeRemoteAgent     AgentType = "remote"
+	TypeCustomAgent      AgentType = "custom"
+	TypeLLMAgent         AgentType = "llm"
+	TypeSequentialAgent  AgentType = "sequential"
+	TypeParallelAgent    AgentType = "parallel"
+	TypeLoopAgent        AgentType = "loop"
+	TypeConditionalAgent AgentType = "conditional"
+	TypeMapReduceAgent   AgentType = "map_reduce"
+	TypeRemoteAgent      AgentType = "remote"
 )
 
 // baseAgent implements the Agent interface with common functionality.
@@ -134,6 +161,7 @@ type baseAgent struct {
 	beforeAgentCallbacks []BeforeAgentCallback
 	run                  func(InvocationContext) iter.Seq2[*Event, error]
 	afterAgentCallbacks  []AfterAgentCallback
+	timeout              time.Duration
 }
 
 // New creates an Agent with custom logic defined by the Run function.
@@ -147,6 +175,9 @@ func New(cfg Config) (Agent, error) {
 	if cfg.Run == nil {
 		return nil, fmt.Errorf("agent Run function is required")
 	}
+	if cfg.Timeout < 0 {
+		return nil, fmt.Errorf("agent timeout must be non-negative")
+	}
 
 	// Check for duplicate sub-agents
 	seen := make(map[string]bool)
@@ -171,6 +202,7 @@ func New(cfg Config) (Agent, error) {
 		beforeAgentCallbacks: cfg.BeforeAgentCallbacks,
 		run:                  cfg.Run,
 		afterAgentCallbacks:  cfg.AfterAgentCallbacks,
+		timeout:              cfg.Timeout,
 	}, nil
 }
 
@@ -193,10 +225,16 @@ func (a *baseAgent) SubAgents() []Agent {
 
 func (a *baseAgent) Run(ctx InvocationContext) iter.Seq2[*Event, error] {
 	return func(yield func(*Event, error) bool) {
+		if a.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = withTimeout(ctx, a.name, a.timeout)
+			defer cancel()
+		}
+
 		// Run before-agent callbacks
 		event, err := a.runBeforeCallbacks(ctx)
 		if event != nil || err != nil {
-			yield(event, err)
+			yield(event, a.runError(ctx, err))
 			return
 		}
 
@@ -205,15 +243,26 @@ func (a *baseAgent) Run(ctx InvocationContext) iter.Seq2[*Event, error] {
 		}
 
 		// Execute agent logic
+		timeoutReported := false
 		for event, err := range a.run(ctx) {
 			if event != nil && event.Author == "" {
 				event.Author = a.name
 			}
+			err = a.runError(ctx, err)
+			timeoutReported = timeoutReported || errors.Is(err, ErrTimeout)
 			if !yield(event, err) {
 				return
 			}
 		}
 
+		// A run cut short by the timeout may end without an error
+		if err := timeoutCause(ctx); err != nil {
+			if !timeoutReported {
+				yield(nil, err)
+			}
+			return
+		}
+
 		if ctx.Ended() {
 			return
 		}
@@ -221,11 +270,23 @@ func (a *baseAgent) Run(ctx InvocationContext) iter.Seq2[*Event, error] {
 		// Run after-agent callbacks
 		event, err = a.runAfterCallbacks(ctx)
 		if event != nil || err != nil {
-			yield(event, err)
+			yield(event, a.runError(ctx, err))
 		}
 	}
 }
 
+// runError reports a failure caused by the run timeout as the timeout, so
+// callers see ErrTimeout rather than the cancellation it triggered.
+func (a *baseAgent) runError(ctx InvocationContext, err error) error {
+	if err == nil {
+		return nil
+	}
+	if cause := timeoutCause(ctx); cause != nil && !errors.Is(err, ErrTimeout) {
+		return cause
+	}
+	return err
+}
+
 func (a *baseAgent) runBeforeCallbacks(ctx InvocationContext) (*Event, error) {
 	cbCtx := newCallbackContext(ctx)
 
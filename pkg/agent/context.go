@@ -18,6 +18,7 @@ package agent
 import (
 	"context"
 	"iter"
+	"time"
 
 	"github.com/a2aproject/a2a-go/a2a"
 	"github.com/google/uuid"
@@ -108,6 +109,16 @@ type CallbackContext interface {
 	State() State
 }
 
+// AnonymousUserID is the user ID of callers that do not identify
+// themselves. Every anonymous caller shares it, so nothing personal may be
+// remembered or recalled for it.
+const AnonymousUserID = "default"
+
+// IsAnonymousUser reports whether userID stands for no particular user.
+func IsAnonymousUser(userID string) bool {
+	return userID == "" || userID == AnonymousUserID
+}
+
 // Session represents a conversation session.
 // Defined here to avoid circular imports with session package.
 type Session interface {
@@ -353,6 +364,10 @@ func (c *callbackContext) SessionID() string {
 	return ""
 }
 
+// Session returns the invocation's session, for callbacks that need the
+// conversation so far.
+func (c *callbackContext) Session() Session { return c.invCtx.Session() }
+
 func (c *callbackContext) ReadonlyState() ReadonlyState {
 	if c.invCtx.Session() != nil {
 		return c.invCtx.Session().State()
@@ -396,10 +411,31 @@ func (s *callbackState) All() iter.Seq2[string, any] {
 	return s.state.All()
 }
 
+// WithContext returns an InvocationContext whose cancellation, deadline
+// and values come from ctx, which should be derived from inv. Everything
+// else is served by inv, so ending the invocation is still seen by its
+// caller. Use it to bound part of an invocation, such as a single call,
+// with a timeout.
+func WithContext(inv InvocationContext, ctx context.Context) InvocationContext {
+	return &contextInvocation{InvocationContext: inv, ctx: ctx}
+}
+
+// contextInvocation is the InvocationContext returned by WithContext.
+type contextInvocation struct {
+	InvocationContext
+	ctx context.Context
+}
+
+func (c *contextInvocation) Deadline() (time.Time, bool) { return c.ctx.Deadline() }
+func (c *contextInvocation) Done() <-chan struct{}       { return c.ctx.Done() }
+func (c *contextInvocation) Err() error                  { return c.ctx.Err() }
+func (c *contextInvocation) Value(key any) any           { return c.ctx.Value(key) }
+
 var (
 	_ InvocationContext = (*invocationContext)(nil)
 	_ ReadonlyContext   = (*invocationContext)(nil)
 	_ CallbackContext   = (*invocationContext)(nil)
 	_ CallbackContext   = (*callbackContext)(nil)
 	_ State             = (*callbackState)(nil)
+	_ InvocationContext = (*contextInvocation)(nil)
 )
@@ -95,6 +95,15 @@ type Event struct {
 	// These update the corresponding ToolWidget status.
 	ToolResults []ToolResultState
 
+	// Usage reports the tokens consumed by the model call that produced
+	// this event. Only set on final (non-partial) model responses.
+	Usage *TokenUsage
+
+	// Sources lists the documents retrieved as context for the model call
+	// that produced this event. Only set on final responses when the agent
+	// attaches sources.
+	Sources []ContextSource
+
 	// CustomMetadata for application-specific data.
 	CustomMetadata map[string]any
 
@@ -123,6 +132,50 @@ type ThinkingState struct {
 	Type string `json:"type,omitempty"`
 }
 
+// ContextSource describes a document retrieved as context for a response.
+type ContextSource struct {
+	// Store is the document store the document came from.
+	Store string `json:"store"`
+
+	// DocumentID identifies the document within the store.
+	DocumentID string `json:"document_id,omitempty"`
+
+	// Title is the document title, or its ID when untitled.
+	Title string `json:"title,omitempty"`
+
+	// Path is the document's file path or URL, if known.
+	Path string `json:"path,omitempty"`
+
+	// Score is the retrieval relevance score.
+	Score float32 `json:"score"`
+}
+
+// MetaKeyPromptVersion is the CustomMetadata key of the PromptVersion that
+// produced a model response. CustomMetadata is persisted with the session.
+const MetaKeyPromptVersion = "prompt_version"
+
+// PromptVersion identifies the instruction an agent ran with.
+type PromptVersion struct {
+	// Version is the configured label, or "v<N>" when versions are counted
+	// automatically.
+	Version string `json:"version"`
+
+	// Hash is a short SHA-256 of the instruction.
+	Hash string `json:"hash"`
+}
+
+// TokenUsage records token consumption of a single model call.
+type TokenUsage struct {
+	// Model is the model that served the call.
+	Model string `json:"model"`
+
+	// InputTokens is the number of prompt tokens.
+	InputTokens int `json:"input_tokens"`
+
+	// OutputTokens is the number of completion tokens.
+	OutputTokens int `json:"output_tokens"`
+}
+
 // ToolCallState represents a tool invocation.
 // Maps to ToolWidget in the UI with "working" status.
 type ToolCallState struct {
@@ -190,6 +243,22 @@ type EventActions struct {
 	// InputPrompt is the message shown to the human when RequireInput is true.
 	// Should explain what input is needed and why.
 	InputPrompt string
+
+	// ApprovalRequests lists the tool calls paused for human approval.
+	// Set alongside RequireInput so clients can approve or deny each call.
+	ApprovalRequests []ToolApprovalRequest
+}
+
+// ToolApprovalRequest describes a tool call awaiting human approval.
+type ToolApprovalRequest struct {
+	// ToolCallID identifies the paused tool call.
+	ToolCallID string `json:"tool_call_id"`
+
+	// ToolName is the name of the tool.
+	ToolName string `json:"tool_name"`
+
+	// Args are the arguments the tool will be called with.
+	Args map[string]any `json:"arguments,omitempty"`
 }
 
 // IsFinalResponse returns whether this event is a final response.
@@ -258,7 +327,57 @@ func hasPartOfType(msg *a2a.Message, partType string) bool {
 	return false
 }
 
+// ThinkingPartType is the DataPart "type" of streamed reasoning content.
+// Partial events carry reasoning as such parts alongside answer parts.
+const ThinkingPartType = "thinking"
+
+// IsThinkingPart reports whether part carries model reasoning rather than
+// answer content.
+func IsThinkingPart(part a2a.Part) bool {
+	dp, ok := part.(a2a.DataPart)
+	if !ok {
+		return false
+	}
+	typeVal, _ := dp.Data["type"].(string)
+	return typeVal == ThinkingPartType
+}
+
+// ThinkingContent returns the model's reasoning carried by the event, from
+// Thinking or, failing that, from thinking parts. Empty if there is none.
+func (e *Event) ThinkingContent() string {
+	if e.Thinking != nil {
+		return e.Thinking.Content
+	}
+	if e.Message == nil {
+		return ""
+	}
+
+	var text string
+	for _, part := range e.Message.Parts {
+		if IsThinkingPart(part) {
+			content, _ := part.(a2a.DataPart).Data["content"].(string)
+			text += content
+		}
+	}
+	return text
+}
+
+// PromptVersion returns the prompt version recorded on the event, or nil.
+func (e *Event) PromptVersion() *PromptVersion {
+	switch v := e.CustomMetadata[MetaKeyPromptVersion].(type) {
+	case *PromptVersion:
+		return v
+	case map[string]any:
+		// Decoded from a persisted session
+		version, _ := v["version"].(string)
+		hash, _ := v["hash"].(string)
+		return &PromptVersion{Version: version, Hash: hash}
+	}
+	return nil
+}
+
 // TextContent extracts text content from the event's message.
+// Reasoning is never included; see ThinkingContent.
 func (e *Event) TextContent() string {
 	if e.Message == nil {
 		return ""
@@ -277,6 +396,9 @@ func (e *Event) TextContent() string {
 type Content struct {
 	Parts []a2a.Part
 	Role  a2a.MessageRole
+
+	// Metadata is carried over to the message (e.g., {"pinned": true}).
+	Metadata map[string]any
 }
 
 // NewTextContent creates content with a text part.
@@ -292,7 +414,9 @@ func (c *Content) ToMessage() *a2a.Message {
 	if c == nil {
 		return nil
 	}
-	return a2a.NewMessage(c.Role, c.Parts...)
+	msg := a2a.NewMessage(c.Role, c.Parts...)
+	msg.Metadata = c.Metadata
+	return msg
 }
 
 // AddPart appends a part to the content.
@@ -17,6 +17,7 @@ package llmagent
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"iter"
 	"log/slog"
@@ -81,7 +82,9 @@ func (f *Flow) Run(ctx agent.InvocationContext) iter.Seq2[*agent.Event, error] {
 		// Execute any pending approved tools directly
 		// This is critical: when user approves a tool, we must execute it immediately
 		// rather than relying on the LLM to re-call it (which it won't)
-		f.executePendingApprovedTools(ctx, yield)
+		if !f.executePendingApprovedTools(ctx, yield) {
+			return
+		}
 
 		// Outer loop: continues until IsFinalResponse
 		// This matches adk-go's Flow.Run pattern
@@ -172,19 +175,51 @@ func (f *Flow) runOneStep(ctx agent.InvocationContext) iter.Seq2[*agent.Event, e
 
 		// 3. Run before-model callbacks
 		stateDelta := make(map[string]any)
-		resp, err := f.callLLMWithCallbacks(ctx, req, stateDelta, yield)
-		if err != nil {
-			yield(nil, err)
-			return
-		}
-		if resp == nil {
-			return // Callback handled the response
-		}
+		var resp *model.Response
+		languageRetries := 0
+		for attempt := 0; ; attempt++ {
+			var err error
+			resp, err = f.callLLMWithCallbacks(ctx, req, stateDelta, yield)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if resp == nil {
+				return // Callback handled the response
+			}
 
-		// 4. Postprocess: run response processors
-		if err := f.pipeline.ProcessResponse(procCtx, req, resp); err != nil {
-			yield(nil, fmt.Errorf("postprocess failed: %w", err))
-			return
+			// 4. Postprocess: run response processors, then output transforms
+			if err := f.pipeline.ProcessResponse(procCtx, req, resp); err != nil {
+				yield(nil, fmt.Errorf("postprocess failed: %w", err))
+				return
+			}
+			if err := f.agent.transformResponse(ctx, resp); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			// Retry a final response in the wrong language with a stronger directive
+			if lang := f.agent.responseLanguage; lang != nil && languageRetries < lang.maxRetries() &&
+				!resp.HasToolCalls() && lang.drifted(resp.TextContent()) {
+				languageRetries++
+				slog.Warn("Model responded in the wrong language, retrying",
+					"agent", f.agent.Name(),
+					"model", f.agent.model.Name(),
+					"language", lang.Language)
+				req.Messages = append(req.Messages, a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: lang.retryDirective()}))
+				continue
+			}
+
+			// Retry a completely empty response once (capped to avoid loops)
+			if attempt > 0 || !f.agent.reasoning.RetryOnEmpty || !isEmptyResponse(resp) {
+				break
+			}
+			slog.Warn("Model returned an empty response, retrying",
+				"agent", f.agent.Name(),
+				"model", f.agent.model.Name())
+			if nudge := f.agent.reasoning.EmptyRetryInstruction; nudge != "" {
+				req.Messages = append(req.Messages, a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: nudge}))
+			}
 		}
 
 		// 5. Skip if no content and no error (adk-go pattern for code executor)
@@ -195,6 +230,15 @@ func (f *Flow) runOneStep(ctx agent.InvocationContext) iter.Seq2[*agent.Event, e
 
 		// 6. Build and yield model response event
 		modelEvent := f.buildModelResponseEvent(ctx, resp, stateDelta)
+		if f.agent.attachSources && modelEvent.IsFinalResponse() {
+			modelEvent.Sources = procCtx.sources
+		}
+		if pv := f.agent.promptVersion; pv != nil && !modelEvent.Partial {
+			if modelEvent.CustomMetadata == nil {
+				modelEvent.CustomMetadata = make(map[string]any)
+			}
+			modelEvent.CustomMetadata[agent.MetaKeyPromptVersion] = pv
+		}
 		if !yield(modelEvent, nil) {
 			return
 		}
@@ -220,6 +264,24 @@ func (f *Flow) runOneStep(ctx agent.InvocationContext) iter.Seq2[*agent.Event, e
 	}
 }
 
+// isEmptyResponse reports whether a response carries nothing usable: no
+// error, no tool calls and no non-blank content. Reasoning alone is empty.
+func isEmptyResponse(resp *model.Response) bool {
+	if resp.ErrorCode != "" || resp.HasToolCalls() {
+		return false
+	}
+	if resp.Content == nil {
+		return true
+	}
+	for _, part := range resp.Content.Parts {
+		tp, ok := part.(a2a.TextPart)
+		if !ok || strings.TrimSpace(tp.Text) != "" {
+			return false
+		}
+	}
+	return true
+}
+
 // callLLMWithCallbacks handles before/after callbacks and LLM call.
 func (f *Flow) callLLMWithCallbacks(
 	ctx agent.InvocationContext,
@@ -238,9 +300,15 @@ func (f *Flow) callLLMWithCallbacks(
 		}
 	}
 
+	// Streamed chunks are only transformed when explicitly enabled
+	var streamTransform *streamTransformer
+	if f.agent.transformStreaming && len(f.agent.outputTransforms) > 0 {
+		streamTransform = &streamTransformer{agent: f.agent}
+	}
+
 	// Call LLM
 	var finalResp *model.Response
-	for resp, err := range f.agent.model.GenerateContent(ctx, req, f.agent.enableStreaming) {
+	for resp, err := range f.generateContent(ctx, req) {
 		// Run after-model callbacks
 		callbackResp, callbackErr := f.runAfterModelCallbacks(ctx, resp, stateDelta, err)
 		if callbackErr != nil {
@@ -258,20 +326,56 @@ func (f *Flow) callLLMWithCallbacks(
 			continue
 		}
 
-		if resp.Partial {
-			// Yield partial events for streaming UI
-			event := f.buildPartialEvent(ctx, resp)
-			if !yield(event, nil) {
-				return nil, fmt.Errorf("streaming interrupted")
+		if !resp.Partial {
+			// Emit any text still buffered for block-level transforms
+			if streamTransform != nil {
+				if err := f.yieldTransformedPartial(ctx, streamTransform.flush, yield); err != nil {
+					return nil, err
+				}
 			}
-		} else {
 			finalResp = resp
+			continue
+		}
+
+		if streamTransform != nil {
+			if err := f.yieldTransformedPartial(ctx, func(ctx agent.ReadonlyContext) (*model.Response, error) {
+				return streamTransform.transformPartial(ctx, resp)
+			}, yield); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		// Yield partial events for streaming UI
+		event := f.buildPartialEvent(ctx, resp)
+		if !yield(event, nil) {
+			return nil, fmt.Errorf("streaming interrupted")
 		}
 	}
 
 	return finalResp, nil
 }
 
+// yieldTransformedPartial yields the partial event produced by a stream
+// transform step, if it produced anything.
+func (f *Flow) yieldTransformedPartial(
+	ctx agent.InvocationContext,
+	step func(agent.ReadonlyContext) (*model.Response, error),
+	yield func(*agent.Event, error) bool,
+) error {
+	resp, err := step(ctx)
+	if err != nil {
+		return err
+	}
+	if resp == nil {
+		return nil
+	}
+	if !yield(f.buildPartialEvent(ctx, resp), nil) {
+		return fmt.Errorf("streaming interrupted")
+	}
+	return nil
+}
+
 // runAfterModelCallbacks runs after-model callbacks.
 func (f *Flow) runAfterModelCallbacks(
 	ctx agent.InvocationContext,
@@ -365,6 +469,14 @@ func (f *Flow) buildModelResponseEvent(ctx agent.InvocationContext, resp *model.
 		}
 	}
 
+	if resp.Usage != nil {
+		event.Usage = &agent.TokenUsage{
+			Model:        f.agent.model.Name(),
+			InputTokens:  resp.Usage.PromptTokens,
+			OutputTokens: resp.Usage.CompletionTokens,
+		}
+	}
+
 	// OutputKey: Save agent output to session state if configured
 	if f.agent.outputKey != "" && resp.Content != nil {
 		if text := resp.TextContent(); text != "" {
@@ -402,7 +514,7 @@ func (f *Flow) buildPartialEvent(ctx agent.InvocationContext, resp *model.Respon
 		// Create thinking Part with type marker so UI can identify it
 		parts = append(parts, a2a.DataPart{
 			Data: map[string]any{
-				"type":    "thinking",
+				"type":    agent.ThinkingPartType,
 				"id":      thinkingID,
 				"content": resp.Thinking.Content,
 				"status":  "active",
@@ -516,6 +628,7 @@ func (f *Flow) handleToolCalls(ctx agent.InvocationContext, resp *model.Response
 	var longRunningToolIDs []string
 	var requiresInput bool
 	var inputPrompt string
+	var approvalRequests []agent.ToolApprovalRequest
 	mergedActions := &agent.EventActions{StateDelta: make(map[string]any)}
 
 	for _, tc := range resp.ToolCalls {
@@ -524,6 +637,7 @@ func (f *Flow) handleToolCalls(ctx agent.InvocationContext, resp *model.Response
 		var resultStr string
 		var isError bool
 		var status string
+		var failErr error
 
 		if t == nil {
 			resultStr = fmt.Sprintf("Error: tool %q not found", tc.Name)
@@ -544,12 +658,13 @@ func (f *Flow) handleToolCalls(ctx agent.InvocationContext, resp *model.Response
 
 					slog.Info("Tool approved, executing", "tool", tc.Name, "callID", tc.ID, "args", tc.Args)
 					toolCtx := newToolContext(ctx, tc.ID)
-					result, err := f.callToolWithCallbacks(ctx, t, tc.Args, toolCtx)
+					result, err := f.callTool(ctx, t, tc.Args, toolCtx)
 					slog.Info("Tool execution completed", "tool", tc.Name, "callID", tc.ID, "error", err != nil)
 					if err != nil {
 						resultStr = fmt.Sprintf("Error: %v", err)
 						isError = true
 						status = "failed"
+						failErr = f.toolFailure(ctx, tc.Name, err)
 					} else {
 						resultStr = formatToolResult(result)
 						status = "success"
@@ -576,6 +691,11 @@ func (f *Flow) handleToolCalls(ctx agent.InvocationContext, resp *model.Response
 				slog.Debug("Tool requires approval", "tool", tc.Name, "callID", tc.ID)
 				longRunningToolIDs = append(longRunningToolIDs, tc.ID)
 				requiresInput = true
+				approvalRequests = append(approvalRequests, agent.ToolApprovalRequest{
+					ToolCallID: tc.ID,
+					ToolName:   tc.Name,
+					Args:       tc.Args,
+				})
 
 				// Build approval prompt - check for custom prompt
 				var toolPrompt string
@@ -601,11 +721,14 @@ func (f *Flow) handleToolCalls(ctx agent.InvocationContext, resp *model.Response
 			// Check for streaming tool first
 			if st, ok := t.(tool.StreamingTool); ok {
 				// Streaming tool - yields partial events during execution
+				// Streaming tools are not retried since partial output has
+				// already been emitted.
 				content, success, err := f.executeStreamingTool(ctx, toolCtx, st, tc, yield)
 				if err != nil {
 					resultStr = fmt.Sprintf("Error: %v", err)
 					isError = true
 					status = "failed"
+					failErr = f.toolFailure(ctx, tc.Name, err)
 				} else {
 					resultStr = content
 					if success {
@@ -613,15 +736,17 @@ func (f *Flow) handleToolCalls(ctx agent.InvocationContext, resp *model.Response
 					} else {
 						status = "failed"
 						isError = true
+						failErr = f.toolFailure(ctx, tc.Name, errors.New(content))
 					}
 				}
 			} else {
-				// Regular callable tool - execute with callbacks
-				result, err := f.callToolWithCallbacks(ctx, t, tc.Args, toolCtx)
+				// Regular callable tool - execute with callbacks and retry policy
+				result, err := f.callTool(ctx, t, tc.Args, toolCtx)
 				if err != nil {
 					resultStr = fmt.Sprintf("Error: %v", err)
 					isError = true
 					status = "failed"
+					failErr = f.toolFailure(ctx, tc.Name, err)
 				} else {
 					resultStr = formatToolResult(result)
 					status = "success"
@@ -632,6 +757,19 @@ func (f *Flow) handleToolCalls(ctx agent.InvocationContext, resp *model.Response
 			mergeEventActions(mergedActions, toolCtx.Actions())
 		}
 
+		if failErr != nil {
+			return nil, failErr
+		}
+
+		// Summarize before capping so the summary sees the whole result;
+		// the cap still bounds results that were not summarized.
+		if status == "success" {
+			resultStr = f.summarizeToolResult(ctx, tc, resultStr)
+		}
+		if t != nil && (status == "success" || status == "failed") {
+			resultStr = f.limitToolOutput(ctx, tc.Name, resultStr)
+		}
+
 		// Track tool result for UI
 		toolResults = append(toolResults, agent.ToolResultState{
 			ToolCallID: tc.ID,
@@ -671,6 +809,7 @@ func (f *Flow) handleToolCalls(ctx agent.InvocationContext, resp *model.Response
 	if requiresInput {
 		event.Actions.RequireInput = true
 		event.Actions.InputPrompt = inputPrompt
+		event.Actions.ApprovalRequests = approvalRequests
 	}
 
 	return event, nil
@@ -707,8 +846,11 @@ func (f *Flow) executeStreamingTool(
 		}
 	}
 
-	// Iterate over streaming results
-	for result, err := range st.CallStreaming(toolCtx, tc.Args) {
+	// Iterate over streaming results, bounded by the tool's timeout
+	timeout := f.agent.toolLimits(ctx, st.Name()).Timeout
+	bc := withToolTimeout(ctx, toolCtx, st.Name(), timeout)
+	defer bc.cancel()
+	for result, err := range st.CallStreaming(bc.ctx, tc.Args) {
 		if err != nil {
 			execError = err
 			break
@@ -746,6 +888,12 @@ func (f *Flow) executeStreamingTool(
 		}
 	}
 
+	if err := bc.timeoutErr(); err != nil {
+		execError = f.toolTimedOut(st.Name(), timeout, err)
+	} else {
+		bc.commit()
+	}
+
 	// Determine final content and status
 	var finalContent string
 	var success bool
@@ -770,15 +918,21 @@ func (f *Flow) executeStreamingTool(
 	// Record metrics
 	if f.agent.metricsRecorder != nil {
 		f.agent.metricsRecorder.RecordToolCall(st.Name(), duration)
-		if execError != nil {
+		if execError != nil && !errors.Is(execError, ErrToolTimeout) {
 			f.agent.metricsRecorder.RecordToolError(st.Name(), "execution_error")
 		}
 	}
 
-	// Run after-tool callbacks
 	resultMap := map[string]any{"content": finalContent}
+	f.observeToolCall(ctx, toolCtx, st.Name(), tc.Args, resultMap, execError, startTime)
+
+	// Run after-tool callbacks, reporting failures like callToolWithCallbacks
+	callbackErr := execError
+	if callbackErr == nil && !success {
+		callbackErr = errors.New(finalResult.Error)
+	}
 	for _, cb := range f.agent.afterToolCallbacks {
-		callbackResult, err := cb(toolCtx, st, tc.Args, resultMap, nil)
+		callbackResult, err := cb(toolCtx, st, tc.Args, resultMap, callbackErr)
 		if err != nil {
 			return "", false, fmt.Errorf("after-tool callback failed: %w", err)
 		}
@@ -816,47 +970,54 @@ func (f *Flow) callToolWithCallbacks(
 		}
 	}
 
-	// Execute tool
-	var result map[string]any
-	var toolErr error
+	// Execute tool, bounded by its timeout
+	var call func(tool.Context) (map[string]any, error)
 
 	if callable, ok := t.(tool.CallableTool); ok {
-		result, toolErr = callable.Call(toolCtx, args)
+		call = func(toolCtx tool.Context) (map[string]any, error) {
+			return callable.Call(toolCtx, args)
+		}
 	} else if streaming, ok := t.(tool.StreamingTool); ok {
 		// Handle streaming tools by collecting all results
-		var finalResult *tool.Result
-		for res, err := range streaming.CallStreaming(toolCtx, args) {
-			if err != nil {
-				toolErr = err
-				break
+		call = func(toolCtx tool.Context) (map[string]any, error) {
+			var finalResult *tool.Result
+			for res, err := range streaming.CallStreaming(toolCtx, args) {
+				if err != nil {
+					return nil, err
+				}
+				// Keep track of the final result (non-streaming)
+				if res != nil && !res.Streaming {
+					finalResult = res
+				}
 			}
-			// Keep track of the final result (non-streaming)
-			if res != nil && !res.Streaming {
-				finalResult = res
+			if finalResult == nil {
+				return nil, nil
 			}
-		}
-		if finalResult != nil {
-			result = map[string]any{
+			result := map[string]any{
 				"content":  finalResult.Content,
 				"metadata": finalResult.Metadata,
 			}
 			if finalResult.Error != "" {
 				result["error"] = finalResult.Error
 			}
+			return result, nil
 		}
 	} else {
 		return nil, fmt.Errorf("tool %q is not callable", t.Name())
 	}
 
+	result, toolErr := f.runWithTimeout(ctx, t, toolCtx, call)
+
 	duration := time.Since(startTime)
 
-	// Record metrics
+	// Record metrics (timeouts are recorded by runWithTimeout)
 	if f.agent.metricsRecorder != nil {
 		f.agent.metricsRecorder.RecordToolCall(t.Name(), duration)
-		if toolErr != nil {
+		if toolErr != nil && !errors.Is(toolErr, ErrToolTimeout) {
 			f.agent.metricsRecorder.RecordToolError(t.Name(), "execution_error")
 		}
 	}
+	f.observeToolCall(ctx, toolCtx, t.Name(), args, result, toolErr, startTime)
 
 	// Run after-tool callbacks
 	for _, cb := range f.agent.afterToolCallbacks {
@@ -1064,7 +1225,8 @@ func (f *Flow) preparePendingDenialMessages(ctx agent.InvocationContext) {
 
 // executePendingApprovedTools finds tool calls that were pending approval and are now approved,
 // executes them directly, and yields the results as events.
-// Returns true if any tools were executed (caller should continue with normal flow after).
+// Returns false if the flow must stop: the caller stopped consuming events, or
+// a tool failed under the fail on_error policy (the failure has been yielded).
 func (f *Flow) executePendingApprovedTools(ctx agent.InvocationContext, yield func(*agent.Event, error) bool) bool {
 	// Check if there are any approval decisions
 	hasApproval := false
@@ -1075,17 +1237,17 @@ func (f *Flow) executePendingApprovedTools(ctx agent.InvocationContext, yield fu
 		}
 	}
 	if !hasApproval {
-		return false
+		return true
 	}
 
 	session := ctx.Session()
 	if session == nil {
-		return false
+		return true
 	}
 
 	events := session.Events()
 	if events == nil {
-		return false
+		return true
 	}
 
 	// Find pending tool calls that are now approved
@@ -1137,7 +1299,7 @@ func (f *Flow) executePendingApprovedTools(ctx agent.InvocationContext, yield fu
 	}
 
 	if len(pendingTools) == 0 {
-		return false
+		return true
 	}
 
 	// Execute each pending approved tool
@@ -1161,8 +1323,12 @@ func (f *Flow) executePendingApprovedTools(ctx agent.InvocationContext, yield fu
 		var isError bool
 		var status string
 
-		result, err := f.callToolWithCallbacks(ctx, t, pt.args, toolCtx)
+		result, err := f.callTool(ctx, t, pt.args, toolCtx)
 		if err != nil {
+			if failErr := f.toolFailure(ctx, pt.toolName, err); failErr != nil {
+				yield(nil, failErr)
+				return false
+			}
 			resultStr = fmt.Sprintf("Error: %v", err)
 			isError = true
 			status = "failed"
@@ -1205,7 +1371,7 @@ func (f *Flow) executePendingApprovedTools(ctx agent.InvocationContext, yield fu
 
 		// Yield the event (runner will persist to session)
 		if !yield(event, nil) {
-			return true
+			return false
 		}
 	}
 
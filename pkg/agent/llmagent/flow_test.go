@@ -0,0 +1,33 @@
+package llmagent
+
+import (
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/model"
+	"github.com/kadirpekel/hector/pkg/tool"
+)
+
+func TestIsEmptyResponse(t *testing.T) {
+	text := func(s string) *model.Content {
+		return &model.Content{Parts: []a2a.Part{a2a.TextPart{Text: s}}}
+	}
+	tests := []struct {
+		name string
+		resp *model.Response
+		want bool
+	}{
+		{"no content", &model.Response{}, true},
+		{"blank text", &model.Response{Content: text(" \n")}, true},
+		{"thinking only", &model.Response{Thinking: &model.ThinkingBlock{Content: "hmm"}}, true},
+		{"text", &model.Response{Content: text("Hi")}, false},
+		{"tool call", &model.Response{ToolCalls: []tool.ToolCall{{Name: "search"}}}, false},
+		{"error", &model.Response{ErrorCode: "filtered"}, false},
+	}
+	for _, tt := range tests {
+		if got := isEmptyResponse(tt.resp); got != tt.want {
+			t.Errorf("%s: isEmptyResponse() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
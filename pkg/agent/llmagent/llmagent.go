@@ -36,6 +36,9 @@ import (
 	"fmt"
 	"iter"
 	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/a2aproject/a2a-go/a2a"
 
@@ -95,6 +98,10 @@ type Config struct {
 	// AfterAgentCallbacks run after the agent completes.
 	AfterAgentCallbacks []agent.AfterAgentCallback
 
+	// Timeout bounds each run of the agent, so a stalled model call fails
+	// the run with agent.ErrTimeout. Zero means no timeout.
+	Timeout time.Duration
+
 	// BeforeModelCallbacks run before each LLM call.
 	BeforeModelCallbacks []BeforeModelCallback
 
@@ -134,11 +141,21 @@ type Config struct {
 	// If nil, all history is included (no filtering).
 	WorkingMemory memory.WorkingMemoryStrategy
 
+	// LongTermMemory remembers durable user facts across sessions.
+	// The runner updates it after each turn, and the facts relevant to
+	// the user's input are injected alongside ContextProvider's context.
+	// If nil, no facts are remembered.
+	LongTermMemory *memory.LongTermMemory
+
 	// ContextProvider retrieves relevant context for RAG.
 	// When set, the agent will query the provider with user input
 	// and inject relevant context into the conversation.
 	ContextProvider ContextProvider
 
+	// AttachSources attaches the documents recorded by the ContextProvider
+	// (see RecordContextSources) to the final response event.
+	AttachSources bool
+
 	// RequestProcessors are custom processors added to the request pipeline.
 	// These run AFTER the default processors.
 	RequestProcessors []RequestProcessor
@@ -147,6 +164,18 @@ type Config struct {
 	// These run AFTER the default processors.
 	ResponseProcessors []ResponseProcessor
 
+	// OutputTransforms post-process response text before it reaches the client.
+	// They run in order on the text of every final model response, and the
+	// result is what gets persisted to the session.
+	OutputTransforms []OutputTransform
+
+	// TransformStreaming also applies OutputTransforms to streamed chunks.
+	// Streamed text is buffered and transformed one block (paragraph) at a
+	// time, which adds latency and means a transform only sees part of the
+	// response. When false, streamed chunks are passed through untransformed
+	// and only the final response is transformed.
+	TransformStreaming bool
+
 	// Pipeline allows complete customization of the processor pipeline.
 	// If set, RequestProcessors and ResponseProcessors are ignored.
 	Pipeline *Pipeline
@@ -154,6 +183,40 @@ type Config struct {
 	// MetricsRecorder records tool execution metrics.
 	// If nil, metrics are not recorded (no-op).
 	MetricsRecorder observability.Recorder
+
+	// Tracer traces LLM calls. If nil, no spans are created.
+	Tracer *observability.Tracer
+
+	// Observers receive the full payloads of LLM and tool calls, e.g. to
+	// export them to Langfuse.
+	Observers []Observer
+
+	// ToolResultSummarization summarizes oversized tool results, keyed by
+	// tool name or by the name of the toolset providing the tool.
+	ToolResultSummarization map[string]ToolResultSummarization
+
+	// ToolErrorPolicies controls how tool failures are handled, keyed by
+	// tool name or by the name of the toolset providing the tool.
+	// Tools without a policy feed errors back to the model.
+	ToolErrorPolicies map[string]ToolErrorPolicy
+
+	// ToolLimits bounds the duration and output size of tool calls, keyed
+	// by tool name or by the name of the toolset providing the tool.
+	// Tools without limits use DefaultToolTimeout and
+	// DefaultToolMaxOutputBytes.
+	ToolLimits map[string]ToolLimits
+
+	// PromptBudget warns or fails when the system prompt takes too much of
+	// the context window. If nil, the system prompt size is not checked.
+	PromptBudget *PromptBudget
+
+	// ResponseLanguage makes the agent answer in a fixed language.
+	// If nil, the model picks the language.
+	ResponseLanguage *ResponseLanguage
+
+	// PromptVersion is recorded on model responses and LLM call spans.
+	// If nil, responses are not stamped.
+	PromptVersion *agent.PromptVersion
 }
 
 // ReasoningConfig configures the chain-of-thought reasoning loop.
@@ -172,6 +235,14 @@ type ReasoningConfig struct {
 
 	// CompletionInstruction is appended to help the model know when to stop.
 	CompletionInstruction string
+
+	// RetryOnEmpty retries the model call once when the response has no
+	// text and no tool calls.
+	RetryOnEmpty bool
+
+	// EmptyRetryInstruction is added as a user message to the retried
+	// request. Empty retries the request unchanged.
+	EmptyRetryInstruction string
 }
 
 // InstructionProvider generates instructions dynamically.
@@ -182,6 +253,48 @@ type InstructionProvider func(ctx agent.ReadonlyContext) (string, error)
 // The returned string is injected into the conversation as additional context.
 type ContextProvider func(ctx agent.ReadonlyContext, query string) (string, error)
 
+// ChainContextProviders returns a provider that joins the non-empty
+// context of each non-nil provider, or nil if there are none.
+func ChainContextProviders(providers ...ContextProvider) ContextProvider {
+	var chain []ContextProvider
+	for _, p := range providers {
+		if p != nil {
+			chain = append(chain, p)
+		}
+	}
+	switch len(chain) {
+	case 0:
+		return nil
+	case 1:
+		return chain[0]
+	}
+
+	return func(ctx agent.ReadonlyContext, query string) (string, error) {
+		var parts []string
+		for _, p := range chain {
+			text, err := p(ctx, query)
+			if err != nil {
+				return "", err
+			}
+			if text != "" {
+				parts = append(parts, text)
+			}
+		}
+		return strings.Join(parts, "\n\n"), nil
+	}
+}
+
+// RecordContextSources records the documents a ContextProvider injected for
+// the current model call. Providers call it with the ctx they were given;
+// it is a no-op for any other context.
+func RecordContextSources(ctx agent.ReadonlyContext, sources ...agent.ContextSource) {
+	if r, ok := ctx.(interface {
+		recordContextSources([]agent.ContextSource)
+	}); ok {
+		r.recordContextSources(sources)
+	}
+}
+
 // BeforeModelCallback runs before an LLM call.
 // Return non-nil Response to skip the actual LLM call.
 type BeforeModelCallback func(ctx agent.CallbackContext, req *model.Request) (*model.Response, error)
@@ -242,14 +355,44 @@ type llmAgent struct {
 	// Working memory strategy for context window management
 	workingMemory memory.WorkingMemoryStrategy
 
+	// Long-term memory of user facts
+	longTermMemory *memory.LongTermMemory
+
 	// Context provider for RAG
 	contextProvider ContextProvider
+	attachSources   bool
 
 	// Processor pipeline
 	pipeline *Pipeline
 
+	// Output post-processing
+	outputTransforms   []OutputTransform
+	transformStreaming bool
+
 	// Metrics recorder for tool execution tracking
 	metricsRecorder observability.Recorder
+
+	// Tracer for LLM call spans
+	tracer *observability.Tracer
+
+	// Observers of full LLM and tool call payloads
+	observers []Observer
+
+	// Summarization of oversized tool results
+	toolSummarization map[string]ToolResultSummarization
+
+	// Error handling policies for failing tools
+	toolErrorPolicies map[string]ToolErrorPolicy
+
+	// Timeout and output size limits of tools
+	toolLimitSettings map[string]ToolLimits
+
+	// System prompt size guard
+	promptBudget       *PromptBudget
+	promptBudgetWarned atomic.Value // invocation ID of the last warning
+
+	responseLanguage *ResponseLanguage
+	promptVersion    *agent.PromptVersion
 }
 
 // New creates a new LLM-based agent.
@@ -282,6 +425,20 @@ func New(cfg Config) (agent.Agent, error) {
 		reasoning.MaxIterations = 100 // Safety limit, not primary control
 	}
 
+	if lang := cfg.ResponseLanguage; lang != nil && lang.Verify {
+		if _, ok := lookupLanguage(lang.Language); !ok {
+			slog.Warn("Response language check does not support this language, responses will not be verified",
+				"agent", cfg.Name,
+				"language", lang.Language)
+		}
+	}
+
+	// Inject remembered user facts alongside the RAG context
+	contextProvider := cfg.ContextProvider
+	if cfg.LongTermMemory != nil {
+		contextProvider = ChainContextProviders(cfg.LongTermMemory.Context, cfg.ContextProvider)
+	}
+
 	// Initialize processor pipeline
 	var pipeline *Pipeline
 	if cfg.Pipeline != nil {
@@ -319,9 +476,21 @@ func New(cfg Config) (agent.Agent, error) {
 		outputSchema:              cfg.OutputSchema,
 		reasoning:                 reasoning,
 		workingMemory:             cfg.WorkingMemory,
-		contextProvider:           cfg.ContextProvider,
+		longTermMemory:            cfg.LongTermMemory,
+		contextProvider:           contextProvider,
+		attachSources:             cfg.AttachSources,
 		pipeline:                  pipeline,
+		outputTransforms:          cfg.OutputTransforms,
+		transformStreaming:        cfg.TransformStreaming,
 		metricsRecorder:           cfg.MetricsRecorder,
+		observers:                 cfg.Observers,
+		tracer:                    cfg.Tracer,
+		toolSummarization:         cfg.ToolResultSummarization,
+		toolErrorPolicies:         cfg.ToolErrorPolicies,
+		toolLimitSettings:         cfg.ToolLimits,
+		promptBudget:              cfg.PromptBudget,
+		responseLanguage:          cfg.ResponseLanguage,
+		promptVersion:             cfg.PromptVersion,
 	}
 
 	// Create base agent with our run function
@@ -333,6 +502,7 @@ func New(cfg Config) (agent.Agent, error) {
 		Run:                  a.run,
 		AfterAgentCallbacks:  cfg.AfterAgentCallbacks,
 		AgentType:            agent.TypeLLMAgent,
+		Timeout:              cfg.Timeout,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create base agent: %w", err)
@@ -692,3 +862,12 @@ func (a *llmAgent) WorkingMemory() memory.WorkingMemoryStrategy {
 
 // Ensure llmAgent implements WorkingMemoryProvider.
 var _ memory.WorkingMemoryProvider = (*llmAgent)(nil)
+
+// LongTermMemory returns the agent's long-term memory of user facts.
+// Implements memory.LongTermMemoryProvider interface.
+func (a *llmAgent) LongTermMemory() *memory.LongTermMemory {
+	return a.longTermMemory
+}
+
+// Ensure llmAgent implements LongTermMemoryProvider.
+var _ memory.LongTermMemoryProvider = (*llmAgent)(nil)
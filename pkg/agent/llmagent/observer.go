@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmagent
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/model"
+	"github.com/kadirpekel/hector/pkg/tool"
+)
+
+// Observer receives the full payloads of an agent's LLM and tool calls,
+// for exporting them to LLM observability platforms such as Langfuse.
+//
+// Unlike tracing spans, observers see complete prompts and responses.
+// Methods are called synchronously from the agent's flow, so
+// implementations must not block: queue the payload and export it in the
+// background. Observers must not modify the payloads they receive.
+type Observer interface {
+	// OnLLMStart is called before a model call with the final request.
+	OnLLMStart(ctx context.Context, call LLMCallInfo, req *model.Request)
+
+	// OnLLMChunk is called for each partial response of a streamed call.
+	OnLLMChunk(ctx context.Context, call LLMCallInfo, chunk *model.Response)
+
+	// OnLLMEnd is called when a model call finishes, with its final
+	// response or error.
+	OnLLMEnd(ctx context.Context, call LLMCallInfo, resp *model.Response, err error)
+
+	// OnToolCall is called when a tool call finishes.
+	OnToolCall(ctx context.Context, call ToolCallInfo)
+}
+
+// CallInfo identifies the run a call belongs to.
+type CallInfo struct {
+	// InvocationID identifies the agent run. Calls of one run share it.
+	InvocationID string
+
+	// SessionID and UserID identify the conversation.
+	SessionID string
+	UserID    string
+
+	// AgentName is the agent that made the call.
+	AgentName string
+}
+
+// LLMCallInfo describes a model call reported to an Observer.
+type LLMCallInfo struct {
+	CallInfo
+
+	// ID uniquely identifies the call.
+	ID string
+
+	// Model and Provider serve the call.
+	Model    string
+	Provider string
+
+	// Stream reports whether the response is streamed.
+	Stream bool
+
+	// StartTime is when the call was made.
+	StartTime time.Time
+}
+
+// ToolCallInfo describes a finished tool call reported to an Observer.
+type ToolCallInfo struct {
+	CallInfo
+
+	// ID is the tool call ID assigned by the model.
+	ID string
+
+	// Name is the tool name.
+	Name string
+
+	// Args are the arguments the model passed.
+	Args map[string]any
+
+	// Result is the tool's result, before after-tool callbacks run.
+	Result map[string]any
+
+	// Err is the error the call failed with, if any.
+	Err error
+
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// callInfo returns the run identifiers of ctx.
+func (a *llmAgent) callInfo(ctx agent.InvocationContext) CallInfo {
+	info := CallInfo{InvocationID: ctx.InvocationID(), AgentName: a.Name()}
+	if s := ctx.Session(); s != nil {
+		info.SessionID = s.ID()
+		info.UserID = s.UserID()
+	}
+	return info
+}
+
+// notifyObservers calls fn for each observer, recovering from panics so a
+// faulty observer cannot fail the run.
+func (a *llmAgent) notifyObservers(fn func(Observer)) {
+	for _, o := range a.observers {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					slog.Warn("LLM observer panicked", "agent", a.Name(), "panic", r)
+				}
+			}()
+			fn(o)
+		}()
+	}
+}
+
+// observeToolCall reports a finished tool call to the observers.
+func (f *Flow) observeToolCall(ctx agent.InvocationContext, toolCtx tool.Context, name string, args, result map[string]any, err error, start time.Time) {
+	if len(f.agent.observers) == 0 {
+		return
+	}
+	call := ToolCallInfo{
+		CallInfo:  f.agent.callInfo(ctx),
+		Name:      name,
+		Args:      args,
+		Result:    result,
+		Err:       err,
+		StartTime: start,
+		EndTime:   time.Now(),
+	}
+	if toolCtx != nil {
+		call.ID = toolCtx.FunctionCallID()
+	}
+	f.agent.notifyObservers(func(o Observer) { o.OnToolCall(ctx, call) })
+}
@@ -0,0 +1,61 @@
+package llmagent
+
+import (
+	"context"
+	"iter"
+	"testing"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/model"
+)
+
+// recordingObserver records tool calls.
+type recordingObserver struct {
+	tools []ToolCallInfo
+}
+
+func (o *recordingObserver) OnLLMStart(context.Context, LLMCallInfo, *model.Request)       {}
+func (o *recordingObserver) OnLLMChunk(context.Context, LLMCallInfo, *model.Response)      {}
+func (o *recordingObserver) OnLLMEnd(context.Context, LLMCallInfo, *model.Response, error) {}
+
+func (o *recordingObserver) OnToolCall(_ context.Context, call ToolCallInfo) {
+	o.tools = append(o.tools, call)
+}
+
+// panickingObserver panics on tool calls.
+type panickingObserver struct{ recordingObserver }
+
+func (o *panickingObserver) OnToolCall(context.Context, ToolCallInfo) { panic("boom") }
+
+func TestObserversReceiveToolCalls(t *testing.T) {
+	base, err := agent.New(agent.Config{
+		Name: "assistant",
+		Run: func(agent.InvocationContext) iter.Seq2[*agent.Event, error] {
+			return func(func(*agent.Event, error) bool) {}
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	rec := &recordingObserver{}
+	f := &Flow{agent: &llmAgent{Agent: base, observers: []Observer{&panickingObserver{}, rec}}}
+
+	ctx := agent.NewInvocationContext(context.Background(), agent.InvocationContextParams{})
+	st := &slowTool{release: make(chan struct{})}
+	close(st.release)
+	args := map[string]any{"q": "x"}
+	if _, err := f.callToolWithCallbacks(ctx, st, args, newToolContext(ctx, "call-1")); err != nil {
+		t.Fatalf("callToolWithCallbacks() error = %v", err)
+	}
+
+	if len(rec.tools) != 1 {
+		t.Fatalf("tool calls = %d, want 1", len(rec.tools))
+	}
+	call := rec.tools[0]
+	if call.Name != "slow" || call.ID != "call-1" || call.AgentName != "assistant" || call.Args["q"] != "x" || call.Result["ok"] != true {
+		t.Errorf("call = %+v", call)
+	}
+	if call.EndTime.Before(call.StartTime) {
+		t.Errorf("end %v before start %v", call.EndTime, call.StartTime)
+	}
+}
@@ -24,8 +24,10 @@ import (
 
 	"github.com/kadirpekel/hector/pkg/agent"
 	"github.com/kadirpekel/hector/pkg/instruction"
+	"github.com/kadirpekel/hector/pkg/memory"
 	"github.com/kadirpekel/hector/pkg/model"
 	"github.com/kadirpekel/hector/pkg/tool"
+	"github.com/kadirpekel/hector/pkg/utils"
 )
 
 // RequestProcessor transforms an LLM request before it's sent to the model.
@@ -59,6 +61,12 @@ type processorContext struct {
 	llmAgent        *llmAgent
 	tools           []tool.Tool
 	toolDefinitions []tool.Definition
+
+	// promptBreakdown tracks system prompt sizes for the prompt budget guard
+	promptBreakdown PromptBreakdown
+
+	// sources are the documents the context provider injected
+	sources []agent.ContextSource
 }
 
 func newProcessorContext(ctx agent.InvocationContext, a *llmAgent) *processorContext {
@@ -68,6 +76,10 @@ func newProcessorContext(ctx agent.InvocationContext, a *llmAgent) *processorCon
 	}
 }
 
+func (c *processorContext) recordContextSources(sources []agent.ContextSource) {
+	c.sources = append(c.sources, sources...)
+}
+
 func (c *processorContext) LLMAgent() *llmAgent {
 	return c.llmAgent
 }
@@ -158,10 +170,12 @@ func DefaultRequestProcessors() []RequestProcessor {
 	return []RequestProcessor{
 		ConfigRequestProcessor,        // 1. Apply generate config and output schema
 		InstructionRequestProcessor,   // 2. Resolve instruction templates
-		ToolsRequestProcessor,         // 3. Collect and add tool definitions
-		ContentsRequestProcessor,      // 4. Build conversation history
-		RAGContextRequestProcessor,    // 5. Inject RAG context (after contents)
-		TransferToolsRequestProcessor, // 6. Add agent transfer tools
+		PinnedContextRequestProcessor, // 3. Inject pinned notes (survive trimming)
+		ToolsRequestProcessor,         // 4. Collect and add tool definitions
+		ContentsRequestProcessor,      // 5. Build conversation history
+		RAGContextRequestProcessor,    // 6. Inject RAG context (after contents)
+		PromptBudgetRequestProcessor,  // 7. Check system prompt size
+		TransferToolsRequestProcessor, // 8. Add agent transfer tools
 	}
 }
 
@@ -236,16 +250,53 @@ func InstructionRequestProcessor(ctx ProcessorContext, req *model.Request) error
 		}
 	}
 
-	// Completion instruction from reasoning config
+	// Completion instruction from reasoning config, then response language
 	completionInst := a.buildCompletionInstruction()
+	languageInst := a.responseLanguage.instruction()
+	if b := promptBreakdown(ctx); b != nil {
+		b.Instruction += utils.EstimateTokens(joinInstructions(parts))
+		b.Fragments += utils.EstimateTokens(completionInst) + utils.EstimateTokens(languageInst)
+	}
 	if completionInst != "" {
 		parts = append(parts, completionInst)
 	}
+	if languageInst != "" {
+		parts = append(parts, languageInst)
+	}
 
 	req.SystemInstruction = joinInstructions(parts)
 	return nil
 }
 
+// PinnedContextRequestProcessor appends notes pinned via the pin_context tool
+// to the system instruction. Notes live in session state rather than the
+// conversation history, so working memory trimming never removes them.
+func PinnedContextRequestProcessor(ctx ProcessorContext, req *model.Request) error {
+	notes := memory.PinnedNotes(ctx.ReadonlyState())
+	if len(notes) == 0 {
+		return nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<pinned_context>\n")
+	for _, note := range notes {
+		sb.WriteString("- ")
+		sb.WriteString(note)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("</pinned_context>")
+	if b := promptBreakdown(ctx); b != nil {
+		b.Fragments += utils.EstimateTokens(sb.String())
+	}
+
+	if req.SystemInstruction == "" {
+		req.SystemInstruction = sb.String()
+	} else {
+		req.SystemInstruction = joinInstructions([]string{req.SystemInstruction, sb.String()})
+	}
+	return nil
+}
+
 // ToolsRequestProcessor collects tool definitions and adds them to the request.
 func ToolsRequestProcessor(ctx ProcessorContext, req *model.Request) error {
 	req.Tools = ctx.ToolDefinitions()
@@ -265,6 +316,12 @@ func ContentsRequestProcessor(ctx ProcessorContext, req *model.Request) error {
 	// The session contains all persisted events including tool calls/results
 	req.Messages = a.buildMessages(ctx)
 
+	// Scope the conversation to session and agent so providers that chain
+	// turns server-side never mix histories of different agents.
+	if sessionID := ctx.SessionID(); sessionID != "" {
+		req.ConversationID = sessionID + ":" + a.Name()
+	}
+
 	slog.Debug("ContentsRequestProcessor: built messages from session",
 		"message_count", len(req.Messages),
 		"agent", a.Name())
@@ -310,6 +367,9 @@ func RAGContextRequestProcessor(ctx ProcessorContext, req *model.Request) error
 	// Insert at the beginning of messages (like legacy: after system prompt, before conversation)
 	// This ensures the LLM sees the context before processing the conversation
 	req.Messages = append([]*a2a.Message{contextMsg}, req.Messages...)
+	if b := promptBreakdown(ctx); b != nil {
+		b.RAGContext += utils.EstimateTokens(ragContext)
+	}
 
 	slog.Debug("RAGContextRequestProcessor: injected context",
 		"agent", a.Name(),
@@ -347,8 +407,12 @@ func TransferToolsRequestProcessor(ctx ProcessorContext, req *model.Request) err
 		return nil
 	}
 
-	// Add transfer tools for each sub-agent
+	// Add transfer tools for each sub-agent that can take the call
 	for _, sub := range subAgents {
+		if !agent.IsAvailable(sub) {
+			slog.Debug("Skipping transfer to unavailable agent", "agent", a.Name(), "target", sub.Name())
+			continue
+		}
 		transferTool := tool.Definition{
 			Name:        "transfer_to_" + sub.Name(),
 			Description: fmt.Sprintf("Transfer control to the %s agent. %s", sub.Name(), sub.Description()),
@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmagent
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/kadirpekel/hector/pkg/model"
+)
+
+// PromptBudgetAction is what the prompt budget guard does when the system
+// prompt is over budget.
+type PromptBudgetAction string
+
+const (
+	// PromptBudgetWarn logs a warning with the size breakdown (default).
+	PromptBudgetWarn PromptBudgetAction = "warn"
+
+	// PromptBudgetError fails the request with a PromptBudgetExceededError.
+	PromptBudgetError PromptBudgetAction = "error"
+)
+
+// DefaultPromptBudgetFraction is the default share of the context window the
+// system prompt may use.
+const DefaultPromptBudgetFraction = 0.5
+
+// PromptBudget guards against system prompts that squeeze the conversation
+// out of the context window.
+//
+// The system prompt here covers everything sent ahead of the conversation:
+// the instruction, fragments appended to it (completion instruction, pinned
+// context) and injected RAG context.
+type PromptBudget struct {
+	// ContextWindow is the model's context window in tokens (required).
+	ContextWindow int
+
+	// MaxFraction is the share (0-1) of the context window the system
+	// prompt may use. Default: DefaultPromptBudgetFraction.
+	MaxFraction float64
+
+	// Action is taken when the budget is exceeded. Default: PromptBudgetWarn.
+	Action PromptBudgetAction
+}
+
+// limit returns the token budget for the system prompt.
+func (b *PromptBudget) limit() int {
+	fraction := b.MaxFraction
+	if fraction <= 0 || fraction > 1 {
+		fraction = DefaultPromptBudgetFraction
+	}
+	return int(float64(b.ContextWindow) * fraction)
+}
+
+// PromptBreakdown is the estimated token size of each system prompt section.
+type PromptBreakdown struct {
+	Instruction int
+	Fragments   int
+	RAGContext  int
+}
+
+// Total returns the combined size of all sections.
+func (b PromptBreakdown) Total() int {
+	return b.Instruction + b.Fragments + b.RAGContext
+}
+
+func (b PromptBreakdown) String() string {
+	return fmt.Sprintf("instruction %d, fragments %d, RAG context %d",
+		b.Instruction, b.Fragments, b.RAGContext)
+}
+
+// PromptBudgetExceededError is returned when the system prompt exceeds its
+// budget and the guard is configured to fail.
+type PromptBudgetExceededError struct {
+	Agent     string
+	Breakdown PromptBreakdown
+	Limit     int
+}
+
+func (e *PromptBudgetExceededError) Error() string {
+	return fmt.Sprintf("agent %q system prompt uses ~%d tokens (%s), over its budget of %d tokens",
+		e.Agent, e.Breakdown.Total(), e.Breakdown, e.Limit)
+}
+
+// promptBreakdown returns the breakdown recorded for this request, or nil if
+// the context does not track one.
+func promptBreakdown(ctx ProcessorContext) *PromptBreakdown {
+	if pc, ok := ctx.(*processorContext); ok {
+		return &pc.promptBreakdown
+	}
+	return nil
+}
+
+// PromptBudgetRequestProcessor checks the assembled system prompt against the
+// agent's prompt budget. It runs after the instruction and RAG context have
+// been added.
+func PromptBudgetRequestProcessor(ctx ProcessorContext, req *model.Request) error {
+	a := ctx.LLMAgent()
+	if a == nil || a.promptBudget == nil || a.promptBudget.ContextWindow <= 0 {
+		return nil
+	}
+	breakdown := promptBreakdown(ctx)
+	if breakdown == nil {
+		return nil
+	}
+
+	limit := a.promptBudget.limit()
+	if breakdown.Total() <= limit {
+		return nil
+	}
+
+	if a.promptBudget.Action == PromptBudgetError {
+		return &PromptBudgetExceededError{
+			Agent:     a.Name(),
+			Breakdown: *breakdown,
+			Limit:     limit,
+		}
+	}
+
+	// Warn once per invocation rather than on every reasoning step
+	if prev, _ := a.promptBudgetWarned.Swap(ctx.InvocationID()).(string); prev == ctx.InvocationID() {
+		return nil
+	}
+	slog.Warn("System prompt exceeds its budget",
+		"agent", a.Name(),
+		"tokens", breakdown.Total(),
+		"limit", limit,
+		"context_window", a.promptBudget.ContextWindow,
+		"instruction_tokens", breakdown.Instruction,
+		"fragment_tokens", breakdown.Fragments,
+		"rag_context_tokens", breakdown.RAGContext)
+	return nil
+}
@@ -0,0 +1,52 @@
+package llmagent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/model"
+)
+
+func TestPromptBudgetRequestProcessor(t *testing.T) {
+	newAgent := func(action PromptBudgetAction) *llmAgent {
+		ag, err := New(Config{
+			Name:        "assistant",
+			Model:       &summaryLLM{},
+			Instruction: strings.Repeat("rule ", 100), // ~125 tokens
+			PromptBudget: &PromptBudget{
+				ContextWindow: 400,
+				MaxFraction:   0.25,
+				Action:        action,
+			},
+		})
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		return ag.(*llmAgent)
+	}
+	run := func(a *llmAgent) error {
+		ctx := agent.NewInvocationContext(context.Background(), agent.InvocationContextParams{})
+		procCtx := newProcessorContext(ctx, a)
+		req := &model.Request{}
+		if err := InstructionRequestProcessor(procCtx, req); err != nil {
+			return err
+		}
+		return PromptBudgetRequestProcessor(procCtx, req)
+	}
+
+	if err := run(newAgent(PromptBudgetWarn)); err != nil {
+		t.Errorf("warn action returned error %v", err)
+	}
+
+	err := run(newAgent(PromptBudgetError))
+	var budgetErr *PromptBudgetExceededError
+	if !errors.As(err, &budgetErr) {
+		t.Fatalf("error action returned %v, want PromptBudgetExceededError", err)
+	}
+	if budgetErr.Limit != 100 || budgetErr.Breakdown.Instruction < 100 {
+		t.Errorf("breakdown = %+v, limit = %d", budgetErr.Breakdown, budgetErr.Limit)
+	}
+}
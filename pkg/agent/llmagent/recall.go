@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmagent
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/memory"
+)
+
+// Default recall settings
+const (
+	DefaultRecallTopK = 3
+
+	// recallSnippetMaxLen caps the characters injected per past snippet.
+	recallSnippetMaxLen = 500
+
+	// recallOverfetch is how many extra results are requested, since
+	// results from the current session are dropped.
+	recallOverfetch = 10
+)
+
+// RecallConfig configures recall from the user's past sessions.
+type RecallConfig struct {
+	// Index searches the indexed sessions (required).
+	Index memory.SearchableService
+
+	// TopK is the maximum number of past snippets injected.
+	// Default: 3
+	TopK int
+
+	// MinScore is the minimum relevance score of an injected snippet.
+	// Default: 0 (no minimum)
+	MinScore float64
+}
+
+// NewRecallContextProvider returns a ContextProvider that injects the
+// snippets of the user's past sessions most similar to the user message.
+//
+// Searches are scoped to the invocation's app and user, so sessions never
+// leak across users, and the current session is excluded since it is
+// already in the conversation. Anonymous users (see agent.IsAnonymousUser)
+// get no recall, since all of them share one user ID.
+func NewRecallContextProvider(cfg RecallConfig) (ContextProvider, error) {
+	if cfg.Index == nil {
+		return nil, fmt.Errorf("index is required for recall")
+	}
+
+	topK := cfg.TopK
+	if topK <= 0 {
+		topK = DefaultRecallTopK
+	}
+
+	return func(ctx agent.ReadonlyContext, query string) (string, error) {
+		userID := ctx.UserID()
+		if agent.IsAnonymousUser(userID) {
+			return "", nil // Anonymous users share an ID; recall would leak across callers
+		}
+
+		resp, err := cfg.Index.Search(ctx, &memory.SearchRequest{
+			Query:   query,
+			UserID:  userID,
+			AppName: ctx.AppName(),
+			Limit:   topK + recallOverfetch,
+		})
+		if err != nil {
+			return "", fmt.Errorf("recall search failed: %w", err)
+		}
+
+		var sb strings.Builder
+		seen := make(map[string]bool)
+		recalled := 0
+		for _, r := range resp.Results {
+			if recalled == topK {
+				break
+			}
+			content := strings.TrimSpace(r.Content)
+			if r.SessionID == ctx.SessionID() || r.Score < cfg.MinScore || content == "" || seen[content] {
+				continue
+			}
+			seen[content] = true
+
+			if recalled == 0 {
+				sb.WriteString("Relevant past context from earlier conversations:\n")
+			}
+			author := r.Author
+			if author == "" {
+				author = "unknown"
+			}
+			sb.WriteString("- [")
+			sb.WriteString(author)
+			if !r.Timestamp.IsZero() {
+				sb.WriteString(", ")
+				sb.WriteString(r.Timestamp.Format("2006-01-02"))
+			}
+			sb.WriteString("]: ")
+			sb.WriteString(truncateSnippet(content, recallSnippetMaxLen))
+			sb.WriteString("\n")
+			recalled++
+		}
+
+		if recalled > 0 {
+			slog.Debug("Recalled past context",
+				"agent", ctx.AgentName(),
+				"snippets", recalled)
+		}
+		return strings.TrimSuffix(sb.String(), "\n"), nil
+	}, nil
+}
+
+// truncateSnippet shortens s to at most maxLen runes, marking the cut.
+func truncateSnippet(s string, maxLen int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s
+	}
+	return string(runes[:maxLen]) + "..."
+}
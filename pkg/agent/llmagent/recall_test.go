@@ -0,0 +1,109 @@
+package llmagent
+
+import (
+	"context"
+	"iter"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/memory"
+)
+
+// recallSession is a minimal agent.Session holding text events.
+type recallSession struct {
+	id, userID string
+	events     []*agent.Event
+}
+
+func (s *recallSession) ID() string           { return s.id }
+func (s *recallSession) AppName() string      { return "app" }
+func (s *recallSession) UserID() string       { return s.userID }
+func (s *recallSession) State() agent.State   { return nil }
+func (s *recallSession) Events() agent.Events { return recallEvents(s.events) }
+
+type recallEvents []*agent.Event
+
+func (e recallEvents) All() iter.Seq[*agent.Event] { return slices.Values(e) }
+func (e recallEvents) Len() int                    { return len(e) }
+func (e recallEvents) At(i int) *agent.Event       { return e[i] }
+
+func newRecallSession(id, userID string, texts ...string) *recallSession {
+	s := &recallSession{id: id, userID: userID}
+	for i, text := range texts {
+		s.events = append(s.events, &agent.Event{
+			ID:        id + "-" + string(rune('0'+i)),
+			Author:    agent.AuthorUser,
+			Timestamp: time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC),
+			Message:   a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: text}),
+		})
+	}
+	return s
+}
+
+func TestRecallContextProvider(t *testing.T) {
+	ctx := context.Background()
+	index := memory.NewKeywordIndexService()
+	current := newRecallSession("current", "alice", "planning the kyoto trip itinerary")
+	for _, sess := range []*recallSession{
+		newRecallSession("past", "alice", "my kyoto trip is in april", "unrelated note"),
+		newRecallSession("other", "bob", "bob's kyoto trip budget is secret"),
+		current,
+	} {
+		if err := index.Index(ctx, sess); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recall, err := NewRecallContextProvider(RecallConfig{Index: index, TopK: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invCtx := agent.NewInvocationContext(ctx, agent.InvocationContextParams{Session: current})
+	got, err := recall(invCtx, "kyoto trip")
+	if err != nil {
+		t.Fatalf("recall() error = %v", err)
+	}
+	want := "Relevant past context from earlier conversations:\n- [user, 2026-09-30]: my kyoto trip is in april"
+	if got != want {
+		t.Errorf("recall() = %q, want %q", got, want)
+	}
+	if strings.Contains(got, "bob") || strings.Contains(got, "itinerary") {
+		t.Error("recall() leaked another user's or the current session's events")
+	}
+
+	// Snippets below the minimum score are dropped
+	strict, err := NewRecallContextProvider(RecallConfig{Index: index, MinScore: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := strict(invCtx, "kyoto trip"); got != "" {
+		t.Errorf("recall() with min score = %q, want empty", got)
+	}
+}
+
+func TestRecallSkipsAnonymousUser(t *testing.T) {
+	ctx := context.Background()
+	index := memory.NewKeywordIndexService()
+	past := newRecallSession("past", agent.AnonymousUserID, "my kyoto trip is in april")
+	current := newRecallSession("current", agent.AnonymousUserID, "planning the kyoto trip")
+	for _, sess := range []*recallSession{past, current} {
+		if err := index.Index(ctx, sess); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	recall, err := NewRecallContextProvider(RecallConfig{Index: index})
+	if err != nil {
+		t.Fatal(err)
+	}
+	invCtx := agent.NewInvocationContext(ctx, agent.InvocationContextParams{Session: current})
+	if got, err := recall(invCtx, "kyoto trip"); err != nil || got != "" {
+		t.Errorf("recall() = %q, %v; want no recall for anonymous users", got, err)
+	}
+}
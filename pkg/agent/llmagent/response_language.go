@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmagent
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// DefaultLanguageRetries is the default number of retries for a response in
+// the wrong language.
+const DefaultLanguageRetries = 1
+
+// ResponseLanguage makes the agent answer in a fixed language.
+//
+// The language instruction is appended to the system instruction. With
+// Verify, each final response is checked by its writing system and retried
+// with a stronger directive when it drifted. The check cannot tell apart
+// languages that share a script (e.g., English and French).
+type ResponseLanguage struct {
+	// Language is a language code ("ja", "pt-BR") or name ("Japanese").
+	Language string
+
+	// Verify checks the script of final responses and retries drifted ones.
+	Verify bool
+
+	// MaxRetries caps the retries of a drifted response.
+	// Default: DefaultLanguageRetries.
+	MaxRetries int
+}
+
+// languageInfo describes a language known to the detector.
+type languageInfo struct {
+	name    string
+	scripts []*unicode.RangeTable
+}
+
+var (
+	latinScript    = []*unicode.RangeTable{unicode.Latin}
+	cyrillicScript = []*unicode.RangeTable{unicode.Cyrillic}
+	arabicScript   = []*unicode.RangeTable{unicode.Arabic}
+)
+
+// knownLanguages maps language codes to their name and writing systems.
+var knownLanguages = map[string]languageInfo{
+	"ja": {"Japanese", []*unicode.RangeTable{unicode.Hiragana, unicode.Katakana, unicode.Han}},
+	"zh": {"Chinese", []*unicode.RangeTable{unicode.Han}},
+	"ko": {"Korean", []*unicode.RangeTable{unicode.Hangul, unicode.Han}},
+	"ru": {"Russian", cyrillicScript},
+	"uk": {"Ukrainian", cyrillicScript},
+	"bg": {"Bulgarian", cyrillicScript},
+	"ar": {"Arabic", arabicScript},
+	"fa": {"Persian", arabicScript},
+	"ur": {"Urdu", arabicScript},
+	"he": {"Hebrew", []*unicode.RangeTable{unicode.Hebrew}},
+	"el": {"Greek", []*unicode.RangeTable{unicode.Greek}},
+	"th": {"Thai", []*unicode.RangeTable{unicode.Thai}},
+	"hi": {"Hindi", []*unicode.RangeTable{unicode.Devanagari}},
+	"en": {"English", latinScript},
+	"fr": {"French", latinScript},
+	"de": {"German", latinScript},
+	"es": {"Spanish", latinScript},
+	"it": {"Italian", latinScript},
+	"pt": {"Portuguese", latinScript},
+	"nl": {"Dutch", latinScript},
+	"pl": {"Polish", latinScript},
+	"tr": {"Turkish", latinScript},
+	"vi": {"Vietnamese", latinScript},
+	"id": {"Indonesian", latinScript},
+}
+
+const (
+	// minLanguageCheckLetters is the fewest letters a response needs to be
+	// checked; shorter ones (names, numbers, code) are accepted as is.
+	minLanguageCheckLetters = 20
+
+	// minLanguageScriptShare is the share of letters that must be in the
+	// language's script. It leaves room for product names and identifiers.
+	minLanguageScriptShare = 0.3
+)
+
+// lookupLanguage resolves a language code or name.
+func lookupLanguage(language string) (languageInfo, bool) {
+	code := strings.ToLower(language)
+	if i := strings.IndexAny(code, "-_"); i > 0 {
+		code = code[:i]
+	}
+	if info, ok := knownLanguages[code]; ok {
+		return info, true
+	}
+	for _, info := range knownLanguages {
+		if strings.EqualFold(info.name, language) {
+			return info, true
+		}
+	}
+	return languageInfo{}, false
+}
+
+// name returns the display name of the language.
+func (l *ResponseLanguage) name() string {
+	if info, ok := lookupLanguage(l.Language); ok {
+		return info.name
+	}
+	return l.Language
+}
+
+// instruction returns the system instruction fragment.
+func (l *ResponseLanguage) instruction() string {
+	if l == nil || l.Language == "" {
+		return ""
+	}
+	return fmt.Sprintf("Always respond in %s, regardless of the language of the user's message, "+
+		"tool results or retrieved documents. Keep code, commands and proper names unchanged.", l.name())
+}
+
+// retryDirective returns the message sent when a response drifted.
+func (l *ResponseLanguage) retryDirective() string {
+	return fmt.Sprintf("Your previous response was not in %s. Respond again, entirely in %s.", l.name(), l.name())
+}
+
+// maxRetries returns the retry cap, applying the default.
+func (l *ResponseLanguage) maxRetries() int {
+	if l.MaxRetries <= 0 {
+		return DefaultLanguageRetries
+	}
+	return l.MaxRetries
+}
+
+// drifted reports whether text is written in another script than the
+// language's. Languages the detector doesn't know are never reported.
+func (l *ResponseLanguage) drifted(text string) bool {
+	if l == nil || !l.Verify {
+		return false
+	}
+	info, ok := lookupLanguage(l.Language)
+	if !ok {
+		return false
+	}
+
+	var letters, matching int
+	for _, r := range stripCodeBlocks(text) {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		if unicode.In(r, info.scripts...) {
+			matching++
+		}
+	}
+	if letters < minLanguageCheckLetters {
+		return false
+	}
+	return float64(matching)/float64(letters) < minLanguageScriptShare
+}
+
+// stripCodeBlocks removes fenced code blocks, which are language-neutral.
+func stripCodeBlocks(text string) string {
+	var sb strings.Builder
+	for i, block := range strings.Split(text, "```") {
+		if i%2 == 0 {
+			sb.WriteString(block)
+		}
+	}
+	return sb.String()
+}
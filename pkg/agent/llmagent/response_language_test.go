@@ -0,0 +1,56 @@
+package llmagent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/model"
+)
+
+func TestResponseLanguageDrifted(t *testing.T) {
+	ja := &ResponseLanguage{Language: "ja", Verify: true}
+	tests := []struct {
+		name string
+		lang *ResponseLanguage
+		text string
+		want bool
+	}{
+		{"japanese", ja, "APIキーは環境変数で設定します。設定ファイルに直接書かないでください。", false},
+		{"english", ja, "You can set the API key with an environment variable instead of the config file.", true},
+		{"code only", ja, "```yaml\nllms:\n  default:\n    provider: openai\n    api_key: ${OPENAI_API_KEY}\n```", false},
+		{"too short", ja, "OK, done.", false},
+		{"by name", &ResponseLanguage{Language: "Japanese", Verify: true}, "The weather is nice today in Tokyo, isn't it?", true},
+		{"verify off", &ResponseLanguage{Language: "ja"}, "The weather is nice today in Tokyo, isn't it?", false},
+		{"unknown language", &ResponseLanguage{Language: "tlh", Verify: true}, "The weather is nice today in Tokyo, isn't it?", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.lang.drifted(tt.text); got != tt.want {
+				t.Errorf("drifted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResponseLanguageInstruction(t *testing.T) {
+	ag, err := New(Config{
+		Name:             "assistant",
+		Model:            &summaryLLM{},
+		Instruction:      "You are helpful.",
+		ResponseLanguage: &ResponseLanguage{Language: "ja-JP"},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx := agent.NewInvocationContext(context.Background(), agent.InvocationContextParams{})
+	req := &model.Request{}
+	if err := InstructionRequestProcessor(newProcessorContext(ctx, ag.(*llmAgent)), req); err != nil {
+		t.Fatalf("InstructionRequestProcessor() error = %v", err)
+	}
+	if !strings.HasPrefix(req.SystemInstruction, "You are helpful.") || !strings.Contains(req.SystemInstruction, "Always respond in Japanese") {
+		t.Errorf("system instruction = %q", req.SystemInstruction)
+	}
+}
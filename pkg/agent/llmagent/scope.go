@@ -0,0 +1,260 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmagent
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/embedder"
+)
+
+const (
+	// DefaultScopeThreshold is the similarity below which input is considered
+	// off-topic. Kept low so only clearly unrelated requests are refused.
+	DefaultScopeThreshold = 0.2
+
+	// minScopeTerms is the minimum number of significant words an input needs
+	// before keyword-only classification may refuse it. Short replies like
+	// "yes, do it" carry too little signal to judge.
+	minScopeTerms = 3
+)
+
+// ScopeConfig configures the out-of-scope guard for focused agents.
+type ScopeConfig struct {
+	// AllowedTopics describes what the agent may talk about
+	// (e.g., "billing", "invoices", "payment methods").
+	AllowedTopics []string
+
+	// RefusalMessage is returned when input is out of scope.
+	RefusalMessage string
+
+	// Threshold is the minimum embedding similarity (0-1) to any allowed
+	// topic for input to be considered in scope.
+	// Default: 0.2
+	Threshold float64
+
+	// Embedder enables semantic similarity checks. If nil, only keyword
+	// matching is used.
+	Embedder embedder.Embedder
+}
+
+// NewScopeGuard returns a before-agent callback that refuses clearly
+// off-topic requests with the configured refusal message, skipping the LLM.
+//
+// The guard is conservative: input is allowed if it shares any keyword with
+// an allowed topic, if it has no text (e.g., tool approvals), or if the
+// similarity check cannot be performed. Without an embedder, input is only
+// refused when it has enough words to judge, none match, and it does not
+// follow up on a conversation the agent already took part in.
+func NewScopeGuard(cfg ScopeConfig) agent.BeforeAgentCallback {
+	g := &scopeGuard{
+		cfg:        cfg,
+		topicTerms: make(map[string]bool),
+	}
+	if g.cfg.Threshold <= 0 {
+		g.cfg.Threshold = DefaultScopeThreshold
+	}
+	if g.cfg.RefusalMessage == "" {
+		g.cfg.RefusalMessage = fmt.Sprintf("I'm sorry, I can only help with: %s.", strings.Join(cfg.AllowedTopics, ", "))
+	}
+	for _, topic := range cfg.AllowedTopics {
+		for _, term := range scopeTerms(topic) {
+			g.topicTerms[term] = true
+		}
+	}
+	return g.check
+}
+
+type scopeGuard struct {
+	cfg        ScopeConfig
+	topicTerms map[string]bool
+
+	mu              sync.Mutex
+	topicEmbeddings [][]float32
+}
+
+func (g *scopeGuard) check(ctx agent.CallbackContext) (*a2a.Message, error) {
+	text := userText(ctx.UserContent())
+	if strings.TrimSpace(text) == "" || len(g.cfg.AllowedTopics) == 0 {
+		return nil, nil
+	}
+
+	terms := scopeTerms(text)
+	if g.matchesKeywords(terms) {
+		return nil, nil
+	}
+
+	if g.cfg.Embedder != nil {
+		similarity, err := g.similarity(ctx, text)
+		if err != nil {
+			// Fail open: a broken classifier must not block legitimate requests
+			slog.Debug("Scope check skipped", "agent", ctx.AgentName(), "error", err)
+			return nil, nil
+		}
+		if similarity >= g.cfg.Threshold {
+			return nil, nil
+		}
+		slog.Debug("Refusing out-of-scope request", "agent", ctx.AgentName(), "similarity", similarity)
+		return g.refusal(), nil
+	}
+
+	if len(terms) < minScopeTerms || g.isFollowUp(ctx) {
+		return nil, nil
+	}
+	slog.Debug("Refusing out-of-scope request", "agent", ctx.AgentName())
+	return g.refusal(), nil
+}
+
+// isFollowUp reports whether the agent already answered in this session, so
+// the input continues an in-scope conversation ("explain the second one").
+// Keywords alone cannot judge such references, and refusals don't count.
+func (g *scopeGuard) isFollowUp(ctx agent.CallbackContext) bool {
+	s, ok := ctx.(interface{ Session() agent.Session })
+	if !ok || s.Session() == nil {
+		return false
+	}
+	for event := range s.Session().Events().All() {
+		if event.Author != ctx.AgentName() || event.Partial || event.Message == nil {
+			continue
+		}
+		text := strings.TrimSpace(userText(&agent.Content{Parts: event.Message.Parts}))
+		if text != "" && text != g.cfg.RefusalMessage {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *scopeGuard) refusal() *a2a.Message {
+	return a2a.NewMessage(a2a.MessageRoleAgent, a2a.TextPart{Text: g.cfg.RefusalMessage})
+}
+
+// matchesKeywords reports whether any term matches an allowed topic term,
+// tolerating simple inflections (e.g., "invoices" vs "invoice").
+func (g *scopeGuard) matchesKeywords(terms []string) bool {
+	for _, term := range terms {
+		if g.topicTerms[term] {
+			return true
+		}
+		for topicTerm := range g.topicTerms {
+			if len(term) >= 4 && len(topicTerm) >= 4 &&
+				(strings.HasPrefix(term, topicTerm) || strings.HasPrefix(topicTerm, term)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// similarity returns the highest cosine similarity between text and any
+// allowed topic. Topic embeddings are computed on first use and cached.
+func (g *scopeGuard) similarity(ctx context.Context, text string) (float64, error) {
+	topics, err := g.topicVectors(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to embed topics: %w", err)
+	}
+
+	vec, err := g.cfg.Embedder.Embed(ctx, text)
+	if err != nil {
+		return 0, fmt.Errorf("failed to embed input: %w", err)
+	}
+
+	best := -1.0
+	for _, topic := range topics {
+		best = max(best, cosineSimilarity(vec, topic))
+	}
+	return best, nil
+}
+
+func (g *scopeGuard) topicVectors(ctx context.Context) ([][]float32, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.topicEmbeddings == nil {
+		vectors, err := g.cfg.Embedder.EmbedBatch(ctx, g.cfg.AllowedTopics)
+		if err != nil {
+			return nil, err
+		}
+		g.topicEmbeddings = vectors
+	}
+	return g.topicEmbeddings, nil
+}
+
+func userText(content *agent.Content) string {
+	if content == nil {
+		return ""
+	}
+	var sb strings.Builder
+	for _, part := range content.Parts {
+		if tp, ok := part.(a2a.TextPart); ok {
+			sb.WriteString(tp.Text)
+			sb.WriteString(" ")
+		}
+	}
+	return sb.String()
+}
+
+// scopeStopWords are ignored when matching keywords.
+var scopeStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "are": true, "but": true, "not": true,
+	"you": true, "your": true, "can": true, "how": true, "what": true, "why": true,
+	"when": true, "where": true, "who": true, "which": true, "this": true, "that": true,
+	"with": true, "from": true, "about": true, "have": true, "has": true, "was": true,
+	"were": true, "will": true, "would": true, "could": true, "should": true, "does": true,
+	"did": true, "please": true, "tell": true, "help": true, "want": true, "need": true,
+	"know": true, "there": true, "their": true, "they": true, "them": true, "its": true,
+	"into": true, "any": true, "all": true, "some": true, "get": true, "give": true,
+}
+
+// scopeTerms extracts lowercase significant words from text.
+func scopeTerms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := words[:0]
+	for _, w := range words {
+		if len(w) >= 3 && !scopeStopWords[w] {
+			terms = append(terms, w)
+		}
+	}
+	return terms
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
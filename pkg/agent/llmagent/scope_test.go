@@ -0,0 +1,87 @@
+package llmagent
+
+import (
+	"context"
+	"iter"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+)
+
+func TestScopeGuardKeywordMatching(t *testing.T) {
+	g := &scopeGuard{topicTerms: make(map[string]bool)}
+	for _, topic := range []string{"billing", "invoice", "payment methods"} {
+		for _, term := range scopeTerms(topic) {
+			g.topicTerms[term] = true
+		}
+	}
+
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"Why was my card charged twice on the last invoices?", true},
+		{"How do I update my payment method?", true},
+		{"Write me a poem about the ocean at night", false},
+	}
+
+	for _, tt := range tests {
+		if got := g.matchesKeywords(scopeTerms(tt.input)); got != tt.want {
+			t.Errorf("matchesKeywords(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestScopeGuardAllowsFollowUps(t *testing.T) {
+	billing, err := agent.New(agent.Config{
+		Name: "billing",
+		Run: func(agent.InvocationContext) iter.Seq2[*agent.Event, error] {
+			return func(func(*agent.Event, error) bool) {}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	guard := NewScopeGuard(ScopeConfig{AllowedTopics: []string{"billing", "invoices"}, RefusalMessage: "Billing only."})
+
+	check := func(sess *recallSession) *a2a.Message {
+		t.Helper()
+		ctx := agent.NewInvocationContext(context.Background(), agent.InvocationContextParams{
+			Agent:       billing,
+			Session:     sess,
+			UserContent: agent.NewTextContent("Explain the second line item again more slowly", a2a.MessageRoleUser),
+		})
+		refusal, err := guard(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return refusal
+	}
+
+	// A new conversation without matching keywords is refused
+	if check(newRecallSession("new", "alice")) == nil {
+		t.Error("off-topic first message was not refused")
+	}
+
+	// The same words follow up on an answered question
+	answered := newRecallSession("answered", "alice", "Why is my invoice higher this month?")
+	answered.events = append(answered.events, &agent.Event{
+		Author:  "billing",
+		Message: a2a.NewMessage(a2a.MessageRoleAgent, a2a.TextPart{Text: "Your invoice has two new line items."}),
+	})
+	if refusal := check(answered); refusal != nil {
+		t.Errorf("follow-up was refused: %v", refusal)
+	}
+
+	// A previous refusal does not make the conversation in scope
+	refused := newRecallSession("refused", "alice", "Write me a poem")
+	refused.events = append(refused.events, &agent.Event{
+		Author:  "billing",
+		Message: a2a.NewMessage(a2a.MessageRoleAgent, a2a.TextPart{Text: "Billing only."}),
+	})
+	if check(refused) == nil {
+		t.Error("message after a refusal was not refused")
+	}
+}
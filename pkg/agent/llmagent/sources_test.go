@@ -0,0 +1,46 @@
+package llmagent
+
+import (
+	"context"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/model"
+)
+
+func TestRAGContextRecordsSources(t *testing.T) {
+	want := agent.ContextSource{Store: "docs", DocumentID: "guide.md", Title: "Guide", Path: "docs/guide.md", Score: 0.9}
+	ag, err := New(Config{
+		Name:  "assistant",
+		Model: &summaryLLM{},
+		ContextProvider: func(ctx agent.ReadonlyContext, query string) (string, error) {
+			RecordContextSources(ctx, want)
+			return "Relevant context from documents:\n...", nil
+		},
+		AttachSources: true,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx := agent.NewInvocationContext(context.Background(), agent.InvocationContextParams{})
+	procCtx := newProcessorContext(ctx, ag.(*llmAgent))
+	req := &model.Request{Messages: []*a2a.Message{
+		a2a.NewMessage(a2a.MessageRoleUser, &a2a.TextPart{Text: "how do I configure retries?"}),
+	}}
+	if err := RAGContextRequestProcessor(procCtx, req); err != nil {
+		t.Fatalf("RAGContextRequestProcessor() error = %v", err)
+	}
+
+	if len(req.Messages) != 2 {
+		t.Fatalf("messages = %d, want context message injected", len(req.Messages))
+	}
+	if len(procCtx.sources) != 1 || procCtx.sources[0] != want {
+		t.Errorf("sources = %+v, want [%+v]", procCtx.sources, want)
+	}
+
+	// Recording is a no-op outside a processor context
+	RecordContextSources(ctx, want)
+}
@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmagent
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/model"
+	"github.com/kadirpekel/hector/pkg/tool"
+	"github.com/kadirpekel/hector/pkg/utils"
+)
+
+// ToolResultSummarization condenses oversized tool results with an LLM
+// before they are returned to the model, instead of letting them be
+// truncated. This trades a small LLM call for context window space.
+type ToolResultSummarization struct {
+	// MaxTokens is the (estimated) result size above which results are summarized.
+	MaxTokens int
+
+	// LLM summarizes results. If nil, the agent's model is used.
+	LLM model.LLM
+}
+
+const toolResultSummaryPrompt = `Summarize the following tool result so it can replace the full result in a conversation with limited context.
+
+Guidelines:
+- Keep every fact likely to matter for the task: names, numbers, identifiers, paths, URLs, errors
+- Keep the structure (lists, sections) where it helps
+- Drop boilerplate, repetition and formatting noise
+- Do not add information that is not in the result
+- Keep the summary under %d tokens
+
+Tool: %s
+Arguments: %s
+
+Result:
+%s`
+
+// toolResultSummarization returns the summarization settings for a tool.
+func (a *llmAgent) toolResultSummarization(ctx agent.ReadonlyContext, toolName string) (ToolResultSummarization, bool) {
+	return lookupToolSetting(ctx, a.toolsets, a.toolSummarization, toolName)
+}
+
+// lookupToolSetting returns the per-tool setting for a tool. Settings are
+// looked up by tool name first, then by the name of the toolset providing
+// the tool.
+func lookupToolSetting[T any](ctx agent.ReadonlyContext, toolsets []tool.Toolset, settings map[string]T, toolName string) (T, bool) {
+	var zero T
+	if len(settings) == 0 {
+		return zero, false
+	}
+	if cfg, ok := settings[toolName]; ok {
+		return cfg, true
+	}
+
+	for _, ts := range toolsets {
+		cfg, ok := settings[ts.Name()]
+		if !ok {
+			continue
+		}
+		tools, err := ts.Tools(ctx)
+		if err != nil {
+			continue
+		}
+		for _, t := range tools {
+			if t.Name() == toolName {
+				return cfg, true
+			}
+		}
+	}
+	return zero, false
+}
+
+// summarizeToolResult replaces a result that exceeds the tool's token budget
+// with an LLM summary. The original result is kept if summarization fails.
+func (f *Flow) summarizeToolResult(ctx agent.InvocationContext, tc tool.ToolCall, result string) string {
+	cfg, ok := f.agent.toolResultSummarization(ctx, tc.Name)
+	if !ok || cfg.MaxTokens <= 0 {
+		return result
+	}
+	tokens := utils.EstimateTokens(result)
+	if tokens <= cfg.MaxTokens {
+		return result
+	}
+
+	llm := cfg.LLM
+	if llm == nil {
+		llm = f.agent.model
+	}
+
+	args, _ := json.Marshal(tc.Args)
+	maxTokens := cfg.MaxTokens
+	req := &model.Request{
+		Messages: []*a2a.Message{
+			a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{
+				Text: fmt.Sprintf(toolResultSummaryPrompt, cfg.MaxTokens, tc.Name, args, result),
+			}),
+		},
+		Config: &model.GenerateConfig{MaxTokens: &maxTokens},
+	}
+
+	var summary string
+	for resp, err := range llm.GenerateContent(ctx, req, false) {
+		if err != nil {
+			slog.Warn("Tool result summarization failed, using full result",
+				"tool", tc.Name, "tokens", tokens, "error", err)
+			return result
+		}
+		if resp != nil && !resp.Partial {
+			summary = resp.TextContent()
+		}
+	}
+
+	summary = strings.TrimSpace(summary)
+	if summary == "" {
+		return result
+	}
+
+	slog.Debug("Summarized tool result", "tool", tc.Name, "summarizer", llm.Name(),
+		"tokens", tokens, "summary_tokens", utils.EstimateTokens(summary))
+	return fmt.Sprintf("[Summary of a %d-token result]\n%s", tokens, summary)
+}
@@ -0,0 +1,108 @@
+package llmagent
+
+import (
+	"context"
+	"iter"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/model"
+	"github.com/kadirpekel/hector/pkg/tool"
+)
+
+type summaryLLM struct {
+	calls  int
+	prompt string
+}
+
+func (s *summaryLLM) Name() string             { return "summarizer" }
+func (s *summaryLLM) Provider() model.Provider { return model.ProviderOpenAI }
+func (s *summaryLLM) Close() error             { return nil }
+
+func (s *summaryLLM) GenerateContent(ctx context.Context, req *model.Request, stream bool) iter.Seq2[*model.Response, error] {
+	return func(yield func(*model.Response, error) bool) {
+		s.calls++
+		s.prompt = req.Messages[0].Parts[0].(a2a.TextPart).Text
+		yield(&model.Response{
+			Content: &model.Content{Parts: []a2a.Part{a2a.TextPart{Text: "  key facts  "}}},
+		}, nil)
+	}
+}
+
+func TestSummarizeToolResult(t *testing.T) {
+	llm := &summaryLLM{}
+	f := &Flow{agent: &llmAgent{
+		toolSummarization: map[string]ToolResultSummarization{
+			"web_request": {MaxTokens: 10, LLM: llm},
+		},
+	}}
+
+	small := "short result"
+	if got := f.summarizeToolResult(nil, tool.ToolCall{Name: "web_request"}, small); got != small {
+		t.Errorf("small result changed to %q", got)
+	}
+
+	large := strings.Repeat("lorem ipsum ", 20)
+	if got := f.summarizeToolResult(nil, tool.ToolCall{Name: "grep_search"}, large); got != large {
+		t.Errorf("result of unconfigured tool changed to %q", got)
+	}
+
+	got := f.summarizeToolResult(nil, tool.ToolCall{Name: "web_request"}, large)
+	if !strings.HasSuffix(got, "\nkey facts") || !strings.HasPrefix(got, "[Summary of a 60-token result]") {
+		t.Errorf("summarized result = %q", got)
+	}
+	if llm.calls != 1 {
+		t.Errorf("summarizer calls = %d, want 1", llm.calls)
+	}
+}
+
+// bigTool returns a result of the given size.
+type bigTool struct{ size int }
+
+func (b *bigTool) Name() string           { return "big" }
+func (b *bigTool) Description() string    { return "returns a large result" }
+func (b *bigTool) IsLongRunning() bool    { return false }
+func (b *bigTool) RequiresApproval() bool { return false }
+func (b *bigTool) Schema() map[string]any { return nil }
+
+func (b *bigTool) Call(ctx tool.Context, args map[string]any) (map[string]any, error) {
+	return map[string]any{"data": strings.Repeat("lorem ", b.size/6) + "tail"}, nil
+}
+
+func TestToolResultSummarizedBeforeOutputLimit(t *testing.T) {
+	base, err := agent.New(agent.Config{
+		Name: "assistant",
+		Run: func(agent.InvocationContext) iter.Seq2[*agent.Event, error] {
+			return func(func(*agent.Event, error) bool) {}
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	llm := &summaryLLM{}
+	f := &Flow{agent: &llmAgent{
+		Agent:             base,
+		reasoning:         &ReasoningConfig{},
+		tools:             []tool.Tool{&bigTool{size: 1000}},
+		toolSummarization: map[string]ToolResultSummarization{"big": {MaxTokens: 10, LLM: llm}},
+		toolLimitSettings: map[string]ToolLimits{"big": {MaxOutputBytes: 100}},
+	}}
+	ctx := agent.NewInvocationContext(context.Background(), agent.InvocationContextParams{})
+
+	event, err := f.handleToolCalls(ctx, &model.Response{ToolCalls: []tool.ToolCall{{ID: "call-1", Name: "big"}}}, nil)
+	if err != nil {
+		t.Fatalf("handleToolCalls() error = %v", err)
+	}
+
+	// The summarizer sees the whole result and its summary fits the limit
+	if !strings.Contains(llm.prompt, "tail") {
+		t.Error("summarizer did not see the end of the result")
+	}
+	got := event.ToolResults[0].Content
+	if strings.Contains(got, "[output truncated") || !strings.HasSuffix(got, "key facts") {
+		t.Errorf("tool result = %q, want the untruncated summary", got)
+	}
+}
@@ -0,0 +1,142 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmagent
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/tool"
+)
+
+// ToolErrorMode controls what happens when a tool call fails.
+type ToolErrorMode string
+
+const (
+	// ToolErrorFeedback returns the error to the model as the tool result
+	// so it can recover on its own. This is the default.
+	ToolErrorFeedback ToolErrorMode = "feedback"
+
+	// ToolErrorFail aborts the run with a ToolFailedError.
+	ToolErrorFail ToolErrorMode = "fail"
+
+	// ToolErrorRetry re-invokes the tool with exponential backoff, then
+	// falls back to feedback once the retries are exhausted.
+	ToolErrorRetry ToolErrorMode = "retry"
+)
+
+// Defaults for ToolErrorRetry.
+const (
+	DefaultToolMaxRetries   = 2
+	DefaultToolRetryBackoff = 500 * time.Millisecond
+)
+
+// ToolErrorPolicy configures error handling for a tool.
+type ToolErrorPolicy struct {
+	// Mode selects how failures are handled. Empty means ToolErrorFeedback.
+	Mode ToolErrorMode
+
+	// MaxRetries is the number of additional attempts for ToolErrorRetry.
+	// Defaults to DefaultToolMaxRetries.
+	MaxRetries int
+
+	// Backoff is the delay before the first retry. It doubles on every
+	// subsequent attempt. Defaults to DefaultToolRetryBackoff.
+	Backoff time.Duration
+}
+
+// ToolFailedError is returned from a run when a tool configured with
+// ToolErrorFail fails.
+type ToolFailedError struct {
+	Tool string
+	Err  error
+}
+
+func (e *ToolFailedError) Error() string {
+	return fmt.Sprintf("tool %q failed: %v", e.Tool, e.Err)
+}
+
+func (e *ToolFailedError) Unwrap() error {
+	return e.Err
+}
+
+// toolErrorPolicy returns the error policy for a tool.
+func (a *llmAgent) toolErrorPolicy(ctx agent.ReadonlyContext, toolName string) ToolErrorPolicy {
+	policy, _ := lookupToolSetting(ctx, a.toolsets, a.toolErrorPolicies, toolName)
+	return policy
+}
+
+// callTool executes a tool and applies its retry policy. Only the final
+// error is returned; intermediate failures are logged.
+func (f *Flow) callTool(
+	ctx agent.InvocationContext,
+	t tool.Tool,
+	args map[string]any,
+	toolCtx tool.Context,
+) (map[string]any, error) {
+	result, err := f.callToolWithCallbacks(ctx, t, args, toolCtx)
+	if err == nil {
+		return result, nil
+	}
+
+	policy := f.agent.toolErrorPolicy(ctx, t.Name())
+	if policy.Mode != ToolErrorRetry {
+		return nil, err
+	}
+
+	maxRetries := policy.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = DefaultToolMaxRetries
+	}
+	backoff := policy.Backoff
+	if backoff <= 0 {
+		backoff = DefaultToolRetryBackoff
+	}
+
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		slog.Warn("Tool call failed, retrying",
+			"tool", t.Name(),
+			"attempt", attempt,
+			"backoff", backoff,
+			"error", err)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+		backoff *= 2
+
+		result, err = f.callToolWithCallbacks(ctx, t, args, toolCtx)
+		if err == nil {
+			return result, nil
+		}
+	}
+	return nil, err
+}
+
+// toolFailure returns a ToolFailedError if the tool's policy aborts the
+// run on failure, or nil if the error should be fed back to the model.
+func (f *Flow) toolFailure(ctx agent.ReadonlyContext, toolName string, err error) error {
+	if f.agent.toolErrorPolicy(ctx, toolName).Mode != ToolErrorFail {
+		return nil
+	}
+	return &ToolFailedError{Tool: toolName, Err: err}
+}
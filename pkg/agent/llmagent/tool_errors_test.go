@@ -0,0 +1,119 @@
+package llmagent
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/tool"
+)
+
+type flakyTool struct {
+	failures int
+	calls    int
+}
+
+func (f *flakyTool) Name() string           { return "flaky" }
+func (f *flakyTool) Description() string    { return "fails a few times" }
+func (f *flakyTool) IsLongRunning() bool    { return false }
+func (f *flakyTool) RequiresApproval() bool { return false }
+func (f *flakyTool) Schema() map[string]any { return nil }
+
+func (f *flakyTool) Call(ctx tool.Context, args map[string]any) (map[string]any, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, errors.New("unavailable")
+	}
+	return map[string]any{"ok": true}, nil
+}
+
+func TestCallToolErrorPolicy(t *testing.T) {
+	ctx := agent.NewInvocationContext(context.Background(), agent.InvocationContextParams{})
+
+	tests := []struct {
+		name      string
+		policy    *ToolErrorPolicy
+		failures  int
+		wantCalls int
+		wantErr   bool
+	}{
+		{"feedback does not retry", nil, 1, 1, true},
+		{"retry recovers", &ToolErrorPolicy{Mode: ToolErrorRetry, Backoff: time.Millisecond}, 2, 3, false},
+		{"retry exhausted", &ToolErrorPolicy{Mode: ToolErrorRetry, MaxRetries: 1, Backoff: time.Millisecond}, 5, 2, true},
+	}
+	for _, tt := range tests {
+		ft := &flakyTool{failures: tt.failures}
+		a := &llmAgent{}
+		if tt.policy != nil {
+			a.toolErrorPolicies = map[string]ToolErrorPolicy{"flaky": *tt.policy}
+		}
+		f := &Flow{agent: a}
+
+		_, err := f.callTool(ctx, ft, nil, nil)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+		if ft.calls != tt.wantCalls {
+			t.Errorf("%s: calls = %d, want %d", tt.name, ft.calls, tt.wantCalls)
+		}
+	}
+
+	f := &Flow{agent: &llmAgent{toolErrorPolicies: map[string]ToolErrorPolicy{"flaky": {Mode: ToolErrorFail}}}}
+	var failed *ToolFailedError
+	if err := f.toolFailure(ctx, "flaky", errors.New("boom")); !errors.As(err, &failed) || failed.Tool != "flaky" {
+		t.Errorf("toolFailure() = %v, want ToolFailedError", err)
+	}
+	if err := f.toolFailure(ctx, "other", errors.New("boom")); err != nil {
+		t.Errorf("toolFailure() for unconfigured tool = %v, want nil", err)
+	}
+}
+
+type approvedFlakyTool struct{ flakyTool }
+
+func (t *approvedFlakyTool) RequiresApproval() bool { return true }
+
+func TestApprovedPendingToolFollowsFailPolicy(t *testing.T) {
+	sess := newRecallSession("s", "alice")
+	sess.events = append(sess.events, &agent.Event{
+		Author: "assistant",
+		Message: a2a.NewMessage(a2a.MessageRoleAgent, a2a.DataPart{Data: map[string]any{
+			"type": "tool_use",
+			"id":   "call-1",
+			"name": "flaky",
+		}}),
+	})
+	ctx := agent.NewInvocationContext(context.Background(), agent.InvocationContextParams{Session: sess})
+
+	ft := &approvedFlakyTool{flakyTool{failures: 1}}
+	f := &Flow{
+		agent: &llmAgent{
+			reasoning:         &ReasoningConfig{},
+			tools:             []tool.Tool{ft},
+			toolErrorPolicies: map[string]ToolErrorPolicy{"flaky": {Mode: ToolErrorFail}},
+		},
+		approvalDecisions: map[string]string{"id:call-1": "approve"},
+	}
+
+	var gotErr error
+	var events int
+	cont := f.executePendingApprovedTools(ctx, func(ev *agent.Event, err error) bool {
+		if err != nil {
+			gotErr = err
+		} else {
+			events++
+		}
+		return true
+	})
+
+	var failed *ToolFailedError
+	if cont || !errors.As(gotErr, &failed) || failed.Tool != "flaky" {
+		t.Errorf("executePendingApprovedTools() = %v with error %v, want stop with ToolFailedError", cont, gotErr)
+	}
+	if events != 0 {
+		t.Errorf("yielded %d result events, want none", events)
+	}
+}
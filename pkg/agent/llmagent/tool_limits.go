@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmagent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/tool"
+)
+
+// Defaults for ToolLimits. They are generous so that only runaway calls
+// are affected.
+const (
+	DefaultToolTimeout        = 10 * time.Minute
+	DefaultToolMaxOutputBytes = 1 << 20 // 1MB
+)
+
+// ErrToolTimeout is the error of a tool call that exceeded its timeout.
+// Check for it with errors.Is; the returned error names the tool.
+var ErrToolTimeout = errors.New("tool call timed out")
+
+// ToolLimits bounds the duration and output size of a tool's calls.
+type ToolLimits struct {
+	// Timeout cancels a call that runs longer. Each retry gets the full
+	// timeout again. Defaults to DefaultToolTimeout.
+	Timeout time.Duration
+
+	// MaxOutputBytes truncates longer results before they reach the model.
+	// Defaults to DefaultToolMaxOutputBytes.
+	MaxOutputBytes int
+}
+
+// toolLimits returns the limits for a tool, with defaults applied.
+func (a *llmAgent) toolLimits(ctx agent.ReadonlyContext, toolName string) ToolLimits {
+	limits, _ := lookupToolSetting(ctx, a.toolsets, a.toolLimitSettings, toolName)
+	if limits.Timeout <= 0 {
+		limits.Timeout = DefaultToolTimeout
+	}
+	if limits.MaxOutputBytes <= 0 {
+		limits.MaxOutputBytes = DefaultToolMaxOutputBytes
+	}
+	return limits
+}
+
+// boundedToolCall is a tool call bounded by the tool's timeout. Its
+// context records actions separately from the caller's tool context so
+// an abandoned call cannot race with the flow.
+type boundedToolCall struct {
+	ctx     tool.Context
+	parent  *toolContext
+	callCtx context.Context
+	cancel  context.CancelFunc
+}
+
+// withToolTimeout bounds a call made with toolCtx by the timeout. The
+// deadline is derived from ctx; tool contexts not created by the flow are
+// passed to the tool unchanged.
+func withToolTimeout(ctx agent.InvocationContext, toolCtx tool.Context, toolName string, timeout time.Duration) *boundedToolCall {
+	cause := fmt.Errorf("%w: tool %q did not finish within %s", ErrToolTimeout, toolName, timeout)
+	callCtx, cancel := context.WithTimeoutCause(ctx, timeout, cause)
+
+	tc, ok := toolCtx.(*toolContext)
+	if !ok {
+		return &boundedToolCall{ctx: toolCtx, callCtx: callCtx, cancel: cancel}
+	}
+	invCtx := agent.WithContext(tc.invCtx, callCtx)
+	return &boundedToolCall{
+		ctx: &toolContext{
+			CallbackContext: newCallbackContextFromInvocation(invCtx),
+			functionCallID:  tc.functionCallID,
+			actions:         &agent.EventActions{StateDelta: make(map[string]any)},
+			invCtx:          invCtx,
+		},
+		parent:  tc,
+		callCtx: callCtx,
+		cancel:  cancel,
+	}
+}
+
+// timeoutErr returns the ErrToolTimeout of a call that hit its deadline.
+func (b *boundedToolCall) timeoutErr() error {
+	if err := context.Cause(b.callCtx); errors.Is(err, ErrToolTimeout) {
+		return err
+	}
+	return nil
+}
+
+// commit merges the actions of a finished call into the caller's context.
+func (b *boundedToolCall) commit() {
+	if b.parent != nil {
+		mergeEventActions(b.parent.actions, b.ctx.Actions())
+	}
+}
+
+// runWithTimeout runs call with a tool context bounded by the tool's
+// timeout. It returns once the timeout passes even if the tool ignores
+// cancellation; the abandoned call's result and actions are discarded.
+func (f *Flow) runWithTimeout(
+	ctx agent.InvocationContext,
+	t tool.Tool,
+	toolCtx tool.Context,
+	call func(tool.Context) (map[string]any, error),
+) (map[string]any, error) {
+	timeout := f.agent.toolLimits(ctx, t.Name()).Timeout
+	bc := withToolTimeout(ctx, toolCtx, t.Name(), timeout)
+	defer bc.cancel()
+
+	type outcome struct {
+		result map[string]any
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		result, err := call(bc.ctx)
+		done <- outcome{result, err}
+	}()
+
+	select {
+	case out := <-done:
+		if err := bc.timeoutErr(); err != nil && out.err != nil {
+			// The tool returned its own error on cancellation
+			return nil, f.toolTimedOut(t.Name(), timeout, err)
+		}
+		bc.commit()
+		return out.result, out.err
+	case <-bc.callCtx.Done():
+		if err := bc.timeoutErr(); err != nil {
+			return nil, f.toolTimedOut(t.Name(), timeout, err)
+		}
+		return nil, context.Cause(bc.callCtx)
+	}
+}
+
+// toolTimedOut logs and records a tool timeout and returns err.
+func (f *Flow) toolTimedOut(toolName string, timeout time.Duration, err error) error {
+	slog.Warn("Tool call timed out", "tool", toolName, "timeout", timeout)
+	if f.agent.metricsRecorder != nil {
+		f.agent.metricsRecorder.RecordToolError(toolName, "timeout")
+	}
+	return err
+}
+
+// limitToolOutput truncates a result that exceeds the tool's output limit,
+// appending a marker that tells the model how much was cut.
+func (f *Flow) limitToolOutput(ctx agent.ReadonlyContext, toolName, result string) string {
+	limit := f.agent.toolLimits(ctx, toolName).MaxOutputBytes
+	if len(result) <= limit {
+		return result
+	}
+
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(result[cut]) {
+		cut--
+	}
+	slog.Warn("Tool output truncated", "tool", toolName, "bytes", len(result), "limit", limit)
+	if f.agent.metricsRecorder != nil {
+		f.agent.metricsRecorder.RecordToolOutputTruncated(toolName)
+	}
+	return fmt.Sprintf("%s\n\n[output truncated: showing %d of %d bytes]", result[:cut], cut, len(result))
+}
@@ -0,0 +1,123 @@
+package llmagent
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/observability"
+	"github.com/kadirpekel/hector/pkg/tool"
+)
+
+// slowTool blocks until released, optionally ignoring cancellation.
+type slowTool struct {
+	cooperative bool
+	release     chan struct{}
+}
+
+func (s *slowTool) Name() string           { return "slow" }
+func (s *slowTool) Description() string    { return "takes its time" }
+func (s *slowTool) IsLongRunning() bool    { return false }
+func (s *slowTool) RequiresApproval() bool { return false }
+func (s *slowTool) Schema() map[string]any { return nil }
+
+func (s *slowTool) Call(ctx tool.Context, args map[string]any) (map[string]any, error) {
+	ctx.Actions().StateDelta["slow"] = "done"
+	done := ctx.Done()
+	if !s.cooperative {
+		done = nil
+	}
+	select {
+	case <-s.release:
+		return map[string]any{"ok": true}, nil
+	case <-done:
+		return nil, ctx.Err()
+	}
+}
+
+// limitRecorder counts timeouts and truncations.
+type limitRecorder struct {
+	observability.NoopMetrics
+	errors    []string
+	truncated []string
+}
+
+func (r *limitRecorder) RecordToolError(toolName, errorType string) {
+	r.errors = append(r.errors, toolName+":"+errorType)
+}
+
+func (r *limitRecorder) RecordToolOutputTruncated(toolName string) {
+	r.truncated = append(r.truncated, toolName)
+}
+
+func TestToolTimeout(t *testing.T) {
+	ctx := agent.NewInvocationContext(context.Background(), agent.InvocationContextParams{})
+
+	for _, cooperative := range []bool{true, false} {
+		st := &slowTool{cooperative: cooperative, release: make(chan struct{})}
+		rec := &limitRecorder{}
+		f := &Flow{agent: &llmAgent{
+			toolLimitSettings: map[string]ToolLimits{"slow": {Timeout: 20 * time.Millisecond}},
+			metricsRecorder:   rec,
+		}}
+		toolCtx := newToolContext(ctx, "call-1")
+
+		start := time.Now()
+		_, err := f.callTool(ctx, st, nil, toolCtx)
+		if !errors.Is(err, ErrToolTimeout) || !strings.Contains(err.Error(), `"slow"`) {
+			t.Errorf("cooperative=%v: err = %v, want ErrToolTimeout", cooperative, err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("cooperative=%v: timeout took %v", cooperative, elapsed)
+		}
+		if _, ok := toolCtx.Actions().StateDelta["slow"]; ok {
+			t.Errorf("cooperative=%v: actions of a timed-out call were kept", cooperative)
+		}
+		if len(rec.errors) != 1 || rec.errors[0] != "slow:timeout" {
+			t.Errorf("cooperative=%v: recorded errors = %v", cooperative, rec.errors)
+		}
+		close(st.release)
+	}
+
+	// Calls that finish in time keep their result and actions
+	st := &slowTool{release: make(chan struct{})}
+	close(st.release)
+	f := &Flow{agent: &llmAgent{}}
+	toolCtx := newToolContext(ctx, "call-2")
+	result, err := f.callTool(ctx, st, nil, toolCtx)
+	if err != nil || result["ok"] != true {
+		t.Fatalf("callTool() = %v, %v", result, err)
+	}
+	if toolCtx.Actions().StateDelta["slow"] != "done" {
+		t.Errorf("actions = %v", toolCtx.Actions().StateDelta)
+	}
+}
+
+func TestLimitToolOutput(t *testing.T) {
+	rec := &limitRecorder{}
+	f := &Flow{agent: &llmAgent{
+		toolLimitSettings: map[string]ToolLimits{"big": {MaxOutputBytes: 10}},
+		metricsRecorder:   rec,
+	}}
+
+	if got := f.limitToolOutput(nil, "big", "short"); got != "short" {
+		t.Errorf("limitToolOutput() = %q, want unchanged", got)
+	}
+
+	// The cut never splits a multi-byte character
+	got := f.limitToolOutput(nil, "big", "abcdefghié and more")
+	if !strings.HasPrefix(got, "abcdefghi\n\n[output truncated: showing 9 of 20 bytes]") {
+		t.Errorf("limitToolOutput() = %q", got)
+	}
+	if len(rec.truncated) != 1 || rec.truncated[0] != "big" {
+		t.Errorf("recorded truncations = %v", rec.truncated)
+	}
+
+	large := strings.Repeat("x", DefaultToolMaxOutputBytes+1)
+	if got := f.limitToolOutput(nil, "other", large); !strings.Contains(got, "[output truncated") {
+		t.Error("Expected default limit to apply to tools without limits")
+	}
+}
@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmagent
+
+import (
+	"context"
+	"iter"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/model"
+	"github.com/kadirpekel/hector/pkg/observability"
+)
+
+// generateContent calls the model inside an LLM span.
+//
+// The span inherits the request's correlation IDs (session, user, task) set
+// by the executor. The agent name is added to the context so providers and
+// LLM wrappers can attribute the call; metrics are labeled with it.
+func (f *Flow) generateContent(ctx agent.InvocationContext, req *model.Request) iter.Seq2[*model.Response, error] {
+	return func(yield func(*model.Response, error) bool) {
+		var maxTokens int
+		var temperature, topP float64
+		if cfg := req.Config; cfg != nil {
+			if cfg.MaxTokens != nil {
+				maxTokens = *cfg.MaxTokens
+			}
+			if cfg.Temperature != nil {
+				temperature = *cfg.Temperature
+			}
+			if cfg.TopP != nil {
+				topP = *cfg.TopP
+			}
+		}
+
+		llm := f.agent.model
+		llmCtx, span := f.agent.tracer.StartLLMCall(ctx, llm.Name(), maxTokens, temperature, topP)
+		defer span.End()
+		llmCtx = observability.WithAgentName(llmCtx, f.agent.Name())
+		span.SetAttributes(attribute.String(observability.AttrHectorAgentName, f.agent.Name()))
+		if pv := f.agent.promptVersion; pv != nil {
+			span.SetAttributes(
+				attribute.String(observability.AttrHectorPromptVersion, pv.Version),
+				attribute.String(observability.AttrHectorPromptHash, pv.Hash),
+			)
+		}
+
+		start := time.Now()
+		observed := len(f.agent.observers) > 0
+		var call LLMCallInfo
+		if observed {
+			call = LLMCallInfo{
+				CallInfo:  f.agent.callInfo(ctx),
+				ID:        uuid.NewString(),
+				Model:     llm.Name(),
+				Provider:  string(llm.Provider()),
+				Stream:    f.agent.enableStreaming,
+				StartTime: start,
+			}
+			f.agent.notifyObservers(func(o Observer) { o.OnLLMStart(llmCtx, call, req) })
+		}
+
+		var final *model.Response
+		var genErr error
+		for resp, err := range llm.GenerateContent(llmCtx, req, f.agent.enableStreaming) {
+			if err != nil {
+				genErr = err
+			} else if resp != nil && !resp.Partial {
+				final = resp
+			} else if resp != nil && observed {
+				f.agent.notifyObservers(func(o Observer) { o.OnLLMChunk(llmCtx, call, resp) })
+			}
+			if !yield(resp, err) {
+				break
+			}
+		}
+
+		f.recordLLMCall(llmCtx, span, time.Since(start), final, genErr)
+		if observed {
+			f.agent.notifyObservers(func(o Observer) { o.OnLLMEnd(llmCtx, call, final, genErr) })
+		}
+	}
+}
+
+// recordLLMCall annotates the LLM span and records call metrics.
+func (f *Flow) recordLLMCall(ctx context.Context, span trace.Span, duration time.Duration, resp *model.Response, err error) {
+	llm := f.agent.model
+	provider := string(llm.Provider())
+	metrics := f.agent.metricsRecorder
+
+	if metrics != nil {
+		metrics.RecordLLMCall(ctx, llm.Name(), provider, duration)
+	}
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		if metrics != nil {
+			metrics.RecordLLMError(ctx, llm.Name(), provider, "generation_error")
+		}
+		slog.Debug("LLM call failed", append([]any{"model", llm.Name(), "error", err}, observability.LogAttrs(ctx)...)...)
+		return
+	}
+
+	if resp != nil && resp.Usage != nil {
+		span.SetAttributes(
+			attribute.Int(observability.AttrGenAIUsageInputTokens, resp.Usage.PromptTokens),
+			attribute.Int(observability.AttrGenAIUsageOutputTokens, resp.Usage.CompletionTokens),
+		)
+		if metrics != nil {
+			metrics.RecordLLMTokens(ctx, llm.Name(), provider, resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
+		}
+	}
+	if resp != nil && resp.FinishReason != "" {
+		span.SetAttributes(attribute.String(observability.AttrGenAIResponseFinishReason, string(resp.FinishReason)))
+	}
+	slog.Debug("LLM call completed", append([]any{"model", llm.Name(), "duration", duration}, observability.LogAttrs(ctx)...)...)
+}
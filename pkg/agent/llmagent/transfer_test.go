@@ -0,0 +1,48 @@
+package llmagent
+
+import (
+	"context"
+	"iter"
+	"testing"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/model"
+)
+
+// downAgent is a sub-agent reporting itself unavailable.
+type downAgent struct{ agent.Agent }
+
+func (downAgent) Available() bool { return false }
+
+func TestTransferToolsSkipUnavailableAgents(t *testing.T) {
+	newSub := func(name string) agent.Agent {
+		a, err := agent.New(agent.Config{
+			Name: name,
+			Run: func(agent.InvocationContext) iter.Seq2[*agent.Event, error] {
+				return func(func(*agent.Event, error) bool) {}
+			},
+		})
+		if err != nil {
+			t.Fatalf("agent.New() error = %v", err)
+		}
+		return a
+	}
+
+	ag, err := New(Config{
+		Name:      "coordinator",
+		Model:     &summaryLLM{},
+		SubAgents: []agent.Agent{newSub("local"), downAgent{newSub("remote")}},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx := agent.NewInvocationContext(context.Background(), agent.InvocationContextParams{})
+	req := &model.Request{}
+	if err := TransferToolsRequestProcessor(newProcessorContext(ctx, ag.(*llmAgent)), req); err != nil {
+		t.Fatalf("TransferToolsRequestProcessor() error = %v", err)
+	}
+	if len(req.Tools) != 1 || req.Tools[0].Name != "transfer_to_local" {
+		t.Errorf("tools = %+v, want only transfer_to_local", req.Tools)
+	}
+}
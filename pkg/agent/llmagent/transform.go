@@ -0,0 +1,144 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llmagent
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/model"
+)
+
+// OutputTransform post-processes response text before it reaches the client
+// (e.g., stripping internal tags, sanitizing HTML, rewriting links).
+//
+// Transforms run in the order they are configured, each receiving the output
+// of the previous one. Returning an error fails the invocation.
+type OutputTransform func(ctx agent.ReadonlyContext, text string) (string, error)
+
+// streamBlockSeparator marks block boundaries for streaming transforms.
+// Text is buffered until a paragraph break so transforms see whole blocks.
+const streamBlockSeparator = "\n\n"
+
+// applyOutputTransforms runs the transform chain over text.
+func (a *llmAgent) applyOutputTransforms(ctx agent.ReadonlyContext, text string) (string, error) {
+	for i, transform := range a.outputTransforms {
+		var err error
+		text, err = transform(ctx, text)
+		if err != nil {
+			return "", fmt.Errorf("output transform %d failed: %w", i, err)
+		}
+	}
+	return text, nil
+}
+
+// transformResponse applies output transforms to the text parts of a final
+// response. Non-text parts (data, files) are left untouched.
+func (a *llmAgent) transformResponse(ctx agent.ReadonlyContext, resp *model.Response) error {
+	if len(a.outputTransforms) == 0 || resp.Content == nil {
+		return nil
+	}
+
+	for i, part := range resp.Content.Parts {
+		tp, ok := part.(a2a.TextPart)
+		if !ok || tp.Text == "" {
+			continue
+		}
+		text, err := a.applyOutputTransforms(ctx, tp.Text)
+		if err != nil {
+			return err
+		}
+		tp.Text = text
+		resp.Content.Parts[i] = tp
+	}
+	return nil
+}
+
+// streamTransformer applies output transforms to streamed text at block
+// boundaries. Each block is transformed independently, so transforms that
+// need the complete response only take full effect on the final response.
+type streamTransformer struct {
+	agent *llmAgent
+	buf   strings.Builder
+}
+
+// transformPartial buffers the text of a partial response and replaces it with
+// the transformed complete blocks, if any. Returns nil when the response has
+// nothing left to emit yet.
+func (s *streamTransformer) transformPartial(ctx agent.ReadonlyContext, resp *model.Response) (*model.Response, error) {
+	if resp.Content == nil {
+		return resp, nil
+	}
+
+	var parts []a2a.Part
+	for _, part := range resp.Content.Parts {
+		if tp, ok := part.(a2a.TextPart); ok {
+			s.buf.WriteString(tp.Text)
+			continue
+		}
+		parts = append(parts, part)
+	}
+
+	buffered := s.buf.String()
+	if idx := strings.LastIndex(buffered, streamBlockSeparator); idx >= 0 {
+		block := buffered[:idx+len(streamBlockSeparator)]
+		s.buf.Reset()
+		s.buf.WriteString(buffered[idx+len(streamBlockSeparator):])
+
+		text, err := s.agent.applyOutputTransforms(ctx, block)
+		if err != nil {
+			return nil, err
+		}
+		if text != "" {
+			parts = append(parts, a2a.TextPart{Text: text})
+		}
+	}
+
+	if len(parts) == 0 && resp.Thinking == nil && len(resp.ToolCalls) == 0 {
+		return nil, nil
+	}
+
+	out := *resp
+	out.Content = &model.Content{Parts: parts, Role: resp.Content.Role}
+	return &out, nil
+}
+
+// flush transforms and returns any buffered text as a final partial response.
+func (s *streamTransformer) flush(ctx agent.ReadonlyContext) (*model.Response, error) {
+	if s.buf.Len() == 0 {
+		return nil, nil
+	}
+
+	text, err := s.agent.applyOutputTransforms(ctx, s.buf.String())
+	s.buf.Reset()
+	if err != nil {
+		return nil, err
+	}
+	if text == "" {
+		return nil, nil
+	}
+
+	return &model.Response{
+		Content: &model.Content{
+			Parts: []a2a.Part{a2a.TextPart{Text: text}},
+			Role:  a2a.MessageRoleAgent,
+		},
+		Partial: true,
+	}, nil
+}
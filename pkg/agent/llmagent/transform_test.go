@@ -0,0 +1,51 @@
+package llmagent
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/model"
+)
+
+func TestStreamTransformerBuffersUntilBlockBoundary(t *testing.T) {
+	a := &llmAgent{
+		outputTransforms: []OutputTransform{
+			func(_ agent.ReadonlyContext, text string) (string, error) {
+				return strings.ReplaceAll(text, "<internal>", ""), nil
+			},
+		},
+	}
+	s := &streamTransformer{agent: a}
+
+	chunk := func(text string) *model.Response {
+		return &model.Response{
+			Partial: true,
+			Content: &model.Content{Parts: []a2a.Part{a2a.TextPart{Text: text}}, Role: a2a.MessageRoleAgent},
+		}
+	}
+
+	// A tag split across chunks must not leak before the block completes
+	resp, err := s.transformPartial(nil, chunk("Hello <inter"))
+	if err != nil || resp != nil {
+		t.Fatalf("expected chunk to be buffered, got %+v (err=%v)", resp, err)
+	}
+
+	resp, err = s.transformPartial(nil, chunk("nal>world\n\nNext"))
+	if err != nil {
+		t.Fatalf("transformPartial failed: %v", err)
+	}
+	if got := resp.Content.Parts[0].(a2a.TextPart).Text; got != "Hello world\n\n" {
+		t.Errorf("block = %q, want %q", got, "Hello world\n\n")
+	}
+
+	resp, err = s.flush(nil)
+	if err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+	if got := resp.Content.Parts[0].(a2a.TextPart).Text; got != "Next" {
+		t.Errorf("flushed = %q, want %q", got, "Next")
+	}
+}
@@ -16,9 +16,13 @@
 package remoteagent
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"iter"
+	"log/slog"
+	"net/http"
 	"os"
 	"strings"
 	"time"
@@ -61,12 +65,24 @@ type Config struct {
 
 	// MessageSendConfig is attached to every message sent to the remote agent.
 	MessageSendConfig *a2a.MessageSendConfig
+
+	// Retry retries calls that fail with a transient error. Nil disables
+	// retries.
+	Retry *RetryConfig
+
+	// CircuitBreaker fails calls fast while the remote agent keeps failing.
+	// Nil disables the breaker.
+	CircuitBreaker *CircuitBreakerConfig
 }
 
 // a2aAgent is the internal implementation of a remote A2A agent.
 type a2aAgent struct {
+	agent.Agent // Embedded base agent
+
 	cfg          Config
+	breaker      *circuitBreaker
 	resolvedCard *a2a.AgentCard
+	httpClient   *http.Client
 }
 
 // NewA2A creates a remote A2A agent.
@@ -106,6 +122,22 @@ func NewA2A(cfg Config) (agent.Agent, error) {
 	if cfg.Timeout == 0 {
 		cfg.Timeout = 30 * time.Second
 	}
+	if cfg.Retry != nil {
+		retry := *cfg.Retry
+		retry.setDefaults()
+		if err := retry.validate(); err != nil {
+			return nil, fmt.Errorf("invalid retry config: %w", err)
+		}
+		cfg.Retry = &retry
+	}
+	if cfg.CircuitBreaker != nil {
+		breaker := *cfg.CircuitBreaker
+		breaker.setDefaults()
+		if err := breaker.validate(); err != nil {
+			return nil, fmt.Errorf("invalid circuit breaker config: %w", err)
+		}
+		cfg.CircuitBreaker = &breaker
+	}
 
 	// If URL provided but no AgentCardSource, construct it
 	if cfg.URL != "" && cfg.AgentCardSource == "" && cfg.AgentCard == nil {
@@ -115,9 +147,13 @@ func NewA2A(cfg Config) (agent.Agent, error) {
 	remoteAgent := &a2aAgent{
 		cfg:          cfg,
 		resolvedCard: cfg.AgentCard,
+		httpClient:   newHTTPClient(cfg),
+	}
+	if cfg.CircuitBreaker != nil {
+		remoteAgent.breaker = newCircuitBreaker(cfg.Name, *cfg.CircuitBreaker)
 	}
 
-	return agent.New(agent.Config{
+	base, err := agent.New(agent.Config{
 		Name:        cfg.Name,
 		Description: cfg.Description,
 		Run: func(ctx agent.InvocationContext) iter.Seq2[*agent.Event, error] {
@@ -125,44 +161,97 @@ func NewA2A(cfg Config) (agent.Agent, error) {
 		},
 		AgentType: agent.TypeRemoteAgent,
 	})
+	if err != nil {
+		return nil, err
+	}
+	remoteAgent.Agent = base
+	return remoteAgent, nil
+}
+
+// CircuitState returns the state of the agent's circuit breaker. Agents
+// without a breaker are always closed.
+func (a *a2aAgent) CircuitState() CircuitState {
+	if a.breaker == nil {
+		return CircuitClosed
+	}
+	return a.breaker.currentState()
+}
+
+// Available reports whether calls are let through, so parents can route
+// around a remote agent whose breaker is open.
+func (a *a2aAgent) Available() bool {
+	return a.CircuitState() != CircuitOpen
 }
 
 func (a *a2aAgent) run(ctx agent.InvocationContext) iter.Seq2[*agent.Event, error] {
 	return func(yield func(*agent.Event, error) bool) {
-		// Resolve agent card if not already resolved
-		card, err := a.resolveAgentCard(ctx)
-		if err != nil {
-			yield(a.errorEvent(ctx, fmt.Errorf("agent card resolution failed: %w", err)), nil)
+		if a.breaker == nil {
+			if err := a.call(ctx, yield); err != nil {
+				yield(a.errorEvent(ctx, err), nil)
+			}
 			return
 		}
-		a.resolvedCard = card
 
-		// Create A2A client
-		client, err := a2aclient.NewFromCard(ctx, card)
+		probe, err := a.breaker.allow()
 		if err != nil {
-			yield(a.errorEvent(ctx, fmt.Errorf("client creation failed: %w", err)), nil)
+			yield(a.errorEvent(ctx, err), nil)
 			return
 		}
-		defer func() { _ = client.Destroy() }()
-
-		// Build message from context
-		msg := a.buildMessage(ctx)
-		if len(msg.Parts) == 0 {
-			// No content to send, yield empty event
-			yield(a.newEvent(ctx), nil)
-			return
+		err = a.call(ctx, yield)
+		// A call cancelled by the caller says nothing about the remote, but
+		// one that ran out of time counts as a failure
+		a.breaker.record(probe, err, errors.Is(ctx.Err(), context.Canceled))
+		if err != nil {
+			yield(a.errorEvent(ctx, err), nil)
 		}
+	}
+}
 
-		// Send message and stream response
-		req := &a2a.MessageSendParams{
-			Message: msg,
-			Config:  a.cfg.MessageSendConfig,
-		}
+// call sends the user content to the remote agent and yields the converted
+// events. It returns the error that ended the call, if any; a call stopped
+// by the consumer returns nil.
+func (a *a2aAgent) call(ctx agent.InvocationContext, yield func(*agent.Event, error) bool) error {
+	// Resolve agent card if not already resolved
+	card, err := a.resolveAgentCard(ctx)
+	if err != nil {
+		return fmt.Errorf("agent card resolution failed: %w", err)
+	}
+	a.resolvedCard = card
 
-		for a2aEvent, err := range client.SendStreamingMessage(ctx, req) {
+	// Create A2A client
+	client, err := a2aclient.NewFromCard(ctx, card, a2aclient.WithJSONRPCTransport(a.httpClient))
+	if err != nil {
+		return fmt.Errorf("client creation failed: %w", err)
+	}
+	defer func() { _ = client.Destroy() }()
+
+	// Build message from context
+	msg := a.buildMessage(ctx)
+	if len(msg.Parts) == 0 {
+		// No content to send, yield empty event
+		yield(a.newEvent(ctx), nil)
+		return nil
+	}
+
+	// Send message and stream response
+	req := &a2a.MessageSendParams{
+		Message: msg,
+		Config:  a.cfg.MessageSendConfig,
+	}
+
+	stream := client.SendStreamingMessage(ctx, req)
+	var taskID a2a.TaskID
+	received := false
+	for attempt := 1; ; attempt++ {
+		var streamErr error
+		for a2aEvent, err := range stream {
 			if err != nil {
-				yield(a.errorEvent(ctx, err), nil)
-				return
+				streamErr = err
+				break
+			}
+			received = true
+			if id := eventTaskID(a2aEvent); id != "" {
+				taskID = id
 			}
 
 			event := a.convertEvent(ctx, a2aEvent)
@@ -171,10 +260,66 @@ func (a *a2aAgent) run(ctx agent.InvocationContext) iter.Seq2[*agent.Event, erro
 			}
 
 			if !yield(event, nil) {
-				break
+				return nil
+			}
+		}
+		if streamErr == nil {
+			return nil
+		}
+
+		// A stream that produced events can only be resumed through its task
+		if !a.cfg.Retry.enabled() || attempt >= a.cfg.Retry.MaxAttempts ||
+			!transient(ctx, streamErr) || (received && taskID == "") {
+			if attempt > 1 {
+				slog.Warn("Remote agent call failed after retries",
+					"agent", a.cfg.Name, "retries", attempt-1, "error", streamErr)
 			}
+			return streamErr
 		}
+
+		delay := a.cfg.Retry.delay(attempt-1, streamErr)
+		slog.Warn("Retrying remote agent call",
+			"agent", a.cfg.Name,
+			"attempt", attempt+1,
+			"max_attempts", a.cfg.Retry.MaxAttempts,
+			"delay", delay,
+			"task", taskID,
+			"error", streamErr)
+		if err := sleep(ctx, delay); err != nil {
+			return streamErr
+		}
+
+		if taskID != "" {
+			stream = client.ResubscribeToTask(ctx, &a2a.TaskIDParams{ID: taskID})
+		} else {
+			stream = client.SendStreamingMessage(ctx, req)
+		}
+	}
+}
+
+// eventTaskID returns the ID of the task an event belongs to, if any.
+func eventTaskID(event a2a.Event) a2a.TaskID {
+	switch e := event.(type) {
+	case *a2a.Task:
+		return e.ID
+	case *a2a.TaskStatusUpdateEvent:
+		return e.TaskID
+	case *a2a.TaskArtifactUpdateEvent:
+		return e.TaskID
+	default:
+		return ""
+	}
+}
+
+// newHTTPClient creates the HTTP client for calls to the remote agent.
+// With retries enabled, responses with a retryable status become errors so
+// run can retry them.
+func newHTTPClient(cfg Config) *http.Client {
+	client := &http.Client{Timeout: cfg.Timeout}
+	if cfg.Retry.enabled() {
+		client.Transport = &statusTransport{base: http.DefaultTransport, retryOn: cfg.Retry.RetryOn}
 	}
+	return client
 }
 
 func (a *a2aAgent) resolveAgentCard(ctx agent.InvocationContext) (*a2a.AgentCard, error) {
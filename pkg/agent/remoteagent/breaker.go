@@ -0,0 +1,202 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remoteagent
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+)
+
+// ErrCircuitOpen is the error of a call rejected by an open circuit breaker.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitBreakerConfig configures the circuit breaker of a remote agent.
+//
+// After FailureThreshold consecutive failed calls the breaker opens and
+// calls fail immediately with ErrCircuitOpen. Once Cooldown has passed it
+// half-opens and lets up to HalfOpenMax calls through to probe the remote:
+// a successful probe closes the breaker, a failed one opens it again.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens
+	// the breaker. Default: 5
+	FailureThreshold int
+
+	// Cooldown is how long the breaker stays open before probing the
+	// remote again. Default: 30s
+	Cooldown time.Duration
+
+	// HalfOpenMax is the number of concurrent probe calls allowed while
+	// half-open. Default: 1
+	HalfOpenMax int
+}
+
+// setDefaults fills in unset fields.
+func (c *CircuitBreakerConfig) setDefaults() {
+	if c.FailureThreshold == 0 {
+		c.FailureThreshold = 5
+	}
+	if c.Cooldown == 0 {
+		c.Cooldown = 30 * time.Second
+	}
+	if c.HalfOpenMax == 0 {
+		c.HalfOpenMax = 1
+	}
+}
+
+// validate checks the configuration for errors.
+func (c *CircuitBreakerConfig) validate() error {
+	if c.FailureThreshold < 0 {
+		return fmt.Errorf("failure threshold must be non-negative")
+	}
+	if c.Cooldown < 0 {
+		return fmt.Errorf("cooldown must be non-negative")
+	}
+	if c.HalfOpenMax < 0 {
+		return fmt.Errorf("half-open max must be non-negative")
+	}
+	return nil
+}
+
+// CircuitState is the state of a remote agent's circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets calls through.
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen rejects calls until the cooldown has passed.
+	CircuitOpen
+
+	// CircuitHalfOpen lets a limited number of probe calls through.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half_open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitStateOf returns the circuit breaker state of a remote agent
+// created by NewA2A. It reports false for other agents.
+func CircuitStateOf(a agent.Agent) (CircuitState, bool) {
+	remote, ok := a.(*a2aAgent)
+	if !ok {
+		return CircuitClosed, false
+	}
+	return remote.CircuitState(), true
+}
+
+// circuitBreaker tracks consecutive failures of calls to one remote agent.
+type circuitBreaker struct {
+	name string
+	cfg  CircuitBreakerConfig
+	now  func() time.Time
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probes   int // calls in flight while half-open
+}
+
+func newCircuitBreaker(name string, cfg CircuitBreakerConfig) *circuitBreaker {
+	return &circuitBreaker{name: name, cfg: cfg, now: time.Now}
+}
+
+// currentState returns the state, reporting an open breaker whose cooldown
+// has passed as half-open.
+func (b *circuitBreaker) currentState() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.halfOpenIfCooled()
+	return b.state
+}
+
+func (b *circuitBreaker) halfOpenIfCooled() {
+	if b.state == CircuitOpen && b.now().Sub(b.openedAt) >= b.cfg.Cooldown {
+		b.state = CircuitHalfOpen
+		b.probes = 0
+		slog.Info("Circuit breaker half-open, probing remote agent", "agent", b.name)
+	}
+}
+
+// allow reserves a call, or returns an ErrCircuitOpen if the breaker
+// rejects it. It reports whether the call is a half-open probe; every
+// allowed call must be followed by record.
+func (b *circuitBreaker) allow() (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.halfOpenIfCooled()
+
+	switch b.state {
+	case CircuitOpen:
+		retryIn := b.cfg.Cooldown - b.now().Sub(b.openedAt)
+		return false, fmt.Errorf("%w: remote agent %q is unavailable, retry in %s",
+			ErrCircuitOpen, b.name, retryIn.Round(time.Second))
+	case CircuitHalfOpen:
+		if b.probes >= b.cfg.HalfOpenMax {
+			return false, fmt.Errorf("%w: remote agent %q is being probed", ErrCircuitOpen, b.name)
+		}
+		b.probes++
+		return true, nil
+	}
+	return false, nil
+}
+
+// record reports the outcome of an allowed call. Cancelled calls only
+// release their probe slot.
+func (b *circuitBreaker) record(probe bool, err error, cancelled bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if probe {
+		b.probes--
+	}
+	if cancelled {
+		return
+	}
+
+	if err == nil {
+		if b.state != CircuitClosed {
+			slog.Info("Circuit breaker closed, remote agent recovered", "agent", b.name)
+		}
+		b.state = CircuitClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if (probe && b.state == CircuitHalfOpen) || (b.state == CircuitClosed && b.failures >= b.cfg.FailureThreshold) {
+		b.state = CircuitOpen
+		b.openedAt = b.now()
+		slog.Warn("Circuit breaker opened, failing calls to remote agent fast",
+			"agent", b.name,
+			"failures", b.failures,
+			"cooldown", b.cfg.Cooldown,
+			"error", err)
+	}
+}
@@ -0,0 +1,98 @@
+package remoteagent
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+func TestCircuitBreakerStates(t *testing.T) {
+	now := time.Now()
+	b := newCircuitBreaker("remote", CircuitBreakerConfig{FailureThreshold: 2, Cooldown: time.Minute, HalfOpenMax: 1})
+	b.now = func() time.Time { return now }
+	failure := errors.New("connection refused")
+
+	for range 2 {
+		probe, err := b.allow()
+		if err != nil {
+			t.Fatalf("allow() error = %v", err)
+		}
+		b.record(probe, failure, false)
+	}
+	if s := b.currentState(); s != CircuitOpen {
+		t.Fatalf("state = %v, want open", s)
+	}
+	if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("allow() error = %v, want ErrCircuitOpen", err)
+	}
+
+	// After the cooldown one probe is let through; a failed probe reopens
+	now = now.Add(time.Minute)
+	probe, err := b.allow()
+	if err != nil || !probe {
+		t.Fatalf("allow() = %v, %v, want probe", probe, err)
+	}
+	if _, err := b.allow(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("second probe error = %v, want ErrCircuitOpen", err)
+	}
+	b.record(probe, failure, false)
+	if s := b.currentState(); s != CircuitOpen {
+		t.Fatalf("state after failed probe = %v, want open", s)
+	}
+
+	// A cancelled probe only frees its slot; a successful one closes
+	now = now.Add(time.Minute)
+	probe, _ = b.allow()
+	b.record(probe, nil, true)
+	if s := b.currentState(); s != CircuitHalfOpen {
+		t.Fatalf("state after cancelled probe = %v, want half_open", s)
+	}
+	probe, _ = b.allow()
+	b.record(probe, nil, false)
+	if s := b.currentState(); s != CircuitClosed {
+		t.Errorf("state after successful probe = %v, want closed", s)
+	}
+}
+
+func TestCircuitBreakerFailsFast(t *testing.T) {
+	var up atomic.Bool
+	srv := &flakyServer{t: t, handle: func(w http.ResponseWriter, _ int, _ string) {
+		if !up.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeStatus(w, a2a.TaskStateCompleted, "done")
+	}}
+	a := newRemote(t, srv, Config{CircuitBreaker: &CircuitBreakerConfig{FailureThreshold: 2, Cooldown: time.Hour}})
+
+	for range 3 {
+		runAgent(t, a)
+	}
+	if n := len(srv.calls()); n != 2 {
+		t.Errorf("calls = %d, want 2", n)
+	}
+	if msg := remoteError(runAgent(t, a)); !strings.Contains(msg, ErrCircuitOpen.Error()) {
+		t.Errorf("remote error = %q, want circuit open", msg)
+	}
+	if state, ok := CircuitStateOf(a); !ok || state != CircuitOpen {
+		t.Errorf("CircuitStateOf() = %v, %v, want open", state, ok)
+	}
+	if a.(*a2aAgent).Available() {
+		t.Error("Available() = true with an open breaker")
+	}
+
+	// Skip the cooldown; the probe succeeds and closes the breaker
+	up.Store(true)
+	a.(*a2aAgent).breaker.now = func() time.Time { return time.Now().Add(time.Hour) }
+	if msg := remoteError(runAgent(t, a)); msg != "" {
+		t.Fatalf("remote error = %q", msg)
+	}
+	if state, _ := CircuitStateOf(a); state != CircuitClosed {
+		t.Errorf("state = %v, want closed", state)
+	}
+}
@@ -0,0 +1,186 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package remoteagent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// defaultRetryOn are the HTTP statuses retried when RetryConfig.RetryOn is
+// empty.
+var defaultRetryOn = []int{
+	http.StatusRequestTimeout,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryConfig configures retries of failed calls to a remote agent.
+//
+// Calls failing with a status in RetryOn or with a network error are
+// retried with exponential backoff. A stream that fails after the remote
+// has created a task is resumed by re-subscribing to that task; one that
+// fails before any event arrived is sent again. Other failures, including
+// 4xx statuses other than 408 and 429, fail immediately.
+type RetryConfig struct {
+	// MaxAttempts is the total number of attempts of a call, including
+	// the first. Values below 2 disable retries.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry. A Retry-After header
+	// from the remote takes precedence. Default: 1s
+	BaseDelay time.Duration
+
+	// Multiplier scales the delay after each retry. Default: 2
+	Multiplier float64
+
+	// MaxDelay caps the delay between retries. Default: 30s
+	MaxDelay time.Duration
+
+	// RetryOn lists the HTTP statuses that are retried. Only 408, 429 and
+	// 5xx statuses may be listed.
+	// Default: 408, 429, 500, 502, 503, 504
+	RetryOn []int
+}
+
+// setDefaults fills in unset fields.
+func (c *RetryConfig) setDefaults() {
+	if c.BaseDelay == 0 {
+		c.BaseDelay = time.Second
+	}
+	if c.Multiplier == 0 {
+		c.Multiplier = 2
+	}
+	if c.MaxDelay == 0 {
+		c.MaxDelay = 30 * time.Second
+	}
+	if len(c.RetryOn) == 0 {
+		c.RetryOn = defaultRetryOn
+	}
+}
+
+// validate checks the configuration for errors.
+func (c *RetryConfig) validate() error {
+	if c.MaxAttempts < 0 {
+		return fmt.Errorf("max attempts must be non-negative")
+	}
+	if c.BaseDelay < 0 || c.MaxDelay < 0 {
+		return fmt.Errorf("retry delays must be non-negative")
+	}
+	if c.Multiplier < 1 {
+		return fmt.Errorf("retry multiplier must be at least 1")
+	}
+	for _, code := range c.RetryOn {
+		if !retryableStatus(code) {
+			return fmt.Errorf("status %d cannot be retried", code)
+		}
+	}
+	return nil
+}
+
+// retryableStatus reports whether a status may be retried at all.
+func retryableStatus(code int) bool {
+	return code == http.StatusRequestTimeout || code == http.StatusTooManyRequests ||
+		(code >= 500 && code <= 599)
+}
+
+// enabled reports whether failed calls are retried.
+func (c *RetryConfig) enabled() bool {
+	return c != nil && c.MaxAttempts > 1
+}
+
+// delay returns the backoff before the given retry (0-based).
+func (c *RetryConfig) delay(retry int, err error) time.Duration {
+	var statusErr *statusError
+	if errors.As(err, &statusErr) && statusErr.retryAfter > 0 {
+		return statusErr.retryAfter
+	}
+	d := float64(c.BaseDelay) * math.Pow(c.Multiplier, float64(retry))
+	return min(time.Duration(d), c.MaxDelay)
+}
+
+// statusError is a response with a status listed in RetryOn.
+type statusError struct {
+	code       int
+	retryAfter time.Duration
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("remote agent returned HTTP %d %s", e.code, http.StatusText(e.code))
+}
+
+// statusTransport turns responses with a status listed in retryOn into a
+// statusError, so the call can be retried. Other responses are returned
+// as-is.
+type statusTransport struct {
+	base    http.RoundTripper
+	retryOn []int
+}
+
+func (t *statusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || !slices.Contains(t.retryOn, resp.StatusCode) {
+		return resp, err
+	}
+	_ = resp.Body.Close()
+	return nil, &statusError{code: resp.StatusCode, retryAfter: parseRetryAfter(resp.Header)}
+}
+
+// parseRetryAfter reads the standard Retry-After header (in seconds).
+func parseRetryAfter(headers http.Header) time.Duration {
+	if secs, err := strconv.Atoi(headers.Get("Retry-After")); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	return 0
+}
+
+// transient reports whether a failed call may succeed when retried: the
+// remote answered with a status listed in RetryOn, or the connection
+// failed or timed out.
+func transient(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
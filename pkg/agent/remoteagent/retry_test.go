@@ -0,0 +1,208 @@
+package remoteagent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+)
+
+// flakyServer is an A2A JSON-RPC endpoint whose handler is chosen per
+// request, recording the methods it was called with.
+type flakyServer struct {
+	t *testing.T
+
+	mu      sync.Mutex
+	methods []string
+	handle  func(w http.ResponseWriter, call int, method string)
+}
+
+func (s *flakyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Method string `json:"method"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.t.Errorf("decode request: %v", err)
+	}
+	s.mu.Lock()
+	s.methods = append(s.methods, req.Method)
+	call := len(s.methods)
+	s.mu.Unlock()
+	s.handle(w, call, req.Method)
+}
+
+func (s *flakyServer) calls() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.methods...)
+}
+
+// writeStatus writes a status update of task t1 as an SSE event.
+func writeStatus(w http.ResponseWriter, state a2a.TaskState, text string) {
+	event := &a2a.TaskStatusUpdateEvent{
+		TaskID:    "t1",
+		ContextID: "c1",
+		Status:    a2a.TaskStatus{State: state, Message: a2a.NewMessage(a2a.MessageRoleAgent, a2a.TextPart{Text: text})},
+		Final:     state.Terminal(),
+	}
+	data, _ := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": "1", "result": event})
+	_, _ = fmt.Fprintf(w, "data: %s\n\n", data)
+	w.(http.Flusher).Flush()
+}
+
+// newRemote creates a remote agent calling srv, configured by cfg.
+func newRemote(t *testing.T, srv *flakyServer, cfg Config) agent.Agent {
+	t.Helper()
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+
+	cfg.Name = "remote"
+	cfg.AgentCard = &a2a.AgentCard{
+		Name:               "remote",
+		URL:                ts.URL,
+		PreferredTransport: a2a.TransportProtocolJSONRPC,
+		Capabilities:       a2a.AgentCapabilities{Streaming: true},
+	}
+	a, err := NewA2A(cfg)
+	if err != nil {
+		t.Fatalf("NewA2A() error = %v", err)
+	}
+	return a
+}
+
+func runAgent(t *testing.T, a agent.Agent) []*agent.Event {
+	t.Helper()
+	ctx := agent.NewInvocationContext(context.Background(), agent.InvocationContextParams{
+		Agent:       a,
+		UserContent: agent.NewTextContent("hi", a2a.MessageRoleUser),
+	})
+	var events []*agent.Event
+	for event, err := range a.Run(ctx) {
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+func runRemote(t *testing.T, srv *flakyServer, retry *RetryConfig) []*agent.Event {
+	t.Helper()
+	return runAgent(t, newRemote(t, srv, Config{Retry: retry}))
+}
+
+func remoteError(events []*agent.Event) string {
+	for _, e := range events {
+		if msg, ok := e.CustomMetadata["_hector_remote_error"].(string); ok {
+			return msg
+		}
+	}
+	return ""
+}
+
+var fastRetry = &RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+func TestRetryTransientStatus(t *testing.T) {
+	srv := &flakyServer{t: t, handle: func(w http.ResponseWriter, call int, _ string) {
+		if call < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeStatus(w, a2a.TaskStateCompleted, "done")
+	}}
+
+	events := runRemote(t, srv, fastRetry)
+	if msg := remoteError(events); msg != "" {
+		t.Fatalf("remote error = %s", msg)
+	}
+	if n := len(srv.calls()); n != 3 {
+		t.Errorf("calls = %d, want 3", n)
+	}
+	if len(events) != 1 || events[0].TextContent() != "done" {
+		t.Errorf("events = %v", events)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	tests := []struct {
+		status int
+		calls  int
+	}{
+		{http.StatusBadGateway, 3},
+		{http.StatusBadRequest, 1},
+		{http.StatusNotFound, 1},
+	}
+	for _, tt := range tests {
+		srv := &flakyServer{t: t, handle: func(w http.ResponseWriter, _ int, _ string) {
+			w.WriteHeader(tt.status)
+		}}
+		events := runRemote(t, srv, fastRetry)
+		if remoteError(events) == "" {
+			t.Errorf("status %d: expected remote error", tt.status)
+		}
+		if n := len(srv.calls()); n != tt.calls {
+			t.Errorf("status %d: calls = %d, want %d", tt.status, n, tt.calls)
+		}
+	}
+}
+
+func TestRetryResubscribesInterruptedStream(t *testing.T) {
+	srv := &flakyServer{t: t, handle: func(w http.ResponseWriter, call int, _ string) {
+		if call == 1 {
+			writeStatus(w, a2a.TaskStateWorking, "working")
+			panic(http.ErrAbortHandler)
+		}
+		writeStatus(w, a2a.TaskStateCompleted, "done")
+	}}
+
+	events := runRemote(t, srv, fastRetry)
+	if msg := remoteError(events); msg != "" {
+		t.Fatalf("remote error = %s", msg)
+	}
+	calls := srv.calls()
+	if len(calls) != 2 || calls[0] != "message/stream" || calls[1] != "tasks/resubscribe" {
+		t.Errorf("calls = %v, want [message/stream tasks/resubscribe]", calls)
+	}
+	if len(events) != 2 || events[1].TextContent() != "done" {
+		t.Errorf("events = %v", events)
+	}
+}
+
+func TestRetryDisabled(t *testing.T) {
+	srv := &flakyServer{t: t, handle: func(w http.ResponseWriter, _ int, _ string) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}}
+	if events := runRemote(t, srv, nil); remoteError(events) == "" {
+		t.Error("expected remote error")
+	}
+	if n := len(srv.calls()); n != 1 {
+		t.Errorf("calls = %d, want 1", n)
+	}
+}
+
+func TestRetryConfigValidation(t *testing.T) {
+	for _, cfg := range []RetryConfig{
+		{MaxAttempts: -1},
+		{MaxAttempts: 3, Multiplier: 0.5},
+		{MaxAttempts: 3, RetryOn: []int{404}},
+	} {
+		if _, err := NewA2A(Config{Name: "remote", URL: "http://localhost", Retry: &cfg}); err == nil {
+			t.Errorf("NewA2A(retry %+v) expected error", cfg)
+		}
+	}
+
+	cfg := RetryConfig{BaseDelay: time.Second, Multiplier: 3, MaxDelay: 5 * time.Second}
+	for retry, want := range []time.Duration{time.Second, 3 * time.Second, 5 * time.Second} {
+		if got := cfg.delay(retry, nil); got != want {
+			t.Errorf("delay(%d) = %v, want %v", retry, got, want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTimeout is the error of an agent run that exceeded its timeout.
+// Check for it with errors.Is; the returned error names the agent.
+var ErrTimeout = errors.New("agent run timed out")
+
+// withTimeout bounds an invocation by the agent's timeout. The returned
+// context's cause is an ErrTimeout once the deadline passes.
+func withTimeout(ctx InvocationContext, name string, timeout time.Duration) (InvocationContext, context.CancelFunc) {
+	cause := fmt.Errorf("%w: agent %q did not finish within %s", ErrTimeout, name, timeout)
+	runCtx, cancel := context.WithTimeoutCause(ctx, timeout, cause)
+	return WithContext(ctx, runCtx), cancel
+}
+
+// timeoutCause returns the ErrTimeout of a timed-out invocation, or nil.
+func timeoutCause(ctx InvocationContext) error {
+	if tc, ok := ctx.(*contextInvocation); ok {
+		if cause := context.Cause(tc.ctx); errors.Is(cause, ErrTimeout) {
+			return cause
+		}
+	}
+	return nil
+}
@@ -0,0 +1,393 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflowagent
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+)
+
+// condition is a parsed predicate over session state.
+//
+// Syntax:
+//
+//	{key}                      state value, truthy unless missing, false, 0 or ""
+//	"text", 'text', 42, 1.5    literals, as well as true, false and null
+//	== != < <= > >=            comparisons
+//	!  &&  ||  ( )             logic and grouping
+//
+// Keys may carry a scope prefix ({app:plan}, {user:tier}, {temp:route}).
+// Missing keys evaluate to null. Numbers compare numerically; values of
+// different types are equal when they print the same.
+type condition interface {
+	eval(state agent.ReadonlyState) any
+}
+
+// parseCondition parses a condition expression.
+func parseCondition(expr string) (condition, error) {
+	p := &conditionParser{src: expr}
+	p.next()
+	c, err := p.parseOr()
+	if err == nil {
+		err = p.err
+	}
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at offset %d", p.tok.text, p.tok.pos)
+	}
+	return c, nil
+}
+
+// evalCondition reports whether c holds for state.
+func evalCondition(c condition, state agent.ReadonlyState) bool {
+	return truthy(c.eval(state))
+}
+
+type (
+	stateRef  struct{ key string }
+	literal   struct{ value any }
+	notExpr   struct{ x condition }
+	logicExpr struct {
+		and  bool
+		l, r condition
+	}
+	compareExpr struct {
+		op   string
+		l, r condition
+	}
+)
+
+func (s stateRef) eval(state agent.ReadonlyState) any {
+	if state == nil {
+		return nil
+	}
+	v, err := state.Get(s.key)
+	if err != nil {
+		return nil
+	}
+	return v
+}
+
+func (l literal) eval(agent.ReadonlyState) any { return l.value }
+
+func (n notExpr) eval(state agent.ReadonlyState) any { return !truthy(n.x.eval(state)) }
+
+func (e logicExpr) eval(state agent.ReadonlyState) any {
+	l := truthy(e.l.eval(state))
+	if e.and {
+		return l && truthy(e.r.eval(state))
+	}
+	return l || truthy(e.r.eval(state))
+}
+
+func (e compareExpr) eval(state agent.ReadonlyState) any {
+	l, r := e.l.eval(state), e.r.eval(state)
+	switch e.op {
+	case "==":
+		return equalValues(l, r)
+	case "!=":
+		return !equalValues(l, r)
+	}
+
+	var cmp int
+	if lf, ok := toFloat(l); ok {
+		rf, ok := toFloat(r)
+		if !ok {
+			return false
+		}
+		switch {
+		case lf < rf:
+			cmp = -1
+		case lf > rf:
+			cmp = 1
+		}
+	} else {
+		ls, lok := l.(string)
+		rs, rok := r.(string)
+		if !lok || !rok {
+			return false
+		}
+		cmp = strings.Compare(ls, rs)
+	}
+
+	switch e.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default: // ">="
+		return cmp >= 0
+	}
+}
+
+func equalValues(l, r any) bool {
+	if l == nil || r == nil {
+		return l == nil && r == nil
+	}
+	if lf, ok := toFloat(l); ok {
+		if rf, ok := toFloat(r); ok {
+			return lf == rf
+		}
+	}
+	return fmt.Sprint(l) == fmt.Sprint(r)
+}
+
+func toFloat(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func truthy(v any) bool {
+	switch x := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return x
+	case string:
+		return x != ""
+	}
+	if f, ok := toFloat(v); ok {
+		return f != 0
+	}
+	return true
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokRef
+	tokString
+	tokNumber
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// conditionParser is a recursive descent parser over a one-token lookahead.
+type conditionParser struct {
+	src string
+	pos int
+	tok token
+	err error
+}
+
+// next advances to the next token, recording the first lexing error.
+func (p *conditionParser) next() {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos >= len(p.src) {
+		p.tok = token{kind: tokEOF, text: "end of expression", pos: start}
+		return
+	}
+
+	c := p.src[p.pos]
+	switch {
+	case c == '{':
+		end := strings.IndexByte(p.src[p.pos:], '}')
+		if end < 0 {
+			p.fail(fmt.Errorf("unterminated state reference at offset %d", start))
+			return
+		}
+		p.pos += end + 1
+		p.tok = token{kind: tokRef, text: strings.TrimSpace(p.src[start+1 : p.pos-1]), pos: start}
+	case c == '"' || c == '\'':
+		end := strings.IndexByte(p.src[p.pos+1:], c)
+		if end < 0 {
+			p.fail(fmt.Errorf("unterminated string at offset %d", start))
+			return
+		}
+		p.pos += end + 2
+		p.tok = token{kind: tokString, text: p.src[start+1 : p.pos-1], pos: start}
+	case c == '-' || c == '.' || (c >= '0' && c <= '9'):
+		p.pos++
+		for p.pos < len(p.src) && (p.src[p.pos] == '.' || (p.src[p.pos] >= '0' && p.src[p.pos] <= '9')) {
+			p.pos++
+		}
+		p.tok = token{kind: tokNumber, text: p.src[start:p.pos], pos: start}
+	case unicode.IsLetter(rune(c)):
+		for p.pos < len(p.src) && (unicode.IsLetter(rune(p.src[p.pos])) || p.src[p.pos] == '_') {
+			p.pos++
+		}
+		p.tok = token{kind: tokIdent, text: p.src[start:p.pos], pos: start}
+	default:
+		for _, op := range []string{"==", "!=", "<=", ">=", "&&", "||", "<", ">", "!", "(", ")"} {
+			if strings.HasPrefix(p.src[p.pos:], op) {
+				p.pos += len(op)
+				p.tok = token{kind: tokOp, text: op, pos: start}
+				return
+			}
+		}
+		p.fail(fmt.Errorf("unexpected %q at offset %d", string(c), start))
+	}
+}
+
+func (p *conditionParser) fail(err error) {
+	if p.err == nil {
+		p.err = err
+	}
+	p.tok = token{kind: tokEOF, text: "end of expression", pos: p.pos}
+	p.pos = len(p.src)
+}
+
+func (p *conditionParser) isOp(op string) bool {
+	return p.tok.kind == tokOp && p.tok.text == op
+}
+
+func (p *conditionParser) parseOr() (condition, error) {
+	l, err := p.parseAnd()
+	for err == nil && p.isOp("||") {
+		p.next()
+		var r condition
+		if r, err = p.parseAnd(); err == nil {
+			l = logicExpr{l: l, r: r}
+		}
+	}
+	return l, err
+}
+
+func (p *conditionParser) parseAnd() (condition, error) {
+	l, err := p.parseUnary()
+	for err == nil && p.isOp("&&") {
+		p.next()
+		var r condition
+		if r, err = p.parseUnary(); err == nil {
+			l = logicExpr{and: true, l: l, r: r}
+		}
+	}
+	return l, err
+}
+
+func (p *conditionParser) parseUnary() (condition, error) {
+	if p.isOp("!") {
+		p.next()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{x: x}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *conditionParser) parseComparison() (condition, error) {
+	l, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokOp {
+		switch op := p.tok.text; op {
+		case "==", "!=", "<", "<=", ">", ">=":
+			p.next()
+			r, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			return compareExpr{op: op, l: l, r: r}, nil
+		}
+	}
+	return l, nil
+}
+
+func (p *conditionParser) parseOperand() (condition, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	tok := p.tok
+	switch tok.kind {
+	case tokRef:
+		if tok.text == "" {
+			return nil, fmt.Errorf("empty state reference at offset %d", tok.pos)
+		}
+		p.next()
+		return stateRef{key: tok.text}, p.err
+	case tokString:
+		p.next()
+		return literal{value: tok.text}, p.err
+	case tokNumber:
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at offset %d", tok.text, tok.pos)
+		}
+		p.next()
+		return literal{value: f}, p.err
+	case tokIdent:
+		var value any
+		switch tok.text {
+		case "true":
+			value = true
+		case "false":
+			value = false
+		case "null", "nil":
+			value = nil
+		default:
+			return nil, fmt.Errorf("unknown identifier %q at offset %d (state keys are written {%s})", tok.text, tok.pos, tok.text)
+		}
+		p.next()
+		return literal{value: value}, p.err
+	case tokOp:
+		if tok.text == "(" {
+			p.next()
+			x, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if !p.isOp(")") {
+				return nil, fmt.Errorf("expected ) at offset %d", p.tok.pos)
+			}
+			p.next()
+			return x, p.err
+		}
+	}
+	return nil, fmt.Errorf("unexpected %s at offset %d", describeToken(tok), tok.pos)
+}
+
+func describeToken(tok token) string {
+	if tok.kind == tokEOF {
+		return tok.text
+	}
+	return strconv.Quote(tok.text)
+}
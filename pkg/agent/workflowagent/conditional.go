@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflowagent
+
+import (
+	"fmt"
+	"iter"
+	"log/slog"
+	"time"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+)
+
+// ConditionalCase routes to Agent when the When expression holds.
+type ConditionalCase struct {
+	// When is a predicate over session state, e.g.
+	// {classification} == "billing" && {priority} >= 2.
+	When string
+
+	// Agent runs when the predicate holds.
+	Agent agent.Agent
+}
+
+// ConditionalConfig defines the configuration for a ConditionalAgent.
+type ConditionalConfig struct {
+	// Name is the agent name.
+	Name string
+
+	// Description describes what the agent does.
+	Description string
+
+	// Cases are evaluated in order; the first that holds is run.
+	Cases []ConditionalCase
+
+	// Default runs when no case holds. If nil, nothing runs.
+	Default agent.Agent
+
+	// Retry re-runs the selected branch if it fails. Nil disables retries.
+	Retry *RetryConfig
+
+	// Timeout bounds each run of the workflow. Sub-agents are also bounded
+	// by their own timeouts. Zero means no timeout.
+	Timeout time.Duration
+}
+
+// NewConditional creates a ConditionalAgent.
+//
+// ConditionalAgent evaluates its cases against session state and runs
+// exactly one branch: the agent of the first case whose predicate holds, or
+// the default agent. Only the selected branch's events are streamed, and an
+// escalation from it reaches the enclosing workflow like any other event.
+//
+// Predicates reference state keys in braces and support literals,
+// comparisons (== != < <= > >=) and logic (! && || and parentheses).
+//
+// Use ConditionalAgent to route a request after a classifier has written
+// its decision to state.
+//
+// Example:
+//
+//	router, _ := workflowagent.NewConditional(workflowagent.ConditionalConfig{
+//	    Name:        "router",
+//	    Description: "Routes requests by classification",
+//	    Cases: []workflowagent.ConditionalCase{
+//	        {When: `{classification} == "billing"`, Agent: billing},
+//	        {When: `{classification} == "technical"`, Agent: support},
+//	    },
+//	    Default: general,
+//	})
+func NewConditional(cfg ConditionalConfig) (agent.Agent, error) {
+	if len(cfg.Cases) == 0 {
+		return nil, fmt.Errorf("conditional agent %q requires at least one case", cfg.Name)
+	}
+
+	type branch struct {
+		when  string
+		cond  condition
+		agent agent.Agent
+	}
+	branches := make([]branch, len(cfg.Cases))
+
+	// Sub-agents are the distinct branch agents, so the agent tree stays valid
+	// when several cases route to the same agent
+	var subAgents []agent.Agent
+	seen := make(map[string]bool)
+	addSubAgent := func(a agent.Agent) {
+		if !seen[a.Name()] {
+			seen[a.Name()] = true
+			subAgents = append(subAgents, a)
+		}
+	}
+
+	for i, c := range cfg.Cases {
+		if c.Agent == nil {
+			return nil, fmt.Errorf("case %d: agent is required", i)
+		}
+		cond, err := parseCondition(c.When)
+		if err != nil {
+			return nil, fmt.Errorf("case %d: invalid condition %q: %w", i, c.When, err)
+		}
+		branches[i] = branch{when: c.When, cond: cond, agent: c.Agent}
+		addSubAgent(c.Agent)
+	}
+	if cfg.Default != nil {
+		addSubAgent(cfg.Default)
+	}
+
+	return agent.New(agent.Config{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		SubAgents:   subAgents,
+		Timeout:     cfg.Timeout,
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*agent.Event, error] {
+			var state agent.ReadonlyState
+			if ctx.Session() != nil {
+				state = ctx.Session().State()
+			}
+
+			selected := cfg.Default
+			for _, b := range branches {
+				if evalCondition(b.cond, state) {
+					slog.Debug("Conditional agent selected branch", "agent", cfg.Name, "branch", b.agent.Name(), "when", b.when)
+					selected = b.agent
+					break
+				}
+			}
+			if selected == nil {
+				slog.Debug("Conditional agent matched no case", "agent", cfg.Name)
+				return func(func(*agent.Event, error) bool) {}
+			}
+
+			return runBranch(ctx, selected, cfg.Retry)
+		},
+		AgentType: agent.TypeConditionalAgent,
+	})
+}
+
+// runBranch runs the selected branch with the conditional agent's context.
+func runBranch(ctx agent.InvocationContext, branch agent.Agent, retry *RetryConfig) iter.Seq2[*agent.Event, error] {
+	newSubCtx := func() agent.InvocationContext {
+		return agent.NewInvocationContext(ctx, agent.InvocationContextParams{
+			Agent:       branch,
+			Session:     ctx.Session(),
+			Artifacts:   ctx.Artifacts(),
+			Memory:      ctx.Memory(),
+			UserContent: ctx.UserContent(),
+			RunConfig:   ctx.RunConfig(),
+			Branch:      ctx.Branch(), // Share branch so the branch sees earlier events
+		})
+	}
+	return runWithRetry(ctx, branch, retry, newSubCtx)
+}
@@ -0,0 +1,268 @@
+package workflowagent
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"sync"
+	"testing"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+)
+
+type testState map[string]any
+
+func (s testState) Get(key string) (any, error) {
+	v, ok := s[key]
+	if !ok {
+		return nil, errors.New("key not found")
+	}
+	return v, nil
+}
+
+func (s testState) Set(key string, value any) error { s[key] = value; return nil }
+func (s testState) Delete(key string) error         { delete(s, key); return nil }
+
+func (s testState) All() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for k, v := range s {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// testSession records persisted events like the runner does.
+type testSession struct {
+	state testState
+
+	mu     sync.Mutex
+	events []*agent.Event
+}
+
+func (s *testSession) ID() string           { return "session" }
+func (s *testSession) AppName() string      { return "app" }
+func (s *testSession) UserID() string       { return "user" }
+func (s *testSession) State() agent.State   { return s.state }
+func (s *testSession) Events() agent.Events { return s }
+
+func (s *testSession) All() iter.Seq[*agent.Event] {
+	s.mu.Lock()
+	events := append([]*agent.Event(nil), s.events...)
+	s.mu.Unlock()
+	return func(yield func(*agent.Event) bool) {
+		for _, e := range events {
+			if !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+func (s *testSession) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.events)
+}
+
+func (s *testSession) At(i int) *agent.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.events[i]
+}
+
+// persist applies a non-partial event to the session.
+func (s *testSession) persist(event *agent.Event) {
+	if event.Partial {
+		return
+	}
+	s.mu.Lock()
+	s.events = append(s.events, event)
+	s.mu.Unlock()
+	for k, v := range event.Actions.StateDelta {
+		s.state[k] = v
+	}
+	if event.OnPersisted != nil {
+		event.OnPersisted()
+	}
+}
+
+// namedAgent emits one event authored by itself, escalating if asked to.
+func namedAgent(t *testing.T, name string, escalate bool) agent.Agent {
+	t.Helper()
+	a, err := agent.New(agent.Config{
+		Name: name,
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*agent.Event, error] {
+			return func(yield func(*agent.Event, error) bool) {
+				event := agent.NewEvent(ctx.InvocationID())
+				event.Author = name
+				event.Actions.Escalate = escalate
+				yield(event, nil)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	return a
+}
+
+func runAuthors(t *testing.T, a agent.Agent, state testState) []string {
+	t.Helper()
+	session := &testSession{state: state}
+	ctx := agent.NewInvocationContext(context.Background(), agent.InvocationContextParams{
+		Agent:   a,
+		Session: session,
+	})
+	var authors []string
+	for event, err := range a.Run(ctx) {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		session.persist(event)
+		authors = append(authors, event.Author)
+	}
+	return authors
+}
+
+func TestConditionEval(t *testing.T) {
+	state := testState{
+		"classification": "billing",
+		"priority":       3,
+		"app:enabled":    true,
+		"empty":          "",
+	}
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`{classification} == "billing"`, true},
+		{`{classification} != 'billing'`, false},
+		{`{priority} >= 2 && {priority} < 5`, true},
+		{`{priority} == 3.0`, true},
+		{`{priority} > "2"`, false},
+		{`{app:enabled}`, true},
+		{`!{empty}`, true},
+		{`{missing}`, false},
+		{`{missing} == null`, true},
+		{`{missing} == "x" || ({priority} > 1 && !{empty})`, true},
+		{`"abc" < "abd"`, true},
+	}
+	for _, tt := range tests {
+		c, err := parseCondition(tt.expr)
+		if err != nil {
+			t.Errorf("parseCondition(%q) error = %v", tt.expr, err)
+			continue
+		}
+		if got := evalCondition(c, state); got != tt.want {
+			t.Errorf("evalCondition(%q) = %v, want %v", tt.expr, got, tt.want)
+		}
+	}
+}
+
+func TestParseConditionErrors(t *testing.T) {
+	for _, expr := range []string{
+		``,
+		`{classification`,
+		`{}`,
+		`classification == "billing"`,
+		`{a} == "b`,
+		`({a} == 1`,
+		`{a} == 1 {b}`,
+		`{a} = 1`,
+	} {
+		if _, err := parseCondition(expr); err == nil {
+			t.Errorf("parseCondition(%q) expected error", expr)
+		}
+	}
+}
+
+func TestConditionalRunsFirstMatchingBranch(t *testing.T) {
+	billing := namedAgent(t, "billing", false)
+	support := namedAgent(t, "support", false)
+	general := namedAgent(t, "general", false)
+
+	router, err := NewConditional(ConditionalConfig{
+		Name: "router",
+		Cases: []ConditionalCase{
+			{When: `{classification} == "billing"`, Agent: billing},
+			{When: `{classification} == "technical" || {priority} > 2`, Agent: support},
+		},
+		Default: general,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+	if n := len(router.SubAgents()); n != 3 {
+		t.Errorf("sub-agents = %d, want 3", n)
+	}
+
+	tests := []struct {
+		state testState
+		want  string
+	}{
+		{testState{"classification": "billing", "priority": 5}, "billing"},
+		{testState{"classification": "technical"}, "support"},
+		{testState{"priority": 3}, "support"},
+		{testState{}, "general"},
+	}
+	for _, tt := range tests {
+		authors := runAuthors(t, router, tt.state)
+		if len(authors) != 1 || authors[0] != tt.want {
+			t.Errorf("state %v: authors = %v, want [%s]", tt.state, authors, tt.want)
+		}
+	}
+}
+
+func TestConditionalWithoutDefault(t *testing.T) {
+	router, err := NewConditional(ConditionalConfig{
+		Name:  "router",
+		Cases: []ConditionalCase{{When: `{ready}`, Agent: namedAgent(t, "worker", false)}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+	if authors := runAuthors(t, router, testState{}); len(authors) != 0 {
+		t.Errorf("authors = %v, want none", authors)
+	}
+}
+
+func TestConditionalRejectsInvalidCases(t *testing.T) {
+	if _, err := NewConditional(ConditionalConfig{Name: "router"}); err == nil {
+		t.Error("expected error for no cases")
+	}
+	if _, err := NewConditional(ConditionalConfig{
+		Name:  "router",
+		Cases: []ConditionalCase{{When: `{a} ==`, Agent: namedAgent(t, "a", false)}},
+	}); err == nil {
+		t.Error("expected error for invalid condition")
+	}
+}
+
+func TestConditionalEscalationStopsLoop(t *testing.T) {
+	router, err := NewConditional(ConditionalConfig{
+		Name:    "router",
+		Cases:   []ConditionalCase{{When: `{done}`, Agent: namedAgent(t, "finisher", true)}},
+		Default: namedAgent(t, "worker", false),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create router: %v", err)
+	}
+	loop, err := NewLoop(LoopConfig{
+		Name:          "loop",
+		SubAgents:     []agent.Agent{router},
+		MaxIterations: 5,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create loop: %v", err)
+	}
+
+	authors := runAuthors(t, loop, testState{"done": true})
+	if len(authors) != 1 || authors[0] != "finisher" {
+		t.Errorf("authors = %v, want [finisher]", authors)
+	}
+	if authors := runAuthors(t, loop, testState{}); len(authors) != 5 {
+		t.Errorf("authors = %v, want 5 iterations", authors)
+	}
+}
@@ -15,7 +15,7 @@
 
 // Package workflowagent provides workflow agents for orchestrating multi-agent flows.
 //
-// This package provides three types of workflow agents aligned with adk-go:
+// This package provides five types of workflow agents aligned with adk-go:
 //
 // # SequentialAgent
 //
@@ -47,4 +47,31 @@
 //	    SubAgents:     []agent.Agent{reviewer, improver},
 //	    MaxIterations: 3,
 //	})
+//
+// # ConditionalAgent
+//
+// Runs one sub-agent, chosen by predicates over session state:
+//
+//	agent, _ := workflowagent.NewConditional(workflowagent.ConditionalConfig{
+//	    Name:        "router",
+//	    Description: "Routes requests by classification",
+//	    Cases: []workflowagent.ConditionalCase{
+//	        {When: `{classification} == "billing"`, Agent: billing},
+//	    },
+//	    Default: general,
+//	})
+//
+// # MapReduceAgent
+//
+// Runs a mapper once per item of a list in session state, then a reducer
+// over the collected outputs:
+//
+//	agent, _ := workflowagent.NewMapReduce(workflowagent.MapReduceConfig{
+//	    Name:           "summarizer",
+//	    InputKey:       "sections",
+//	    OutputKey:      "section_summaries",
+//	    Mapper:         sectionSummarizer,
+//	    Reducer:        merger,
+//	    MaxConcurrency: 4,
+//	})
 package workflowagent
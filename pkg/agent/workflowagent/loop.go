@@ -17,6 +17,7 @@ package workflowagent
 
 import (
 	"iter"
+	"time"
 
 	"github.com/kadirpekel/hector/pkg/agent"
 )
@@ -38,6 +39,13 @@ type LoopConfig struct {
 	// MaxIterations is the maximum number of iterations.
 	// If 0, runs indefinitely until any sub-agent escalates.
 	MaxIterations uint
+
+	// Retry re-runs sub-agents that fail. Nil disables retries.
+	Retry *RetryConfig
+
+	// Timeout bounds each run of the workflow. Sub-agents are also bounded
+	// by their own timeouts. Zero means no timeout.
+	Timeout time.Duration
 }
 
 // NewLoop creates a LoopAgent.
@@ -71,15 +79,16 @@ func NewLoop(cfg LoopConfig) (agent.Agent, error) {
 		Name:        cfg.Name,
 		Description: cfg.Description,
 		SubAgents:   cfg.SubAgents,
+		Timeout:     cfg.Timeout,
 		Run: func(ctx agent.InvocationContext) iter.Seq2[*agent.Event, error] {
-			return runLoop(ctx, maxIterations)
+			return runLoop(ctx, maxIterations, cfg.Retry)
 		},
 		AgentType: agentType,
 	})
 
 }
 
-func runLoop(ctx agent.InvocationContext, maxIterations uint) iter.Seq2[*agent.Event, error] {
+func runLoop(ctx agent.InvocationContext, maxIterations uint, retry *RetryConfig) iter.Seq2[*agent.Event, error] {
 	count := maxIterations
 
 	return func(yield func(*agent.Event, error) bool) {
@@ -87,18 +96,20 @@ func runLoop(ctx agent.InvocationContext, maxIterations uint) iter.Seq2[*agent.E
 			shouldExit := false
 
 			for _, subAgent := range ctx.Agent().SubAgents() {
-				// Create sub-context for the sub-agent
-				subCtx := agent.NewInvocationContext(ctx, agent.InvocationContextParams{
-					Agent:       subAgent,
-					Session:     ctx.Session(),
-					Artifacts:   ctx.Artifacts(),
-					Memory:      ctx.Memory(),
-					UserContent: ctx.UserContent(),
-					RunConfig:   ctx.RunConfig(),
-					Branch:      ctx.Branch(), // Share branch so sub-agents see each other's events
-				})
-
-				for event, err := range subAgent.Run(subCtx) {
+				// Create sub-context for the sub-agent (fresh per attempt)
+				newSubCtx := func() agent.InvocationContext {
+					return agent.NewInvocationContext(ctx, agent.InvocationContextParams{
+						Agent:       subAgent,
+						Session:     ctx.Session(),
+						Artifacts:   ctx.Artifacts(),
+						Memory:      ctx.Memory(),
+						UserContent: ctx.UserContent(),
+						RunConfig:   ctx.RunConfig(),
+						Branch:      ctx.Branch(), // Share branch so sub-agents see each other's events
+					})
+				}
+
+				for event, err := range runWithRetry(ctx, subAgent, retry, newSubCtx) {
 					if !yield(event, err) {
 						return
 					}
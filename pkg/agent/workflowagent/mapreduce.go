@@ -0,0 +1,313 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflowagent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"log/slog"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+)
+
+// MapReduceConfig defines the configuration for a MapReduceAgent.
+type MapReduceConfig struct {
+	// Name is the agent name.
+	Name string
+
+	// Description describes what the agent does.
+	Description string
+
+	// InputKey is the session state key holding the list of items to map.
+	// The value may be a list or a string containing a JSON array.
+	InputKey string
+
+	// OutputKey is the session state key the mapper outputs are written to,
+	// as a list indexed like the input. Default: "<name>_outputs"
+	OutputKey string
+
+	// Mapper runs once per item, with the item as its input.
+	Mapper agent.Agent
+
+	// Reducer runs once after all mappers, with OutputKey in state.
+	// If nil, the agent stops after collecting the outputs.
+	Reducer agent.Agent
+
+	// MaxConcurrency bounds the number of mappers running at once.
+	// 0 runs all of them at once.
+	MaxConcurrency int
+
+	// Retry re-runs the mapper or reducer if it fails. Nil disables retries.
+	Retry *RetryConfig
+
+	// Timeout bounds each run of the workflow. Sub-agents are also bounded
+	// by their own timeouts. Zero means no timeout.
+	Timeout time.Duration
+}
+
+// NewMapReduce creates a MapReduceAgent.
+//
+// MapReduceAgent fans a list out to a mapper and fans the results back in.
+// It reads the list from session state, runs the mapper once per item with
+// at most MaxConcurrency runs at a time, and writes the final text of each
+// run to OutputKey as a list in input order. The reducer then runs over
+// the collected outputs, typically by referencing {OutputKey} in its
+// instruction.
+//
+// Each mapper run gets its own branch, so it sees the conversation so far
+// and its own item, but not the other items. If a mapper fails or the
+// context is cancelled, outstanding mappers are cancelled and the reducer
+// does not run.
+//
+// Example:
+//
+//	summarizer, _ := workflowagent.NewMapReduce(workflowagent.MapReduceConfig{
+//	    Name:           "summarizer",
+//	    Description:    "Summarizes each section, then merges the summaries",
+//	    InputKey:       "sections",
+//	    OutputKey:      "section_summaries",
+//	    Mapper:         sectionSummarizer,
+//	    Reducer:        merger,
+//	    MaxConcurrency: 4,
+//	})
+func NewMapReduce(cfg MapReduceConfig) (agent.Agent, error) {
+	if cfg.InputKey == "" {
+		return nil, fmt.Errorf("map-reduce agent %q requires an input key", cfg.Name)
+	}
+	if cfg.Mapper == nil {
+		return nil, fmt.Errorf("map-reduce agent %q requires a mapper", cfg.Name)
+	}
+	if cfg.MaxConcurrency < 0 {
+		return nil, fmt.Errorf("map-reduce agent %q: max concurrency must be non-negative", cfg.Name)
+	}
+	if cfg.OutputKey == "" {
+		cfg.OutputKey = cfg.Name + "_outputs"
+	}
+
+	subAgents := []agent.Agent{cfg.Mapper}
+	if cfg.Reducer != nil && cfg.Reducer.Name() != cfg.Mapper.Name() {
+		subAgents = append(subAgents, cfg.Reducer)
+	}
+
+	return agent.New(agent.Config{
+		Name:        cfg.Name,
+		Description: cfg.Description,
+		SubAgents:   subAgents,
+		Timeout:     cfg.Timeout,
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*agent.Event, error] {
+			return runMapReduce(ctx, cfg)
+		},
+		AgentType: agent.TypeMapReduceAgent,
+	})
+}
+
+func runMapReduce(ctx agent.InvocationContext, cfg MapReduceConfig) iter.Seq2[*agent.Event, error] {
+	return func(yield func(*agent.Event, error) bool) {
+		var value any
+		if ctx.Session() != nil {
+			value, _ = ctx.Session().State().Get(cfg.InputKey)
+		}
+		items, err := mapItems(value)
+		if err != nil {
+			yield(nil, fmt.Errorf("map-reduce agent %q: state key %q: %w", cfg.Name, cfg.InputKey, err))
+			return
+		}
+
+		outputs, ok := runMappers(ctx, cfg, items, yield)
+		if !ok {
+			return
+		}
+
+		// Write the outputs through the session so the reducer can read them
+		event := agent.NewEvent(ctx.InvocationID())
+		event.Author = ctx.Agent().Name()
+		event.Branch = ctx.Branch()
+		event.Actions.StateDelta[cfg.OutputKey] = outputs
+		if !yield(event, nil) {
+			return
+		}
+
+		if cfg.Reducer == nil {
+			return
+		}
+		newSubCtx := func() agent.InvocationContext {
+			return agent.NewInvocationContext(ctx, agent.InvocationContextParams{
+				Agent:       cfg.Reducer,
+				Session:     ctx.Session(),
+				Artifacts:   ctx.Artifacts(),
+				Memory:      ctx.Memory(),
+				UserContent: ctx.UserContent(),
+				RunConfig:   ctx.RunConfig(),
+				Branch:      ctx.Branch(),
+			})
+		}
+		for event, err := range runWithRetry(ctx, cfg.Reducer, cfg.Retry, newSubCtx) {
+			if !yield(event, err) {
+				return
+			}
+		}
+	}
+}
+
+// runMappers runs the mapper over items, forwarding its events to yield.
+// It returns the outputs and whether the reducer should run.
+func runMappers(ctx agent.InvocationContext, cfg MapReduceConfig, items []any, yield func(*agent.Event, error) bool) ([]any, bool) {
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		errGroup, errGroupCtx = errgroup.WithContext(runCtx)
+		doneChan              = make(chan bool)
+		resultsChan           = make(chan result)
+		outputs               = make([]any, len(items))
+		curAgent              = ctx.Agent()
+	)
+	if cfg.MaxConcurrency > 0 {
+		errGroup.SetLimit(cfg.MaxConcurrency)
+	}
+
+	// Start mappers from a separate goroutine, as Go blocks at the limit
+	go func() {
+		for i, item := range items {
+			if errGroupCtx.Err() != nil {
+				break
+			}
+			branch := fmt.Sprintf("%s.%s.%d", curAgent.Name(), cfg.Mapper.Name(), i)
+			if ctx.Branch() != "" {
+				branch = fmt.Sprintf("%s.%s", ctx.Branch(), branch)
+			}
+
+			errGroup.Go(func() error {
+				newSubCtx := func() agent.InvocationContext {
+					return agent.NewInvocationContext(errGroupCtx, agent.InvocationContextParams{
+						Agent:       cfg.Mapper,
+						Session:     ctx.Session(),
+						Artifacts:   ctx.Artifacts(),
+						Memory:      ctx.Memory(),
+						UserContent: ctx.UserContent(),
+						RunConfig:   ctx.RunConfig(),
+						Branch:      branch,
+					})
+				}
+
+				events := func(yield func(*agent.Event, error) bool) {
+					if !yield(newItemEvent(ctx, branch, item), nil) {
+						return
+					}
+					for event, err := range runWithRetry(ctx, cfg.Mapper, cfg.Retry, newSubCtx) {
+						// Each mapper writes only its own slot
+						if event != nil && !event.Partial && event.Author == cfg.Mapper.Name() {
+							if text := event.TextContent(); text != "" {
+								outputs[i] = text
+							}
+						}
+						if !yield(event, err) {
+							return
+						}
+					}
+				}
+				if err := runSubAgent(errGroupCtx, cfg.Mapper, events, resultsChan, doneChan); err != nil {
+					return fmt.Errorf("failed to map item %d: %w", i, err)
+				}
+				return nil
+			})
+		}
+
+		_ = errGroup.Wait()
+		close(resultsChan)
+	}()
+
+	defer close(doneChan)
+	failed := false
+	for res := range resultsChan {
+		if res.err != nil {
+			failed = true
+		}
+		if !yield(res.event, res.err) {
+			return nil, false
+		}
+		if failed {
+			// Cancel outstanding mappers; the reducer does not run
+			return nil, false
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		yield(nil, err)
+		return nil, false
+	}
+
+	// Mappers without text output leave an empty slot
+	for i := range outputs {
+		if outputs[i] == nil {
+			outputs[i] = ""
+		}
+	}
+	slog.Debug("Map-reduce agent collected outputs", "agent", curAgent.Name(), "items", len(items))
+	return outputs, true
+}
+
+// newItemEvent builds the event that hands item to the mapper on branch.
+func newItemEvent(ctx agent.InvocationContext, branch string, item any) *agent.Event {
+	event := agent.NewEvent(ctx.InvocationID())
+	event.Author = ctx.Agent().Name()
+	event.Branch = branch
+	event.Message = a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: itemText(item)})
+	return event
+}
+
+// mapItems returns the items of a state value.
+func mapItems(value any) ([]any, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, errors.New("not set")
+	case []any:
+		return v, nil
+	case []string:
+		items := make([]any, len(v))
+		for i, s := range v {
+			items[i] = s
+		}
+		return items, nil
+	case string:
+		var items []any
+		if err := json.Unmarshal([]byte(v), &items); err != nil {
+			return nil, fmt.Errorf("not a JSON array: %w", err)
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("expected a list, got %T", value)
+	}
+}
+
+// itemText renders an item as mapper input: strings as-is, others as JSON.
+func itemText(item any) string {
+	if s, ok := item.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Sprint(item)
+	}
+	return string(data)
+}
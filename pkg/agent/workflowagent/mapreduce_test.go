@@ -0,0 +1,231 @@
+package workflowagent
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+)
+
+// upperMapper replies with its item in upper case, tracking how many
+// mappers run at once.
+type upperMapper struct {
+	running, peak atomic.Int32
+	fail          string // item to fail on
+}
+
+func (m *upperMapper) agent(t *testing.T) agent.Agent {
+	t.Helper()
+	a, err := agent.New(agent.Config{
+		Name: "upper",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*agent.Event, error] {
+			return func(yield func(*agent.Event, error) bool) {
+				n := m.running.Add(1)
+				defer m.running.Add(-1)
+				for {
+					peak := m.peak.Load()
+					if n <= peak || m.peak.CompareAndSwap(peak, n) {
+						break
+					}
+				}
+
+				var item string
+				for event := range ctx.Session().Events().All() {
+					if event.Branch == ctx.Branch() {
+						item = event.TextContent()
+					}
+				}
+				if item == m.fail {
+					yield(nil, errors.New("mapper failed"))
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					yield(nil, ctx.Err())
+					return
+				case <-time.After(5 * time.Millisecond):
+				}
+
+				event := agent.NewEvent(ctx.InvocationID())
+				event.Author = "upper"
+				event.Branch = ctx.Branch()
+				event.Message = a2a.NewMessage(a2a.MessageRoleAgent, a2a.TextPart{Text: strings.ToUpper(item)})
+				yield(event, nil)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create mapper: %v", err)
+	}
+	return a
+}
+
+// joinReducer replies with the outputs in state joined by commas.
+func joinReducer(t *testing.T, key string) agent.Agent {
+	t.Helper()
+	a, err := agent.New(agent.Config{
+		Name: "join",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*agent.Event, error] {
+			return func(yield func(*agent.Event, error) bool) {
+				outputs, _ := ctx.Session().State().Get(key)
+				var parts []string
+				for _, o := range outputs.([]any) {
+					parts = append(parts, fmt.Sprint(o))
+				}
+				event := agent.NewEvent(ctx.InvocationID())
+				event.Author = "join"
+				event.Message = a2a.NewMessage(a2a.MessageRoleAgent, a2a.TextPart{Text: strings.Join(parts, ",")})
+				yield(event, nil)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create reducer: %v", err)
+	}
+	return a
+}
+
+func runMapReduceAgent(ctx context.Context, a agent.Agent, state testState) (string, error) {
+	session := &testSession{state: state}
+	invCtx := agent.NewInvocationContext(ctx, agent.InvocationContextParams{Agent: a, Session: session})
+	var last string
+	for event, err := range a.Run(invCtx) {
+		if err != nil {
+			return "", err
+		}
+		session.persist(event)
+		if event.Author == "join" {
+			last = event.TextContent()
+		}
+	}
+	return last, nil
+}
+
+func TestMapReduceCollectsOutputsInOrder(t *testing.T) {
+	mapper := &upperMapper{}
+	mr, err := NewMapReduce(MapReduceConfig{
+		Name:           "sections",
+		InputKey:       "items",
+		Mapper:         mapper.agent(t),
+		Reducer:        joinReducer(t, "sections_outputs"),
+		MaxConcurrency: 2,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	state := testState{"items": []any{"a", "b", "c", "d", "e"}}
+	got, err := runMapReduceAgent(context.Background(), mr, state)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got != "A,B,C,D,E" {
+		t.Errorf("reducer output = %q, want A,B,C,D,E", got)
+	}
+	if outputs, ok := state["sections_outputs"].([]any); !ok || len(outputs) != 5 {
+		t.Errorf("state outputs = %v", state["sections_outputs"])
+	}
+	if peak := mapper.peak.Load(); peak > 2 {
+		t.Errorf("peak concurrency = %d, want <= 2", peak)
+	}
+}
+
+func TestMapReduceInputs(t *testing.T) {
+	mr, err := NewMapReduce(MapReduceConfig{
+		Name:      "mr",
+		InputKey:  "items",
+		OutputKey: "out",
+		Mapper:    (&upperMapper{}).agent(t),
+		Reducer:   joinReducer(t, "out"),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	got, err := runMapReduceAgent(context.Background(), mr, testState{"items": `["x", {"k": "v"}]`})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got != `X,{"K":"V"}` {
+		t.Errorf("reducer output = %q", got)
+	}
+
+	if _, err := runMapReduceAgent(context.Background(), mr, testState{}); err == nil {
+		t.Error("expected error for missing input")
+	}
+	if _, err := runMapReduceAgent(context.Background(), mr, testState{"items": 42}); err == nil {
+		t.Error("expected error for non-list input")
+	}
+}
+
+func TestMapReduceMapperFailureSkipsReducer(t *testing.T) {
+	mr, err := NewMapReduce(MapReduceConfig{
+		Name:     "mr",
+		InputKey: "items",
+		Mapper:   (&upperMapper{fail: "bad"}).agent(t),
+		Reducer:  joinReducer(t, "mr_outputs"),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	got, err := runMapReduceAgent(context.Background(), mr, testState{"items": []string{"a", "bad", "c"}})
+	if err == nil || err.Error() != "mapper failed" {
+		t.Errorf("error = %v, want mapper failed", err)
+	}
+	if got != "" {
+		t.Errorf("reducer ran with output %q", got)
+	}
+}
+
+func TestMapReduceCancellation(t *testing.T) {
+	mapper := &upperMapper{}
+	mr, err := NewMapReduce(MapReduceConfig{
+		Name:           "mr",
+		InputKey:       "items",
+		Mapper:         mapper.agent(t),
+		Reducer:        joinReducer(t, "mr_outputs"),
+		MaxConcurrency: 1,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	items := make([]any, 100)
+	for i := range items {
+		items[i] = fmt.Sprint(i)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = runMapReduceAgent(ctx, mr, testState{"items": items})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("error = %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("cancellation took %v", elapsed)
+	}
+}
+
+func TestNewMapReduceValidation(t *testing.T) {
+	mapper := (&upperMapper{}).agent(t)
+	for _, cfg := range []MapReduceConfig{
+		{Name: "mr", Mapper: mapper},
+		{Name: "mr", InputKey: "items"},
+		{Name: "mr", InputKey: "items", Mapper: mapper, MaxConcurrency: -1},
+	} {
+		if _, err := NewMapReduce(cfg); err == nil {
+			t.Errorf("NewMapReduce(%+v) expected error", cfg)
+		}
+	}
+}
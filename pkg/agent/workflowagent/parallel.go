@@ -16,9 +16,11 @@
 package workflowagent
 
 import (
+	"context"
 	"fmt"
 	"iter"
 	"log/slog"
+	"time"
 
 	"golang.org/x/sync/errgroup"
 
@@ -35,6 +37,13 @@ type ParallelConfig struct {
 
 	// SubAgents are the agents to run in parallel.
 	SubAgents []agent.Agent
+
+	// Retry re-runs sub-agents that fail. Nil disables retries.
+	Retry *RetryConfig
+
+	// Timeout bounds each run of the workflow. Sub-agents are also bounded
+	// by their own timeouts. Zero means no timeout.
+	Timeout time.Duration
 }
 
 // NewParallel creates a ParallelAgent.
@@ -64,8 +73,9 @@ func NewParallel(cfg ParallelConfig) (agent.Agent, error) {
 		Name:        cfg.Name,
 		Description: cfg.Description,
 		SubAgents:   cfg.SubAgents,
+		Timeout:     cfg.Timeout,
 		Run: func(ctx agent.InvocationContext) iter.Seq2[*agent.Event, error] {
-			return runParallel(ctx)
+			return runParallel(ctx, cfg.Retry)
 		},
 		AgentType: agent.TypeParallelAgent,
 	})
@@ -77,7 +87,7 @@ type result struct {
 	err   error
 }
 
-func runParallel(ctx agent.InvocationContext) iter.Seq2[*agent.Event, error] {
+func runParallel(ctx agent.InvocationContext, retry *RetryConfig) iter.Seq2[*agent.Event, error] {
 	return func(yield func(*agent.Event, error) bool) {
 		var (
 			errGroup, errGroupCtx = errgroup.WithContext(ctx)
@@ -96,17 +106,20 @@ func runParallel(ctx agent.InvocationContext) iter.Seq2[*agent.Event, error] {
 			}
 
 			errGroup.Go(func() error {
-				subCtx := agent.NewInvocationContext(errGroupCtx, agent.InvocationContextParams{
-					Agent:       subAgent,
-					Session:     ctx.Session(),
-					Artifacts:   ctx.Artifacts(),
-					Memory:      ctx.Memory(),
-					UserContent: ctx.UserContent(),
-					RunConfig:   ctx.RunConfig(),
-					Branch:      branch,
-				})
-
-				if err := runSubAgent(subCtx, subAgent, resultsChan, doneChan); err != nil {
+				newSubCtx := func() agent.InvocationContext {
+					return agent.NewInvocationContext(errGroupCtx, agent.InvocationContextParams{
+						Agent:       subAgent,
+						Session:     ctx.Session(),
+						Artifacts:   ctx.Artifacts(),
+						Memory:      ctx.Memory(),
+						UserContent: ctx.UserContent(),
+						RunConfig:   ctx.RunConfig(),
+						Branch:      branch,
+					})
+				}
+
+				events := runWithRetry(ctx, subAgent, retry, newSubCtx)
+				if err := runSubAgent(errGroupCtx, subAgent, events, resultsChan, doneChan); err != nil {
 					return fmt.Errorf("failed to run sub-agent %q: %w", subAgent.Name(), err)
 				}
 				return nil
@@ -131,9 +144,9 @@ func runParallel(ctx agent.InvocationContext) iter.Seq2[*agent.Event, error] {
 	}
 }
 
-// runSubAgent runs a single sub-agent and forwards its events.
-func runSubAgent(ctx agent.InvocationContext, ag agent.Agent, results chan<- result, done <-chan bool) error {
-	for event, err := range ag.Run(ctx) {
+// runSubAgent forwards the events of a single sub-agent run.
+func runSubAgent(ctx context.Context, ag agent.Agent, events iter.Seq2[*agent.Event, error], results chan<- result, done <-chan bool) error {
+	for event, err := range events {
 		if err != nil {
 			// Forward error
 			select {
@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workflowagent
+
+import (
+	"context"
+	"iter"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+)
+
+// RetryEventType identifies the data part of events emitted before a
+// sub-agent is retried.
+const RetryEventType = "workflow_retry"
+
+// RetryConfig configures retries for sub-agents of a workflow agent.
+//
+// A failed sub-agent is re-run from scratch with a fresh invocation context.
+// Events produced by the failed attempt have already been yielded and stay
+// in the session, so sub-agents should be safe to re-run.
+type RetryConfig struct {
+	// MaxRetries is the number of retries after the first attempt.
+	// Zero disables retries.
+	MaxRetries int
+
+	// BaseDelay is the delay before the first retry.
+	// Each subsequent retry doubles it. Default: 1s
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between retries. Default: 30s
+	MaxDelay time.Duration
+
+	// Jitter adds randomness (0.0-1.0) to delays.
+	Jitter float64
+}
+
+// delay returns the backoff before the given retry (0-based).
+func (c *RetryConfig) delay(retry int) time.Duration {
+	base := c.BaseDelay
+	if base <= 0 {
+		base = time.Second
+	}
+	maxDelay := c.MaxDelay
+	if maxDelay <= 0 {
+		maxDelay = 30 * time.Second
+	}
+
+	d := float64(base) * math.Pow(2, float64(retry))
+	if c.Jitter > 0 {
+		d += d * c.Jitter * (rand.Float64()*2 - 1)
+	}
+	return min(time.Duration(d), maxDelay)
+}
+
+// runWithRetry runs a sub-agent, re-running it on failure according to cfg.
+//
+// newCtx is called for every attempt so each run gets a fresh invocation.
+// Before each retry a partial event describing the failure is yielded so
+// observers can see the retry; the last error is yielded once retries are
+// exhausted.
+func runWithRetry(parent agent.InvocationContext, subAgent agent.Agent, cfg *RetryConfig, newCtx func() agent.InvocationContext) iter.Seq2[*agent.Event, error] {
+	if cfg == nil || cfg.MaxRetries <= 0 {
+		return subAgent.Run(newCtx())
+	}
+
+	return func(yield func(*agent.Event, error) bool) {
+		for attempt := 0; ; attempt++ {
+			// Wait on the attempt's context, which a parallel workflow
+			// cancels when a sibling fails
+			runCtx := newCtx()
+			var runErr error
+			for event, err := range subAgent.Run(runCtx) {
+				if err != nil {
+					runErr = err
+					break
+				}
+				if !yield(event, nil) {
+					return
+				}
+			}
+			if runErr == nil {
+				return
+			}
+
+			if attempt >= cfg.MaxRetries || runCtx.Err() != nil {
+				yield(nil, runErr)
+				return
+			}
+
+			delay := cfg.delay(attempt)
+			slog.Warn("Retrying sub-agent",
+				"workflow", parent.Agent().Name(),
+				"agent", subAgent.Name(),
+				"attempt", attempt+1,
+				"max_attempts", cfg.MaxRetries+1,
+				"delay", delay,
+				"error", runErr)
+
+			if !yield(newRetryEvent(parent, subAgent, attempt+1, cfg.MaxRetries+1, delay, runErr), nil) {
+				return
+			}
+
+			if err := sleep(runCtx, delay); err != nil {
+				yield(nil, runErr)
+				return
+			}
+		}
+	}
+}
+
+// newRetryEvent builds the event announcing a retry of subAgent.
+func newRetryEvent(ctx agent.InvocationContext, subAgent agent.Agent, attempt, maxAttempts int, delay time.Duration, err error) *agent.Event {
+	data := map[string]any{
+		"type":         RetryEventType,
+		"agent":        subAgent.Name(),
+		"attempt":      attempt,
+		"max_attempts": maxAttempts,
+		"delay_ms":     delay.Milliseconds(),
+		"error":        err.Error(),
+	}
+
+	event := agent.NewEvent(ctx.InvocationID())
+	event.Author = ctx.Agent().Name()
+	event.Branch = ctx.Branch()
+	event.Partial = true
+	event.Message = a2a.NewMessage(a2a.MessageRoleAgent, a2a.DataPart{Data: data})
+	event.CustomMetadata = map[string]any{RetryEventType: data}
+	return event
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,140 @@
+package workflowagent
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+)
+
+func TestSequentialRetriesFailedSubAgent(t *testing.T) {
+	attempts := 0
+	flaky, err := agent.New(agent.Config{
+		Name: "flaky",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*agent.Event, error] {
+			return func(yield func(*agent.Event, error) bool) {
+				attempts++
+				if attempts < 3 {
+					yield(nil, errors.New("transient failure"))
+					return
+				}
+				yield(agent.NewEvent(ctx.InvocationID()), nil)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	pipeline, err := NewSequential(SequentialConfig{
+		Name:      "pipeline",
+		SubAgents: []agent.Agent{flaky},
+		Retry:     &RetryConfig{MaxRetries: 2, BaseDelay: time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create pipeline: %v", err)
+	}
+
+	ctx := agent.NewInvocationContext(context.Background(), agent.InvocationContextParams{Agent: pipeline})
+
+	var retries, results int
+	for event, err := range pipeline.Run(ctx) {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if _, ok := event.CustomMetadata[RetryEventType]; ok {
+			retries++
+			continue
+		}
+		results++
+	}
+
+	if attempts != 3 {
+		t.Errorf("attempts = %d, want 3", attempts)
+	}
+	if retries != 2 {
+		t.Errorf("retry events = %d, want 2", retries)
+	}
+	if results != 1 {
+		t.Errorf("result events = %d, want 1", results)
+	}
+}
+
+func TestSequentialRetriesExhausted(t *testing.T) {
+	failing, err := agent.New(agent.Config{
+		Name: "failing",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*agent.Event, error] {
+			return func(yield func(*agent.Event, error) bool) {
+				yield(nil, errors.New("permanent failure"))
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	pipeline, err := NewSequential(SequentialConfig{
+		Name:      "pipeline",
+		SubAgents: []agent.Agent{failing},
+		Retry:     &RetryConfig{MaxRetries: 1, BaseDelay: time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create pipeline: %v", err)
+	}
+
+	ctx := agent.NewInvocationContext(context.Background(), agent.InvocationContextParams{Agent: pipeline})
+
+	var lastErr error
+	for _, err := range pipeline.Run(ctx) {
+		if err != nil {
+			lastErr = err
+		}
+	}
+	if lastErr == nil || lastErr.Error() != "permanent failure" {
+		t.Errorf("Expected final error to be surfaced, got %v", lastErr)
+	}
+}
+
+func TestRetryWaitStopsWhenAttemptContextIsCancelled(t *testing.T) {
+	failing, err := agent.New(agent.Config{
+		Name: "failing",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*agent.Event, error] {
+			return func(yield func(*agent.Event, error) bool) {
+				yield(nil, errors.New("transient failure"))
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	parent := agent.NewInvocationContext(context.Background(), agent.InvocationContextParams{Agent: failing})
+
+	// Attempts run under a context the parallel workflow's errgroup
+	// cancels when a sibling fails, while the parent stays alive
+	groupCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	newCtx := func() agent.InvocationContext {
+		return agent.NewInvocationContext(groupCtx, agent.InvocationContextParams{Agent: failing})
+	}
+
+	start := time.Now()
+	var lastErr error
+	for event, err := range runWithRetry(parent, failing, &RetryConfig{MaxRetries: 3, BaseDelay: time.Minute}, newCtx) {
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if _, ok := event.CustomMetadata[RetryEventType]; ok {
+			cancel() // A sibling failed while this sub-agent waits to retry
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("retry wait took %v after cancellation", elapsed)
+	}
+	if lastErr == nil {
+		t.Error("expected the failure to be surfaced")
+	}
+}
@@ -16,6 +16,8 @@
 package workflowagent
 
 import (
+	"time"
+
 	"github.com/kadirpekel/hector/pkg/agent"
 )
 
@@ -29,6 +31,13 @@ type SequentialConfig struct {
 
 	// SubAgents are the agents to run in sequence.
 	SubAgents []agent.Agent
+
+	// Retry re-runs sub-agents that fail. Nil disables retries.
+	Retry *RetryConfig
+
+	// Timeout bounds each run of the workflow. Sub-agents are also bounded
+	// by their own timeouts. Zero means no timeout.
+	Timeout time.Duration
 }
 
 // NewSequential creates a SequentialAgent.
@@ -57,5 +66,7 @@ func NewSequential(cfg SequentialConfig) (agent.Agent, error) {
 		SubAgents:     cfg.SubAgents,
 		MaxIterations: 1, // Sequential = single iteration
 		AgentType:     agent.TypeSequentialAgent,
+		Retry:         cfg.Retry,
+		Timeout:       cfg.Timeout,
 	})
 }
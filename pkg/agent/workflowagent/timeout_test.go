@@ -0,0 +1,98 @@
+package workflowagent
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+)
+
+// stalledAgent blocks until its context is done, then either reports the
+// cancellation or returns without an error.
+func stalledAgent(t *testing.T, name string, timeout time.Duration, silent bool) agent.Agent {
+	t.Helper()
+	a, err := agent.New(agent.Config{
+		Name:    name,
+		Timeout: timeout,
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*agent.Event, error] {
+			return func(yield func(*agent.Event, error) bool) {
+				<-ctx.Done()
+				if !silent {
+					yield(nil, ctx.Err())
+				}
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+	return a
+}
+
+func runUntilError(a agent.Agent) ([]string, error) {
+	session := &testSession{state: testState{}}
+	ctx := agent.NewInvocationContext(context.Background(), agent.InvocationContextParams{
+		Agent:   a,
+		Session: session,
+	})
+	var authors []string
+	for event, err := range a.Run(ctx) {
+		if err != nil {
+			return authors, err
+		}
+		session.persist(event)
+		authors = append(authors, event.Author)
+	}
+	return authors, nil
+}
+
+func TestSubAgentTimeout(t *testing.T) {
+	for _, silent := range []bool{false, true} {
+		seq, err := NewSequential(SequentialConfig{
+			Name: "pipeline",
+			SubAgents: []agent.Agent{
+				namedAgent(t, "first", false),
+				stalledAgent(t, "stalled", 20*time.Millisecond, silent),
+				namedAgent(t, "last", false),
+			},
+		})
+		if err != nil {
+			t.Fatalf("Failed to create agent: %v", err)
+		}
+
+		start := time.Now()
+		authors, err := runUntilError(seq)
+		if !errors.Is(err, agent.ErrTimeout) {
+			t.Errorf("silent=%v: error = %v, want ErrTimeout", silent, err)
+		}
+		if len(authors) != 1 || authors[0] != "first" {
+			t.Errorf("silent=%v: authors = %v, want [first]", silent, authors)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("silent=%v: timeout took %v", silent, elapsed)
+		}
+	}
+}
+
+func TestWorkflowTimeout(t *testing.T) {
+	seq, err := NewSequential(SequentialConfig{
+		Name:      "pipeline",
+		SubAgents: []agent.Agent{stalledAgent(t, "stalled", 0, false)},
+		Timeout:   20 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	_, err = runUntilError(seq)
+	if !errors.Is(err, agent.ErrTimeout) {
+		t.Fatalf("error = %v, want ErrTimeout", err)
+	}
+	if want := `agent "pipeline"`; !strings.Contains(err.Error(), want) {
+		t.Errorf("error = %v, want it to name %s", err, want)
+	}
+}
@@ -275,6 +275,15 @@ type ParallelConfig = workflowagent.ParallelConfig
 // LoopConfig is the configuration for a loop agent.
 type LoopConfig = workflowagent.LoopConfig
 
+// ConditionalConfig configures a conditional agent.
+type ConditionalConfig = workflowagent.ConditionalConfig
+
+// ConditionalCase is a branch of a conditional agent.
+type ConditionalCase = workflowagent.ConditionalCase
+
+// MapReduceConfig configures a map-reduce agent.
+type MapReduceConfig = workflowagent.MapReduceConfig
+
 // NewSequentialAgent creates an agent that runs sub-agents once, in sequence.
 //
 // Use this when you want execution to occur in a fixed, strict order,
@@ -345,6 +354,50 @@ func NewLoopAgent(cfg LoopConfig) (Agent, error) {
 	return workflowagent.NewLoop(cfg)
 }
 
+// NewConditionalAgent creates an agent that runs one of several branches.
+//
+// Cases are predicates over session state, evaluated in order. The agent
+// of the first case that holds runs; if none holds, the default agent runs.
+//
+// Use this to route requests after a classifier has stored its decision
+// in state, such as:
+//   - Sending billing questions to a billing agent
+//   - Skipping a review step for low-risk changes
+//
+// Example:
+//
+//	router, _ := pkg.NewConditionalAgent(pkg.ConditionalConfig{
+//	    Name:        "router",
+//	    Description: "Routes requests by classification",
+//	    Cases: []pkg.ConditionalCase{
+//	        {When: `{classification} == "billing"`, Agent: billing},
+//	    },
+//	    Default: general,
+//	})
+func NewConditionalAgent(cfg ConditionalConfig) (Agent, error) {
+	return workflowagent.NewConditional(cfg)
+}
+
+// NewMapReduceAgent creates an agent that maps a list and reduces the results.
+//
+// The mapper runs once per item of the list in the InputKey state key, with
+// bounded concurrency. Its outputs are written to OutputKey in input order,
+// then the reducer runs over them.
+//
+// Example:
+//
+//	summarizer, _ := pkg.NewMapReduceAgent(pkg.MapReduceConfig{
+//	    Name:           "summarizer",
+//	    InputKey:       "sections",
+//	    OutputKey:      "section_summaries",
+//	    Mapper:         sectionSummarizer,
+//	    Reducer:        merger,
+//	    MaxConcurrency: 4,
+//	})
+func NewMapReduceAgent(cfg MapReduceConfig) (Agent, error) {
+	return workflowagent.NewMapReduce(cfg)
+}
+
 // ============================================================================
 // Remote Agents (adk-go aligned)
 // ============================================================================
@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// DefaultAPIKeyHeader is the request header that carries an API key.
+const DefaultAPIKeyHeader = "X-API-Key"
+
+// APIKey maps a static key to the principal it authenticates.
+type APIKey struct {
+	Key      string
+	Subject  string
+	Role     string
+	TenantID string
+
+	// Scopes are exposed as the "scopes" custom claim ([]string).
+	Scopes []string
+}
+
+// APIKeyValidator authenticates callers by static API key.
+//
+// It implements TokenValidator: a key passed as a bearer token is accepted
+// too, and tokens matching no key are handed to the next validator (usually
+// a JWTValidator), so API keys and JWTs can be used side by side.
+type APIKeyValidator struct {
+	header string
+	keys   []apiKeyEntry
+	next   TokenValidator
+}
+
+type apiKeyEntry struct {
+	hash   [sha256.Size]byte
+	claims Claims
+}
+
+// NewAPIKeyValidator creates a validator for the given keys, read from the
+// header (DefaultAPIKeyHeader if empty). next validates tokens that match
+// no key; nil rejects them.
+func NewAPIKeyValidator(header string, keys []APIKey, next TokenValidator) (*APIKeyValidator, error) {
+	if header == "" {
+		header = DefaultAPIKeyHeader
+	}
+	v := &APIKeyValidator{header: header, next: next}
+	seen := make(map[[sha256.Size]byte]bool, len(keys))
+	for i, k := range keys {
+		if k.Key == "" || k.Subject == "" {
+			return nil, fmt.Errorf("api key %d: key and subject are required", i)
+		}
+		hash := sha256.Sum256([]byte(k.Key))
+		if seen[hash] {
+			return nil, fmt.Errorf("api key %d (%s): duplicate key", i, k.Subject)
+		}
+		seen[hash] = true
+
+		claims := Claims{Subject: k.Subject, Role: k.Role, TenantID: k.TenantID}
+		if len(k.Scopes) > 0 {
+			claims.Custom = map[string]any{"scopes": slices.Clone(k.Scopes)}
+		}
+		v.keys = append(v.keys, apiKeyEntry{hash: hash, claims: claims})
+	}
+	return v, nil
+}
+
+// Header returns the request header that carries the API key.
+func (v *APIKeyValidator) Header() string {
+	return v.header
+}
+
+// Authenticate returns the claims of the principal the key belongs to.
+//
+// Keys are compared by SHA-256 digest in constant time, and every
+// configured key is checked, so timing reveals neither how close a guess
+// was nor which key matched.
+func (v *APIKeyValidator) Authenticate(key string) (*Claims, bool) {
+	if key == "" {
+		return nil, false
+	}
+	hash := sha256.Sum256([]byte(key))
+	match := -1
+	for i := range v.keys {
+		if subtle.ConstantTimeCompare(hash[:], v.keys[i].hash[:]) == 1 {
+			match = i
+		}
+	}
+	if match < 0 {
+		return nil, false
+	}
+
+	// Return a copy so callers cannot modify the configured claims
+	claims := v.keys[match].claims
+	claims.Custom = maps.Clone(claims.Custom)
+	return &claims, true
+}
+
+// ValidateToken accepts a token that is one of the API keys, and passes
+// any other token to the next validator.
+func (v *APIKeyValidator) ValidateToken(ctx context.Context, tokenString string) (*Claims, error) {
+	if claims, ok := v.Authenticate(tokenString); ok {
+		return claims, nil
+	}
+	if v.next == nil {
+		return nil, ErrInvalidAPIKey
+	}
+	return v.next.ValidateToken(ctx, tokenString)
+}
+
+// Close closes the next validator.
+func (v *APIKeyValidator) Close() error {
+	if v.next == nil {
+		return nil
+	}
+	return v.next.Close()
+}
+
+// Ensure APIKeyValidator implements TokenValidator
+var _ TokenValidator = (*APIKeyValidator)(nil)
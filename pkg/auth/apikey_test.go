@@ -0,0 +1,179 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kadirpekel/hector/pkg/config"
+)
+
+// stubValidator accepts a single token.
+type stubValidator struct {
+	token  string
+	closed bool
+}
+
+func (s *stubValidator) ValidateToken(_ context.Context, token string) (*Claims, error) {
+	if token != s.token {
+		return nil, ErrInvalidToken
+	}
+	return &Claims{Subject: "jwt-user"}, nil
+}
+
+func (s *stubValidator) Close() error {
+	s.closed = true
+	return nil
+}
+
+func newTestKeys(t *testing.T, next TokenValidator) *APIKeyValidator {
+	t.Helper()
+	v, err := NewAPIKeyValidator("", []APIKey{
+		{Key: "key-billing", Subject: "billing", Role: "service", Scopes: []string{"agents:invoke"}},
+		{Key: "key-reports", Subject: "reports"},
+	}, next)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestAPIKeyValidator(t *testing.T) {
+	next := &stubValidator{token: "jwt-token"}
+	v := newTestKeys(t, next)
+
+	if v.Header() != DefaultAPIKeyHeader {
+		t.Errorf("Header() = %q, want %q", v.Header(), DefaultAPIKeyHeader)
+	}
+
+	claims, ok := v.Authenticate("key-billing")
+	if !ok || claims.Subject != "billing" || claims.Role != "service" {
+		t.Fatalf("Authenticate(key-billing) = %+v, %v", claims, ok)
+	}
+	if scopes, _ := claims.GetClaim("scopes"); len(scopes.([]string)) != 1 {
+		t.Errorf("scopes = %v, want [agents:invoke]", scopes)
+	}
+
+	// Returned claims are copies
+	claims.Custom["scopes"] = nil
+	claims, _ = v.Authenticate("key-billing")
+	if claims.Custom["scopes"] == nil {
+		t.Error("modifying returned claims changed the configured ones")
+	}
+
+	if _, ok := v.Authenticate("key-bill"); ok {
+		t.Error("prefix of a key should not authenticate")
+	}
+	if _, ok := v.Authenticate(""); ok {
+		t.Error("empty key should not authenticate")
+	}
+
+	// Tokens that are no key fall through to the next validator
+	if claims, err := v.ValidateToken(context.Background(), "jwt-token"); err != nil || claims.Subject != "jwt-user" {
+		t.Errorf("ValidateToken(jwt-token) = %+v, %v", claims, err)
+	}
+	if claims, err := v.ValidateToken(context.Background(), "key-reports"); err != nil || claims.Subject != "reports" {
+		t.Errorf("ValidateToken(key-reports) = %+v, %v", claims, err)
+	}
+
+	if err := v.Close(); err != nil || !next.closed {
+		t.Errorf("Close() = %v, next closed = %v", err, next.closed)
+	}
+
+	// Without a next validator unknown tokens are rejected
+	alone := newTestKeys(t, nil)
+	if _, err := alone.ValidateToken(context.Background(), "jwt-token"); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("err = %v, want ErrInvalidAPIKey", err)
+	}
+}
+
+func TestNewAPIKeyValidatorRejectsBadKeys(t *testing.T) {
+	if _, err := NewAPIKeyValidator("", []APIKey{{Key: "k"}}, nil); err == nil {
+		t.Error("missing subject should fail")
+	}
+	dup := []APIKey{{Key: "k", Subject: "a"}, {Key: "k", Subject: "b"}}
+	if _, err := NewAPIKeyValidator("", dup, nil); err == nil {
+		t.Error("duplicate key should fail")
+	}
+}
+
+func TestMiddlewareAPIKey(t *testing.T) {
+	v := newTestKeys(t, &stubValidator{token: "jwt-token"})
+	var subject string
+	handler := MiddlewareWithExclusions(v, []string{"/", "/health"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		subject = ""
+		if claims := ClaimsFromContext(r.Context()); claims != nil {
+			subject = claims.Subject
+		}
+	}))
+
+	tests := []struct {
+		name        string
+		path        string
+		headers     map[string]string
+		wantStatus  int
+		wantSubject string
+	}{
+		{"api key", "/agents", map[string]string{"X-API-Key": "key-billing"}, http.StatusOK, "billing"},
+		{"api key wins over jwt", "/agents", map[string]string{"X-API-Key": "key-billing", "Authorization": "Bearer jwt-token"}, http.StatusOK, "billing"},
+		{"bad api key is not retried as jwt", "/agents", map[string]string{"X-API-Key": "nope", "Authorization": "Bearer jwt-token"}, http.StatusUnauthorized, ""},
+		{"jwt", "/agents", map[string]string{"Authorization": "Bearer jwt-token"}, http.StatusOK, "jwt-user"},
+		{"no credentials", "/tasks", nil, http.StatusUnauthorized, ""},
+		{"root is public", "/", nil, http.StatusOK, ""},
+		{"health is public", "/health", nil, http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subject = ""
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for k, val := range tt.headers {
+				req.Header.Set(k, val)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if subject != tt.wantSubject {
+				t.Errorf("subject = %q, want %q", subject, tt.wantSubject)
+			}
+		})
+	}
+}
+
+func TestNewValidatorFromConfigAPIKeysOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.yaml")
+	data := "- key: from-file\n  subject: file-service\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.AuthConfig{
+		Enabled: true,
+		APIKeys: &config.APIKeysConfig{
+			Header: "X-Internal-Key",
+			Keys:   []config.APIKeyConfig{{Key: "inline", Subject: "inline-service"}},
+			File:   path,
+		},
+	}
+	validator, err := NewValidatorFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("NewValidatorFromConfig: %v", err)
+	}
+	keys, ok := validator.(*APIKeyValidator)
+	if !ok {
+		t.Fatalf("validator = %T, want *APIKeyValidator", validator)
+	}
+	if keys.Header() != "X-Internal-Key" {
+		t.Errorf("Header() = %q", keys.Header())
+	}
+	for key, want := range map[string]string{"inline": "inline-service", "from-file": "file-service"} {
+		if claims, ok := keys.Authenticate(key); !ok || claims.Subject != want {
+			t.Errorf("Authenticate(%q) = %+v, %v", key, claims, ok)
+		}
+	}
+}
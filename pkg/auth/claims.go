@@ -43,6 +43,8 @@ package auth
 
 import (
 	"context"
+	"slices"
+	"strings"
 )
 
 // contextKey is a private type for context keys to avoid collisions.
@@ -107,6 +109,56 @@ func (c *Claims) HasAnyRole(roles ...string) bool {
 	return false
 }
 
+// Scopes returns the caller's granted scopes, merged from the "scopes"
+// claim (API keys), the OAuth 2.0 "scope" claim (space-separated string)
+// and the "scp" claim (Okta, Azure AD). Duplicates are removed.
+func (c *Claims) Scopes() []string {
+	if c == nil || c.Custom == nil {
+		return nil
+	}
+	var scopes []string
+	seen := make(map[string]bool)
+	add := func(scope string) {
+		if scope != "" && !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	for _, key := range []string{"scopes", "scope", "scp"} {
+		switch v := c.Custom[key].(type) {
+		case string:
+			for _, scope := range strings.Fields(v) {
+				add(scope)
+			}
+		case []string:
+			for _, scope := range v {
+				add(scope)
+			}
+		case []any:
+			for _, item := range v {
+				if scope, ok := item.(string); ok {
+					add(scope)
+				}
+			}
+		}
+	}
+	return scopes
+}
+
+// HasScopes checks if the caller was granted all of the specified scopes.
+func (c *Claims) HasScopes(scopes ...string) bool {
+	if len(scopes) == 0 {
+		return true
+	}
+	granted := c.Scopes()
+	for _, scope := range scopes {
+		if !slices.Contains(granted, scope) {
+			return false
+		}
+	}
+	return true
+}
+
 // ClaimsFromContext extracts claims from a context.
 // Returns nil if no claims are present.
 func ClaimsFromContext(ctx context.Context) *Claims {
@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestClaimsScopes(t *testing.T) {
+	claims := &Claims{Custom: map[string]any{
+		"scopes": []string{"reports:read"},
+		"scope":  "openid reports:read reports:write",
+		"scp":    []any{"admin", 42},
+	}}
+	want := []string{"reports:read", "openid", "reports:write", "admin"}
+	if got := claims.Scopes(); !slices.Equal(got, want) {
+		t.Errorf("Scopes() = %v, want %v", got, want)
+	}
+
+	if !claims.HasScopes() || !claims.HasScopes("reports:read", "admin") {
+		t.Error("HasScopes() = false for granted scopes")
+	}
+	if claims.HasScopes("reports:read", "reports:delete") {
+		t.Error("HasScopes() = true with a missing scope")
+	}
+
+	var none *Claims
+	if none.Scopes() != nil || none.HasScopes("openid") {
+		t.Error("nil claims should grant no scopes")
+	}
+}
@@ -33,4 +33,7 @@ var (
 
 	// ErrMissingClaims is returned when required claims are missing.
 	ErrMissingClaims = errors.New("missing required claims")
+
+	// ErrInvalidAPIKey is returned when an API key matches no configured key.
+	ErrInvalidAPIKey = errors.New("invalid API key")
 )
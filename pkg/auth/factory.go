@@ -17,6 +17,9 @@ package auth
 
 import (
 	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
 
 	"github.com/kadirpekel/hector/pkg/config"
 )
@@ -36,16 +39,65 @@ func NewValidatorFromConfig(cfg *config.AuthConfig) (TokenValidator, error) {
 		return nil, fmt.Errorf("invalid auth config: %w", err)
 	}
 
-	// Create JWT validator
-	validator, err := NewJWTValidator(JWTValidatorConfig{
-		JWKSURL:         cfg.JWKSURL,
-		Issuer:          cfg.Issuer,
-		Audience:        cfg.Audience,
-		RefreshInterval: cfg.RefreshInterval,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to create JWT validator: %w", err)
+	var validator TokenValidator
+	if cfg.HasJWT() {
+		jwtValidator, err := NewJWTValidator(JWTValidatorConfig{
+			JWKSURL:         cfg.JWKSURL,
+			Issuer:          cfg.Issuer,
+			Audience:        cfg.Audience,
+			RefreshInterval: cfg.RefreshInterval,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create JWT validator: %w", err)
+		}
+		validator = jwtValidator
+	}
+
+	// API keys are checked first and fall back to JWT validation
+	if cfg.APIKeys != nil {
+		keys, err := apiKeysFromConfig(cfg.APIKeys)
+		if err != nil {
+			if validator != nil {
+				_ = validator.Close()
+			}
+			return nil, err
+		}
+		apiKeyValidator, err := NewAPIKeyValidator(cfg.APIKeys.Header, keys, validator)
+		if err != nil {
+			if validator != nil {
+				_ = validator.Close()
+			}
+			return nil, fmt.Errorf("invalid auth.api_keys: %w", err)
+		}
+		validator = apiKeyValidator
 	}
 
 	return validator, nil
 }
+
+// apiKeysFromConfig collects the configured keys and the ones in the keys file.
+func apiKeysFromConfig(cfg *config.APIKeysConfig) ([]APIKey, error) {
+	entries := cfg.Keys
+	if cfg.File != "" {
+		data, err := os.ReadFile(cfg.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read auth.api_keys.file: %w", err)
+		}
+		var fileEntries []config.APIKeyConfig
+		if err := yaml.Unmarshal(data, &fileEntries); err != nil {
+			return nil, fmt.Errorf("failed to parse auth.api_keys.file %s: %w", cfg.File, err)
+		}
+		for i, e := range fileEntries {
+			if err := e.Validate(); err != nil {
+				return nil, fmt.Errorf("auth.api_keys.file %s: entry %d: %w", cfg.File, i, err)
+			}
+		}
+		entries = append(append([]config.APIKeyConfig{}, entries...), fileEntries...)
+	}
+
+	keys := make([]APIKey, len(entries))
+	for i, e := range entries {
+		keys[i] = APIKey{Key: e.Key, Subject: e.Subject, Role: e.Role, TenantID: e.TenantID, Scopes: e.Scopes}
+	}
+	return keys, nil
+}
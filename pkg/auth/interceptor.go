@@ -18,6 +18,7 @@ package auth
 import (
 	"context"
 
+	"github.com/a2aproject/a2a-go/a2a"
 	"github.com/a2aproject/a2a-go/a2asrv"
 )
 
@@ -50,12 +51,23 @@ func NewInterceptor(requireAuth bool) *Interceptor {
 	}
 }
 
+// MetaKeyUserID is the message metadata key naming the user a message is
+// sent on behalf of. With auth enabled, the Interceptor overwrites it with
+// the authenticated subject.
+const MetaKeyUserID = "user_id"
+
 // Before is called before each a2a-go request handler method.
-// It bridges Claims from HTTP context to a2a-go's User interface.
+// It bridges Claims from HTTP context to a2a-go's User interface, and binds
+// the user_id of sent messages to the authenticated subject so callers
+// cannot act as another user. Anonymous callers lose any user_id they set.
 func (i *Interceptor) Before(ctx context.Context, callCtx *a2asrv.CallContext, req *a2asrv.Request) (context.Context, error) {
 	// Get claims from HTTP context (set by auth.Middleware)
 	claims := ClaimsFromContext(ctx)
 
+	if params, ok := req.Payload.(*a2a.MessageSendParams); ok && params != nil && params.Message != nil {
+		bindUserID(params.Message, claims)
+	}
+
 	if claims != nil {
 		// Set authenticated user on a2a-go CallContext
 		callCtx.User = &AuthenticatedUser{claims: claims}
@@ -68,6 +80,19 @@ func (i *Interceptor) Before(ctx context.Context, callCtx *a2asrv.CallContext, r
 	return ctx, nil
 }
 
+// bindUserID sets the message's user_id to the claims subject, or removes it
+// for anonymous callers.
+func bindUserID(msg *a2a.Message, claims *Claims) {
+	if claims != nil && claims.Subject != "" {
+		if msg.Metadata == nil {
+			msg.Metadata = make(map[string]any)
+		}
+		msg.Metadata[MetaKeyUserID] = claims.Subject
+		return
+	}
+	delete(msg.Metadata, MetaKeyUserID)
+}
+
 // After is called after each a2a-go request handler method.
 // Currently a no-op but can be extended for audit logging.
 func (i *Interceptor) After(ctx context.Context, callCtx *a2asrv.CallContext, resp *a2asrv.Response) error {
@@ -29,11 +29,23 @@ import (
 //   - "Bearer <token>" format (preferred)
 //   - Raw token (fallback)
 //
+// When the validator is an APIKeyValidator, a key in its header (X-API-Key
+// by default) is checked first and the Authorization header is ignored.
+//
 // Valid claims are stored in the request context and can be retrieved
 // using ClaimsFromContext().
 func Middleware(validator TokenValidator) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if claims, ok, err := requestAPIKey(r, validator); ok {
+				if err != nil {
+					writeAuthError(w, "Invalid API key", http.StatusUnauthorized)
+					return
+				}
+				next.ServeHTTP(w, r.WithContext(ContextWithClaims(r.Context(), claims)))
+				return
+			}
+
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
 				writeAuthError(w, "Missing Authorization header", http.StatusUnauthorized)
@@ -90,7 +102,8 @@ func MiddlewareWithExclusions(validator TokenValidator, excludedPaths []string)
 
 			// Check prefix matches (for paths in exclusion list ending with /)
 			for path := range excludeSet {
-				if strings.HasSuffix(path, "/") && strings.HasPrefix(r.URL.Path, path) {
+				// "/" excludes only the root, not every path
+				if path != "/" && strings.HasSuffix(path, "/") && strings.HasPrefix(r.URL.Path, path) {
 					next.ServeHTTP(w, r)
 					return
 				}
@@ -156,6 +169,15 @@ func RequireTenant(tenants ...string) func(http.Handler) http.Handler {
 func OptionalMiddleware(validator TokenValidator) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if claims, ok, err := requestAPIKey(r, validator); ok {
+				if err != nil {
+					writeAuthError(w, "Invalid API key", http.StatusUnauthorized)
+					return
+				}
+				next.ServeHTTP(w, r.WithContext(ContextWithClaims(r.Context(), claims)))
+				return
+			}
+
 			authHeader := r.Header.Get("Authorization")
 			if authHeader == "" {
 				// No token - proceed without auth
@@ -182,6 +204,37 @@ func OptionalMiddleware(validator TokenValidator) func(http.Handler) http.Handle
 	}
 }
 
+// AuthenticateRequest validates the credentials of a request the way
+// Middleware does, for handlers that check authentication themselves.
+func AuthenticateRequest(r *http.Request, validator TokenValidator) (*Claims, error) {
+	if claims, ok, err := requestAPIKey(r, validator); ok {
+		return claims, err
+	}
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		return nil, ErrUnauthorized
+	}
+	return validator.ValidateToken(r.Context(), extractToken(authHeader))
+}
+
+// requestAPIKey authenticates the API key header of r, if the validator
+// accepts API keys and the header is set. ok reports whether it was.
+func requestAPIKey(r *http.Request, validator TokenValidator) (claims *Claims, ok bool, err error) {
+	keys, isAPIKey := validator.(*APIKeyValidator)
+	if !isAPIKey {
+		return nil, false, nil
+	}
+	key := r.Header.Get(keys.Header())
+	if key == "" {
+		return nil, false, nil
+	}
+	claims, valid := keys.Authenticate(key)
+	if !valid {
+		return nil, true, ErrInvalidAPIKey
+	}
+	return claims, true, nil
+}
+
 // extractToken extracts the token from an Authorization header.
 // Supports "Bearer <token>" and raw token formats.
 func extractToken(authHeader string) string {
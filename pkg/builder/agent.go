@@ -17,6 +17,7 @@ package builder
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/kadirpekel/hector/pkg/agent"
 	"github.com/kadirpekel/hector/pkg/agent/llmagent"
@@ -62,6 +63,11 @@ type AgentBuilder struct {
 	afterModelCallbacks  []llmagent.AfterModelCallback
 	beforeToolCallbacks  []llmagent.BeforeToolCallback
 	afterToolCallbacks   []llmagent.AfterToolCallback
+
+	outputTransforms   []llmagent.OutputTransform
+	transformStreaming bool
+
+	timeout time.Duration
 }
 
 // NewAgent creates a new agent builder.
@@ -399,6 +405,42 @@ func (b *AgentBuilder) WithAfterToolCallback(cb llmagent.AfterToolCallback) *Age
 	return b
 }
 
+// WithOutputTransform adds a transform that post-processes response text
+// before it reaches the client. Transforms run in the order they are added.
+//
+// Example:
+//
+//	builder.NewAgent("my-agent").WithOutputTransform(func(ctx agent.ReadonlyContext, text string) (string, error) {
+//	    return internalTagPattern.ReplaceAllString(text, ""), nil
+//	})
+func (b *AgentBuilder) WithOutputTransform(transform llmagent.OutputTransform) *AgentBuilder {
+	b.outputTransforms = append(b.outputTransforms, transform)
+	return b
+}
+
+// TransformStreaming applies output transforms to streamed chunks as well.
+// Streamed text is transformed one block (paragraph) at a time, so transforms
+// that need the full response only take full effect on the final response.
+//
+// Example:
+//
+//	builder.NewAgent("my-agent").TransformStreaming(true)
+func (b *AgentBuilder) TransformStreaming(enable bool) *AgentBuilder {
+	b.transformStreaming = enable
+	return b
+}
+
+// WithTimeout bounds each run of the agent. A run that takes longer is
+// cancelled and fails with agent.ErrTimeout.
+//
+// Example:
+//
+//	builder.NewAgent("my-agent").WithTimeout(2 * time.Minute)
+func (b *AgentBuilder) WithTimeout(d time.Duration) *AgentBuilder {
+	b.timeout = d
+	return b
+}
+
 // Build creates the agent.
 //
 // Returns an error if required parameters are missing.
@@ -434,6 +476,9 @@ func (b *AgentBuilder) Build() (agent.Agent, error) {
 		AfterModelCallbacks:      b.afterModelCallbacks,
 		BeforeToolCallbacks:      b.beforeToolCallbacks,
 		AfterToolCallbacks:       b.afterToolCallbacks,
+		OutputTransforms:         b.outputTransforms,
+		TransformStreaming:       b.transformStreaming,
+		Timeout:                  b.timeout,
 	}
 
 	return llmagent.New(cfg)
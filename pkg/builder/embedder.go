@@ -47,14 +47,14 @@ type EmbedderBuilder struct {
 	batchSize       int
 	encodingFormat  string // OpenAI: "float", "base64"
 	user            string // OpenAI: end-user identifier
-	inputType       string // Cohere: "search_document", "search_query", etc.
-	outputDimension int    // Cohere v4+: 256, 512, 1024, 1536
+	inputType       string // Cohere: "search_document", "search_query", etc.; Voyage: "document", "query", "none"
+	outputDimension int    // Cohere v4+: 256, 512, 1024, 1536; Voyage: 256, 512, 1024, 2048
 	truncate        string // Cohere: "NONE", "START", "END"
 }
 
 // NewEmbedder creates a new embedder builder.
 //
-// Supported providers: "openai", "ollama", "cohere"
+// Supported providers: "openai", "ollama", "cohere", "voyage"
 //
 // Example:
 //
@@ -80,6 +80,8 @@ func NewEmbedder(providerType string) *EmbedderBuilder {
 	case "cohere":
 		b.model = "embed-english-v3.0"
 		b.dimension = 1024
+	case "voyage":
+		b.model = "voyage-3.5"
 	}
 
 	return b
@@ -180,8 +182,9 @@ func (b *EmbedderBuilder) User(user string) *EmbedderBuilder {
 	return b
 }
 
-// InputType sets the input type for Cohere v3+ models.
-// Values: "search_document", "search_query", "classification", "clustering"
+// InputType sets the input type for Cohere v3+ and Voyage models.
+// Cohere: "search_document", "search_query", "classification", "clustering"
+// Voyage: "document" (default), "query", "none"; search queries always use "query"
 //
 // Example:
 //
@@ -191,8 +194,8 @@ func (b *EmbedderBuilder) InputType(inputType string) *EmbedderBuilder {
 	return b
 }
 
-// OutputDimension sets the output dimension for Cohere v4+ models.
-// Values: 256, 512, 1024, 1536
+// OutputDimension sets the output dimension for Cohere v4+ and Voyage models.
+// Values: 256, 512, 1024, 1536 (Cohere); 256, 512, 1024, 2048 (Voyage)
 //
 // Example:
 //
@@ -228,6 +231,8 @@ func (b *EmbedderBuilder) Build() (embedder.Embedder, error) {
 			b.apiKey = os.Getenv("OPENAI_API_KEY")
 		case "cohere":
 			b.apiKey = os.Getenv("COHERE_API_KEY")
+		case "voyage":
+			b.apiKey = os.Getenv("VOYAGE_API_KEY")
 		case "ollama":
 			// Ollama doesn't require API key
 		}
@@ -276,8 +281,23 @@ func (b *EmbedderBuilder) Build() (embedder.Embedder, error) {
 		}
 		return embedder.NewCohereEmbedder(cfg)
 
+	case "voyage":
+		cfg := embedder.VoyageConfig{
+			APIKey:    b.apiKey,
+			Model:     b.model,
+			BaseURL:   b.baseURL,
+			Dimension: b.dimension,
+			Timeout:   timeout,
+			BatchSize: b.batchSize,
+			InputType: b.inputType,
+		}
+		if b.outputDimension > 0 {
+			cfg.OutputDimension = &b.outputDimension
+		}
+		return embedder.NewVoyageEmbedder(cfg)
+
 	default:
-		return nil, fmt.Errorf("unknown embedder provider: %s (supported: openai, ollama, cohere)", b.providerType)
+		return nil, fmt.Errorf("unknown embedder provider: %s (supported: openai, ollama, cohere, voyage)", b.providerType)
 	}
 }
 
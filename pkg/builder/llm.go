@@ -16,6 +16,7 @@
 package builder
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"time"
@@ -23,6 +24,7 @@ import (
 	"github.com/kadirpekel/hector/pkg/config"
 	"github.com/kadirpekel/hector/pkg/model"
 	"github.com/kadirpekel/hector/pkg/model/anthropic"
+	"github.com/kadirpekel/hector/pkg/model/cohere"
 	"github.com/kadirpekel/hector/pkg/model/gemini"
 	"github.com/kadirpekel/hector/pkg/model/ollama"
 	"github.com/kadirpekel/hector/pkg/model/openai"
@@ -46,15 +48,34 @@ type LLMBuilder struct {
 	temperature         *float64
 	maxTokens           int
 	timeout             time.Duration
+	connectTimeout      time.Duration
 	maxRetries          int
 	enableThinking      bool
 	thinkingBudget      int
+	reasoningSummary    string
 	maxToolOutputLength int
+	storedResponses     bool
+	storedResponseTTL   time.Duration
+	roles               model.RoleMapping
+	systemMessages      model.SystemMessageMode
+
+	// Azure OpenAI deployment and Entra ID auth
+	azureEndpoint    string
+	azureDeployment  string
+	azureAPIVersion  string
+	azureTokenSource openai.TokenSource
+
+	// AWS Bedrock region and credentials
+	bedrockRegion      string
+	bedrockCredentials anthropic.AWSCredentialsProvider
+
+	// err records a failure from FromConfigFile, reported by Build.
+	err error
 }
 
 // NewLLM creates a new LLM builder.
 //
-// Supported providers: "openai", "anthropic", "gemini", "ollama"
+// Supported providers: "openai", "anthropic", "gemini", "ollama", "cohere", "azure_openai", "bedrock"
 //
 // Example:
 //
@@ -66,7 +87,6 @@ func NewLLM(providerType string) *LLMBuilder {
 	b := &LLMBuilder{
 		providerType: providerType,
 		maxRetries:   3,
-		timeout:      120 * time.Second,
 	}
 
 	// Set provider-specific defaults
@@ -79,9 +99,20 @@ func NewLLM(providerType string) *LLMBuilder {
 		b.baseURL = "https://api.anthropic.com"
 	case "gemini":
 		b.model = "gemini-2.0-flash"
+	case "azure_openai":
+		b.azureEndpoint = os.Getenv("AZURE_OPENAI_ENDPOINT")
 	case "ollama":
 		b.model = "qwen3"
 		b.baseURL = "http://localhost:11434"
+	case "cohere":
+		b.model = "command-r-plus"
+		b.baseURL = "https://api.cohere.com"
+	case "bedrock":
+		b.model = "anthropic.claude-3-5-sonnet-20240620-v1:0"
+		b.bedrockRegion = os.Getenv("AWS_REGION")
+		if b.bedrockRegion == "" {
+			b.bedrockRegion = os.Getenv("AWS_DEFAULT_REGION")
+		}
 	}
 
 	return b
@@ -153,7 +184,9 @@ func (b *LLMBuilder) MaxTokens(max int) *LLMBuilder {
 	return b
 }
 
-// Timeout sets the request timeout.
+// Timeout sets the total request timeout, from connect to the last byte.
+// Without it, non-streaming requests use the provider default and streams
+// run as long as they need.
 //
 // Example:
 //
@@ -163,6 +196,16 @@ func (b *LLMBuilder) Timeout(timeout time.Duration) *LLMBuilder {
 	return b
 }
 
+// ConnectTimeout bounds connection establishment (dial and TLS handshake).
+//
+// Example:
+//
+//	builder.NewLLM("openai").ConnectTimeout(5 * time.Second)
+func (b *LLMBuilder) ConnectTimeout(timeout time.Duration) *LLMBuilder {
+	b.connectTimeout = timeout
+	return b
+}
+
 // MaxToolOutputLength sets the maximum length for tool outputs.
 //
 // Example:
@@ -213,11 +256,129 @@ func (b *LLMBuilder) ThinkingBudget(budget int) *LLMBuilder {
 	return b
 }
 
+// ReasoningSummary sets OpenAI reasoning summary verbosity:
+// "none", "auto", "concise" or "detailed".
+//
+// Example:
+//
+//	builder.NewLLM("openai").
+//	    Model("o3-mini").
+//	    EnableThinking(true).
+//	    ReasoningSummary("detailed")
+func (b *LLMBuilder) ReasoningSummary(summary string) *LLMBuilder {
+	b.reasoningSummary = summary
+	return b
+}
+
+// StoredResponses enables OpenAI stored response chaining.
+// Follow-up turns send only new messages and reference the previous response.
+// A zero ttl uses OpenAI's retention period.
+//
+// Example:
+//
+//	builder.NewLLM("openai").StoredResponses(24 * time.Hour)
+func (b *LLMBuilder) StoredResponses(ttl time.Duration) *LLMBuilder {
+	b.storedResponses = true
+	b.storedResponseTTL = ttl
+	return b
+}
+
+// Azure sets the Azure OpenAI resource endpoint, deployment and API version
+// (provider "azure_openai"). An empty deployment uses the model name and an
+// empty apiVersion uses openai.DefaultAzureAPIVersion.
+//
+// Example:
+//
+//	builder.NewLLM("azure_openai").
+//	    Azure("https://myresource.openai.azure.com", "gpt-4o-prod", "").
+//	    APIKeyFromEnv("AZURE_OPENAI_API_KEY")
+func (b *LLMBuilder) Azure(endpoint, deployment, apiVersion string) *LLMBuilder {
+	b.azureEndpoint = endpoint
+	b.azureDeployment = deployment
+	b.azureAPIVersion = apiVersion
+	return b
+}
+
+// AzureADToken authenticates to Azure OpenAI with a static Entra ID bearer
+// token instead of an API key.
+//
+// Example:
+//
+//	builder.NewLLM("azure_openai").AzureADToken(os.Getenv("AZURE_OPENAI_AD_TOKEN"))
+func (b *LLMBuilder) AzureADToken(token string) *LLMBuilder {
+	b.azureTokenSource = openai.StaticToken(token)
+	return b
+}
+
+// AzureClientCredentials authenticates to Azure OpenAI as an Entra ID
+// service principal instead of with an API key. Tokens are refreshed
+// automatically.
+//
+// Example:
+//
+//	builder.NewLLM("azure_openai").AzureClientCredentials(tenantID, clientID, secret)
+func (b *LLMBuilder) AzureClientCredentials(tenantID, clientID, clientSecret string) *LLMBuilder {
+	b.azureTokenSource = openai.AzureClientCredentials(tenantID, clientID, clientSecret)
+	return b
+}
+
+// Bedrock sets the AWS region of Claude on Bedrock (provider "bedrock").
+// Requests are signed with credentials from the environment or the shared
+// credentials file unless BedrockCredentials is set.
+//
+// Example:
+//
+//	builder.NewLLM("bedrock").
+//	    Model("anthropic.claude-3-5-sonnet-20240620-v1:0").
+//	    Bedrock("us-east-1")
+func (b *LLMBuilder) Bedrock(region string) *LLMBuilder {
+	b.bedrockRegion = region
+	return b
+}
+
+// BedrockCredentials sets the AWS credentials Bedrock requests are signed
+// with.
+//
+// Example:
+//
+//	builder.NewLLM("bedrock").BedrockCredentials(anthropic.DefaultAWSCredentials("prod"))
+func (b *LLMBuilder) BedrockCredentials(creds anthropic.AWSCredentialsProvider) *LLMBuilder {
+	b.bedrockCredentials = creds
+	return b
+}
+
+// Roles overrides the role names sent to the provider.
+// Empty fields keep the provider's defaults.
+//
+// Example:
+//
+//	builder.NewLLM("openai").
+//	    BaseURL("http://localhost:8000/v1").
+//	    Roles(model.RoleMapping{System: "developer"})
+func (b *LLMBuilder) Roles(roles model.RoleMapping) *LLMBuilder {
+	b.roles = roles
+	return b
+}
+
+// SystemMessages sets how system messages that arrive after the first turn
+// are sent: merged into the instruction, as user messages, or natively.
+//
+// Example:
+//
+//	builder.NewLLM("ollama").SystemMessages(model.SystemMessagesUser)
+func (b *LLMBuilder) SystemMessages(mode model.SystemMessageMode) *LLMBuilder {
+	b.systemMessages = mode
+	return b
+}
+
 // Build creates the LLM provider.
 //
 // Returns an error if required parameters are missing or invalid.
 func (b *LLMBuilder) Build() (model.LLM, error) {
-	if b.model == "" {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.model == "" && (b.providerType != "azure_openai" || b.azureDeployment == "") {
 		return nil, fmt.Errorf("model is required")
 	}
 
@@ -230,13 +391,19 @@ func (b *LLMBuilder) Build() (model.LLM, error) {
 			b.apiKey = os.Getenv("ANTHROPIC_API_KEY")
 		case "gemini":
 			b.apiKey = os.Getenv("GEMINI_API_KEY")
+		case "azure_openai":
+			if b.azureTokenSource == nil {
+				b.apiKey = os.Getenv("AZURE_OPENAI_API_KEY")
+			}
+		case "cohere":
+			b.apiKey = os.Getenv("COHERE_API_KEY")
 		case "ollama":
 			// Ollama doesn't require API key
 		}
 	}
 
 	switch b.providerType {
-	case "openai":
+	case "openai", "azure_openai":
 		cfg := openai.Config{
 			APIKey:      b.apiKey,
 			Model:       b.model,
@@ -245,14 +412,31 @@ func (b *LLMBuilder) Build() (model.LLM, error) {
 			BaseURL:     b.baseURL,
 			Timeout:     b.timeout,
 			MaxRetries:  b.maxRetries,
+
+			ConnectTimeout: b.connectTimeout,
+			Roles:          b.roles,
+			SystemMessages: b.systemMessages,
 		}
 		if b.enableThinking {
 			cfg.EnableReasoning = true
 			cfg.ReasoningBudget = b.thinkingBudget
+			cfg.ReasoningSummary = b.reasoningSummary
+		}
+		if b.storedResponses {
+			cfg.EnableStoredResponses = true
+			cfg.StoredResponseTTL = b.storedResponseTTL
+		}
+		if b.providerType == "azure_openai" {
+			cfg.Azure = &openai.AzureConfig{
+				Endpoint:    b.azureEndpoint,
+				Deployment:  b.azureDeployment,
+				APIVersion:  b.azureAPIVersion,
+				TokenSource: b.azureTokenSource,
+			}
 		}
 		return openai.New(cfg)
 
-	case "anthropic":
+	case "anthropic", "bedrock":
 		cfg := anthropic.Config{
 			APIKey:      b.apiKey,
 			Model:       b.model,
@@ -261,11 +445,22 @@ func (b *LLMBuilder) Build() (model.LLM, error) {
 			BaseURL:     b.baseURL,
 			Timeout:     b.timeout,
 			MaxRetries:  b.maxRetries,
+
+			ConnectTimeout: b.connectTimeout,
+			Roles:          b.roles,
+			SystemMessages: b.systemMessages,
 		}
 		if b.enableThinking {
 			cfg.EnableThinking = true
 			cfg.ThinkingBudget = b.thinkingBudget
 		}
+		if b.providerType == "bedrock" {
+			cfg.Bedrock = &anthropic.BedrockConfig{
+				Region:      b.bedrockRegion,
+				Endpoint:    b.baseURL,
+				Credentials: b.bedrockCredentials,
+			}
+		}
 		return anthropic.New(cfg)
 
 	case "gemini":
@@ -279,6 +474,8 @@ func (b *LLMBuilder) Build() (model.LLM, error) {
 			MaxTokens:           b.maxTokens,
 			Temperature:         temp,
 			MaxToolOutputLength: b.maxToolOutputLength,
+			Roles:               b.roles,
+			SystemMessages:      b.systemMessages,
 		})
 
 	case "ollama":
@@ -294,10 +491,30 @@ func (b *LLMBuilder) Build() (model.LLM, error) {
 			cfg.EnableThinking = true
 		}
 		cfg.MaxToolOutputLength = b.maxToolOutputLength
+		cfg.Timeout = b.timeout
+		cfg.ConnectTimeout = b.connectTimeout
+		cfg.MaxRetries = b.maxRetries
+		cfg.Roles = b.roles
+		cfg.SystemMessages = b.systemMessages
 		return ollama.New(cfg)
 
+	case "cohere":
+		return cohere.New(cohere.Config{
+			APIKey:              b.apiKey,
+			Model:               b.model,
+			MaxTokens:           b.maxTokens,
+			Temperature:         b.temperature,
+			BaseURL:             b.baseURL,
+			Timeout:             b.timeout,
+			ConnectTimeout:      b.connectTimeout,
+			MaxRetries:          b.maxRetries,
+			MaxToolOutputLength: b.maxToolOutputLength,
+			Roles:               b.roles,
+			SystemMessages:      b.systemMessages,
+		})
+
 	default:
-		return nil, fmt.Errorf("unknown provider type: %s (supported: openai, anthropic, gemini, ollama)", b.providerType)
+		return nil, fmt.Errorf("unknown provider type: %s (supported: openai, anthropic, gemini, ollama, cohere, azure_openai, bedrock)", b.providerType)
 	}
 }
 
@@ -323,22 +540,145 @@ func LLMFromConfig(cfg *config.LLMConfig) *LLMBuilder {
 	if cfg == nil {
 		return NewLLM("")
 	}
+	return NewLLM(string(cfg.Provider)).FromConfig(cfg)
+}
+
+// FromConfig seeds the builder from a config.LLMConfig.
+// Fields set in cfg replace the builder's values; fluent methods called
+// afterwards override them. If cfg names a different provider, the builder
+// is reset to that provider's defaults first.
+//
+// Example:
+//
+//	llm, err := builder.NewLLM("openai").
+//	    FromConfig(baseCfg).
+//	    Temperature(0.2).
+//	    Build()
+func (b *LLMBuilder) FromConfig(cfg *config.LLMConfig) *LLMBuilder {
+	if cfg == nil {
+		return b
+	}
 
-	b := NewLLM(string(cfg.Provider))
-	b.model = cfg.Model
-	b.apiKey = cfg.APIKey
-	b.maxTokens = cfg.MaxTokens
-	b.temperature = cfg.Temperature
-	b.maxToolOutputLength = cfg.MaxToolOutputLength
+	if cfg.Provider != "" && string(cfg.Provider) != b.providerType {
+		*b = *NewLLM(string(cfg.Provider))
+	}
 
+	if cfg.Model != "" {
+		b.model = cfg.Model
+	}
+	if cfg.APIKey != "" {
+		b.apiKey = cfg.APIKey
+	}
 	if cfg.BaseURL != "" {
 		b.baseURL = cfg.BaseURL
 	}
+	if cfg.Temperature != nil {
+		b.temperature = cfg.Temperature
+	}
+	if cfg.MaxTokens != 0 {
+		b.maxTokens = cfg.MaxTokens
+	}
+	if cfg.MaxToolOutputLength != 0 {
+		b.maxToolOutputLength = cfg.MaxToolOutputLength
+	}
+	if cfg.ConnectTimeout != 0 {
+		b.connectTimeout = cfg.ConnectTimeout.Duration()
+	}
+	if cfg.TotalTimeout != 0 {
+		b.timeout = cfg.TotalTimeout.Duration()
+	}
+	if cfg.MaxRetries != 0 {
+		b.maxRetries = cfg.MaxRetries
+	}
 
 	if cfg.Thinking != nil && config.BoolValue(cfg.Thinking.Enabled, false) {
 		b.enableThinking = true
 		b.thinkingBudget = cfg.Thinking.BudgetTokens
+		b.reasoningSummary = cfg.Thinking.Summary
+	}
+
+	if cfg.StoredResponses != nil && config.BoolValue(cfg.StoredResponses.Enabled, false) {
+		b.storedResponses = true
+		b.storedResponseTTL = cfg.StoredResponses.TTL.Duration()
+	}
+
+	if cfg.Roles != nil {
+		b.roles = model.RoleMapping{
+			User:   cfg.Roles.User,
+			Agent:  cfg.Roles.Agent,
+			System: cfg.Roles.System,
+		}
+	}
+	if cfg.SystemMessages != "" {
+		b.systemMessages = model.SystemMessageMode(cfg.SystemMessages)
+	}
+
+	if az := cfg.Azure; az != nil {
+		if az.Endpoint != "" {
+			b.azureEndpoint = az.Endpoint
+		}
+		if az.Deployment != "" {
+			b.azureDeployment = az.Deployment
+		}
+		if az.APIVersion != "" {
+			b.azureAPIVersion = az.APIVersion
+		}
+		switch {
+		case az.ClientSecret != "":
+			b.AzureClientCredentials(az.TenantID, az.ClientID, az.ClientSecret)
+		case az.ADToken != "":
+			b.AzureADToken(az.ADToken)
+		}
+	}
+
+	if br := cfg.Bedrock; br != nil {
+		if br.Region != "" {
+			b.bedrockRegion = br.Region
+		}
+		if br.AccessKeyID != "" {
+			b.bedrockCredentials = anthropic.StaticAWSCredentials(br.AccessKeyID, br.SecretAccessKey, br.SessionToken)
+		} else if br.Profile != "" {
+			b.bedrockCredentials = anthropic.DefaultAWSCredentials(br.Profile)
+		}
 	}
 
 	return b
 }
+
+// FromConfigFile seeds the builder from an LLM defined in a Hector config
+// file, with environment variables expanded and defaults applied. name
+// selects the entry under llms; if empty, the file must define exactly one
+// LLM. Load errors are returned by Build.
+//
+// Example:
+//
+//	llm, err := builder.NewLLM("openai").
+//	    FromConfigFile("hector.yaml", "default").
+//	    MaxTokens(8000).
+//	    Build()
+func (b *LLMBuilder) FromConfigFile(path, name string) *LLMBuilder {
+	cfg, loader, err := config.LoadConfigFile(context.Background(), path)
+	if err != nil {
+		b.err = fmt.Errorf("failed to load %s: %w", path, err)
+		return b
+	}
+	_ = loader.Close()
+
+	if name == "" {
+		if len(cfg.LLMs) != 1 {
+			b.err = fmt.Errorf("%s defines %d llms; specify which one to use", path, len(cfg.LLMs))
+			return b
+		}
+		for n := range cfg.LLMs {
+			name = n
+		}
+	}
+
+	llmCfg, ok := cfg.LLMs[name]
+	if !ok || llmCfg == nil {
+		b.err = fmt.Errorf("llm %q not found in %s", name, path)
+		return b
+	}
+
+	return b.FromConfig(llmCfg)
+}
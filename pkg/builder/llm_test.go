@@ -0,0 +1,63 @@
+package builder
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kadirpekel/hector/pkg/config"
+)
+
+func TestLLMBuilderFromConfigThenOverride(t *testing.T) {
+	temp := 0.9
+	b := NewLLM("anthropic").
+		FromConfig(&config.LLMConfig{Provider: "openai", Model: "gpt-4o", BaseURL: "http://proxy/v1", Temperature: &temp}).
+		Temperature(0.2)
+
+	if b.providerType != "openai" || b.model != "gpt-4o" || b.baseURL != "http://proxy/v1" {
+		t.Errorf("builder = %s/%s/%s, want openai/gpt-4o/http://proxy/v1", b.providerType, b.model, b.baseURL)
+	}
+	if *b.temperature != 0.2 {
+		t.Errorf("temperature = %v, want fluent override 0.2", *b.temperature)
+	}
+}
+
+func TestLLMBuilderFromConfigTimeouts(t *testing.T) {
+	b := NewLLM("openai").FromConfig(&config.LLMConfig{
+		ConnectTimeout: config.Duration(5 * time.Second),
+		TotalTimeout:   config.Duration(10 * time.Minute),
+	})
+	if b.connectTimeout != 5*time.Second || b.timeout != 10*time.Minute {
+		t.Errorf("timeouts = %v/%v, want 5s/10m", b.connectTimeout, b.timeout)
+	}
+}
+
+func TestLLMBuilderFromConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hector.yaml")
+	yaml := `llms:
+  default:
+    provider: openai
+    model: gpt-4o-mini
+    api_key: sk-test
+  smart:
+    provider: anthropic
+    model: claude-sonnet-4-20250514
+    api_key: sk-test
+`
+	if err := os.WriteFile(path, []byte(yaml), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	b := NewLLM("openai").FromConfigFile(path, "smart").MaxTokens(100)
+	if b.err != nil {
+		t.Fatalf("FromConfigFile() error = %v", b.err)
+	}
+	if b.providerType != "anthropic" || b.model != "claude-sonnet-4-20250514" || b.maxTokens != 100 {
+		t.Errorf("builder = %s/%s/%d", b.providerType, b.model, b.maxTokens)
+	}
+
+	if _, err := NewLLM("openai").FromConfigFile(path, "").Build(); err == nil {
+		t.Error("Expected error when name is empty and several llms are defined")
+	}
+}
@@ -39,6 +39,8 @@ type WorkingMemoryBuilder struct {
 	threshold      float64
 	target         float64
 	preserveRecent int
+	keepLastTurns  int
+	maxTokens      int
 	modelName      string // For token counting
 	llm            model.LLM
 }
@@ -49,6 +51,7 @@ type WorkingMemoryBuilder struct {
 //   - "buffer_window": Simple sliding window of recent messages
 //   - "token_window": Token-based window management
 //   - "summary_buffer": Summarization-based memory (requires LLM)
+//   - "summary_window": Recent turns plus a rolling summary (requires LLM)
 //
 // Example:
 //
@@ -63,10 +66,12 @@ func NewWorkingMemory(strategyType string) *WorkingMemoryBuilder {
 		threshold:      0.85,
 		target:         0.6,
 		preserveRecent: 5,
+		keepLastTurns:  4,
+		maxTokens:      8000,
 	}
 }
 
-// ModelName sets the model name for token counting (used by token_window and summary_buffer).
+// ModelName sets the model name for token counting (used by token_window, summary_buffer and summary_window).
 //
 // Example:
 //
@@ -142,7 +147,33 @@ func (b *WorkingMemoryBuilder) Target(target float64) *WorkingMemoryBuilder {
 	return b
 }
 
-// WithLLM sets the LLM for summarization (required for summary_buffer).
+// KeepLastTurns sets the number of recent turns kept verbatim (summary_window only).
+//
+// Example:
+//
+//	builder.NewWorkingMemory("summary_window").KeepLastTurns(4)
+func (b *WorkingMemoryBuilder) KeepLastTurns(turns int) *WorkingMemoryBuilder {
+	if turns <= 0 {
+		panic("keep last turns must be positive")
+	}
+	b.keepLastTurns = turns
+	return b
+}
+
+// MaxTokens sets the hard token cap for summary_window strategy.
+//
+// Example:
+//
+//	builder.NewWorkingMemory("summary_window").MaxTokens(8000)
+func (b *WorkingMemoryBuilder) MaxTokens(tokens int) *WorkingMemoryBuilder {
+	if tokens <= 0 {
+		panic("max tokens must be positive")
+	}
+	b.maxTokens = tokens
+	return b
+}
+
+// WithLLM sets the LLM for summarization (required for summary_buffer and summary_window).
 //
 // Example:
 //
@@ -191,8 +222,29 @@ func (b *WorkingMemoryBuilder) Build() (memory.WorkingMemoryStrategy, error) {
 			Summarizer: summarizer,
 		})
 
+	case "summary_window":
+		if b.llm == nil {
+			return nil, fmt.Errorf("LLM is required for summary_window strategy")
+		}
+		summarizer, err := memory.NewLLMSummarizer(memory.LLMSummarizerConfig{
+			LLM: b.llm,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create summarizer: %w", err)
+		}
+		modelName := b.modelName
+		if modelName == "" {
+			modelName = b.llm.Name()
+		}
+		return memory.NewSummaryWindowStrategy(memory.SummaryWindowConfig{
+			KeepLastTurns: b.keepLastTurns,
+			MaxTokens:     b.maxTokens,
+			Model:         modelName,
+			Summarizer:    summarizer,
+		})
+
 	default:
-		return nil, fmt.Errorf("unknown working memory strategy: %s (supported: buffer_window, token_window, summary_buffer)", b.strategyType)
+		return nil, fmt.Errorf("unknown working memory strategy: %s (supported: buffer_window, token_window, summary_buffer, summary_window)", b.strategyType)
 	}
 }
 
@@ -234,6 +286,12 @@ func WorkingMemoryFromConfig(cfg *config.ContextConfig) *WorkingMemoryBuilder {
 	if cfg.PreserveRecent > 0 {
 		b.preserveRecent = cfg.PreserveRecent
 	}
+	if cfg.KeepLastTurns > 0 {
+		b.keepLastTurns = cfg.KeepLastTurns
+	}
+	if cfg.MaxTokens > 0 {
+		b.maxTokens = cfg.MaxTokens
+	}
 
 	return b
 }
@@ -56,8 +56,11 @@ type DocumentStoreBuilder struct {
 	embedder       embedder.Embedder
 
 	// Chunking options
-	chunkSize    int
-	chunkOverlap int
+	chunkStrategy     rag.ChunkerStrategy
+	chunkSize         int
+	chunkOverlap      int
+	maxChunkSize      int
+	semanticThreshold float64
 
 	// Index options
 	watchEnabled        bool
@@ -72,6 +75,10 @@ type DocumentStoreBuilder struct {
 	enableHyDE       bool
 	enableRerank     bool
 	enableMultiQuery bool
+	reranker         rag.Reranker
+	rerankTopN       int
+	searchMode       string
+	keywordWeight    float64
 }
 
 // NewDocumentStore creates a new document store builder.
@@ -90,6 +97,7 @@ func NewDocumentStore(name string) *DocumentStoreBuilder {
 	return &DocumentStoreBuilder{
 		name:                name,
 		collection:          name,
+		chunkStrategy:       rag.ChunkerSimple,
 		chunkSize:           512,
 		chunkOverlap:        50,
 		incrementalIndexing: true,
@@ -194,6 +202,53 @@ func (b *DocumentStoreBuilder) WithEmbedder(emb embedder.Embedder) *DocumentStor
 	return b
 }
 
+// ChunkStrategy sets how documents are split: "simple" (default),
+// "overlapping" or "semantic".
+//
+// The semantic strategy uses the store's embedder to split where the
+// topic changes.
+//
+// Example:
+//
+//	builder.NewDocumentStore("docs").ChunkStrategy("semantic")
+func (b *DocumentStoreBuilder) ChunkStrategy(strategy string) *DocumentStoreBuilder {
+	switch s := rag.ChunkerStrategy(strategy); s {
+	case rag.ChunkerSimple, rag.ChunkerOverlapping, rag.ChunkerSemantic:
+		b.chunkStrategy = s
+	default:
+		panic("chunk strategy must be simple, overlapping or semantic")
+	}
+	return b
+}
+
+// SemanticThreshold sets the similarity between adjacent sentences below
+// which the semantic strategy starts a new chunk. Default is 0.75.
+//
+// Example:
+//
+//	builder.NewDocumentStore("docs").ChunkStrategy("semantic").SemanticThreshold(0.6)
+func (b *DocumentStoreBuilder) SemanticThreshold(threshold float64) *DocumentStoreBuilder {
+	if threshold <= 0 || threshold > 1 {
+		panic("semantic threshold must be between 0 and 1")
+	}
+	b.semanticThreshold = threshold
+	return b
+}
+
+// MaxChunkSize sets the hard limit on chunk size (in characters).
+// Default is 2000.
+//
+// Example:
+//
+//	builder.NewDocumentStore("docs").ChunkStrategy("semantic").MaxChunkSize(1500)
+func (b *DocumentStoreBuilder) MaxChunkSize(size int) *DocumentStoreBuilder {
+	if size <= 0 {
+		panic("max chunk size must be positive")
+	}
+	b.maxChunkSize = size
+	return b
+}
+
 // ChunkSize sets the size of document chunks.
 //
 // Example:
@@ -320,6 +375,38 @@ func (b *DocumentStoreBuilder) EnableRerank(enabled bool) *DocumentStoreBuilder
 	return b
 }
 
+// WithReranker sets the reranker that reorders search candidates before
+// the top-k cut, and enables reranking.
+//
+// Example:
+//
+//	reranker, _ := rag.NewCrossEncoderReranker(rag.CrossEncoderConfig{
+//	    Endpoint: "http://localhost:8080/rerank",
+//	})
+//	builder.NewDocumentStore("docs").WithReranker(reranker).RerankTopN(30)
+func (b *DocumentStoreBuilder) WithReranker(reranker rag.Reranker) *DocumentStoreBuilder {
+	if reranker == nil {
+		panic("reranker cannot be nil")
+	}
+	b.reranker = reranker
+	b.enableRerank = true
+	return b
+}
+
+// RerankTopN sets how many candidates are fetched and reranked.
+// Default is 3x top-k, at most 100.
+//
+// Example:
+//
+//	builder.NewDocumentStore("docs").WithReranker(reranker).RerankTopN(30)
+func (b *DocumentStoreBuilder) RerankTopN(n int) *DocumentStoreBuilder {
+	if n <= 0 {
+		panic("rerank top n must be positive")
+	}
+	b.rerankTopN = n
+	return b
+}
+
 // EnableMultiQuery enables query expansion for better recall.
 //
 // Example:
@@ -330,6 +417,35 @@ func (b *DocumentStoreBuilder) EnableMultiQuery(enabled bool) *DocumentStoreBuil
 	return b
 }
 
+// SearchMode sets how chunks are ranked: "vector" (default), "keyword"
+// or "hybrid".
+//
+// Example:
+//
+//	builder.NewDocumentStore("docs").SearchMode("hybrid")
+func (b *DocumentStoreBuilder) SearchMode(mode string) *DocumentStoreBuilder {
+	switch mode {
+	case rag.SearchModeVector, rag.SearchModeKeyword, rag.SearchModeHybrid:
+	default:
+		panic("search mode must be vector, keyword or hybrid")
+	}
+	b.searchMode = mode
+	return b
+}
+
+// KeywordWeight sets the keyword ranking's share in hybrid search.
+//
+// Example:
+//
+//	builder.NewDocumentStore("docs").SearchMode("hybrid").KeywordWeight(0.3)
+func (b *DocumentStoreBuilder) KeywordWeight(weight float64) *DocumentStoreBuilder {
+	if weight < 0 || weight > 1 {
+		panic("keyword weight must be between 0 and 1")
+	}
+	b.keywordWeight = weight
+	return b
+}
+
 // Build creates the document store.
 //
 // Returns an error if required parameters are missing.
@@ -344,6 +460,9 @@ func (b *DocumentStoreBuilder) Build() (*rag.DocumentStore, error) {
 	if b.sourceType == "" || b.sourcePath == "" {
 		return nil, fmt.Errorf("source is required: use FromDirectory()")
 	}
+	if b.maxChunkSize > 0 && b.maxChunkSize < b.chunkSize {
+		return nil, fmt.Errorf("max chunk size (%d) must be at least chunk size (%d)", b.maxChunkSize, b.chunkSize)
+	}
 
 	// Create source
 	var source rag.DataSource
@@ -365,10 +484,23 @@ func (b *DocumentStoreBuilder) Build() (*rag.DocumentStore, error) {
 	}
 
 	// Create chunker
-	chunker := rag.NewSimpleChunker(rag.ChunkerConfig{
-		Size:    b.chunkSize,
-		Overlap: b.chunkOverlap,
-	})
+	chunkerCfg := rag.ChunkerConfig{
+		Strategy:  b.chunkStrategy,
+		Size:      b.chunkSize,
+		Overlap:   b.chunkOverlap,
+		MaxSize:   b.maxChunkSize,
+		Threshold: b.semanticThreshold,
+		Embedder:  b.embedder,
+	}
+	var chunker rag.Chunker
+	switch b.chunkStrategy {
+	case rag.ChunkerOverlapping:
+		chunker = rag.NewOverlappingChunker(chunkerCfg)
+	case rag.ChunkerSemantic:
+		chunker = rag.NewSemanticChunker(chunkerCfg)
+	default:
+		chunker = rag.NewSimpleChunker(chunkerCfg)
+	}
 
 	// Create search engine
 	engineCfg := rag.SearchEngineConfig{
@@ -378,6 +510,10 @@ func (b *DocumentStoreBuilder) Build() (*rag.DocumentStore, error) {
 		Collection:       b.collection,
 		DefaultTopK:      b.defaultTopK,
 		DefaultThreshold: b.defaultThreshold,
+		Reranker:         b.reranker,
+		RerankTopN:       b.rerankTopN,
+		SearchMode:       b.searchMode,
+		KeywordWeight:    b.keywordWeight,
 	}
 	engine, err := rag.NewSearchEngine(engineCfg)
 	if err != nil {
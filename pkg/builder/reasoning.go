@@ -34,6 +34,8 @@ type ReasoningBuilder struct {
 	enableExitTool        bool
 	enableEscalateTool    bool
 	completionInstruction string
+	retryOnEmpty          bool
+	emptyRetryInstruction string
 }
 
 // NewReasoning creates a new reasoning configuration builder.
@@ -99,6 +101,18 @@ func (b *ReasoningBuilder) CompletionInstruction(instruction string) *ReasoningB
 	return b
 }
 
+// RetryOnEmpty retries the model call once when it returns no text and no
+// tool calls. A non-empty instruction is sent with the retry as a nudge.
+//
+// Example:
+//
+//	builder.NewReasoning().RetryOnEmpty(true, "Please answer the question.")
+func (b *ReasoningBuilder) RetryOnEmpty(enable bool, instruction string) *ReasoningBuilder {
+	b.retryOnEmpty = enable
+	b.emptyRetryInstruction = instruction
+	return b
+}
+
 // Build creates the reasoning configuration.
 func (b *ReasoningBuilder) Build() *llmagent.ReasoningConfig {
 	return &llmagent.ReasoningConfig{
@@ -106,5 +120,7 @@ func (b *ReasoningBuilder) Build() *llmagent.ReasoningConfig {
 		EnableExitTool:        b.enableExitTool,
 		EnableEscalateTool:    b.enableEscalateTool,
 		CompletionInstruction: b.completionInstruction,
+		RetryOnEmpty:          b.retryOnEmpty,
+		EmptyRetryInstruction: b.emptyRetryInstruction,
 	}
 }
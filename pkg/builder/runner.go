@@ -43,6 +43,7 @@ type RunnerBuilder struct {
 	sessionService    session.Service
 	indexService      runner.IndexService
 	checkpointManager runner.CheckpointManager
+	sessionIDGen      func() string
 }
 
 // NewRunner creates a new runner builder.
@@ -117,6 +118,17 @@ func (b *RunnerBuilder) WithCheckpointManager(mgr runner.CheckpointManager) *Run
 	return b
 }
 
+// WithSessionIDGenerator sets the function that generates IDs for new
+// sessions when the caller does not supply one.
+//
+// Example:
+//
+//	builder.NewRunner("app").WithSessionIDGenerator(func() string { return "sess-" + uuid.NewString() })
+func (b *RunnerBuilder) WithSessionIDGenerator(gen func() string) *RunnerBuilder {
+	b.sessionIDGen = gen
+	return b
+}
+
 // Build creates the runner.
 //
 // Returns an error if required parameters are missing.
@@ -132,11 +144,12 @@ func (b *RunnerBuilder) Build() (*runner.Runner, error) {
 	}
 
 	return runner.New(runner.Config{
-		AppName:           b.appName,
-		Agent:             b.agent,
-		SessionService:    sessionSvc,
-		IndexService:      b.indexService,
-		CheckpointManager: b.checkpointManager,
+		AppName:            b.appName,
+		Agent:              b.agent,
+		SessionService:     sessionSvc,
+		IndexService:       b.indexService,
+		CheckpointManager:  b.checkpointManager,
+		SessionIDGenerator: b.sessionIDGen,
 	})
 }
 
@@ -42,7 +42,10 @@ type MCPBuilder struct {
 	args      []string
 	transport string
 	filter    []string
+	exclude   []string
+	prefix    string
 	env       map[string]string
+	grpc      *mcptoolset.GRPCConfig
 }
 
 // NewMCP creates a new MCP toolset builder.
@@ -58,6 +61,11 @@ type MCPBuilder struct {
 //	toolset, _ := builder.NewMCP("filesystem").
 //	    Command("npx", "-y", "@modelcontextprotocol/server-filesystem", "/tmp").
 //	    Build()
+//
+//	// gRPC transport
+//	toolset, _ := builder.NewMCP("search").
+//	    GRPC("search-tools:50051", mcptoolset.WithTLS(&httpclient.TLSConfig{})).
+//	    Build()
 func NewMCP(name string) *MCPBuilder {
 	if name == "" {
 		panic("MCP toolset name cannot be empty")
@@ -90,7 +98,22 @@ func (b *MCPBuilder) Command(cmd string, args ...string) *MCPBuilder {
 	return b
 }
 
-// Transport sets the transport type: "sse", "stdio", or "streamable-http".
+// GRPC sets the address of an MCP server exposed over gRPC. The connection
+// is opened on first use and kept for later calls.
+//
+// Example:
+//
+//	builder.NewMCP("search").GRPC("search-tools:50051",
+//	    mcptoolset.WithTLS(&httpclient.TLSConfig{CACertificate: "/etc/ssl/ca.pem"}),
+//	    mcptoolset.WithMetadata(map[string]string{"authorization": "Bearer " + token}),
+//	)
+func (b *MCPBuilder) GRPC(address string, opts ...mcptoolset.GRPCOption) *MCPBuilder {
+	b.grpc = mcptoolset.NewGRPCConfig(address, opts...)
+	b.transport = "grpc"
+	return b
+}
+
+// Transport sets the transport type: "sse", "stdio", "streamable-http" or "grpc".
 //
 // Example:
 //
@@ -101,12 +124,45 @@ func (b *MCPBuilder) Transport(transport string) *MCPBuilder {
 }
 
 // Filter limits which tools from the MCP server are exposed.
+// It is the same as IncludeTools.
 //
 // Example:
 //
 //	builder.NewMCP("weather").Filter("get_weather", "get_forecast")
 func (b *MCPBuilder) Filter(tools ...string) *MCPBuilder {
-	b.filter = tools
+	return b.IncludeTools(tools...)
+}
+
+// IncludeTools limits which tools from the MCP server are exposed, by their
+// names on the server. Other tools are never shown to the LLM.
+//
+// Example:
+//
+//	builder.NewMCP("fs").IncludeTools("read_file", "list_directory")
+func (b *MCPBuilder) IncludeTools(names ...string) *MCPBuilder {
+	b.filter = names
+	return b
+}
+
+// ExcludeTools hides tools of the MCP server, by their names on the server.
+// It applies after IncludeTools.
+//
+// Example:
+//
+//	builder.NewMCP("fs").ExcludeTools("write_file", "delete_file")
+func (b *MCPBuilder) ExcludeTools(names ...string) *MCPBuilder {
+	b.exclude = names
+	return b
+}
+
+// Prefix namespaces the exposed tools, so they don't clash with other tools.
+// Calls are dispatched to the server under the original names.
+//
+// Example:
+//
+//	builder.NewMCP("fs").Prefix("fs_") // read_file is exposed as fs_read_file
+func (b *MCPBuilder) Prefix(prefix string) *MCPBuilder {
+	b.prefix = prefix
 	return b
 }
 
@@ -125,8 +181,10 @@ func (b *MCPBuilder) Env(env map[string]string) *MCPBuilder {
 // Returns an error if required parameters are missing.
 func (b *MCPBuilder) Build() (*mcptoolset.Toolset, error) {
 	cfg := mcptoolset.Config{
-		Name:   b.name,
-		Filter: b.filter,
+		Name:    b.name,
+		Filter:  b.filter,
+		Exclude: b.exclude,
+		Prefix:  b.prefix,
 	}
 
 	switch b.transport {
@@ -146,8 +204,15 @@ func (b *MCPBuilder) Build() (*mcptoolset.Toolset, error) {
 		cfg.Env = b.env
 		cfg.Transport = "stdio"
 
+	case "grpc":
+		if b.grpc == nil || b.grpc.Address == "" {
+			return nil, fmt.Errorf("address is required for grpc transport")
+		}
+		cfg.GRPC = b.grpc
+		cfg.Transport = "grpc"
+
 	default:
-		return nil, fmt.Errorf("unknown transport: %s (supported: sse, stdio, streamable-http)", b.transport)
+		return nil, fmt.Errorf("unknown transport: %s (supported: sse, stdio, streamable-http, grpc)", b.transport)
 	}
 
 	return mcptoolset.New(cfg)
@@ -182,6 +247,8 @@ func MCPFromConfig(name string, cfg *config.ToolConfig) *MCPBuilder {
 	b.args = cfg.Args
 	b.env = cfg.Env
 	b.filter = cfg.Filter
+	b.exclude = cfg.Exclude
+	b.prefix = cfg.Prefix
 
 	if cfg.Transport != "" {
 		b.transport = cfg.Transport
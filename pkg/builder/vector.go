@@ -39,10 +39,12 @@ type VectorProviderBuilder struct {
 	compress    bool
 
 	// Qdrant options
-	qdrantHost   string
-	qdrantPort   int
-	qdrantAPIKey string
-	qdrantUseTLS bool
+	qdrantHost     string
+	qdrantPort     int
+	qdrantAPIKey   string
+	qdrantUseTLS   bool
+	qdrantProtocol string
+	qdrantDistance string
 
 	// Chroma options
 	chromaHost   string
@@ -81,7 +83,7 @@ type VectorProviderBuilder struct {
 //	// Cloud provider (Qdrant)
 //	provider, err := builder.NewVectorProvider("qdrant").
 //	    Host("localhost").
-//	    Port(6333).
+//	    Protocol("rest").
 //	    Build()
 func NewVectorProvider(providerType string) *VectorProviderBuilder {
 	b := &VectorProviderBuilder{
@@ -95,7 +97,6 @@ func NewVectorProvider(providerType string) *VectorProviderBuilder {
 		b.compress = true
 	case "qdrant":
 		b.qdrantHost = "localhost"
-		b.qdrantPort = 6333
 	case "chroma":
 		b.chromaHost = "localhost"
 		b.chromaPort = 8000
@@ -147,7 +148,7 @@ func (b *VectorProviderBuilder) Host(host string) *VectorProviderBuilder {
 //
 // Example:
 //
-//	builder.NewVectorProvider("qdrant").Port(6333)
+//	builder.NewVectorProvider("qdrant").Port(6334)
 func (b *VectorProviderBuilder) Port(port int) *VectorProviderBuilder {
 	if port <= 0 {
 		panic("port must be positive")
@@ -186,6 +187,28 @@ func (b *VectorProviderBuilder) UseTLS(useTLS bool) *VectorProviderBuilder {
 	return b
 }
 
+// Protocol selects the Qdrant API: "grpc" (default, port 6334) or "rest"
+// (port 6333).
+//
+// Example:
+//
+//	builder.NewVectorProvider("qdrant").Protocol("rest")
+func (b *VectorProviderBuilder) Protocol(protocol string) *VectorProviderBuilder {
+	b.qdrantProtocol = protocol
+	return b
+}
+
+// Distance sets the distance metric for new Qdrant collections:
+// "cosine" (default), "dot", "euclid" or "manhattan".
+//
+// Example:
+//
+//	builder.NewVectorProvider("qdrant").Distance("dot")
+func (b *VectorProviderBuilder) Distance(distance string) *VectorProviderBuilder {
+	b.qdrantDistance = distance
+	return b
+}
+
 // IndexName sets the index name (Pinecone).
 //
 // Example:
@@ -209,10 +232,12 @@ func (b *VectorProviderBuilder) Build() (vector.Provider, error) {
 
 	case "qdrant":
 		return vector.NewQdrantProvider(vector.QdrantConfig{
-			Host:   b.qdrantHost,
-			Port:   b.qdrantPort,
-			APIKey: b.qdrantAPIKey,
-			UseTLS: b.qdrantUseTLS,
+			Host:     b.qdrantHost,
+			Port:     b.qdrantPort,
+			APIKey:   b.qdrantAPIKey,
+			UseTLS:   b.qdrantUseTLS,
+			Protocol: b.qdrantProtocol,
+			Distance: b.qdrantDistance,
 		})
 
 	case "chroma":
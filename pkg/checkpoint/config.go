@@ -32,6 +32,11 @@ const (
 
 	// StrategyHybrid - Both event and interval checkpointing.
 	StrategyHybrid Strategy = "hybrid"
+
+	// StrategyOnError - Checkpoint before each tool call, after each
+	// successful one, and whenever a tool fails, so recovery can replay
+	// completed tool results instead of re-running them.
+	StrategyOnError Strategy = "on_error"
 )
 
 // Config configures checkpoint behavior.
@@ -54,7 +59,7 @@ type Config struct {
 	Enabled *bool `yaml:"enabled,omitempty"`
 
 	// Strategy determines when checkpoints are created.
-	// Values: "event", "interval", "hybrid"
+	// Values: "event", "interval", "hybrid", "on_error"
 	// Default: "event"
 	Strategy Strategy `yaml:"strategy,omitempty"`
 
@@ -135,8 +140,9 @@ func (c *Config) Validate() error {
 	if c.Strategy != "" &&
 		c.Strategy != StrategyEvent &&
 		c.Strategy != StrategyInterval &&
-		c.Strategy != StrategyHybrid {
-		return fmt.Errorf("invalid checkpoint strategy '%s' (valid: event, interval, hybrid)", c.Strategy)
+		c.Strategy != StrategyHybrid &&
+		c.Strategy != StrategyOnError {
+		return fmt.Errorf("invalid checkpoint strategy '%s' (valid: event, interval, hybrid, on_error)", c.Strategy)
 	}
 	if c.Interval < 0 {
 		return fmt.Errorf("checkpoint interval must be non-negative")
@@ -172,6 +178,12 @@ func (c *Config) ShouldCheckpointBeforeLLM() bool {
 	return c.IsEnabled() && c.BeforeLLM != nil && *c.BeforeLLM
 }
 
+// ShouldCheckpointOnError returns whether tool calls are checkpointed for
+// error recovery.
+func (c *Config) ShouldCheckpointOnError() bool {
+	return c.IsEnabled() && c.Strategy == StrategyOnError
+}
+
 // ShouldCheckpointInterval returns whether interval checkpointing is enabled.
 func (c *Config) ShouldCheckpointInterval() bool {
 	return c.IsEnabled() &&
@@ -18,6 +18,7 @@ package checkpoint
 import (
 	"context"
 	"log/slog"
+	"sync"
 
 	"github.com/kadirpekel/hector/pkg/session"
 )
@@ -32,6 +33,47 @@ type Manager struct {
 	config   *Config
 	storage  *Storage
 	recovery *RecoveryManager
+
+	// toolLocks serializes on_error checkpoint updates of parallel tool
+	// calls within one invocation
+	toolLocks keyedMutex
+}
+
+// keyedMutex hands out one mutex per key, dropping it once no caller
+// holds or waits on it.
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyedLock
+}
+
+type keyedLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// Lock locks the mutex of key and returns the function that unlocks it.
+func (k *keyedMutex) Lock(key string) func() {
+	k.mu.Lock()
+	if k.locks == nil {
+		k.locks = make(map[string]*keyedLock)
+	}
+	l, ok := k.locks[key]
+	if !ok {
+		l = &keyedLock{}
+		k.locks[key] = l
+	}
+	l.refs++
+	k.mu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+		k.mu.Lock()
+		if l.refs--; l.refs == 0 {
+			delete(k.locks, key)
+		}
+		k.mu.Unlock()
+	}
 }
 
 // NewManager creates a new checkpoint Manager.
@@ -53,7 +95,7 @@ func NewManager(cfg *Config, sessionService session.Service) *Manager {
 
 // IsEnabled returns whether checkpointing is enabled.
 func (m *Manager) IsEnabled() bool {
-	return m.config.IsEnabled()
+	return m != nil && m.config.IsEnabled()
 }
 
 // SetResumeCallback sets the callback for resuming tasks.
@@ -114,6 +156,12 @@ func (m *Manager) ShouldCheckpointAfterTools() bool {
 	return m.config.ShouldCheckpointAfterTools()
 }
 
+// ShouldCheckpointOnError returns whether tool calls are checkpointed for
+// error recovery.
+func (m *Manager) ShouldCheckpointOnError() bool {
+	return m.config.ShouldCheckpointOnError()
+}
+
 // ShouldCheckpointBeforeLLM returns whether to checkpoint before LLM calls.
 func (m *Manager) ShouldCheckpointBeforeLLM() bool {
 	return m.config.ShouldCheckpointBeforeLLM()
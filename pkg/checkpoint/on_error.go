@@ -0,0 +1,224 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkpoint
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/tool"
+)
+
+// On-error checkpointing (StrategyOnError):
+//
+//	The checkpoint of an invocation is keyed by its invocation ID and
+//	updated around every tool call:
+//	  1. Before the tool runs   → Phase=tool_execution, PendingToolCall=call
+//	  2. After it succeeds      → Phase=post_tool, call appended to CompletedToolCalls
+//	  3. After it fails         → Phase=error, PendingToolCall=failed call
+//
+//	The runner clears the checkpoint when the invocation completes. After a
+//	crash, the recovery path resumes under WithReplay, so tool calls that
+//	match a completed call return its recorded result instead of running
+//	again. The resumed invocation's checkpoint supersedes the replayed one.
+
+// replayKey is the context key for the checkpoint being replayed.
+type replayKey struct{}
+
+// replay tracks which recorded tool calls a resumed run has consumed.
+type replay struct {
+	state *State
+
+	mu   sync.Mutex
+	used []bool
+}
+
+// WithReplay returns a context that replays the completed tool calls
+// recorded in state. Tool calls made under it that match a recorded call by
+// name and arguments return the recorded result instead of executing.
+func WithReplay(ctx context.Context, state *State) context.Context {
+	if state == nil || state.AgentState == nil || len(state.AgentState.CompletedToolCalls) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, replayKey{}, &replay{
+		state: state,
+		used:  make([]bool, len(state.AgentState.CompletedToolCalls)),
+	})
+}
+
+// replayFromContext returns the replay carried by ctx, or nil.
+func replayFromContext(ctx context.Context) *replay {
+	r, _ := ctx.Value(replayKey{}).(*replay)
+	return r
+}
+
+// take returns the recorded result of the first unconsumed completed call
+// with the given name and arguments.
+func (r *replay) take(name string, args map[string]any) (map[string]any, bool) {
+	key := argsKey(args)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, call := range r.state.AgentState.CompletedToolCalls {
+		if r.used[i] || call.Name != name || argsKey(call.Arguments) != key {
+			continue
+		}
+		r.used[i] = true
+		if result, ok := call.Result.(map[string]any); ok {
+			return result, true
+		}
+		return map[string]any{"result": call.Result}, true
+	}
+	return nil, false
+}
+
+// argsKey returns a canonical form of tool arguments for comparison.
+// Recorded arguments have been through JSON, so live ones must be too.
+func argsKey(args map[string]any) string {
+	if len(args) == 0 {
+		return "{}"
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// BeforeToolCallback returns a tool callback for the on_error strategy.
+// During recovery it returns recorded results of completed calls; otherwise
+// it checkpoints the invocation before the tool runs.
+func (m *Manager) BeforeToolCallback() func(tool.Context, tool.Tool, map[string]any) (map[string]any, error) {
+	return func(ctx tool.Context, t tool.Tool, args map[string]any) (map[string]any, error) {
+		if !m.config.ShouldCheckpointOnError() {
+			return nil, nil
+		}
+
+		if r := replayFromContext(ctx); r != nil {
+			if result, ok := r.take(t.Name(), args); ok {
+				slog.Info("Replaying tool result from checkpoint",
+					"task_id", r.state.TaskID,
+					"tool", t.Name())
+				return result, nil
+			}
+		}
+
+		m.updateToolCheckpoint(ctx, func(state *State) {
+			state.WithPhase(PhaseToolExecution).WithPendingToolCall(&PendingToolCall{
+				ID:               ctx.FunctionCallID(),
+				Name:             t.Name(),
+				Description:      t.Description(),
+				Arguments:        args,
+				RequiresApproval: t.RequiresApproval(),
+			})
+		})
+		return nil, nil
+	}
+}
+
+// AfterToolCallback returns a tool callback for the on_error strategy.
+// It records successful results in the checkpoint and captures the state
+// when a tool fails.
+func (m *Manager) AfterToolCallback() func(tool.Context, tool.Tool, map[string]any, map[string]any, error) (map[string]any, error) {
+	return func(ctx tool.Context, t tool.Tool, args, result map[string]any, err error) (map[string]any, error) {
+		if !m.config.ShouldCheckpointOnError() {
+			return nil, nil
+		}
+
+		m.updateToolCheckpoint(ctx, func(state *State) {
+			if err != nil {
+				// Keep the failed call pending so recovery retries it
+				state.WithError(err)
+				return
+			}
+			state.PendingToolCall = nil
+			state.Error = ""
+			state.WithPhase(PhasePostTool).WithType(TypeEvent)
+			state.AgentState.CompletedToolCalls = append(state.AgentState.CompletedToolCalls, &ToolCallSnapshot{
+				ID:        ctx.FunctionCallID(),
+				Name:      t.Name(),
+				Arguments: args,
+				Result:    result,
+				Completed: true,
+			})
+		})
+		return nil, nil
+	}
+}
+
+// updateToolCheckpoint loads the invocation's checkpoint, or starts one,
+// applies update and saves it. Failures are logged; checkpointing never
+// fails the tool call.
+func (m *Manager) updateToolCheckpoint(ctx tool.Context, update func(*State)) {
+	unlock := m.toolLocks.Lock(strings.Join([]string{ctx.AppName(), ctx.UserID(), ctx.SessionID(), ctx.InvocationID()}, "/"))
+	defer unlock()
+
+	var supersedes string
+	state, err := m.storage.Load(ctx, ctx.AppName(), ctx.UserID(), ctx.SessionID(), ctx.InvocationID())
+	if err != nil {
+		state = NewState(ctx.InvocationID(), ctx.SessionID(), ctx.UserID(), ctx.AppName(),
+			contentText(ctx.UserContent()), ctx.AgentName(), ctx.InvocationID())
+		state.AgentState = &AgentStateSnapshot{Branch: ctx.Branch()}
+
+		// A resumed run carries the replayed results forward
+		if r := replayFromContext(ctx); r != nil {
+			state.AgentState.CompletedToolCalls = append(state.AgentState.CompletedToolCalls, r.state.AgentState.CompletedToolCalls...)
+			supersedes = r.state.TaskID
+		}
+	}
+	if state.AgentState == nil {
+		state.AgentState = &AgentStateSnapshot{}
+	}
+
+	update(state)
+
+	if err := m.storage.Save(ctx, state); err != nil {
+		slog.Warn("Failed to save tool checkpoint",
+			"task_id", state.TaskID,
+			"phase", state.Phase,
+			"error", err)
+		return
+	}
+
+	if supersedes != "" && supersedes != state.TaskID {
+		if err := m.storage.Clear(ctx, ctx.AppName(), ctx.UserID(), ctx.SessionID(), supersedes); err != nil {
+			slog.Warn("Failed to clear superseded checkpoint",
+				"task_id", supersedes,
+				"error", err)
+		}
+	}
+}
+
+// contentText returns the text parts of content.
+func contentText(content *agent.Content) string {
+	if content == nil {
+		return ""
+	}
+	var text string
+	for _, part := range content.Parts {
+		if tp, ok := part.(a2a.TextPart); ok {
+			text += tp.Text
+		}
+	}
+	return text
+}
@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/session"
+)
+
+type fakeTool struct{ name string }
+
+func (t fakeTool) Name() string           { return t.name }
+func (t fakeTool) Description() string    { return t.name + " tool" }
+func (t fakeTool) IsLongRunning() bool    { return false }
+func (t fakeTool) RequiresApproval() bool { return false }
+
+type fakeToolContext struct {
+	agent.InvocationContext
+	callID string
+}
+
+func (c *fakeToolContext) FunctionCallID() string       { return c.callID }
+func (c *fakeToolContext) Actions() *agent.EventActions { return &agent.EventActions{} }
+func (c *fakeToolContext) SearchMemory(context.Context, string) (*agent.MemorySearchResponse, error) {
+	return nil, nil
+}
+
+func newOnErrorManager(t *testing.T) (*Manager, session.Session) {
+	t.Helper()
+	sessions := session.InMemoryService()
+	resp, err := sessions.Create(context.Background(), &session.CreateRequest{
+		AppName:   "app",
+		UserID:    "user",
+		SessionID: "sess",
+		State:     make(map[string]any),
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	enabled := true
+	cfg := &Config{Enabled: &enabled, Strategy: StrategyOnError}
+	cfg.SetDefaults()
+	return NewManager(cfg, sessions), resp.Session
+}
+
+func TestOnErrorCheckpointsToolCalls(t *testing.T) {
+	mgr, sess := newOnErrorManager(t)
+	before, after := mgr.BeforeToolCallback(), mgr.AfterToolCallback()
+
+	inv := agent.NewInvocationContext(context.Background(), agent.InvocationContextParams{
+		Session:     sess,
+		UserContent: agent.NewTextContent("crawl the docs", "user"),
+	})
+	search := fakeTool{name: "search"}
+	fetch := fakeTool{name: "fetch"}
+	searchArgs := map[string]any{"query": "checkpoints", "limit": 5}
+
+	ctx := &fakeToolContext{InvocationContext: inv, callID: "call-1"}
+	if result, _ := before(ctx, search, searchArgs); result != nil {
+		t.Fatalf("before() result = %v, want nil", result)
+	}
+	state, err := mgr.LoadCheckpoint(ctx, "app", "user", "sess", inv.InvocationID())
+	if err != nil {
+		t.Fatalf("LoadCheckpoint() error = %v", err)
+	}
+	if state.Phase != PhaseToolExecution || state.PendingToolCall.Name != "search" {
+		t.Errorf("pre-tool checkpoint = %s/%v, want tool_execution/search", state.Phase, state.PendingToolCall)
+	}
+	if state.Query != "crawl the docs" {
+		t.Errorf("Query = %q", state.Query)
+	}
+	_, _ = after(ctx, search, searchArgs, map[string]any{"hits": 3}, nil)
+
+	ctx = &fakeToolContext{InvocationContext: inv, callID: "call-2"}
+	_, _ = before(ctx, fetch, map[string]any{"url": "x"})
+	_, _ = after(ctx, fetch, map[string]any{"url": "x"}, nil, errors.New("connection reset"))
+
+	state, err = mgr.LoadCheckpoint(ctx, "app", "user", "sess", inv.InvocationID())
+	if err != nil {
+		t.Fatalf("LoadCheckpoint() error = %v", err)
+	}
+	if state.Phase != PhaseError || state.CheckpointType != TypeError || state.Error != "connection reset" {
+		t.Errorf("error checkpoint = %s/%s/%q", state.Phase, state.CheckpointType, state.Error)
+	}
+	if state.PendingToolCall == nil || state.PendingToolCall.Name != "fetch" {
+		t.Errorf("PendingToolCall = %v, want the failed fetch", state.PendingToolCall)
+	}
+	if calls := state.AgentState.CompletedToolCalls; len(calls) != 1 || calls[0].Name != "search" {
+		t.Fatalf("CompletedToolCalls = %v, want [search]", calls)
+	}
+
+	// A resumed run replays the completed search and re-runs the failed fetch
+	resumed := agent.NewInvocationContext(WithReplay(context.Background(), state), agent.InvocationContextParams{
+		Session:     sess,
+		UserContent: agent.NewTextContent("crawl the docs", "user"),
+	})
+	ctx = &fakeToolContext{InvocationContext: resumed, callID: "call-3"}
+	result, _ := before(ctx, search, map[string]any{"limit": 5, "query": "checkpoints"})
+	if result == nil || result["hits"] != float64(3) {
+		t.Errorf("replayed result = %v, want recorded hits", result)
+	}
+	if result, _ := before(ctx, search, searchArgs); result != nil {
+		t.Errorf("second replay = %v, want nil (recorded call already consumed)", result)
+	}
+	if result, _ := before(ctx, fetch, map[string]any{"url": "x"}); result != nil {
+		t.Errorf("failed call replayed = %v, want nil", result)
+	}
+
+	// The resumed invocation's checkpoint carries the results and supersedes the old one
+	if _, err := mgr.LoadCheckpoint(ctx, "app", "user", "sess", inv.InvocationID()); err == nil {
+		t.Error("replayed checkpoint not cleared")
+	}
+	state, err = mgr.LoadCheckpoint(ctx, "app", "user", "sess", resumed.InvocationID())
+	if err != nil {
+		t.Fatalf("LoadCheckpoint() error = %v", err)
+	}
+	if len(state.AgentState.CompletedToolCalls) != 1 {
+		t.Errorf("CompletedToolCalls = %v, want carried-over search", state.AgentState.CompletedToolCalls)
+	}
+}
+
+func TestOnErrorCallbacksInactiveForOtherStrategies(t *testing.T) {
+	enabled := true
+	cfg := &Config{Enabled: &enabled, Strategy: StrategyHybrid}
+	cfg.SetDefaults()
+	mgr := NewManager(cfg, session.InMemoryService())
+
+	inv := agent.NewInvocationContext(context.Background(), agent.InvocationContextParams{})
+	ctx := &fakeToolContext{InvocationContext: inv, callID: "call-1"}
+	if result, err := mgr.BeforeToolCallback()(ctx, fakeTool{name: "search"}, nil); result != nil || err != nil {
+		t.Errorf("before() = %v, %v", result, err)
+	}
+	if result, err := mgr.AfterToolCallback()(ctx, fakeTool{name: "search"}, nil, nil, errors.New("boom")); result != nil || err != nil {
+		t.Errorf("after() = %v, %v", result, err)
+	}
+}
+
+func TestKeyedMutexLocksPerKey(t *testing.T) {
+	var locks keyedMutex
+
+	unlockA := locks.Lock("a")
+
+	// Another key is not blocked by a held lock
+	done := make(chan struct{})
+	go func() {
+		locks.Lock("b")()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("lock of another key blocked")
+	}
+
+	// The same key waits for the holder
+	acquired := make(chan struct{})
+	go func() {
+		unlock := locks.Lock("a")
+		close(acquired)
+		unlock()
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("lock of a held key did not wait")
+	case <-time.After(20 * time.Millisecond):
+	}
+	unlockA()
+	<-acquired
+
+	locks.mu.Lock()
+	defer locks.mu.Unlock()
+	if len(locks.locks) != 0 {
+		t.Errorf("released locks kept: %d", len(locks.locks))
+	}
+}
@@ -154,7 +154,7 @@ func (m *RecoveryManager) recoverCheckpoint(ctx context.Context, state *State) e
 
 	// Run resume in background
 	go func() {
-		if err := callback(ctx, state); err != nil {
+		if err := m.resume(ctx, callback, state); err != nil {
 			slog.Error("Failed to resume task from checkpoint",
 				"task_id", state.TaskID,
 				"error", err)
@@ -208,7 +208,26 @@ func (m *RecoveryManager) ResumeTask(ctx context.Context, appName, userID, sessi
 		}
 	}
 
-	return callback(ctx, state)
+	return m.resume(ctx, callback, state)
+}
+
+// resume runs callback for state. Under the on_error strategy the callback
+// runs with WithReplay, so completed tool calls are not executed again, and
+// the replayed checkpoint is cleared once the resumed run succeeds.
+func (m *RecoveryManager) resume(ctx context.Context, callback ResumeCallback, state *State) error {
+	if !m.config.ShouldCheckpointOnError() {
+		return callback(ctx, state)
+	}
+
+	if err := callback(WithReplay(ctx, state), state); err != nil {
+		return err
+	}
+	if err := m.storage.Clear(ctx, state.AppName, state.UserID, state.SessionID, state.TaskID); err != nil {
+		slog.Warn("Failed to clear replayed checkpoint",
+			"task_id", state.TaskID,
+			"error", err)
+	}
+	return nil
 }
 
 // GetPendingCheckpoints returns all pending checkpoints for a user.
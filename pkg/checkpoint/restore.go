@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ErrCheckpointNotFound is returned when a session has no checkpoint with
+// the requested ID.
+var ErrCheckpointNotFound = errors.New("checkpoint not found")
+
+// ErrRestoreUnsupported is returned when the session service cannot rewind
+// sessions (see session.Rewinder).
+var ErrRestoreUnsupported = errors.New("session service does not support restoring checkpoints")
+
+// CheckpointInfo summarizes a checkpoint for operators.
+type CheckpointInfo struct {
+	// ID identifies the checkpoint within its session (the task ID).
+	ID        string    `json:"id"`
+	SessionID string    `json:"session_id"`
+	AgentName string    `json:"agent_name,omitempty"`
+	Query     string    `json:"query,omitempty"`
+	Phase     Phase     `json:"phase"`
+	Type      Type      `json:"type"`
+	Time      time.Time `json:"time"`
+
+	// EventCount is the number of session events a restore keeps.
+	EventCount int `json:"event_count"`
+
+	// PendingTool is the tool awaiting execution or approval, if any.
+	PendingTool string `json:"pending_tool,omitempty"`
+
+	Error         string `json:"error,omitempty"`
+	NeedsApproval bool   `json:"needs_approval"`
+}
+
+// info summarizes the checkpoint.
+func (s *State) info() CheckpointInfo {
+	info := CheckpointInfo{
+		ID:            s.TaskID,
+		SessionID:     s.SessionID,
+		AgentName:     s.AgentName,
+		Query:         s.Query,
+		Phase:         s.Phase,
+		Type:          s.CheckpointType,
+		Time:          s.CheckpointTime,
+		EventCount:    s.EventCount,
+		Error:         s.Error,
+		NeedsApproval: s.NeedsUserInput(),
+	}
+	if s.PendingToolCall != nil {
+		info.PendingTool = s.PendingToolCall.Name
+	}
+	return info
+}
+
+// List returns the checkpoints of a session, newest first.
+func (m *Manager) List(ctx context.Context, appName, userID, sessionID string) ([]CheckpointInfo, error) {
+	states, err := m.storage.ListSession(ctx, appName, userID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]CheckpointInfo, 0, len(states))
+	for _, state := range states {
+		infos = append(infos, state.info())
+	}
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].Time.After(infos[j].Time)
+	})
+	return infos, nil
+}
+
+// Restore rolls a session back to a checkpoint: events after the checkpoint
+// are removed and session-scoped state is reset to its snapshot, atomically.
+// Checkpoints newer than the restored one are dropped; the restored
+// checkpoint stays so the task can be resumed from it.
+func (m *Manager) Restore(ctx context.Context, appName, userID, sessionID, checkpointID string) error {
+	states, err := m.storage.ListSession(ctx, appName, userID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	for _, state := range states {
+		if state.TaskID != checkpointID {
+			continue
+		}
+		if err := m.storage.Rewind(ctx, state); err != nil {
+			return fmt.Errorf("failed to restore checkpoint %s: %w", checkpointID, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrCheckpointNotFound, checkpointID)
+}
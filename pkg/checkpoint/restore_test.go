@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkpoint
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+)
+
+func TestListAndRestore(t *testing.T) {
+	ctx := context.Background()
+	mgr, sess := newOnErrorManager(t)
+
+	appendStep := func(step string) {
+		t.Helper()
+		_ = sess.State().Set("step", step)
+		if err := mgr.storage.sessionService.AppendEvent(ctx, sess, agent.NewEvent("inv")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	appendStep("one")
+	older := NewState("task-1", "sess", "user", "app", "crawl", "crawler", "inv-1").WithPhase(PhasePostTool)
+	if err := mgr.SaveCheckpoint(ctx, older); err != nil {
+		t.Fatal(err)
+	}
+
+	appendStep("two")
+	newer := NewState("task-2", "sess", "user", "app", "crawl", "crawler", "inv-2").
+		WithPhase(PhaseError).
+		WithPendingToolCall(&PendingToolCall{Name: "fetch"})
+	newer.CheckpointTime = older.CheckpointTime.Add(time.Second)
+	if err := mgr.SaveCheckpoint(ctx, newer); err != nil {
+		t.Fatal(err)
+	}
+	appendStep("three")
+
+	infos, err := mgr.List(ctx, "app", "user", "sess")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(infos) != 2 || infos[0].ID != "task-2" || infos[1].ID != "task-1" {
+		t.Fatalf("List() = %+v, want task-2 then task-1", infos)
+	}
+	if infos[0].PendingTool != "fetch" || infos[0].EventCount != 2 {
+		t.Errorf("newest info = %+v", infos[0])
+	}
+
+	if err := mgr.Restore(ctx, "app", "user", "sess", "task-1"); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if n := sess.Events().Len(); n != 1 {
+		t.Errorf("events after restore = %d, want 1", n)
+	}
+	if step, _ := sess.State().Get("step"); step != "one" {
+		t.Errorf("step after restore = %v, want one", step)
+	}
+
+	// The newer checkpoint no longer matches the session and is dropped
+	infos, err = mgr.List(ctx, "app", "user", "sess")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 1 || infos[0].ID != "task-1" {
+		t.Errorf("List() after restore = %+v, want only task-1", infos)
+	}
+
+	if err := mgr.Restore(ctx, "app", "user", "sess", "task-2"); !errors.Is(err, ErrCheckpointNotFound) {
+		t.Errorf("Restore(dropped) error = %v, want ErrCheckpointNotFound", err)
+	}
+}
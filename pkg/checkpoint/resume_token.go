@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package checkpoint
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// ResumeToken identifies a tool call paused for HITL approval.
+//
+// Tokens are handed to clients in the input-required event and sent back
+// with the approval decision to resume the task. They are opaque to clients
+// but not secret: they only carry identifiers, and resuming still goes
+// through the agent endpoint's normal authentication.
+type ResumeToken struct {
+	TaskID     string `json:"t"`
+	SessionID  string `json:"s"`
+	ToolCallID string `json:"c"`
+	ToolName   string `json:"n"`
+}
+
+// Encode returns the token's string form.
+func (t *ResumeToken) Encode() string {
+	data, _ := json.Marshal(t)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// ParseResumeToken decodes a token produced by Encode.
+func ParseResumeToken(s string) (*ResumeToken, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resume token: %w", err)
+	}
+
+	var token ResumeToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("invalid resume token: %w", err)
+	}
+	if token.TaskID == "" || token.ToolCallID == "" {
+		return nil, fmt.Errorf("invalid resume token: missing task or tool call")
+	}
+
+	return &token, nil
+}
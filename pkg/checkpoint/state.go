@@ -158,6 +158,10 @@ type State struct {
 	InvocationID   string              `json:"invocation_id"`
 	LastEventIndex int                 `json:"last_event_index"` // Index of last processed event
 
+	// Session snapshot, captured when the checkpoint is saved (for Restore)
+	EventCount   int            `json:"event_count"`
+	SessionState map[string]any `json:"session_state,omitempty"` // Session-scoped keys only
+
 	// Pending tool call (for HITL approval)
 	PendingToolCall *PendingToolCall `json:"pending_tool_call,omitempty"`
 
@@ -200,6 +204,7 @@ type AgentStateSnapshot struct {
 	// Tool execution tracking
 	PendingToolCalls        []*ToolCallSnapshot `json:"pending_tool_calls,omitempty"`
 	FirstIterationToolCalls []*ToolCallSnapshot `json:"first_iteration_tool_calls,omitempty"` // For agentic loop
+	CompletedToolCalls      []*ToolCallSnapshot `json:"completed_tool_calls,omitempty"`       // Replayed on recovery (on_error)
 
 	// Multi-agent context (from legacy SubAgents field)
 	SubAgents   []string `json:"sub_agents,omitempty"`   // Available sub-agents (for transfer)
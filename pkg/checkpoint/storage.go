@@ -20,7 +20,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"strings"
 
+	"github.com/kadirpekel/hector/pkg/agent"
 	"github.com/kadirpekel/hector/pkg/session"
 )
 
@@ -70,6 +72,10 @@ func (s *Storage) Save(ctx context.Context, state *State) error {
 		return fmt.Errorf("failed to get session for checkpoint: %w", err)
 	}
 
+	// Snapshot the session so the checkpoint can be restored
+	state.EventCount = sess.Events().Len()
+	state.SessionState = sessionScopedState(sess.State())
+
 	// Serialize checkpoint state
 	stateJSON, err := state.Serialize()
 	if err != nil {
@@ -158,6 +164,11 @@ func (s *Storage) Clear(ctx context.Context, appName, userID, sessionID, taskID
 		return err
 	}
 
+	// Nothing to remove
+	if _, exists := pendingMap[taskID]; !exists {
+		return nil
+	}
+
 	// Remove task checkpoint
 	delete(pendingMap, taskID)
 
@@ -275,6 +286,104 @@ func (s *Storage) ListAllPending(ctx context.Context, appName string) ([]*State,
 	return states, nil
 }
 
+// ListSession returns all checkpoints of a session.
+func (s *Storage) ListSession(ctx context.Context, appName, userID, sessionID string) ([]*State, error) {
+	sess, err := s.getSession(ctx, appName, userID, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	pendingMap, err := s.getPendingExecutions(sess)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]*State, 0, len(pendingMap))
+	for taskID, taskState := range pendingMap {
+		stateJSON, err := json.Marshal(taskState)
+		if err != nil {
+			continue
+		}
+		state, err := Deserialize(stateJSON)
+		if err != nil {
+			slog.Warn("Failed to deserialize checkpoint",
+				"task_id", taskID,
+				"session_id", sessionID,
+				"error", err)
+			continue
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// Rewind atomically resets a session's events and session-scoped state to
+// the snapshot in state. Checkpoints saved after state are dropped, since
+// they refer to events that no longer exist.
+func (s *Storage) Rewind(ctx context.Context, state *State) error {
+	rewinder, ok := s.sessionService.(session.Rewinder)
+	if !ok {
+		return ErrRestoreUnsupported
+	}
+
+	sess, err := s.getSession(ctx, state.AppName, state.UserID, state.SessionID)
+	if err != nil {
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+	pendingMap, err := s.getPendingExecutions(sess)
+	if err != nil {
+		return err
+	}
+
+	kept := make(map[string]any, len(pendingMap))
+	for taskID, taskState := range pendingMap {
+		stateJSON, err := json.Marshal(taskState)
+		if err != nil {
+			continue
+		}
+		other, err := Deserialize(stateJSON)
+		if err != nil || other.CheckpointTime.After(state.CheckpointTime) {
+			continue
+		}
+		kept[taskID] = taskState
+	}
+
+	restored := make(map[string]any, len(state.SessionState)+1)
+	for key, val := range state.SessionState {
+		restored[key] = val
+	}
+	if len(kept) > 0 {
+		restored[pendingExecutionsKey] = kept
+	}
+
+	return rewinder.Rewind(ctx, &session.RewindRequest{
+		AppName:   state.AppName,
+		UserID:    state.UserID,
+		SessionID: state.SessionID,
+		NumEvents: state.EventCount,
+		State:     restored,
+	})
+}
+
+// sessionScopedState copies the session-scoped keys of state, leaving out
+// shared (app:, user:), temporary and checkpoint keys.
+func sessionScopedState(state agent.State) map[string]any {
+	if state == nil {
+		return nil
+	}
+	snapshot := make(map[string]any)
+	for key, val := range state.All() {
+		if key == pendingExecutionsKey ||
+			strings.HasPrefix(key, session.KeyPrefixApp) ||
+			strings.HasPrefix(key, session.KeyPrefixUser) ||
+			strings.HasPrefix(key, session.KeyPrefixTemp) {
+			continue
+		}
+		snapshot[key] = val
+	}
+	return snapshot
+}
+
 // getSession retrieves a session by its identifiers.
 func (s *Storage) getSession(ctx context.Context, appName, userID, sessionID string) (session.Session, error) {
 	resp, err := s.sessionService.Get(ctx, &session.GetRequest{
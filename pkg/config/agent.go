@@ -15,7 +15,15 @@
 
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// slugPattern matches discovery tags and categories.
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
 
 // AgentConfig configures an agent.
 type AgentConfig struct {
@@ -32,6 +40,22 @@ type AgentConfig struct {
 	//   - "private": Hidden from discovery, NOT accessible via HTTP (internal calls only)
 	Visibility string `yaml:"visibility,omitempty" json:"visibility,omitempty" jsonschema:"title=Visibility,description=Controls agent discovery and access,enum=public,enum=internal,enum=private,default=public"`
 
+	// RequiredScopes lists scopes a caller must hold to discover or call the
+	// agent over HTTP. Scopes come from the JWT "scope"/"scp" claims or the
+	// API key's scopes. Requires server auth to be enabled.
+	RequiredScopes []string `yaml:"required_scopes,omitempty" json:"required_scopes,omitempty" jsonschema:"title=Required Scopes,description=Scopes a caller must hold to access the agent"`
+
+	// Tags label the agent for discovery filtering (e.g., "coding", "support").
+	// Tags are lowercase slugs: letters, digits and hyphens.
+	Tags []string `yaml:"tags,omitempty" json:"tags,omitempty" jsonschema:"title=Tags,description=Discovery tags (lowercase slugs)"`
+
+	// Category groups the agent in discovery (e.g., "engineering").
+	Category string `yaml:"category,omitempty" json:"category,omitempty" jsonschema:"title=Category,description=Discovery category (lowercase slug)"`
+
+	// CardHeaders are static response headers for this agent's card,
+	// merged over server.a2a.card_headers (e.g., Cache-Control: max-age=300).
+	CardHeaders map[string]string `yaml:"card_headers,omitempty" json:"card_headers,omitempty" jsonschema:"title=Card Headers,description=Response headers for the agent card endpoint"`
+
 	// LLM references a configured LLM by name.
 	LLM string `yaml:"llm,omitempty" json:"llm,omitempty" jsonschema:"title=LLM Reference,description=References a configured LLM by name,default=default"`
 
@@ -80,6 +104,13 @@ type AgentConfig struct {
 	// Supports the same template placeholders as Instruction.
 	GlobalInstruction string `yaml:"global_instruction,omitempty" json:"global_instruction,omitempty" jsonschema:"title=Global Instruction,description=Instruction applied to all agents in the tree"`
 
+	// PromptVersion records which instruction produced each response, in
+	// task and session metadata and on LLM call spans. "auto" numbers
+	// versions v1, v2, ... and moves to the next one whenever the instruction
+	// changes on reload; any other value is used as the version label.
+	// Empty disables prompt versioning.
+	PromptVersion string `yaml:"prompt_version,omitempty" json:"prompt_version,omitempty" jsonschema:"title=Prompt Version,description=Version label of the instruction recorded with responses (auto to count versions)"`
+
 	// Reasoning configures the chain-of-thought reasoning loop.
 	Reasoning *ReasoningConfig `yaml:"reasoning,omitempty" json:"reasoning,omitempty" jsonschema:"title=Reasoning Configuration,description=Chain-of-thought reasoning loop settings"`
 
@@ -87,16 +118,58 @@ type AgentConfig struct {
 	// Controls how conversation history is managed to fit within LLM limits.
 	Context *ContextConfig `yaml:"context,omitempty" json:"context,omitempty" jsonschema:"title=Context Configuration,description=Working memory and context window settings"`
 
+	// Memory configures memory that outlives a session.
+	//
+	// Example:
+	//   memory:
+	//     long_term:
+	//       enabled: true
+	//       extractor_llm: fast
+	//       max_facts: 50
+	Memory *AgentMemoryConfig `yaml:"memory,omitempty" json:"memory,omitempty" jsonschema:"title=Memory,description=Memory that persists across sessions"`
+
+	// Scope restricts the agent to a set of topics.
+	// Clearly off-topic requests are refused without calling the LLM.
+	//
+	// Example:
+	//   scope:
+	//     allowed_topics: [billing, invoices, payment methods, refunds]
+	//     refusal_message: "I can only help with billing questions."
+	//     threshold: 0.2
+	//     embedder: default  # optional, enables semantic matching
+	Scope *ScopeConfig `yaml:"scope,omitempty" json:"scope,omitempty" jsonschema:"title=Scope,description=Topic guardrail that refuses out-of-scope requests"`
+
+	// PromptBudget warns or fails when the system prompt (instruction,
+	// fragments and RAG context) takes too much of the context window.
+	//
+	// Example:
+	//   prompt_budget:
+	//     context_window: 128000
+	//     max_fraction: 0.25
+	//     action: error
+	PromptBudget *PromptBudgetConfig `yaml:"prompt_budget,omitempty" json:"prompt_budget,omitempty" jsonschema:"title=Prompt Budget,description=Guard against system prompts that crowd out the conversation"`
+
+	// ResponseLanguage makes the agent answer in a fixed language, with an
+	// optional check that retries responses written in another script.
+	//
+	// Example:
+	//   response_language:
+	//     language: ja
+	//     verify: true
+	ResponseLanguage *ResponseLanguageConfig `yaml:"response_language,omitempty" json:"response_language,omitempty" jsonschema:"title=Response Language,description=Language the agent must respond in"`
+
 	// Prompt provides detailed prompt configuration.
 	Prompt *PromptConfig `yaml:"prompt,omitempty" json:"prompt,omitempty" jsonschema:"title=Prompt Configuration,description=Detailed prompt configuration"`
 
 	// Skills describes agent capabilities for A2A discovery.
 	Skills []SkillConfig `yaml:"skills,omitempty" json:"skills,omitempty" jsonschema:"title=Skills,description=Agent capabilities for A2A discovery"`
 
-	// InputModes are supported input MIME types.
+	// InputModes are accepted input MIME types (e.g., "text/plain", "image/*").
+	// When set, messages with other content types are rejected. Empty accepts all.
 	InputModes []string `yaml:"input_modes,omitempty" json:"input_modes,omitempty" jsonschema:"title=Input Modes,description=Supported input MIME types"`
 
-	// OutputModes are supported output MIME types.
+	// OutputModes are produced output MIME types. When set, requests whose
+	// accepted output modes don't overlap are rejected. Empty accepts all.
 	OutputModes []string `yaml:"output_modes,omitempty" json:"output_modes,omitempty" jsonschema:"title=Output Modes,description=Supported output MIME types"`
 
 	// Streaming enables token-by-token streaming from the LLM.
@@ -163,13 +236,72 @@ type AgentConfig struct {
 	//   - "sequential": Runs sub-agents in sequence
 	//   - "parallel": Runs sub-agents in parallel
 	//   - "loop": Runs sub-agents repeatedly
+	//   - "conditional": Runs the sub-agent of the first matching case
+	//   - "map_reduce": Runs a mapper per list item, then a reducer
 	//   - "remote": Remote A2A agent
-	Type string `yaml:"type,omitempty" json:"type,omitempty" jsonschema:"title=Agent Type,description=Type of agent,enum=llm,enum=sequential,enum=parallel,enum=loop,enum=remote,default=llm"`
+	Type string `yaml:"type,omitempty" json:"type,omitempty" jsonschema:"title=Agent Type,description=Type of agent,enum=llm,enum=sequential,enum=parallel,enum=loop,enum=conditional,enum=map_reduce,enum=remote,default=llm"`
 
 	// MaxIterations is the maximum iterations for loop agents.
 	// Only used when Type="loop". If 0, loops until escalation.
 	MaxIterations uint `yaml:"max_iterations,omitempty" json:"max_iterations,omitempty" jsonschema:"title=Max Iterations,description=Maximum iterations for loop agents,minimum=0"`
 
+	// Cases route conditional agents: the agent of the first case whose
+	// when expression holds against session state is run.
+	// Only used when Type="conditional".
+	//
+	// Example:
+	//   cases:
+	//     - when: '{classification} == "billing"'
+	//       agent: billing
+	//     - when: '{classification} == "technical"'
+	//       agent: support
+	//   default: general
+	Cases []ConditionalCaseConfig `yaml:"cases,omitempty" json:"cases,omitempty" jsonschema:"title=Cases,description=Branches of a conditional agent"`
+
+	// Default is the agent a conditional agent runs when no case matches.
+	// If empty, nothing runs.
+	Default string `yaml:"default,omitempty" json:"default,omitempty" jsonschema:"title=Default Agent,description=Agent run when no case of a conditional agent matches"`
+
+	// Mapper is the agent a map-reduce agent runs once per item of the
+	// list in InputKey. Only used when Type="map_reduce".
+	//
+	// Example:
+	//   type: map_reduce
+	//   input_key: sections
+	//   output_key: summaries
+	//   mapper: section-summarizer
+	//   reducer: merger
+	//   max_concurrency: 4
+	Mapper string `yaml:"mapper,omitempty" json:"mapper,omitempty" jsonschema:"title=Mapper,description=Agent run once per item of a map-reduce agent"`
+
+	// Reducer is the agent a map-reduce agent runs over the mapper outputs.
+	// If empty, the outputs are only written to state.
+	Reducer string `yaml:"reducer,omitempty" json:"reducer,omitempty" jsonschema:"title=Reducer,description=Agent run over the mapper outputs of a map-reduce agent"`
+
+	// InputKey is the session state key holding the list a map-reduce
+	// agent maps over. The value may be a list or a JSON array string.
+	InputKey string `yaml:"input_key,omitempty" json:"input_key,omitempty" jsonschema:"title=Input Key,description=State key holding the items of a map-reduce agent"`
+
+	// OutputKey is the session state key the mapper outputs are written to.
+	// Default: "<agent name>_outputs"
+	OutputKey string `yaml:"output_key,omitempty" json:"output_key,omitempty" jsonschema:"title=Output Key,description=State key receiving the mapper outputs of a map-reduce agent"`
+
+	// MaxConcurrency bounds the mappers a map-reduce agent runs at once.
+	// 0 runs all of them at once.
+	MaxConcurrency int `yaml:"max_concurrency,omitempty" json:"max_concurrency,omitempty" jsonschema:"title=Max Concurrency,description=Maximum concurrent mappers of a map-reduce agent (0 = unbounded),minimum=0"`
+
+	// Retry re-runs failed sub-agents of workflow agents with backoff.
+	// For remote agents, it retries calls that fail with a status in retry_on
+	// or a network error, resuming interrupted streams.
+	// Only used when Type is "sequential", "parallel", "loop", "conditional",
+	// "map_reduce" or "remote".
+	//
+	// Example:
+	//   retry:
+	//     max_retries: 2
+	//     base_delay: 2s
+	Retry *AgentRetryConfig `yaml:"retry,omitempty" json:"retry,omitempty" jsonschema:"title=Retry,description=Retry failed sub-agents of workflow agents or failed remote agent calls"`
+
 	// === Remote Agent Configuration (Type="remote") ===
 
 	// URL is the base URL of the remote A2A server.
@@ -190,11 +322,157 @@ type AgentConfig struct {
 	// Example:
 	//   headers:
 	//     Authorization: "Bearer ${API_TOKEN}"
-	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty" jsonschema:"title=HTTP Headers,description=Custom headers for remote requests"`
+	Headers map[string]string `yaml:"headers,omitempty" json:"headers,omitempty" jsonschema:"title=HTTP Headers,description=Custom headers for remote requests" sensitive:"true"`
+
+	// Timeout bounds each run of the agent, e.g. "2m". A run that takes
+	// longer is cancelled and fails with a timeout error. For workflow agents
+	// it bounds the whole workflow; each sub-agent step is bounded by the
+	// sub-agent's own timeout. For remote agents it is also the request
+	// timeout, which defaults to 30s.
+	// Default: no timeout
+	Timeout string `yaml:"timeout,omitempty" json:"timeout,omitempty" jsonschema:"title=Timeout,description=Maximum duration of an agent run (request timeout for remote agents)"`
+
+	// CircuitBreaker fails calls to a remote agent fast after repeated
+	// failures, so parents stop waiting on a dead remote and route around
+	// it. Only used when Type="remote".
+	//
+	// Example:
+	//   circuit_breaker:
+	//     failure_threshold: 3
+	//     cooldown: 1m
+	CircuitBreaker *CircuitBreakerConfig `yaml:"circuit_breaker,omitempty" json:"circuit_breaker,omitempty" jsonschema:"title=Circuit Breaker,description=Fail calls to a failing remote agent fast"`
+}
+
+// AgentRetryConfig configures retries of workflow sub-agents and of remote
+// agent calls.
+type AgentRetryConfig struct {
+	// MaxRetries is the number of retries after the first attempt.
+	// 0 disables retries.
+	// Default: 3
+	MaxRetries *int `yaml:"max_retries,omitempty" json:"max_retries,omitempty" jsonschema:"title=Max Retries,description=Retries after the first attempt (0 disables retries),minimum=0,default=3"`
+
+	// BaseDelay is the delay before the first retry. For remote agents, a
+	// Retry-After header from the remote takes precedence.
+	// Default: 1s
+	BaseDelay Duration `yaml:"base_delay,omitempty" json:"base_delay,omitempty" jsonschema:"title=Base Delay,description=Delay before the first retry,default=1s"`
+
+	// MaxDelay caps the delay between retries.
+	// Default: 30s
+	MaxDelay Duration `yaml:"max_delay,omitempty" json:"max_delay,omitempty" jsonschema:"title=Max Delay,description=Maximum delay between retries,default=30s"`
+
+	// Jitter adds randomness (0.0-1.0) to the delays of workflow retries,
+	// which double after each retry.
+	// Default: 0.1
+	Jitter float64 `yaml:"jitter,omitempty" json:"jitter,omitempty" jsonschema:"title=Jitter,description=Randomness of workflow retry delays (0-1),minimum=0,maximum=1,default=0.1"`
+
+	// Multiplier scales the delay after each retry of a remote agent call.
+	// Default: 2
+	Multiplier float64 `yaml:"multiplier,omitempty" json:"multiplier,omitempty" jsonschema:"title=Multiplier,description=Delay growth per retry of a remote agent call,minimum=1,default=2"`
+
+	// RetryOn lists the HTTP statuses retried for remote agent calls.
+	// Only 408, 429 and 5xx statuses may be listed.
+	// Default: 408, 429, 500, 502, 503, 504
+	RetryOn []int `yaml:"retry_on,omitempty" json:"retry_on,omitempty" jsonschema:"title=Retry On,description=HTTP statuses retried for remote agent calls"`
+}
+
+// SetDefaults applies default values to AgentRetryConfig.
+func (c *AgentRetryConfig) SetDefaults() {
+	if c.MaxRetries == nil {
+		c.MaxRetries = IntPtr(3)
+	}
+	if c.BaseDelay == 0 {
+		c.BaseDelay = Duration(time.Second)
+	}
+	if c.MaxDelay == 0 {
+		c.MaxDelay = Duration(30 * time.Second)
+	}
+}
+
+// Retries returns the number of retries after the first attempt.
+func (c *AgentRetryConfig) Retries() int {
+	if c.MaxRetries == nil {
+		return 3
+	}
+	return *c.MaxRetries
+}
+
+// Validate checks the retry configuration of an agent of agentType.
+func (c *AgentRetryConfig) Validate(agentType string) error {
+	if c.MaxRetries != nil && *c.MaxRetries < 0 {
+		return fmt.Errorf("max_retries must be non-negative")
+	}
+	if c.BaseDelay < 0 {
+		return fmt.Errorf("base_delay must be non-negative")
+	}
+	if c.MaxDelay < 0 {
+		return fmt.Errorf("max_delay must be non-negative")
+	}
+	if agentType == "remote" {
+		if c.Jitter != 0 {
+			return fmt.Errorf("jitter is only supported for workflow agents")
+		}
+		if c.Multiplier != 0 && c.Multiplier < 1 {
+			return fmt.Errorf("multiplier must be at least 1")
+		}
+		for _, code := range c.RetryOn {
+			if code != 408 && code != 429 && (code < 500 || code > 599) {
+				return fmt.Errorf("retry_on: status %d cannot be retried (only 408, 429 and 5xx)", code)
+			}
+		}
+		return nil
+	}
+	if c.Jitter < 0 || c.Jitter > 1 {
+		return fmt.Errorf("jitter must be between 0 and 1")
+	}
+	if c.Multiplier != 0 || len(c.RetryOn) > 0 {
+		return fmt.Errorf("multiplier and retry_on are only supported for remote agents")
+	}
+	return nil
+}
+
+// CircuitBreakerConfig configures the circuit breaker of a remote agent.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failed calls that
+	// opens the breaker.
+	// Default: 5
+	FailureThreshold int `yaml:"failure_threshold,omitempty" json:"failure_threshold,omitempty" jsonschema:"title=Failure Threshold,description=Consecutive failures that open the breaker,minimum=1,default=5"`
+
+	// Cooldown is how long an open breaker fails calls before probing the
+	// remote again.
+	// Default: 30s
+	Cooldown Duration `yaml:"cooldown,omitempty" json:"cooldown,omitempty" jsonschema:"title=Cooldown,description=How long the breaker stays open before probing,default=30s"`
+
+	// HalfOpenMax is the number of concurrent probe calls let through
+	// after the cooldown.
+	// Default: 1
+	HalfOpenMax int `yaml:"half_open_max,omitempty" json:"half_open_max,omitempty" jsonschema:"title=Half-Open Max,description=Concurrent probe calls after the cooldown,minimum=1,default=1"`
+}
+
+// SetDefaults applies default values to CircuitBreakerConfig.
+func (c *CircuitBreakerConfig) SetDefaults() {
+	if c.FailureThreshold == 0 {
+		c.FailureThreshold = 5
+	}
+	if c.Cooldown == 0 {
+		c.Cooldown = Duration(30 * time.Second)
+	}
+	if c.HalfOpenMax == 0 {
+		c.HalfOpenMax = 1
+	}
+}
 
-	// Timeout is the request timeout for remote agents.
-	// Default: "30s"
-	Timeout string `yaml:"timeout,omitempty" json:"timeout,omitempty" jsonschema:"title=Timeout,description=Request timeout,default=30s"`
+// Validate checks the circuit breaker configuration.
+func (c *CircuitBreakerConfig) Validate() error {
+	if c.FailureThreshold < 1 {
+		return fmt.Errorf("failure_threshold must be at least 1")
+	}
+	if c.Cooldown < 0 {
+		return fmt.Errorf("cooldown must be non-negative")
+	}
+	if c.HalfOpenMax < 1 {
+		return fmt.Errorf("half_open_max must be at least 1")
+	}
+	return nil
 }
 
 // PromptConfig provides detailed prompt configuration.
@@ -237,8 +515,9 @@ type ContextConfig struct {
 	//   - "buffer_window": Keep last N messages (simple, fast)
 	//   - "token_window": Keep messages within token budget (accurate)
 	//   - "summary_buffer": Summarize old messages when exceeding budget
+	//   - "summary_window": Keep recent turns verbatim plus a rolling summary
 	// Default: "none" (for backwards compatibility)
-	Strategy string `yaml:"strategy,omitempty" json:"strategy,omitempty" jsonschema:"title=Strategy,description=Context window management strategy,enum=none,enum=buffer_window,enum=token_window,enum=summary_buffer,default=none"`
+	Strategy string `yaml:"strategy,omitempty" json:"strategy,omitempty" jsonschema:"title=Strategy,description=Context window management strategy,enum=none,enum=buffer_window,enum=token_window,enum=summary_buffer,enum=summary_window,default=none"`
 
 	// WindowSize is the number of messages to keep for buffer_window strategy.
 	// Only used when Strategy="buffer_window".
@@ -261,16 +540,42 @@ type ContextConfig struct {
 	// Default: 0.7 (70%)
 	Target float64 `yaml:"target,omitempty" json:"target,omitempty" jsonschema:"title=Target,description=Percentage of budget to reduce to after summarization,minimum=0,maximum=1,default=0.7"`
 
+	// KeepLastTurns is the number of recent turns kept verbatim.
+	// Only used when Strategy="summary_window".
+	// Default: 4
+	KeepLastTurns int `yaml:"keep_last_turns,omitempty" json:"keep_last_turns,omitempty" jsonschema:"title=Keep Last Turns,description=Number of recent turns kept verbatim for summary_window strategy,minimum=1,default=4"`
+
+	// MaxTokens is the hard cap on context tokens, including the summary.
+	// Only used when Strategy="summary_window".
+	// Default: 8000
+	MaxTokens int `yaml:"max_tokens,omitempty" json:"max_tokens,omitempty" jsonschema:"title=Max Tokens,description=Hard token cap for summary_window strategy,minimum=1,default=8000"`
+
 	// PreserveRecent is the minimum number of recent messages to always keep.
 	// Only used when Strategy="token_window".
 	// Default: 5
 	PreserveRecent int `yaml:"preserve_recent,omitempty" json:"preserve_recent,omitempty" jsonschema:"title=Preserve Recent,description=Minimum number of recent messages to always keep,minimum=0,default=5"`
 
 	// SummarizerLLM references an LLM from the global llms config to use for summarization.
-	// Only used when Strategy="summary_buffer".
+	// Only used when Strategy="summary_buffer" or "summary_window".
 	// If empty, uses the same LLM as the agent.
 	// Example: "gpt-4o-mini" (for cheaper summarization)
 	SummarizerLLM string `yaml:"summarizer_llm,omitempty" json:"summarizer_llm,omitempty" jsonschema:"title=Summarizer LLM,description=LLM reference for summarization (uses agent LLM if empty)"`
+
+	// Recall injects snippets of the user's past sessions relevant to the
+	// current message, found through the session index (server.memory).
+	//
+	// Example:
+	//   recall:
+	//     enabled: true
+	//     top_k: 3
+	//     min_score: 0.75
+	Recall *RecallConfig `yaml:"recall,omitempty" json:"recall,omitempty" jsonschema:"title=Recall,description=Inject relevant snippets from the user's past sessions"`
+
+	// AttachSources attaches the documents retrieved for a turn (store,
+	// title, path, score) to the final response metadata.
+	// Only applies when include_context is enabled.
+	// Default: false
+	AttachSources *bool `yaml:"attach_sources,omitempty" json:"attach_sources,omitempty" jsonschema:"title=Attach Sources,description=Attach retrieved RAG documents to the response metadata,default=false"`
 }
 
 // SetDefaults applies default values to ContextConfig.
@@ -302,6 +607,17 @@ func (c *ContextConfig) SetDefaults() {
 		if c.Target <= 0 || c.Target > 1 {
 			c.Target = 0.7
 		}
+	case "summary_window":
+		if c.KeepLastTurns <= 0 {
+			c.KeepLastTurns = 4
+		}
+		if c.MaxTokens <= 0 {
+			c.MaxTokens = 8000
+		}
+	}
+
+	if c.Recall != nil {
+		c.Recall.SetDefaults()
 	}
 }
 
@@ -313,10 +629,11 @@ func (c *ContextConfig) Validate() error {
 		"buffer_window":  true,
 		"token_window":   true,
 		"summary_buffer": true,
+		"summary_window": true,
 	}
 
 	if !validStrategies[c.Strategy] {
-		return fmt.Errorf("invalid context strategy %q (valid: none, buffer_window, token_window, summary_buffer)", c.Strategy)
+		return fmt.Errorf("invalid context strategy %q (valid: none, buffer_window, token_window, summary_buffer, summary_window)", c.Strategy)
 	}
 
 	if c.WindowSize < 0 {
@@ -339,6 +656,242 @@ func (c *ContextConfig) Validate() error {
 		return fmt.Errorf("preserve_recent must be non-negative")
 	}
 
+	if c.KeepLastTurns < 0 {
+		return fmt.Errorf("keep_last_turns must be non-negative")
+	}
+
+	if c.MaxTokens < 0 {
+		return fmt.Errorf("max_tokens must be non-negative")
+	}
+
+	if c.Recall != nil {
+		if err := c.Recall.Validate(); err != nil {
+			return fmt.Errorf("recall: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// RecallConfig configures semantic recall from the user's past sessions.
+type RecallConfig struct {
+	// Enabled turns on recall.
+	// Default: false
+	Enabled *bool `yaml:"enabled,omitempty" json:"enabled,omitempty" jsonschema:"title=Enabled,description=Enable recall from past sessions,default=false"`
+
+	// TopK is the maximum number of past snippets injected.
+	// Default: 3
+	TopK int `yaml:"top_k,omitempty" json:"top_k,omitempty" jsonschema:"title=Top K,description=Maximum past snippets injected,minimum=1,default=3"`
+
+	// MinScore is the minimum relevance score of an injected snippet. The
+	// scale depends on the index backend: cosine similarity (0-1) for
+	// vector, the number of matching words for keyword.
+	// Default: 0 (no minimum)
+	MinScore float64 `yaml:"min_score,omitempty" json:"min_score,omitempty" jsonschema:"title=Min Score,description=Minimum relevance score of injected snippets,minimum=0,default=0"`
+}
+
+// SetDefaults applies default values to RecallConfig.
+func (c *RecallConfig) SetDefaults() {
+	if c.Enabled == nil {
+		c.Enabled = BoolPtr(false)
+	}
+	if c.TopK <= 0 {
+		c.TopK = 3
+	}
+}
+
+// Validate checks the recall configuration.
+func (c *RecallConfig) Validate() error {
+	if c.TopK < 0 {
+		return fmt.Errorf("top_k must be non-negative")
+	}
+	if c.MinScore < 0 {
+		return fmt.Errorf("min_score must be non-negative")
+	}
+	return nil
+}
+
+// AgentMemoryConfig configures memory that outlives a session.
+type AgentMemoryConfig struct {
+	// LongTerm remembers durable facts about the user across sessions.
+	LongTerm *LongTermMemoryConfig `yaml:"long_term,omitempty" json:"long_term,omitempty" jsonschema:"title=Long-Term Memory,description=Durable user facts remembered across sessions"`
+}
+
+// LongTermMemoryConfig configures fact extraction long-term memory.
+// After each turn an LLM extracts durable facts about the user (name,
+// preferences), which are stored in user-scoped state and injected into
+// later prompts in any session.
+type LongTermMemoryConfig struct {
+	// Enabled turns on fact extraction and injection.
+	// Default: false
+	Enabled *bool `yaml:"enabled,omitempty" json:"enabled,omitempty" jsonschema:"title=Enabled,description=Enable long-term fact memory,default=false"`
+
+	// ExtractorLLM references an LLM from the global llms config used to
+	// extract facts. If empty, uses the same LLM as the agent.
+	ExtractorLLM string `yaml:"extractor_llm,omitempty" json:"extractor_llm,omitempty" jsonschema:"title=Extractor LLM,description=LLM reference for fact extraction (uses agent LLM if empty)"`
+
+	// MaxFacts is the maximum number of facts kept per user. The oldest
+	// facts are dropped first.
+	// Default: 50
+	MaxFacts int `yaml:"max_facts,omitempty" json:"max_facts,omitempty" jsonschema:"title=Max Facts,description=Maximum facts kept per user,minimum=1,default=50"`
+}
+
+// SetDefaults applies default values to AgentMemoryConfig.
+func (c *AgentMemoryConfig) SetDefaults() {
+	if c.LongTerm != nil {
+		c.LongTerm.SetDefaults()
+	}
+}
+
+// Validate checks the memory configuration.
+func (c *AgentMemoryConfig) Validate() error {
+	if c.LongTerm != nil {
+		if err := c.LongTerm.Validate(); err != nil {
+			return fmt.Errorf("long_term: %w", err)
+		}
+	}
+	return nil
+}
+
+// IsLongTermEnabled reports whether long-term fact memory is enabled.
+func (c *AgentMemoryConfig) IsLongTermEnabled() bool {
+	return c != nil && c.LongTerm != nil && BoolValue(c.LongTerm.Enabled, false)
+}
+
+// SetDefaults applies default values to LongTermMemoryConfig.
+func (c *LongTermMemoryConfig) SetDefaults() {
+	if c.Enabled == nil {
+		c.Enabled = BoolPtr(false)
+	}
+	if c.MaxFacts <= 0 {
+		c.MaxFacts = 50
+	}
+}
+
+// Validate checks the long-term memory configuration.
+func (c *LongTermMemoryConfig) Validate() error {
+	if c.MaxFacts < 0 {
+		return fmt.Errorf("max_facts must be non-negative")
+	}
+	return nil
+}
+
+// ScopeConfig configures the out-of-scope guardrail for focused agents.
+// Input is refused only when it is confidently off-topic: it shares no
+// keywords with the allowed topics and, if an embedder is configured, its
+// similarity to every topic is below the threshold.
+type ScopeConfig struct {
+	// AllowedTopics lists the topics the agent handles.
+	AllowedTopics []string `yaml:"allowed_topics,omitempty" json:"allowed_topics,omitempty" jsonschema:"title=Allowed Topics,description=Topics the agent handles,minItems=1"`
+
+	// RefusalMessage is returned for out-of-scope requests.
+	// Default: "I'm sorry, I can only help with: <allowed topics>."
+	RefusalMessage string `yaml:"refusal_message,omitempty" json:"refusal_message,omitempty" jsonschema:"title=Refusal Message,description=Message returned for out-of-scope requests"`
+
+	// Threshold is the minimum similarity (0-1) to an allowed topic for input
+	// to be considered in scope. Only used when Embedder is set.
+	// Default: 0.2
+	Threshold float64 `yaml:"threshold,omitempty" json:"threshold,omitempty" jsonschema:"title=Threshold,description=Minimum similarity to an allowed topic,minimum=0,maximum=1,default=0.2"`
+
+	// Embedder references an embedder for semantic matching.
+	// If empty, only keyword matching is used.
+	Embedder string `yaml:"embedder,omitempty" json:"embedder,omitempty" jsonschema:"title=Embedder,description=Embedder reference for semantic topic matching"`
+}
+
+// SetDefaults applies default values to ScopeConfig.
+func (c *ScopeConfig) SetDefaults() {
+	if c.Threshold <= 0 {
+		c.Threshold = 0.2
+	}
+}
+
+// Validate checks the scope configuration.
+func (c *ScopeConfig) Validate() error {
+	if len(c.AllowedTopics) == 0 {
+		return fmt.Errorf("allowed_topics is required")
+	}
+	if c.Threshold < 0 || c.Threshold > 1 {
+		return fmt.Errorf("threshold must be between 0 and 1")
+	}
+	return nil
+}
+
+// PromptBudgetConfig limits the share of the context window the system
+// prompt may use.
+type PromptBudgetConfig struct {
+	// ContextWindow is the model's context window in tokens.
+	ContextWindow int `yaml:"context_window,omitempty" json:"context_window,omitempty" jsonschema:"title=Context Window,description=Model context window in tokens,minimum=1"`
+
+	// MaxFraction is the share (0-1) of the context window the system
+	// prompt may use.
+	// Default: 0.5
+	MaxFraction float64 `yaml:"max_fraction,omitempty" json:"max_fraction,omitempty" jsonschema:"title=Max Fraction,description=Share of the context window the system prompt may use,minimum=0,maximum=1,default=0.5"`
+
+	// Action is taken when the budget is exceeded: "warn" logs the size
+	// breakdown, "error" fails the request.
+	// Default: "warn"
+	Action string `yaml:"action,omitempty" json:"action,omitempty" jsonschema:"title=Action,description=What to do when the budget is exceeded,enum=warn,enum=error,default=warn"`
+}
+
+// SetDefaults applies default values to PromptBudgetConfig.
+func (c *PromptBudgetConfig) SetDefaults() {
+	if c.MaxFraction <= 0 {
+		c.MaxFraction = 0.5
+	}
+	if c.Action == "" {
+		c.Action = "warn"
+	}
+}
+
+// Validate checks the prompt budget configuration.
+func (c *PromptBudgetConfig) Validate() error {
+	if c.ContextWindow <= 0 {
+		return fmt.Errorf("context_window is required")
+	}
+	if c.MaxFraction < 0 || c.MaxFraction > 1 {
+		return fmt.Errorf("max_fraction must be between 0 and 1")
+	}
+	if c.Action != "" && c.Action != "warn" && c.Action != "error" {
+		return fmt.Errorf("invalid action %q (valid: warn, error)", c.Action)
+	}
+	return nil
+}
+
+// ResponseLanguageConfig enforces the language of agent responses.
+type ResponseLanguageConfig struct {
+	// Language is a language code (e.g., "ja", "pt-BR") or name
+	// (e.g., "Japanese").
+	Language string `yaml:"language,omitempty" json:"language,omitempty" jsonschema:"title=Language,description=Language code or name the agent must respond in"`
+
+	// Verify checks the writing system of each final response and retries
+	// it with a stronger directive when it drifted. Opt-in, since retries
+	// cost an extra model call.
+	// Default: false
+	Verify *bool `yaml:"verify,omitempty" json:"verify,omitempty" jsonschema:"title=Verify,description=Check response language and retry on drift,default=false"`
+
+	// MaxRetries caps the retries of a drifted response.
+	// Default: 1
+	MaxRetries int `yaml:"max_retries,omitempty" json:"max_retries,omitempty" jsonschema:"title=Max Retries,description=Retries of a response in the wrong language,minimum=0,default=1"`
+}
+
+// SetDefaults applies default values to ResponseLanguageConfig.
+func (c *ResponseLanguageConfig) SetDefaults() {
+	if c.Verify == nil {
+		c.Verify = BoolPtr(false)
+	}
+	if c.MaxRetries <= 0 {
+		c.MaxRetries = 1
+	}
+}
+
+// Validate checks the response language configuration.
+func (c *ResponseLanguageConfig) Validate() error {
+	if c.Language == "" {
+		return fmt.Errorf("language is required")
+	}
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("max_retries must be non-negative")
+	}
 	return nil
 }
 
@@ -431,6 +984,15 @@ type ReasoningConfig struct {
 	// If empty and EnableExitTool/EnableEscalateTool are set, a default
 	// completion instruction is generated.
 	CompletionInstruction string `yaml:"completion_instruction,omitempty" json:"completion_instruction,omitempty" jsonschema:"title=Completion Instruction,description=Instruction appended to help model know when to stop"`
+
+	// RetryOnEmpty retries the model call once when it returns nothing at
+	// all (no text, no tool calls), e.g. after a transient failure or
+	// aggressive content filtering.
+	RetryOnEmpty *bool `yaml:"retry_on_empty,omitempty" json:"retry_on_empty,omitempty" jsonschema:"title=Retry On Empty,description=Retry the model call once on a completely empty response,default=false"`
+
+	// EmptyRetryInstruction is sent as a user message with the retry to nudge
+	// the model into answering. Optional.
+	EmptyRetryInstruction string `yaml:"empty_retry_instruction,omitempty" json:"empty_retry_instruction,omitempty" jsonschema:"title=Empty Retry Instruction,description=Nudge sent with the retry after an empty response"`
 }
 
 // SetDefaults applies default values to ReasoningConfig.
@@ -495,10 +1057,11 @@ func joinStrings(parts []string, sep string) string {
 }
 
 // isWorkflowAgent returns true if the agent type is a workflow orchestrator
-// that doesn't need its own LLM (sequential, parallel, loop).
+// that doesn't need its own LLM (sequential, parallel, loop, conditional,
+// map_reduce).
 func isWorkflowAgent(agentType string) bool {
 	switch agentType {
-	case "sequential", "parallel", "loop":
+	case "sequential", "parallel", "loop", "conditional", "map_reduce":
 		return true
 	default:
 		return false
@@ -515,11 +1078,26 @@ func (c *AgentConfig) SetDefaults(defaults *DefaultsConfig) {
 	}
 
 	// If still no LLM, use "default" (but only for agent types that need an LLM)
-	// Workflow agents (sequential, parallel, loop) don't need an LLM
+	// Workflow agents (sequential, parallel, loop, conditional, map_reduce)
+	// don't need an LLM
 	if c.LLM == "" && !isWorkflowAgent(c.Type) {
 		c.LLM = "default"
 	}
 
+	// The branches of a conditional agent are its sub-agents
+	if c.Type == "conditional" && len(c.SubAgents) == 0 {
+		c.SubAgents = c.conditionalBranches()
+	}
+
+	// The mapper and reducer of a map-reduce agent are its sub-agents
+	if c.Type == "map_reduce" && len(c.SubAgents) == 0 {
+		for _, name := range []string{c.Mapper, c.Reducer} {
+			if name != "" {
+				c.SubAgents = append(c.SubAgents, name)
+			}
+		}
+	}
+
 	// Default description (A2A spec required)
 	if c.Description == "" {
 		if c.Name != "" {
@@ -529,14 +1107,6 @@ func (c *AgentConfig) SetDefaults(defaults *DefaultsConfig) {
 		}
 	}
 
-	// Default input/output modes (A2A spec required)
-	if len(c.InputModes) == 0 {
-		c.InputModes = []string{"text/plain"}
-	}
-	if len(c.OutputModes) == 0 {
-		c.OutputModes = []string{"text/plain"}
-	}
-
 	// Default visibility
 	if c.Visibility == "" {
 		c.Visibility = "public"
@@ -562,11 +1132,42 @@ func (c *AgentConfig) SetDefaults(defaults *DefaultsConfig) {
 		c.Context.SetDefaults()
 	}
 
+	// Apply memory config defaults
+	if c.Memory != nil {
+		c.Memory.SetDefaults()
+	}
+
 	// Apply structured output config defaults
 	if c.StructuredOutput != nil {
 		c.StructuredOutput.SetDefaults()
 	}
 
+	// Apply scope config defaults
+	if c.Scope != nil {
+		c.Scope.SetDefaults()
+	}
+
+	// Apply prompt budget defaults
+	if c.PromptBudget != nil {
+		c.PromptBudget.SetDefaults()
+	}
+
+	// Apply response language defaults
+	if c.ResponseLanguage != nil {
+		c.ResponseLanguage.SetDefaults()
+	}
+
+	// Apply retry defaults
+	if c.Retry != nil {
+		c.Retry.SetDefaults()
+		if c.Type != "remote" && c.Retry.Jitter == 0 {
+			c.Retry.Jitter = 0.1
+		}
+	}
+	if c.CircuitBreaker != nil {
+		c.CircuitBreaker.SetDefaults()
+	}
+
 	// Apply IncludeContext defaults (matches legacy PromptConfig.SetDefaults)
 	if c.IncludeContext == nil {
 		c.IncludeContext = BoolPtr(false)
@@ -598,6 +1199,25 @@ func (c *AgentConfig) Validate() error {
 	default:
 		return fmt.Errorf("invalid visibility %q (must be public, internal, or private)", c.Visibility)
 	}
+	for _, scope := range c.RequiredScopes {
+		if len(strings.Fields(scope)) != 1 || strings.TrimSpace(scope) != scope {
+			return fmt.Errorf("invalid required scope %q", scope)
+		}
+	}
+
+	// Validate discovery tags and category
+	for _, tag := range c.Tags {
+		if !slugPattern.MatchString(tag) {
+			return fmt.Errorf("invalid tag %q (use lowercase letters, digits and hyphens)", tag)
+		}
+	}
+	if c.Category != "" && !slugPattern.MatchString(c.Category) {
+		return fmt.Errorf("invalid category %q (use lowercase letters, digits and hyphens)", c.Category)
+	}
+
+	if err := validateHeaders(c.CardHeaders); err != nil {
+		return fmt.Errorf("card_headers: %w", err)
+	}
 
 	// Validate context config
 	if c.Context != nil {
@@ -606,10 +1226,118 @@ func (c *AgentConfig) Validate() error {
 		}
 	}
 
+	// Validate memory config
+	if c.Memory != nil {
+		if err := c.Memory.Validate(); err != nil {
+			return fmt.Errorf("memory: %w", err)
+		}
+	}
+
+	// Validate scope config
+	if c.Scope != nil {
+		if err := c.Scope.Validate(); err != nil {
+			return fmt.Errorf("scope: %w", err)
+		}
+	}
+
+	// Validate prompt budget config
+	if c.PromptBudget != nil {
+		if err := c.PromptBudget.Validate(); err != nil {
+			return fmt.Errorf("prompt_budget: %w", err)
+		}
+	}
+
+	// Validate response language config
+	if c.ResponseLanguage != nil {
+		if err := c.ResponseLanguage.Validate(); err != nil {
+			return fmt.Errorf("response_language: %w", err)
+		}
+	}
+
+	if c.Timeout != "" {
+		if d, err := time.ParseDuration(c.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("invalid timeout %q (use a positive duration such as 30s or 2m)", c.Timeout)
+		}
+	}
+
+	// Validate retry config
+	if c.Retry != nil {
+		if err := c.Retry.Validate(c.Type); err != nil {
+			return fmt.Errorf("retry: %w", err)
+		}
+	}
+	if c.CircuitBreaker != nil {
+		if c.Type != "remote" {
+			return fmt.Errorf("circuit_breaker is only supported for remote agents")
+		}
+		if err := c.CircuitBreaker.Validate(); err != nil {
+			return fmt.Errorf("circuit_breaker: %w", err)
+		}
+	}
+
+	// Validate conditional routing
+	if c.Type == "conditional" {
+		if len(c.Cases) == 0 {
+			return fmt.Errorf("cases is required for conditional agents")
+		}
+		for i, cs := range c.Cases {
+			if cs.When == "" || cs.Agent == "" {
+				return fmt.Errorf("cases[%d]: when and agent are required", i)
+			}
+		}
+	} else if len(c.Cases) > 0 || c.Default != "" {
+		return fmt.Errorf("cases and default are only supported for conditional agents")
+	}
+
+	// Validate map-reduce fan-out
+	if c.Type == "map_reduce" {
+		if c.InputKey == "" || c.Mapper == "" {
+			return fmt.Errorf("input_key and mapper are required for map_reduce agents")
+		}
+		if c.MaxConcurrency < 0 {
+			return fmt.Errorf("max_concurrency must be non-negative")
+		}
+	} else if c.Mapper != "" || c.Reducer != "" || c.InputKey != "" || c.OutputKey != "" || c.MaxConcurrency != 0 {
+		return fmt.Errorf("mapper, reducer, input_key, output_key and max_concurrency are only supported for map_reduce agents")
+	}
+
 	// LLM reference is validated at Config level
 	return nil
 }
 
+// ConditionalCaseConfig is a branch of a conditional agent.
+type ConditionalCaseConfig struct {
+	// When is a predicate over session state. State keys are written in
+	// braces and compared with == != < <= > >=, combined with ! && ||.
+	// Example: {classification} == "billing" && {priority} >= 2
+	When string `yaml:"when" json:"when" jsonschema:"title=When,description=Predicate over session state"`
+
+	// Agent is the agent to run when the predicate holds.
+	Agent string `yaml:"agent" json:"agent" jsonschema:"title=Agent,description=Agent to run when the predicate holds"`
+}
+
+// conditionalBranches returns the distinct agents of the cases and default,
+// in order.
+func (c *AgentConfig) conditionalBranches() []string {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, cs := range c.Cases {
+		add(cs.Agent)
+	}
+	add(c.Default)
+	return names
+}
+
+// PromptVersionAuto numbers prompt versions automatically (see
+// AgentConfig.PromptVersion).
+const PromptVersionAuto = "auto"
+
 // GetSystemPrompt returns the system prompt to use.
 func (c *AgentConfig) GetSystemPrompt() string {
 	if c.Prompt != nil && c.Prompt.SystemPrompt != "" {
@@ -618,6 +1346,15 @@ func (c *AgentConfig) GetSystemPrompt() string {
 	return c.Instruction
 }
 
+// RunTimeout returns the parsed Timeout, or zero when unset or invalid.
+func (c *AgentConfig) RunTimeout() time.Duration {
+	d, err := time.ParseDuration(c.Timeout)
+	if err != nil || d < 0 {
+		return 0
+	}
+	return d
+}
+
 // GetDisplayName returns the name to display.
 func (c *AgentConfig) GetDisplayName() string {
 	if c.Name != "" {
@@ -37,23 +37,36 @@ import (
 // The JWT token should be passed in the Authorization header:
 //
 //	Authorization: Bearer <token>
+//
+// Internal callers can use static API keys instead, alone or next to JWT:
+//
+//	server:
+//	  auth:
+//	    enabled: true
+//	    api_keys:
+//	      keys:
+//	        - key: ${BILLING_API_KEY}
+//	          subject: billing-service
+//	          scopes: [agents:invoke]
+//
+// The key is passed in the X-API-Key header.
 type AuthConfig struct {
 	// Enabled controls whether authentication is required.
 	// Default: false
 	Enabled bool `yaml:"enabled,omitempty"`
 
 	// JWKSURL is the URL to fetch JSON Web Key Set from.
-	// Required when Enabled is true.
+	// Required when Enabled is true, unless only API keys are used.
 	// Example: "https://auth.example.com/.well-known/jwks.json"
 	JWKSURL string `yaml:"jwks_url,omitempty"`
 
 	// Issuer is the expected token issuer (iss claim).
-	// Required when Enabled is true.
+	// Required with JWKSURL.
 	// Example: "https://auth.example.com"
 	Issuer string `yaml:"issuer,omitempty"`
 
 	// Audience is the expected token audience (aud claim).
-	// Required when Enabled is true.
+	// Required with JWKSURL.
 	// Example: "hector-api"
 	Audience string `yaml:"audience,omitempty"`
 
@@ -69,6 +82,74 @@ type AuthConfig struct {
 	// When false, unauthenticated requests proceed but without user context.
 	// Default: true (when Enabled is true)
 	RequireAuth *bool `yaml:"require_auth,omitempty"`
+
+	// APIKeys configures static API keys, checked before JWT validation.
+	APIKeys *APIKeysConfig `yaml:"api_keys,omitempty"`
+}
+
+// APIKeysConfig configures static API key authentication.
+type APIKeysConfig struct {
+	// Header is the request header carrying the key.
+	// Default: X-API-Key
+	Header string `yaml:"header,omitempty"`
+
+	// Keys are the accepted keys. Use ${VAR} or a secret reference
+	// (vault://, awssm://, ...) rather than literal keys.
+	Keys []APIKeyConfig `yaml:"keys,omitempty"`
+
+	// File is a YAML file with more keys, in the same format as Keys
+	// (a list of key/subject/role/tenant_id/scopes entries). It is read
+	// when the server starts, e.g. from a mounted secrets volume.
+	File string `yaml:"file,omitempty"`
+}
+
+// APIKeyConfig maps an API key to the principal it authenticates.
+type APIKeyConfig struct {
+	// Key is the secret key value.
+	Key string `yaml:"key" sensitive:"true"`
+
+	// Subject identifies the caller, like the sub claim of a JWT.
+	Subject string `yaml:"subject"`
+
+	// Role is the caller's role for authorization decisions.
+	Role string `yaml:"role,omitempty"`
+
+	// TenantID is the caller's tenant.
+	TenantID string `yaml:"tenant_id,omitempty"`
+
+	// Scopes are the permissions granted to the key.
+	Scopes []string `yaml:"scopes,omitempty"`
+}
+
+// SetDefaults applies default values to APIKeysConfig.
+func (c *APIKeysConfig) SetDefaults() {
+	if c.Header == "" {
+		c.Header = "X-API-Key"
+	}
+}
+
+// Validate checks the APIKeysConfig for errors.
+func (c *APIKeysConfig) Validate() error {
+	if len(c.Keys) == 0 && c.File == "" {
+		return fmt.Errorf("auth.api_keys needs keys or a file")
+	}
+	for i, k := range c.Keys {
+		if err := k.Validate(); err != nil {
+			return fmt.Errorf("auth.api_keys.keys[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Validate checks the APIKeyConfig for errors.
+func (c *APIKeyConfig) Validate() error {
+	if c.Key == "" {
+		return fmt.Errorf("key is required")
+	}
+	if c.Subject == "" {
+		return fmt.Errorf("subject is required")
+	}
+	return nil
 }
 
 // SetDefaults applies default values to AuthConfig.
@@ -88,6 +169,10 @@ func (c *AuthConfig) SetDefaults() {
 		requireAuth := true
 		c.RequireAuth = &requireAuth
 	}
+
+	if c.APIKeys != nil {
+		c.APIKeys.SetDefaults()
+	}
 }
 
 // Validate checks the AuthConfig for errors.
@@ -96,6 +181,15 @@ func (c *AuthConfig) Validate() error {
 		return nil // No validation needed when disabled
 	}
 
+	if c.APIKeys != nil {
+		if err := c.APIKeys.Validate(); err != nil {
+			return err
+		}
+		if c.JWKSURL == "" && c.Issuer == "" && c.Audience == "" {
+			return nil // API keys only
+		}
+	}
+
 	if c.JWKSURL == "" {
 		return fmt.Errorf("auth.jwks_url is required when auth is enabled")
 	}
@@ -117,7 +211,12 @@ func (c *AuthConfig) Validate() error {
 
 // IsEnabled returns true if authentication is configured and enabled.
 func (c *AuthConfig) IsEnabled() bool {
-	return c != nil && c.Enabled && c.JWKSURL != "" && c.Issuer != "" && c.Audience != ""
+	return c != nil && c.Enabled && (c.HasJWT() || c.APIKeys != nil)
+}
+
+// HasJWT returns true if JWT validation is configured.
+func (c *AuthConfig) HasJWT() bool {
+	return c != nil && c.JWKSURL != "" && c.Issuer != "" && c.Audience != ""
 }
 
 // IsRequireAuth returns whether authentication is mandatory.
@@ -135,10 +234,10 @@ type CredentialsConfig struct {
 	Type string `yaml:"type,omitempty"`
 
 	// Token is the bearer token (for type: bearer)
-	Token string `yaml:"token,omitempty"`
+	Token string `yaml:"token,omitempty" sensitive:"true"`
 
 	// APIKey is the API key (for type: api_key)
-	APIKey string `yaml:"api_key,omitempty"`
+	APIKey string `yaml:"api_key,omitempty" sensitive:"true"`
 
 	// APIKeyHeader is the header name for API key (default: X-API-Key)
 	APIKeyHeader string `yaml:"api_key_header,omitempty"`
@@ -147,7 +246,7 @@ type CredentialsConfig struct {
 	Username string `yaml:"username,omitempty"`
 
 	// Password for basic auth (for type: basic)
-	Password string `yaml:"password,omitempty"`
+	Password string `yaml:"password,omitempty" sensitive:"true"`
 }
 
 // SetDefaults applies default values to CredentialsConfig.
@@ -46,6 +46,7 @@ package config
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -92,14 +93,76 @@ type Config struct {
 	// RateLimiting configures rate limiting.
 	RateLimiting *RateLimitConfig `yaml:"rate_limiting,omitempty" json:"rate_limiting,omitempty" jsonschema:"title=Rate Limiting,description=Rate limiting configuration"`
 
-	// Defaults provides default values for agents.
-	Defaults *DefaultsConfig `yaml:"defaults,omitempty" json:"defaults,omitempty" jsonschema:"title=Defaults,description=Default values for agents"`
+	// LLMCache caches LLM responses to repeated identical requests.
+	LLMCache *LLMCacheConfig `yaml:"llm_cache,omitempty" json:"llm_cache,omitempty" jsonschema:"title=LLM Cache,description=LLM response cache configuration"`
+
+	// Defaults provides default values for agents and LLMs.
+	Defaults *DefaultsConfig `yaml:"defaults,omitempty" json:"defaults,omitempty" jsonschema:"title=Defaults,description=Default values for agents and LLMs"`
 }
 
-// DefaultsConfig provides default values for agent configurations.
+// DefaultsConfig provides default values for agent and LLM configurations.
 type DefaultsConfig struct {
 	// LLM is the default LLM reference for agents.
 	LLM string `yaml:"llm,omitempty" json:"llm,omitempty" jsonschema:"title=Default LLM,description=Default LLM reference for agents"`
+
+	// LLMSettings are applied to every LLM that doesn't set them.
+	LLMSettings *LLMDefaults `yaml:"llm_settings,omitempty" json:"llm_settings,omitempty" jsonschema:"title=LLM Settings,description=Default generation settings for all LLMs"`
+}
+
+// LLMDefaults are generation settings shared by all LLMs.
+//
+// Precedence: a value set on the LLM wins over llm_settings, which wins
+// over the built-in defaults.
+//
+// Example:
+//
+//	defaults:
+//	  llm_settings:
+//	    temperature: 0.2
+//	    max_tokens: 4096
+type LLMDefaults struct {
+	// Temperature is the default sampling temperature.
+	Temperature *float64 `yaml:"temperature,omitempty" json:"temperature,omitempty" jsonschema:"title=Temperature,description=Default sampling temperature,minimum=0,maximum=2"`
+
+	// MaxTokens is the default response length limit.
+	MaxTokens int `yaml:"max_tokens,omitempty" json:"max_tokens,omitempty" jsonschema:"title=Max Tokens,description=Default maximum tokens to generate,minimum=1"`
+
+	// TotalTimeout is the default bound on each request.
+	TotalTimeout Duration `yaml:"total_timeout,omitempty" json:"total_timeout,omitempty" jsonschema:"title=Total Timeout,description=Default bound on the whole request (e.g. 10m)"`
+
+	// MaxRetries is the default number of retries of failed requests.
+	MaxRetries int `yaml:"max_retries,omitempty" json:"max_retries,omitempty" jsonschema:"title=Max Retries,description=Default retries of failed requests,minimum=0"`
+}
+
+// Validate checks the LLM defaults.
+func (d *LLMDefaults) Validate() error {
+	if d.Temperature != nil && (*d.Temperature < 0 || *d.Temperature > 2) {
+		return fmt.Errorf("temperature must be between 0 and 2")
+	}
+	if d.MaxTokens < 0 || d.TotalTimeout < 0 || d.MaxRetries < 0 {
+		return fmt.Errorf("max_tokens, total_timeout and max_retries must be non-negative")
+	}
+	return nil
+}
+
+// apply fills the fields llm leaves unset.
+func (d *LLMDefaults) apply(llm *LLMConfig) {
+	if d == nil {
+		return
+	}
+	if llm.Temperature == nil && d.Temperature != nil {
+		temp := *d.Temperature
+		llm.Temperature = &temp
+	}
+	if llm.MaxTokens == 0 {
+		llm.MaxTokens = d.MaxTokens
+	}
+	if llm.TotalTimeout == 0 {
+		llm.TotalTimeout = d.TotalTimeout
+	}
+	if llm.MaxRetries == 0 {
+		llm.MaxRetries = d.MaxRetries
+	}
 }
 
 // SetDefaults applies default values to the config.
@@ -167,14 +230,19 @@ func (c *Config) SetDefaults() {
 		}
 	}
 
-	// Apply defaults to each component
+	// Apply defaults to each component; shared LLM settings come first so
+	// they take precedence over the built-in defaults
+	var llmDefaults *LLMDefaults
+	if c.Defaults != nil {
+		llmDefaults = c.Defaults.LLMSettings
+	}
 	for name, llm := range c.LLMs {
-		if llm != nil {
-			llm.SetDefaults()
-		} else {
-			c.LLMs[name] = &LLMConfig{}
-			c.LLMs[name].SetDefaults()
+		if llm == nil {
+			llm = &LLMConfig{}
+			c.LLMs[name] = llm
 		}
+		llmDefaults.apply(llm)
+		llm.SetDefaults()
 	}
 
 	for name, tool := range c.Tools {
@@ -201,6 +269,11 @@ func (c *Config) SetDefaults() {
 	if c.RateLimiting != nil {
 		c.RateLimiting.SetDefaults()
 	}
+
+	// Apply defaults to the LLM response cache
+	if c.LLMCache != nil {
+		c.LLMCache.SetDefaults()
+	}
 }
 
 // Validate checks the configuration for errors.
@@ -227,6 +300,13 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// Validate shared LLM settings
+	if c.Defaults != nil && c.Defaults.LLMSettings != nil {
+		if err := c.Defaults.LLMSettings.Validate(); err != nil {
+			errs = append(errs, fmt.Sprintf("defaults.llm_settings: %v", err))
+		}
+	}
+
 	// Validate LLMs
 	for name, llm := range c.LLMs {
 		if llm == nil {
@@ -286,6 +366,13 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	// Validate LLMCache
+	if c.LLMCache != nil {
+		if err := c.LLMCache.Validate(); err != nil {
+			errs = append(errs, fmt.Sprintf("llm_cache: %v", err))
+		}
+	}
+
 	// Validate references
 	if err := c.validateReferences(); err != nil {
 		errs = append(errs, err.Error())
@@ -302,6 +389,38 @@ func (c *Config) Validate() error {
 func (c *Config) validateReferences() error {
 	var errs []string
 
+	for llmName, llm := range c.LLMs {
+		if llm == nil || llm.Shadow == "" {
+			continue
+		}
+		if llm.Shadow == llmName {
+			errs = append(errs, fmt.Sprintf("llm %q cannot shadow itself", llmName))
+		} else if _, ok := c.LLMs[llm.Shadow]; !ok {
+			errs = append(errs, fmt.Sprintf("llm %q references undefined shadow llm %q", llmName, llm.Shadow))
+		}
+	}
+
+	for toolName, tool := range c.Tools {
+		if tool == nil {
+			continue
+		}
+		if tool.SummarizerLLM != "" {
+			if _, ok := c.LLMs[tool.SummarizerLLM]; !ok {
+				errs = append(errs, fmt.Sprintf("tool %q references undefined summarizer llm %q", toolName, tool.SummarizerLLM))
+			}
+		}
+		for _, agentName := range tool.AllowedAgents {
+			if _, ok := c.Agents[agentName]; !ok {
+				errs = append(errs, fmt.Sprintf("tool %q allows undefined agent %q", toolName, agentName))
+			}
+		}
+		for _, dbName := range tool.Databases {
+			if _, ok := c.Databases[dbName]; !ok {
+				errs = append(errs, fmt.Sprintf("tool %q references undefined database %q", toolName, dbName))
+			}
+		}
+	}
+
 	for agentName, agent := range c.Agents {
 		if agent == nil {
 			continue
@@ -313,6 +432,11 @@ func (c *Config) validateReferences() error {
 				errs = append(errs, fmt.Sprintf("agent %q references undefined llm %q", agentName, agent.LLM))
 			}
 		}
+		if agent.Memory != nil && agent.Memory.LongTerm != nil && agent.Memory.LongTerm.ExtractorLLM != "" {
+			if _, ok := c.LLMs[agent.Memory.LongTerm.ExtractorLLM]; !ok {
+				errs = append(errs, fmt.Sprintf("agent %q references undefined extractor llm %q", agentName, agent.Memory.LongTerm.ExtractorLLM))
+			}
+		}
 
 		// Check tool references
 		for _, toolName := range agent.Tools {
@@ -361,6 +485,13 @@ func (c *Config) validateReferences() error {
 				}
 			}
 		}
+
+		// Check scope embedder reference
+		if agent.Scope != nil && agent.Scope.Embedder != "" {
+			if _, ok := c.Embedders[agent.Scope.Embedder]; !ok {
+				errs = append(errs, fmt.Sprintf("agent %q scope references undefined embedder %q", agentName, agent.Scope.Embedder))
+			}
+		}
 	}
 
 	// Check document store references
@@ -407,6 +538,11 @@ func (c *Config) validateReferences() error {
 					errs = append(errs, fmt.Sprintf("document_store %q references undefined llm %q for multi-query", storeName, store.Search.MultiQueryLLM))
 				}
 			}
+			if r := store.Search.Reranker; r != nil && r.Type == "llm" {
+				if _, ok := c.LLMs[r.Model]; !ok {
+					errs = append(errs, fmt.Sprintf("document_store %q references undefined llm %q for reranking", storeName, r.Model))
+				}
+			}
 		}
 	}
 
@@ -431,6 +567,26 @@ func (c *Config) validateReferences() error {
 		}
 	}
 
+	// Check vector_stores database references
+	for name, store := range c.VectorStores {
+		if store == nil || store.Type != "pgvector" || store.Database == "" {
+			continue
+		}
+		db, ok := c.Databases[store.Database]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("vector_store %q references undefined database %q", name, store.Database))
+		} else if db.Driver != "postgres" {
+			errs = append(errs, fmt.Sprintf("vector_store %q requires a postgres database, %q uses %s", name, store.Database, db.Driver))
+		}
+	}
+
+	// Check llm_cache database reference
+	if c.LLMCache != nil && c.LLMCache.Backend == "sql" && c.LLMCache.SQLDatabase != "" {
+		if _, ok := c.Databases[c.LLMCache.SQLDatabase]; !ok {
+			errs = append(errs, fmt.Sprintf("llm_cache references undefined database %q", c.LLMCache.SQLDatabase))
+		}
+	}
+
 	// Check server.memory embedder reference
 	if c.Server.Memory != nil && c.Server.Memory.Embedder != "" {
 		if _, ok := c.Embedders[c.Server.Memory.Embedder]; !ok {
@@ -438,6 +594,24 @@ func (c *Config) validateReferences() error {
 		}
 	}
 
+	// Required scopes can only be enforced against authenticated callers
+	if !c.Server.Auth.IsEnabled() {
+		for agentName, agent := range c.Agents {
+			if agent != nil && len(agent.RequiredScopes) > 0 {
+				errs = append(errs, fmt.Sprintf("agent %q sets required_scopes but server.auth is not enabled", agentName))
+			}
+		}
+	}
+
+	// Check observability.langfuse agent references
+	if obs := c.Server.Observability; obs != nil && obs.Langfuse.IsEnabled() {
+		for _, agentName := range obs.Langfuse.Agents {
+			if _, ok := c.Agents[agentName]; !ok {
+				errs = append(errs, fmt.Sprintf("server.observability.langfuse references undefined agent %q", agentName))
+			}
+		}
+	}
+
 	if len(errs) > 0 {
 		return fmt.Errorf("reference errors:\n  - %s", strings.Join(errs, "\n  - "))
 	}
@@ -457,6 +631,30 @@ func (c *Config) GetLLM(name string) (*LLMConfig, bool) {
 	return llm, ok
 }
 
+// ModelPricing maps model names to the pricing of the LLM that serves them.
+// If several LLMs use the same model, the first one by name with pricing wins.
+func (c *Config) ModelPricing() map[string]*PricingConfig {
+	pricing := make(map[string]*PricingConfig)
+	if c == nil {
+		return pricing
+	}
+	names := make([]string, 0, len(c.LLMs))
+	for name := range c.LLMs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		llm := c.LLMs[name]
+		if llm == nil || llm.Pricing == nil {
+			continue
+		}
+		if _, ok := pricing[llm.Model]; !ok {
+			pricing[llm.Model] = llm.Pricing
+		}
+	}
+	return pricing
+}
+
 // GetTool returns the tool config by name.
 func (c *Config) GetTool(name string) (*ToolConfig, bool) {
 	tool, ok := c.Tools[name]
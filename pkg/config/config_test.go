@@ -0,0 +1,328 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLLMSettingsDefaults(t *testing.T) {
+	low, high := 0.2, 1.5
+	cfg := &Config{
+		Defaults: &DefaultsConfig{LLMSettings: &LLMDefaults{
+			Temperature:  &low,
+			MaxTokens:    2048,
+			TotalTimeout: Duration(5 * time.Minute),
+			MaxRetries:   1,
+		}},
+		LLMs: map[string]*LLMConfig{
+			"default":  {Provider: LLMProviderOpenAI, APIKey: "sk-test"},
+			"override": {Provider: LLMProviderOpenAI, APIKey: "sk-test", Temperature: &high, MaxTokens: 100},
+		},
+	}
+	cfg.SetDefaults()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	shared := cfg.LLMs["default"]
+	if *shared.Temperature != 0.2 || shared.MaxTokens != 2048 || shared.TotalTimeout != Duration(5*time.Minute) || shared.MaxRetries != 1 {
+		t.Errorf("shared = temperature %v, max_tokens %d, total_timeout %v, max_retries %d",
+			*shared.Temperature, shared.MaxTokens, shared.TotalTimeout, shared.MaxRetries)
+	}
+
+	// Per-LLM values win over llm_settings
+	override := cfg.LLMs["override"]
+	if *override.Temperature != 1.5 || override.MaxTokens != 100 || override.MaxRetries != 1 {
+		t.Errorf("override = temperature %v, max_tokens %d, max_retries %d",
+			*override.Temperature, override.MaxTokens, override.MaxRetries)
+	}
+
+	invalid := 3.0
+	cfg.Defaults.LLMSettings.Temperature = &invalid
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for out-of-range default temperature")
+	}
+}
+
+func TestAzureOpenAIAPIVersion(t *testing.T) {
+	newCfg := func(version string) *Config {
+		return &Config{LLMs: map[string]*LLMConfig{"default": {
+			Provider: LLMProviderAzureOpenAI,
+			Model:    "gpt-4o",
+			APIKey:   "key",
+			Azure:    &AzureOpenAIConfig{Endpoint: "https://myres.openai.azure.com", APIVersion: version},
+		}}}
+	}
+
+	for _, version := range []string{"", "2025-03-01-preview", "2025-04-01-preview", "2026-01-01", "preview"} {
+		cfg := newCfg(version)
+		cfg.SetDefaults()
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("api_version %q: Validate() error = %v", version, err)
+		}
+	}
+	for _, version := range []string{"2024-10-21", "2025-01-01-preview"} {
+		cfg := newCfg(version)
+		cfg.SetDefaults()
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "Responses API") {
+			t.Errorf("api_version %q: Validate() error = %v, want Responses API error", version, err)
+		}
+	}
+}
+
+func TestToolAllowedAgents(t *testing.T) {
+	cfg := &Config{
+		LLMs: map[string]*LLMConfig{
+			"default": {Provider: LLMProviderOpenAI, APIKey: "sk-test"},
+		},
+		Tools: map[string]*ToolConfig{
+			"execute_command": {Type: ToolTypeCommand, AllowedAgents: []string{"admin"}},
+		},
+		Agents: map[string]*AgentConfig{
+			"admin":     {LLM: "default"},
+			"assistant": {LLM: "default"},
+		},
+	}
+	cfg.SetDefaults()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	tool := cfg.Tools["execute_command"]
+	if !tool.AllowsAgent("admin") || tool.AllowsAgent("assistant") {
+		t.Errorf("AllowsAgent: admin = %v, assistant = %v", tool.AllowsAgent("admin"), tool.AllowsAgent("assistant"))
+	}
+	if !(&ToolConfig{}).AllowsAgent("assistant") {
+		t.Error("Expected empty allowed_agents to allow every agent")
+	}
+
+	tool.AllowedAgents = append(tool.AllowedAgents, "missing")
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error for undefined allowed agent")
+	}
+}
+
+func TestHTTPRequestToolConfig(t *testing.T) {
+	tool := &ToolConfig{Type: ToolTypeFunction, Handler: "http_request", AllowedHosts: []string{"api.example.com", "*.internal.example.com"}}
+	tool.SetDefaults()
+	if err := tool.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if !tool.NeedsApproval() {
+		t.Error("Expected http_request to require approval by default")
+	}
+
+	for _, host := range []string{"", "https://api.example.com", "api.example.com:443", "api.example.com/v1"} {
+		tool.AllowedHosts = []string{host}
+		if err := tool.Validate(); err == nil {
+			t.Errorf("Validate() error = nil for allowed host %q", host)
+		}
+	}
+}
+
+func TestSQLQueryToolConfig(t *testing.T) {
+	cfg := &Config{
+		LLMs: map[string]*LLMConfig{
+			"default": {Provider: LLMProviderOpenAI, APIKey: "sk-test"},
+		},
+		Databases: map[string]*DatabaseConfig{
+			"warehouse": {Driver: "postgres", Host: "localhost", Database: "dw", Username: "analyst"},
+		},
+		Tools: map[string]*ToolConfig{
+			"sql_query": {Type: ToolTypeFunction, Handler: "sql_query", Databases: []string{"warehouse"}},
+		},
+	}
+	cfg.SetDefaults()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if !cfg.Tools["sql_query"].NeedsApproval() {
+		t.Error("Expected sql_query to require approval by default")
+	}
+
+	for _, databases := range [][]string{nil, {"missing"}} {
+		cfg.Tools["sql_query"].Databases = databases
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Validate() error = nil for databases %v", databases)
+		}
+	}
+}
+
+func TestPgvectorStoreDatabase(t *testing.T) {
+	cfg := &Config{
+		LLMs: map[string]*LLMConfig{
+			"default": {Provider: LLMProviderOpenAI, APIKey: "sk-test"},
+		},
+		Databases: map[string]*DatabaseConfig{
+			"main":  {Driver: "postgres", Host: "localhost", Database: "hector", Username: "hector"},
+			"local": {Driver: "sqlite", Database: "./hector.db"},
+		},
+		VectorStores: map[string]*VectorStoreConfig{
+			"docs": {Type: "pgvector", Database: "main", Index: "ivfflat"},
+		},
+	}
+	cfg.SetDefaults()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	for _, store := range []*VectorStoreConfig{
+		{Type: "pgvector"},
+		{Type: "pgvector", Database: "missing"},
+		{Type: "pgvector", Database: "local"},
+		{Type: "pgvector", Database: "main", Index: "flat"},
+	} {
+		cfg.VectorStores["docs"] = store
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Validate() error = nil for %+v", store)
+		}
+	}
+}
+
+func TestDocumentStoreReranker(t *testing.T) {
+	cfg := &Config{
+		LLMs: map[string]*LLMConfig{
+			"default": {Provider: LLMProviderOpenAI, APIKey: "sk-test"},
+		},
+		DocumentStores: map[string]*DocumentStoreConfig{
+			"docs": {
+				Source: &DocumentSourceConfig{Type: "directory", Path: "./docs"},
+				Search: &DocumentSearchConfig{Reranker: &RerankerConfig{Type: "llm", Model: "default"}},
+			},
+		},
+	}
+	cfg.SetDefaults()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if topN := cfg.DocumentStores["docs"].Search.Reranker.TopN; topN != 20 {
+		t.Errorf("TopN = %d, want 20", topN)
+	}
+
+	for _, search := range []*DocumentSearchConfig{
+		{Reranker: &RerankerConfig{Type: "llm", Model: "missing"}},
+		{Reranker: &RerankerConfig{Type: "llm"}},
+		{Reranker: &RerankerConfig{Type: "cross_encoder"}},
+		{Reranker: &RerankerConfig{Type: "colbert", Endpoint: "http://localhost/rerank"}},
+		{Reranker: &RerankerConfig{Type: "cross_encoder", Endpoint: "http://localhost/rerank"}, EnableRerank: true, RerankLLM: "default"},
+	} {
+		cfg.DocumentStores["docs"].Search = search
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Validate() error = nil for %+v", search.Reranker)
+		}
+	}
+}
+
+func TestAgentTimeout(t *testing.T) {
+	cfg := &Config{
+		LLMs:   map[string]*LLMConfig{"default": {Provider: LLMProviderOpenAI, APIKey: "sk-test"}},
+		Agents: map[string]*AgentConfig{"assistant": {LLM: "default", Timeout: "90s"}},
+	}
+	cfg.SetDefaults()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if got := cfg.Agents["assistant"].RunTimeout(); got != 90*time.Second {
+		t.Errorf("RunTimeout() = %v, want 90s", got)
+	}
+	if got := (&AgentConfig{}).RunTimeout(); got != 0 {
+		t.Errorf("RunTimeout() without timeout = %v, want 0", got)
+	}
+
+	for _, timeout := range []string{"soon", "0s", "-1m"} {
+		cfg.Agents["assistant"].Timeout = timeout
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Expected error for timeout %q", timeout)
+		}
+	}
+}
+
+func TestAgentRequiredScopes(t *testing.T) {
+	cfg := &Config{
+		LLMs:   map[string]*LLMConfig{"default": {Provider: LLMProviderOpenAI, APIKey: "sk-test"}},
+		Agents: map[string]*AgentConfig{"reports": {LLM: "default", RequiredScopes: []string{"reports:read"}}},
+	}
+	cfg.SetDefaults()
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "server.auth is not enabled") {
+		t.Errorf("Validate() error = %v, want server.auth error", err)
+	}
+
+	cfg.Server.Auth = &AuthConfig{Enabled: true, APIKeys: &APIKeysConfig{Keys: []APIKeyConfig{{Key: "secret", Subject: "svc"}}}}
+	cfg.SetDefaults()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	for _, scope := range []string{"", " reports:read", "reports:read reports:write"} {
+		cfg.Agents["reports"].RequiredScopes = []string{scope}
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Expected error for required scope %q", scope)
+		}
+	}
+}
+
+func TestCircuitBreakerConfig(t *testing.T) {
+	cfg := &Config{
+		Agents: map[string]*AgentConfig{"specialist": {
+			Type:           "remote",
+			URL:            "http://localhost:9000",
+			CircuitBreaker: &CircuitBreakerConfig{FailureThreshold: 3},
+		}},
+	}
+	cfg.SetDefaults()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	cb := cfg.Agents["specialist"].CircuitBreaker
+	if cb.FailureThreshold != 3 || cb.Cooldown.Duration() != 30*time.Second || cb.HalfOpenMax != 1 {
+		t.Errorf("circuit breaker = %+v", cb)
+	}
+
+	cb.HalfOpenMax = -1
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for negative half_open_max")
+	}
+	cb.HalfOpenMax = 1
+	cfg.Agents["specialist"].Type = "llm"
+	if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "circuit_breaker") {
+		t.Errorf("Validate() error = %v, want circuit_breaker error for a non-remote agent", err)
+	}
+}
+
+func TestAgentRetryMaxRetriesZeroDisablesRetries(t *testing.T) {
+	zero := &AgentRetryConfig{MaxRetries: IntPtr(0)}
+	zero.SetDefaults()
+	if got := zero.Retries(); got != 0 {
+		t.Errorf("Retries() with max_retries: 0 = %d, want 0", got)
+	}
+
+	unset := &AgentRetryConfig{}
+	unset.SetDefaults()
+	if got := unset.Retries(); got != 3 {
+		t.Errorf("Retries() by default = %d, want 3", got)
+	}
+
+	remoteOnly := &AgentRetryConfig{RetryOn: []int{503}}
+	if err := remoteOnly.Validate("sequential"); err == nil {
+		t.Error("Validate() accepted retry_on for a workflow agent")
+	}
+	if err := remoteOnly.Validate("remote"); err != nil {
+		t.Errorf("Validate() for a remote agent error = %v", err)
+	}
+}
+
+func TestSearchDefaultsValidate(t *testing.T) {
+	for _, tt := range []struct {
+		cfg     SearchDefaultsConfig
+		wantErr bool
+	}{
+		{SearchDefaultsConfig{TopK: 5, MinScore: 0.75}, false},
+		{SearchDefaultsConfig{TopK: -1}, true},
+		{SearchDefaultsConfig{MinScore: 1.5}, true},
+	} {
+		if err := tt.cfg.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) error = %v, wantErr %v", tt.cfg, err, tt.wantErr)
+		}
+	}
+}
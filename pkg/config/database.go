@@ -15,7 +15,10 @@
 
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // DatabaseConfig holds configuration for SQL database connections.
 // Supports PostgreSQL, MySQL, and SQLite.
@@ -36,16 +39,31 @@ type DatabaseConfig struct {
 	Username string `yaml:"username,omitempty" json:"username,omitempty" jsonschema:"title=Username,description=Database username (not required for SQLite)"`
 
 	// Password for database authentication (not required for SQLite).
-	Password string `yaml:"password,omitempty" json:"password,omitempty" jsonschema:"title=Password,description=Database password (not required for SQLite)"`
+	Password string `yaml:"password,omitempty" json:"password,omitempty" jsonschema:"title=Password,description=Database password (not required for SQLite)" sensitive:"true"`
 
 	// SSLMode for PostgreSQL connections.
 	SSLMode string `yaml:"ssl_mode,omitempty" json:"ssl_mode,omitempty" jsonschema:"title=SSL Mode,description=SSL mode for PostgreSQL connections"`
 
 	// MaxConns is the maximum number of open connections.
+	// Ignored for SQLite, which always uses a single connection.
 	MaxConns int `yaml:"max_conns,omitempty" json:"max_conns,omitempty" jsonschema:"title=Max Open Connections,description=Maximum open connections,minimum=1,default=25"`
 
 	// MaxIdle is the maximum number of idle connections.
+	// Ignored for SQLite, which always uses a single connection.
 	MaxIdle int `yaml:"max_idle,omitempty" json:"max_idle,omitempty" jsonschema:"title=Max Idle Connections,description=Maximum idle connections,minimum=1,default=5"`
+
+	// ConnMaxLifetime closes connections after they have been open this long.
+	// Default: 1h
+	ConnMaxLifetime Duration `yaml:"conn_max_lifetime,omitempty" json:"conn_max_lifetime,omitempty" jsonschema:"title=Connection Max Lifetime,description=Maximum time a connection may be reused,default=1h"`
+
+	// ConnMaxIdleTime closes connections after they have been idle this long.
+	// 0 keeps idle connections open.
+	ConnMaxIdleTime Duration `yaml:"conn_max_idle_time,omitempty" json:"conn_max_idle_time,omitempty" jsonschema:"title=Connection Max Idle Time,description=Maximum time a connection may sit idle (0 = no limit)"`
+
+	// ReadReplicas are DSNs of read-only replicas of this database, in the
+	// driver's format. Session and task reads are spread across them;
+	// writes go to the primary. Not supported for SQLite.
+	ReadReplicas []string `yaml:"read_replicas,omitempty" json:"read_replicas,omitempty" jsonschema:"title=Read Replicas,description=DSNs of read-only replicas used for session and task reads" sensitive:"true"`
 }
 
 // SetDefaults applies default values to the database config.
@@ -56,6 +74,9 @@ func (c *DatabaseConfig) SetDefaults() {
 	if c.MaxIdle == 0 {
 		c.MaxIdle = 5
 	}
+	if c.ConnMaxLifetime == 0 {
+		c.ConnMaxLifetime = Duration(time.Hour)
+	}
 
 	// Default ports per driver
 	if c.Port == 0 {
@@ -108,6 +129,23 @@ func (c *DatabaseConfig) Validate() error {
 		return fmt.Errorf("max_idle must be non-negative")
 	}
 
+	if c.ConnMaxLifetime < 0 {
+		return fmt.Errorf("conn_max_lifetime must be non-negative")
+	}
+
+	if c.ConnMaxIdleTime < 0 {
+		return fmt.Errorf("conn_max_idle_time must be non-negative")
+	}
+
+	if len(c.ReadReplicas) > 0 && c.Dialect() == "sqlite" {
+		return fmt.Errorf("read_replicas is not supported for sqlite")
+	}
+	for i, dsn := range c.ReadReplicas {
+		if dsn == "" {
+			return fmt.Errorf("read_replicas[%d] is empty", i)
+		}
+	}
+
 	return nil
 }
 
@@ -141,6 +179,20 @@ func (c *DatabaseConfig) DSN() string {
 	}
 }
 
+// PoolName identifies the database in logs and metrics without the
+// password: the file path for SQLite, driver://user@host:port/database
+// otherwise. Databases that differ only in other options share a name;
+// DBPool tells their pools apart.
+func (c *DatabaseConfig) PoolName() string {
+	if c.Dialect() == "sqlite" {
+		return c.Database
+	}
+	if c.Username != "" {
+		return fmt.Sprintf("%s://%s@%s:%d/%s", c.Driver, c.Username, c.Host, c.Port, c.Database)
+	}
+	return fmt.Sprintf("%s://%s:%d/%s", c.Driver, c.Host, c.Port, c.Database)
+}
+
 // DriverName returns the normalized driver name for sql.Open().
 // Converts "sqlite" to "sqlite3" for the go-sqlite3 driver.
 func (c *DatabaseConfig) DriverName() string {
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
@@ -31,17 +32,89 @@ import (
 // DBPool manages shared database connections.
 // For SQLite, it ensures only one connection is used to prevent "database is locked" errors.
 type DBPool struct {
-	mu    sync.Mutex
-	pools map[string]*sql.DB
+	mu     sync.Mutex
+	pools  map[string]*sql.DB
+	names  map[string]string    // DSN -> pool name
+	routed map[string]*RoutedDB // primary DSN -> routed connections
 }
 
 // NewDBPool creates a new database pool manager.
 func NewDBPool() *DBPool {
 	return &DBPool{
-		pools: make(map[string]*sql.DB),
+		pools:  make(map[string]*sql.DB),
+		names:  make(map[string]string),
+		routed: make(map[string]*RoutedDB),
 	}
 }
 
+// replicaReadsKey marks a context whose point reads may use a replica.
+type replicaReadsKey struct{}
+
+// WithReplicaReads marks ctx as tolerating stale reads, so session and
+// task stores may serve gets from a read replica. Only read-only external
+// endpoints should use it; the runner and the task manager read their own
+// writes and must stay on the primary.
+func WithReplicaReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replicaReadsKey{}, true)
+}
+
+// ReplicaReadsAllowed reports whether ctx was marked by WithReplicaReads.
+func ReplicaReadsAllowed(ctx context.Context) bool {
+	allowed, _ := ctx.Value(replicaReadsKey{}).(bool)
+	return allowed
+}
+
+// RoutedDB sends reads to read replicas and writes to the primary.
+// Replicas are used in turn; without replicas, reads go to the primary.
+//
+// Replicas lag behind the primary, so a read right after a write may not
+// see it. Callers that must read their own writes should use Primary.
+type RoutedDB struct {
+	primary  *sql.DB
+	replicas []*sql.DB
+	next     atomic.Uint32
+}
+
+// Primary returns the primary connection pool.
+func (d *RoutedDB) Primary() *sql.DB {
+	return d.primary
+}
+
+// Replica returns the next replica connection pool, or the primary if no
+// replica is configured.
+func (d *RoutedDB) Replica() *sql.DB {
+	if len(d.replicas) == 0 {
+		return d.primary
+	}
+	n := d.next.Add(1)
+	return d.replicas[int(n-1)%len(d.replicas)]
+}
+
+// HasReplicas reports whether reads go to replicas.
+func (d *RoutedDB) HasReplicas() bool {
+	return len(d.replicas) > 0
+}
+
+// QueryContext runs a read query on a replica.
+func (d *RoutedDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return d.Replica().QueryContext(ctx, query, args...)
+}
+
+// QueryRowContext runs a single-row read query on a replica.
+func (d *RoutedDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return d.Replica().QueryRowContext(ctx, query, args...)
+}
+
+// ExecContext runs a write statement on the primary.
+func (d *RoutedDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return d.primary.ExecContext(ctx, query, args...)
+}
+
+// BeginTx starts a transaction on the primary.
+func (d *RoutedDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	return d.primary.BeginTx(ctx, opts)
+}
+
 // Get returns a database connection for the given config.
 // For the same DSN, it returns the same connection pool.
 func (p *DBPool) Get(cfg *DatabaseConfig) (*sql.DB, error) {
@@ -56,18 +129,81 @@ func (p *DBPool) Get(cfg *DatabaseConfig) (*sql.DB, error) {
 	}
 
 	// Create new pool
-	db, err := p.createPool(cfg)
+	db, err := p.createPool(cfg, dsn)
 	if err != nil {
 		return nil, err
 	}
 
 	p.pools[dsn] = db
+	p.names[dsn] = p.uniqueName(cfg.PoolName())
 	return db, nil
 }
 
-func (p *DBPool) createPool(cfg *DatabaseConfig) (*sql.DB, error) {
-	driverName := cfg.DriverName()
+// uniqueName returns name, or name#2, name#3... if another pool has it, so
+// the pools of databases that share a PoolName keep separate stats.
+// The caller must hold p.mu.
+func (p *DBPool) uniqueName(name string) string {
+	taken := make(map[string]bool, len(p.names))
+	for _, n := range p.names {
+		taken[n] = true
+	}
+	unique := name
+	for i := 2; taken[unique]; i++ {
+		unique = fmt.Sprintf("%s#%d", name, i)
+	}
+	return unique
+}
+
+// GetRouted returns the primary connection for the given config together
+// with its read replicas (see DatabaseConfig.ReadReplicas). Primary and
+// replica pools are shared like those returned by Get.
+func (p *DBPool) GetRouted(cfg *DatabaseConfig) (*RoutedDB, error) {
+	primary, err := p.Get(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	dsn := cfg.DSN()
+	if d, ok := p.routed[dsn]; ok {
+		return d, nil
+	}
+
+	d := &RoutedDB{primary: primary}
+	for i, replicaDSN := range cfg.ReadReplicas {
+		db, ok := p.pools[replicaDSN]
+		if !ok {
+			db, err = p.createPool(cfg, replicaDSN)
+			if err != nil {
+				return nil, fmt.Errorf("read replica %d: %w", i, err)
+			}
+			p.pools[replicaDSN] = db
+			p.names[replicaDSN] = p.uniqueName(fmt.Sprintf("%s#replica%d", p.names[dsn], i+1))
+		}
+		d.replicas = append(d.replicas, db)
+	}
+	p.routed[dsn] = d
+	return d, nil
+}
+
+// Stats returns the connection statistics of each pool by pool name:
+// DatabaseConfig.PoolName, with a #2, #3... suffix for pools whose
+// databases share a name.
+func (p *DBPool) Stats() map[string]sql.DBStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := make(map[string]sql.DBStats, len(p.pools))
+	for dsn, db := range p.pools {
+		stats[p.names[dsn]] = db.Stats()
+	}
+	return stats
+}
+
+func (p *DBPool) createPool(cfg *DatabaseConfig, dsn string) (*sql.DB, error) {
+	driverName := cfg.DriverName()
 
 	db, err := sql.Open(driverName, dsn)
 	if err != nil {
@@ -88,7 +224,14 @@ func (p *DBPool) createPool(cfg *DatabaseConfig) (*sql.DB, error) {
 			db.SetMaxIdleConns(cfg.MaxIdle)
 		}
 	}
-	db.SetConnMaxLifetime(time.Hour)
+	lifetime := cfg.ConnMaxLifetime.Duration()
+	if lifetime <= 0 {
+		lifetime = time.Hour
+	}
+	db.SetConnMaxLifetime(lifetime)
+	if idleTime := cfg.ConnMaxIdleTime.Duration(); idleTime > 0 {
+		db.SetConnMaxIdleTime(idleTime)
+	}
 
 	// Test connection
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -122,10 +265,12 @@ func (p *DBPool) Close() error {
 	var errs []error
 	for dsn, db := range p.pools {
 		if err := db.Close(); err != nil {
-			errs = append(errs, fmt.Errorf("failed to close %s: %w", dsn, err))
+			errs = append(errs, fmt.Errorf("failed to close %s: %w", p.names[dsn], err))
 		}
 	}
 	p.pools = make(map[string]*sql.DB)
+	p.names = make(map[string]string)
+	p.routed = make(map[string]*RoutedDB)
 
 	if len(errs) > 0 {
 		return fmt.Errorf("errors closing pools: %v", errs)
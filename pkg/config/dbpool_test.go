@@ -0,0 +1,81 @@
+package config
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestDBPoolGetRouted(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &DatabaseConfig{
+		Driver:       "sqlite",
+		Database:     filepath.Join(dir, "primary.db"),
+		ReadReplicas: []string{filepath.Join(dir, "replica.db")},
+	}
+	pool := NewDBPool()
+	defer pool.Close()
+
+	db, err := pool.GetRouted(cfg)
+	if err != nil {
+		t.Fatalf("GetRouted() error = %v", err)
+	}
+	if again, _ := pool.GetRouted(cfg); again != db {
+		t.Error("GetRouted() did not reuse the routed connection")
+	}
+	if primary, _ := pool.Get(cfg); primary != db.Primary() {
+		t.Error("Primary() is not the pooled primary")
+	}
+
+	ctx := context.Background()
+	if _, err := db.Replica().ExecContext(ctx, `CREATE TABLE t (v TEXT)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, `CREATE TABLE t (v TEXT)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.ExecContext(ctx, `INSERT INTO t VALUES ('primary')`); err != nil {
+		t.Fatalf("ExecContext() error = %v", err)
+	}
+
+	// Reads go to the replica, which has not seen the write
+	var n int
+	if err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM t`).Scan(&n); err != nil || n != 0 {
+		t.Errorf("replica rows = %d, %v; want 0", n, err)
+	}
+	if err := db.Primary().QueryRowContext(ctx, `SELECT COUNT(*) FROM t`).Scan(&n); err != nil || n != 1 {
+		t.Errorf("primary rows = %d, %v; want 1", n, err)
+	}
+	if len(pool.Stats()) != 2 {
+		t.Errorf("Stats() = %v, want primary and replica", pool.Stats())
+	}
+
+	// Without replicas, reads go to the primary
+	single, err := pool.GetRouted(&DatabaseConfig{Driver: "sqlite", Database: filepath.Join(dir, "single.db")})
+	if err != nil {
+		t.Fatalf("GetRouted() error = %v", err)
+	}
+	if single.HasReplicas() || single.Replica() != single.Primary() {
+		t.Error("Replica() should fall back to the primary")
+	}
+}
+
+func TestDBPoolNamesArePerPool(t *testing.T) {
+	a := &DatabaseConfig{Driver: "postgres", Host: "db", Port: 5432, Database: "hector", Username: "app", SSLMode: "disable"}
+	b := &DatabaseConfig{Driver: "postgres", Host: "db", Port: 5432, Database: "hector", Username: "reporting"}
+	if a.PoolName() == b.PoolName() {
+		t.Errorf("PoolName() = %q for different users", a.PoolName())
+	}
+
+	// Databases that differ only in options keep separate stats
+	pool := NewDBPool()
+	pool.names["dsn-a"] = pool.uniqueName(a.PoolName())
+	pool.names["dsn-b"] = pool.uniqueName(a.PoolName())
+	pool.names["dsn-c"] = pool.uniqueName(a.PoolName())
+	want := []string{"postgres://app@db:5432/hector", "postgres://app@db:5432/hector#2", "postgres://app@db:5432/hector#3"}
+	for i, dsn := range []string{"dsn-a", "dsn-b", "dsn-c"} {
+		if got := pool.names[dsn]; got != want[i] {
+			t.Errorf("name of %s = %q, want %q", dsn, got, want[i])
+		}
+	}
+}
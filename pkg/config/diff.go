@@ -0,0 +1,148 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// ChangeKind describes how a named config entry differs between two configs.
+type ChangeKind string
+
+const (
+	ChangeAdded   ChangeKind = "added"
+	ChangeRemoved ChangeKind = "removed"
+	ChangeChanged ChangeKind = "changed"
+)
+
+// EntryChange is one added, removed or changed entry in a config section.
+type EntryChange struct {
+	// Section is the top-level key holding the entry ("agents", "llms", "tools").
+	Section string `json:"section"`
+
+	// Name is the entry's key within the section.
+	Name string `json:"name"`
+
+	// Kind is how the entry changed.
+	Kind ChangeKind `json:"kind"`
+
+	// Fields lists the dotted paths of the settings that differ, for
+	// changed entries. Values are left out since they may hold secrets.
+	Fields []string `json:"fields,omitempty"`
+}
+
+// ConfigDiff is the structured difference between two configs.
+type ConfigDiff struct {
+	Changes []EntryChange `json:"changes"`
+}
+
+// Changed reports whether any entries were added, removed or changed.
+func (d *ConfigDiff) Changed() bool {
+	return len(d.Changes) > 0
+}
+
+// Diff compares the agents, LLMs and tools of two configs. Changes are
+// ordered by section, then by name.
+func Diff(old, new *Config) *ConfigDiff {
+	diff := &ConfigDiff{Changes: []EntryChange{}}
+	diff.Changes = append(diff.Changes, diffSection("agents", old.Agents, new.Agents)...)
+	diff.Changes = append(diff.Changes, diffSection("llms", old.LLMs, new.LLMs)...)
+	diff.Changes = append(diff.Changes, diffSection("tools", old.Tools, new.Tools)...)
+	return diff
+}
+
+func diffSection[T any](section string, old, new map[string]*T) []EntryChange {
+	names := make(map[string]struct{}, len(old)+len(new))
+	for name := range old {
+		names[name] = struct{}{}
+	}
+	for name := range new {
+		names[name] = struct{}{}
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var changes []EntryChange
+	for _, name := range sorted {
+		before, inOld := old[name]
+		after, inNew := new[name]
+		switch {
+		case !inOld:
+			changes = append(changes, EntryChange{Section: section, Name: name, Kind: ChangeAdded})
+		case !inNew:
+			changes = append(changes, EntryChange{Section: section, Name: name, Kind: ChangeRemoved})
+		default:
+			if fields := diffFields("", toGeneric(before), toGeneric(after)); len(fields) > 0 {
+				changes = append(changes, EntryChange{Section: section, Name: name, Kind: ChangeChanged, Fields: fields})
+			}
+		}
+	}
+	return changes
+}
+
+// toGeneric converts a config entry to its JSON map form so entries can be
+// compared field by field using their config key names.
+func toGeneric(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil
+	}
+	return out
+}
+
+// diffFields returns the sorted dotted paths at which a and b differ.
+// Nested objects are descended into; any other differing value, including
+// lists, is reported at its own path.
+func diffFields(prefix string, a, b any) []string {
+	am, aIsMap := a.(map[string]any)
+	bm, bIsMap := b.(map[string]any)
+	if !aIsMap || !bIsMap {
+		if reflect.DeepEqual(a, b) {
+			return nil
+		}
+		if prefix == "" {
+			return []string{"."}
+		}
+		return []string{prefix}
+	}
+
+	keys := make(map[string]struct{}, len(am)+len(bm))
+	for k := range am {
+		keys[k] = struct{}{}
+	}
+	for k := range bm {
+		keys[k] = struct{}{}
+	}
+	var fields []string
+	for k := range keys {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		fields = append(fields, diffFields(path, am[k], bm[k])...)
+	}
+	sort.Strings(fields)
+	return fields
+}
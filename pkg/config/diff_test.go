@@ -0,0 +1,67 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	temp := 0.3
+	old := &Config{
+		LLMs: map[string]*LLMConfig{
+			"default": {Provider: "openai", Model: "gpt-4o"},
+		},
+		Agents: map[string]*AgentConfig{
+			"keep":    {LLM: "default", Instruction: "hi"},
+			"edit":    {LLM: "default", Instruction: "hi"},
+			"dropped": {LLM: "default"},
+		},
+	}
+	updated := &Config{
+		LLMs: map[string]*LLMConfig{
+			"default": {Provider: "openai", Model: "gpt-4o-mini", Temperature: &temp},
+		},
+		Agents: map[string]*AgentConfig{
+			"keep":  {LLM: "default", Instruction: "hi"},
+			"edit":  {LLM: "default", Instruction: "hello"},
+			"fresh": {LLM: "default"},
+		},
+		Tools: map[string]*ToolConfig{
+			"search": {Type: "function"},
+		},
+	}
+
+	diff := Diff(old, updated)
+	want := []EntryChange{
+		{Section: "agents", Name: "dropped", Kind: ChangeRemoved},
+		{Section: "agents", Name: "edit", Kind: ChangeChanged, Fields: []string{"instruction"}},
+		{Section: "agents", Name: "fresh", Kind: ChangeAdded},
+		{Section: "llms", Name: "default", Kind: ChangeChanged, Fields: []string{"model", "temperature"}},
+		{Section: "tools", Name: "search", Kind: ChangeAdded},
+	}
+	if !reflect.DeepEqual(diff.Changes, want) {
+		t.Errorf("Changes = %+v, want %+v", diff.Changes, want)
+	}
+
+	if Diff(old, old).Changed() {
+		t.Error("Diff of a config with itself reports changes")
+	}
+}
+
+func TestSchemaType(t *testing.T) {
+	v, ok := SchemaType("AgentConfig")
+	if !ok {
+		t.Fatal("AgentConfig not found")
+	}
+	if _, isAgent := v.(*AgentConfig); !isAgent {
+		t.Errorf("SchemaType(AgentConfig) = %T, want *AgentConfig", v)
+	}
+
+	// Nested structs are reachable too.
+	if _, ok := SchemaType("ReasoningConfig"); !ok {
+		t.Error("ReasoningConfig not found")
+	}
+	if _, ok := SchemaType("agentconfig"); ok {
+		t.Error("lookup should be case-sensitive")
+	}
+}
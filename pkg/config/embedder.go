@@ -34,25 +34,32 @@ import "fmt"
 //	    provider: ollama
 //	    model: nomic-embed-text
 //	    base_url: http://localhost:11434
+//
+//	  retrieval:
+//	    provider: voyage
+//	    model: voyage-3.5
+//	    api_key: ${VOYAGE_API_KEY}
 type EmbedderConfig struct {
 	// Provider specifies the embedding service.
-	// Values: "openai", "ollama", "cohere"
+	// Values: "openai", "ollama", "cohere", "voyage"
 	Provider string `yaml:"provider,omitempty"`
 
 	// Model is the embedding model name.
 	// OpenAI: "text-embedding-3-small", "text-embedding-3-large"
 	// Ollama: "nomic-embed-text", "all-minilm:l6-v2"
 	// Cohere: "embed-english-v3.0", "embed-multilingual-v3.0", "embed-v4.0"
+	// Voyage: "voyage-3.5", "voyage-3.5-lite", "voyage-3-large", "voyage-code-3"
 	Model string `yaml:"model,omitempty"`
 
-	// APIKey for the embedding provider (OpenAI and Cohere require this).
+	// APIKey for the embedding provider (OpenAI, Cohere and Voyage require this).
 	// Can use environment variable expansion: ${OPENAI_API_KEY}
-	APIKey string `yaml:"api_key,omitempty"`
+	APIKey string `yaml:"api_key,omitempty" sensitive:"true"`
 
 	// BaseURL for the API endpoint.
 	// OpenAI default: https://api.openai.com/v1
 	// Ollama default: http://localhost:11434
 	// Cohere default: https://api.cohere.com
+	// Voyage default: https://api.voyageai.com/v1
 	BaseURL string `yaml:"base_url,omitempty"`
 
 	// Dimension of the embedding vectors (auto-detected if 0).
@@ -61,7 +68,8 @@ type EmbedderConfig struct {
 	// Timeout in seconds for API requests (default: 30).
 	Timeout int `yaml:"timeout,omitempty"`
 
-	// BatchSize for batch embedding requests (default: 100 for OpenAI/Ollama, 96 for Cohere).
+	// BatchSize for batch embedding requests (default: 100 for OpenAI/Ollama,
+	// 96 for Cohere, 128 for Voyage, which accepts up to 1000).
 	BatchSize int `yaml:"batch_size,omitempty"`
 
 	// EncodingFormat for OpenAI API (optional).
@@ -76,10 +84,13 @@ type EmbedderConfig struct {
 	// InputType for Cohere v3+ models (required).
 	// Values: "search_document", "search_query", "classification", "clustering"
 	// Default: "search_document"
+	//
+	// InputType for Voyage applies to indexed text; search queries always
+	// use "query". Values: "document" (default), "query", "none"
 	InputType string `yaml:"input_type,omitempty"`
 
-	// OutputDimension for Cohere v4+ models (optional).
-	// Values: 256, 512, 1024, 1536
+	// OutputDimension for Cohere v4+ and Voyage models (optional).
+	// Values: 256, 512, 1024, 1536 (Cohere); 256, 512, 1024, 2048 (Voyage)
 	// If set, overrides model's default dimension.
 	OutputDimension int `yaml:"output_dimension,omitempty"`
 
@@ -102,6 +113,8 @@ func (c *EmbedderConfig) SetDefaults() {
 			c.Model = "nomic-embed-text"
 		case "cohere":
 			c.Model = "embed-english-v3.0"
+		case "voyage":
+			c.Model = "voyage-3.5"
 		default:
 			c.Model = "nomic-embed-text"
 		}
@@ -115,6 +128,8 @@ func (c *EmbedderConfig) SetDefaults() {
 			c.BaseURL = "http://localhost:11434"
 		case "cohere":
 			c.BaseURL = "https://api.cohere.com"
+		case "voyage":
+			c.BaseURL = "https://api.voyageai.com/v1"
 		}
 	}
 
@@ -149,6 +164,17 @@ func (c *EmbedderConfig) SetDefaults() {
 			default:
 				c.Dimension = 1024
 			}
+		case "voyage":
+			switch {
+			case c.OutputDimension > 0:
+				c.Dimension = c.OutputDimension
+			case c.Model == "voyage-3-lite":
+				c.Dimension = 512
+			case c.Model == "voyage-code-2":
+				c.Dimension = 1536
+			default:
+				c.Dimension = 1024 // voyage-3.5, voyage-3-large, voyage-code-3, etc.
+			}
 		}
 	}
 
@@ -160,11 +186,17 @@ func (c *EmbedderConfig) SetDefaults() {
 		switch c.Provider {
 		case "cohere":
 			c.BatchSize = 96 // Cohere's maximum per request
+		case "voyage":
+			c.BatchSize = 128 // Stays under Voyage's per-request token limit
 		default:
 			c.BatchSize = 100
 		}
 	}
 
+	if c.Provider == "voyage" && c.InputType == "" {
+		c.InputType = "document"
+	}
+
 	// Cohere-specific defaults
 	if c.Provider == "cohere" {
 		if c.InputType == "" {
@@ -182,13 +214,14 @@ func (c *EmbedderConfig) Validate() error {
 		"openai": true,
 		"ollama": true,
 		"cohere": true,
+		"voyage": true,
 	}
 
 	if !validProviders[c.Provider] {
-		return fmt.Errorf("invalid provider %q (valid: openai, ollama, cohere)", c.Provider)
+		return fmt.Errorf("invalid provider %q (valid: openai, ollama, cohere, voyage)", c.Provider)
 	}
 
-	if (c.Provider == "openai" || c.Provider == "cohere") && c.APIKey == "" {
+	if c.Provider != "ollama" && c.APIKey == "" {
 		return fmt.Errorf("api_key is required for %s embedder", c.Provider)
 	}
 
@@ -200,6 +233,24 @@ func (c *EmbedderConfig) Validate() error {
 		return fmt.Errorf("dimension must be positive")
 	}
 
+	if c.Provider == "openai" && c.BatchSize > 2048 {
+		return fmt.Errorf("batch_size %d exceeds OpenAI's limit of 2048", c.BatchSize)
+	}
+
+	// Voyage-specific validation
+	if c.Provider == "voyage" {
+		if c.InputType != "" && c.InputType != "document" && c.InputType != "query" && c.InputType != "none" {
+			return fmt.Errorf("invalid input_type %q for Voyage (valid: document, query, none)", c.InputType)
+		}
+		if c.OutputDimension > 0 && c.OutputDimension != 256 && c.OutputDimension != 512 &&
+			c.OutputDimension != 1024 && c.OutputDimension != 2048 {
+			return fmt.Errorf("invalid output_dimension %d for Voyage (valid: 256, 512, 1024, 2048)", c.OutputDimension)
+		}
+		if c.BatchSize > 1000 {
+			return fmt.Errorf("batch_size %d exceeds Voyage's limit of 1000", c.BatchSize)
+		}
+	}
+
 	// Cohere-specific validation
 	if c.Provider == "cohere" {
 		validInputTypes := map[string]bool{
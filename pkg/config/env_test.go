@@ -0,0 +1,90 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestExpandEnvVars(t *testing.T) {
+	t.Setenv("ENV_TEST_SET", "value")
+	t.Setenv("ENV_TEST_EMPTY", "")
+
+	input := map[string]any{
+		"plain":    "${ENV_TEST_SET}",
+		"short":    "$ENV_TEST_SET",
+		"fallback": "${ENV_TEST_UNSET:-dflt}",
+		"empty":    "${ENV_TEST_EMPTY:-dflt}",
+		"set":      "${ENV_TEST_SET:-dflt}",
+		"required": "${ENV_TEST_SET:?needed}",
+		"nested":   map[string]any{"list": []any{"x-${ENV_TEST_SET}"}},
+	}
+	got, err := expandEnvVars(input)
+	if err != nil {
+		t.Fatalf("expandEnvVars: %v", err)
+	}
+	want := map[string]any{
+		"plain":    "value",
+		"short":    "value",
+		"fallback": "dflt",
+		"empty":    "dflt",
+		"set":      "value",
+		"required": "value",
+		"nested":   map[string]any{"list": []any{"x-value"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expandEnvVars = %v, want %v", got, want)
+	}
+}
+
+func TestExpandEnvVarsMissingRequired(t *testing.T) {
+	t.Setenv("ENV_TEST_EMPTY", "")
+
+	input := map[string]any{
+		"llms": map[string]any{
+			"default": map[string]any{
+				"api_key":  "${ENV_TEST_UNSET:?set your API key}",
+				"base_url": "http://${ENV_TEST_EMPTY:?}/v1",
+			},
+		},
+	}
+	_, err := expandEnvVars(input)
+
+	var missing *MissingEnvError
+	if !errors.As(err, &missing) {
+		t.Fatalf("err = %v, want *MissingEnvError", err)
+	}
+	want := []MissingEnvVar{
+		{Name: "ENV_TEST_UNSET", Message: "set your API key", Path: "llms.default.api_key"},
+		{Name: "ENV_TEST_EMPTY", Message: "required but not set", Path: "llms.default.base_url"},
+	}
+	if !reflect.DeepEqual(missing.Vars, want) {
+		t.Errorf("Vars = %+v, want %+v", missing.Vars, want)
+	}
+}
+
+func TestLoadConfigFileMissingRequiredEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `
+llms:
+  default:
+    provider: openai
+    model: gpt-4o
+    api_key: ${ENV_TEST_UNSET_KEY:?export your OpenAI key}
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err := LoadConfigFile(context.Background(), path)
+	var missing *MissingEnvError
+	if !errors.As(err, &missing) || len(missing.Vars) != 1 {
+		t.Fatalf("err = %v, want one missing env var", err)
+	}
+	if missing.Vars[0].Name != "ENV_TEST_UNSET_KEY" {
+		t.Errorf("Name = %q, want ENV_TEST_UNSET_KEY", missing.Vars[0].Name)
+	}
+}
@@ -18,6 +18,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
+	"time"
 )
 
 // LLMProvider identifies the LLM provider type.
@@ -28,18 +30,25 @@ const (
 	LLMProviderOpenAI    LLMProvider = "openai"
 	LLMProviderGemini    LLMProvider = "gemini"
 	LLMProviderOllama    LLMProvider = "ollama"
+	LLMProviderCohere    LLMProvider = "cohere"
+
+	// LLMProviderAzureOpenAI is OpenAI served from an Azure OpenAI resource.
+	LLMProviderAzureOpenAI LLMProvider = "azure_openai"
+
+	// LLMProviderBedrock is Anthropic Claude served from AWS Bedrock.
+	LLMProviderBedrock LLMProvider = "bedrock"
 )
 
 // LLMConfig configures an LLM provider.
 type LLMConfig struct {
-	// Provider type (anthropic, openai, gemini, ollama).
-	Provider LLMProvider `yaml:"provider,omitempty" json:"provider,omitempty" jsonschema:"title=Provider,description=LLM provider,enum=anthropic,enum=openai,enum=gemini,enum=ollama,default=anthropic"`
+	// Provider type (anthropic, openai, gemini, ollama, cohere, azure_openai, bedrock).
+	Provider LLMProvider `yaml:"provider,omitempty" json:"provider,omitempty" jsonschema:"title=Provider,description=LLM provider,enum=anthropic,enum=openai,enum=gemini,enum=ollama,enum=cohere,enum=azure_openai,enum=bedrock,default=anthropic"`
 
 	// Model name (e.g., "claude-sonnet-4-20250514", "gpt-4o").
 	Model string `yaml:"model,omitempty" json:"model,omitempty" jsonschema:"title=Model,description=Model identifier"`
 
 	// APIKey for authentication. Supports ${VAR} expansion.
-	APIKey string `yaml:"api_key,omitempty" json:"api_key,omitempty" jsonschema:"title=API Key,description=API key for authentication (use ${ENV_VAR})"`
+	APIKey string `yaml:"api_key,omitempty" json:"api_key,omitempty" jsonschema:"title=API Key,description=API key for authentication (use ${ENV_VAR})" sensitive:"true"`
 
 	// BaseURL overrides the default API endpoint.
 	BaseURL string `yaml:"base_url,omitempty" json:"base_url,omitempty" jsonschema:"title=Base URL,description=Custom base URL for API endpoint"`
@@ -56,6 +65,192 @@ type LLMConfig struct {
 
 	// Thinking enables extended thinking (Claude).
 	Thinking *ThinkingConfig `yaml:"thinking,omitempty" json:"thinking,omitempty" jsonschema:"title=Thinking Configuration,description=Extended thinking configuration (Claude)"`
+
+	// StoredResponses chains turns server-side so follow-up turns only send new messages (OpenAI).
+	StoredResponses *StoredResponsesConfig `yaml:"stored_responses,omitempty" json:"stored_responses,omitempty" jsonschema:"title=Stored Responses,description=Chain turns via stored responses and previous_response_id (OpenAI)"`
+
+	// Azure configures the Azure OpenAI resource (provider azure_openai).
+	Azure *AzureOpenAIConfig `yaml:"azure,omitempty" json:"azure,omitempty" jsonschema:"title=Azure OpenAI,description=Azure OpenAI endpoint and deployment (provider azure_openai)"`
+
+	// Bedrock configures the AWS region and credentials (provider bedrock).
+	Bedrock *BedrockConfig `yaml:"bedrock,omitempty" json:"bedrock,omitempty" jsonschema:"title=AWS Bedrock,description=AWS region and credentials (provider bedrock)"`
+
+	// Roles overrides the role names sent to the provider, for
+	// OpenAI-compatible servers that expect non-standard roles.
+	Roles *RolesConfig `yaml:"roles,omitempty" json:"roles,omitempty" jsonschema:"title=Roles,description=Role names sent to the provider"`
+
+	// SystemMessages controls how system messages that arrive after the first
+	// turn are sent: "merge" (into the instruction), "user" (as user messages)
+	// or "native" (provider system role; openai, ollama and cohere only).
+	// Default: native for openai and ollama, merge otherwise.
+	SystemMessages string `yaml:"system_messages,omitempty" json:"system_messages,omitempty" jsonschema:"title=System Messages,description=How mid-conversation system messages are sent,enum=merge,enum=user,enum=native"`
+
+	// Shadow references another LLM that receives a copy of every request in
+	// the background. Responses always come from this LLM; the shadow's
+	// latency, token usage and output are only logged for comparison.
+	Shadow string `yaml:"shadow,omitempty" json:"shadow,omitempty" jsonschema:"title=Shadow LLM,description=LLM to mirror requests to for comparison (output is discarded)"`
+
+	// ShadowSimilarity logs a word-overlap similarity score (0-1) between
+	// this LLM's and the shadow's output.
+	ShadowSimilarity bool `yaml:"shadow_similarity,omitempty" json:"shadow_similarity,omitempty" jsonschema:"title=Shadow Similarity,description=Log output similarity between primary and shadow,default=false"`
+
+	// ConnectTimeout bounds connection establishment (dial and TLS handshake).
+	// Default: 10s.
+	ConnectTimeout Duration `yaml:"connect_timeout,omitempty" json:"connect_timeout,omitempty" jsonschema:"title=Connect Timeout,description=Bound on dialing and TLS handshake (e.g. 5s),default=10s"`
+
+	// TotalTimeout bounds each request from connect to the last byte.
+	// Default: 120s (300s for ollama) for non-streaming requests; streaming
+	// requests are only bounded when this is set, so long streams aren't cut off.
+	TotalTimeout Duration `yaml:"total_timeout,omitempty" json:"total_timeout,omitempty" jsonschema:"title=Total Timeout,description=Bound on the whole request including streaming (e.g. 10m)"`
+
+	// MaxRetries is the number of retries of requests that fail with a rate
+	// limit or server error. Not used by gemini. Default: 3.
+	MaxRetries int `yaml:"max_retries,omitempty" json:"max_retries,omitempty" jsonschema:"title=Max Retries,description=Retries of rate-limited or failed requests,minimum=0,default=3"`
+
+	// Deduplicate shares one in-flight call between concurrent identical
+	// requests (same messages, tools and generation config), so traffic
+	// spikes on the same question don't multiply cost.
+	Deduplicate bool `yaml:"deduplicate,omitempty" json:"deduplicate,omitempty" jsonschema:"title=Deduplicate,description=Share in-flight calls between concurrent identical requests,default=false"`
+
+	// Pricing is used to estimate per-session cost from token usage.
+	Pricing *PricingConfig `yaml:"pricing,omitempty" json:"pricing,omitempty" jsonschema:"title=Pricing,description=Per-token pricing for cost estimation"`
+}
+
+// PricingConfig sets the price of 1000 tokens, in any currency.
+//
+// Example YAML:
+//
+//	pricing:
+//	  input_per_1k: 0.0025
+//	  output_per_1k: 0.01
+type PricingConfig struct {
+	// InputPer1K is the price of 1000 input (prompt) tokens.
+	InputPer1K float64 `yaml:"input_per_1k,omitempty" json:"input_per_1k,omitempty" jsonschema:"title=Input Price per 1K,description=Price of 1000 input tokens,minimum=0"`
+
+	// OutputPer1K is the price of 1000 output (completion) tokens.
+	OutputPer1K float64 `yaml:"output_per_1k,omitempty" json:"output_per_1k,omitempty" jsonschema:"title=Output Price per 1K,description=Price of 1000 output tokens,minimum=0"`
+}
+
+// Cost estimates the price of the given token counts.
+func (p *PricingConfig) Cost(inputTokens, outputTokens int) float64 {
+	if p == nil {
+		return 0
+	}
+	return float64(inputTokens)/1000*p.InputPer1K + float64(outputTokens)/1000*p.OutputPer1K
+}
+
+// AzureOpenAIConfig configures an Azure OpenAI deployment.
+//
+// Requests authenticate with api_key (sent in the api-key header) or, as an
+// alternative, with an Entra ID (AAD) token: either a static ad_token or a
+// service principal (tenant_id, client_id, client_secret).
+//
+// Example YAML:
+//
+//	llms:
+//	  azure:
+//	    provider: azure_openai
+//	    model: gpt-4o
+//	    api_key: ${AZURE_OPENAI_API_KEY}
+//	    azure:
+//	      endpoint: https://myresource.openai.azure.com
+//	      deployment: gpt-4o-prod
+//	      api_version: 2025-04-01-preview
+type AzureOpenAIConfig struct {
+	// Endpoint is the resource endpoint.
+	// Default: $AZURE_OPENAI_ENDPOINT
+	Endpoint string `yaml:"endpoint,omitempty" json:"endpoint,omitempty" jsonschema:"title=Endpoint,description=Azure OpenAI resource endpoint (e.g. https://myresource.openai.azure.com)"`
+
+	// Deployment is the deployment name. Default: the model name.
+	Deployment string `yaml:"deployment,omitempty" json:"deployment,omitempty" jsonschema:"title=Deployment,description=Deployment name (defaults to the model)"`
+
+	// APIVersion is the api-version query parameter. The Responses API
+	// requires 2025-03-01-preview or later.
+	// Default: 2025-04-01-preview
+	APIVersion string `yaml:"api_version,omitempty" json:"api_version,omitempty" jsonschema:"title=API Version,description=Azure OpenAI api-version (2025-03-01-preview or later),default=2025-04-01-preview"`
+
+	// ADToken is a static Entra ID bearer token, used instead of api_key.
+	ADToken string `yaml:"ad_token,omitempty" json:"ad_token,omitempty" jsonschema:"title=AD Token,description=Entra ID bearer token (use ${ENV_VAR})" sensitive:"true"`
+
+	// TenantID, ClientID and ClientSecret authenticate a service principal
+	// with Entra ID, used instead of api_key.
+	TenantID     string `yaml:"tenant_id,omitempty" json:"tenant_id,omitempty" jsonschema:"title=Tenant ID,description=Entra ID tenant for service principal auth"`
+	ClientID     string `yaml:"client_id,omitempty" json:"client_id,omitempty" jsonschema:"title=Client ID,description=Service principal client ID"`
+	ClientSecret string `yaml:"client_secret,omitempty" json:"client_secret,omitempty" jsonschema:"title=Client Secret,description=Service principal client secret (use ${ENV_VAR})" sensitive:"true"`
+}
+
+// azureMinResponsesAPIVersion is the first Azure OpenAI api-version that
+// serves the Responses API.
+const azureMinResponsesAPIVersion = "2025-03-01-preview"
+
+// azureSupportsResponses reports whether an api-version serves the
+// Responses API. Dated versions (YYYY-MM-DD[-preview]) are compared with
+// the first one that does; other values such as "preview" are passed
+// through for Azure to check.
+func azureSupportsResponses(version string) bool {
+	if len(version) < 10 {
+		return true
+	}
+	if _, err := time.Parse("2006-01-02", version[:10]); err != nil {
+		return true
+	}
+	return version >= azureMinResponsesAPIVersion
+}
+
+// usesADAuth reports whether Entra ID auth is configured.
+func (c *AzureOpenAIConfig) usesADAuth() bool {
+	return c != nil && (c.ADToken != "" || c.ClientSecret != "")
+}
+
+// BedrockConfig configures Claude on AWS Bedrock.
+//
+// The model is a Bedrock model ID or inference profile of an Anthropic
+// model. Requests are signed with AWS credentials: the static keys below
+// if set, otherwise AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY, then the
+// shared credentials file (~/.aws/credentials).
+//
+// Example YAML:
+//
+//	llms:
+//	  claude:
+//	    provider: bedrock
+//	    model: anthropic.claude-3-5-sonnet-20240620-v1:0
+//	    bedrock:
+//	      region: us-east-1
+//	      profile: prod
+type BedrockConfig struct {
+	// Region is the AWS region.
+	// Default: $AWS_REGION, then $AWS_DEFAULT_REGION
+	Region string `yaml:"region,omitempty" json:"region,omitempty" jsonschema:"title=Region,description=AWS region (e.g. us-east-1)"`
+
+	// Profile selects the shared credentials file profile.
+	// Default: $AWS_PROFILE, then "default"
+	Profile string `yaml:"profile,omitempty" json:"profile,omitempty" jsonschema:"title=Profile,description=Shared credentials profile"`
+
+	// AccessKeyID, SecretAccessKey and SessionToken are static credentials,
+	// used instead of the environment and shared credentials file.
+	AccessKeyID     string `yaml:"access_key_id,omitempty" json:"access_key_id,omitempty" jsonschema:"title=Access Key ID,description=AWS access key ID (use ${ENV_VAR})"`
+	SecretAccessKey string `yaml:"secret_access_key,omitempty" json:"secret_access_key,omitempty" jsonschema:"title=Secret Access Key,description=AWS secret access key (use ${ENV_VAR})" sensitive:"true"`
+	SessionToken    string `yaml:"session_token,omitempty" json:"session_token,omitempty" jsonschema:"title=Session Token,description=AWS session token for temporary credentials (use ${ENV_VAR})" sensitive:"true"`
+}
+
+// RolesConfig maps message roles to provider role names.
+// Empty fields keep the provider's defaults.
+//
+// Example YAML:
+//
+//	roles:
+//	  agent: assistant
+//	  system: developer
+type RolesConfig struct {
+	// User is the role for user messages.
+	User string `yaml:"user,omitempty" json:"user,omitempty" jsonschema:"title=User Role,description=Role name for user messages"`
+
+	// Agent is the role for agent messages.
+	Agent string `yaml:"agent,omitempty" json:"agent,omitempty" jsonschema:"title=Agent Role,description=Role name for agent messages"`
+
+	// System is the role for system messages sent in native mode.
+	System string `yaml:"system,omitempty" json:"system,omitempty" jsonschema:"title=System Role,description=Role name for system messages"`
 }
 
 // ThinkingConfig configures extended thinking (Claude).
@@ -65,6 +260,33 @@ type ThinkingConfig struct {
 
 	// BudgetTokens is the token budget for thinking.
 	BudgetTokens int `yaml:"budget_tokens,omitempty" json:"budget_tokens,omitempty" jsonschema:"title=Budget Tokens,description=Token budget for thinking,minimum=1,default=1024"`
+
+	// Summary sets reasoning summary verbosity (OpenAI): none, auto, concise
+	// or detailed. Detailed summaries require a verified organization; if the
+	// API refuses them, requests are retried without a summary.
+	// Default: auto
+	Summary string `yaml:"summary,omitempty" json:"summary,omitempty" jsonschema:"title=Reasoning Summary,description=Reasoning summary verbosity (OpenAI),enum=none,enum=auto,enum=concise,enum=detailed,default=auto"`
+}
+
+// StoredResponsesConfig configures OpenAI stored responses.
+//
+// When enabled, responses are stored server-side (store: true) and follow-up
+// turns reference the previous response via previous_response_id, sending only
+// the new messages. If history was rewritten (e.g., trimmed) or the stored
+// response is gone, the full history is resent.
+//
+// Example YAML:
+//
+//	stored_responses:
+//	  enabled: true
+//	  ttl: 24h
+type StoredResponsesConfig struct {
+	// Enabled turns on stored response chaining.
+	Enabled *bool `yaml:"enabled,omitempty" json:"enabled,omitempty" jsonschema:"title=Enabled,description=Enable stored response chaining,default=true"`
+
+	// TTL is how long a stored response is reused before resending full history.
+	// Default: 720h (OpenAI's 30 day retention)
+	TTL Duration `yaml:"ttl,omitempty" json:"ttl,omitempty" jsonschema:"title=TTL,description=How long a stored response is reused (e.g. 24h),default=720h"`
 }
 
 // SetDefaults applies default values.
@@ -85,11 +307,41 @@ func (c *LLMConfig) SetDefaults() {
 			c.Model = "gemini-2.0-flash"
 		case LLMProviderOllama:
 			c.Model = "llama3.2"
+		case LLMProviderCohere:
+			c.Model = "command-r-plus"
+		case LLMProviderBedrock:
+			c.Model = "anthropic.claude-3-5-sonnet-20240620-v1:0"
+		}
+	}
+
+	// Bedrock region
+	if c.Provider == LLMProviderBedrock {
+		if c.Bedrock == nil {
+			c.Bedrock = &BedrockConfig{}
+		}
+		if c.Bedrock.Region == "" {
+			c.Bedrock.Region = os.Getenv("AWS_REGION")
+		}
+		if c.Bedrock.Region == "" {
+			c.Bedrock.Region = os.Getenv("AWS_DEFAULT_REGION")
+		}
+	}
+
+	// Azure endpoint and API version
+	if c.Provider == LLMProviderAzureOpenAI {
+		if c.Azure == nil {
+			c.Azure = &AzureOpenAIConfig{}
+		}
+		if c.Azure.Endpoint == "" {
+			c.Azure.Endpoint = os.Getenv("AZURE_OPENAI_ENDPOINT")
+		}
+		if c.Azure.APIVersion == "" {
+			c.Azure.APIVersion = "2025-04-01-preview"
 		}
 	}
 
 	// Get API key from environment if not set
-	if c.APIKey == "" {
+	if c.APIKey == "" && !c.Azure.usesADAuth() {
 		c.APIKey = getAPIKeyFromEnv(c.Provider)
 	}
 
@@ -113,6 +365,16 @@ func (c *LLMConfig) SetDefaults() {
 			c.Thinking.BudgetTokens = 1024
 		}
 	}
+
+	// Default stored responses config
+	if c.StoredResponses != nil {
+		if c.StoredResponses.Enabled == nil {
+			c.StoredResponses.Enabled = BoolPtr(true)
+		}
+		if c.StoredResponses.TTL == 0 {
+			c.StoredResponses.TTL = Duration(30 * 24 * time.Hour)
+		}
+	}
 }
 
 // Validate checks the LLM configuration.
@@ -122,14 +384,51 @@ func (c *LLMConfig) Validate() error {
 		LLMProviderOpenAI:    true,
 		LLMProviderGemini:    true,
 		LLMProviderOllama:    true,
+		LLMProviderCohere:    true,
+
+		LLMProviderAzureOpenAI: true,
+		LLMProviderBedrock:     true,
 	}
 
 	if c.Provider != "" && !validProviders[c.Provider] {
-		return fmt.Errorf("invalid provider %q (valid: anthropic, openai, gemini, ollama)", c.Provider)
+		return fmt.Errorf("invalid provider %q (valid: anthropic, openai, gemini, ollama, cohere, azure_openai, bedrock)", c.Provider)
 	}
 
-	// Ollama doesn't require API key
-	if c.Provider != LLMProviderOllama && c.APIKey == "" {
+	if c.Provider == LLMProviderAzureOpenAI {
+		if c.Azure == nil || c.Azure.Endpoint == "" {
+			return fmt.Errorf("azure.endpoint is required for provider %q", c.Provider)
+		}
+		if c.Model == "" && c.Azure.Deployment == "" {
+			return fmt.Errorf("azure.deployment or model is required for provider %q", c.Provider)
+		}
+		if c.Azure.ClientSecret != "" && (c.Azure.TenantID == "" || c.Azure.ClientID == "") {
+			return fmt.Errorf("azure.tenant_id and azure.client_id are required with azure.client_secret")
+		}
+		if !azureSupportsResponses(c.Azure.APIVersion) {
+			return fmt.Errorf("azure.api_version %q does not support the Responses API (use %s or later)", c.Azure.APIVersion, azureMinResponsesAPIVersion)
+		}
+	} else if c.Azure != nil {
+		return fmt.Errorf("azure is only supported for provider %q", LLMProviderAzureOpenAI)
+	}
+
+	if c.Provider == LLMProviderBedrock {
+		if c.Bedrock == nil || c.Bedrock.Region == "" {
+			return fmt.Errorf("bedrock.region is required for provider %q", c.Provider)
+		}
+		// Only the Anthropic Messages format is implemented
+		if !strings.Contains(c.Model, "anthropic.") && !strings.HasPrefix(c.Model, "arn:") {
+			return fmt.Errorf("model %q is not supported for provider %q (Anthropic Claude models only)", c.Model, c.Provider)
+		}
+		if (c.Bedrock.AccessKeyID == "") != (c.Bedrock.SecretAccessKey == "") {
+			return fmt.Errorf("bedrock.access_key_id and bedrock.secret_access_key must be set together")
+		}
+	} else if c.Bedrock != nil {
+		return fmt.Errorf("bedrock is only supported for provider %q", LLMProviderBedrock)
+	}
+
+	// Ollama doesn't require API key, Azure can use Entra ID instead and
+	// Bedrock signs requests with AWS credentials
+	if c.Provider != LLMProviderOllama && c.Provider != LLMProviderBedrock && c.APIKey == "" && !c.Azure.usesADAuth() {
 		return fmt.Errorf("api_key is required for provider %q", c.Provider)
 	}
 
@@ -137,6 +436,45 @@ func (c *LLMConfig) Validate() error {
 		return fmt.Errorf("temperature must be between 0 and 2")
 	}
 
+	if c.Thinking != nil {
+		switch c.Thinking.Summary {
+		case "", "none", "auto", "concise", "detailed":
+		default:
+			return fmt.Errorf("invalid thinking.summary %q (valid: none, auto, concise, detailed)", c.Thinking.Summary)
+		}
+	}
+
+	if c.StoredResponses != nil && BoolValue(c.StoredResponses.Enabled, false) {
+		if c.Provider != LLMProviderOpenAI && c.Provider != LLMProviderAzureOpenAI {
+			return fmt.Errorf("stored_responses is only supported for providers %q and %q", LLMProviderOpenAI, LLMProviderAzureOpenAI)
+		}
+		if c.StoredResponses.TTL < 0 {
+			return fmt.Errorf("stored_responses.ttl must be non-negative")
+		}
+	}
+
+	if c.ConnectTimeout < 0 || c.TotalTimeout < 0 {
+		return fmt.Errorf("connect_timeout and total_timeout must be non-negative")
+	}
+
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("max_retries must be non-negative")
+	}
+
+	if c.Pricing != nil && (c.Pricing.InputPer1K < 0 || c.Pricing.OutputPer1K < 0) {
+		return fmt.Errorf("pricing must be non-negative")
+	}
+
+	switch c.SystemMessages {
+	case "", "merge", "user":
+	case "native":
+		if c.Provider == LLMProviderAnthropic || c.Provider == LLMProviderBedrock || c.Provider == LLMProviderGemini {
+			return fmt.Errorf("system_messages %q is not supported for provider %q", c.SystemMessages, c.Provider)
+		}
+	default:
+		return fmt.Errorf("invalid system_messages %q (valid: merge, user, native)", c.SystemMessages)
+	}
+
 	return nil
 }
 
@@ -167,6 +505,10 @@ func getAPIKeyFromEnv(provider LLMProvider) string {
 			return key
 		}
 		return os.Getenv("GOOGLE_API_KEY")
+	case LLMProviderAzureOpenAI:
+		return os.Getenv("AZURE_OPENAI_API_KEY")
+	case LLMProviderCohere:
+		return os.Getenv("COHERE_API_KEY")
 	case LLMProviderOllama:
 		return "" // Ollama doesn't need API key
 	default:
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import "fmt"
+
+// LLMCacheConfig configures the LLM response cache.
+//
+// When enabled, every LLM answers repeated identical requests (same model,
+// instruction, messages, tools and generation settings) from the cache
+// instead of calling the provider.
+//
+// Example YAML:
+//
+//	llm_cache:
+//	  enabled: true
+//	  backend: sql
+//	  sql_database: default
+//	  ttl: 24h
+type LLMCacheConfig struct {
+	// Enabled controls whether responses are cached.
+	Enabled *bool `yaml:"enabled,omitempty" json:"enabled,omitempty"`
+
+	// Backend is the storage backend ("memory" or "sql").
+	Backend string `yaml:"backend,omitempty" json:"backend,omitempty"`
+
+	// SQLDatabase is the reference to a SQL database from the databases section.
+	// Required when backend is "sql".
+	SQLDatabase string `yaml:"sql_database,omitempty" json:"sql_database,omitempty"`
+
+	// TTL is how long a cached response is served. 0 never expires.
+	TTL Duration `yaml:"ttl,omitempty" json:"ttl,omitempty"`
+
+	// MaxEntries bounds the memory backend, evicting the least recently
+	// used response. Default: 1000. The sql backend is bounded by TTL
+	// instead, deleting expired responses periodically.
+	MaxEntries int `yaml:"max_entries,omitempty" json:"max_entries,omitempty"`
+
+	// ReplayStream replays the cached streaming chunks to streaming
+	// requests, instead of sending only the final response.
+	ReplayStream bool `yaml:"replay_stream,omitempty" json:"replay_stream,omitempty"`
+}
+
+// IsEnabled returns true if the response cache is enabled.
+func (c *LLMCacheConfig) IsEnabled() bool {
+	return c != nil && c.Enabled != nil && *c.Enabled
+}
+
+// SetDefaults sets default values for LLMCacheConfig.
+func (c *LLMCacheConfig) SetDefaults() {
+	if c.Enabled == nil {
+		c.Enabled = BoolPtr(false)
+	}
+	if c.Backend == "" {
+		c.Backend = "memory"
+	}
+	if c.Backend == "memory" && c.MaxEntries == 0 {
+		c.MaxEntries = 1000
+	}
+}
+
+// Validate validates the LLMCacheConfig.
+func (c *LLMCacheConfig) Validate() error {
+	if !c.IsEnabled() {
+		return nil
+	}
+
+	if c.Backend != "" && c.Backend != "memory" && c.Backend != "sql" {
+		return fmt.Errorf("invalid llm_cache.backend '%s', must be 'memory' or 'sql'", c.Backend)
+	}
+	if c.Backend == "sql" && c.SQLDatabase == "" {
+		return fmt.Errorf("llm_cache.backend 'sql' requires 'sql_database' reference")
+	}
+	if c.TTL < 0 {
+		return fmt.Errorf("llm_cache.ttl must be non-negative")
+	}
+	if c.MaxEntries < 0 {
+		return fmt.Errorf("llm_cache.max_entries must be non-negative")
+	}
+	if c.Backend == "sql" && c.MaxEntries > 0 {
+		return fmt.Errorf("llm_cache.max_entries is only supported by the memory backend; use ttl to bound the sql backend")
+	}
+
+	return nil
+}
@@ -23,6 +23,7 @@ import (
 	"os"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -33,8 +34,10 @@ import (
 
 // Loader loads and watches configuration from a Provider.
 type Loader struct {
-	provider provider.Provider
-	onChange func(*Config)
+	provider        provider.Provider
+	onChange        func(*Config)
+	secretProviders map[string]SecretProvider
+	strict          bool
 }
 
 // LoaderOption configures a Loader.
@@ -47,10 +50,26 @@ func WithOnChange(fn func(*Config)) LoaderOption {
 	}
 }
 
+// WithSecretProvider adds a secret provider for this loader only,
+// overriding any globally registered provider for the same scheme.
+func WithSecretProvider(sp SecretProvider) LoaderOption {
+	return func(l *Loader) {
+		l.secretProviders[sp.Scheme()] = sp
+	}
+}
+
+// WithStrict makes unknown config keys an error instead of a warning.
+func WithStrict() LoaderOption {
+	return func(l *Loader) {
+		l.strict = true
+	}
+}
+
 // NewLoader creates a Loader with the given provider.
 func NewLoader(p provider.Provider, opts ...LoaderOption) *Loader {
 	l := &Loader{
-		provider: p,
+		provider:        p,
+		secretProviders: registeredSecretProviders(),
 	}
 	for _, opt := range opts {
 		opt(l)
@@ -60,6 +79,22 @@ func NewLoader(p provider.Provider, opts ...LoaderOption) *Loader {
 
 // Load reads, parses, and processes the configuration.
 func (l *Loader) Load(ctx context.Context) (*Config, error) {
+	return l.load(ctx, nil)
+}
+
+// LoadWithProvenance is like Load but also records where each effective
+// value came from (config file, env vars, secrets, or defaults).
+func (l *Loader) LoadWithProvenance(ctx context.Context) (*Config, *Provenance, error) {
+	prov := newProvenance()
+	cfg, err := l.load(ctx, prov)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, prov, nil
+}
+
+// load runs the loading pipeline, recording provenance if prov is non-nil.
+func (l *Loader) load(ctx context.Context, prov *Provenance) (*Config, error) {
 	// 1. Read raw bytes from provider
 	data, err := l.provider.Load(ctx)
 	if err != nil {
@@ -72,8 +107,30 @@ func (l *Loader) Load(ctx context.Context) (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config: %w", err)
 	}
 
+	// 2b. Flag keys that match no config field (typos are otherwise ignored)
+	if unknown := findUnknownKeys(data); len(unknown) > 0 {
+		if l.strict {
+			return nil, &UnknownKeysError{Keys: unknown}
+		}
+		for _, k := range unknown {
+			slog.Warn("Ignoring unknown config key", "line", k.Line, "key", k.Key, "in", k.Type)
+		}
+	}
+	if prov != nil {
+		prov.recordRaw("", rawMap, l.secretProviders)
+	}
+
 	// 3. Expand environment variables
-	expandedMap := expandEnvVars(rawMap)
+	expandedMap, err := expandEnvVars(rawMap)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3b. Resolve secret references (vault://, awssm://, ...)
+	expandedMap, err = resolveSecrets(ctx, expandedMap, l.secretProviders)
+	if err != nil {
+		return nil, err
+	}
 
 	// 4. Decode into Config struct
 	cfg := &Config{}
@@ -82,7 +139,20 @@ func (l *Loader) Load(ctx context.Context) (*Config, error) {
 	}
 
 	// 5. Apply defaults
+	var beforeDefaults map[string]any
+	if prov != nil {
+		if beforeDefaults, err = flattenConfig(cfg); err != nil {
+			return nil, fmt.Errorf("failed to record provenance: %w", err)
+		}
+	}
 	cfg.SetDefaults()
+	if prov != nil {
+		afterDefaults, err := flattenConfig(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to record provenance: %w", err)
+		}
+		prov.resolve(cfg, beforeDefaults, afterDefaults)
+	}
 
 	// 6. Validate
 	if err := cfg.Validate(); err != nil {
@@ -212,25 +282,67 @@ func decodeConfig(input map[string]any, output *Config) error {
 	return nil
 }
 
+// MissingEnvVar is a ${VAR:?message} reference whose variable is unset or empty.
+type MissingEnvVar struct {
+	// Name is the environment variable name.
+	Name string
+
+	// Message is the text after :?, or a generic one if none was given.
+	Message string
+
+	// Path is the config path of the value holding the reference.
+	Path string
+}
+
+// String formats the variable like "OPENAI_API_KEY: message (llms.default.api_key)".
+func (v MissingEnvVar) String() string {
+	return fmt.Sprintf("%s: %s (%s)", v.Name, v.Message, v.Path)
+}
+
+// MissingEnvError lists every required environment variable that is not set.
+type MissingEnvError struct {
+	Vars []MissingEnvVar
+}
+
+func (e *MissingEnvError) Error() string {
+	parts := make([]string, len(e.Vars))
+	for i, v := range e.Vars {
+		parts[i] = v.String()
+	}
+	return "missing required environment variables: " + strings.Join(parts, "; ")
+}
+
 // expandEnvVars recursively expands ${VAR} and $VAR patterns in a map.
-func expandEnvVars(input map[string]any) map[string]any {
+// All unset ${VAR:?message} references are reported together in a
+// *MissingEnvError, so one run surfaces every variable that needs setting.
+func expandEnvVars(input map[string]any) (map[string]any, error) {
+	var missing []MissingEnvVar
+	result := expandMap("", input, &missing)
+	if len(missing) > 0 {
+		sort.Slice(missing, func(i, j int) bool { return missing[i].Path < missing[j].Path })
+		return nil, &MissingEnvError{Vars: missing}
+	}
+	return result, nil
+}
+
+func expandMap(path string, input map[string]any, missing *[]MissingEnvVar) map[string]any {
 	result := make(map[string]any, len(input))
 	for k, v := range input {
-		result[k] = expandValue(v)
+		result[k] = expandValue(joinConfigPath(path, k), v, missing)
 	}
 	return result
 }
 
-func expandValue(v any) any {
+func expandValue(path string, v any, missing *[]MissingEnvVar) any {
 	switch val := v.(type) {
 	case string:
-		return expandEnvString(val)
+		return expandEnvString(path, val, missing)
 	case map[string]any:
-		return expandEnvVars(val)
+		return expandMap(path, val, missing)
 	case []any:
 		result := make([]any, len(val))
 		for i, item := range val {
-			result[i] = expandValue(item)
+			result[i] = expandValue(fmt.Sprintf("%s[%d]", path, i), item, missing)
 		}
 		return result
 	default:
@@ -238,43 +350,55 @@ func expandValue(v any) any {
 	}
 }
 
-// envVarPattern matches ${VAR}, ${VAR:-default}, and $VAR
+// envVarPattern matches ${VAR}, ${VAR:-default}, ${VAR:?message} and $VAR
 var envVarPattern = regexp.MustCompile(`\$\{([^}]+)\}|\$([A-Za-z_][A-Za-z0-9_]*)`)
 
-func expandEnvString(s string) string {
-	return envVarPattern.ReplaceAllStringFunc(s, func(match string) string {
-		// Handle ${VAR} and ${VAR:-default}
-		if strings.HasPrefix(match, "${") {
-			inner := match[2 : len(match)-1] // Remove ${ and }
-
-			// Check for default value syntax: ${VAR:-default}
-			if idx := strings.Index(inner, ":-"); idx != -1 {
-				varName := inner[:idx]
-				defaultVal := inner[idx+2:]
-				if val := os.Getenv(varName); val != "" {
-					return val
-				}
-				return defaultVal
-			}
-
-			// Simple ${VAR}
-			return os.Getenv(inner)
+// parseEnvRef splits the inside of a ${...} reference into the variable
+// name, the operator (":-", ":?" or "") and the operator's argument.
+func parseEnvRef(inner string) (name, op, arg string) {
+	for _, candidate := range []string{":-", ":?"} {
+		if idx := strings.Index(inner, candidate); idx != -1 {
+			return inner[:idx], candidate, inner[idx+2:]
 		}
+	}
+	return inner, "", ""
+}
 
+func expandEnvString(path, s string, missing *[]MissingEnvVar) string {
+	return envVarPattern.ReplaceAllStringFunc(s, func(match string) string {
 		// Handle $VAR
-		varName := match[1:] // Remove $
-		return os.Getenv(varName)
+		if !strings.HasPrefix(match, "${") {
+			return os.Getenv(match[1:])
+		}
+
+		// Handle ${VAR}, ${VAR:-default} and ${VAR:?message}; as in the
+		// shell, an empty variable counts as unset for both operators.
+		name, op, arg := parseEnvRef(match[2 : len(match)-1])
+		val := os.Getenv(name)
+		if val != "" {
+			return val
+		}
+		switch op {
+		case ":-":
+			return arg
+		case ":?":
+			if arg == "" {
+				arg = "required but not set"
+			}
+			*missing = append(*missing, MissingEnvVar{Name: name, Message: arg, Path: path})
+		}
+		return ""
 	})
 }
 
 // LoadConfig is a convenience function that creates a loader and loads config.
-func LoadConfig(ctx context.Context, opts provider.ProviderConfig) (*Config, *Loader, error) {
+func LoadConfig(ctx context.Context, opts provider.ProviderConfig, loaderOpts ...LoaderOption) (*Config, *Loader, error) {
 	p, err := provider.New(opts)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to create provider: %w", err)
 	}
 
-	loader := NewLoader(p)
+	loader := NewLoader(p, loaderOpts...)
 	cfg, err := loader.Load(ctx)
 	if err != nil {
 		p.Close()
@@ -284,10 +408,26 @@ func LoadConfig(ctx context.Context, opts provider.ProviderConfig) (*Config, *Lo
 	return cfg, loader, nil
 }
 
+// LoadConfigFileWithProvenance loads a config file and records where each
+// effective value came from.
+func LoadConfigFileWithProvenance(ctx context.Context, path string, opts ...LoaderOption) (*Config, *Provenance, error) {
+	p, err := provider.New(provider.ProviderConfig{
+		Type: provider.TypeFile,
+		Path: path,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create provider: %w", err)
+	}
+	defer p.Close()
+
+	return NewLoader(p, opts...).LoadWithProvenance(ctx)
+}
+
 // LoadConfigFile is a convenience function for loading from a file.
-func LoadConfigFile(ctx context.Context, path string) (*Config, *Loader, error) {
+// Pass WithStrict() to reject unknown keys instead of warning about them.
+func LoadConfigFile(ctx context.Context, path string, opts ...LoaderOption) (*Config, *Loader, error) {
 	return LoadConfig(ctx, provider.ProviderConfig{
 		Type: provider.TypeFile,
 		Path: path,
-	})
+	}, opts...)
 }
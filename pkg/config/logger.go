@@ -20,8 +20,8 @@ import "fmt"
 // LoggerConfig configures logging behavior.
 //
 // Priority order (highest to lowest):
-//  1. CLI flags (--log-level, --log-file, --log-format)
-//  2. Environment variables (LOG_LEVEL, LOG_FILE, LOG_FORMAT)
+//  1. CLI flags (--log-level, --log-file, --log-format, --log-sampling)
+//  2. Environment variables (LOG_LEVEL, LOG_FILE, LOG_FORMAT, LOG_SAMPLING)
 //  3. Config file (logger section)
 //  4. Defaults (info level, simple format, stderr)
 //
@@ -31,6 +31,7 @@ import "fmt"
 //	  level: info
 //	  file: hector.log
 //	  format: simple
+//	  sampling: 100
 type LoggerConfig struct {
 	// Level specifies the log level (debug, info, warn, error).
 	// Default: info
@@ -45,6 +46,12 @@ type LoggerConfig struct {
 	// Values: "simple" (level + message), "verbose" (time + level + message), or custom.
 	// Default: simple
 	Format string `yaml:"format,omitempty"`
+
+	// Sampling logs only the first and then every Nth occurrence of each
+	// debug or info message, keeping high-frequency lines from flooding logs.
+	// Warnings and errors are never sampled.
+	// Default: 0 (disabled)
+	Sampling int `yaml:"sampling,omitempty"`
 }
 
 // SetDefaults applies default values to LoggerConfig.
@@ -73,6 +80,10 @@ func (c *LoggerConfig) Validate() error {
 		}
 	}
 
+	if c.Sampling < 0 {
+		return fmt.Errorf("sampling must be non-negative")
+	}
+
 	// Format can be "simple", "verbose", or any custom value
 	// No validation needed - custom formats are allowed
 	_ = c.Format
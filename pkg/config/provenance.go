@@ -0,0 +1,238 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValueSource identifies where an effective config value came from.
+type ValueSource string
+
+const (
+	// SourceFile is a value set explicitly in the config file.
+	SourceFile ValueSource = "file"
+
+	// SourceEnv is a value expanded from ${VAR} references.
+	SourceEnv ValueSource = "env"
+
+	// SourceSecret is a value resolved from a secret provider (vault://, ...).
+	SourceSecret ValueSource = "secret"
+
+	// SourceDefault is a value filled in by SetDefaults, including the
+	// top-level defaults block and API keys detected from the environment.
+	SourceDefault ValueSource = "default"
+)
+
+// ResolvedValue is an effective config value and its provenance.
+type ResolvedValue struct {
+	// Path is the dotted config path (e.g., "llms.default.model").
+	Path string `json:"path"`
+
+	// Value is the effective value after resolution.
+	Value any `json:"value"`
+
+	// Source is the layer the value came from.
+	Source ValueSource `json:"source"`
+
+	// Detail adds context, such as the env vars or secret scheme used.
+	Detail string `json:"detail,omitempty"`
+
+	// Sensitive is set for secrets: values of fields tagged
+	// sensitive:"true" and values resolved from a secret provider.
+	Sensitive bool `json:"sensitive,omitempty"`
+}
+
+// Provenance records where each effective config value came from.
+type Provenance struct {
+	// raw holds the source of every leaf present in the config file
+	raw    map[string]ResolvedValue
+	values []ResolvedValue
+}
+
+func newProvenance() *Provenance {
+	return &Provenance{raw: make(map[string]ResolvedValue)}
+}
+
+// Values returns all effective leaf values, sorted by path.
+func (p *Provenance) Values() []ResolvedValue {
+	return p.values
+}
+
+// Lookup returns the effective value at path.
+func (p *Provenance) Lookup(path string) (ResolvedValue, bool) {
+	i := sort.Search(len(p.values), func(i int) bool { return p.values[i].Path >= path })
+	if i < len(p.values) && p.values[i].Path == path {
+		return p.values[i], true
+	}
+	return ResolvedValue{}, false
+}
+
+// recordRaw classifies every leaf of the parsed config file before env
+// vars and secrets are resolved.
+func (p *Provenance) recordRaw(path string, v any, secretProviders map[string]SecretProvider) {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, item := range val {
+			p.recordRaw(joinConfigPath(path, k), item, secretProviders)
+		}
+	case []any:
+		for i, item := range val {
+			p.recordRaw(fmt.Sprintf("%s[%d]", path, i), item, secretProviders)
+		}
+	case string:
+		rv := ResolvedValue{Path: path, Source: SourceFile}
+		if scheme, _, ok := strings.Cut(val, "://"); ok && secretProviders[scheme] != nil {
+			rv.Source = SourceSecret
+			rv.Detail = scheme
+			rv.Sensitive = true
+		} else if vars := envVarRefs(val); len(vars) > 0 {
+			rv.Source = SourceEnv
+			rv.Detail = strings.Join(vars, ", ")
+		}
+		p.raw[path] = rv
+	default:
+		p.raw[path] = ResolvedValue{Path: path, Source: SourceFile}
+	}
+}
+
+// resolve computes the effective values from the config before and after
+// defaults were applied.
+func (p *Provenance) resolve(cfg *Config, beforeDefaults, afterDefaults map[string]any) {
+	var sensitive []string
+	sensitivePaths("", reflect.ValueOf(cfg), &sensitive)
+
+	p.values = make([]ResolvedValue, 0, len(afterDefaults))
+	for path, value := range afterDefaults {
+		rv, ok := p.raw[path]
+		if !ok || !reflect.DeepEqual(beforeDefaults[path], value) {
+			// Not in the file, or changed by SetDefaults
+			rv = ResolvedValue{Path: path, Source: SourceDefault}
+		}
+		rv.Value = value
+		if !rv.Sensitive {
+			rv.Sensitive = underAnyPath(path, sensitive)
+		}
+		p.values = append(p.values, rv)
+	}
+	sort.Slice(p.values, func(i, j int) bool { return p.values[i].Path < p.values[j].Path })
+}
+
+// sensitivePaths collects the config paths of fields tagged
+// sensitive:"true". Everything below such a path (map entries, list
+// items) is sensitive too.
+func sensitivePaths(path string, v reflect.Value, out *[]string) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			sensitivePaths(path, v.Elem(), out)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(field.Name)
+			}
+			fieldPath := joinConfigPath(path, name)
+			if field.Tag.Get("sensitive") == "true" {
+				*out = append(*out, fieldPath)
+				continue
+			}
+			sensitivePaths(fieldPath, v.Field(i), out)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			sensitivePaths(joinConfigPath(path, fmt.Sprint(iter.Key().Interface())), iter.Value(), out)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			sensitivePaths(fmt.Sprintf("%s[%d]", path, i), v.Index(i), out)
+		}
+	}
+}
+
+// underAnyPath reports whether path equals or is nested below one of prefixes.
+func underAnyPath(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if rest, ok := strings.CutPrefix(path, prefix); ok && (rest == "" || rest[0] == '.' || rest[0] == '[') {
+			return true
+		}
+	}
+	return false
+}
+
+// envVarRefs returns the env vars referenced by s, noting unset vars whose
+// ${VAR:-default} fallback was used.
+func envVarRefs(s string) []string {
+	var vars []string
+	for _, m := range envVarPattern.FindAllStringSubmatch(s, -1) {
+		name := m[2]
+		if m[1] != "" {
+			var op string
+			name, op, _ = parseEnvRef(m[1])
+			if op == ":-" && os.Getenv(name) == "" {
+				name += " (unset, fallback used)"
+			}
+		}
+		vars = append(vars, name)
+	}
+	return vars
+}
+
+// flattenConfig returns the config's leaf values keyed by config path.
+func flattenConfig(cfg *Config) (map[string]any, error) {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	result := make(map[string]any)
+	flattenValue("", m, result)
+	return result, nil
+}
+
+func flattenValue(path string, v any, out map[string]any) {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, item := range val {
+			flattenValue(joinConfigPath(path, k), item, out)
+		}
+	case []any:
+		for i, item := range val {
+			flattenValue(fmt.Sprintf("%s[%d]", path, i), item, out)
+		}
+	default:
+		out[path] = val
+	}
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadWithProvenance(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `
+llms:
+  default:
+    provider: openai
+    model: gpt-4o
+    api_key: ${PROVENANCE_TEST_KEY}
+agents:
+  assistant:
+    llm: default
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PROVENANCE_TEST_KEY", "sk-test")
+
+	_, prov, err := LoadConfigFileWithProvenance(context.Background(), path)
+	if err != nil {
+		t.Fatalf("LoadConfigFileWithProvenance() error = %v", err)
+	}
+
+	tests := []struct {
+		path   string
+		value  any
+		source ValueSource
+	}{
+		{"llms.default.model", "gpt-4o", SourceFile},
+		{"llms.default.api_key", "sk-test", SourceEnv},
+		{"llms.default.temperature", 0.7, SourceDefault},
+		{"server.port", 8080, SourceDefault},
+	}
+	for _, tt := range tests {
+		rv, ok := prov.Lookup(tt.path)
+		if !ok {
+			t.Errorf("%s: not recorded", tt.path)
+			continue
+		}
+		if rv.Value != tt.value || rv.Source != tt.source {
+			t.Errorf("%s = %v (%s), want %v (%s)", tt.path, rv.Value, rv.Source, tt.value, tt.source)
+		}
+	}
+	if rv, _ := prov.Lookup("llms.default.api_key"); rv.Detail != "PROVENANCE_TEST_KEY" {
+		t.Errorf("api_key detail = %q, want env var name", rv.Detail)
+	}
+}
+
+func TestProvenanceMarksSensitiveValues(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `
+databases:
+  main:
+    driver: postgres
+    host: db
+    database: hector
+    password: pw
+    read_replicas:
+      - postgres://u:pw@replica/hector
+llms:
+  default:
+    provider: openai
+    model: gpt-4o
+    api_key: sk-test
+agents:
+  assistant:
+    llm: default
+  remote:
+    type: remote
+    url: https://remote.example.com
+    headers:
+      Authorization: Bearer secret
+server:
+  observability:
+    langfuse:
+      enabled: true
+      public_key: pk-lf-test
+      secret_key: sk-lf-test
+    tracing:
+      enabled: true
+      endpoint: collector:4317
+      headers:
+        Authorization: Bearer otlp-secret
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, prov, err := LoadConfigFileWithProvenance(context.Background(), path)
+	if err != nil {
+		t.Fatalf("LoadConfigFileWithProvenance() error = %v", err)
+	}
+
+	tests := []struct {
+		path      string
+		sensitive bool
+	}{
+		{"llms.default.api_key", true},
+		{"databases.main.password", true},
+		{"databases.main.read_replicas[0]", true},
+		{"agents.remote.headers.Authorization", true},
+		{"server.observability.langfuse.secret_key", true},
+		{"server.observability.langfuse.public_key", false},
+		{"server.observability.tracing.headers.Authorization", true},
+		{"server.observability.tracing.endpoint", false},
+		{"llms.default.model", false},
+		{"agents.remote.url", false},
+	}
+	for _, tt := range tests {
+		rv, ok := prov.Lookup(tt.path)
+		if !ok {
+			t.Errorf("%s: not recorded", tt.path)
+			continue
+		}
+		if rv.Sensitive != tt.sensitive {
+			t.Errorf("%s sensitive = %v, want %v", tt.path, rv.Sensitive, tt.sensitive)
+		}
+	}
+}
@@ -15,7 +15,11 @@
 
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
 
 // VectorStoreConfig configures a vector database provider.
 //
@@ -28,10 +32,13 @@ import "fmt"
 //	  production:
 //	    type: qdrant
 //	    host: qdrant.example.com
-//	    port: 6333
+//	    protocol: grpc
 //	    api_key: ${QDRANT_API_KEY}
+//	  postgres:
+//	    type: pgvector
+//	    database: main
 type VectorStoreConfig struct {
-	// Type is the vector store type: "chromem", "qdrant", "pinecone", "weaviate", "milvus".
+	// Type is the vector store type: "chromem", "qdrant", "pinecone", "weaviate", "milvus", "pgvector".
 	Type string `yaml:"type"`
 
 	// Host for external vector stores (qdrant, weaviate, milvus).
@@ -41,7 +48,7 @@ type VectorStoreConfig struct {
 	Port int `yaml:"port,omitempty"`
 
 	// APIKey for authenticated access.
-	APIKey string `yaml:"api_key,omitempty"`
+	APIKey string `yaml:"api_key,omitempty" sensitive:"true"`
 
 	// EnableTLS enables TLS connections.
 	EnableTLS *bool `yaml:"enable_tls,omitempty"`
@@ -60,6 +67,21 @@ type VectorStoreConfig struct {
 
 	// Environment for Pinecone.
 	Environment string `yaml:"environment,omitempty"`
+
+	// Database references a postgres database from the databases section
+	// (pgvector). The connection is shared with other components.
+	Database string `yaml:"database,omitempty"`
+
+	// Index is the pgvector index type: "hnsw" (default) or "ivfflat".
+	Index string `yaml:"index,omitempty"`
+
+	// Protocol is the Qdrant API: "grpc" (default, port 6334) or "rest"
+	// (port 6333).
+	Protocol string `yaml:"protocol,omitempty"`
+
+	// Distance is the Qdrant distance metric for new collections:
+	// "cosine" (default), "dot", "euclid" or "manhattan".
+	Distance string `yaml:"distance,omitempty"`
 }
 
 // SetDefaults applies default values.
@@ -70,7 +92,10 @@ func (c *VectorStoreConfig) SetDefaults() {
 	if c.Port == 0 {
 		switch c.Type {
 		case "qdrant":
-			c.Port = 6333
+			c.Port = 6334
+			if c.Protocol == "rest" {
+				c.Port = 6333
+			}
 		case "weaviate":
 			c.Port = 8080
 		case "milvus":
@@ -88,10 +113,11 @@ func (c *VectorStoreConfig) Validate() error {
 		"weaviate": true,
 		"milvus":   true,
 		"chroma":   true,
+		"pgvector": true,
 	}
 
 	if !validTypes[c.Type] {
-		return fmt.Errorf("invalid vector store type %q (valid: chromem, qdrant, pinecone, weaviate, milvus, chroma)", c.Type)
+		return fmt.Errorf("invalid vector store type %q (valid: chromem, qdrant, pinecone, weaviate, milvus, chroma, pgvector)", c.Type)
 	}
 
 	// External stores require host
@@ -109,6 +135,20 @@ func (c *VectorStoreConfig) Validate() error {
 		return fmt.Errorf("api_key is required for pinecone vector store")
 	}
 
+	// pgvector requires a database reference
+	if c.Type == "pgvector" && c.Database == "" {
+		return fmt.Errorf("database is required for pgvector vector store")
+	}
+	if c.Index != "" && (c.Type != "pgvector" || (c.Index != "hnsw" && c.Index != "ivfflat")) {
+		return fmt.Errorf("invalid index %q (valid for pgvector: hnsw, ivfflat)", c.Index)
+	}
+	if c.Protocol != "" && (c.Type != "qdrant" || (c.Protocol != "grpc" && c.Protocol != "rest")) {
+		return fmt.Errorf("invalid protocol %q (valid for qdrant: grpc, rest)", c.Protocol)
+	}
+	if c.Distance != "" && (c.Type != "qdrant" || !slices.Contains([]string{"cosine", "dot", "euclid", "manhattan"}, c.Distance)) {
+		return fmt.Errorf("invalid distance %q (valid for qdrant: cosine, dot, euclid, manhattan)", c.Distance)
+	}
+
 	return nil
 }
 
@@ -132,6 +172,7 @@ func (c *VectorStoreConfig) IsEmbedded() bool {
 //	      size: 1000
 //	    vector_store: local
 //	    embedder: default
+//	    search_mode: hybrid
 //	    watch: true
 //	    indexing:
 //	      max_concurrent: 8
@@ -160,17 +201,38 @@ type DocumentStoreConfig struct {
 	// IncrementalIndexing only re-indexes changed documents.
 	IncrementalIndexing bool `yaml:"incremental_indexing,omitempty"`
 
+	// SearchMode selects how chunks are ranked: "vector" (default),
+	// "keyword" (BM25 over chunk text) or "hybrid" (both, fused with
+	// reciprocal rank fusion).
+	SearchMode string `yaml:"search_mode,omitempty"`
+
+	// KeywordWeight is the keyword ranking's share (0-1) in hybrid search.
+	// Default: 0.5.
+	KeywordWeight float64 `yaml:"keyword_weight,omitempty"`
+
 	// Search configures search behavior for this store.
 	Search *DocumentSearchConfig `yaml:"search,omitempty"`
 
+	// SearchDefaults configures defaults the search tool applies when the
+	// agent doesn't specify them.
+	SearchDefaults *SearchDefaultsConfig `yaml:"search_defaults,omitempty"`
+
 	// Indexing configures indexing behavior (concurrency, retry).
 	Indexing *IndexingConfig `yaml:"indexing,omitempty"`
 
+	// NativeExtractors limits which formats are extracted by the built-in
+	// parsers: "pdf", "docx", "xlsx", "html". Empty means all.
+	// When set, MCP parsers default to a fallback role (see MCPParserConfig.Priority).
+	NativeExtractors []string `yaml:"native_extractors,omitempty"`
+
 	// MCPParsers configures MCP-based document parsing (e.g., Docling).
 	// When configured, MCP tools are used to parse documents instead of native parsers.
 	MCPParsers *MCPParserConfig `yaml:"mcp_parsers,omitempty"`
 }
 
+// NativeExtractorFormats lists the formats supported by native extractors.
+var NativeExtractorFormats = []string{"pdf", "docx", "xlsx", "html"}
+
 // SetDefaults applies default values.
 func (c *DocumentStoreConfig) SetDefaults() {
 	if c.Source != nil {
@@ -189,6 +251,12 @@ func (c *DocumentStoreConfig) SetDefaults() {
 	}
 	c.Indexing.SetDefaults()
 	if c.MCPParsers != nil {
+		if len(c.NativeExtractors) > 0 && c.MCPParsers.Priority == nil {
+			// Native extractors were chosen explicitly, so MCP only handles
+			// the remaining formats or native failures
+			priority := 4
+			c.MCPParsers.Priority = &priority
+		}
 		c.MCPParsers.SetDefaults()
 	}
 }
@@ -206,16 +274,35 @@ func (c *DocumentStoreConfig) Validate() error {
 			return fmt.Errorf("chunking: %w", err)
 		}
 	}
+	switch c.SearchMode {
+	case "", "vector", "keyword", "hybrid":
+	default:
+		return fmt.Errorf("invalid search_mode %q (valid: vector, keyword, hybrid)", c.SearchMode)
+	}
+	if c.KeywordWeight < 0 || c.KeywordWeight > 1 {
+		return fmt.Errorf("keyword_weight must be between 0 and 1")
+	}
 	if c.Search != nil {
 		if err := c.Search.Validate(); err != nil {
 			return fmt.Errorf("search: %w", err)
 		}
 	}
+	if c.SearchDefaults != nil {
+		if err := c.SearchDefaults.Validate(); err != nil {
+			return fmt.Errorf("search_defaults: %w", err)
+		}
+	}
 	if c.Indexing != nil {
 		if err := c.Indexing.Validate(); err != nil {
 			return fmt.Errorf("indexing: %w", err)
 		}
 	}
+	for _, format := range c.NativeExtractors {
+		if !slices.Contains(NativeExtractorFormats, format) {
+			return fmt.Errorf("native_extractors: unsupported format %q (supported: %s)",
+				format, strings.Join(NativeExtractorFormats, ", "))
+		}
+	}
 	if c.MCPParsers != nil {
 		if err := c.MCPParsers.Validate(); err != nil {
 			return fmt.Errorf("mcp_parsers: %w", err)
@@ -369,7 +456,7 @@ type APISourceConfig struct {
 	URL string `yaml:"url"`
 
 	// Headers are HTTP headers to include.
-	Headers map[string]string `yaml:"headers,omitempty"`
+	Headers map[string]string `yaml:"headers,omitempty" sensitive:"true"`
 
 	// IDField is the JSON path to document IDs.
 	IDField string `yaml:"id_field"`
@@ -405,6 +492,14 @@ type ChunkingConfig struct {
 
 	// PreserveWords avoids splitting mid-word.
 	PreserveWords *bool `yaml:"preserve_words,omitempty"`
+
+	// Threshold is the similarity between adjacent sentences below which
+	// the semantic strategy starts a new chunk (default: 0.75).
+	Threshold float64 `yaml:"threshold,omitempty"`
+
+	// MinDocumentSize is the length below which the semantic strategy
+	// chunks by size instead of by topic (default: 2000).
+	MinDocumentSize int `yaml:"min_document_size,omitempty"`
 }
 
 // SetDefaults applies default values.
@@ -427,6 +522,12 @@ func (c *ChunkingConfig) SetDefaults() {
 	if c.PreserveWords == nil {
 		c.PreserveWords = BoolPtr(true)
 	}
+	if c.Threshold <= 0 {
+		c.Threshold = 0.75
+	}
+	if c.MinDocumentSize <= 0 {
+		c.MinDocumentSize = 2000
+	}
 }
 
 // Validate checks the configuration for errors.
@@ -448,6 +549,9 @@ func (c *ChunkingConfig) Validate() error {
 	if c.Overlap >= c.Size {
 		return fmt.Errorf("overlap must be less than size")
 	}
+	if c.Threshold < 0 || c.Threshold > 1 {
+		return fmt.Errorf("threshold must be between 0 and 1")
+	}
 	return nil
 }
 
@@ -482,6 +586,10 @@ type DocumentSearchConfig struct {
 
 	// MultiQueryCount is the number of query variants.
 	MultiQueryCount int `yaml:"multi_query_count,omitempty"`
+
+	// Reranker reorders the top-N candidates before the top-k cut.
+	// An alternative to enable_rerank that also supports cross-encoders.
+	Reranker *RerankerConfig `yaml:"reranker,omitempty"`
 }
 
 // SetDefaults applies default values.
@@ -495,6 +603,9 @@ func (c *DocumentSearchConfig) SetDefaults() {
 	if c.MultiQueryCount <= 0 {
 		c.MultiQueryCount = 3
 	}
+	if c.Reranker != nil {
+		c.Reranker.SetDefaults()
+	}
 }
 
 // Validate checks the configuration for errors.
@@ -514,6 +625,108 @@ func (c *DocumentSearchConfig) Validate() error {
 	if c.EnableMultiQuery && c.MultiQueryLLM == "" {
 		return fmt.Errorf("multi_query_llm is required when enable_multi_query is true")
 	}
+	if c.Reranker != nil {
+		if c.EnableRerank {
+			return fmt.Errorf("reranker and enable_rerank are mutually exclusive")
+		}
+		if err := c.Reranker.Validate(); err != nil {
+			return fmt.Errorf("reranker: %w", err)
+		}
+	}
+	return nil
+}
+
+// RerankerConfig configures the reranking stage of a document store.
+//
+// Example YAML:
+//
+//	search:
+//	  top_k: 5
+//	  reranker:
+//	    type: cross_encoder
+//	    endpoint: http://localhost:8080/rerank
+//	    model: BAAI/bge-reranker-base
+//	    top_n: 30      # Candidates fetched and reranked
+//
+//	  reranker:
+//	    type: llm
+//	    model: fast    # From the llms section
+type RerankerConfig struct {
+	// Type is the reranker: "llm" or "cross_encoder".
+	Type string `yaml:"type"`
+
+	// Model references an LLM for type llm, or is the model name sent to
+	// the endpoint for type cross_encoder.
+	Model string `yaml:"model,omitempty"`
+
+	// TopN is the number of candidates fetched and reranked.
+	// Default: 20
+	TopN int `yaml:"top_n,omitempty"`
+
+	// Endpoint is the rerank URL (required for type cross_encoder).
+	Endpoint string `yaml:"endpoint,omitempty"`
+
+	// APIKey is sent to the endpoint as a bearer token.
+	APIKey string `yaml:"api_key,omitempty" sensitive:"true"`
+}
+
+// SetDefaults applies default values.
+func (c *RerankerConfig) SetDefaults() {
+	if c.TopN <= 0 {
+		c.TopN = 20
+	}
+}
+
+// Validate checks the configuration for errors.
+func (c *RerankerConfig) Validate() error {
+	switch c.Type {
+	case "llm":
+		if c.Model == "" {
+			return fmt.Errorf("model is required for llm reranker")
+		}
+	case "cross_encoder":
+		if c.Endpoint == "" {
+			return fmt.Errorf("endpoint is required for cross_encoder reranker")
+		}
+	default:
+		return fmt.Errorf("invalid type %q (valid: llm, cross_encoder)", c.Type)
+	}
+	if c.TopN < 0 {
+		return fmt.Errorf("top_n must be non-negative")
+	}
+	if c.APIKey != "" && c.Type != "cross_encoder" {
+		return fmt.Errorf("api_key is only supported for cross_encoder reranker")
+	}
+	return nil
+}
+
+// SearchDefaultsConfig configures search tool defaults for a document store.
+//
+// Example YAML:
+//
+//	search_defaults:
+//	  top_k: 5         # Results when the agent doesn't pass a limit
+//	  min_score: 0.75  # Drop vector matches less similar than this
+type SearchDefaultsConfig struct {
+	// TopK is the number of results returned when the agent doesn't specify a limit.
+	// Default: search tool default (10)
+	TopK int `yaml:"top_k,omitempty"`
+
+	// MinScore drops vector matches with a similarity below this value so
+	// weak matches don't pollute the agent's context. Keyword and hybrid
+	// scores are not filtered.
+	// Default: 0 (no filtering)
+	MinScore float32 `yaml:"min_score,omitempty"`
+}
+
+// Validate checks the configuration for errors.
+func (c *SearchDefaultsConfig) Validate() error {
+	if c.TopK < 0 {
+		return fmt.Errorf("top_k must be non-negative")
+	}
+	if c.MinScore < 0 || c.MinScore > 1 {
+		return fmt.Errorf("min_score must be between 0 and 1")
+	}
 	return nil
 }
 
@@ -651,7 +864,8 @@ type MCPParserConfig struct {
 	Extensions []string `yaml:"extensions,omitempty"`
 
 	// Priority sets the extractor priority (higher = preferred).
-	// Default: 8 (higher than native parsers at 5)
+	// Default: 8 (higher than native parsers at 5), or 4 when the store
+	// sets native_extractors
 	Priority *int `yaml:"priority,omitempty"`
 
 	// PreferNative uses MCP only when native parsers fail.
@@ -15,7 +15,10 @@
 
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // RateLimitConfig defines rate limiting configuration.
 type RateLimitConfig struct {
@@ -25,26 +28,74 @@ type RateLimitConfig struct {
 	// Scope is the rate limiting scope ("session" or "user").
 	Scope string `yaml:"scope,omitempty" json:"scope,omitempty"`
 
-	// Backend is the storage backend ("memory" or "sql").
+	// Backend is the storage backend ("memory", "sql" or "redis").
 	Backend string `yaml:"backend,omitempty" json:"backend,omitempty"`
 
 	// SQLDatabase is the reference to a SQL database from the databases section.
 	// Required when backend is "sql".
 	SQLDatabase string `yaml:"sql_database,omitempty" json:"sql_database,omitempty"`
 
+	// Redis is the Redis connection. Used when backend is "redis".
+	Redis *RateLimitRedisConfig `yaml:"redis,omitempty" json:"redis,omitempty"`
+
+	// OnStoreError decides requests while the backend is unreachable:
+	// "allow" (fail open, limits are not enforced) or "deny" (fail closed,
+	// requests are rejected). Default: "allow".
+	OnStoreError string `yaml:"on_store_error,omitempty" json:"on_store_error,omitempty"`
+
 	// Limits defines the rate limit rules.
 	Limits []RateLimitRule `yaml:"limits,omitempty" json:"limits,omitempty"`
+
+	// Pricing maps model names to token prices, charged against cost
+	// limits. A "default" entry prices models without an entry of their own.
+	Pricing map[string]ModelPricing `yaml:"pricing,omitempty" json:"pricing,omitempty"`
+}
+
+// ModelPricing is the price of a model's tokens in US dollars.
+type ModelPricing struct {
+	// InputPerMillion is the price of one million input tokens.
+	InputPerMillion float64 `yaml:"input_per_million" json:"input_per_million"`
+
+	// OutputPerMillion is the price of one million output tokens.
+	OutputPerMillion float64 `yaml:"output_per_million" json:"output_per_million"`
+}
+
+// RateLimitRedisConfig configures the Redis rate limit backend.
+type RateLimitRedisConfig struct {
+	// Address is the host:port of the Redis server. Default: "localhost:6379"
+	Address string `yaml:"address,omitempty" json:"address,omitempty"`
+
+	// Username authenticates with Redis 6 ACLs (optional).
+	Username string `yaml:"username,omitempty" json:"username,omitempty"`
+
+	// Password authenticates with the server (optional).
+	Password string `yaml:"password,omitempty" json:"password,omitempty" sensitive:"true"`
+
+	// DB is the Redis database number.
+	DB int `yaml:"db,omitempty" json:"db,omitempty"`
+
+	// TLS connects with TLS.
+	TLS bool `yaml:"tls,omitempty" json:"tls,omitempty"`
+
+	// KeyPrefix prefixes every key, to isolate tenants or deployments
+	// sharing a server. Default: "hector:ratelimit:"
+	KeyPrefix string `yaml:"key_prefix,omitempty" json:"key_prefix,omitempty"`
+
+	// Timeout bounds each command, so an unresponsive server fails the
+	// request over to on_store_error instead of blocking it. Default: 2s
+	Timeout Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
 }
 
 // RateLimitRule defines a single rate limit rule.
 type RateLimitRule struct {
-	// Type is the limit type ("token" or "count").
+	// Type is the limit type ("token", "count" or "cost").
 	Type string `yaml:"type" json:"type"`
 
 	// Window is the time window ("minute", "hour", "day", "week", "month").
 	Window string `yaml:"window" json:"window"`
 
-	// Limit is the maximum allowed in the window.
+	// Limit is the maximum allowed in the window. Cost limits are in
+	// US dollars.
 	Limit int64 `yaml:"limit" json:"limit"`
 }
 
@@ -71,6 +122,23 @@ func (c *RateLimitConfig) SetDefaults() {
 	if c.Backend == "" {
 		c.Backend = "memory"
 	}
+	if c.OnStoreError == "" {
+		c.OnStoreError = "allow"
+	}
+	if c.Backend == "redis" {
+		if c.Redis == nil {
+			c.Redis = &RateLimitRedisConfig{}
+		}
+		if c.Redis.Address == "" {
+			c.Redis.Address = "localhost:6379"
+		}
+		if c.Redis.KeyPrefix == "" {
+			c.Redis.KeyPrefix = "hector:ratelimit:"
+		}
+		if c.Redis.Timeout <= 0 {
+			c.Redis.Timeout = Duration(2 * time.Second)
+		}
+	}
 }
 
 // Validate validates the RateLimitConfig.
@@ -85,8 +153,13 @@ func (c *RateLimitConfig) Validate() error {
 	}
 
 	// Validate backend
-	if c.Backend != "" && c.Backend != "memory" && c.Backend != "sql" {
-		return fmt.Errorf("invalid rate_limiting.backend '%s', must be 'memory' or 'sql'", c.Backend)
+	if c.Backend != "" && c.Backend != "memory" && c.Backend != "sql" && c.Backend != "redis" {
+		return fmt.Errorf("invalid rate_limiting.backend '%s', must be 'memory', 'sql' or 'redis'", c.Backend)
+	}
+
+	// Validate store error policy
+	if c.OnStoreError != "" && c.OnStoreError != "allow" && c.OnStoreError != "deny" {
+		return fmt.Errorf("invalid rate_limiting.on_store_error '%s', must be 'allow' or 'deny'", c.OnStoreError)
 	}
 
 	// Validate SQL backend requires database reference
@@ -94,6 +167,18 @@ func (c *RateLimitConfig) Validate() error {
 		return fmt.Errorf("rate_limiting.backend 'sql' requires 'sql_database' reference")
 	}
 
+	if c.Redis != nil {
+		if c.Backend != "redis" {
+			return fmt.Errorf("rate_limiting.redis is only used with backend 'redis'")
+		}
+		if c.Redis.DB < 0 {
+			return fmt.Errorf("rate_limiting.redis.db must be non-negative")
+		}
+		if c.Redis.Timeout < 0 {
+			return fmt.Errorf("rate_limiting.redis.timeout must be non-negative")
+		}
+	}
+
 	// Validate limits
 	if len(c.Limits) == 0 {
 		return fmt.Errorf("rate_limiting.limits is required when rate limiting is enabled")
@@ -105,6 +190,12 @@ func (c *RateLimitConfig) Validate() error {
 		}
 	}
 
+	for model, price := range c.Pricing {
+		if price.InputPerMillion < 0 || price.OutputPerMillion < 0 {
+			return fmt.Errorf("rate_limiting.pricing.%s prices must not be negative", model)
+		}
+	}
+
 	return nil
 }
 
@@ -114,8 +205,11 @@ func (c *RateLimitConfig) validateLimit(index int, limit RateLimitRule) error {
 	if limit.Type == "" {
 		return fmt.Errorf("rate_limiting.limits[%d].type is required", index)
 	}
-	if limit.Type != "token" && limit.Type != "count" {
-		return fmt.Errorf("invalid rate_limiting.limits[%d].type '%s', must be 'token' or 'count'", index, limit.Type)
+	if limit.Type != "token" && limit.Type != "count" && limit.Type != "cost" {
+		return fmt.Errorf("invalid rate_limiting.limits[%d].type '%s', must be 'token', 'count' or 'cost'", index, limit.Type)
+	}
+	if limit.Type == "cost" && len(c.Pricing) == 0 {
+		return fmt.Errorf("rate_limiting.limits[%d] has type 'cost', which requires rate_limiting.pricing", index)
 	}
 
 	// Validate window
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"reflect"
+	"sort"
+)
+
+// SchemaType returns a zero value of the named config struct (e.g.
+// "AgentConfig") for schema reflection. Only structs reachable from Config
+// are known; the lookup is case-sensitive, matching the Go type name.
+func SchemaType(name string) (any, bool) {
+	t, ok := schemaTypes()[name]
+	if !ok {
+		return nil, false
+	}
+	return reflect.New(t).Interface(), true
+}
+
+// SchemaTypeNames returns the names accepted by SchemaType, sorted.
+func SchemaTypeNames() []string {
+	types := schemaTypes()
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// schemaTypes walks Config and collects every named struct type in this
+// package that it references, through pointers, slices and maps.
+func schemaTypes() map[string]reflect.Type {
+	root := reflect.TypeOf(Config{})
+	types := make(map[string]reflect.Type)
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice ||
+			t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct || t.PkgPath() != root.PkgPath() || t.Name() == "" {
+			return
+		}
+		if _, seen := types[t.Name()]; seen {
+			return
+		}
+		types[t.Name()] = t
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); f.IsExported() {
+				walk(f.Type)
+			}
+		}
+	}
+	walk(root)
+	return types
+}
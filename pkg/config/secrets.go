@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// SecretProvider resolves secret references in config values.
+//
+// A config value of the form "<scheme>://<ref>" is replaced at load time with
+// the secret returned by the provider registered for that scheme:
+//
+//	llms:
+//	  default:
+//	    api_key: vault://secret/data/hector#openai_api_key
+//	  claude:
+//	    api_key: awssm://prod/anthropic#api_key
+//
+// Only whole values are resolved, and only for registered schemes, so regular
+// URLs (http://, https://) are never affected.
+type SecretProvider interface {
+	// Scheme returns the URI scheme handled by this provider (e.g., "vault").
+	Scheme() string
+
+	// Resolve returns the secret for ref (the value without "<scheme>://").
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+var (
+	secretProvidersMu sync.RWMutex
+	secretProviders   = map[string]SecretProvider{
+		"vault": NewVaultSecretProvider(),
+		"awssm": NewAWSSecretsManagerProvider(),
+	}
+)
+
+// RegisterSecretProvider registers a provider for its scheme, replacing any
+// provider previously registered for the same scheme.
+func RegisterSecretProvider(p SecretProvider) {
+	secretProvidersMu.Lock()
+	defer secretProvidersMu.Unlock()
+	secretProviders[p.Scheme()] = p
+}
+
+// registeredSecretProviders returns a snapshot of the registered providers.
+func registeredSecretProviders() map[string]SecretProvider {
+	secretProvidersMu.RLock()
+	defer secretProvidersMu.RUnlock()
+
+	result := make(map[string]SecretProvider, len(secretProviders))
+	for scheme, p := range secretProviders {
+		result[scheme] = p
+	}
+	return result
+}
+
+// secretResolver resolves secret references across a parsed config map.
+type secretResolver struct {
+	providers map[string]SecretProvider
+	cache     map[string]string
+}
+
+// resolveSecrets replaces secret references in input with their values.
+// Each distinct reference is resolved once per load.
+func resolveSecrets(ctx context.Context, input map[string]any, providers map[string]SecretProvider) (map[string]any, error) {
+	r := &secretResolver{
+		providers: providers,
+		cache:     make(map[string]string),
+	}
+	result, err := r.resolveMap(ctx, "", input)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *secretResolver) resolveMap(ctx context.Context, path string, input map[string]any) (map[string]any, error) {
+	// Iterate in key order so the first error reported is deterministic
+	keys := make([]string, 0, len(input))
+	for k := range input {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := make(map[string]any, len(input))
+	for _, k := range keys {
+		v, err := r.resolveValue(ctx, joinConfigPath(path, k), input[k])
+		if err != nil {
+			return nil, err
+		}
+		result[k] = v
+	}
+	return result, nil
+}
+
+func (r *secretResolver) resolveValue(ctx context.Context, path string, v any) (any, error) {
+	switch val := v.(type) {
+	case string:
+		return r.resolveString(ctx, path, val)
+	case map[string]any:
+		return r.resolveMap(ctx, path, val)
+	case []any:
+		result := make([]any, len(val))
+		for i, item := range val {
+			resolved, err := r.resolveValue(ctx, fmt.Sprintf("%s[%d]", path, i), item)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = resolved
+		}
+		return result, nil
+	default:
+		return v, nil
+	}
+}
+
+func (r *secretResolver) resolveString(ctx context.Context, path, s string) (string, error) {
+	scheme, ref, ok := strings.Cut(s, "://")
+	if !ok {
+		return s, nil
+	}
+	p, ok := r.providers[scheme]
+	if !ok {
+		return s, nil
+	}
+
+	if cached, ok := r.cache[s]; ok {
+		return cached, nil
+	}
+
+	secret, err := p.Resolve(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve secret %q for %s: %w", s, path, err)
+	}
+	r.cache[s] = secret
+	return secret, nil
+}
+
+func joinConfigPath(parent, key string) string {
+	if parent == "" {
+		return key
+	}
+	return parent + "." + key
+}
+
+// splitSecretKey splits "path#key" into its path and optional key.
+func splitSecretKey(ref string) (path, key string) {
+	path, key, _ = strings.Cut(ref, "#")
+	return path, key
+}
+
+// selectSecretField picks a field from a structured secret.
+// If key is empty, the secret must contain exactly one field.
+func selectSecretField(fields map[string]any, key string) (string, error) {
+	if key == "" {
+		if len(fields) != 1 {
+			return "", fmt.Errorf("secret has %d fields, specify one with #key", len(fields))
+		}
+		for k := range fields {
+			key = k
+		}
+	}
+
+	v, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("secret has no field %q", key)
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(v), nil
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResolveSecretsVaultKV2(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/secret/data/hector" || r.Header.Get("X-Vault-Token") != "test-token" {
+			http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"data":{"openai":"sk-from-vault"},"metadata":{"version":1}}}`))
+	}))
+	defer srv.Close()
+
+	t.Setenv("VAULT_ADDR", srv.URL)
+	t.Setenv("VAULT_TOKEN", "test-token")
+
+	providers := map[string]SecretProvider{"vault": NewVaultSecretProvider()}
+	input := map[string]any{
+		"llms": map[string]any{
+			"default": map[string]any{
+				"api_key":  "vault://secret/data/hector#openai",
+				"base_url": "https://api.openai.com/v1",
+			},
+		},
+	}
+
+	resolved, err := resolveSecrets(context.Background(), input, providers)
+	if err != nil {
+		t.Fatalf("resolveSecrets failed: %v", err)
+	}
+	llm := resolved["llms"].(map[string]any)["default"].(map[string]any)
+	if llm["api_key"] != "sk-from-vault" {
+		t.Errorf("api_key = %v, want sk-from-vault", llm["api_key"])
+	}
+	if llm["base_url"] != "https://api.openai.com/v1" {
+		t.Errorf("base_url should be untouched, got %v", llm["base_url"])
+	}
+
+	// Missing fields fail clearly, naming the config path
+	input["llms"].(map[string]any)["default"].(map[string]any)["api_key"] = "vault://secret/data/hector#missing"
+	_, err = resolveSecrets(context.Background(), input, providers)
+	if err == nil || !strings.Contains(err.Error(), "llms.default.api_key") {
+		t.Errorf("expected error naming llms.default.api_key, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// defaultSecretRequestTimeout bounds each request to a secret manager.
+const defaultSecretRequestTimeout = 30 * time.Second
+
+// VaultSecretProvider resolves "vault://<path>#<key>" references using the
+// HashiCorp Vault HTTP API.
+//
+// The path is the API path below /v1/, e.g. "secret/data/hector" for a KV v2
+// mount named "secret". Both KV v1 and KV v2 responses are supported. The key
+// may be omitted if the secret has a single field.
+//
+// Configuration is read from the standard Vault environment variables:
+//   - VAULT_ADDR: server address (default: http://127.0.0.1:8200)
+//   - VAULT_TOKEN: authentication token (required)
+//   - VAULT_NAMESPACE: enterprise namespace (optional)
+type VaultSecretProvider struct {
+	client *http.Client
+}
+
+// NewVaultSecretProvider creates a Vault secret provider.
+func NewVaultSecretProvider() *VaultSecretProvider {
+	return &VaultSecretProvider{
+		client: &http.Client{Timeout: defaultSecretRequestTimeout},
+	}
+}
+
+// Scheme returns "vault".
+func (p *VaultSecretProvider) Scheme() string {
+	return "vault"
+}
+
+// Resolve fetches the secret field from Vault.
+func (p *VaultSecretProvider) Resolve(ctx context.Context, ref string) (string, error) {
+	path, key := splitSecretKey(ref)
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return "", fmt.Errorf("vault secret path is empty")
+	}
+
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		return "", fmt.Errorf("VAULT_TOKEN is not set")
+	}
+	addr := os.Getenv("VAULT_ADDR")
+	if addr == "" {
+		addr = "http://127.0.0.1:8200"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(addr, "/")+"/v1/"+path, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if ns := os.Getenv("VAULT_NAMESPACE"); ns != "" {
+		req.Header.Set("X-Vault-Namespace", ns)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read vault response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("vault returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var result struct {
+		Data map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("failed to parse vault response: %w", err)
+	}
+
+	fields := result.Data
+	// KV v2 nests the secret under data.data alongside data.metadata
+	if nested, ok := fields["data"].(map[string]any); ok {
+		if _, hasMeta := fields["metadata"]; hasMeta {
+			fields = nested
+		}
+	}
+	if len(fields) == 0 {
+		return "", fmt.Errorf("vault secret %q is empty", path)
+	}
+
+	return selectSecretField(fields, key)
+}
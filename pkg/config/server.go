@@ -17,6 +17,12 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/kadirpekel/hector/pkg/observability"
 )
@@ -29,6 +35,9 @@ const (
 	TransportGRPC    TransportType = "grpc"
 )
 
+// unixListenPrefix marks a Unix domain socket listen address.
+const unixListenPrefix = "unix://"
+
 // ServerConfig configures the A2A server.
 type ServerConfig struct {
 	// Host to bind to.
@@ -37,6 +46,16 @@ type ServerConfig struct {
 	// Port to listen on (HTTP/JSON-RPC).
 	Port int `yaml:"port,omitempty"`
 
+	// Listen is the address to bind to: "host:port", or
+	// "unix:///path/to/hector.sock" for a Unix domain socket.
+	// Overrides Host and Port when set.
+	Listen string `yaml:"listen,omitempty"`
+
+	// BaseURL is the public URL advertised in agent cards
+	// (e.g., "https://agents.example.com"). Default: http://<host>:<port>,
+	// or http://localhost when listening on a Unix socket.
+	BaseURL string `yaml:"base_url,omitempty"`
+
 	// GRPCPort is the port for gRPC server (default: 50051).
 	// Only used when Transport is "grpc".
 	GRPCPort int `yaml:"grpc_port,omitempty"`
@@ -50,6 +69,25 @@ type ServerConfig struct {
 	// CORS configuration.
 	CORS *CORSConfig `yaml:"cors,omitempty"`
 
+	// HTTP configures HTTP server connection timeouts.
+	HTTP *HTTPConfig `yaml:"http,omitempty"`
+
+	// ShutdownGrace is how long shutdown waits for in-flight requests,
+	// including streaming responses, to finish. Streams still open after
+	// it are ended with a final "done" event.
+	// Default: 20s
+	ShutdownGrace Duration `yaml:"shutdown_grace,omitempty"`
+
+	// A2A configures which optional A2A protocol methods are served.
+	A2A *A2AConfig `yaml:"a2a,omitempty"`
+
+	// Batch configures the batch message endpoint.
+	Batch *BatchConfig `yaml:"batch,omitempty"`
+
+	// StreamUsage emits live token usage updates during streamed responses.
+	// Clients can also request them per message. Default: false.
+	StreamUsage bool `yaml:"stream_usage,omitempty"`
+
 	// Auth configures JWT-based authentication.
 	Auth *AuthConfig `yaml:"auth,omitempty"`
 
@@ -98,6 +136,14 @@ type SessionsConfig struct {
 	// Database is a reference to a database defined in the databases section.
 	// Required when Backend is "sql".
 	Database string `yaml:"database,omitempty"`
+
+	// TTL deletes sessions that have had no new events for this long.
+	// Default: 0 (sessions never expire)
+	TTL Duration `yaml:"ttl,omitempty"`
+
+	// SweepInterval is how often expired sessions are deleted.
+	// Default: 1m, or TTL if shorter
+	SweepInterval Duration `yaml:"sweep_interval,omitempty"`
 }
 
 // MemoryConfig configures the memory index service.
@@ -231,7 +277,7 @@ type CheckpointConfig struct {
 	Enabled *bool `yaml:"enabled,omitempty"`
 
 	// Strategy determines when checkpoints are created.
-	// Values: "event" (default), "interval", "hybrid"
+	// Values: "event" (default), "interval", "hybrid", "on_error"
 	Strategy string `yaml:"strategy,omitempty"`
 
 	// Interval specifies checkpoint frequency (every N iterations).
@@ -308,8 +354,8 @@ func (c *CheckpointRecoveryConfig) SetDefaults() {
 
 // Validate checks the CheckpointConfig.
 func (c *CheckpointConfig) Validate() error {
-	if c.Strategy != "" && c.Strategy != "event" && c.Strategy != "interval" && c.Strategy != "hybrid" {
-		return fmt.Errorf("invalid strategy %q (valid: event, interval, hybrid)", c.Strategy)
+	if c.Strategy != "" && c.Strategy != "event" && c.Strategy != "interval" && c.Strategy != "hybrid" && c.Strategy != "on_error" {
+		return fmt.Errorf("invalid strategy %q (valid: event, interval, hybrid, on_error)", c.Strategy)
 	}
 	if c.Interval < 0 {
 		return fmt.Errorf("interval must be non-negative")
@@ -377,8 +423,201 @@ type CORSConfig struct {
 	AllowCredentials *bool `yaml:"allow_credentials,omitempty"`
 }
 
+// HTTPConfig configures HTTP server connection timeouts.
+//
+// WriteTimeout bounds how long a non-streaming response may take, including
+// agent execution. It is lifted for streaming (SSE) requests such as
+// message/stream, which stay open for the whole agent run; those rely on
+// client disconnects and the idle timeout instead. MaxStreamDuration caps
+// how long a single stream may stay open.
+//
+// Example:
+//
+//	server:
+//	  http:
+//	    read_timeout: 30s
+//	    read_header_timeout: 10s
+//	    write_timeout: 120s
+//	    idle_timeout: 120s
+//	    max_stream_duration: 15m
+type HTTPConfig struct {
+	// ReadTimeout is the maximum duration for reading the entire request.
+	// Default: 30s
+	ReadTimeout Duration `yaml:"read_timeout,omitempty"`
+
+	// ReadHeaderTimeout is the maximum duration for reading request headers.
+	// Protects against slowloris-style attacks.
+	// Default: 10s
+	ReadHeaderTimeout Duration `yaml:"read_header_timeout,omitempty"`
+
+	// WriteTimeout is the maximum duration before timing out writes of a
+	// non-streaming response.
+	// Default: 120s
+	WriteTimeout Duration `yaml:"write_timeout,omitempty"`
+
+	// IdleTimeout is the maximum time to wait for the next request on a
+	// keep-alive connection.
+	// Default: 120s
+	IdleTimeout Duration `yaml:"idle_timeout,omitempty"`
+
+	// MaxStreamDuration is the maximum time a streaming (SSE) response may
+	// stay open. When reached, the server sends a final event and closes the
+	// stream; the task keeps running and can be followed with
+	// tasks/resubscribe.
+	// Default: 0 (unlimited)
+	MaxStreamDuration Duration `yaml:"max_stream_duration,omitempty"`
+}
+
+// SetDefaults applies default values for HTTPConfig.
+func (c *HTTPConfig) SetDefaults() {
+	if c.ReadTimeout == 0 {
+		c.ReadTimeout = Duration(30 * time.Second)
+	}
+	if c.ReadHeaderTimeout == 0 {
+		c.ReadHeaderTimeout = Duration(10 * time.Second)
+	}
+	if c.WriteTimeout == 0 {
+		c.WriteTimeout = Duration(120 * time.Second)
+	}
+	if c.IdleTimeout == 0 {
+		c.IdleTimeout = Duration(120 * time.Second)
+	}
+}
+
+// Validate checks the HTTP configuration.
+func (c *HTTPConfig) Validate() error {
+	if c.ReadTimeout < 0 || c.ReadHeaderTimeout < 0 || c.WriteTimeout < 0 || c.IdleTimeout < 0 || c.MaxStreamDuration < 0 {
+		return fmt.Errorf("timeouts must be non-negative")
+	}
+	return nil
+}
+
+// A2AOptionalMethods lists the A2A methods that can be disabled.
+// message/send is always served.
+var A2AOptionalMethods = []string{
+	"message/stream",
+	"tasks/get",
+	"tasks/cancel",
+	"tasks/resubscribe",
+	"tasks/list",
+	"tasks/pushNotificationConfig/get",
+	"tasks/pushNotificationConfig/list",
+	"tasks/pushNotificationConfig/set",
+	"tasks/pushNotificationConfig/delete",
+	"agent/getAuthenticatedExtendedCard",
+}
+
+// A2AConfig configures the A2A protocol surface.
+//
+// Disabled methods answer with an "unsupported operation" error on every
+// transport and are reflected in agent card capabilities (e.g., disabling
+// message/stream turns off the streaming capability). Use this when a
+// deployment does not back a method, such as task lookups without task
+// persistence.
+//
+// Example:
+//
+//	server:
+//	  a2a:
+//	    disabled_methods: [tasks/get, tasks/resubscribe]
+//	    card_headers:
+//	      Cache-Control: max-age=300
+type A2AConfig struct {
+	// DisabledMethods lists optional A2A methods to refuse.
+	DisabledMethods []string `yaml:"disabled_methods,omitempty"`
+
+	// CardHeaders are static response headers for agent card and discovery
+	// responses, e.g. to let intermediaries cache them. Agents can override
+	// individual headers with their own card_headers.
+	CardHeaders map[string]string `yaml:"card_headers,omitempty"`
+}
+
+// Validate checks the A2A configuration.
+func (c *A2AConfig) Validate() error {
+	for _, method := range c.DisabledMethods {
+		if !slices.Contains(A2AOptionalMethods, method) {
+			return fmt.Errorf("disabled_methods: %q is not an optional A2A method (valid: %s)",
+				method, strings.Join(A2AOptionalMethods, ", "))
+		}
+	}
+	if err := validateHeaders(c.CardHeaders); err != nil {
+		return fmt.Errorf("card_headers: %w", err)
+	}
+	return nil
+}
+
+// validateHeaders checks static HTTP header names and values.
+func validateHeaders(headers map[string]string) error {
+	for name, value := range headers {
+		if name == "" || strings.ContainsAny(name, " \t\r\n:") {
+			return fmt.Errorf("invalid header name %q", name)
+		}
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("header %q: value must not contain line breaks", name)
+		}
+	}
+	return nil
+}
+
+// IsMethodEnabled reports whether an A2A method is served.
+func (c *A2AConfig) IsMethodEnabled(method string) bool {
+	return c == nil || !slices.Contains(c.DisabledMethods, method)
+}
+
+// BatchConfig configures POST /agents/{name}/messages:batch, which sends
+// many messages in one request.
+//
+// Example:
+//
+//	server:
+//	  batch:
+//	    max_items: 500
+//	    max_concurrency: 8
+type BatchConfig struct {
+	// MaxItems is the maximum number of messages in one batch.
+	// Default: 100
+	MaxItems int `yaml:"max_items,omitempty"`
+
+	// MaxConcurrency is the number of batch messages processed at once.
+	// Default: 4
+	MaxConcurrency int `yaml:"max_concurrency,omitempty"`
+}
+
+// SetDefaults applies default values for BatchConfig.
+func (c *BatchConfig) SetDefaults() {
+	if c.MaxItems == 0 {
+		c.MaxItems = 100
+	}
+	if c.MaxConcurrency == 0 {
+		c.MaxConcurrency = 4
+	}
+}
+
+// Validate checks the batch configuration.
+func (c *BatchConfig) Validate() error {
+	if c.MaxItems < 0 {
+		return fmt.Errorf("max_items must be non-negative")
+	}
+	if c.MaxConcurrency < 0 {
+		return fmt.Errorf("max_concurrency must be non-negative")
+	}
+	return nil
+}
+
 // SetDefaults applies default values.
 func (c *ServerConfig) SetDefaults() {
+	// A TCP listen address takes precedence over host and port
+	if c.Listen != "" && c.UnixSocket() == "" {
+		if host, port, err := net.SplitHostPort(c.Listen); err == nil {
+			if host != "" {
+				c.Host = host
+			}
+			if p, err := strconv.Atoi(port); err == nil && p > 0 {
+				c.Port = p
+			}
+		}
+	}
+
 	if c.Host == "" {
 		c.Host = "0.0.0.0"
 	}
@@ -404,6 +643,21 @@ func (c *ServerConfig) SetDefaults() {
 		}
 	}
 
+	// Default HTTP timeouts for production hardening
+	if c.HTTP == nil {
+		c.HTTP = &HTTPConfig{}
+	}
+	c.HTTP.SetDefaults()
+
+	if c.ShutdownGrace == 0 {
+		c.ShutdownGrace = Duration(20 * time.Second)
+	}
+
+	if c.Batch == nil {
+		c.Batch = &BatchConfig{}
+	}
+	c.Batch.SetDefaults()
+
 	// Apply auth defaults if configured
 	if c.Auth != nil {
 		c.Auth.SetDefaults()
@@ -445,6 +699,29 @@ func (c *ServerConfig) Validate() error {
 		return fmt.Errorf("invalid grpc_port %d", c.GRPCPort)
 	}
 
+	if c.ShutdownGrace < 0 {
+		return fmt.Errorf("shutdown_grace must be non-negative")
+	}
+
+	if c.Listen != "" {
+		if strings.HasPrefix(c.Listen, unixListenPrefix) {
+			if c.UnixSocket() == "" {
+				return fmt.Errorf("listen %q is missing the socket path", c.Listen)
+			}
+		} else if _, port, err := net.SplitHostPort(c.Listen); err != nil {
+			return fmt.Errorf("invalid listen address %q (use host:port or unix:///path)", c.Listen)
+		} else if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+			return fmt.Errorf("invalid port in listen address %q", c.Listen)
+		}
+	}
+
+	if c.BaseURL != "" {
+		u, err := url.Parse(c.BaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid base_url %q (must be an http or https URL)", c.BaseURL)
+		}
+	}
+
 	if c.Transport != TransportJSONRPC && c.Transport != TransportGRPC {
 		return fmt.Errorf("invalid transport %q (valid: json-rpc, grpc)", c.Transport)
 	}
@@ -455,6 +732,27 @@ func (c *ServerConfig) Validate() error {
 		}
 	}
 
+	// Validate HTTP config
+	if c.HTTP != nil {
+		if err := c.HTTP.Validate(); err != nil {
+			return fmt.Errorf("http: %w", err)
+		}
+	}
+
+	// Validate A2A config
+	if c.A2A != nil {
+		if err := c.A2A.Validate(); err != nil {
+			return fmt.Errorf("a2a: %w", err)
+		}
+	}
+
+	// Validate batch config
+	if c.Batch != nil {
+		if err := c.Batch.Validate(); err != nil {
+			return fmt.Errorf("batch: %w", err)
+		}
+	}
+
 	// Validate auth config
 	if c.Auth != nil {
 		if err := c.Auth.Validate(); err != nil {
@@ -569,6 +867,9 @@ func (c *SessionsConfig) SetDefaults() {
 	if c.Backend == "" {
 		c.Backend = StorageBackendInMemory
 	}
+	if c.TTL > 0 && c.SweepInterval == 0 {
+		c.SweepInterval = min(Duration(time.Minute), c.TTL)
+	}
 }
 
 // Validate checks the sessions configuration.
@@ -588,6 +889,10 @@ func (c *SessionsConfig) Validate() error {
 		return fmt.Errorf("database reference requires backend to be sql")
 	}
 
+	if c.TTL < 0 || c.SweepInterval < 0 {
+		return fmt.Errorf("ttl and sweep_interval must be non-negative")
+	}
+
 	return nil
 }
 
@@ -654,10 +959,36 @@ func (c *MemoryConfig) IsVector() bool {
 }
 
 // Address returns the HTTP server address.
+// For Unix sockets this is the listen address (unix:///path).
 func (c *ServerConfig) Address() string {
+	if c.UnixSocket() != "" {
+		return c.Listen
+	}
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
 
+// UnixSocket returns the socket path when listening on a Unix domain socket,
+// or "" when listening on TCP.
+func (c *ServerConfig) UnixSocket() string {
+	if !strings.HasPrefix(c.Listen, unixListenPrefix) {
+		return ""
+	}
+	return strings.TrimPrefix(c.Listen, unixListenPrefix)
+}
+
+// URL returns the base URL advertised to clients (e.g., in agent cards).
+func (c *ServerConfig) URL() string {
+	switch {
+	case c.BaseURL != "":
+		return strings.TrimSuffix(c.BaseURL, "/")
+	case c.UnixSocket() != "":
+		// Clients dialing the socket accept any host
+		return "http://localhost"
+	default:
+		return "http://" + c.Address()
+	}
+}
+
 // GRPCAddress returns the gRPC server address.
 func (c *ServerConfig) GRPCAddress() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.GRPCPort)
@@ -15,7 +15,16 @@
 
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// toolPrefixPattern matches prefixes that keep tool names valid for LLM
+// providers.
+var toolPrefixPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
 
 // ToolType identifies the tool type.
 type ToolType string
@@ -56,11 +65,23 @@ type ToolConfig struct {
 	Args []string `yaml:"args,omitempty" json:"args,omitempty" jsonschema:"title=Args,description=Arguments for MCP stdio transport"`
 
 	// Env for MCP stdio transport.
-	Env map[string]string `yaml:"env,omitempty" json:"env,omitempty" jsonschema:"title=Environment Variables,description=Environment variables for MCP stdio transport"`
+	Env map[string]string `yaml:"env,omitempty" json:"env,omitempty" jsonschema:"title=Environment Variables,description=Environment variables for MCP stdio transport" sensitive:"true"`
 
 	// Filter limits which tools are exposed from an MCP server.
 	Filter []string `yaml:"filter,omitempty" json:"filter,omitempty" jsonschema:"title=Filter,description=Limit which tools are exposed from MCP server"`
 
+	// Exclude hides tools of an MCP server. Applies after Filter.
+	Exclude []string `yaml:"exclude,omitempty" json:"exclude,omitempty" jsonschema:"title=Exclude,description=Tools of the MCP server to hide"`
+
+	// Prefix namespaces the tools of an MCP server (e.g., "fs_" exposes
+	// read_file as fs_read_file). Filter and Exclude use the server's names.
+	Prefix string `yaml:"prefix,omitempty" json:"prefix,omitempty" jsonschema:"title=Prefix,description=Prefix added to the names of the MCP server's tools"`
+
+	// AllowedAgents limits which agents are given this tool. Other agents
+	// do not see it even when their tools list includes it.
+	// Empty allows all agents.
+	AllowedAgents []string `yaml:"allowed_agents,omitempty" json:"allowed_agents,omitempty" jsonschema:"title=Allowed Agents,description=Agents allowed to use this tool (all if empty)"`
+
 	// Function-specific configuration
 	// Handler is the function name (for type: function).
 	Handler string `yaml:"handler,omitempty" json:"handler,omitempty" jsonschema:"title=Handler,description=Function name (for type=function)"`
@@ -84,12 +105,72 @@ type ToolConfig struct {
 	// DenyByDefault requires explicit allowed_commands whitelist.
 	DenyByDefault *bool `yaml:"deny_by_default,omitempty" json:"deny_by_default,omitempty" jsonschema:"title=Deny By Default,description=Require explicit allowed_commands whitelist,default=false"`
 
+	// HTTP request tool configuration (for handler: http_request)
+	// AllowedHosts limits which hosts http_request may call. Entries match
+	// exactly or, with a "*." prefix, any subdomain. Empty allows any host
+	// with a public address.
+	AllowedHosts []string `yaml:"allowed_hosts,omitempty" json:"allowed_hosts,omitempty" jsonschema:"title=Allowed Hosts,description=Hosts http_request may call (e.g. api.example.com or *.example.com; any public host if empty)"`
+
+	// MaxResponseBytes caps the response body returned by http_request.
+	// Longer bodies are truncated.
+	MaxResponseBytes int64 `yaml:"max_response_bytes,omitempty" json:"max_response_bytes,omitempty" jsonschema:"title=Max Response Bytes,description=Response body size returned by http_request before truncation,minimum=0,default=65536"`
+
+	// SQL query tool configuration (for handler: sql_query)
+	// Databases lists the databases (from the databases section) that
+	// sql_query may query.
+	Databases []string `yaml:"databases,omitempty" json:"databases,omitempty" jsonschema:"title=Databases,description=Databases sql_query may query"`
+
+	// MaxRows caps the rows returned by sql_query. Default: 100.
+	MaxRows int `yaml:"max_rows,omitempty" json:"max_rows,omitempty" jsonschema:"title=Max Rows,description=Rows returned by sql_query before truncation,minimum=0,default=100"`
+
+	// AllowWrites lets sql_query run statements other than SELECT.
+	AllowWrites *bool `yaml:"allow_writes,omitempty" json:"allow_writes,omitempty" jsonschema:"title=Allow Writes,description=Allow sql_query to run statements other than SELECT,default=false"`
+
+	// Execution limits
+	// Timeout cancels a tool call that runs longer and returns a timeout
+	// error to the model. http_request and sql_query also use it for the
+	// request or query itself. Default: 10m (30s for http_request and
+	// sql_query).
+	Timeout Duration `yaml:"timeout,omitempty" json:"timeout,omitempty" jsonschema:"title=Timeout,description=Maximum duration of a tool call,default=10m"`
+
+	// MaxOutputBytes truncates larger tool results before they reach the
+	// model. Default: 1MB.
+	MaxOutputBytes int `yaml:"max_output_bytes,omitempty" json:"max_output_bytes,omitempty" jsonschema:"title=Max Output Bytes,description=Tool result size before truncation,minimum=0,default=1048576"`
+
 	// HITL (Human-in-the-Loop) settings
 	// RequireApproval requires user approval before execution.
 	RequireApproval *bool `yaml:"require_approval,omitempty" json:"require_approval,omitempty" jsonschema:"title=Requires Approval (HITL),description=Whether this tool requires human approval,default=false"`
 
 	// ApprovalPrompt is the message shown when requesting approval.
 	ApprovalPrompt string `yaml:"approval_prompt,omitempty" json:"approval_prompt,omitempty" jsonschema:"title=Approval Prompt,description=Message shown when requesting approval"`
+
+	// Result summarization settings
+	// SummarizeIfOverTokens summarizes results larger than this many tokens
+	// (estimated) with an LLM before they are returned to the model.
+	// 0 disables summarization.
+	SummarizeIfOverTokens int `yaml:"summarize_if_over_tokens,omitempty" json:"summarize_if_over_tokens,omitempty" jsonschema:"title=Summarize If Over Tokens,description=Summarize results larger than this many tokens (0 = never),minimum=0,default=0"`
+
+	// SummarizerLLM references an LLM from the global llms config used to
+	// summarize oversized results. Uses the calling agent's LLM if empty.
+	SummarizerLLM string `yaml:"summarizer_llm,omitempty" json:"summarizer_llm,omitempty" jsonschema:"title=Summarizer LLM,description=LLM reference for result summarization (uses agent LLM if empty)"`
+
+	// Error handling settings
+	// OnError controls what happens when the tool fails:
+	// "feedback" (default) returns the error to the model as the tool result,
+	// "fail" aborts the run, and "retry" re-invokes the tool with backoff
+	// before falling back to feedback.
+	OnError string `yaml:"on_error,omitempty" json:"on_error,omitempty" jsonschema:"title=On Error,description=How tool failures are handled,enum=feedback,enum=fail,enum=retry,default=feedback"`
+
+	// MaxRetries is the number of additional attempts when on_error is "retry".
+	MaxRetries int `yaml:"max_retries,omitempty" json:"max_retries,omitempty" jsonschema:"title=Max Retries,description=Additional attempts when on_error is retry,minimum=0,default=2"`
+
+	// RetryBackoff is the delay before the first retry; it doubles on each attempt.
+	RetryBackoff Duration `yaml:"retry_backoff,omitempty" json:"retry_backoff,omitempty" jsonschema:"title=Retry Backoff,description=Delay before the first retry (doubles on each attempt),default=500ms"`
+}
+
+// AllowsAgent reports whether the named agent may use the tool.
+func (c *ToolConfig) AllowsAgent(agentName string) bool {
+	return len(c.AllowedAgents) == 0 || slices.Contains(c.AllowedAgents, agentName)
 }
 
 // SetDefaults applies default values.
@@ -124,10 +205,13 @@ func (c *ToolConfig) SetDefaults() {
 			case "write_file", "search_replace", "apply_patch":
 				// File modification tools: require approval (high risk)
 				c.RequireApproval = BoolPtr(true)
-			case "web_request":
+			case "web_request", "http_request":
 				// External requests: require approval (high risk)
 				c.RequireApproval = BoolPtr(true)
-			case "read_file", "grep_search", "todo_write":
+			case "sql_query":
+				// Database access: require approval (high risk)
+				c.RequireApproval = BoolPtr(true)
+			case "read_file", "grep_search", "todo_write", "pin_context":
 				// Read-only or safe operations: no approval needed
 				c.RequireApproval = BoolPtr(false)
 			default:
@@ -159,16 +243,56 @@ func (c *ToolConfig) Validate() error {
 		if c.URL == "" && c.Command == "" {
 			return fmt.Errorf("mcp tool requires url or command")
 		}
+		if c.Prefix != "" && !toolPrefixPattern.MatchString(c.Prefix) {
+			return fmt.Errorf("invalid prefix %q (use letters, digits, '_' or '-')", c.Prefix)
+		}
 	}
 
 	if c.Type == ToolTypeFunction {
 		if c.Handler == "" {
 			return fmt.Errorf("function tool requires handler")
 		}
+		if c.Handler == "sql_query" && len(c.Databases) == 0 {
+			return fmt.Errorf("sql_query tool requires databases")
+		}
 	}
 
 	// Command tools validation is lenient - defaults are applied
 
+	for _, host := range c.AllowedHosts {
+		if strings.TrimSpace(host) == "" || strings.ContainsAny(host, "/:") {
+			return fmt.Errorf("invalid allowed_hosts entry %q (use a hostname such as api.example.com or *.example.com)", host)
+		}
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("timeout must be non-negative")
+	}
+	if c.MaxResponseBytes < 0 {
+		return fmt.Errorf("max_response_bytes must be non-negative")
+	}
+	if c.MaxOutputBytes < 0 {
+		return fmt.Errorf("max_output_bytes must be non-negative")
+	}
+	if c.MaxRows < 0 {
+		return fmt.Errorf("max_rows must be non-negative")
+	}
+
+	if c.SummarizeIfOverTokens < 0 {
+		return fmt.Errorf("summarize_if_over_tokens must be non-negative")
+	}
+
+	switch c.OnError {
+	case "", "feedback", "fail", "retry":
+	default:
+		return fmt.Errorf("invalid on_error %q (valid: feedback, fail, retry)", c.OnError)
+	}
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("max_retries must be non-negative")
+	}
+	if c.RetryBackoff < 0 {
+		return fmt.Errorf("retry_backoff must be non-negative")
+	}
+
 	return nil
 }
 
@@ -244,6 +368,13 @@ func GetDefaultToolConfigs() map[string]*ToolConfig {
 			Description: "Make HTTP requests to external APIs or services. Supports GET, POST, PUT, DELETE, PATCH, HEAD, OPTIONS methods.",
 			// Note: Approval defaults are set in SetDefaults() - requires approval by default
 		},
+		"http_request": {
+			Type:        ToolTypeFunction,
+			Handler:     "http_request",
+			Enabled:     BoolPtr(true),
+			Description: "Call a REST API with a method, URL, headers and body. Returns the status, headers and a size-capped body.",
+			// Note: Approval defaults are set in SetDefaults() - requires approval by default
+		},
 
 		// Task management tools
 		"todo_write": {
@@ -253,5 +384,14 @@ func GetDefaultToolConfigs() map[string]*ToolConfig {
 			Description: "Create and manage a structured task list for tracking progress. Use for complex multi-step tasks (3+ steps) to demonstrate thoroughness.",
 			// Safe operation - no approval needed
 		},
+
+		// Context management tools
+		"pin_context": {
+			Type:        ToolTypeFunction,
+			Handler:     "pin_context",
+			Enabled:     BoolPtr(true),
+			Description: "Pin an important fact, constraint, or user preference so it stays in context even after older messages are trimmed.",
+			// Safe operation - no approval needed
+		},
 	}
 }
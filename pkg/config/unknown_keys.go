@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnknownKey is a config key that matches no configuration field, such as
+// a misspelled "temprature:".
+type UnknownKey struct {
+	// Line is the 1-based line of the key in the config file.
+	Line int
+
+	// Key is the key as written.
+	Key string
+
+	// Type is the config type the key was found in (e.g. "config.LLMConfig").
+	Type string
+}
+
+func (k UnknownKey) String() string {
+	return fmt.Sprintf("line %d: unknown key %q in %s", k.Line, k.Key, k.Type)
+}
+
+// UnknownKeysError is returned by strict loading when the config contains
+// unknown keys.
+type UnknownKeysError struct {
+	Keys []UnknownKey
+}
+
+func (e *UnknownKeysError) Error() string {
+	lines := make([]string, len(e.Keys))
+	for i, k := range e.Keys {
+		lines[i] = k.String()
+	}
+	return "unknown config keys:\n  " + strings.Join(lines, "\n  ")
+}
+
+// unknownFieldPattern matches yaml.v3's error for a key with no struct field.
+var unknownFieldPattern = regexp.MustCompile(`^line (\d+): field (.+) not found in type (\S+)$`)
+
+// findUnknownKeys returns the keys of a YAML (or JSON) config document that
+// match no Config field. Other decoding problems, such as ${VAR} references
+// not yet expanded into numeric fields, are left to the regular loader.
+func findUnknownKeys(data []byte) []UnknownKey {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+
+	var cfg Config
+	var typeErr *yaml.TypeError
+	if err := dec.Decode(&cfg); !errors.As(err, &typeErr) {
+		return nil
+	}
+
+	var keys []UnknownKey
+	for _, msg := range typeErr.Errors {
+		m := unknownFieldPattern.FindStringSubmatch(msg)
+		if m == nil {
+			continue
+		}
+		line, _ := strconv.Atoi(m[1])
+		keys = append(keys, UnknownKey{Line: line, Key: m[2], Type: m[3]})
+	}
+	return keys
+}
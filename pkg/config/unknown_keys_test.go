@@ -0,0 +1,60 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfigFileUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `
+server:
+  port: ${UNKNOWN_KEYS_TEST_PORT:-8081}
+llms:
+  default:
+    provider: openai
+    model: gpt-4o
+    api_key: sk-test
+    temprature: 0.2
+agents:
+  assistant:
+    llm: default
+    sub_agent: [helper]
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Lenient (default): unknown keys are ignored with a warning
+	cfg, loader, err := LoadConfigFile(context.Background(), path)
+	if err != nil {
+		t.Fatalf("LoadConfigFile() error = %v", err)
+	}
+	loader.Close()
+	if cfg.Server.Port != 8081 {
+		t.Errorf("port = %d, want 8081", cfg.Server.Port)
+	}
+
+	// Strict: unknown keys are errors with line numbers; the unexpanded
+	// ${...} port is not reported
+	_, _, err = LoadConfigFile(context.Background(), path, WithStrict())
+	var unknownErr *UnknownKeysError
+	if !errors.As(err, &unknownErr) {
+		t.Fatalf("LoadConfigFile(strict) error = %v, want UnknownKeysError", err)
+	}
+	want := []UnknownKey{
+		{Line: 9, Key: "temprature", Type: "config.LLMConfig"},
+		{Line: 13, Key: "sub_agent", Type: "config.AgentConfig"},
+	}
+	if len(unknownErr.Keys) != len(want) {
+		t.Fatalf("unknown keys = %v, want %v", unknownErr.Keys, want)
+	}
+	for i, k := range want {
+		if unknownErr.Keys[i] != k {
+			t.Errorf("key %d = %+v, want %+v", i, unknownErr.Keys[i], k)
+		}
+	}
+}
@@ -104,14 +104,14 @@ type ZeroConfig struct {
 	VectorType string
 
 	// VectorHost is the host:port for external vector databases (qdrant, chroma, weaviate, milvus).
-	// Example: "localhost:6333" for Qdrant
+	// Example: "localhost:6334" for Qdrant
 	VectorHost string
 
 	// VectorAPIKey is the API key for vector databases that require authentication (pinecone).
 	VectorAPIKey string
 
 	// EmbedderProvider overrides the auto-detected embedder provider.
-	// Values: "openai", "ollama", "cohere"
+	// Values: "openai", "ollama", "cohere", "voyage"
 	// Auto-detection: Uses LLM provider if it has embeddings (openai), otherwise ollama.
 	EmbedderProvider string
 
@@ -457,6 +457,8 @@ func expandDocsFolder(cfg *Config, agentConfig *AgentConfig, opts ZeroConfig) {
 		embedderAPIKey = os.Getenv("OPENAI_API_KEY")
 	} else if embedderProvider == "cohere" {
 		embedderAPIKey = os.Getenv("COHERE_API_KEY")
+	} else if embedderProvider == "voyage" {
+		embedderAPIKey = os.Getenv("VOYAGE_API_KEY")
 	}
 
 	embedderConfig := &EmbedderConfig{
@@ -557,7 +559,7 @@ func createVectorStoreConfig(opts ZeroConfig) *VectorStoreConfig {
 			config.Host = opts.VectorHost
 		} else {
 			config.Host = "localhost"
-			config.Port = 6333 // Qdrant default
+			config.Port = 6334 // Qdrant gRPC default
 		}
 		if opts.VectorAPIKey != "" {
 			config.APIKey = opts.VectorAPIKey
@@ -659,6 +661,8 @@ func detectEmbedderModelForProvider(provider string) string {
 		return "nomic-embed-text"
 	case "cohere":
 		return "embed-english-v3.0"
+	case "voyage":
+		return "voyage-3.5"
 	default:
 		return "nomic-embed-text"
 	}
@@ -0,0 +1,40 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embedder
+
+import "github.com/kadirpekel/hector/pkg/utils"
+
+// packBatches splits texts into consecutive batches of at most maxCount
+// texts and maxTokens estimated tokens, so each batch fits one request.
+// A text estimated over maxTokens is sent alone and left for the API to
+// truncate or reject. maxTokens <= 0 disables the token limit.
+func packBatches(texts []string, maxCount, maxTokens int) [][]string {
+	var batches [][]string
+	start, tokens := 0, 0
+	for i, text := range texts {
+		n := utils.EstimateTokens(text)
+		full := i-start >= maxCount || (maxTokens > 0 && tokens+n > maxTokens)
+		if full && i > start {
+			batches = append(batches, texts[start:i])
+			start, tokens = i, 0
+		}
+		tokens += n
+	}
+	if start < len(texts) {
+		batches = append(batches, texts[start:])
+	}
+	return batches
+}
@@ -0,0 +1,43 @@
+package embedder
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPackBatches(t *testing.T) {
+	short := "abcd"                 // 1 token
+	long := strings.Repeat("x", 40) // 10 tokens
+
+	tests := []struct {
+		name      string
+		texts     []string
+		maxCount  int
+		maxTokens int
+		want      []int
+	}{
+		{"count", []string{short, short, short, short, short}, 2, 0, []int{2, 2, 1}},
+		{"tokens", []string{long, long, short, long}, 10, 20, []int{2, 2}},
+		{"oversized text alone", []string{short, long, short}, 10, 5, []int{1, 1, 1}},
+		{"empty", nil, 10, 10, nil},
+	}
+	for _, tt := range tests {
+		batches := packBatches(tt.texts, tt.maxCount, tt.maxTokens)
+		var sizes []int
+		total := 0
+		for _, b := range batches {
+			sizes = append(sizes, len(b))
+			total += len(b)
+		}
+		if len(sizes) != len(tt.want) || total != len(tt.texts) {
+			t.Errorf("%s: batch sizes = %v, want %v", tt.name, sizes, tt.want)
+			continue
+		}
+		for i := range sizes {
+			if sizes[i] != tt.want[i] {
+				t.Errorf("%s: batch sizes = %v, want %v", tt.name, sizes, tt.want)
+				break
+			}
+		}
+	}
+}
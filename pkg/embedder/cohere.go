@@ -251,7 +251,7 @@ func (e *CohereEmbedder) embedBatch(ctx context.Context, texts []string) ([][]fl
 	if resp.StatusCode != http.StatusOK {
 		var errorResp cohereErrorResponse
 		if err := json.Unmarshal(body, &errorResp); err == nil {
-			return nil, fmt.Errorf("Cohere API error: %s", errorResp.Message)
+			return nil, fmt.Errorf("Cohere API error (status %d): %s", resp.StatusCode, errorResp.Message)
 		}
 		return nil, fmt.Errorf("Cohere API returned status %d: %s", resp.StatusCode, string(body))
 	}
@@ -43,3 +43,20 @@ type Embedder interface {
 	// Close releases any resources held by the embedder.
 	Close() error
 }
+
+// QueryEmbedder is implemented by embedders that embed search queries
+// differently from the documents they are matched against, such as
+// models trained with query and document input types.
+type QueryEmbedder interface {
+	// EmbedQuery converts a search query to a vector embedding.
+	EmbedQuery(ctx context.Context, text string) ([]float32, error)
+}
+
+// EmbedQuery embeds a search query, using EmbedQuery if e implements
+// QueryEmbedder and Embed otherwise.
+func EmbedQuery(ctx context.Context, e Embedder, text string) ([]float32, error) {
+	if qe, ok := e.(QueryEmbedder); ok {
+		return qe.EmbedQuery(ctx, text)
+	}
+	return e.Embed(ctx, text)
+}
@@ -25,6 +25,15 @@ import (
 	"time"
 )
 
+const (
+	// openaiMaxBatchSize is the most inputs OpenAI accepts per request.
+	openaiMaxBatchSize = 2048
+
+	// openaiMaxBatchTokens caps the estimated tokens packed into one
+	// request, half of OpenAI's 300,000 limit to absorb estimation error.
+	openaiMaxBatchTokens = 150_000
+)
+
 // OpenAIEmbedder implements Embedder using OpenAI's embeddings API.
 //
 // Ported from legacy pkg/embedders/openai.go.
@@ -57,9 +66,9 @@ type OpenAIConfig struct {
 	// Timeout for API requests (default: 30s).
 	Timeout time.Duration
 
-	// BatchSize for batch embedding (default: 100).
-	// Note: OpenAI supports up to 2048 inputs per request, but we use 100 as default
-	// to stay within token limits (300,000 tokens total per request).
+	// BatchSize for batch embedding (default: 100, max: 2048).
+	// Batches are also split to stay within OpenAI's per-request token
+	// limit (300,000 tokens), so long chunks never overflow a request.
 	BatchSize int
 
 	// EncodingFormat specifies the format to return embeddings in.
@@ -136,6 +145,9 @@ func NewOpenAIEmbedder(cfg OpenAIConfig) (*OpenAIEmbedder, error) {
 	if batchSize == 0 {
 		batchSize = 100
 	}
+	if batchSize > openaiMaxBatchSize {
+		return nil, fmt.Errorf("batch size %d exceeds OpenAI's limit of %d", batchSize, openaiMaxBatchSize)
+	}
 
 	encodingFormat := cfg.EncodingFormat
 	if encodingFormat == "" {
@@ -174,14 +186,8 @@ func (e *OpenAIEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]fl
 
 	results := make([][]float32, 0, len(texts))
 
-	// Process in batches
-	for i := 0; i < len(texts); i += e.batchSize {
-		end := i + e.batchSize
-		if end > len(texts) {
-			end = len(texts)
-		}
-
-		batch := texts[i:end]
+	// Pack as many texts per request as the count and token limits allow
+	for _, batch := range packBatches(texts, e.batchSize, openaiMaxBatchTokens) {
 		embeddings, err := e.embedBatch(ctx, batch)
 		if err != nil {
 			return nil, err
@@ -239,8 +245,8 @@ func (e *OpenAIEmbedder) embedBatch(ctx context.Context, texts []string) ([][]fl
 	if resp.StatusCode != http.StatusOK {
 		var errorResp openaiErrorResponse
 		if err := json.Unmarshal(body, &errorResp); err == nil {
-			return nil, fmt.Errorf("OpenAI API error: %s (type: %s, code: %s)",
-				errorResp.Error.Message, errorResp.Error.Type, errorResp.Error.Code)
+			return nil, fmt.Errorf("OpenAI API error (status %d): %s (type: %s, code: %s)",
+				resp.StatusCode, errorResp.Error.Message, errorResp.Error.Type, errorResp.Error.Code)
 		}
 		return nil, fmt.Errorf("OpenAI API returned status %d: %s", resp.StatusCode, string(body))
 	}
@@ -0,0 +1,321 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embedder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// VoyageMaxBatchSize is the most inputs Voyage accepts per request.
+const VoyageMaxBatchSize = 1000
+
+// voyageBatchTokens returns the per-request token budget used to pack
+// batches for a Voyage model: half the documented limit, since token
+// counts are estimated rather than tokenized.
+func voyageBatchTokens(model string) int {
+	switch model {
+	case "voyage-3.5-lite", "voyage-3-lite":
+		return 500_000
+	case "voyage-3.5", "voyage-3", "voyage-2":
+		return 160_000
+	default:
+		return 60_000
+	}
+}
+
+// VoyageEmbedder implements Embedder using Voyage AI's embeddings API.
+//
+// Documents are embedded with the configured input type (default
+// "document"); queries passed to EmbedQuery use "query", which Voyage
+// prepends a retrieval prompt for.
+// See: https://docs.voyageai.com/reference/embeddings-api
+type VoyageEmbedder struct {
+	client    *http.Client
+	apiKey    string
+	baseURL   string
+	model     string
+	dimension int
+	batchSize int
+	inputType string // "document", "query" or "none"
+	outputDim *int   // Optional output dimension (256, 512, 1024, 2048)
+	truncate  bool
+}
+
+// VoyageConfig configures the Voyage embedder.
+type VoyageConfig struct {
+	// APIKey for Voyage API (required).
+	APIKey string
+
+	// BaseURL for the API (default: https://api.voyageai.com/v1).
+	BaseURL string
+
+	// Model name (default: voyage-3.5).
+	// Supported: voyage-3.5, voyage-3.5-lite, voyage-3-large, voyage-code-3, etc.
+	Model string
+
+	// Dimension of embeddings (auto-detected from model if 0).
+	Dimension int
+
+	// Timeout for API requests (default: 30s).
+	Timeout time.Duration
+
+	// BatchSize for batch embedding (default: 128, max: 1000).
+	// Voyage also caps tokens per request, so very long inputs may need
+	// smaller batches.
+	BatchSize int
+
+	// InputType for Embed and EmbedBatch.
+	// Values: "document", "query", "none" (default: "document")
+	InputType string
+
+	// OutputDimension for models supporting flexible dimensions (optional).
+	// Values: 256, 512, 1024, 2048
+	OutputDimension *int
+
+	// DisableTruncation rejects inputs over the model's context length
+	// instead of truncating them.
+	DisableTruncation bool
+}
+
+// voyageRequest represents the request payload for Voyage embeddings API.
+type voyageRequest struct {
+	Input           []string `json:"input"`
+	Model           string   `json:"model"`
+	InputType       *string  `json:"input_type"`
+	OutputDimension *int     `json:"output_dimension,omitempty"`
+	Truncation      bool     `json:"truncation"`
+}
+
+// voyageResponse represents the response from Voyage embeddings API.
+type voyageResponse struct {
+	Data []struct {
+		Embedding []float32 `json:"embedding"`
+		Index     int       `json:"index"`
+	} `json:"data"`
+	Model string `json:"model"`
+	Usage struct {
+		TotalTokens int `json:"total_tokens"`
+	} `json:"usage"`
+}
+
+// voyageErrorResponse represents an error response from Voyage API.
+type voyageErrorResponse struct {
+	Detail string `json:"detail"`
+}
+
+// NewVoyageEmbedder creates a new Voyage embedder.
+func NewVoyageEmbedder(cfg VoyageConfig) (*VoyageEmbedder, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("API key is required for Voyage embedder")
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = "voyage-3.5"
+	}
+
+	dimension := cfg.Dimension
+	if dimension == 0 {
+		dimension = VoyageModelDimension(model)
+	}
+	if cfg.OutputDimension != nil {
+		dimension = *cfg.OutputDimension
+	}
+
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.voyageai.com/v1"
+	}
+
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	batchSize := cfg.BatchSize
+	if batchSize == 0 {
+		batchSize = 128
+	}
+	if batchSize > VoyageMaxBatchSize {
+		return nil, fmt.Errorf("batch size %d exceeds Voyage's limit of %d", batchSize, VoyageMaxBatchSize)
+	}
+
+	inputType := cfg.InputType
+	if inputType == "" {
+		inputType = "document"
+	}
+	switch inputType {
+	case "document", "query", "none":
+	default:
+		return nil, fmt.Errorf("invalid input type %q for Voyage (valid: document, query, none)", inputType)
+	}
+
+	return &VoyageEmbedder{
+		client:    &http.Client{Timeout: timeout},
+		apiKey:    cfg.APIKey,
+		baseURL:   baseURL,
+		model:     model,
+		dimension: dimension,
+		batchSize: batchSize,
+		inputType: inputType,
+		outputDim: cfg.OutputDimension,
+		truncate:  !cfg.DisableTruncation,
+	}, nil
+}
+
+// VoyageModelDimension returns the default embedding dimension of a
+// Voyage model.
+func VoyageModelDimension(model string) int {
+	switch model {
+	case "voyage-3-lite":
+		return 512
+	case "voyage-3.5", "voyage-3.5-lite", "voyage-3-large", "voyage-3", "voyage-code-3",
+		"voyage-finance-2", "voyage-law-2", "voyage-multilingual-2":
+		return 1024
+	case "voyage-code-2":
+		return 1536
+	default:
+		return 1024
+	}
+}
+
+// Embed converts text to a vector embedding.
+func (e *VoyageEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	return e.embedOne(ctx, text, e.inputType)
+}
+
+// EmbedQuery converts a search query to a vector embedding
+// (implements QueryEmbedder).
+func (e *VoyageEmbedder) EmbedQuery(ctx context.Context, text string) ([]float32, error) {
+	return e.embedOne(ctx, text, "query")
+}
+
+func (e *VoyageEmbedder) embedOne(ctx context.Context, text, inputType string) ([]float32, error) {
+	embeddings, err := e.embedBatch(ctx, []string{text}, inputType)
+	if err != nil {
+		return nil, err
+	}
+	return embeddings[0], nil
+}
+
+// EmbedBatch converts multiple texts to vector embeddings.
+func (e *VoyageEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	results := make([][]float32, 0, len(texts))
+	for _, batch := range packBatches(texts, e.batchSize, voyageBatchTokens(e.model)) {
+		embeddings, err := e.embedBatch(ctx, batch, e.inputType)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, embeddings...)
+	}
+
+	return results, nil
+}
+
+func (e *VoyageEmbedder) embedBatch(ctx context.Context, texts []string, inputType string) ([][]float32, error) {
+	req := voyageRequest{
+		Input:           texts,
+		Model:           e.model,
+		OutputDimension: e.outputDim,
+		Truncation:      e.truncate,
+	}
+	if inputType != "none" {
+		req.InputType = &inputType
+	}
+
+	reqBody, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", e.baseURL+"/embeddings", bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+e.apiKey)
+
+	resp, err := e.client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to Voyage: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var errorResp voyageErrorResponse
+		if err := json.Unmarshal(body, &errorResp); err == nil && errorResp.Detail != "" {
+			return nil, fmt.Errorf("Voyage API error (status %d): %s", resp.StatusCode, errorResp.Detail)
+		}
+		return nil, fmt.Errorf("Voyage API returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var response voyageResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(response.Data) != len(texts) {
+		return nil, fmt.Errorf("received %d embeddings from Voyage for %d inputs", len(response.Data), len(texts))
+	}
+
+	// Data is ordered by index; place by index to be safe
+	embeddings := make([][]float32, len(texts))
+	for _, d := range response.Data {
+		if d.Index < 0 || d.Index >= len(texts) {
+			return nil, fmt.Errorf("received embedding with invalid index %d from Voyage", d.Index)
+		}
+		embeddings[d.Index] = d.Embedding
+	}
+
+	return embeddings, nil
+}
+
+// Dimension returns the embedding vector dimension.
+func (e *VoyageEmbedder) Dimension() int {
+	return e.dimension
+}
+
+// Model returns the model name being used.
+func (e *VoyageEmbedder) Model() string {
+	return e.model
+}
+
+// Close releases any resources.
+func (e *VoyageEmbedder) Close() error {
+	return nil
+}
+
+// Ensure VoyageEmbedder implements Embedder and QueryEmbedder.
+var (
+	_ Embedder      = (*VoyageEmbedder)(nil)
+	_ QueryEmbedder = (*VoyageEmbedder)(nil)
+)
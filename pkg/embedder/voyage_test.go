@@ -0,0 +1,78 @@
+package embedder
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVoyageEmbedderBatchesAndInputTypes(t *testing.T) {
+	type call struct {
+		inputs    int
+		inputType any
+	}
+	var calls []call
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/embeddings" || r.Header.Get("Authorization") != "Bearer key" {
+			t.Errorf("unexpected request %s with auth %q", r.URL.Path, r.Header.Get("Authorization"))
+		}
+		var req map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		inputs := req["input"].([]any)
+		calls = append(calls, call{len(inputs), req["input_type"]})
+
+		// Answer out of order to check embeddings are placed by index
+		var data []map[string]any
+		for i := len(inputs) - 1; i >= 0; i-- {
+			data = append(data, map[string]any{"index": i, "embedding": []float32{float32(len(inputs[i].(string)))}})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": data})
+	}))
+	defer server.Close()
+
+	e, err := NewVoyageEmbedder(VoyageConfig{APIKey: "key", BaseURL: server.URL, BatchSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Dimension() != 1024 || e.Model() != "voyage-3.5" {
+		t.Errorf("defaults = %s/%d", e.Model(), e.Dimension())
+	}
+
+	ctx := context.Background()
+	vecs, err := e.EmbedBatch(ctx, []string{"a", "bb", "ccc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range vecs {
+		if v[0] != float32(i+1) {
+			t.Errorf("embedding %d = %v, want [%d]", i, v, i+1)
+		}
+	}
+	if _, err := EmbedQuery(ctx, e, "query"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []call{{2, "document"}, {1, "document"}, {1, "query"}}
+	if len(calls) != len(want) {
+		t.Fatalf("calls = %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("call %d = %v, want %v", i, calls[i], want[i])
+		}
+	}
+}
+
+func TestNewVoyageEmbedderValidates(t *testing.T) {
+	for name, cfg := range map[string]VoyageConfig{
+		"no key":     {},
+		"batch":      {APIKey: "k", BatchSize: 1001},
+		"input type": {APIKey: "k", InputType: "search_query"},
+	} {
+		if _, err := NewVoyageEmbedder(cfg); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
@@ -44,6 +44,7 @@ import (
 	"github.com/kadirpekel/hector/pkg/agent"
 	"github.com/kadirpekel/hector/pkg/builder"
 	"github.com/kadirpekel/hector/pkg/config"
+	"github.com/kadirpekel/hector/pkg/runner"
 	"github.com/kadirpekel/hector/pkg/tool"
 )
 
@@ -196,43 +197,32 @@ Always be friendly and concise in your responses.`).
 		Parts: []a2a.Part{a2a.TextPart{Text: userMessage}},
 	}
 
-	// Run the agent
+	// Run the agent, dispatching events to typed handlers
+	// (r.Run returns the raw event iterator for full control)
 	var response string
-	for event, err := range r.Run(ctx, "user1", "session1", content, agent.RunConfig{}) {
-		if err != nil {
-			log.Printf("Error: %v", err)
-			continue
-		}
-
-		if event == nil {
-			continue
-		}
-
-		// Handle different event types
-		if event.Message != nil {
-			for _, part := range event.Message.Parts {
-				switch p := part.(type) {
-				case a2a.TextPart:
-					if event.Partial {
-						// Streaming chunk
-						fmt.Print(p.Text)
-					} else {
-						// Final message
-						response = p.Text
-					}
-				}
+	err = r.RunWithHandlers(ctx, "user1", "session1", content, agent.RunConfig{}, runner.Handlers{
+		OnText: func(text string, partial bool) {
+			if partial {
+				fmt.Print(text) // Streaming chunk
+			} else {
+				response = text // Final message
 			}
-		}
-
-		// Log state changes (indicates tool execution progress)
-		if len(event.Actions.StateDelta) > 0 {
-			fmt.Printf("\n📊 State updated\n")
-		}
-
-		// Log transfers
-		if event.Actions.TransferToAgent != "" {
-			fmt.Printf("\n🔄 Transferring to: %s\n", event.Actions.TransferToAgent)
-		}
+		},
+		OnThinking: func(text string, partial bool) {
+			// Reasoning (thinking-enabled models) is kept apart from the answer
+			if partial {
+				fmt.Printf("\n💭 %s", text)
+			}
+		},
+		OnToolCall: func(call agent.ToolCallState) {
+			fmt.Printf("\n🔧 Calling %s\n", call.Name)
+		},
+		OnTransfer: func(agentName string) {
+			fmt.Printf("\n🔄 Transferring to: %s\n", agentName)
+		},
+	})
+	if err != nil {
+		log.Printf("Error: %v", err)
 	}
 
 	fmt.Println("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
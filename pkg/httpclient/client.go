@@ -32,9 +32,13 @@ import (
 	"log/slog"
 	"math"
 	"math/rand"
+	"net"
 	"net/http"
 	"os"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // RetryStrategy defines how to handle retries.
@@ -211,6 +215,34 @@ func ConfigureTLS(config *TLSConfig) (*http.Transport, error) {
 	return transport, nil
 }
 
+// WithConnectTimeout bounds connection establishment (dial and TLS
+// handshake) without limiting how long the response takes to arrive, so
+// long-running streams aren't cut off. Call it after WithHTTPClient and
+// WithTLSConfig; clients with a non-standard transport are left unchanged.
+func WithConnectTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		if timeout <= 0 {
+			return
+		}
+
+		var transport *http.Transport
+		switch t := c.client.Transport.(type) {
+		case nil:
+			transport = http.DefaultTransport.(*http.Transport).Clone()
+		case *http.Transport:
+			transport = t.Clone()
+		default:
+			slog.Debug("Connect timeout not applied to custom transport")
+			return
+		}
+
+		dialer := &net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}
+		transport.DialContext = dialer.DialContext
+		transport.TLSHandshakeTimeout = timeout
+		c.client.Transport = transport
+	}
+}
+
 // WithTLSConfig sets TLS configuration for the HTTP client.
 // This is useful for:
 //   - Corporate networks with custom CA certificates
@@ -324,9 +356,16 @@ func (c *Client) Do(req *http.Request) (*http.Response, error) {
 			return resp, err
 		}
 
-		// Log and wait
-		c.logRetry(strategy, delay, attempt, resp)
-		time.Sleep(delay)
+		// Log, discard the failed response and wait
+		c.logRetry(req, strategy, delay, attempt, resp)
+		if resp != nil && resp.Body != nil {
+			resp.Body.Close()
+		}
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
 	}
 
 	return nil, &RetryableError{
@@ -338,7 +377,15 @@ func (c *Client) Do(req *http.Request) (*http.Response, error) {
 }
 
 func (c *Client) attemptRequest(req *http.Request) (*http.Response, RetryStrategy, RateLimitInfo, error) {
+	req, watchdog := watchIdle(req)
 	resp, err := c.client.Do(req)
+	if watchdog != nil {
+		if err != nil {
+			watchdog.stop()
+			return nil, NoRetry, RateLimitInfo{}, watchdog.err(err)
+		}
+		resp.Body = &idleBody{ReadCloser: resp.Body, w: watchdog}
+	}
 	if err != nil {
 		return nil, NoRetry, RateLimitInfo{}, err
 	}
@@ -389,7 +436,28 @@ func (c *Client) calculateDelay(strategy RetryStrategy, attempt int, info RateLi
 	}
 }
 
-func (c *Client) logRetry(strategy RetryStrategy, delay time.Duration, attempt int, resp *http.Response) {
+// Transport returns an http.RoundTripper that sends requests with the
+// client's retry logic, for libraries that take an *http.Client. Once
+// retries are exhausted the last response is returned as-is, leaving status
+// handling to the caller.
+func (c *Client) Transport() http.RoundTripper {
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		// RoundTrippers must not modify the request; Do replaces its body
+		resp, err := c.Do(req.Clone(req.Context()))
+		if resp != nil {
+			return resp, nil
+		}
+		return nil, err
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func (c *Client) logRetry(req *http.Request, strategy RetryStrategy, delay time.Duration, attempt int, resp *http.Response) {
 	maxAttempts := c.maxRetries
 	if strategy == ConservativeRetry {
 		maxAttempts = 2
@@ -402,9 +470,19 @@ func (c *Client) logRetry(strategy RetryStrategy, delay time.Duration, attempt i
 		errorDetails = extractErrorDetails(resp)
 	}
 
+	trace.SpanFromContext(req.Context()).AddEvent("http.retry", trace.WithAttributes(
+		attribute.Int("http.status_code", statusCode),
+		attribute.Int("retry.attempt", attempt+1),
+		attribute.String("retry.delay", delay.String()),
+	))
+
 	switch strategy {
 	case SmartRetry:
-		slog.Info("Rate limited, retrying",
+		msg := "Rate limited, retrying"
+		if statusCode != http.StatusTooManyRequests {
+			msg = "Server error, retrying"
+		}
+		slog.Info(msg,
 			"status", statusCode,
 			"delay", delay,
 			"attempt", attempt+1,
@@ -0,0 +1,100 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTransportRetriesServerErrors(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "ping" {
+			t.Errorf("attempt %d body = %q, want ping", attempts, body)
+		}
+		if attempts < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte("pong"))
+	}))
+	defer srv.Close()
+
+	smart := func(int) RetryStrategy { return SmartRetry }
+	client := &http.Client{Transport: New(
+		WithMaxRetries(3),
+		WithBaseDelay(time.Millisecond),
+		WithRetryStrategy(smart),
+	).Transport()}
+
+	resp, err := client.Post(srv.URL, "text/plain", strings.NewReader("ping"))
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || attempts != 3 {
+		t.Errorf("status = %d after %d attempts, want 200 after 3", resp.StatusCode, attempts)
+	}
+
+	// Exhausted retries hand back the last response
+	attempts = 0
+	client = &http.Client{Transport: New(
+		WithMaxRetries(1),
+		WithBaseDelay(time.Millisecond),
+		WithRetryStrategy(smart),
+	).Transport()}
+	resp, err = client.Post(srv.URL, "text/plain", strings.NewReader("ping"))
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadGateway || attempts != 2 {
+		t.Errorf("status = %d after %d attempts, want 502 after 2", resp.StatusCode, attempts)
+	}
+}
+
+func TestIdleTimeoutCutsOffStalledResponses(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/stall-headers" {
+			<-r.Context().Done()
+			return
+		}
+		// Chunks arrive more often than the idle timeout, then stop
+		for range 3 {
+			_, _ = w.Write([]byte("chunk\n"))
+			w.(http.Flusher).Flush()
+			time.Sleep(50 * time.Millisecond)
+		}
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	client := New(WithHTTPClient(&http.Client{}), WithMaxRetries(0))
+	ctx := WithIdleTimeout(context.Background(), 200*time.Millisecond)
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/stall-headers", nil)
+	if _, err := client.Do(req); !errors.Is(err, ErrIdleTimeout) {
+		t.Errorf("Do() without headers error = %v, want ErrIdleTimeout", err)
+	}
+
+	req, _ = http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/stream", nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if !errors.Is(err, ErrIdleTimeout) {
+		t.Errorf("reading stalled body error = %v, want ErrIdleTimeout", err)
+	}
+	if got := strings.Count(string(body), "chunk"); got != 3 {
+		t.Errorf("read %d chunks before the stall, want 3", got)
+	}
+}
@@ -0,0 +1,103 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ErrIdleTimeout is the cause of requests cut off by an idle timeout.
+var ErrIdleTimeout = errors.New("no response data received")
+
+type idleTimeoutKey struct{}
+
+// WithIdleTimeout returns a context under which requests sent through
+// Client.Do fail once no response data arrives for d, whether they are
+// waiting for headers or reading the body. Unlike a deadline, it never
+// cuts off a response that keeps arriving, such as a long stream.
+func WithIdleTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, idleTimeoutKey{}, d)
+}
+
+// IdleTimeout returns the idle timeout set by WithIdleTimeout, or zero.
+func IdleTimeout(ctx context.Context) time.Duration {
+	d, _ := ctx.Value(idleTimeoutKey{}).(time.Duration)
+	return d
+}
+
+// idleWatchdog cancels a request when its timer runs out. Reading body
+// data resets the timer.
+type idleWatchdog struct {
+	ctx     context.Context
+	cancel  context.CancelCauseFunc
+	timer   *time.Timer
+	timeout time.Duration
+}
+
+// watchIdle returns req bound to a watchdog if its context carries an idle
+// timeout, or req and nil otherwise.
+func watchIdle(req *http.Request) (*http.Request, *idleWatchdog) {
+	timeout := IdleTimeout(req.Context())
+	if timeout <= 0 {
+		return req, nil
+	}
+	ctx, cancel := context.WithCancelCause(req.Context())
+	w := &idleWatchdog{ctx: ctx, cancel: cancel, timeout: timeout}
+	w.timer = time.AfterFunc(timeout, func() {
+		cancel(fmt.Errorf("%w for %v", ErrIdleTimeout, timeout))
+	})
+	return req.WithContext(ctx), w
+}
+
+// err returns the idle timeout error if the watchdog fired, or err.
+func (w *idleWatchdog) err(err error) error {
+	if cause := context.Cause(w.ctx); errors.Is(cause, ErrIdleTimeout) {
+		return cause
+	}
+	return err
+}
+
+func (w *idleWatchdog) stop() {
+	w.timer.Stop()
+	w.cancel(nil)
+}
+
+// idleBody resets its watchdog on every read that returns data.
+type idleBody struct {
+	io.ReadCloser
+	w *idleWatchdog
+}
+
+func (b *idleBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		b.w.timer.Reset(b.w.timeout)
+	}
+	if err != nil && err != io.EOF {
+		err = b.w.err(err)
+	}
+	return n, err
+}
+
+func (b *idleBody) Close() error {
+	b.w.stop()
+	return b.ReadCloser.Close()
+}
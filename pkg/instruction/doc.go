@@ -27,7 +27,22 @@
 //	{user:variable}      - User-scoped state (shared across sessions for a user)
 //	{temp:variable}      - Temporary state (discarded after invocation)
 //	{artifact.filename}  - Artifact text content
+//	{artifact.filename.mimetype} - Artifact MIME type
+//	{artifact.filename.size}     - Artifact size in bytes
 //	{variable?}          - Optional (empty string if not found, no error)
+//	{variable:-text}     - Default (text, verbatim, if not found or empty)
+//
+// # Conditional Blocks
+//
+// Blocks include text depending on whether a state variable is set (exists
+// and is not nil, false or an empty string). Blocks may be nested, and
+// their bodies may contain placeholders:
+//
+//	{if:user:tier}Tier: {user:tier}{/if}
+//	{unless:temp:verified}Ask the user to verify their email.{/unless}
+//
+// Placeholders in a block that is left out are not resolved, so they
+// don't need to exist.
 //
 // # Usage
 //
@@ -64,5 +79,11 @@
 //
 // Required placeholders (without ?) return an error if not found.
 // Optional placeholders (with ?) return an empty string if not found.
+// Placeholders with a default (:-text) return the text if not found or
+// empty, like shell parameter expansion.
+// Artifacts that aren't text (such as images) can't be injected with
+// {artifact.filename} and return an error; use their metadata instead.
 // Invalid placeholder names (not valid identifiers) are left as-is.
+// Closing tags without a block are left as-is; a block without its closing
+// tag returns an error naming the opening tag and its offset.
 package instruction
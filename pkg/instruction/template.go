@@ -22,7 +22,12 @@
 //	{user:variable}      - resolves from user-scoped state
 //	{temp:variable}      - resolves from temp-scoped state
 //	{artifact.filename}  - resolves artifact text content
+//	{artifact.filename.mimetype} - resolves the artifact MIME type
+//	{artifact.filename.size}     - resolves the artifact size in bytes
 //	{variable?}          - optional (empty string if not found)
+//	{variable:-text}     - text if not found or empty
+//	{if:variable}...{/if}         - included only if variable is set
+//	{unless:variable}...{/unless} - included only if variable is not set
 //
 // Example:
 //
@@ -31,11 +36,17 @@
 package instruction
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf8"
+
+	"github.com/a2aproject/a2a-go/a2a"
 
 	"github.com/kadirpekel/hector/pkg/agent"
 )
@@ -51,6 +62,10 @@ const (
 // Matches one or more opening braces, content without braces, one or more closing braces.
 var placeholderRegex = regexp.MustCompile(`{+[^{}]*}+`)
 
+// blockTagRegex matches block tags: {if:variable}, {unless:variable},
+// {/if} and {/unless}.
+var blockTagRegex = regexp.MustCompile(`{(if|unless):([^{}]*)}|{/(if|unless)}`)
+
 // Template represents an instruction template with placeholders.
 type Template struct {
 	raw string
@@ -80,15 +95,30 @@ func (t *Template) Render(ctx agent.ReadonlyContext) (string, error) {
 //   - {user:variable} - resolves from user-scoped state
 //   - {temp:variable} - resolves from temp-scoped state
 //   - {artifact.filename} - resolves artifact text content
+//   - {artifact.filename.mimetype} - resolves the artifact MIME type
+//   - {artifact.filename.size} - resolves the artifact size in bytes
 //   - {variable?} - optional (empty string if not found, no error)
+//   - {variable:-default} - default text if not found or empty
+//   - {if:variable}...{/if} - included only if variable is set
+//   - {unless:variable}...{/unless} - included only if variable is not set
+//
+// Blocks are resolved first and may be nested; placeholders in a block
+// that is left out are not resolved. A variable is set when it exists and
+// is not nil, false or an empty string.
 //
-// If a required placeholder cannot be resolved, an error is returned.
-// Invalid placeholder names (not matching identifier rules) are left as-is.
+// If a required placeholder cannot be resolved, or a block is not closed,
+// an error is returned. Invalid placeholder names (not matching identifier
+// rules) and closing tags without a block are left as-is.
 func InjectState(ctx agent.ReadonlyContext, template string) (string, error) {
 	if template == "" {
 		return "", nil
 	}
 
+	template, err := expandBlocks(ctx, template)
+	if err != nil {
+		return "", err
+	}
+
 	var result strings.Builder
 	lastIndex := 0
 	matches := placeholderRegex.FindAllStringIndex(template, -1)
@@ -115,10 +145,123 @@ func InjectState(ctx agent.ReadonlyContext, template string) (string, error) {
 	return result.String(), nil
 }
 
+// block is an {if:...} or {unless:...} block of a template.
+type block struct {
+	negate   bool
+	name     string
+	open     []int // indexes of the opening tag
+	close    []int // indexes of the closing tag
+	children []*block
+}
+
+// expandBlocks keeps the bodies of blocks whose condition holds and drops
+// the others, along with the block tags.
+func expandBlocks(ctx agent.ReadonlyContext, template string) (string, error) {
+	tags := blockTagRegex.FindAllStringSubmatchIndex(template, -1)
+	if len(tags) == 0 {
+		return template, nil
+	}
+
+	root := &block{}
+	stack := []*block{root}
+	for _, tag := range tags {
+		top := stack[len(stack)-1]
+
+		// Closing tag: {/if} or {/unless}
+		if tag[6] >= 0 {
+			kind := template[tag[6]:tag[7]]
+			if top == root || kindOf(top) != kind {
+				continue // Unmatched: left as-is
+			}
+			top.close = tag[:2]
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		name := strings.TrimSpace(template[tag[4]:tag[5]])
+		if !isValidStateName(name) {
+			continue // Not a block tag: left as-is
+		}
+		b := &block{
+			negate: template[tag[2]:tag[3]] == "unless",
+			name:   name,
+			open:   tag[:2],
+		}
+		top.children = append(top.children, b)
+		stack = append(stack, b)
+	}
+
+	if len(stack) > 1 {
+		b := stack[len(stack)-1]
+		return "", fmt.Errorf("unterminated block %s at offset %d: missing {/%s}",
+			template[b.open[0]:b.open[1]], b.open[0], kindOf(b))
+	}
+
+	var result strings.Builder
+	renderBlocks(ctx, &result, template, 0, len(template), root.children)
+	return result.String(), nil
+}
+
+// renderBlocks writes template[start:end], expanding the given blocks.
+func renderBlocks(ctx agent.ReadonlyContext, w *strings.Builder, template string, start, end int, blocks []*block) {
+	for _, b := range blocks {
+		w.WriteString(template[start:b.open[0]])
+		if isSet(ctx, b.name) != b.negate {
+			renderBlocks(ctx, w, template, b.open[1], b.close[0], b.children)
+		}
+		start = b.close[1]
+	}
+	w.WriteString(template[start:end])
+}
+
+func kindOf(b *block) string {
+	if b.negate {
+		return "unless"
+	}
+	return "if"
+}
+
+// isSet reports whether the state variable exists and is not nil, false
+// or an empty string.
+func isSet(ctx agent.ReadonlyContext, varName string) bool {
+	state := ctx.ReadonlyState()
+	if state == nil {
+		return false
+	}
+	value, err := state.Get(varName)
+	if err != nil {
+		return false
+	}
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != ""
+	}
+	return true
+}
+
 // replaceMatch resolves a single placeholder match.
 func replaceMatch(ctx agent.ReadonlyContext, match string) (string, error) {
 	// Trim braces: "{var_name}" -> "var_name"
-	varName := strings.TrimSpace(strings.Trim(match, "{}"))
+	varName := strings.Trim(match, "{}")
+
+	// Check for a default: "{var_name:-text}" uses text verbatim when
+	// var_name is missing or empty
+	if name, defaultText, ok := strings.Cut(varName, ":-"); ok {
+		name = strings.TrimSpace(name)
+		if !isValidStateName(name) && !strings.HasPrefix(name, "artifact.") {
+			return match, nil
+		}
+		value, err := replaceMatch(ctx, "{"+name+"?}")
+		if err != nil || value == "" {
+			return defaultText, err
+		}
+		return value, nil
+	}
+	varName = strings.TrimSpace(varName)
 
 	// Check for optional marker
 	optional := false
@@ -127,9 +270,17 @@ func replaceMatch(ctx agent.ReadonlyContext, match string) (string, error) {
 		varName = strings.TrimSuffix(varName, "?")
 	}
 
-	// Handle artifact references: {artifact.filename}
+	// Handle artifact references: {artifact.filename},
+	// {artifact.filename.mimetype} and {artifact.filename.size}
 	if after, ok := strings.CutPrefix(varName, "artifact."); ok {
-		return resolveArtifact(ctx, after, optional)
+		filename, field := after, ""
+		for _, f := range []string{artifactFieldMimeType, artifactFieldSize} {
+			if name, ok := strings.CutSuffix(after, "."+f); ok && name != "" {
+				filename, field = name, f
+				break
+			}
+		}
+		return resolveArtifact(ctx, filename, field, optional)
 	}
 
 	// Validate state name
@@ -142,8 +293,16 @@ func replaceMatch(ctx agent.ReadonlyContext, match string) (string, error) {
 	return resolveState(ctx, varName, optional)
 }
 
-// resolveArtifact loads artifact content by filename.
-func resolveArtifact(ctx agent.ReadonlyContext, filename string, optional bool) (string, error) {
+// Artifact metadata fields: {artifact.filename.mimetype} and
+// {artifact.filename.size}.
+const (
+	artifactFieldMimeType = "mimetype"
+	artifactFieldSize     = "size"
+)
+
+// resolveArtifact loads an artifact by filename and returns its text
+// content, or the given metadata field.
+func resolveArtifact(ctx agent.ReadonlyContext, filename, field string, optional bool) (string, error) {
 	if filename == "" {
 		if optional {
 			return "", nil
@@ -169,39 +328,159 @@ func resolveArtifact(ctx agent.ReadonlyContext, filename string, optional bool)
 	}
 
 	resp, err := artifacts.Load(ctx, filename)
-	if err != nil {
+	if err != nil || resp == nil || resp.Part == nil {
 		if optional {
 			return "", nil
 		}
+		if err == nil {
+			err = fmt.Errorf("artifact is empty")
+		}
 		return "", fmt.Errorf("failed to load artifact %q: %w", filename, err)
 	}
 
-	// Extract text from the artifact part
-	return extractTextFromPart(resp.Part), nil
+	switch field {
+	case artifactFieldMimeType:
+		return artifactMimeType(resp.Part), nil
+	case artifactFieldSize:
+		size, err := artifactSize(resp.Part)
+		if err != nil {
+			if optional {
+				return "", nil
+			}
+			return "", fmt.Errorf("artifact %q: %w", filename, err)
+		}
+		return strconv.Itoa(size), nil
+	default:
+		return artifactText(filename, resp.Part)
+	}
+}
+
+// artifactMimeType returns the MIME type of an artifact part.
+func artifactMimeType(part a2a.Part) string {
+	switch p := part.(type) {
+	case a2a.TextPart, *a2a.TextPart:
+		return "text/plain"
+	case a2a.DataPart, *a2a.DataPart:
+		return "application/json"
+	case a2a.FilePart:
+		return fileMimeType(p.File)
+	case *a2a.FilePart:
+		return fileMimeType(p.File)
+	}
+	return "application/octet-stream"
 }
 
-// extractTextFromPart extracts text content from an a2a.Part.
-func extractTextFromPart(part any) string {
-	if part == nil {
-		return ""
+func fileMimeType(file a2a.FilePartContent) string {
+	var mimeType string
+	switch f := file.(type) {
+	case a2a.FileBytes:
+		mimeType = f.MimeType
+	case *a2a.FileBytes:
+		mimeType = f.MimeType
+	case a2a.FileURI:
+		mimeType = f.MimeType
+	case *a2a.FileURI:
+		mimeType = f.MimeType
+	}
+	if mimeType == "" {
+		return "application/octet-stream"
 	}
+	return mimeType
+}
 
-	// Try common text extraction patterns
+// artifactSize returns the size of an artifact part in bytes.
+func artifactSize(part a2a.Part) (int, error) {
 	switch p := part.(type) {
-	case interface{ GetText() string }:
-		return p.GetText()
-	case interface{ Text() string }:
-		return p.Text()
-	case fmt.Stringer:
-		return p.String()
+	case a2a.TextPart:
+		return len(p.Text), nil
+	case *a2a.TextPart:
+		return len(p.Text), nil
+	case a2a.DataPart, *a2a.DataPart:
+		text, err := artifactText("", part)
+		return len(text), err
 	}
 
-	// Try to extract from struct with Text field
-	if textPart, ok := part.(struct{ Text string }); ok {
-		return textPart.Text
+	data, ok, err := fileBytes(part)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, fmt.Errorf("size unknown for artifacts stored by URI")
+	}
+	return len(data), nil
+}
+
+// artifactText returns the text content of an artifact part. Binary files
+// return an error rather than being injected into the instruction.
+func artifactText(filename string, part a2a.Part) (string, error) {
+	switch p := part.(type) {
+	case a2a.TextPart:
+		return p.Text, nil
+	case *a2a.TextPart:
+		return p.Text, nil
+	case a2a.DataPart:
+		data, err := json.Marshal(p.Data)
+		return string(data), err
+	case *a2a.DataPart:
+		data, err := json.Marshal(p.Data)
+		return string(data), err
 	}
 
-	return ""
+	mimeType := artifactMimeType(part)
+	data, ok, err := fileBytes(part)
+	if err != nil {
+		return "", fmt.Errorf("artifact %q: %w", filename, err)
+	}
+	if !ok || !isTextMimeType(mimeType) || !utf8.Valid(data) {
+		return "", fmt.Errorf("artifact %q is not text (%s); reference {artifact.%s.mimetype} or {artifact.%s.size} instead",
+			filename, mimeType, filename, filename)
+	}
+	return string(data), nil
+}
+
+// fileBytes returns the decoded content of a file part. ok is false for
+// files stored by URI.
+func fileBytes(part a2a.Part) (data []byte, ok bool, err error) {
+	var file a2a.FilePartContent
+	switch p := part.(type) {
+	case a2a.FilePart:
+		file = p.File
+	case *a2a.FilePart:
+		file = p.File
+	}
+
+	var encoded string
+	switch f := file.(type) {
+	case a2a.FileBytes:
+		encoded = f.Bytes
+	case *a2a.FileBytes:
+		encoded = f.Bytes
+	default:
+		return nil, false, nil
+	}
+	data, err = base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid file content: %w", err)
+	}
+	return data, true, nil
+}
+
+// isTextMimeType reports whether content of the MIME type is text.
+func isTextMimeType(mimeType string) bool {
+	mimeType, _, _ = strings.Cut(mimeType, ";")
+	mimeType = strings.TrimSpace(strings.ToLower(mimeType))
+	switch {
+	case strings.HasPrefix(mimeType, "text/"),
+		strings.HasSuffix(mimeType, "+json"),
+		strings.HasSuffix(mimeType, "+xml"):
+		return true
+	}
+	switch mimeType {
+	case "application/json", "application/xml", "application/yaml",
+		"application/x-yaml", "application/javascript":
+		return true
+	}
+	return false
 }
 
 // resolveState resolves a variable from session state.
@@ -292,8 +571,8 @@ func ListPlaceholders(template string) []string {
 	seen := make(map[string]bool)
 
 	for _, match := range matches {
-		name := strings.TrimSpace(strings.Trim(match, "{}"))
-		name = strings.TrimSuffix(name, "?")
+		name, _, _ := strings.Cut(strings.Trim(match, "{}"), ":-")
+		name = strings.TrimSuffix(strings.TrimSpace(name), "?")
 		if !seen[name] {
 			names = append(names, name)
 			seen[name] = true
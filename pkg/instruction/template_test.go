@@ -0,0 +1,204 @@
+package instruction
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"iter"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+)
+
+type testState map[string]any
+
+func (s testState) Get(key string) (any, error) {
+	v, ok := s[key]
+	if !ok {
+		return nil, errors.New("key not found")
+	}
+	return v, nil
+}
+
+func (s testState) All() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for k, v := range s {
+			if !yield(k, v) {
+				return
+			}
+		}
+	}
+}
+
+// stateContext is a ReadonlyContext serving only state.
+type stateContext struct {
+	agent.ReadonlyContext
+	state testState
+}
+
+func (c stateContext) ReadonlyState() agent.ReadonlyState { return c.state }
+
+func TestInjectStateBlocks(t *testing.T) {
+	ctx := stateContext{state: testState{
+		"name":      "Alice",
+		"user:tier": "gold",
+		"verified":  false,
+		"notes":     "",
+	}}
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"Hi {name}.{if:user:tier} Tier: {user:tier}.{/if}", "Hi Alice. Tier: gold."},
+		{"Hi.{if:missing} Missing: {missing}.{/if}", "Hi."},
+		{"{unless:verified}Verify first.{/unless}", "Verify first."},
+		{"{if:notes}Notes: {notes}{/if}{unless:notes}No notes.{/unless}", "No notes."},
+		{"{if:name}A{if:missing}B{/if}{unless:missing}C{/unless}{/if}", "AC"},
+		{"{if:name}{if:user:tier}nested{/if}{/if}", "nested"},
+		{"{if: name }spaced{/if}", "spaced"},
+		// Unmatched and invalid tags are left as-is
+		{"a {/if} b", "a {/if} b"},
+		{"{if:name}x{/unless}{/if}", "x{/unless}"},
+		{"{if:not valid}x", "{if:not valid}x"},
+	}
+	for _, tt := range tests {
+		got, err := New(tt.template).Render(ctx)
+		if err != nil {
+			t.Errorf("Render(%q) error = %v", tt.template, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Render(%q) = %q, want %q", tt.template, got, tt.want)
+		}
+	}
+}
+
+func TestInjectStateUnterminatedBlock(t *testing.T) {
+	ctx := stateContext{state: testState{"name": "Alice"}}
+
+	_, err := InjectState(ctx, "Hi {name}. {if:name}{unless:admin}text{/unless}")
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.Contains(err.Error(), "{if:name}") || !strings.Contains(err.Error(), "offset 11") {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestInjectStateDefaults(t *testing.T) {
+	ctx := stateContext{state: testState{
+		"name":      "Alice",
+		"user:tier": "gold",
+		"notes":     "",
+		"count":     0,
+	}}
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"{name:-friend}", "Alice"},
+		{"{nickname:-friend}", "friend"},
+		{"{notes:-no notes yet}", "no notes yet"},
+		{"{count:-none}", "0"},
+		{"{user:tier:-free}", "gold"},
+		{"{app:plan:-free}", "free"},
+		{"{temp:route:-see: the docs, then ask}", "see: the docs, then ask"},
+		{"{nickname:- spaced default }", " spaced default "},
+		{"{nickname:-}", ""},
+		{"{nickname:-a:-b}", "a:-b"},
+		{"{if:nickname}{nickname}{/if}{unless:nickname}{name:-x}{/unless}", "Alice"},
+		{"{not valid:-x}", "{not valid:-x}"},
+	}
+	for _, tt := range tests {
+		got, err := InjectState(ctx, tt.template)
+		if err != nil {
+			t.Errorf("InjectState(%q) error = %v", tt.template, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("InjectState(%q) = %q, want %q", tt.template, got, tt.want)
+		}
+	}
+
+	if names := ListPlaceholders("{user:tier:-free} {name?}"); len(names) != 2 || names[0] != "user:tier" || names[1] != "name" {
+		t.Errorf("ListPlaceholders() = %v", names)
+	}
+}
+
+type testArtifacts struct {
+	agent.Artifacts
+	parts map[string]a2a.Part
+}
+
+func (a testArtifacts) Load(_ context.Context, name string) (*agent.ArtifactLoadResponse, error) {
+	part, ok := a.parts[name]
+	if !ok {
+		return nil, errors.New("artifact not found")
+	}
+	return &agent.ArtifactLoadResponse{Name: name, Part: part}, nil
+}
+
+// artifactContext is a CallbackContext serving only artifacts.
+type artifactContext struct {
+	agent.CallbackContext
+	artifacts testArtifacts
+}
+
+func (c artifactContext) Artifacts() agent.Artifacts { return c.artifacts }
+
+func fileArtifact(mimeType string, content []byte) a2a.Part {
+	return a2a.FilePart{File: a2a.FileBytes{
+		FileMeta: a2a.FileMeta{MimeType: mimeType},
+		Bytes:    base64.StdEncoding.EncodeToString(content),
+	}}
+}
+
+func TestInjectStateArtifacts(t *testing.T) {
+	ctx := artifactContext{artifacts: testArtifacts{parts: map[string]a2a.Part{
+		"notes.txt":  a2a.TextPart{Text: "hello"},
+		"photo.png":  fileArtifact("image/png", []byte{0x89, 'P', 'N', 'G', 0, 1, 2}),
+		"data.csv":   fileArtifact("text/csv; charset=utf-8", []byte("a,b\n1,2")),
+		"remote.pdf": a2a.FilePart{File: a2a.FileURI{FileMeta: a2a.FileMeta{MimeType: "application/pdf"}, URI: "https://example.com/r.pdf"}},
+	}}}
+
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{"{artifact.notes.txt}", "hello"},
+		{"{artifact.notes.txt.mimetype}", "text/plain"},
+		{"{artifact.notes.txt.size}", "5"},
+		{"{artifact.photo.png.mimetype} {artifact.photo.png.size}", "image/png 7"},
+		{"{artifact.data.csv}", "a,b\n1,2"},
+		{"{artifact.remote.pdf.mimetype}", "application/pdf"},
+		{"{artifact.remote.pdf.size?}", ""},
+		{"[{artifact.missing.png.mimetype?}]", "[]"},
+		{"{artifact.missing.png.size:-0}", "0"},
+	}
+	for _, tt := range tests {
+		got, err := InjectState(ctx, tt.template)
+		if err != nil {
+			t.Errorf("InjectState(%q) error = %v", tt.template, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("InjectState(%q) = %q, want %q", tt.template, got, tt.want)
+		}
+	}
+
+	for _, template := range []string{"{artifact.missing.png.mimetype}", "{artifact.remote.pdf.size}"} {
+		if _, err := InjectState(ctx, template); err == nil {
+			t.Errorf("InjectState(%q) expected error", template)
+		}
+	}
+
+	_, err := InjectState(ctx, "{artifact.photo.png?}")
+	if err == nil || !strings.Contains(err.Error(), "image/png") || !strings.Contains(err.Error(), "{artifact.photo.png.mimetype}") {
+		t.Errorf("binary artifact error = %v", err)
+	}
+}
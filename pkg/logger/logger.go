@@ -240,13 +240,35 @@ func (h *coloredTextHandler) WithGroup(name string) slog.Handler {
 	}
 }
 
+// Option configures optional logger behavior.
+type Option func(*initOptions)
+
+type initOptions struct {
+	sampling uint64
+}
+
+// WithSampling logs only the first and then every Nth occurrence of each
+// debug or info message. Values below 2 disable sampling.
+func WithSampling(every int) Option {
+	return func(o *initOptions) {
+		if every > 1 {
+			o.sampling = uint64(every)
+		}
+	}
+}
+
 // Init initializes the logger with the specified level and format
 // Third-party library logs are only shown when level is DEBUG
 // Color support is enabled automatically for terminal output
 // format: "simple" (level + message only), "verbose" (time + level + message + attributes),
 //
 //	or any custom value (falls back to default slog.TextHandler format)
-func Init(level slog.Level, output *os.File, format string) {
+func Init(level slog.Level, output *os.File, format string, options ...Option) {
+	var o initOptions
+	for _, opt := range options {
+		opt(&o)
+	}
+
 	useColor := isTerminal(output)
 	simple := format == "simple" || format == "" // default to simple
 	verbose := format == "verbose"
@@ -289,6 +311,11 @@ func Init(level slog.Level, output *os.File, format string) {
 	}
 	// For verbose or custom formats in non-terminal, use baseHandler (standard slog format)
 
+	// Wrap with sampling handler to rate-limit repetitive lines
+	if o.sampling > 0 {
+		handler = newSamplingHandler(handler, o.sampling)
+	}
+
 	// Wrap with filtering handler
 	filteringHandler := &filteringHandler{
 		handler:  handler,
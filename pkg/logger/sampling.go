@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// maxSampledMessages bounds the number of distinct messages tracked by the
+// sampling handler. Counters are reset when it is exceeded.
+const maxSampledMessages = 10000
+
+// samplingHandler rate-limits repetitive debug and info lines.
+//
+// For each distinct level and message, the first record is logged and then
+// every Nth one; records in between are dropped. Sampled records carry a
+// "sampled" attribute with N. Warnings and errors are never sampled.
+type samplingHandler struct {
+	handler  slog.Handler
+	every    uint64
+	counters *sampleCounters
+}
+
+// sampleCounters counts occurrences per level and message.
+// Shared by all handlers derived via WithAttrs/WithGroup.
+type sampleCounters struct {
+	mu     sync.Mutex
+	counts map[sampleKey]uint64
+}
+
+type sampleKey struct {
+	level   slog.Level
+	message string
+}
+
+func newSamplingHandler(handler slog.Handler, every uint64) *samplingHandler {
+	return &samplingHandler{
+		handler:  handler,
+		every:    every,
+		counters: &sampleCounters{counts: make(map[sampleKey]uint64)},
+	}
+}
+
+// next increments and returns the occurrence count for a record.
+func (c *sampleCounters) next(key sampleKey) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.counts[key]; !ok && len(c.counts) >= maxSampledMessages {
+		c.counts = make(map[sampleKey]uint64)
+	}
+	c.counts[key]++
+	return c.counts[key]
+}
+
+func (h *samplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level >= slog.LevelWarn {
+		return h.handler.Handle(ctx, record)
+	}
+
+	n := h.counters.next(sampleKey{level: record.Level, message: record.Message})
+	if (n-1)%h.every != 0 {
+		return nil
+	}
+	if n > 1 {
+		record = record.Clone()
+		record.AddAttrs(slog.Uint64("sampled", h.every))
+	}
+	return h.handler.Handle(ctx, record)
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{
+		handler:  h.handler.WithAttrs(attrs),
+		every:    h.every,
+		counters: h.counters,
+	}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{
+		handler:  h.handler.WithGroup(name),
+		every:    h.every,
+		counters: h.counters,
+	}
+}
@@ -0,0 +1,31 @@
+package logger
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSamplingHandler(t *testing.T) {
+	var buf bytes.Buffer
+	base := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	log := slog.New(newSamplingHandler(base, 10))
+
+	for i := 0; i < 25; i++ {
+		log.Debug("SSE event received", "i", i)
+	}
+	log.Warn("upstream slow")
+	log.Warn("upstream slow")
+
+	out := buf.String()
+	if got := strings.Count(out, "SSE event received"); got != 3 {
+		t.Errorf("logged %d debug lines, want 3 (1st, 11th, 21st)", got)
+	}
+	if got := strings.Count(out, "sampled=10"); got != 2 {
+		t.Errorf("got %d sampled attributes, want 2", got)
+	}
+	if got := strings.Count(out, "upstream slow"); got != 2 {
+		t.Errorf("logged %d warnings, want 2 (never sampled)", got)
+	}
+}
@@ -56,13 +56,15 @@ func (s *BufferWindowStrategy) Name() string {
 	return "buffer_window"
 }
 
-// FilterEvents returns the last windowSize events.
+// FilterEvents returns the last windowSize events plus any pinned events.
 // If there are fewer events than windowSize, all events are returned.
+// A tool call and its results are never split by the window boundary.
 func (s *BufferWindowStrategy) FilterEvents(events []*agent.Event) []*agent.Event {
 	if len(events) <= s.windowSize {
 		return events
 	}
-	return events[len(events)-s.windowSize:]
+	start := toolSafeStart(events, len(events)-s.windowSize, 1)
+	return withPinned(events, events[start:])
 }
 
 // CheckAndSummarize always returns nil (buffer window doesn't summarize).
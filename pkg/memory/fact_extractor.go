@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/model"
+)
+
+// Default fact extraction prompt
+const defaultFactExtractionPrompt = `You extract durable facts about the user from a conversation, so an assistant can remember them in future conversations.
+
+Guidelines:
+- Only extract facts about the user that stay true over time: name, location, job, preferences, goals, constraints
+- Ignore one-off requests, questions and anything about the assistant
+- Write each fact as a short standalone sentence about "the user"
+- Do not repeat facts that are already known
+- Do not add information not present in the conversation
+
+Already known facts:
+%s
+
+Conversation:
+%s
+
+Reply with one new fact per line, or NONE if there are no new facts:`
+
+// noFactsReply is the extractor's reply when a turn holds no new facts.
+const noFactsReply = "NONE"
+
+// LLMFactExtractor implements the FactExtractor interface using an LLM.
+type LLMFactExtractor struct {
+	llm    model.LLM
+	prompt string
+}
+
+// LLMFactExtractorConfig configures the LLM fact extractor.
+type LLMFactExtractorConfig struct {
+	// LLM is the language model to use for extraction.
+	LLM model.LLM
+
+	// Prompt is a custom extraction prompt template.
+	// Use %s placeholders for the known facts and the conversation text.
+	// If empty, uses the default prompt.
+	Prompt string
+}
+
+// NewLLMFactExtractor creates a new LLM-based fact extractor.
+func NewLLMFactExtractor(cfg LLMFactExtractorConfig) (*LLMFactExtractor, error) {
+	if cfg.LLM == nil {
+		return nil, fmt.Errorf("LLM is required for fact extraction")
+	}
+
+	prompt := cfg.Prompt
+	if prompt == "" {
+		prompt = defaultFactExtractionPrompt
+	}
+
+	return &LLMFactExtractor{
+		llm:    cfg.LLM,
+		prompt: prompt,
+	}, nil
+}
+
+// ExtractFacts returns the new facts about the user stated in events.
+func (e *LLMFactExtractor) ExtractFacts(ctx context.Context, events []*agent.Event, known []string) ([]string, error) {
+	// Build conversation text from events
+	var conversation strings.Builder
+	for _, ev := range events {
+		if ev.Message == nil {
+			continue
+		}
+
+		role := ev.Author
+		if role == "" {
+			role = "unknown"
+		}
+
+		text := extractTextFromA2AMessage(ev.Message)
+		if text != "" {
+			conversation.WriteString(fmt.Sprintf("[%s]: %s\n\n", role, text))
+		}
+	}
+
+	if conversation.Len() == 0 {
+		return nil, nil
+	}
+
+	knownText := noFactsReply
+	if len(known) > 0 {
+		knownText = "- " + strings.Join(known, "\n- ")
+	}
+
+	req := &model.Request{
+		Messages: []*a2a.Message{
+			a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{
+				Text: fmt.Sprintf(e.prompt, knownText, conversation.String()),
+			}),
+		},
+	}
+
+	// Call LLM (non-streaming)
+	var reply strings.Builder
+	for resp, err := range e.llm.GenerateContent(ctx, req, false) {
+		if err != nil {
+			return nil, fmt.Errorf("fact extraction failed: %w", err)
+		}
+		if resp.Content != nil {
+			for _, part := range resp.Content.Parts {
+				if tp, ok := part.(a2a.TextPart); ok {
+					reply.WriteString(tp.Text)
+				}
+			}
+		}
+	}
+
+	return parseFacts(reply.String()), nil
+}
+
+// parseFacts splits an extraction reply into facts, one per line, dropping
+// list markers and the NONE reply.
+func parseFacts(reply string) []string {
+	var facts []string
+	for _, line := range strings.Split(reply, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimLeft(line, "-*• ")
+		if i := strings.Index(line, ". "); i > 0 && i <= 3 && strings.Trim(line[:i], "0123456789") == "" {
+			line = line[i+2:] // Numbered list item
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.EqualFold(strings.TrimRight(line, "."), noFactsReply) {
+			continue
+		}
+		facts = append(facts, line)
+	}
+	return facts
+}
+
+// Ensure LLMFactExtractor implements FactExtractor.
+var _ FactExtractor = (*LLMFactExtractor)(nil)
@@ -33,6 +33,22 @@ type SearchRequest struct {
 
 	// AppName scopes the search to a specific application.
 	AppName string
+
+	// Limit is the maximum number of results.
+	// Default: 10
+	Limit int
+}
+
+// DefaultSearchLimit is the number of results returned when
+// SearchRequest.Limit is not set.
+const DefaultSearchLimit = 10
+
+// limit returns the maximum number of results for the request.
+func (r *SearchRequest) limit() int {
+	if r.Limit > 0 {
+		return r.Limit
+	}
+	return DefaultSearchLimit
 }
 
 // SearchResponse represents the response from a memory search.
@@ -147,8 +147,8 @@ func (s *KeywordIndexService) Search(ctx context.Context, req *SearchRequest) (*
 	})
 
 	// Limit results
-	if len(results) > 10 {
-		results = results[:10]
+	if len(results) > req.limit() {
+		results = results[:req.limit()]
 	}
 
 	return &SearchResponse{Results: results}, nil
@@ -151,7 +151,7 @@ func (s *VectorIndexService) Search(ctx context.Context, req *SearchRequest) (*S
 	}
 
 	// Generate query embedding
-	queryEmbedding, err := s.embedder.Embed(ctx, req.Query)
+	queryEmbedding, err := embedder.EmbedQuery(ctx, s.embedder, req.Query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to embed query: %w", err)
 	}
@@ -163,7 +163,7 @@ func (s *VectorIndexService) Search(ctx context.Context, req *SearchRequest) (*S
 	}
 
 	// Query vector store
-	results, err := s.provider.SearchWithFilter(ctx, s.collectionName, queryEmbedding, 10, filter)
+	results, err := s.provider.SearchWithFilter(ctx, s.collectionName, queryEmbedding, req.limit(), filter)
 	if err != nil {
 		return nil, fmt.Errorf("search failed: %w", err)
 	}
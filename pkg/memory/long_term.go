@@ -0,0 +1,234 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+)
+
+// Default long-term memory settings
+const (
+	DefaultMaxFacts      = 50 // Facts kept per user
+	DefaultInjectedFacts = 10 // Facts injected into a prompt
+)
+
+// LongTermFactsStateKey is the user-scoped state key holding the facts
+// remembered about a user. The "user:" prefix shares it across sessions.
+const LongTermFactsStateKey = "user:long_term_facts"
+
+// FactExtractor extracts durable facts about the user from a conversation.
+// Implementations should use an LLM to pick out facts worth remembering.
+type FactExtractor interface {
+	// ExtractFacts returns facts stated in events that are not in known.
+	ExtractFacts(ctx context.Context, events []*agent.Event, known []string) ([]string, error)
+}
+
+// LongTermMemory remembers durable facts about a user across sessions.
+//
+// After each turn, Update extracts facts (name, preferences) from the turn
+// and returns an event that stores them in user-scoped state. Context
+// returns the facts most relevant to a query for injection into the next
+// prompt, so it composes with any working memory strategy as a context
+// provider.
+type LongTermMemory struct {
+	extractor   FactExtractor
+	maxFacts    int
+	maxInjected int
+}
+
+// LongTermMemoryConfig holds configuration for long-term memory.
+type LongTermMemoryConfig struct {
+	// Extractor extracts facts from each turn (required).
+	Extractor FactExtractor
+
+	// MaxFacts is the maximum number of facts kept per user.
+	// The oldest facts are dropped first.
+	// Default: 50
+	MaxFacts int
+
+	// MaxInjected is the maximum number of facts injected into a prompt.
+	// Default: 10
+	MaxInjected int
+}
+
+// NewLongTermMemory creates a new long-term memory.
+func NewLongTermMemory(cfg LongTermMemoryConfig) (*LongTermMemory, error) {
+	if cfg.Extractor == nil {
+		return nil, fmt.Errorf("extractor is required for long-term memory")
+	}
+
+	maxFacts := cfg.MaxFacts
+	if maxFacts <= 0 {
+		maxFacts = DefaultMaxFacts
+	}
+
+	maxInjected := cfg.MaxInjected
+	if maxInjected <= 0 {
+		maxInjected = DefaultInjectedFacts
+	}
+
+	return &LongTermMemory{
+		extractor:   cfg.Extractor,
+		maxFacts:    maxFacts,
+		maxInjected: maxInjected,
+	}, nil
+}
+
+// Update extracts facts from the latest turn in events and returns an event
+// whose state delta stores them, or nil if no new facts were found.
+// Facts already known, ignoring case, whitespace and trailing punctuation,
+// are not stored again.
+func (m *LongTermMemory) Update(ctx context.Context, state agent.ReadonlyState, events []*agent.Event) (*agent.Event, error) {
+	turn := lastTurn(events)
+	if len(turn) == 0 {
+		return nil, nil
+	}
+
+	known := Facts(state)
+	extracted, err := m.extractor.ExtractFacts(ctx, turn, known)
+	if err != nil {
+		return nil, fmt.Errorf("fact extraction failed: %w", err)
+	}
+
+	facts, added := mergeFacts(known, extracted, m.maxFacts)
+	if added == 0 {
+		return nil, nil
+	}
+
+	slog.Debug("Remembered user facts",
+		"added", added,
+		"total", len(facts))
+
+	return &agent.Event{
+		ID:     uuid.NewString(),
+		Author: agent.AuthorSystem,
+		Actions: agent.EventActions{
+			StateDelta: map[string]any{LongTermFactsStateKey: facts},
+		},
+	}, nil
+}
+
+// Context returns the remembered facts most relevant to query, formatted
+// for injection into the prompt, or "" if there are none or the user is
+// anonymous. Its signature matches llmagent.ContextProvider.
+func (m *LongTermMemory) Context(ctx agent.ReadonlyContext, query string) (string, error) {
+	if agent.IsAnonymousUser(ctx.UserID()) {
+		return "", nil
+	}
+	facts := m.Relevant(Facts(ctx.ReadonlyState()), query)
+	if len(facts) == 0 {
+		return "", nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Known facts about the user:\n")
+	for _, fact := range facts {
+		sb.WriteString("- ")
+		sb.WriteString(fact)
+		sb.WriteString("\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n"), nil
+}
+
+// Relevant returns up to MaxInjected facts, those sharing the most words
+// with query first and the most recent first among equals.
+func (m *LongTermMemory) Relevant(facts []string, query string) []string {
+	queryWords := tokenize(query)
+	ranked := slices.Clone(facts)
+	slices.Reverse(ranked)
+	slices.SortStableFunc(ranked, func(a, b string) int {
+		sa := calculateScore(queryWords, tokenize(a))
+		sb := calculateScore(queryWords, tokenize(b))
+		switch {
+		case sa > sb:
+			return -1
+		case sa < sb:
+			return 1
+		}
+		return 0
+	})
+	return ranked[:min(len(ranked), m.maxInjected)]
+}
+
+// MaxFacts returns the maximum number of facts kept per user.
+func (m *LongTermMemory) MaxFacts() int {
+	return m.maxFacts
+}
+
+// Facts returns the facts remembered about the user.
+func Facts(state agent.ReadonlyState) []string {
+	return stateStrings(state, LongTermFactsStateKey)
+}
+
+// mergeFacts appends the extracted facts not already in known, dropping the
+// oldest facts beyond maxFacts. It returns the merged facts and the number
+// of facts added.
+func mergeFacts(known, extracted []string, maxFacts int) ([]string, int) {
+	seen := make(map[string]bool, len(known)+len(extracted))
+	for _, fact := range known {
+		seen[normalizeFact(fact)] = true
+	}
+
+	facts := slices.Clone(known)
+	added := 0
+	for _, fact := range extracted {
+		fact = strings.TrimSpace(fact)
+		key := normalizeFact(fact)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		facts = append(facts, fact)
+		added++
+	}
+
+	if len(facts) > maxFacts {
+		facts = facts[len(facts)-maxFacts:]
+	}
+	return facts, added
+}
+
+// normalizeFact returns the comparison key of a fact, so facts differing
+// only in case, whitespace or trailing punctuation are duplicates.
+func normalizeFact(fact string) string {
+	fact = strings.Join(strings.Fields(strings.ToLower(fact)), " ")
+	return strings.TrimRight(fact, ".!;")
+}
+
+// lastTurn returns the events from the latest user message onwards.
+func lastTurn(events []*agent.Event) []*agent.Event {
+	starts := turnStarts(events)
+	if len(starts) == 0 {
+		return nil
+	}
+	return events[starts[len(starts)-1]:]
+}
+
+// LongTermMemoryProvider is implemented by agents that have long-term memory.
+// This allows the runner to update it after each turn.
+type LongTermMemoryProvider interface {
+	// LongTermMemory returns the agent's long-term memory.
+	// Returns nil if long-term memory is not configured.
+	LongTermMemory() *LongTermMemory
+}
@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory_test
+
+import (
+	"context"
+	"iter"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/memory"
+)
+
+// mapState is a ReadonlyState backed by a map.
+type mapState map[string]any
+
+func (s mapState) Get(key string) (any, error) { return s[key], nil }
+func (s mapState) All() iter.Seq2[string, any] { return maps.All(s) }
+
+// staticExtractor returns fixed facts and records the events it was given.
+type staticExtractor struct {
+	facts  []string
+	events []*agent.Event
+	known  []string
+}
+
+func (e *staticExtractor) ExtractFacts(_ context.Context, events []*agent.Event, known []string) ([]string, error) {
+	e.events, e.known = events, known
+	return e.facts, nil
+}
+
+func textEvent(id, author, text string) *agent.Event {
+	return &agent.Event{
+		ID:      id,
+		Author:  author,
+		Message: a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: text}),
+	}
+}
+
+func TestLongTermMemory_UpdateDedupsFacts(t *testing.T) {
+	extractor := &staticExtractor{facts: []string{
+		"The user's name is Ana.",
+		"the user's  name is ana",
+		"The user prefers tea.",
+		"The user prefers tea",
+	}}
+	ltm, err := memory.NewLongTermMemory(memory.LongTermMemoryConfig{Extractor: extractor})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := []*agent.Event{
+		textEvent("0", agent.AuthorUser, "Hi, I'm Ana"),
+		textEvent("1", "assistant", "Hello Ana"),
+		textEvent("2", agent.AuthorUser, "I prefer tea over coffee"),
+		textEvent("3", "assistant", "Noted"),
+	}
+	state := mapState{memory.LongTermFactsStateKey: []any{"The user's name is Ana"}}
+
+	ev, err := ltm.Update(context.Background(), state, events)
+	if err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if got := eventIDs(extractor.events); !slices.Equal(got, []string{"2", "3"}) {
+		t.Errorf("extracted from %v, want only the latest turn [2 3]", got)
+	}
+	if !slices.Equal(extractor.known, []string{"The user's name is Ana"}) {
+		t.Errorf("known facts = %v", extractor.known)
+	}
+
+	want := []string{"The user's name is Ana", "The user prefers tea."}
+	if ev == nil {
+		t.Fatal("Update() = nil, want state delta event")
+	}
+	if got := ev.Actions.StateDelta[memory.LongTermFactsStateKey]; !slices.Equal(got.([]string), want) {
+		t.Errorf("stored facts = %v, want %v", got, want)
+	}
+
+	// Nothing new is stored when every extracted fact is known
+	extractor.facts = []string{"the user prefers tea"}
+	state[memory.LongTermFactsStateKey] = want
+	if ev, err := ltm.Update(context.Background(), state, events); ev != nil || err != nil {
+		t.Errorf("Update() with known facts = %v, %v; want nil", ev, err)
+	}
+}
+
+func TestLongTermMemory_MaxFacts(t *testing.T) {
+	extractor := &staticExtractor{facts: []string{"fact c", "fact d"}}
+	ltm, err := memory.NewLongTermMemory(memory.LongTermMemoryConfig{Extractor: extractor, MaxFacts: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	state := mapState{memory.LongTermFactsStateKey: []string{"fact a", "fact b"}}
+	ev, err := ltm.Update(context.Background(), state, []*agent.Event{textEvent("0", agent.AuthorUser, "hi")})
+	if err != nil || ev == nil {
+		t.Fatalf("Update() = %v, %v", ev, err)
+	}
+	if got := ev.Actions.StateDelta[memory.LongTermFactsStateKey].([]string); !slices.Equal(got, []string{"fact b", "fact c", "fact d"}) {
+		t.Errorf("stored facts = %v, want oldest dropped", got)
+	}
+}
+
+func TestLongTermMemory_Relevant(t *testing.T) {
+	ltm, err := memory.NewLongTermMemory(memory.LongTermMemoryConfig{
+		Extractor:   &staticExtractor{},
+		MaxInjected: 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	facts := []string{
+		"The user's name is Ana",
+		"The user is vegetarian",
+		"The user lives in Lisbon",
+	}
+	got := ltm.Relevant(facts, "any vegetarian restaurants nearby?")
+	if want := []string{"The user is vegetarian", "The user lives in Lisbon"}; !slices.Equal(got, want) {
+		t.Errorf("Relevant() = %v, want matching fact then most recent", got)
+	}
+}
@@ -17,8 +17,10 @@ package memory_test
 
 import (
 	"context"
+	"fmt"
 	"iter"
 	"slices"
+	"strconv"
 	"testing"
 	"time"
 
@@ -572,6 +574,201 @@ func TestBufferWindowStrategy_DefaultSize(t *testing.T) {
 	}
 }
 
+func TestBufferWindowStrategy_KeepsPinnedEvents(t *testing.T) {
+	strategy := memory.NewBufferWindowStrategy(memory.BufferWindowConfig{WindowSize: 2})
+
+	events := make([]*agent.Event, 5)
+	for i := range events {
+		events[i] = &agent.Event{ID: string(rune('0' + i)), Author: "user"}
+	}
+	events[1].CustomMetadata = map[string]any{memory.PinnedMetadataKey: true}
+
+	filtered := strategy.FilterEvents(events)
+	if len(filtered) != 3 {
+		t.Fatalf("expected 3 events (1 pinned + 2 in window), got %d", len(filtered))
+	}
+	if filtered[0].ID != "1" || filtered[1].ID != "3" || filtered[2].ID != "4" {
+		t.Errorf("unexpected events kept: %q, %q, %q", filtered[0].ID, filtered[1].ID, filtered[2].ID)
+	}
+}
+
+func TestBufferWindowStrategy_KeepsToolCallsWithResults(t *testing.T) {
+	// user, call (a, b), result a, result b, answer
+	events := []*agent.Event{
+		{ID: "0", Author: "user"},
+		{ID: "1", Author: "agent", ToolCalls: []agent.ToolCallState{{ID: "a"}, {ID: "b"}}},
+		{ID: "2", Author: "agent", ToolResults: []agent.ToolResultState{{ToolCallID: "a"}}},
+		{ID: "3", Author: "agent", ToolResults: []agent.ToolResultState{{ToolCallID: "b"}}},
+		{ID: "4", Author: "agent"},
+	}
+
+	tests := []struct {
+		windowSize int
+		wantFirst  string
+	}{
+		{4, "1"}, // boundary before the call keeps the whole unit
+		{3, "4"}, // boundary after the call drops both results
+		{2, "4"}, // boundary between the results drops the second one too
+	}
+	for _, tt := range tests {
+		strategy := memory.NewBufferWindowStrategy(memory.BufferWindowConfig{WindowSize: tt.windowSize})
+		filtered := strategy.FilterEvents(events)
+		if len(filtered) == 0 || filtered[0].ID != tt.wantFirst {
+			t.Errorf("window %d: kept %v, want first event %q", tt.windowSize, eventIDs(filtered), tt.wantFirst)
+		}
+	}
+
+	// Calls and results carried only as message parts, without IDs
+	parts := []*agent.Event{
+		{ID: "0", Author: "user"},
+		{ID: "1", Author: "agent", Message: a2a.NewMessage(a2a.MessageRoleAgent,
+			a2a.DataPart{Data: map[string]any{"type": "tool_use", "id": "x"}})},
+		{ID: "2", Author: "agent", Message: a2a.NewMessage(a2a.MessageRoleUser,
+			a2a.DataPart{Data: map[string]any{"type": "tool_result"}})},
+		{ID: "3", Author: "agent"},
+	}
+	strategy := memory.NewBufferWindowStrategy(memory.BufferWindowConfig{WindowSize: 2})
+	if filtered := strategy.FilterEvents(parts); len(filtered) != 1 || filtered[0].ID != "3" {
+		t.Errorf("kept %v, want [3]", eventIDs(filtered))
+	}
+}
+
+func TestTokenWindowStrategy_KeepsToolCallsWithResults(t *testing.T) {
+	strategy, err := memory.NewTokenWindowStrategy(memory.TokenWindowConfig{Budget: 1, PreserveRecent: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	long := a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "a long message that is over budget"})
+	events := []*agent.Event{
+		{ID: "0", Author: "user", Message: long},
+		{ID: "1", Author: "agent", Message: long, ToolCalls: []agent.ToolCallState{{ID: "a"}}},
+		{ID: "2", Author: "agent", Message: long, ToolResults: []agent.ToolResultState{{ToolCallID: "a"}}},
+		{ID: "3", Author: "agent", Message: long},
+	}
+
+	// The preserved minimum (last 2) starts at the result; dropping it would
+	// go below the minimum, so the call is kept with it instead
+	filtered := strategy.FilterEvents(events)
+	if got := eventIDs(filtered); !slices.Equal(got, []string{"1", "2", "3"}) {
+		t.Errorf("kept %v, want [1 2 3]", got)
+	}
+}
+
+// recordingSummarizer returns numbered summaries and records its inputs.
+type recordingSummarizer struct {
+	inputs [][]*agent.Event
+}
+
+func (r *recordingSummarizer) SummarizeConversation(_ context.Context, events []*agent.Event) (string, error) {
+	r.inputs = append(r.inputs, events)
+	return fmt.Sprintf("summary %d", len(r.inputs)), nil
+}
+
+// conversationTurns returns n turns of a user message and an agent reply,
+// with event IDs numbered from first.
+func conversationTurns(first, n int) []*agent.Event {
+	var events []*agent.Event
+	for i := range n {
+		for j, author := range []string{agent.AuthorUser, "assistant"} {
+			id := first + 2*i + j
+			events = append(events, &agent.Event{
+				ID:      strconv.Itoa(id),
+				Author:  author,
+				Message: a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: fmt.Sprintf("message %d about the quarterly planning review", id)}),
+			})
+		}
+	}
+	return events
+}
+
+func TestSummaryWindowStrategy_SummarizesIncrementally(t *testing.T) {
+	summarizer := &recordingSummarizer{}
+	strategy, err := memory.NewSummaryWindowStrategy(memory.SummaryWindowConfig{
+		KeepLastTurns: 2,
+		MaxTokens:     60,
+		Summarizer:    summarizer,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := conversationTurns(0, 6)
+	first, err := strategy.CheckAndSummarize(context.Background(), events)
+	if err != nil || first == nil {
+		t.Fatalf("CheckAndSummarize() = %v, %v; want summary event", first, err)
+	}
+	if got := eventIDs(summarizer.inputs[0]); !slices.Equal(got, []string{"0", "1", "2", "3", "4", "5", "6", "7"}) {
+		t.Errorf("first summary input = %v, want the 4 turns before the window", got)
+	}
+	if through := first.CustomMetadata[memory.SummaryThroughKey]; through != "7" {
+		t.Errorf("summary through = %v, want 7", through)
+	}
+
+	// Two more turns push the oldest kept turns out of the window; only
+	// they and the previous summary are summarized again.
+	events = append(append(events, first), conversationTurns(12, 2)...)
+	second, err := strategy.CheckAndSummarize(context.Background(), events)
+	if err != nil || second == nil {
+		t.Fatalf("second CheckAndSummarize() = %v, %v; want summary event", second, err)
+	}
+	if got := eventIDs(summarizer.inputs[1]); !slices.Equal(got, []string{first.ID, "8", "9", "10", "11"}) {
+		t.Errorf("second summary input = %v, want previous summary and turns 8-11", got)
+	}
+}
+
+func TestSummaryWindowStrategy_FilterEvents(t *testing.T) {
+	strategy, err := memory.NewSummaryWindowStrategy(memory.SummaryWindowConfig{
+		KeepLastTurns: 2,
+		MaxTokens:     10000,
+		Summarizer:    &recordingSummarizer{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	events := conversationTurns(0, 6)
+	summary := &agent.Event{
+		ID:             "s",
+		Author:         agent.AuthorSystem,
+		Message:        a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: memory.SummaryPrefix + "earlier turns"}),
+		CustomMetadata: map[string]any{memory.SummaryThroughKey: "7"},
+	}
+	events = append(events, summary)
+
+	if got := eventIDs(strategy.FilterEvents(events)); !slices.Equal(got, []string{"s", "8", "9", "10", "11"}) {
+		t.Errorf("FilterEvents() = %v, want summary and unsummarized turns", got)
+	}
+
+	// Under budget nothing is summarized
+	if ev, err := strategy.CheckAndSummarize(context.Background(), events); ev != nil || err != nil {
+		t.Errorf("CheckAndSummarize() under budget = %v, %v; want nil", ev, err)
+	}
+}
+
+func TestSummaryWindowStrategy_HardCap(t *testing.T) {
+	strategy, err := memory.NewSummaryWindowStrategy(memory.SummaryWindowConfig{
+		KeepLastTurns: 2,
+		MaxTokens:     1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a summarizer the window is trimmed down to the latest event
+	if got := eventIDs(strategy.FilterEvents(conversationTurns(0, 4))); !slices.Equal(got, []string{"7"}) {
+		t.Errorf("FilterEvents() = %v, want [7]", got)
+	}
+}
+
+func eventIDs(events []*agent.Event) []string {
+	ids := make([]string, len(events))
+	for i, ev := range events {
+		ids[i] = ev.ID
+	}
+	return ids
+}
+
 func TestWorkingMemoryProvider_Interface(t *testing.T) {
 	// Verify the interface is exported and usable
 	var _ memory.WorkingMemoryProvider = &mockWorkingMemoryProvider{}
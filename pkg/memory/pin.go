@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"github.com/kadirpekel/hector/pkg/agent"
+)
+
+const (
+	// PinnedMetadataKey marks an event as pinned when set to true in the
+	// message metadata (e.g., A2A message {"metadata": {"pinned": true}})
+	// or in the event's CustomMetadata.
+	PinnedMetadataKey = "pinned"
+
+	// PinnedStateKey is the session state key holding notes pinned via the
+	// pin_context tool. State is never trimmed, so these always survive.
+	PinnedStateKey = "pinned_context"
+)
+
+// IsPinned reports whether an event is pinned and must survive trimming.
+func IsPinned(ev *agent.Event) bool {
+	if ev == nil {
+		return false
+	}
+	if pinned, _ := ev.CustomMetadata[PinnedMetadataKey].(bool); pinned {
+		return true
+	}
+	if ev.Message != nil {
+		if pinned, _ := ev.Message.Metadata[PinnedMetadataKey].(bool); pinned {
+			return true
+		}
+	}
+	return false
+}
+
+// withPinned restores pinned events that a strategy dropped.
+// kept must be a suffix of events; pinned events from the dropped prefix are
+// prepended in their original order. Events carrying tool calls or results are
+// never restored on their own, since that would orphan the call/result pair.
+func withPinned(events, kept []*agent.Event) []*agent.Event {
+	dropped := events[:len(events)-len(kept)]
+
+	var pinned []*agent.Event
+	for _, ev := range dropped {
+		if IsPinned(ev) && !ev.HasToolCalls() && !ev.HasToolResults() {
+			pinned = append(pinned, ev)
+		}
+	}
+	if len(pinned) == 0 {
+		return kept
+	}
+
+	result := make([]*agent.Event, 0, len(pinned)+len(kept))
+	result = append(result, pinned...)
+	return append(result, kept...)
+}
+
+// PinnedNotes returns the notes pinned via the pin_context tool.
+func PinnedNotes(state agent.ReadonlyState) []string {
+	return stateStrings(state, PinnedStateKey)
+}
+
+// stateStrings returns the string list stored under key in state. Lists
+// read back from persisted state arrive as []any.
+func stateStrings(state agent.ReadonlyState, key string) []string {
+	if state == nil {
+		return nil
+	}
+	value, err := state.Get(key)
+	if err != nil || value == nil {
+		return nil
+	}
+
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []any:
+		items := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" {
+				items = append(items, s)
+			}
+		}
+		return items
+	}
+	return nil
+}
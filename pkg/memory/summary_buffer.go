@@ -131,11 +131,13 @@ func (s *SummaryBufferStrategy) Name() string {
 
 // FilterEvents returns events that fit within the target token budget.
 // It looks for existing summaries (checkpoint) and loads from there,
-// or applies token-based filtering.
+// or applies token-based filtering. Pinned events are always kept,
+// even when they precede the summary.
 func (s *SummaryBufferStrategy) FilterEvents(events []*agent.Event) []*agent.Event {
 	if len(events) == 0 {
 		return events
 	}
+	all := events
 
 	// Look for existing summary (checkpoint) - start from there
 	summaryIdx := s.findLastSummaryIndex(events)
@@ -149,7 +151,7 @@ func (s *SummaryBufferStrategy) FilterEvents(events []*agent.Event) []*agent.Eve
 
 	// Apply token-based filtering within target budget
 	targetBudget := int(float64(s.budget) * s.target)
-	return s.filterEventsWithinBudget(events, targetBudget)
+	return withPinned(all, s.filterEventsWithinBudget(events, targetBudget))
 }
 
 // CheckAndSummarize checks if summarization should occur and performs it.
@@ -216,8 +218,17 @@ func (s *SummaryBufferStrategy) summarize(ctx context.Context, events []*agent.E
 	return summaryEvent, nil
 }
 
-// filterEventsWithinBudget returns events that fit within the token budget.
+// filterEventsWithinBudget returns events that fit within the token budget,
+// keeping each tool call together with its results.
 func (s *SummaryBufferStrategy) filterEventsWithinBudget(events []*agent.Event, budget int) []*agent.Event {
+	selected := s.selectWithinBudget(events, budget)
+	start := toolSafeStart(events, len(events)-len(selected), min(len(selected), DefaultMinMessagesToKeep))
+	return events[start:]
+}
+
+// selectWithinBudget returns the most recent events that fit within budget,
+// subject to the DefaultMinMessagesToKeep minimum.
+func (s *SummaryBufferStrategy) selectWithinBudget(events []*agent.Event, budget int) []*agent.Event {
 	if len(events) == 0 {
 		return events
 	}
@@ -268,7 +279,7 @@ func (s *SummaryBufferStrategy) selectRecentEventsWithMinimum(events []*agent.Ev
 		if startIdx < 0 {
 			startIdx = 0
 		}
-		return events[startIdx:]
+		return events[toolSafeStart(events, startIdx, minEvents):]
 	}
 
 	return recentEvents
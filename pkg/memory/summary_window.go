@@ -0,0 +1,277 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/google/uuid"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/utils"
+)
+
+// Default summary window settings
+const (
+	DefaultSummaryWindowTurns     = 4    // Recent turns kept verbatim
+	DefaultSummaryWindowMaxTokens = 8000 // Hard cap on context tokens
+)
+
+// SummaryThroughKey is the CustomMetadata key of a summary_window summary
+// event. It holds the ID of the last event folded into the summary.
+const SummaryThroughKey = "summary_through"
+
+// SummaryWindowStrategy keeps the last N turns verbatim and folds everything
+// older into a rolling summary, within a hard token cap.
+//
+// A turn starts at each user message. When the summary plus the unsummarized
+// events exceed MaxTokens, the turns older than the window are summarized
+// together with the previous summary only, so each summarization costs one
+// window's worth of history rather than the whole conversation.
+type SummaryWindowStrategy struct {
+	keepLastTurns int
+	maxTokens     int
+	tokenCounter  *utils.TokenCounter
+	summarizer    Summarizer
+}
+
+// SummaryWindowConfig holds configuration for the summary window strategy.
+type SummaryWindowConfig struct {
+	// KeepLastTurns is the number of recent turns kept verbatim.
+	// Default: 4
+	KeepLastTurns int
+
+	// MaxTokens caps the tokens of the summary and kept events.
+	// Default: 8000
+	MaxTokens int
+
+	// Model is the LLM model name for accurate token counting.
+	// If empty, tokens are estimated (~4 chars per token).
+	Model string
+
+	// Summarizer performs conversation summarization.
+	// If nil, summarization is disabled and old turns are only trimmed.
+	Summarizer Summarizer
+}
+
+// NewSummaryWindowStrategy creates a new summary window strategy.
+func NewSummaryWindowStrategy(cfg SummaryWindowConfig) (*SummaryWindowStrategy, error) {
+	keepLastTurns := cfg.KeepLastTurns
+	if keepLastTurns <= 0 {
+		keepLastTurns = DefaultSummaryWindowTurns
+	}
+
+	maxTokens := cfg.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = DefaultSummaryWindowMaxTokens
+	}
+
+	// Create token counter if model is provided
+	var tokenCounter *utils.TokenCounter
+	if cfg.Model != "" {
+		var err error
+		tokenCounter, err = utils.NewTokenCounter(cfg.Model)
+		if err != nil {
+			slog.Warn("Failed to create token counter, using estimation",
+				"model", cfg.Model,
+				"error", err)
+		}
+	}
+
+	return &SummaryWindowStrategy{
+		keepLastTurns: keepLastTurns,
+		maxTokens:     maxTokens,
+		tokenCounter:  tokenCounter,
+		summarizer:    cfg.Summarizer,
+	}, nil
+}
+
+// Name returns the strategy name.
+func (s *SummaryWindowStrategy) Name() string {
+	return "summary_window"
+}
+
+// FilterEvents returns the rolling summary followed by the unsummarized
+// events. When they exceed MaxTokens, only the last KeepLastTurns turns are
+// kept, and the oldest of those are trimmed further if still over the cap.
+// Pinned events are always kept.
+func (s *SummaryWindowStrategy) FilterEvents(events []*agent.Event) []*agent.Event {
+	if len(events) == 0 {
+		return events
+	}
+
+	summary, history, pending := s.split(events)
+	budget := s.maxTokens - s.countEventTokens(summary)
+	kept := withPinned(history, s.fit(pending, budget))
+	if summary == nil {
+		return kept
+	}
+	return append([]*agent.Event{summary}, kept...)
+}
+
+// CheckAndSummarize folds the turns older than the window into the rolling
+// summary once the context exceeds MaxTokens.
+// Returns the new summary event, or nil if no summarization was needed.
+func (s *SummaryWindowStrategy) CheckAndSummarize(ctx context.Context, events []*agent.Event) (*agent.Event, error) {
+	if s.summarizer == nil {
+		return nil, nil // Summarization disabled
+	}
+
+	summary, _, pending := s.split(events)
+	if s.countEventTokens(summary)+s.countEventsTokens(pending) <= s.maxTokens {
+		return nil, nil
+	}
+
+	starts := turnStarts(pending)
+	if len(starts) <= s.keepLastTurns {
+		return nil, nil // Nothing older than the window; FilterEvents trims
+	}
+	evicted := pending[:starts[len(starts)-s.keepLastTurns]]
+
+	// Summarize only the previous summary and the newly evicted turns
+	input := evicted
+	if summary != nil {
+		input = append([]*agent.Event{summary}, evicted...)
+	}
+
+	slog.Info("Updating rolling summary",
+		"evicted", len(evicted),
+		"keeping_turns", s.keepLastTurns)
+
+	text, err := s.summarizer.SummarizeConversation(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("summarization failed: %w", err)
+	}
+
+	return &agent.Event{
+		ID:     uuid.NewString(),
+		Author: agent.AuthorSystem,
+		Message: a2a.NewMessage(a2a.MessageRoleUser,
+			a2a.TextPart{Text: SummaryPrefix + text}),
+		CustomMetadata: map[string]any{
+			SummaryThroughKey: evicted[len(evicted)-1].ID,
+		},
+	}, nil
+}
+
+// split returns the latest rolling summary, the history without summary
+// events, and the suffix of that history not yet folded into the summary.
+func (s *SummaryWindowStrategy) split(events []*agent.Event) (summary *agent.Event, history, pending []*agent.Event) {
+	through := ""
+	for i := len(events) - 1; i >= 0; i-- {
+		if isWindowSummary(events[i]) {
+			summary = events[i]
+			through, _ = summary.CustomMetadata[SummaryThroughKey].(string)
+			break
+		}
+	}
+
+	history = make([]*agent.Event, 0, len(events))
+	pendingStart := 0
+	for _, ev := range events {
+		if isWindowSummary(ev) {
+			if ev == summary && through == "" {
+				pendingStart = len(history)
+			}
+			continue
+		}
+		history = append(history, ev)
+		if summary != nil && through != "" && ev.ID == through {
+			pendingStart = len(history)
+		}
+	}
+	return summary, history, history[pendingStart:]
+}
+
+// fit returns the suffix of events within budget: all of them if they fit,
+// otherwise the last KeepLastTurns turns, trimmed from the front until
+// they fit or only the latest event remains.
+func (s *SummaryWindowStrategy) fit(events []*agent.Event, budget int) []*agent.Event {
+	if s.countEventsTokens(events) <= budget {
+		return events
+	}
+
+	start := 0
+	if starts := turnStarts(events); len(starts) > s.keepLastTurns {
+		start = starts[len(starts)-s.keepLastTurns]
+	}
+
+	tokens := s.countEventsTokens(events[start:])
+	for tokens > budget && start < len(events)-1 {
+		tokens -= s.countEventTokens(events[start])
+		start++
+	}
+	return events[toolSafeStart(events, start, 1):]
+}
+
+// turnStarts returns the indexes of the user messages that open each turn.
+func turnStarts(events []*agent.Event) []int {
+	var starts []int
+	for i, ev := range events {
+		if ev != nil && ev.Author == agent.AuthorUser && ev.Message != nil && !ev.HasToolResults() {
+			starts = append(starts, i)
+		}
+	}
+	return starts
+}
+
+// isWindowSummary reports whether ev is a summary_window summary event.
+func isWindowSummary(ev *agent.Event) bool {
+	if ev == nil {
+		return false
+	}
+	_, ok := ev.CustomMetadata[SummaryThroughKey]
+	return ok
+}
+
+// countEventsTokens counts total tokens for all events.
+func (s *SummaryWindowStrategy) countEventsTokens(events []*agent.Event) int {
+	total := 0
+	for _, ev := range events {
+		total += s.countEventTokens(ev)
+	}
+	return total
+}
+
+// countEventTokens counts tokens for a single event.
+func (s *SummaryWindowStrategy) countEventTokens(ev *agent.Event) int {
+	if ev == nil || ev.Message == nil {
+		return 0
+	}
+
+	text := extractTextFromMessage(ev.Message)
+	if s.tokenCounter == nil {
+		return utils.EstimateTokens(text)
+	}
+	messages := []utils.Message{{Role: ev.Author, Content: text}}
+	return s.tokenCounter.CountMessages(messages)
+}
+
+// KeepLastTurns returns the number of turns kept verbatim.
+func (s *SummaryWindowStrategy) KeepLastTurns() int {
+	return s.keepLastTurns
+}
+
+// MaxTokens returns the configured token cap.
+func (s *SummaryWindowStrategy) MaxTokens() int {
+	return s.maxTokens
+}
+
+// Ensure SummaryWindowStrategy implements WorkingMemoryStrategy.
+var _ WorkingMemoryStrategy = (*SummaryWindowStrategy)(nil)
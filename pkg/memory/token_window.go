@@ -99,7 +99,8 @@ func (s *TokenWindowStrategy) Name() string {
 
 // FilterEvents returns events that fit within the token budget.
 // It preserves at least preserveRecent messages and works backwards
-// from the most recent events.
+// from the most recent events. Pinned events are always kept, and a tool
+// call is kept or dropped together with its results.
 func (s *TokenWindowStrategy) FilterEvents(events []*agent.Event) []*agent.Event {
 	if len(events) == 0 {
 		return events
@@ -138,7 +139,8 @@ func (s *TokenWindowStrategy) FilterEvents(events []*agent.Event) []*agent.Event
 	}
 	if len(fitted) < minKeep {
 		// Return last minKeep events
-		return events[len(events)-minKeep:]
+		start := toolSafeStart(events, len(events)-minKeep, minKeep)
+		return withPinned(events, events[start:])
 	}
 
 	// Return events corresponding to fitted messages
@@ -146,13 +148,14 @@ func (s *TokenWindowStrategy) FilterEvents(events []*agent.Event) []*agent.Event
 	if startIdx < 0 {
 		startIdx = 0
 	}
+	startIdx = toolSafeStart(events, startIdx, minKeep)
 
 	slog.Debug("TokenWindowStrategy filtered events",
 		"total_events", len(events),
 		"kept_events", len(events)-startIdx,
 		"budget", s.budget)
 
-	return events[startIdx:]
+	return withPinned(events, events[startIdx:])
 }
 
 // CheckAndSummarize always returns nil (token window doesn't summarize).
@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+)
+
+// toolSafeStart adjusts the start of a trimmed window events[start:] so a
+// tool call and its results are kept or dropped together. Providers reject
+// histories with a tool result whose call is missing.
+//
+// The start first moves forward, dropping the orphaned results, as long as
+// at least minKeep events remain. Otherwise it moves back to include the
+// call that the window's first results answer.
+func toolSafeStart(events []*agent.Event, start, minKeep int) int {
+	if start <= 0 || start >= len(events) {
+		return start
+	}
+
+	// callIdx[j] is the earliest event holding a call answered in event j
+	callIdx := toolCallIndexes(events)
+
+	// safe reports whether no result in events[s:] answers a call before s
+	safe := func(s int) bool {
+		for j := s; j < len(events); j++ {
+			if callIdx[j] >= 0 && callIdx[j] < s {
+				return false
+			}
+		}
+		return true
+	}
+
+	for s := start; s < len(events) && len(events)-s >= minKeep; s++ {
+		if safe(s) {
+			return s
+		}
+	}
+	for s := start - 1; s > 0; s-- {
+		if safe(s) {
+			return s
+		}
+	}
+	return 0
+}
+
+// toolCallIndexes maps each event to the earliest event holding a tool call
+// it answers, or -1 for events without tool results. Results that carry no
+// known call ID are attributed to the nearest preceding tool call.
+func toolCallIndexes(events []*agent.Event) []int {
+	callAt := make(map[string]int)
+	idx := make([]int, len(events))
+	lastCall := -1
+
+	for i, ev := range events {
+		idx[i] = -1
+		if ev == nil {
+			continue
+		}
+
+		for _, id := range eventToolResultIDs(ev) {
+			at, ok := callAt[id]
+			if !ok {
+				at = lastCall
+			}
+			if at >= 0 && (idx[i] < 0 || at < idx[i]) {
+				idx[i] = at
+			}
+		}
+
+		if ev.HasToolCalls() {
+			for _, id := range eventToolCallIDs(ev) {
+				callAt[id] = i
+			}
+			lastCall = i
+		}
+	}
+	return idx
+}
+
+// eventToolCallIDs returns the IDs of the tool calls in an event.
+func eventToolCallIDs(ev *agent.Event) []string {
+	var ids []string
+	for _, tc := range ev.ToolCalls {
+		ids = append(ids, tc.ID)
+	}
+	return append(ids, dataPartIDs(ev.Message, "tool_use", "id")...)
+}
+
+// eventToolResultIDs returns the call IDs answered by the results in an
+// event. Results without an ID are reported as "".
+func eventToolResultIDs(ev *agent.Event) []string {
+	var ids []string
+	for _, tr := range ev.ToolResults {
+		ids = append(ids, tr.ToolCallID)
+	}
+	return append(ids, dataPartIDs(ev.Message, "tool_result", "tool_call_id")...)
+}
+
+// dataPartIDs returns the idKey values of a message's data parts of the given type.
+func dataPartIDs(msg *a2a.Message, partType, idKey string) []string {
+	if msg == nil {
+		return nil
+	}
+	var ids []string
+	for _, part := range msg.Parts {
+		dp, ok := part.(a2a.DataPart)
+		if !ok {
+			continue
+		}
+		if t, _ := dp.Data["type"].(string); t == partType {
+			id, _ := dp.Data[idKey].(string)
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
@@ -26,6 +26,7 @@ import (
 //   - buffer_window: Keep last N messages (simple, fast)
 //   - token_window: Keep messages within token budget (accurate)
 //   - summary_buffer: Summarize old messages when exceeding budget (compact)
+//   - summary_window: Keep recent turns verbatim plus a rolling summary
 //
 // Ported from pkg/memory/types.go for use in v2.
 //
@@ -50,6 +50,9 @@ const (
 	defaultMaxTokens = 4096
 	defaultTimeout   = 120 * time.Second
 
+	// defaultConnectTimeout bounds dialing and the TLS handshake.
+	defaultConnectTimeout = 10 * time.Second
+
 	// Temperature when thinking is enabled (Anthropic requirement)
 	thinkingTemperature = 1.0
 )
@@ -61,17 +64,40 @@ type Config struct {
 	MaxTokens           int
 	Temperature         *float64
 	BaseURL             string
-	Timeout             time.Duration
 	MaxRetries          int
 	EnableThinking      bool
 	ThinkingBudget      int
 	MaxToolOutputLength int
+
+	// Timeout bounds each request from connect to the last byte.
+	// Default: 120s for non-streaming requests; streams are unbounded.
+	Timeout time.Duration
+
+	// ConnectTimeout bounds dialing and the TLS handshake. Default: 10s.
+	ConnectTimeout time.Duration
+
+	// Roles overrides the role names sent for user and agent messages.
+	// Default: user, assistant.
+	Roles model.RoleMapping
+	// SystemMessages controls how mid-conversation system messages are sent.
+	// The Messages API has no system role, so native mode is not supported.
+	// Default: model.SystemMessagesMerge.
+	SystemMessages model.SystemMessageMode
+
+	// Bedrock, when set, sends requests to Claude on AWS Bedrock instead of
+	// the Anthropic API. Model is then a Bedrock model ID, APIKey and
+	// BaseURL are ignored.
+	Bedrock *BedrockConfig
 }
 
+// defaultRoles are the Messages API role names.
+var defaultRoles = model.RoleMapping{User: "user", Agent: "assistant"}
+
 // Client is an Anthropic LLM implementation.
 // Implements model.LLM interface aligned with ADK-Go.
 type Client struct {
 	httpClient          *httpclient.Client
+	timeout             time.Duration
 	apiKey              string
 	baseURL             string
 	model               string
@@ -80,13 +106,19 @@ type Client struct {
 	temperature         *float64
 	enableThinking      bool
 	thinkingBudget      int
+	roles               model.RoleMapping
+	systemMessages      model.SystemMessageMode
+	bedrock             *bedrockTransport
 }
 
 // New creates a new Anthropic client.
 func New(cfg Config) (*Client, error) {
-	if cfg.APIKey == "" {
+	if cfg.APIKey == "" && cfg.Bedrock == nil {
 		return nil, fmt.Errorf("API key is required")
 	}
+	if cfg.SystemMessages == model.SystemMessagesNative {
+		return nil, fmt.Errorf("system message mode %q is not supported by Anthropic", cfg.SystemMessages)
+	}
 
 	baseURL := cfg.BaseURL
 	if baseURL == "" {
@@ -103,9 +135,9 @@ func New(cfg Config) (*Client, error) {
 		maxTokens = defaultMaxTokens
 	}
 
-	timeout := cfg.Timeout
-	if timeout == 0 {
-		timeout = defaultTimeout
+	connectTimeout := cfg.ConnectTimeout
+	if connectTimeout == 0 {
+		connectTimeout = defaultConnectTimeout
 	}
 
 	maxRetries := cfg.MaxRetries
@@ -114,7 +146,8 @@ func New(cfg Config) (*Client, error) {
 	}
 
 	httpClient := httpclient.New(
-		httpclient.WithHTTPClient(&http.Client{Timeout: timeout}),
+		httpclient.WithHTTPClient(&http.Client{}),
+		httpclient.WithConnectTimeout(connectTimeout),
 		httpclient.WithMaxRetries(maxRetries),
 		httpclient.WithHeaderParser(httpclient.ParseAnthropicHeaders),
 	)
@@ -124,8 +157,32 @@ func New(cfg Config) (*Client, error) {
 		thinkingBudget = 10000
 	}
 
+	systemMessages := cfg.SystemMessages
+	if systemMessages == "" {
+		systemMessages = model.SystemMessagesMerge
+	}
+
+	var bedrock *bedrockTransport
+	if cfg.Bedrock != nil {
+		if cfg.Bedrock.Region == "" {
+			return nil, fmt.Errorf("bedrock region is required")
+		}
+		bedrock = &bedrockTransport{
+			region:      cfg.Bedrock.Region,
+			endpoint:    strings.TrimSuffix(cfg.Bedrock.Endpoint, "/"),
+			credentials: cfg.Bedrock.Credentials,
+		}
+		if bedrock.endpoint == "" {
+			bedrock.endpoint = fmt.Sprintf("https://bedrock-runtime.%s.amazonaws.com", cfg.Bedrock.Region)
+		}
+		if bedrock.credentials == nil {
+			bedrock.credentials = DefaultAWSCredentials("")
+		}
+	}
+
 	return &Client{
 		httpClient:          httpClient,
+		timeout:             cfg.Timeout,
 		apiKey:              cfg.APIKey,
 		baseURL:             baseURL,
 		model:               modelName,
@@ -134,6 +191,9 @@ func New(cfg Config) (*Client, error) {
 		temperature:         cfg.Temperature,
 		enableThinking:      cfg.EnableThinking,
 		thinkingBudget:      thinkingBudget,
+		roles:               cfg.Roles.WithDefaults(defaultRoles),
+		systemMessages:      systemMessages,
+		bedrock:             bedrock,
 	}, nil
 }
 
@@ -157,11 +217,19 @@ func (c *Client) Provider() model.Provider {
 //   - Yields multiple partial Responses (Partial=true) for real-time UI updates
 //   - Finally yields aggregated Response (Partial=false) for session persistence
 func (c *Client) GenerateContent(ctx context.Context, req *model.Request, stream bool) iter.Seq2[*model.Response, error] {
-	if stream {
-		return c.generateStream(ctx, req)
-	}
-
 	return func(yield func(*model.Response, error) bool) {
+		ctx, cancel := model.WithRequestTimeout(ctx, c.timeout, defaultTimeout, stream)
+		defer cancel()
+
+		if stream {
+			for resp, err := range c.generateStream(ctx, req) {
+				if !yield(resp, err) {
+					return
+				}
+			}
+			return
+		}
+
 		resp, err := c.generate(ctx, req)
 		yield(resp, err)
 	}
@@ -175,19 +243,11 @@ func (c *Client) Close() error {
 // generate performs non-streaming generation.
 func (c *Client) generate(ctx context.Context, req *model.Request) (*model.Response, error) {
 	apiReq := c.buildRequest(req, false)
-
-	body, err := json.Marshal(apiReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/v1/messages", bytes.NewReader(body))
+	httpReq, err := c.newHTTPRequest(ctx, apiReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	c.setHeaders(httpReq)
-
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
@@ -204,7 +264,7 @@ func (c *Client) generate(ctx context.Context, req *model.Request) (*model.Respo
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return c.parseResponse(&apiResp), nil
+	return c.parseResponse(&apiResp, apiReq.structured), nil
 }
 
 // streamState holds state accumulated during SSE streaming.
@@ -215,6 +275,12 @@ type streamState struct {
 	thinkingSignatures map[int]string
 	usage              *model.Usage
 	finishReason       model.FinishReason
+
+	// structured is set when the structured output tool was requested.
+	// Its input is streamed as text rather than as a tool call.
+	structured      *structuredOutput
+	structuredIndex int
+	otherToolCalls  bool
 }
 
 func newStreamState() *streamState {
@@ -234,21 +300,12 @@ func (c *Client) generateStream(ctx context.Context, req *model.Request) iter.Se
 
 	return func(yield func(*model.Response, error) bool) {
 		apiReq := c.buildRequest(req, true)
-
-		body, err := json.Marshal(apiReq)
-		if err != nil {
-			yield(nil, fmt.Errorf("failed to marshal request: %w", err))
-			return
-		}
-
-		httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/v1/messages", bytes.NewReader(body))
+		httpReq, err := c.newHTTPRequest(ctx, apiReq)
 		if err != nil {
-			yield(nil, fmt.Errorf("failed to create request: %w", err))
+			yield(nil, err)
 			return
 		}
 
-		c.setHeaders(httpReq)
-
 		resp, err := c.httpClient.Do(httpReq)
 		if err != nil {
 			yield(nil, fmt.Errorf("request failed: %w", err))
@@ -262,32 +319,23 @@ func (c *Client) generateStream(ctx context.Context, req *model.Request) iter.Se
 			return
 		}
 
-		// Parse SSE stream
-		reader := bufio.NewReader(resp.Body)
+		// The Anthropic API streams SSE; Bedrock wraps the same events in
+		// the AWS event stream encoding
+		events := sseEvents(resp.Body)
+		if c.bedrock != nil {
+			events = bedrockStreamEvents(resp.Body)
+		}
 		state := newStreamState()
+		state.structured = apiReq.structured
 
-		for {
-			line, err := reader.ReadString('\n')
+		for data, err := range events {
 			if err != nil {
-				if err == io.EOF {
-					break
-				}
-				yield(nil, fmt.Errorf("stream read error: %w", err))
+				yield(nil, err)
 				return
 			}
 
-			line = strings.TrimSpace(line)
-			if line == "" || !strings.HasPrefix(line, "data: ") {
-				continue
-			}
-
-			data := strings.TrimPrefix(line, "data: ")
-			if data == "[DONE]" {
-				break
-			}
-
 			var event streamEvent
-			if err := json.Unmarshal([]byte(data), &event); err != nil {
+			if err := json.Unmarshal(data, &event); err != nil {
 				continue
 			}
 
@@ -313,6 +361,35 @@ func (c *Client) generateStream(ctx context.Context, req *model.Request) iter.Se
 	}
 }
 
+// sseEvents yields the data of each server-sent event in r.
+func sseEvents(r io.Reader) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		reader := bufio.NewReader(r)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				if err != io.EOF {
+					yield(nil, fmt.Errorf("stream read error: %w", err))
+				}
+				return
+			}
+
+			line = strings.TrimSpace(line)
+			if line == "" || !strings.HasPrefix(line, "data: ") {
+				continue
+			}
+
+			data := strings.TrimPrefix(line, "data: ")
+			if data == "[DONE]" {
+				return
+			}
+			if !yield([]byte(data), nil) {
+				return
+			}
+		}
+	}
+}
+
 // processStreamEvent processes a single SSE event through the aggregator.
 // Returns partial responses for real-time UI updates.
 func (c *Client) processStreamEvent(event *streamEvent, state *streamState, agg *model.StreamingAggregator) iter.Seq2[*model.Response, error] {
@@ -322,6 +399,12 @@ func (c *Client) processStreamEvent(event *streamEvent, state *streamState, agg
 			if event.ContentBlock != nil {
 				switch event.ContentBlock.Type {
 				case "tool_use":
+					if state.structured != nil && event.ContentBlock.Name == structuredOutputTool {
+						state.structuredIndex = event.Index
+						state.toolJSONBuffers[event.Index] = ""
+						break
+					}
+					state.otherToolCalls = true
 					state.toolCalls[event.Index] = &tool.ToolCall{
 						ID:   event.ContentBlock.ID,
 						Name: event.ContentBlock.Name,
@@ -360,6 +443,19 @@ func (c *Client) processStreamEvent(event *streamEvent, state *streamState, agg
 			}
 
 		case "content_block_stop":
+			// Structured output is returned as the response text
+			if state.structured != nil && event.Index == state.structuredIndex {
+				if jsonStr, ok := state.toolJSONBuffers[event.Index]; ok {
+					delete(state.toolJSONBuffers, event.Index)
+					for resp, err := range agg.ProcessTextDelta(state.structured.text(json.RawMessage(jsonStr))) {
+						if !yield(resp, err) {
+							return
+						}
+					}
+					return
+				}
+			}
+
 			// Handle tool call completion
 			if tc, ok := state.toolCalls[event.Index]; ok {
 				if jsonStr, ok := state.toolJSONBuffers[event.Index]; ok && jsonStr != "" {
@@ -387,6 +483,10 @@ func (c *Client) processStreamEvent(event *streamEvent, state *streamState, agg
 					switch event.Delta.StopReason {
 					case "tool_use":
 						state.finishReason = model.FinishReasonToolCalls
+						if !state.otherToolCalls {
+							// Only the structured output tool was called
+							state.finishReason = model.FinishReasonStop
+						}
 					case "max_tokens":
 						state.finishReason = model.FinishReasonLength
 					default:
@@ -405,6 +505,44 @@ func (c *Client) processStreamEvent(event *streamEvent, state *streamState, agg
 	}
 }
 
+// newHTTPRequest creates the HTTP request for apiReq, addressed and
+// authenticated for the Anthropic API or Bedrock.
+func (c *Client) newHTTPRequest(ctx context.Context, apiReq *apiRequest) (*http.Request, error) {
+	url := c.baseURL + "/v1/messages"
+	if c.bedrock != nil {
+		// Bedrock takes the model and streaming mode from the URL, and the
+		// API version and beta features from the body
+		url = c.bedrock.url(apiReq.Model, apiReq.Stream)
+		apiReq.Model = ""
+		apiReq.Stream = false
+		apiReq.AnthropicVersion = bedrockAnthropicVersion
+		if apiReq.Thinking != nil {
+			apiReq.AnthropicBeta = []string{betaThinking}
+		}
+	}
+
+	body, err := json.Marshal(apiReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if c.bedrock == nil {
+		c.setHeaders(httpReq)
+		return httpReq, nil
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	if err := c.bedrock.sign(httpReq, body, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
+	return httpReq, nil
+}
+
 // setHeaders sets the required HTTP headers.
 func (c *Client) setHeaders(req *http.Request) {
 	req.Header.Set("Content-Type", "application/json")
@@ -417,6 +555,7 @@ func (c *Client) setHeaders(req *http.Request) {
 
 // buildRequest creates an API request from model.Request.
 func (c *Client) buildRequest(req *model.Request, stream bool) *apiRequest {
+	req = model.ApplySystemMessages(req, c.systemMessages)
 	thinkingEnabled := c.enableThinking || (req.Config != nil && req.Config.EnableThinking)
 
 	apiReq := &apiRequest{
@@ -455,10 +594,7 @@ func (c *Client) buildRequest(req *model.Request, stream bool) *apiRequest {
 			continue
 		}
 
-		role := "user"
-		if msg.Role == a2a.MessageRoleAgent {
-			role = "assistant"
-		}
+		role := c.roles.Role(msg)
 
 		var content []apiContent
 		for _, part := range msg.Parts {
@@ -542,11 +678,15 @@ func (c *Client) buildRequest(req *model.Request, stream bool) *apiRequest {
 		})
 	}
 
+	applyResponseSchema(apiReq, req.Config, thinkingEnabled)
+
 	return apiReq
 }
 
 // parseResponse converts API response to model.Response.
-func (c *Client) parseResponse(resp *apiResponse) *model.Response {
+// With structured output, the structured output tool's input replaces
+// the response text.
+func (c *Client) parseResponse(resp *apiResponse, structured *structuredOutput) *model.Response {
 	result := &model.Response{
 		Partial:      false, // Non-streaming response is always complete
 		TurnComplete: true,
@@ -568,6 +708,7 @@ func (c *Client) parseResponse(resp *apiResponse) *model.Response {
 
 	// Parse content
 	var parts []a2a.Part
+	var structuredText string
 	for _, content := range resp.Content {
 		switch content.Type {
 		case "text":
@@ -578,6 +719,11 @@ func (c *Client) parseResponse(resp *apiResponse) *model.Response {
 				Signature: content.Signature,
 			}
 		case "tool_use":
+			if structured != nil && content.Name == structuredOutputTool {
+				input, _ := json.Marshal(content.Input)
+				structuredText = structured.text(input)
+				continue
+			}
 			result.ToolCalls = append(result.ToolCalls, tool.ToolCall{
 				ID:   content.ID,
 				Name: content.Name,
@@ -586,6 +732,13 @@ func (c *Client) parseResponse(resp *apiResponse) *model.Response {
 		}
 	}
 
+	if structuredText != "" {
+		parts = []a2a.Part{a2a.TextPart{Text: structuredText}}
+		if len(result.ToolCalls) == 0 {
+			result.FinishReason = model.FinishReasonStop
+		}
+	}
+
 	if len(parts) > 0 {
 		result.Content = &model.Content{
 			Parts: parts,
@@ -617,14 +770,27 @@ func getString(m map[string]any, key string) string {
 // API types
 
 type apiRequest struct {
-	Model       string            `json:"model"`
+	Model       string            `json:"model,omitempty"`
 	Messages    []apiMessage      `json:"messages"`
 	MaxTokens   int               `json:"max_tokens"`
 	Temperature float64           `json:"temperature,omitempty"`
-	Stream      bool              `json:"stream"`
+	Stream      bool              `json:"stream,omitempty"`
 	System      string            `json:"system,omitempty"`
 	Tools       []apiTool         `json:"tools,omitempty"`
+	ToolChoice  *toolChoice       `json:"tool_choice,omitempty"`
 	Thinking    *thinkingSettings `json:"thinking,omitempty"`
+
+	// Bedrock only
+	AnthropicVersion string   `json:"anthropic_version,omitempty"`
+	AnthropicBeta    []string `json:"anthropic_beta,omitempty"`
+
+	// structured is set when the request asks for structured output.
+	structured *structuredOutput
+}
+
+type toolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
 }
 
 type thinkingSettings struct {
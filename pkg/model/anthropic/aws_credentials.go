@@ -0,0 +1,282 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// awsCredentialsRefreshWindow is how long before expiry temporary
+	// credentials are replaced.
+	awsCredentialsRefreshWindow = 5 * time.Minute
+
+	// awsMetadataTimeout bounds each request to a credentials endpoint, so
+	// hosts outside AWS don't wait long on the instance metadata service.
+	awsMetadataTimeout = 2 * time.Second
+
+	defaultIMDSEndpoint      = "http://169.254.169.254"
+	defaultContainerEndpoint = "http://169.254.170.2"
+)
+
+// errNoAWSCredentials reports that a source is not configured, so the chain
+// moves on to the next one.
+var errNoAWSCredentials = errors.New("not configured")
+
+// refreshingAWSCredentials caches temporary credentials of fetch until
+// shortly before they expire.
+type refreshingAWSCredentials struct {
+	fetch func(ctx context.Context) (AWSCredentials, time.Time, error)
+	now   func() time.Time
+
+	mu      sync.Mutex
+	creds   AWSCredentials
+	expires time.Time
+}
+
+func (r *refreshingAWSCredentials) get(ctx context.Context) (AWSCredentials, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.creds.AccessKeyID != "" && r.now().Before(r.expires.Add(-awsCredentialsRefreshWindow)) {
+		return r.creds, nil
+	}
+	creds, expires, err := r.fetch(ctx)
+	if err != nil {
+		return AWSCredentials{}, err
+	}
+	r.creds, r.expires = creds, expires
+	return creds, nil
+}
+
+// awsCredentialsResponse is the JSON document served by the container and
+// instance metadata credential endpoints.
+type awsCredentialsResponse struct {
+	Code            string
+	Message         string
+	AccessKeyID     string `json:"AccessKeyId"`
+	SecretAccessKey string
+	Token           string
+	Expiration      time.Time
+}
+
+func (r *awsCredentialsResponse) credentials() (AWSCredentials, time.Time, error) {
+	if r.Code != "" && r.Code != "Success" {
+		return AWSCredentials{}, time.Time{}, fmt.Errorf("%s: %s", r.Code, r.Message)
+	}
+	if r.AccessKeyID == "" || r.SecretAccessKey == "" {
+		return AWSCredentials{}, time.Time{}, errors.New("response has no credentials")
+	}
+	return AWSCredentials{AccessKeyID: r.AccessKeyID, SecretAccessKey: r.SecretAccessKey, SessionToken: r.Token}, r.Expiration, nil
+}
+
+// awsMetadataGet sends a request to a credentials endpoint and returns the
+// response body.
+func awsMetadataGet(ctx context.Context, client *http.Client, method, url string, header http.Header) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, awsMetadataTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: status %d", method, url, resp.StatusCode)
+	}
+	return body, nil
+}
+
+// containerAWSCredentials fetches the task role credentials of ECS, EKS Pod
+// Identity and other container platforms from
+// AWS_CONTAINER_CREDENTIALS_RELATIVE_URI or AWS_CONTAINER_CREDENTIALS_FULL_URI.
+func containerAWSCredentials(client *http.Client) func(ctx context.Context) (AWSCredentials, time.Time, error) {
+	return func(ctx context.Context) (AWSCredentials, time.Time, error) {
+		endpoint := os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI")
+		if rel := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); rel != "" {
+			endpoint = defaultContainerEndpoint + rel
+		}
+		if endpoint == "" {
+			return AWSCredentials{}, time.Time{}, errNoAWSCredentials
+		}
+
+		header := http.Header{}
+		token := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN")
+		if path := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE"); path != "" {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return AWSCredentials{}, time.Time{}, fmt.Errorf("container authorization token: %w", err)
+			}
+			token = strings.TrimSpace(string(data))
+		}
+		if token != "" {
+			header.Set("Authorization", token)
+		}
+
+		body, err := awsMetadataGet(ctx, client, http.MethodGet, endpoint, header)
+		if err != nil {
+			return AWSCredentials{}, time.Time{}, fmt.Errorf("container credentials: %w", err)
+		}
+		var resp awsCredentialsResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return AWSCredentials{}, time.Time{}, fmt.Errorf("container credentials: %w", err)
+		}
+		return resp.credentials()
+	}
+}
+
+// imdsAWSCredentials fetches the EC2 instance role credentials from the
+// instance metadata service, using an IMDSv2 session token.
+func imdsAWSCredentials(client *http.Client) func(ctx context.Context) (AWSCredentials, time.Time, error) {
+	return func(ctx context.Context) (AWSCredentials, time.Time, error) {
+		if strings.EqualFold(os.Getenv("AWS_EC2_METADATA_DISABLED"), "true") {
+			return AWSCredentials{}, time.Time{}, errNoAWSCredentials
+		}
+		endpoint := strings.TrimSuffix(os.Getenv("AWS_EC2_METADATA_SERVICE_ENDPOINT"), "/")
+		if endpoint == "" {
+			endpoint = defaultIMDSEndpoint
+		}
+
+		token, err := awsMetadataGet(ctx, client, http.MethodPut, endpoint+"/latest/api/token",
+			http.Header{"X-Aws-Ec2-Metadata-Token-Ttl-Seconds": {"21600"}})
+		if err != nil {
+			return AWSCredentials{}, time.Time{}, fmt.Errorf("instance metadata: %w", err)
+		}
+		header := http.Header{"X-Aws-Ec2-Metadata-Token": {string(token)}}
+
+		base := endpoint + "/latest/meta-data/iam/security-credentials/"
+		roles, err := awsMetadataGet(ctx, client, http.MethodGet, base, header)
+		if err != nil {
+			return AWSCredentials{}, time.Time{}, fmt.Errorf("instance metadata: %w", err)
+		}
+		role, _, _ := strings.Cut(strings.TrimSpace(string(roles)), "\n")
+		if role == "" {
+			return AWSCredentials{}, time.Time{}, errors.New("instance metadata: no instance role attached")
+		}
+
+		body, err := awsMetadataGet(ctx, client, http.MethodGet, base+url.PathEscape(role), header)
+		if err != nil {
+			return AWSCredentials{}, time.Time{}, fmt.Errorf("instance metadata: %w", err)
+		}
+		var resp awsCredentialsResponse
+		if err := json.Unmarshal(body, &resp); err != nil {
+			return AWSCredentials{}, time.Time{}, fmt.Errorf("instance metadata: %w", err)
+		}
+		return resp.credentials()
+	}
+}
+
+// webIdentityAWSCredentials exchanges the token in
+// AWS_WEB_IDENTITY_TOKEN_FILE for credentials of AWS_ROLE_ARN with STS
+// AssumeRoleWithWebIdentity, as used by EKS IAM roles for service accounts.
+func webIdentityAWSCredentials(client *http.Client) func(ctx context.Context) (AWSCredentials, time.Time, error) {
+	return func(ctx context.Context) (AWSCredentials, time.Time, error) {
+		tokenFile, roleARN := os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE"), os.Getenv("AWS_ROLE_ARN")
+		if tokenFile == "" || roleARN == "" {
+			return AWSCredentials{}, time.Time{}, errNoAWSCredentials
+		}
+		// The token file is rotated; read it on every refresh
+		token, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return AWSCredentials{}, time.Time{}, fmt.Errorf("web identity token: %w", err)
+		}
+		sessionName := os.Getenv("AWS_ROLE_SESSION_NAME")
+		if sessionName == "" {
+			sessionName = fmt.Sprintf("hector-%d", time.Now().UnixNano())
+		}
+
+		endpoint := os.Getenv("AWS_ENDPOINT_URL_STS")
+		if endpoint == "" {
+			endpoint = "https://sts.amazonaws.com"
+			if region := awsRegionFromEnv(); region != "" {
+				endpoint = "https://sts." + region + ".amazonaws.com"
+			}
+		}
+		query := url.Values{
+			"Action":           {"AssumeRoleWithWebIdentity"},
+			"Version":          {"2011-06-15"},
+			"RoleArn":          {roleARN},
+			"RoleSessionName":  {sessionName},
+			"WebIdentityToken": {strings.TrimSpace(string(token))},
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/",
+			strings.NewReader(query.Encode()))
+		if err != nil {
+			return AWSCredentials{}, time.Time{}, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		resp, err := client.Do(req)
+		if err != nil {
+			return AWSCredentials{}, time.Time{}, fmt.Errorf("assume role with web identity: %w", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		if err != nil {
+			return AWSCredentials{}, time.Time{}, fmt.Errorf("assume role with web identity: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return AWSCredentials{}, time.Time{}, fmt.Errorf("assume role with web identity: status %d: %s", resp.StatusCode, body)
+		}
+
+		var result struct {
+			Credentials struct {
+				AccessKeyID     string    `xml:"AccessKeyId"`
+				SecretAccessKey string    `xml:"SecretAccessKey"`
+				SessionToken    string    `xml:"SessionToken"`
+				Expiration      time.Time `xml:"Expiration"`
+			} `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+		}
+		if err := xml.Unmarshal(body, &result); err != nil {
+			return AWSCredentials{}, time.Time{}, fmt.Errorf("assume role with web identity: %w", err)
+		}
+		c := result.Credentials
+		if c.AccessKeyID == "" || c.SecretAccessKey == "" {
+			return AWSCredentials{}, time.Time{}, errors.New("assume role with web identity: response has no credentials")
+		}
+		return AWSCredentials{AccessKeyID: c.AccessKeyID, SecretAccessKey: c.SecretAccessKey, SessionToken: c.SessionToken}, c.Expiration, nil
+	}
+}
+
+// awsRegionFromEnv returns $AWS_REGION, then $AWS_DEFAULT_REGION.
+func awsRegionFromEnv() string {
+	if region := os.Getenv("AWS_REGION"); region != "" {
+		return region
+	}
+	return os.Getenv("AWS_DEFAULT_REGION")
+}
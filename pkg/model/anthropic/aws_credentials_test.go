@@ -0,0 +1,170 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anthropic
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// clearAWSCredentialsEnv unsets every credential source so a test enables
+// only the one it exercises.
+func clearAWSCredentialsEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{
+		"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN", "AWS_PROFILE",
+		"AWS_WEB_IDENTITY_TOKEN_FILE", "AWS_ROLE_ARN", "AWS_ROLE_SESSION_NAME", "AWS_ENDPOINT_URL_STS",
+		"AWS_CONTAINER_CREDENTIALS_RELATIVE_URI", "AWS_CONTAINER_CREDENTIALS_FULL_URI",
+		"AWS_CONTAINER_AUTHORIZATION_TOKEN", "AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE",
+		"AWS_EC2_METADATA_SERVICE_ENDPOINT",
+	} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", t.TempDir()+"/missing")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+}
+
+func TestDefaultAWSCredentialsContainer(t *testing.T) {
+	clearAWSCredentialsEnv(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer task" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		io.WriteString(w, `{"AccessKeyId":"AKIDTASK","SecretAccessKey":"s","Token":"tok","Expiration":"`+
+			time.Now().Add(time.Hour).UTC().Format(time.RFC3339)+`"}`)
+	}))
+	defer srv.Close()
+	t.Setenv("AWS_CONTAINER_CREDENTIALS_FULL_URI", srv.URL+"/creds")
+	t.Setenv("AWS_CONTAINER_AUTHORIZATION_TOKEN", "Bearer task")
+
+	creds, err := DefaultAWSCredentials("")(context.Background())
+	if err != nil {
+		t.Fatalf("DefaultAWSCredentials() error = %v", err)
+	}
+	if creds.AccessKeyID != "AKIDTASK" || creds.SecretAccessKey != "s" || creds.SessionToken != "tok" {
+		t.Errorf("creds = %+v", creds)
+	}
+}
+
+func TestDefaultAWSCredentialsInstanceMetadata(t *testing.T) {
+	clearAWSCredentialsEnv(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/latest/api/token":
+			io.WriteString(w, "session")
+		case r.Header.Get("X-Aws-Ec2-Metadata-Token") != "session":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/latest/meta-data/iam/security-credentials/":
+			io.WriteString(w, "app-role\n")
+		case r.URL.Path == "/latest/meta-data/iam/security-credentials/app-role":
+			io.WriteString(w, `{"Code":"Success","AccessKeyId":"AKIDEC2","SecretAccessKey":"s","Token":"tok","Expiration":"`+
+				time.Now().Add(time.Hour).UTC().Format(time.RFC3339)+`"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "")
+	t.Setenv("AWS_EC2_METADATA_SERVICE_ENDPOINT", srv.URL)
+
+	creds, err := DefaultAWSCredentials("")(context.Background())
+	if err != nil {
+		t.Fatalf("DefaultAWSCredentials() error = %v", err)
+	}
+	if creds.AccessKeyID != "AKIDEC2" || creds.SessionToken != "tok" {
+		t.Errorf("creds = %+v", creds)
+	}
+}
+
+func TestDefaultAWSCredentialsWebIdentity(t *testing.T) {
+	clearAWSCredentialsEnv(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		form, _ := url.ParseQuery(string(body))
+		if form.Get("Action") != "AssumeRoleWithWebIdentity" || form.Get("WebIdentityToken") != "jwt" ||
+			form.Get("RoleArn") != "arn:aws:iam::123:role/app" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		io.WriteString(w, `<AssumeRoleWithWebIdentityResponse><AssumeRoleWithWebIdentityResult><Credentials>`+
+			`<AccessKeyId>AKIDIRSA</AccessKeyId><SecretAccessKey>s</SecretAccessKey><SessionToken>tok</SessionToken>`+
+			`<Expiration>`+time.Now().Add(time.Hour).UTC().Format(time.RFC3339)+`</Expiration>`+
+			`</Credentials></AssumeRoleWithWebIdentityResult></AssumeRoleWithWebIdentityResponse>`)
+	}))
+	defer srv.Close()
+	tokenFile := t.TempDir() + "/token"
+	if err := os.WriteFile(tokenFile, []byte("jwt\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AWS_WEB_IDENTITY_TOKEN_FILE", tokenFile)
+	t.Setenv("AWS_ROLE_ARN", "arn:aws:iam::123:role/app")
+	t.Setenv("AWS_ENDPOINT_URL_STS", srv.URL)
+
+	creds, err := DefaultAWSCredentials("")(context.Background())
+	if err != nil {
+		t.Fatalf("DefaultAWSCredentials() error = %v", err)
+	}
+	if creds.AccessKeyID != "AKIDIRSA" || creds.SessionToken != "tok" {
+		t.Errorf("creds = %+v", creds)
+	}
+}
+
+func TestDefaultAWSCredentialsNoneFound(t *testing.T) {
+	clearAWSCredentialsEnv(t)
+
+	_, err := DefaultAWSCredentials("")(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "no AWS credentials found") {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestRefreshingAWSCredentialsRefreshesBeforeExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	var fetches atomic.Int32
+	r := &refreshingAWSCredentials{
+		fetch: func(context.Context) (AWSCredentials, time.Time, error) {
+			fetches.Add(1)
+			return AWSCredentials{AccessKeyID: "AKID", SecretAccessKey: "s"}, now.Add(time.Hour), nil
+		},
+		now: func() time.Time { return now },
+	}
+
+	for range 3 {
+		if _, err := r.get(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := fetches.Load(); got != 1 {
+		t.Errorf("fetches before expiry = %d, want 1", got)
+	}
+
+	now = now.Add(56 * time.Minute)
+	if _, err := r.get(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := fetches.Load(); got != 2 {
+		t.Errorf("fetches near expiry = %d, want 2", got)
+	}
+}
@@ -0,0 +1,422 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anthropic
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"iter"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// bedrockAnthropicVersion is the anthropic_version Bedrock expects in
+	// the request body.
+	bedrockAnthropicVersion = "bedrock-2023-05-31"
+
+	// bedrockSigningService is the SigV4 service name of bedrock-runtime.
+	bedrockSigningService = "bedrock"
+)
+
+// BedrockConfig routes requests to Claude on AWS Bedrock.
+//
+// Bedrock serves the Messages API at
+// {endpoint}/model/{model-id}/invoke (invoke-with-response-stream when
+// streaming), with the model ID in the path and the API version in the body.
+// Requests are signed with AWS Signature Version 4.
+type BedrockConfig struct {
+	// Region is the AWS region (e.g., "us-east-1").
+	Region string
+
+	// Endpoint overrides the bedrock-runtime endpoint.
+	// Default: https://bedrock-runtime.{region}.amazonaws.com
+	Endpoint string
+
+	// Credentials signs requests. Default: DefaultAWSCredentials("").
+	Credentials AWSCredentialsProvider
+}
+
+// AWSCredentials are the keys requests are signed with.
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// AWSCredentialsProvider returns the credentials for a request.
+type AWSCredentialsProvider func(ctx context.Context) (AWSCredentials, error)
+
+// StaticAWSCredentials returns a provider that always returns the given keys.
+func StaticAWSCredentials(accessKeyID, secretAccessKey, sessionToken string) AWSCredentialsProvider {
+	creds := AWSCredentials{AccessKeyID: accessKeyID, SecretAccessKey: secretAccessKey, SessionToken: sessionToken}
+	return func(context.Context) (AWSCredentials, error) {
+		return creds, nil
+	}
+}
+
+// DefaultAWSCredentials returns a provider that looks up credentials like the
+// AWS SDKs do, in order:
+//   - AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN
+//   - The shared credentials file (AWS_SHARED_CREDENTIALS_FILE or
+//     ~/.aws/credentials), section profile, $AWS_PROFILE or "default"
+//   - Web identity (AWS_WEB_IDENTITY_TOKEN_FILE and AWS_ROLE_ARN), as set by
+//     EKS IAM roles for service accounts
+//   - The container credentials endpoint of ECS tasks and EKS Pod Identity
+//   - The EC2 instance role, through IMDSv2
+//
+// Temporary credentials are cached and refreshed before they expire. SSO
+// profiles are not supported.
+func DefaultAWSCredentials(profile string) AWSCredentialsProvider {
+	// Metadata endpoints are link-local and must not go through a proxy
+	metadataClient := &http.Client{Transport: &http.Transport{}}
+	webIdentity := &refreshingAWSCredentials{fetch: webIdentityAWSCredentials(http.DefaultClient), now: time.Now}
+	container := &refreshingAWSCredentials{fetch: containerAWSCredentials(metadataClient), now: time.Now}
+	instance := &refreshingAWSCredentials{fetch: imdsAWSCredentials(metadataClient), now: time.Now}
+
+	return func(ctx context.Context) (AWSCredentials, error) {
+		if id, secret := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"); id != "" && secret != "" {
+			return AWSCredentials{AccessKeyID: id, SecretAccessKey: secret, SessionToken: os.Getenv("AWS_SESSION_TOKEN")}, nil
+		}
+
+		name := profile
+		if name == "" {
+			name = os.Getenv("AWS_PROFILE")
+		}
+		if name == "" {
+			name = "default"
+		}
+		creds, sharedErr := sharedAWSCredentials(name)
+		if sharedErr == nil {
+			return creds, nil
+		}
+
+		// A configured web identity or container source that fails is an
+		// error rather than a reason to fall back to the instance role
+		for _, source := range []*refreshingAWSCredentials{webIdentity, container} {
+			creds, err := source.get(ctx)
+			if err == nil {
+				return creds, nil
+			}
+			if !errors.Is(err, errNoAWSCredentials) {
+				return AWSCredentials{}, err
+			}
+		}
+
+		creds, err := instance.get(ctx)
+		if err != nil {
+			return AWSCredentials{}, fmt.Errorf("no AWS credentials found: %w", errors.Join(sharedErr, err))
+		}
+		return creds, nil
+	}
+}
+
+// sharedAWSCredentials reads a profile from the shared credentials file.
+func sharedAWSCredentials(profile string) (AWSCredentials, error) {
+	path := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return AWSCredentials{}, err
+		}
+		path = filepath.Join(home, ".aws", "credentials")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return AWSCredentials{}, err
+	}
+	defer f.Close()
+
+	var creds AWSCredentials
+	var inProfile bool
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			inProfile = strings.TrimSpace(line[1:len(line)-1]) == profile
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !inProfile || !ok {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "aws_access_key_id":
+			creds.AccessKeyID = strings.TrimSpace(value)
+		case "aws_secret_access_key":
+			creds.SecretAccessKey = strings.TrimSpace(value)
+		case "aws_session_token":
+			creds.SessionToken = strings.TrimSpace(value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return AWSCredentials{}, err
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return AWSCredentials{}, fmt.Errorf("profile %q not found in %s", profile, path)
+	}
+	return creds, nil
+}
+
+// bedrockTransport sends Messages API requests to bedrock-runtime.
+type bedrockTransport struct {
+	region      string
+	endpoint    string
+	credentials AWSCredentialsProvider
+}
+
+// url returns the invoke URL of a model, escaped as AWS expects.
+func (t *bedrockTransport) url(modelID string, stream bool) string {
+	action := "invoke"
+	if stream {
+		action = "invoke-with-response-stream"
+	}
+	return t.endpoint + "/model/" + awsURIEscape(modelID) + "/" + action
+}
+
+// sign signs req and its body with SigV4.
+func (t *bedrockTransport) sign(req *http.Request, body []byte, now time.Time) error {
+	creds, err := t.credentials(req.Context())
+	if err != nil {
+		return err
+	}
+	signSigV4(req, body, creds, t.region, bedrockSigningService, now)
+	return nil
+}
+
+// signSigV4 adds AWS Signature Version 4 headers to req.
+// See: https://docs.aws.amazon.com/IAM/latest/UserGuide/create-signed-request.html
+func signSigV4(req *http.Request, body []byte, creds AWSCredentials, region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", creds.SessionToken)
+	}
+
+	// Canonical headers: host plus every header set so far, sorted
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	// Services other than S3 sign the escaped path escaped once more
+	segments := strings.Split(req.URL.EscapedPath(), "/")
+	for i, s := range segments {
+		segments[i] = awsURIEscape(s)
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		strings.Join(segments, "/"),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+creds.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		creds.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// awsURIEscape escapes everything but unreserved characters (RFC 3986).
+func awsURIEscape(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			sb.WriteByte(c)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// bedrockStreamEvents decodes an invoke-with-response-stream body.
+//
+// The body is in the AWS event stream encoding: binary frames with headers
+// and a payload. Each "chunk" event carries one Messages API stream event,
+// base64-encoded in the payload's bytes field.
+func bedrockStreamEvents(r io.Reader) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		reader := bufio.NewReader(r)
+		for {
+			headers, payload, err := readEventStreamMessage(reader)
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					yield(nil, fmt.Errorf("stream read error: %w", err))
+				}
+				return
+			}
+
+			switch headers[":message-type"] {
+			case "event":
+				if headers[":event-type"] != "chunk" {
+					continue
+				}
+				var chunk struct {
+					Bytes string `json:"bytes"`
+				}
+				if err := json.Unmarshal(payload, &chunk); err != nil {
+					continue
+				}
+				data, err := base64.StdEncoding.DecodeString(chunk.Bytes)
+				if err != nil {
+					continue
+				}
+				if !yield(data, nil) {
+					return
+				}
+			case "exception", "error":
+				var body struct {
+					Message string `json:"message"`
+				}
+				_ = json.Unmarshal(payload, &body)
+				kind := headers[":exception-type"]
+				if kind == "" {
+					kind = headers[":error-code"]
+				}
+				yield(nil, fmt.Errorf("bedrock stream error (%s): %s", kind, body.Message))
+				return
+			}
+		}
+	}
+}
+
+// readEventStreamMessage reads one frame of the AWS event stream encoding.
+// Only string header values are returned; other header types are skipped.
+func readEventStreamMessage(r io.Reader) (map[string]string, []byte, error) {
+	prelude := make([]byte, 12)
+	if _, err := io.ReadFull(r, prelude); err != nil {
+		return nil, nil, err
+	}
+	totalLen := binary.BigEndian.Uint32(prelude[0:4])
+	headersLen := binary.BigEndian.Uint32(prelude[4:8])
+	if crc32.ChecksumIEEE(prelude[:8]) != binary.BigEndian.Uint32(prelude[8:12]) {
+		return nil, nil, fmt.Errorf("event stream prelude checksum mismatch")
+	}
+	if totalLen < 16 || headersLen > totalLen-16 {
+		return nil, nil, fmt.Errorf("invalid event stream message length")
+	}
+
+	rest := make([]byte, totalLen-12)
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return nil, nil, err
+	}
+	crc := crc32.NewIEEE()
+	crc.Write(prelude)
+	crc.Write(rest[:len(rest)-4])
+	if crc.Sum32() != binary.BigEndian.Uint32(rest[len(rest)-4:]) {
+		return nil, nil, fmt.Errorf("event stream message checksum mismatch")
+	}
+
+	headers, err := parseEventStreamHeaders(rest[:headersLen])
+	if err != nil {
+		return nil, nil, err
+	}
+	return headers, rest[headersLen : len(rest)-4], nil
+}
+
+// parseEventStreamHeaders decodes event stream headers.
+func parseEventStreamHeaders(b []byte) (map[string]string, error) {
+	// Sizes of the fixed-length header value types, by type ID
+	fixed := map[byte]int{0: 0, 1: 0, 2: 1, 3: 2, 4: 4, 5: 8, 8: 8, 9: 16}
+
+	headers := make(map[string]string)
+	for len(b) > 0 {
+		nameLen := int(b[0])
+		if len(b) < 2+nameLen {
+			return nil, fmt.Errorf("truncated event stream header")
+		}
+		name := string(b[1 : 1+nameLen])
+		valueType := b[1+nameLen]
+		b = b[2+nameLen:]
+
+		if size, ok := fixed[valueType]; ok {
+			if len(b) < size {
+				return nil, fmt.Errorf("truncated event stream header")
+			}
+			b = b[size:]
+			continue
+		}
+		// Byte array (6) and string (7) values are length-prefixed
+		if (valueType != 6 && valueType != 7) || len(b) < 2 {
+			return nil, fmt.Errorf("invalid event stream header %q", name)
+		}
+		valueLen := int(binary.BigEndian.Uint16(b[:2]))
+		if len(b) < 2+valueLen {
+			return nil, fmt.Errorf("truncated event stream header")
+		}
+		if valueType == 7 {
+			headers[name] = string(b[2 : 2+valueLen])
+		}
+		b = b[2+valueLen:]
+	}
+	return headers, nil
+}
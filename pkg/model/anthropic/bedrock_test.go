@@ -0,0 +1,228 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anthropic
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/model"
+)
+
+const testBedrockModel = "anthropic.claude-3-5-sonnet-20240620-v1:0"
+
+func newBedrockTestClient(t *testing.T, thinking bool, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := New(Config{
+		Model:          testBedrockModel,
+		EnableThinking: thinking,
+		Bedrock: &BedrockConfig{
+			Region:      "us-east-1",
+			Endpoint:    server.URL,
+			Credentials: StaticAWSCredentials("AKIDEXAMPLE", "secret", "token"),
+		},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return client
+}
+
+// eventStreamMessage encodes a message in the AWS event stream encoding.
+func eventStreamMessage(headers map[string]string, payload []byte) []byte {
+	var hb bytes.Buffer
+	for name, value := range headers {
+		hb.WriteByte(byte(len(name)))
+		hb.WriteString(name)
+		hb.WriteByte(7)
+		binary.Write(&hb, binary.BigEndian, uint16(len(value)))
+		hb.WriteString(value)
+	}
+
+	var msg bytes.Buffer
+	binary.Write(&msg, binary.BigEndian, uint32(16+hb.Len()+len(payload)))
+	binary.Write(&msg, binary.BigEndian, uint32(hb.Len()))
+	binary.Write(&msg, binary.BigEndian, crc32.ChecksumIEEE(msg.Bytes()))
+	msg.Write(hb.Bytes())
+	msg.Write(payload)
+	binary.Write(&msg, binary.BigEndian, crc32.ChecksumIEEE(msg.Bytes()))
+	return msg.Bytes()
+}
+
+func bedrockChunk(event string) []byte {
+	payload := fmt.Sprintf(`{"bytes":%q}`, base64.StdEncoding.EncodeToString([]byte(event)))
+	return eventStreamMessage(map[string]string{
+		":message-type": "event",
+		":event-type":   "chunk",
+		":content-type": "application/json",
+	}, []byte(payload))
+}
+
+func TestBedrockGenerate(t *testing.T) {
+	var body map[string]any
+	client := newBedrockTestClient(t, true, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/model/anthropic.claude-3-5-sonnet-20240620-v1%3A0/invoke" {
+			t.Errorf("path = %q", r.URL.EscapedPath())
+		}
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") ||
+			!strings.Contains(auth, "/us-east-1/bedrock/aws4_request") ||
+			!strings.Contains(auth, "x-amz-security-token") {
+			t.Errorf("Authorization = %q", auth)
+		}
+		if r.Header.Get("x-api-key") != "" {
+			t.Error("x-api-key must not be sent to Bedrock")
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		fmt.Fprint(w, `{"content":[{"type":"text","text":"Hello!"}],"stop_reason":"end_turn","usage":{"input_tokens":5,"output_tokens":2}}`)
+	})
+
+	req := &model.Request{Messages: []*a2a.Message{
+		a2a.NewMessage(a2a.MessageRoleUser, &a2a.TextPart{Text: "Hi"}),
+	}}
+	var resp *model.Response
+	for r, err := range client.GenerateContent(context.Background(), req, false) {
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+		resp = r
+	}
+
+	if body["anthropic_version"] != bedrockAnthropicVersion {
+		t.Errorf("anthropic_version = %v", body["anthropic_version"])
+	}
+	if _, ok := body["model"]; ok {
+		t.Error("model must be sent in the path, not the body")
+	}
+	if beta, _ := body["anthropic_beta"].([]any); len(beta) != 1 || beta[0] != betaThinking {
+		t.Errorf("anthropic_beta = %v", body["anthropic_beta"])
+	}
+	if thinking, _ := body["thinking"].(map[string]any); thinking["budget_tokens"] != float64(10000) {
+		t.Errorf("thinking = %v", body["thinking"])
+	}
+	if resp.TextContent() != "Hello!" {
+		t.Errorf("text = %q", resp.TextContent())
+	}
+}
+
+func TestBedrockGenerateStream(t *testing.T) {
+	client := newBedrockTestClient(t, false, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/invoke-with-response-stream") {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/vnd.amazon.eventstream")
+		for _, event := range []string{
+			`{"type":"message_start","message":{"usage":{"input_tokens":5}}}`,
+			`{"type":"content_block_start","index":0,"content_block":{"type":"text","text":""}}`,
+			`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"Hel"}}`,
+			`{"type":"content_block_delta","index":0,"delta":{"type":"text_delta","text":"lo!"}}`,
+			`{"type":"content_block_stop","index":0}`,
+			`{"type":"message_delta","delta":{"stop_reason":"end_turn"},"usage":{"output_tokens":2}}`,
+			`{"type":"message_stop"}`,
+		} {
+			w.Write(bedrockChunk(event))
+		}
+	})
+
+	req := &model.Request{Messages: []*a2a.Message{
+		a2a.NewMessage(a2a.MessageRoleUser, &a2a.TextPart{Text: "Hi"}),
+	}}
+	var partials int
+	var final *model.Response
+	for r, err := range client.GenerateContent(context.Background(), req, true) {
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+		if r.Partial {
+			partials++
+		} else {
+			final = r
+		}
+	}
+
+	if partials == 0 {
+		t.Error("expected partial responses")
+	}
+	if final == nil || final.TextContent() != "Hello!" {
+		t.Fatalf("final = %+v", final)
+	}
+}
+
+func TestBedrockStreamException(t *testing.T) {
+	client := newBedrockTestClient(t, false, func(w http.ResponseWriter, r *http.Request) {
+		w.Write(eventStreamMessage(map[string]string{
+			":message-type":   "exception",
+			":exception-type": "throttlingException",
+		}, []byte(`{"message":"Too many requests"}`)))
+	})
+
+	req := &model.Request{Messages: []*a2a.Message{
+		a2a.NewMessage(a2a.MessageRoleUser, &a2a.TextPart{Text: "Hi"}),
+	}}
+	var gotErr error
+	for _, err := range client.GenerateContent(context.Background(), req, true) {
+		if err != nil {
+			gotErr = err
+		}
+	}
+	if gotErr == nil || !strings.Contains(gotErr.Error(), "throttlingException") {
+		t.Errorf("error = %v", gotErr)
+	}
+}
+
+func TestDefaultAWSCredentialsSharedFile(t *testing.T) {
+	path := t.TempDir() + "/credentials"
+	content := "[default]\naws_access_key_id = AKIDDEFAULT\naws_secret_access_key = s1\n\n[prod]\naws_access_key_id = AKIDPROD\naws_secret_access_key = s2\naws_session_token = tok\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+	t.Setenv("AWS_PROFILE", "")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", path)
+
+	creds, err := DefaultAWSCredentials("prod")(context.Background())
+	if err != nil {
+		t.Fatalf("DefaultAWSCredentials() error = %v", err)
+	}
+	if creds.AccessKeyID != "AKIDPROD" || creds.SecretAccessKey != "s2" || creds.SessionToken != "tok" {
+		t.Errorf("creds = %+v", creds)
+	}
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDENV")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "s3")
+	creds, err = DefaultAWSCredentials("prod")(context.Background())
+	if err != nil || creds.AccessKeyID != "AKIDENV" {
+		t.Errorf("env credentials = %+v, %v", creds, err)
+	}
+}
@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anthropic
+
+import (
+	"encoding/json"
+
+	"github.com/kadirpekel/hector/pkg/model"
+)
+
+// structuredOutputTool is the tool Claude is made to call when a response
+// schema is set. The Messages API has no JSON mode, so the schema is sent
+// as the tool's input schema and the tool input becomes the response text.
+const structuredOutputTool = "structured_output"
+
+// structuredOutputValue wraps schemas that are not objects, since tool
+// inputs must be JSON objects.
+const structuredOutputValue = "value"
+
+// structuredOutput describes the structured output tool of a request.
+type structuredOutput struct {
+	// wrapped is set when the schema was wrapped in an object.
+	wrapped bool
+}
+
+// applyResponseSchema adds the structured output tool to apiReq if the
+// request sets a response schema. The tool is forced unless thinking is
+// enabled, which only allows automatic tool choice; with other tools
+// present, Claude may call those first and must respond through the
+// structured output tool.
+func applyResponseSchema(apiReq *apiRequest, cfg *model.GenerateConfig, thinking bool) {
+	if cfg == nil || cfg.ResponseSchema == nil {
+		return
+	}
+
+	schema := cfg.ResponseSchema
+	so := &structuredOutput{}
+	if t, _ := schema["type"].(string); t != "object" {
+		so.wrapped = true
+		schema = map[string]any{
+			"type":       "object",
+			"properties": map[string]any{structuredOutputValue: schema},
+			"required":   []string{structuredOutputValue},
+		}
+	}
+
+	description := "Respond with this tool. Its input is your final answer and must match the schema exactly."
+	if cfg.ResponseSchemaName != "" {
+		description += " Schema: " + cfg.ResponseSchemaName + "."
+	}
+	apiReq.Tools = append(apiReq.Tools, apiTool{
+		Name:        structuredOutputTool,
+		Description: description,
+		InputSchema: schema,
+	})
+
+	switch {
+	case thinking:
+		apiReq.ToolChoice = &toolChoice{Type: "auto"}
+	case len(apiReq.Tools) > 1:
+		apiReq.ToolChoice = &toolChoice{Type: "any"}
+	default:
+		apiReq.ToolChoice = &toolChoice{Type: "tool", Name: structuredOutputTool}
+	}
+	apiReq.structured = so
+}
+
+// text returns the response text for the structured output tool's input,
+// given as raw JSON.
+func (so *structuredOutput) text(input json.RawMessage) string {
+	if !so.wrapped {
+		return string(input)
+	}
+	var wrapper map[string]json.RawMessage
+	if err := json.Unmarshal(input, &wrapper); err != nil {
+		return string(input)
+	}
+	if value, ok := wrapper[structuredOutputValue]; ok {
+		return string(value)
+	}
+	return string(input)
+}
@@ -0,0 +1,223 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package anthropic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/model"
+)
+
+var personSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"name": map[string]any{"type": "string"},
+		"age":  map[string]any{"type": "integer"},
+	},
+	"required": []any{"name", "age"},
+}
+
+func newStructuredTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := New(Config{APIKey: "test", Model: "claude-sonnet-4-5", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return client
+}
+
+// conforms reports whether value matches the subset of JSON schema used
+// in these tests.
+func conforms(schema map[string]any, value any) bool {
+	switch schema["type"] {
+	case "object":
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return false
+		}
+		for _, r := range schema["required"].([]any) {
+			if _, ok := obj[r.(string)]; !ok {
+				return false
+			}
+		}
+		props, _ := schema["properties"].(map[string]any)
+		for k, v := range obj {
+			prop, ok := props[k].(map[string]any)
+			if !ok || !conforms(prop, v) {
+				return false
+			}
+		}
+		return true
+	case "array":
+		arr, ok := value.([]any)
+		if !ok {
+			return false
+		}
+		for _, v := range arr {
+			if !conforms(schema["items"].(map[string]any), v) {
+				return false
+			}
+		}
+		return true
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == float64(int64(n))
+	}
+	return false
+}
+
+func assertConforms(t *testing.T, schema map[string]any, text string) {
+	t.Helper()
+	var value any
+	if err := json.Unmarshal([]byte(text), &value); err != nil {
+		t.Fatalf("response %q is not JSON: %v", text, err)
+	}
+	if !conforms(schema, value) {
+		t.Errorf("response %s does not match schema", text)
+	}
+}
+
+func structuredRequest(schema map[string]any) *model.Request {
+	return &model.Request{
+		Messages: []*a2a.Message{
+			a2a.NewMessage(a2a.MessageRoleUser, &a2a.TextPart{Text: "Describe Ada"}),
+		},
+		Config: &model.GenerateConfig{ResponseSchema: schema, ResponseSchemaName: "person"},
+	}
+}
+
+func TestStructuredOutput(t *testing.T) {
+	var body map[string]any
+	client := newStructuredTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		fmt.Fprint(w, `{"content":[{"type":"tool_use","id":"toolu_1","name":"structured_output","input":{"name":"Ada","age":36}}],"stop_reason":"tool_use","usage":{"input_tokens":5,"output_tokens":9}}`)
+	})
+
+	var resp *model.Response
+	for r, err := range client.GenerateContent(context.Background(), structuredRequest(personSchema), false) {
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+		resp = r
+	}
+
+	tools, _ := body["tools"].([]any)
+	if len(tools) != 1 || tools[0].(map[string]any)["name"] != structuredOutputTool {
+		t.Errorf("tools = %v", body["tools"])
+	}
+	choice, _ := body["tool_choice"].(map[string]any)
+	if choice["type"] != "tool" || choice["name"] != structuredOutputTool {
+		t.Errorf("tool_choice = %v", body["tool_choice"])
+	}
+	if len(resp.ToolCalls) != 0 {
+		t.Errorf("tool calls = %v, want none", resp.ToolCalls)
+	}
+	if resp.FinishReason != model.FinishReasonStop {
+		t.Errorf("finish reason = %v", resp.FinishReason)
+	}
+	assertConforms(t, personSchema, resp.TextContent())
+}
+
+func TestStructuredOutputStreamWrapsArrays(t *testing.T) {
+	schema := map[string]any{"type": "array", "items": personSchema}
+	var body map[string]any
+	client := newStructuredTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, event := range []string{
+			`{"type":"message_start","message":{"usage":{"input_tokens":5}}}`,
+			`{"type":"content_block_start","index":0,"content_block":{"type":"tool_use","id":"toolu_1","name":"structured_output","input":{}}}`,
+			`{"type":"content_block_delta","index":0,"delta":{"type":"input_json_delta","partial_json":"{\"value\":[{\"name\":\"Ada\","}}`,
+			`{"type":"content_block_delta","index":0,"delta":{"type":"input_json_delta","partial_json":"\"age\":36}]}"}}`,
+			`{"type":"content_block_stop","index":0}`,
+			`{"type":"message_delta","delta":{"stop_reason":"tool_use"},"usage":{"output_tokens":9}}`,
+			`{"type":"message_stop"}`,
+		} {
+			fmt.Fprintf(w, "data: %s\n\n", event)
+		}
+	})
+
+	var final *model.Response
+	for r, err := range client.GenerateContent(context.Background(), structuredRequest(schema), true) {
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+		if !r.Partial {
+			final = r
+		}
+	}
+
+	tool := body["tools"].([]any)[0].(map[string]any)
+	input := tool["input_schema"].(map[string]any)
+	if input["type"] != "object" || input["properties"].(map[string]any)[structuredOutputValue] == nil {
+		t.Errorf("input_schema = %v", input)
+	}
+	if final == nil {
+		t.Fatal("no final response")
+	}
+	if len(final.ToolCalls) != 0 {
+		t.Errorf("tool calls = %v, want none", final.ToolCalls)
+	}
+	if final.FinishReason != model.FinishReasonStop {
+		t.Errorf("finish reason = %v", final.FinishReason)
+	}
+	assertConforms(t, schema, final.TextContent())
+}
+
+func TestStructuredOutputToolChoice(t *testing.T) {
+	tests := []struct {
+		name     string
+		tools    int
+		thinking bool
+		want     string
+	}{
+		{"forced", 0, false, "tool"},
+		{"with tools", 1, false, "any"},
+		{"with thinking", 0, true, "auto"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiReq := &apiRequest{}
+			for i := 0; i < tt.tools; i++ {
+				apiReq.Tools = append(apiReq.Tools, apiTool{Name: fmt.Sprintf("tool_%d", i)})
+			}
+			applyResponseSchema(apiReq, &model.GenerateConfig{ResponseSchema: personSchema}, tt.thinking)
+			if apiReq.ToolChoice == nil || apiReq.ToolChoice.Type != tt.want {
+				t.Errorf("tool_choice = %+v, want %s", apiReq.ToolChoice, tt.want)
+			}
+			if apiReq.structured == nil || apiReq.structured.wrapped {
+				t.Errorf("structured = %+v", apiReq.structured)
+			}
+		})
+	}
+}
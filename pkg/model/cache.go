@@ -0,0 +1,360 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/tool"
+)
+
+// defaultCacheMaxEntries bounds a MemoryResponseCache created with
+// maxEntries <= 0.
+const defaultCacheMaxEntries = 1000
+
+// ResponseCache stores encoded model responses by request key.
+type ResponseCache interface {
+	// Get returns the value stored under key. ok is false if there is none
+	// or it has expired.
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+
+	// Set stores value under key. A ttl of 0 never expires.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// CacheMetrics records response cache lookups.
+// *observability.Metrics satisfies this interface.
+type CacheMetrics interface {
+	RecordLLMCacheHit(model, provider string)
+	RecordLLMCacheMiss(model, provider string)
+}
+
+// CacheConfig configures a CachingLLM.
+type CacheConfig struct {
+	// Cache stores the responses. Default: a MemoryResponseCache with
+	// 1000 entries.
+	Cache ResponseCache
+
+	// TTL is how long a response is served from the cache. 0 never expires.
+	TTL time.Duration
+
+	// ReplayStream replays the cached streaming chunks to streaming
+	// requests. Otherwise they receive only the final response.
+	ReplayStream bool
+
+	// Scope is mixed into the cache keys. Set it to the settings of the
+	// wrapped model that requests don't carry, such as its temperature,
+	// so a shared cache doesn't serve responses generated with others.
+	Scope string
+
+	// Metrics records cache hits and misses (optional).
+	Metrics CacheMetrics
+}
+
+// CachingLLM serves repeated identical requests from a response cache.
+//
+// Requests are identical when they carry the same messages, tools,
+// instruction and generation config for the same model. Only successful,
+// complete responses are cached; errors and abandoned streams are not.
+// Streaming and non-streaming requests share cache entries.
+type CachingLLM struct {
+	llm LLM
+	cfg CacheConfig
+}
+
+// NewCachingLLM wraps llm so identical requests are answered from cfg.Cache.
+func NewCachingLLM(llm LLM, cfg CacheConfig) *CachingLLM {
+	if cfg.Cache == nil {
+		cfg.Cache = NewMemoryResponseCache(0)
+	}
+	return &CachingLLM{llm: llm, cfg: cfg}
+}
+
+// Name returns the wrapped model name.
+func (c *CachingLLM) Name() string {
+	return c.llm.Name()
+}
+
+// Provider returns the wrapped model provider.
+func (c *CachingLLM) Provider() Provider {
+	return c.llm.Provider()
+}
+
+// Close closes the wrapped model, and the cache if it has a Close method.
+// LLMs may share a cache, so its Close must be safe to call repeatedly.
+func (c *CachingLLM) Close() error {
+	err := c.llm.Close()
+	if closer, ok := c.cfg.Cache.(interface{ Close() error }); ok {
+		err = errors.Join(err, closer.Close())
+	}
+	return err
+}
+
+// GenerateContent returns the cached responses for req, or calls the
+// wrapped model and caches its responses.
+func (c *CachingLLM) GenerateContent(ctx context.Context, req *Request, stream bool) iter.Seq2[*Response, error] {
+	return func(yield func(*Response, error) bool) {
+		key, err := c.key(req)
+		if err != nil {
+			// Unhashable request: call the model directly
+			for resp, err := range c.llm.GenerateContent(ctx, req, stream) {
+				if !yield(resp, err) {
+					return
+				}
+			}
+			return
+		}
+
+		if entry, ok := c.lookup(ctx, key); ok {
+			c.record(true)
+			if stream && c.cfg.ReplayStream {
+				for _, chunk := range entry.Chunks {
+					if !yield(chunk.response(), nil) {
+						return
+					}
+				}
+			}
+			yield(entry.Final.response(), nil)
+			return
+		}
+		c.record(false)
+
+		var entry cacheEntry
+		for resp, err := range c.llm.GenerateContent(ctx, req, stream) {
+			if err != nil {
+				yield(resp, err)
+				return
+			}
+			if resp != nil {
+				if resp.Partial {
+					if c.cfg.ReplayStream {
+						entry.Chunks = append(entry.Chunks, newCachedResponse(resp))
+					}
+				} else if resp.ErrorCode == "" {
+					entry.Final = newCachedResponse(resp)
+				}
+			}
+			if !yield(resp, nil) {
+				return
+			}
+		}
+
+		if entry.Final != nil {
+			c.store(ctx, key, &entry)
+		}
+	}
+}
+
+// key hashes the model and the parts of req that determine its output.
+func (c *CachingLLM) key(req *Request) (string, error) {
+	reqKey, err := requestKey(req, false)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%s\x00%s", c.llm.Provider(), c.llm.Name(), c.cfg.Scope, reqKey)))
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// lookup returns the cache entry for key. Cache failures count as misses.
+func (c *CachingLLM) lookup(ctx context.Context, key string) (*cacheEntry, bool) {
+	data, ok, err := c.cfg.Cache.Get(ctx, key)
+	if err != nil {
+		slog.Warn("LLM response cache lookup failed", "model", c.llm.Name(), "error", err)
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Final == nil {
+		slog.Warn("Ignoring unreadable LLM response cache entry", "model", c.llm.Name(), "error", err)
+		return nil, false
+	}
+	return &entry, true
+}
+
+// store caches entry under key. The write outlives a cancelled request.
+func (c *CachingLLM) store(ctx context.Context, key string, entry *cacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		slog.Warn("Failed to encode LLM response for caching", "model", c.llm.Name(), "error", err)
+		return
+	}
+	if err := c.cfg.Cache.Set(context.WithoutCancel(ctx), key, data, c.cfg.TTL); err != nil {
+		slog.Warn("Failed to cache LLM response", "model", c.llm.Name(), "error", err)
+	}
+}
+
+func (c *CachingLLM) record(hit bool) {
+	m := c.cfg.Metrics
+	if m == nil {
+		return
+	}
+	if hit {
+		m.RecordLLMCacheHit(c.llm.Name(), string(c.llm.Provider()))
+	} else {
+		m.RecordLLMCacheMiss(c.llm.Name(), string(c.llm.Provider()))
+	}
+}
+
+// cacheEntry is the cached output of one request.
+type cacheEntry struct {
+	Chunks []*cachedResponse `json:"chunks,omitempty"`
+	Final  *cachedResponse   `json:"final"`
+}
+
+// cachedResponse is the JSON form of a Response. Content parts are
+// interfaces and need a2a.ContentParts to decode.
+type cachedResponse struct {
+	Parts        a2a.ContentParts `json:"parts,omitempty"`
+	Role         a2a.MessageRole  `json:"role,omitempty"`
+	HasContent   bool             `json:"has_content,omitempty"`
+	Partial      bool             `json:"partial,omitempty"`
+	TurnComplete bool             `json:"turn_complete,omitempty"`
+	ToolCalls    []tool.ToolCall  `json:"tool_calls,omitempty"`
+	Usage        *Usage           `json:"usage,omitempty"`
+	Thinking     *ThinkingBlock   `json:"thinking,omitempty"`
+	FinishReason FinishReason     `json:"finish_reason,omitempty"`
+}
+
+func newCachedResponse(resp *Response) *cachedResponse {
+	cached := &cachedResponse{
+		Partial:      resp.Partial,
+		TurnComplete: resp.TurnComplete,
+		ToolCalls:    resp.ToolCalls,
+		Usage:        resp.Usage,
+		Thinking:     resp.Thinking,
+		FinishReason: resp.FinishReason,
+	}
+	if resp.Content != nil {
+		cached.HasContent = true
+		cached.Parts = resp.Content.Parts
+		cached.Role = resp.Content.Role
+	}
+	return cached
+}
+
+func (r *cachedResponse) response() *Response {
+	resp := &Response{
+		Partial:      r.Partial,
+		TurnComplete: r.TurnComplete,
+		ToolCalls:    r.ToolCalls,
+		Usage:        r.Usage,
+		Thinking:     r.Thinking,
+		FinishReason: r.FinishReason,
+	}
+	if r.HasContent {
+		resp.Content = &Content{Parts: r.Parts, Role: r.Role}
+	}
+	return resp
+}
+
+// MemoryResponseCache is an in-memory ResponseCache that evicts the least
+// recently used entry once full.
+type MemoryResponseCache struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	order   *list.List // front is most recently used
+	entries map[string]*list.Element
+}
+
+type memoryCacheItem struct {
+	key       string
+	value     []byte
+	expiresAt time.Time // zero never expires
+}
+
+// NewMemoryResponseCache creates a cache holding up to maxEntries responses.
+// maxEntries <= 0 uses 1000.
+func NewMemoryResponseCache(maxEntries int) *MemoryResponseCache {
+	if maxEntries <= 0 {
+		maxEntries = defaultCacheMaxEntries
+	}
+	return &MemoryResponseCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns the value stored under key.
+func (m *MemoryResponseCache) Get(_ context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	elem, ok := m.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	item := elem.Value.(*memoryCacheItem)
+	if !item.expiresAt.IsZero() && time.Now().After(item.expiresAt) {
+		m.order.Remove(elem)
+		delete(m.entries, key)
+		return nil, false, nil
+	}
+	m.order.MoveToFront(elem)
+	return item.value, true, nil
+}
+
+// Set stores value under key, evicting the least recently used entry if
+// the cache is full.
+func (m *MemoryResponseCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item := &memoryCacheItem{key: key, value: value}
+	if ttl > 0 {
+		item.expiresAt = time.Now().Add(ttl)
+	}
+
+	if elem, ok := m.entries[key]; ok {
+		elem.Value = item
+		m.order.MoveToFront(elem)
+		return nil
+	}
+
+	m.entries[key] = m.order.PushFront(item)
+	for m.order.Len() > m.maxEntries {
+		oldest := m.order.Back()
+		m.order.Remove(oldest)
+		delete(m.entries, oldest.Value.(*memoryCacheItem).key)
+	}
+	return nil
+}
+
+// Len returns the number of cached entries, expired ones included.
+func (m *MemoryResponseCache) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
+
+// Ensure CachingLLM implements LLM.
+var _ LLM = (*CachingLLM)(nil)
@@ -0,0 +1,211 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// createLLMCacheTableSQL creates the llm_cache table. expires_at is in Unix
+// milliseconds; 0 never expires. MySQL needs LONGTEXT for large responses.
+const createLLMCacheTableSQL = `
+CREATE TABLE IF NOT EXISTS llm_cache (
+    cache_key VARCHAR(64) NOT NULL PRIMARY KEY,
+    value %s NOT NULL,
+    expires_at BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL
+)`
+
+// SQLResponseCache is a ResponseCache stored in a SQL database, shared by
+// every process using the database. It supports Postgres, MySQL, and SQLite.
+// Expired entries are deleted when read, and by StartPruning.
+type SQLResponseCache struct {
+	db      *sql.DB
+	dialect string
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewSQLResponseCache creates a SQL response cache and its table.
+// Supported dialects: "postgres", "mysql", "sqlite".
+func NewSQLResponseCache(db *sql.DB, dialect string) (*SQLResponseCache, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database connection is required")
+	}
+
+	valueType := "TEXT"
+	switch dialect {
+	case "postgres", "sqlite":
+	case "mysql":
+		valueType = "LONGTEXT"
+	default:
+		return nil, fmt.Errorf("unsupported dialect: %s (supported: postgres, mysql, sqlite)", dialect)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if _, err := db.ExecContext(ctx, fmt.Sprintf(createLLMCacheTableSQL, valueType)); err != nil {
+		return nil, fmt.Errorf("failed to create llm_cache table: %w", err)
+	}
+
+	return &SQLResponseCache{db: db, dialect: dialect}, nil
+}
+
+// Get returns the value stored under key.
+func (s *SQLResponseCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	query := `SELECT value, expires_at FROM llm_cache WHERE cache_key = ?`
+	if s.dialect == "postgres" {
+		query = `SELECT value, expires_at FROM llm_cache WHERE cache_key = $1`
+	}
+
+	var value string
+	var expiresAt int64
+	err := s.db.QueryRowContext(ctx, query, key).Scan(&value, &expiresAt)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to query llm_cache: %w", err)
+	}
+
+	if expiresAt != 0 && time.Now().UnixMilli() > expiresAt {
+		deleteQuery := `DELETE FROM llm_cache WHERE cache_key = ? AND expires_at = ?`
+		if s.dialect == "postgres" {
+			deleteQuery = `DELETE FROM llm_cache WHERE cache_key = $1 AND expires_at = $2`
+		}
+		if _, err := s.db.ExecContext(ctx, deleteQuery, key, expiresAt); err != nil {
+			return nil, false, fmt.Errorf("failed to delete expired llm_cache entry: %w", err)
+		}
+		return nil, false, nil
+	}
+
+	return []byte(value), true, nil
+}
+
+// Set stores value under key.
+func (s *SQLResponseCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	now := time.Now()
+	var expiresAt int64
+	if ttl > 0 {
+		expiresAt = now.Add(ttl).UnixMilli()
+	}
+
+	var query string
+	switch s.dialect {
+	case "postgres":
+		query = `
+			INSERT INTO llm_cache (cache_key, value, expires_at, created_at)
+			VALUES ($1, $2, $3, $4)
+			ON CONFLICT (cache_key)
+			DO UPDATE SET value = EXCLUDED.value, expires_at = EXCLUDED.expires_at, created_at = EXCLUDED.created_at
+		`
+	case "mysql":
+		query = `
+			INSERT INTO llm_cache (cache_key, value, expires_at, created_at)
+			VALUES (?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE value = VALUES(value), expires_at = VALUES(expires_at), created_at = VALUES(created_at)
+		`
+	default:
+		// SQLite
+		query = `
+			INSERT OR REPLACE INTO llm_cache (cache_key, value, expires_at, created_at)
+			VALUES (?, ?, ?, ?)
+		`
+	}
+
+	if _, err := s.db.ExecContext(ctx, query, key, string(value), expiresAt, now); err != nil {
+		return fmt.Errorf("failed to store llm_cache entry: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired deletes the entries that expired before the given time and
+// returns how many were deleted.
+func (s *SQLResponseCache) DeleteExpired(ctx context.Context, before time.Time) (int, error) {
+	query := `DELETE FROM llm_cache WHERE expires_at <> 0 AND expires_at < ?`
+	if s.dialect == "postgres" {
+		query = `DELETE FROM llm_cache WHERE expires_at <> 0 AND expires_at < $1`
+	}
+	result, err := s.db.ExecContext(ctx, query, before.UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired llm_cache entries: %w", err)
+	}
+	deleted, _ := result.RowsAffected()
+	return int(deleted), nil
+}
+
+// StartPruning deletes expired entries every interval in the background,
+// until Close. It is a no-op if pruning is already running.
+func (s *SQLResponseCache) StartPruning(interval time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil || interval <= 0 {
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.prune(interval, s.stop, s.done)
+}
+
+// Close stops pruning. It does not close the database, which may be shared.
+func (s *SQLResponseCache) Close() error {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+	return nil
+}
+
+func (s *SQLResponseCache) prune(interval time.Duration, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			deleted, err := s.DeleteExpired(ctx, time.Now())
+			if err != nil && ctx.Err() == nil {
+				slog.Warn("Failed to prune LLM cache", "error", err)
+			} else if deleted > 0 {
+				slog.Debug("Pruned LLM cache", "deleted", deleted)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
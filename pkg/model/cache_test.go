@@ -0,0 +1,265 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"iter"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/kadirpekel/hector/pkg/tool"
+)
+
+// countingLLM streams two chunks and a final response with a tool call.
+type countingLLM struct {
+	calls atomic.Int32
+	fail  bool
+}
+
+func (c *countingLLM) Name() string       { return "counting" }
+func (c *countingLLM) Provider() Provider { return ProviderOpenAI }
+func (c *countingLLM) Close() error       { return nil }
+
+func (c *countingLLM) GenerateContent(ctx context.Context, req *Request, stream bool) iter.Seq2[*Response, error] {
+	return func(yield func(*Response, error) bool) {
+		c.calls.Add(1)
+		if c.fail {
+			yield(nil, errors.New("rate limited"))
+			return
+		}
+		if stream {
+			for _, text := range []string{"Hel", "lo"} {
+				if !yield(&Response{Content: &Content{Parts: []a2a.Part{a2a.TextPart{Text: text}}}, Partial: true}, nil) {
+					return
+				}
+			}
+		}
+		yield(&Response{
+			Content:      &Content{Parts: []a2a.Part{a2a.TextPart{Text: "Hello"}}, Role: a2a.MessageRoleAgent},
+			ToolCalls:    []tool.ToolCall{{ID: "call_1", Name: "search", Args: map[string]any{"q": "go"}}},
+			Usage:        &Usage{PromptTokens: 10, CompletionTokens: 3, TotalTokens: 13},
+			FinishReason: FinishReasonToolCalls,
+		}, nil)
+	}
+}
+
+type cacheCounter struct {
+	hits, misses int
+}
+
+func (c *cacheCounter) RecordLLMCacheHit(model, provider string)  { c.hits++ }
+func (c *cacheCounter) RecordLLMCacheMiss(model, provider string) { c.misses++ }
+
+func cacheRequest(text string) *Request {
+	return &Request{Messages: []*a2a.Message{a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: text})}}
+}
+
+func collect(t *testing.T, llm LLM, req *Request, stream bool) []*Response {
+	t.Helper()
+	var out []*Response
+	for resp, err := range llm.GenerateContent(context.Background(), req, stream) {
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+		out = append(out, resp)
+	}
+	return out
+}
+
+func TestCachingLLMServesRepeatedRequests(t *testing.T) {
+	inner := &countingLLM{}
+	metrics := &cacheCounter{}
+	llm := NewCachingLLM(inner, CacheConfig{Metrics: metrics})
+
+	collect(t, llm, cacheRequest("Hi"), false)
+	got := collect(t, llm, cacheRequest("Hi"), false)
+
+	if calls := inner.calls.Load(); calls != 1 {
+		t.Errorf("model calls = %d, want 1", calls)
+	}
+	if len(got) != 1 {
+		t.Fatalf("responses = %d, want 1", len(got))
+	}
+	resp := got[0]
+	if resp.TextContent() != "Hello" || resp.Content.Role != a2a.MessageRoleAgent {
+		t.Errorf("content = %+v", resp.Content)
+	}
+	if len(resp.ToolCalls) != 1 || resp.ToolCalls[0].Args["q"] != "go" {
+		t.Errorf("tool calls = %+v", resp.ToolCalls)
+	}
+	if resp.Usage == nil || resp.Usage.TotalTokens != 13 || resp.FinishReason != FinishReasonToolCalls {
+		t.Errorf("usage = %+v, finish reason = %q", resp.Usage, resp.FinishReason)
+	}
+	if metrics.hits != 1 || metrics.misses != 1 {
+		t.Errorf("hits = %d, misses = %d", metrics.hits, metrics.misses)
+	}
+
+	collect(t, llm, cacheRequest("Bye"), false)
+	if calls := inner.calls.Load(); calls != 2 {
+		t.Errorf("model calls after new request = %d, want 2", calls)
+	}
+}
+
+func TestCachingLLMReplaysStream(t *testing.T) {
+	inner := &countingLLM{}
+	llm := NewCachingLLM(inner, CacheConfig{ReplayStream: true})
+
+	first := collect(t, llm, cacheRequest("Hi"), true)
+	replayed := collect(t, llm, cacheRequest("Hi"), true)
+	if calls := inner.calls.Load(); calls != 1 {
+		t.Errorf("model calls = %d, want 1", calls)
+	}
+	if len(replayed) != len(first) {
+		t.Fatalf("replayed %d responses, want %d", len(replayed), len(first))
+	}
+	for i := range first {
+		if replayed[i].Partial != first[i].Partial || replayed[i].TextContent() != first[i].TextContent() {
+			t.Errorf("response %d = %+v, want %+v", i, replayed[i], first[i])
+		}
+	}
+
+	// Without replay, streaming requests get the final response only
+	llm = NewCachingLLM(inner, CacheConfig{})
+	collect(t, llm, cacheRequest("Hi"), true)
+	got := collect(t, llm, cacheRequest("Hi"), true)
+	if len(got) != 1 || got[0].Partial {
+		t.Errorf("responses = %+v, want final only", got)
+	}
+}
+
+func TestCachingLLMSkipsFailures(t *testing.T) {
+	inner := &countingLLM{fail: true}
+	llm := NewCachingLLM(inner, CacheConfig{})
+
+	for range 2 {
+		for _, err := range llm.GenerateContent(context.Background(), cacheRequest("Hi"), false) {
+			if err == nil {
+				t.Fatal("expected error")
+			}
+		}
+	}
+	if calls := inner.calls.Load(); calls != 2 {
+		t.Errorf("model calls = %d, want 2", calls)
+	}
+}
+
+func TestCachingLLMScopesKeys(t *testing.T) {
+	inner := &countingLLM{}
+	cache := NewMemoryResponseCache(0)
+
+	collect(t, NewCachingLLM(inner, CacheConfig{Cache: cache, Scope: "temperature=0.2"}), cacheRequest("Hi"), false)
+	collect(t, NewCachingLLM(inner, CacheConfig{Cache: cache, Scope: "temperature=0.9"}), cacheRequest("Hi"), false)
+
+	if calls := inner.calls.Load(); calls != 2 {
+		t.Errorf("model calls = %d, want 2", calls)
+	}
+}
+
+func TestMemoryResponseCacheEvictsAndExpires(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryResponseCache(2)
+
+	cache.Set(ctx, "a", []byte("1"), 0)
+	cache.Set(ctx, "b", []byte("2"), 0)
+	cache.Get(ctx, "a") // b is now least recently used
+	cache.Set(ctx, "c", []byte("3"), 0)
+
+	if _, ok, _ := cache.Get(ctx, "b"); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+	if _, ok, _ := cache.Get(ctx, "a"); !ok {
+		t.Error("recently used entry was evicted")
+	}
+
+	cache.Set(ctx, "d", []byte("4"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, ok, _ := cache.Get(ctx, "d"); ok {
+		t.Error("expired entry was returned")
+	}
+	if cache.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", cache.Len())
+	}
+}
+
+func TestSQLResponseCache(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "cache.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	cache, err := NewSQLResponseCache(db, "sqlite")
+	if err != nil {
+		t.Fatalf("NewSQLResponseCache() error = %v", err)
+	}
+
+	inner := &countingLLM{}
+	collect(t, NewCachingLLM(inner, CacheConfig{Cache: cache, TTL: time.Hour}), cacheRequest("Hi"), false)
+
+	// A second process sharing the database is served from the cache
+	got := collect(t, NewCachingLLM(inner, CacheConfig{Cache: cache, TTL: time.Hour}), cacheRequest("Hi"), false)
+	if calls := inner.calls.Load(); calls != 1 {
+		t.Errorf("model calls = %d, want 1", calls)
+	}
+	if len(got) != 1 || got[0].TextContent() != "Hello" {
+		t.Errorf("responses = %+v", got)
+	}
+
+	ctx := context.Background()
+	if err := cache.Set(ctx, "old", []byte("x"), time.Nanosecond); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	time.Sleep(2 * time.Millisecond)
+	if _, ok, err := cache.Get(ctx, "old"); ok || err != nil {
+		t.Errorf("expired Get() = %v, %v", ok, err)
+	}
+}
+
+func TestSQLResponseCachePrunesExpiredEntries(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "cache.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	cache, err := NewSQLResponseCache(db, "sqlite")
+	if err != nil {
+		t.Fatalf("NewSQLResponseCache() error = %v", err)
+	}
+	ctx := context.Background()
+	for key, ttl := range map[string]time.Duration{"expired": time.Nanosecond, "live": time.Hour, "forever": 0} {
+		if err := cache.Set(ctx, key, []byte("x"), ttl); err != nil {
+			t.Fatalf("Set(%q) error = %v", key, err)
+		}
+	}
+	time.Sleep(2 * time.Millisecond)
+
+	// Expired entries are deleted without being read again
+	cache.StartPruning(10 * time.Millisecond)
+	defer cache.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var n int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM llm_cache`).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		if n == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("rows = %d, want 2 after pruning", n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	for _, key := range []string{"live", "forever"} {
+		if _, ok, err := cache.Get(ctx, key); !ok || err != nil {
+			t.Errorf("Get(%q) = %v, %v", key, ok, err)
+		}
+	}
+}
@@ -0,0 +1,785 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cohere provides a Cohere LLM implementation.
+//
+// This implementation is aligned with ADK-Go's model architecture:
+//   - Uses Cohere's Chat API (/v1/chat)
+//   - Unified GenerateContent method with stream boolean
+//   - Returns iter.Seq2[*Response, error]
+//   - Uses StreamingAggregator for streaming with Partial flag
+//   - Tool calls and results translated to Cohere's tools/tool_results format
+//
+// See: https://docs.cohere.com/v1/reference/chat
+package cohere
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/httpclient"
+	"github.com/kadirpekel/hector/pkg/model"
+	"github.com/kadirpekel/hector/pkg/tool"
+)
+
+const (
+	defaultBaseURL        = "https://api.cohere.com"
+	defaultModel          = "command-r-plus"
+	defaultTimeout        = 120 * time.Second
+	defaultConnectTimeout = 10 * time.Second
+
+	// roleTool is the chat history role of tool results.
+	roleTool = "TOOL"
+)
+
+// Config configures the Cohere client.
+type Config struct {
+	// APIKey for the Cohere API (required).
+	APIKey string
+
+	// Model is the model name (e.g., "command-r-plus", "command-r").
+	Model string
+
+	// MaxTokens limits the response length. 0 uses the model's default.
+	MaxTokens int
+
+	// Temperature controls randomness (0-1).
+	Temperature *float64
+
+	// BaseURL overrides the API endpoint (default: https://api.cohere.com).
+	BaseURL string
+
+	// MaxRetries for HTTP requests with retry/backoff. Default: 3.
+	MaxRetries int
+
+	// MaxToolOutputLength limits the length of tool outputs.
+	MaxToolOutputLength int
+
+	// Timeout bounds each request from connect to the last byte.
+	// Default: 120s for non-streaming requests; streams are unbounded.
+	Timeout time.Duration
+
+	// ConnectTimeout bounds dialing and the TLS handshake. Default: 10s.
+	ConnectTimeout time.Duration
+
+	// Roles overrides the role names sent for each message type.
+	// Default: USER, CHATBOT, SYSTEM.
+	Roles model.RoleMapping
+
+	// SystemMessages controls how mid-conversation system messages are sent.
+	// Default: model.SystemMessagesMerge.
+	SystemMessages model.SystemMessageMode
+}
+
+// defaultRoles are the Chat API role names.
+var defaultRoles = model.RoleMapping{User: "USER", Agent: "CHATBOT", System: "SYSTEM"}
+
+// Client is a Cohere LLM implementation.
+// Implements model.LLM interface aligned with ADK-Go.
+type Client struct {
+	httpClient          *httpclient.Client
+	timeout             time.Duration
+	apiKey              string
+	baseURL             string
+	model               string
+	maxTokens           int
+	maxToolOutputLength int
+	temperature         *float64
+	roles               model.RoleMapping
+	systemMessages      model.SystemMessageMode
+}
+
+// New creates a new Cohere client.
+func New(cfg Config) (*Client, error) {
+	if cfg.APIKey == "" {
+		return nil, fmt.Errorf("API key is required")
+	}
+
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = defaultBaseURL
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	modelName := cfg.Model
+	if modelName == "" {
+		modelName = defaultModel
+	}
+
+	connectTimeout := cfg.ConnectTimeout
+	if connectTimeout == 0 {
+		connectTimeout = defaultConnectTimeout
+	}
+
+	maxRetries := cfg.MaxRetries
+	if maxRetries == 0 {
+		maxRetries = 3
+	}
+
+	systemMessages := cfg.SystemMessages
+	if systemMessages == "" {
+		systemMessages = model.SystemMessagesMerge
+	}
+
+	hc := httpclient.New(
+		httpclient.WithHTTPClient(&http.Client{}),
+		httpclient.WithConnectTimeout(connectTimeout),
+		httpclient.WithMaxRetries(maxRetries),
+		httpclient.WithBaseDelay(2*time.Second),
+	)
+
+	return &Client{
+		httpClient:          hc,
+		timeout:             cfg.Timeout,
+		apiKey:              cfg.APIKey,
+		baseURL:             baseURL,
+		model:               modelName,
+		maxTokens:           cfg.MaxTokens,
+		maxToolOutputLength: cfg.MaxToolOutputLength,
+		temperature:         cfg.Temperature,
+		roles:               cfg.Roles.WithDefaults(defaultRoles),
+		systemMessages:      systemMessages,
+	}, nil
+}
+
+// Name returns the model identifier.
+func (c *Client) Name() string {
+	return c.model
+}
+
+// Provider returns the provider type.
+func (c *Client) Provider() model.Provider {
+	return model.ProviderCohere
+}
+
+// GenerateContent produces responses for the given request.
+// This is the ADK-Go aligned interface.
+//
+// When stream=false:
+//   - Yields exactly one Response with complete content, Partial=false
+//
+// When stream=true:
+//   - Yields multiple partial Responses (Partial=true) for real-time UI updates
+//   - Finally yields aggregated Response (Partial=false) for session persistence
+func (c *Client) GenerateContent(ctx context.Context, req *model.Request, stream bool) iter.Seq2[*model.Response, error] {
+	return func(yield func(*model.Response, error) bool) {
+		ctx, cancel := model.WithRequestTimeout(ctx, c.timeout, defaultTimeout, stream)
+		defer cancel()
+
+		if stream {
+			for resp, err := range c.generateStream(ctx, req) {
+				if !yield(resp, err) {
+					return
+				}
+			}
+			return
+		}
+
+		resp, err := c.generate(ctx, req)
+		yield(resp, err)
+	}
+}
+
+// Close releases resources.
+func (c *Client) Close() error {
+	return nil
+}
+
+// generate performs non-streaming generation.
+func (c *Client) generate(ctx context.Context, req *model.Request) (*model.Response, error) {
+	resp, err := c.send(ctx, c.buildRequest(req, false))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var apiResp chatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return c.parseResponse(&apiResp), nil
+}
+
+// generateStream performs streaming generation with aggregator.
+//
+// The Chat API streams one JSON event per line. text-generation events carry
+// text deltas, tool-calls-chunk events carry tool call deltas (completed by a
+// tool-calls-generation event), and stream-end carries the finish reason and
+// the full response including usage.
+func (c *Client) generateStream(ctx context.Context, req *model.Request) iter.Seq2[*model.Response, error] {
+	aggregator := model.NewStreamingAggregator()
+
+	return func(yield func(*model.Response, error) bool) {
+		resp, err := c.send(ctx, c.buildRequest(req, true))
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer resp.Body.Close()
+
+		reader := bufio.NewReader(resp.Body)
+		state := &cohereStreamState{}
+
+		for {
+			line, err := reader.ReadBytes('\n')
+			if err != nil && err != io.EOF {
+				yield(nil, fmt.Errorf("stream read error: %w", err))
+				return
+			}
+			eof := err == io.EOF
+
+			// Tolerate SSE framing from proxies
+			line = bytes.TrimSpace(line)
+			line = bytes.TrimSpace(bytes.TrimPrefix(line, []byte("data:")))
+
+			if len(line) > 0 {
+				var event streamEvent
+				if json.Unmarshal(line, &event) == nil {
+					for resp, err := range c.processStreamEvent(&event, aggregator, state) {
+						if !yield(resp, err) {
+							return
+						}
+					}
+				}
+			}
+
+			if eof || state.done {
+				break
+			}
+		}
+
+		// Emit tool calls once complete
+		toolCalls := state.finalToolCalls()
+		for _, tc := range toolCalls {
+			for resp, err := range aggregator.ProcessToolCall(tc) {
+				if !yield(resp, err) {
+					return
+				}
+			}
+		}
+		if len(toolCalls) > 0 {
+			aggregator.SetFinishReason(model.FinishReasonToolCalls)
+		}
+
+		if final := aggregator.Close(); final != nil {
+			yield(final, nil)
+		}
+	}
+}
+
+// cohereStreamState holds state accumulated during streaming.
+type cohereStreamState struct {
+	// chunks accumulates tool-calls-chunk deltas by index
+	chunks []*toolCallDelta
+
+	// toolCalls holds the complete tool calls once generated
+	toolCalls []*toolCall
+
+	done bool
+}
+
+// finalToolCalls returns the stream's tool calls, preferring the complete
+// tool-calls-generation event over accumulated deltas.
+func (s *cohereStreamState) finalToolCalls() []tool.ToolCall {
+	calls := s.toolCalls
+	if calls == nil {
+		for _, d := range s.chunks {
+			if d == nil || d.Name == "" {
+				continue
+			}
+			var params map[string]any
+			if d.Parameters != "" {
+				_ = json.Unmarshal([]byte(d.Parameters), &params)
+			}
+			calls = append(calls, &toolCall{Name: d.Name, Parameters: params})
+		}
+	}
+	return toToolCalls(calls)
+}
+
+// processStreamEvent processes a single stream event through the aggregator.
+func (c *Client) processStreamEvent(event *streamEvent, agg *model.StreamingAggregator, state *cohereStreamState) iter.Seq2[*model.Response, error] {
+	return func(yield func(*model.Response, error) bool) {
+		switch event.EventType {
+		case "text-generation":
+			if event.Text == "" {
+				return
+			}
+			for resp, err := range agg.ProcessTextDelta(event.Text) {
+				if !yield(resp, err) {
+					return
+				}
+			}
+
+		case "tool-calls-chunk":
+			d := event.ToolCallDelta
+			if d == nil || d.Index == nil {
+				return
+			}
+			for len(state.chunks) <= *d.Index {
+				state.chunks = append(state.chunks, &toolCallDelta{})
+			}
+			acc := state.chunks[*d.Index]
+			if d.Name != "" {
+				acc.Name = d.Name
+			}
+			acc.Parameters += d.Parameters
+
+		case "tool-calls-generation":
+			state.toolCalls = event.ToolCalls
+			if state.toolCalls == nil {
+				state.toolCalls = []*toolCall{}
+			}
+
+		case "stream-end":
+			state.done = true
+			agg.SetFinishReason(mapFinishReason(event.FinishReason))
+			if event.Response != nil {
+				if state.toolCalls == nil && event.Response.ToolCalls != nil {
+					state.toolCalls = event.Response.ToolCalls
+				}
+				if usage := event.Response.Meta.usage(); usage != nil {
+					agg.SetUsage(usage)
+				}
+			}
+		}
+	}
+}
+
+// send posts a chat request and returns the successful response.
+func (c *Client) send(ctx context.Context, apiReq *chatRequest) (*http.Response, error) {
+	body, err := json.Marshal(apiReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/v1/chat", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+	return resp, nil
+}
+
+// buildRequest creates an API request from model.Request.
+//
+// The Chat API takes the latest user message separately from the history.
+// When the conversation ends with tool results, they are sent as the
+// request's tool_results with an empty message instead.
+func (c *Client) buildRequest(req *model.Request, stream bool) *chatRequest {
+	req = model.ApplySystemMessages(req, c.systemMessages)
+
+	apiReq := &chatRequest{
+		Model:    c.model,
+		Preamble: req.SystemInstruction,
+		Stream:   stream,
+	}
+
+	if c.temperature != nil {
+		apiReq.Temperature = c.temperature
+	} else if req.Config != nil && req.Config.Temperature != nil {
+		apiReq.Temperature = req.Config.Temperature
+	}
+
+	if c.maxTokens > 0 {
+		apiReq.MaxTokens = c.maxTokens
+	} else if req.Config != nil && req.Config.MaxTokens != nil {
+		apiReq.MaxTokens = *req.Config.MaxTokens
+	}
+
+	if req.Config != nil {
+		apiReq.P = req.Config.TopP
+		apiReq.K = req.Config.TopK
+		apiReq.StopSequences = req.Config.StopSequences
+
+		// Handle structured output
+		if req.Config.ResponseSchema != nil {
+			apiReq.ResponseFormat = &responseFormat{Type: "json_object", Schema: req.Config.ResponseSchema}
+		} else if req.Config.ResponseMIMEType == "application/json" {
+			apiReq.ResponseFormat = &responseFormat{Type: "json_object"}
+		}
+	}
+
+	history := c.convertMessages(req.Messages)
+
+	// Lift trailing tool results, or else the last user message
+	i := len(history)
+	for i > 0 && history[i-1].Role == roleTool {
+		i--
+	}
+	if i < len(history) {
+		for _, m := range history[i:] {
+			apiReq.ToolResults = append(apiReq.ToolResults, m.ToolResults...)
+		}
+		history = history[:i]
+	} else if n := len(history); n > 0 && history[n-1].Role == c.roles.User {
+		apiReq.Message = history[n-1].Message
+		history = history[:n-1]
+	}
+	apiReq.ChatHistory = history
+
+	if len(req.Tools) > 0 {
+		apiReq.Tools = convertTools(req.Tools)
+	}
+
+	return apiReq
+}
+
+// convertMessages converts the conversation to Chat API history entries.
+func (c *Client) convertMessages(messages []*a2a.Message) []*chatMessage {
+	var history []*chatMessage
+
+	// Cohere identifies tool calls by name and parameters, not IDs, so
+	// results are matched back to the calls they answer.
+	calls := make(map[string]*toolCall)
+
+	for _, msg := range messages {
+		if msg == nil {
+			continue
+		}
+
+		entry := &chatMessage{Role: c.roles.Role(msg)}
+		var textParts []string
+		var results []*toolResult
+
+		for _, part := range msg.Parts {
+			switch p := part.(type) {
+			case a2a.TextPart:
+				if p.Text != "" {
+					textParts = append(textParts, p.Text)
+				}
+			case *a2a.TextPart:
+				if p.Text != "" {
+					textParts = append(textParts, p.Text)
+				}
+
+			case a2a.DataPart:
+				dataType, _ := p.Data["type"].(string)
+				switch dataType {
+				case "tool_use":
+					name, _ := p.Data["name"].(string)
+					if name == "" {
+						continue
+					}
+					args, _ := p.Data["arguments"].(map[string]any)
+					call := &toolCall{Name: name, Parameters: args}
+					if id, ok := p.Data["id"].(string); ok {
+						calls[id] = call
+					}
+					entry.ToolCalls = append(entry.ToolCalls, call)
+
+				case "tool_result":
+					id, _ := p.Data["tool_call_id"].(string)
+					call, ok := calls[id]
+					if !ok {
+						name, _ := p.Data["tool_name"].(string)
+						call = &toolCall{Name: name}
+					}
+					content, _ := p.Data["content"].(string)
+					// Safety Truncation
+					if c.maxToolOutputLength > 0 && len(content) > c.maxToolOutputLength {
+						content = content[:c.maxToolOutputLength] + fmt.Sprintf("\n... [TRUNCATED by client: output length %d exceeded safety limit]", len(content))
+					}
+					results = append(results, &toolResult{
+						Call:    call,
+						Outputs: []map[string]any{{"output": content}},
+					})
+				}
+			}
+		}
+
+		if len(results) > 0 {
+			history = append(history, &chatMessage{Role: roleTool, ToolResults: results})
+		}
+
+		entry.Message = strings.Join(textParts, "\n")
+		if entry.Message == "" && len(entry.ToolCalls) == 0 {
+			continue
+		}
+		history = append(history, entry)
+	}
+
+	return history
+}
+
+// convertTools converts tool definitions to Cohere format.
+func convertTools(tools []tool.Definition) []*apiTool {
+	result := make([]*apiTool, len(tools))
+	for i, t := range tools {
+		result[i] = &apiTool{
+			Name:                 t.Name,
+			Description:          t.Description,
+			ParameterDefinitions: parameterDefinitions(t.Parameters),
+		}
+	}
+	return result
+}
+
+// parameterDefinitions converts a JSON schema object to Cohere's flat
+// parameter definitions.
+func parameterDefinitions(schema map[string]any) map[string]*parameterDefinition {
+	props, _ := schema["properties"].(map[string]any)
+	if len(props) == 0 {
+		return nil
+	}
+
+	required := make(map[string]bool)
+	switch r := schema["required"].(type) {
+	case []string:
+		for _, name := range r {
+			required[name] = true
+		}
+	case []any:
+		for _, name := range r {
+			if s, ok := name.(string); ok {
+				required[s] = true
+			}
+		}
+	}
+
+	defs := make(map[string]*parameterDefinition, len(props))
+	for name, raw := range props {
+		prop, _ := raw.(map[string]any)
+		description, _ := prop["description"].(string)
+		defs[name] = &parameterDefinition{
+			Description: description,
+			Type:        parameterType(prop),
+			Required:    required[name],
+		}
+	}
+	return defs
+}
+
+// parameterType maps a JSON schema type to Cohere's Python-style type names.
+func parameterType(prop map[string]any) string {
+	t, _ := prop["type"].(string)
+	switch t {
+	case "string":
+		return "str"
+	case "integer":
+		return "int"
+	case "number":
+		return "float"
+	case "boolean":
+		return "bool"
+	case "array":
+		items, _ := prop["items"].(map[string]any)
+		if items == nil {
+			return "list"
+		}
+		return "List[" + parameterType(items) + "]"
+	case "object":
+		return "dict"
+	default:
+		return "str"
+	}
+}
+
+// parseResponse converts API response to model.Response.
+func (c *Client) parseResponse(resp *chatResponse) *model.Response {
+	result := &model.Response{
+		Partial:      false,
+		TurnComplete: true,
+		FinishReason: mapFinishReason(resp.FinishReason),
+		Usage:        resp.Meta.usage(),
+	}
+
+	var parts []a2a.Part
+	if resp.Text != "" {
+		parts = append(parts, a2a.TextPart{Text: resp.Text})
+	}
+
+	for _, tc := range toToolCalls(resp.ToolCalls) {
+		result.ToolCalls = append(result.ToolCalls, tc)
+		parts = append(parts, a2a.DataPart{
+			Data: map[string]any{
+				"type":      "tool_use",
+				"id":        tc.ID,
+				"name":      tc.Name,
+				"arguments": tc.Args,
+			},
+		})
+	}
+	if len(result.ToolCalls) > 0 {
+		result.FinishReason = model.FinishReasonToolCalls
+	}
+
+	if len(parts) > 0 {
+		result.Content = &model.Content{
+			Parts: parts,
+			Role:  a2a.MessageRoleAgent,
+		}
+	}
+
+	return result
+}
+
+// toToolCalls converts Cohere tool calls, assigning IDs by position.
+func toToolCalls(calls []*toolCall) []tool.ToolCall {
+	var result []tool.ToolCall
+	for i, tc := range calls {
+		if tc == nil || tc.Name == "" {
+			continue
+		}
+		args := tc.Parameters
+		if args == nil {
+			args = make(map[string]any)
+		}
+		result = append(result, tool.ToolCall{
+			ID:   fmt.Sprintf("call_%d", i),
+			Name: tc.Name,
+			Args: args,
+		})
+	}
+	return result
+}
+
+// mapFinishReason maps Cohere finish reasons.
+func mapFinishReason(reason string) model.FinishReason {
+	switch reason {
+	case "MAX_TOKENS":
+		return model.FinishReasonLength
+	case "ERROR_TOXIC":
+		return model.FinishReasonContent
+	case "ERROR", "ERROR_LIMIT":
+		return model.FinishReasonError
+	default:
+		return model.FinishReasonStop
+	}
+}
+
+// API types
+
+type chatRequest struct {
+	Model          string          `json:"model"`
+	Message        string          `json:"message"`
+	ChatHistory    []*chatMessage  `json:"chat_history,omitempty"`
+	Preamble       string          `json:"preamble,omitempty"`
+	Tools          []*apiTool      `json:"tools,omitempty"`
+	ToolResults    []*toolResult   `json:"tool_results,omitempty"`
+	Temperature    *float64        `json:"temperature,omitempty"`
+	MaxTokens      int             `json:"max_tokens,omitempty"`
+	P              *float64        `json:"p,omitempty"`
+	K              *int            `json:"k,omitempty"`
+	StopSequences  []string        `json:"stop_sequences,omitempty"`
+	ResponseFormat *responseFormat `json:"response_format,omitempty"`
+	Stream         bool            `json:"stream"`
+}
+
+type chatMessage struct {
+	Role        string        `json:"role"`
+	Message     string        `json:"message,omitempty"`
+	ToolCalls   []*toolCall   `json:"tool_calls,omitempty"`
+	ToolResults []*toolResult `json:"tool_results,omitempty"`
+}
+
+type toolCall struct {
+	Name       string         `json:"name"`
+	Parameters map[string]any `json:"parameters"`
+}
+
+type toolResult struct {
+	Call    *toolCall        `json:"call"`
+	Outputs []map[string]any `json:"outputs"`
+}
+
+type apiTool struct {
+	Name                 string                          `json:"name"`
+	Description          string                          `json:"description"`
+	ParameterDefinitions map[string]*parameterDefinition `json:"parameter_definitions,omitempty"`
+}
+
+type parameterDefinition struct {
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+type responseFormat struct {
+	Type   string         `json:"type"`
+	Schema map[string]any `json:"schema,omitempty"`
+}
+
+type chatResponse struct {
+	Text         string      `json:"text"`
+	GenerationID string      `json:"generation_id,omitempty"`
+	FinishReason string      `json:"finish_reason,omitempty"`
+	ToolCalls    []*toolCall `json:"tool_calls,omitempty"`
+	Meta         *apiMeta    `json:"meta,omitempty"`
+}
+
+type apiMeta struct {
+	BilledUnits *billedUnits `json:"billed_units,omitempty"`
+}
+
+type billedUnits struct {
+	InputTokens  float64 `json:"input_tokens"`
+	OutputTokens float64 `json:"output_tokens"`
+}
+
+// usage returns the billed token usage, or nil when not reported.
+func (m *apiMeta) usage() *model.Usage {
+	if m == nil || m.BilledUnits == nil {
+		return nil
+	}
+	input, output := int(m.BilledUnits.InputTokens), int(m.BilledUnits.OutputTokens)
+	return &model.Usage{
+		PromptTokens:     input,
+		CompletionTokens: output,
+		TotalTokens:      input + output,
+	}
+}
+
+type streamEvent struct {
+	EventType     string         `json:"event_type"`
+	Text          string         `json:"text,omitempty"`
+	ToolCallDelta *toolCallDelta `json:"tool_call_delta,omitempty"`
+	ToolCalls     []*toolCall    `json:"tool_calls,omitempty"`
+	FinishReason  string         `json:"finish_reason,omitempty"`
+	Response      *chatResponse  `json:"response,omitempty"`
+}
+
+type toolCallDelta struct {
+	Index      *int   `json:"index,omitempty"`
+	Name       string `json:"name,omitempty"`
+	Parameters string `json:"parameters,omitempty"`
+}
+
+// Ensure Client implements model.LLM
+var _ model.LLM = (*Client)(nil)
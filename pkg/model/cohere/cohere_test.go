@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cohere
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/model"
+	"github.com/kadirpekel/hector/pkg/tool"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := New(Config{APIKey: "co-test", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return client
+}
+
+func TestGenerateSendsToolResults(t *testing.T) {
+	var got chatRequest
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat" {
+			t.Errorf("path = %q, want /v1/chat", r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); auth != "Bearer co-test" {
+			t.Errorf("Authorization = %q", auth)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		fmt.Fprint(w, `{"text":"It is sunny.","finish_reason":"COMPLETE","meta":{"billed_units":{"input_tokens":42,"output_tokens":7}}}`)
+	})
+
+	req := &model.Request{
+		SystemInstruction: "Be brief.",
+		Messages: []*a2a.Message{
+			a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "Weather in Paris?"}),
+			a2a.NewMessage(a2a.MessageRoleAgent, a2a.DataPart{Data: map[string]any{
+				"type": "tool_use", "id": "call_0", "name": "weather",
+				"arguments": map[string]any{"city": "Paris"},
+			}}),
+			a2a.NewMessage(a2a.MessageRoleUser, a2a.DataPart{Data: map[string]any{
+				"type": "tool_result", "tool_call_id": "call_0", "content": "sunny",
+			}}),
+		},
+		Tools: []tool.Definition{{
+			Name:        "weather",
+			Description: "Get the weather",
+			Parameters: map[string]any{
+				"type":       "object",
+				"properties": map[string]any{"city": map[string]any{"type": "string", "description": "City name"}},
+				"required":   []any{"city"},
+			},
+		}},
+	}
+
+	var resp *model.Response
+	for r, err := range client.GenerateContent(context.Background(), req, false) {
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+		resp = r
+	}
+
+	if got.Preamble != "Be brief." || got.Message != "" {
+		t.Errorf("preamble = %q, message = %q", got.Preamble, got.Message)
+	}
+	if len(got.ChatHistory) != 2 || got.ChatHistory[0].Role != "USER" || got.ChatHistory[1].Role != "CHATBOT" {
+		t.Fatalf("chat_history = %+v", got.ChatHistory)
+	}
+	if len(got.ToolResults) != 1 {
+		t.Fatalf("tool_results = %+v", got.ToolResults)
+	}
+	result := got.ToolResults[0]
+	if result.Call.Name != "weather" || result.Call.Parameters["city"] != "Paris" || result.Outputs[0]["output"] != "sunny" {
+		t.Errorf("tool result = %+v", result)
+	}
+	def := got.Tools[0].ParameterDefinitions["city"]
+	if def == nil || def.Type != "str" || !def.Required {
+		t.Errorf("parameter definition = %+v", def)
+	}
+
+	if resp.TextContent() != "It is sunny." {
+		t.Errorf("text = %q", resp.TextContent())
+	}
+	if resp.Usage == nil || resp.Usage.PromptTokens != 42 || resp.Usage.CompletionTokens != 7 {
+		t.Errorf("usage = %+v", resp.Usage)
+	}
+}
+
+func TestGenerateStream(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		events := []string{
+			`{"event_type":"stream-start","generation_id":"gen"}`,
+			`{"event_type":"text-generation","text":"Let me "}`,
+			`{"event_type":"text-generation","text":"check."}`,
+			`{"event_type":"tool-calls-chunk","tool_call_delta":{"index":0,"name":"weather"}}`,
+			`{"event_type":"tool-calls-chunk","tool_call_delta":{"index":0,"parameters":"{\"city\":"}}`,
+			`{"event_type":"tool-calls-chunk","tool_call_delta":{"index":0,"parameters":"\"Paris\"}"}}`,
+			`{"event_type":"stream-end","finish_reason":"COMPLETE","response":{"meta":{"billed_units":{"input_tokens":10,"output_tokens":5}}}}`,
+		}
+		for _, e := range events {
+			fmt.Fprintln(w, e)
+		}
+	})
+
+	req := &model.Request{Messages: []*a2a.Message{
+		a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "Weather in Paris?"}),
+	}}
+
+	var partials int
+	var final *model.Response
+	for r, err := range client.GenerateContent(context.Background(), req, true) {
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+		if r.Partial {
+			partials++
+		} else {
+			final = r
+		}
+	}
+
+	if partials == 0 {
+		t.Error("expected partial responses")
+	}
+	if final == nil {
+		t.Fatal("expected final response")
+	}
+	if final.TextContent() != "Let me check." {
+		t.Errorf("text = %q", final.TextContent())
+	}
+	if len(final.ToolCalls) != 1 || final.ToolCalls[0].Name != "weather" || final.ToolCalls[0].Args["city"] != "Paris" {
+		t.Errorf("tool calls = %+v", final.ToolCalls)
+	}
+	if final.FinishReason != model.FinishReasonToolCalls {
+		t.Errorf("finish reason = %q", final.FinishReason)
+	}
+	if final.Usage == nil || final.Usage.TotalTokens != 15 {
+		t.Errorf("usage = %+v", final.Usage)
+	}
+}
@@ -0,0 +1,300 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"iter"
+	"log/slog"
+	"sync"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/tool"
+)
+
+// DedupLLM shares in-flight calls between concurrent identical requests.
+//
+// Requests are identical when they carry the same messages (roles and parts),
+// tools, instruction, generation config and streaming mode. The first request
+// makes the call; requests arriving while it runs subscribe to it and receive
+// every response from the start, streamed chunks included. The call is
+// cancelled once all subscribers have gone away.
+type DedupLLM struct {
+	llm LLM
+
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// NewDedupLLM wraps llm so identical concurrent requests share one call.
+func NewDedupLLM(llm LLM) *DedupLLM {
+	return &DedupLLM{
+		llm:     llm,
+		flights: make(map[string]*flight),
+	}
+}
+
+// Name returns the wrapped model name.
+func (d *DedupLLM) Name() string {
+	return d.llm.Name()
+}
+
+// Provider returns the wrapped model provider.
+func (d *DedupLLM) Provider() Provider {
+	return d.llm.Provider()
+}
+
+// Close closes the wrapped model.
+func (d *DedupLLM) Close() error {
+	return d.llm.Close()
+}
+
+// GenerateContent joins an in-flight identical call or starts a new one.
+func (d *DedupLLM) GenerateContent(ctx context.Context, req *Request, stream bool) iter.Seq2[*Response, error] {
+	return func(yield func(*Response, error) bool) {
+		key, err := requestKey(req, stream)
+		if err != nil {
+			// Unhashable request: call the model directly
+			for resp, err := range d.llm.GenerateContent(ctx, req, stream) {
+				if !yield(resp, err) {
+					return
+				}
+			}
+			return
+		}
+
+		f := d.join(ctx, key, req, stream)
+		defer d.leave(key, f)
+
+		for i := 0; ; i++ {
+			item, ok, err := f.wait(ctx, i)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !ok {
+				return
+			}
+			// Subscribers get their own copy so they can't affect each other
+			if !yield(cloneResponse(item.resp), item.err) {
+				return
+			}
+		}
+	}
+}
+
+// cloneResponse deep-copies the parts of resp that consumers modify in
+// place (content parts, tool calls and their args).
+func cloneResponse(resp *Response) *Response {
+	if resp == nil {
+		return nil
+	}
+	clone := *resp
+	if resp.Content != nil {
+		content := *resp.Content
+		if content.Parts != nil {
+			content.Parts = make([]a2a.Part, len(resp.Content.Parts))
+			for i, part := range resp.Content.Parts {
+				content.Parts[i] = clonePart(part)
+			}
+		}
+		clone.Content = &content
+	}
+	if resp.ToolCalls != nil {
+		clone.ToolCalls = make([]tool.ToolCall, len(resp.ToolCalls))
+		for i, tc := range resp.ToolCalls {
+			tc.Args = deepCopyMap(tc.Args)
+			clone.ToolCalls[i] = tc
+		}
+	}
+	if resp.Usage != nil {
+		usage := *resp.Usage
+		clone.Usage = &usage
+	}
+	if resp.Thinking != nil {
+		thinking := *resp.Thinking
+		clone.Thinking = &thinking
+	}
+	return &clone
+}
+
+// clonePart copies the maps of text and data parts.
+func clonePart(part a2a.Part) a2a.Part {
+	switch p := part.(type) {
+	case a2a.TextPart:
+		p.Metadata = deepCopyMap(p.Metadata)
+		return p
+	case a2a.DataPart:
+		p.Data = deepCopyMap(p.Data)
+		p.Metadata = deepCopyMap(p.Metadata)
+		return p
+	case *a2a.DataPart:
+		clone := *p
+		clone.Data = deepCopyMap(p.Data)
+		clone.Metadata = deepCopyMap(p.Metadata)
+		return &clone
+	}
+	return part
+}
+
+// join subscribes to the flight for key, starting it if needed.
+func (d *DedupLLM) join(ctx context.Context, key string, req *Request, stream bool) *flight {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if f, ok := d.flights[key]; ok {
+		f.subscribers++
+		slog.Debug("Sharing in-flight LLM call", "model", d.llm.Name())
+		return f
+	}
+
+	// The call outlives the request that started it while others are subscribed
+	callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	f := &flight{
+		wake:        make(chan struct{}),
+		cancel:      cancel,
+		subscribers: 1,
+	}
+	d.flights[key] = f
+
+	go func() {
+		defer cancel()
+		for resp, err := range d.llm.GenerateContent(callCtx, req, stream) {
+			f.add(flightItem{resp: resp, err: err})
+		}
+		d.mu.Lock()
+		if d.flights[key] == f {
+			delete(d.flights, key)
+		}
+		d.mu.Unlock()
+		f.finish()
+	}()
+
+	return f
+}
+
+// leave unsubscribes from f, cancelling the call when nobody is left.
+func (d *DedupLLM) leave(key string, f *flight) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	f.subscribers--
+	if f.subscribers > 0 {
+		return
+	}
+	if d.flights[key] == f {
+		delete(d.flights, key)
+	}
+	f.cancel()
+}
+
+// flightItem is one response or error of a shared call.
+type flightItem struct {
+	resp *Response
+	err  error
+}
+
+// flight buffers the output of a shared call for its subscribers.
+type flight struct {
+	cancel      context.CancelFunc
+	subscribers int // guarded by DedupLLM.mu
+
+	mu    sync.Mutex
+	items []flightItem
+	done  bool
+	wake  chan struct{} // closed and replaced whenever items or done change
+}
+
+func (f *flight) add(item flightItem) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.items = append(f.items, item)
+	close(f.wake)
+	f.wake = make(chan struct{})
+}
+
+func (f *flight) finish() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.done = true
+	close(f.wake)
+	f.wake = make(chan struct{})
+}
+
+// wait returns item i once available. ok is false when the call finished
+// without it; err is set when ctx ends first.
+func (f *flight) wait(ctx context.Context, i int) (flightItem, bool, error) {
+	for {
+		f.mu.Lock()
+		if i < len(f.items) {
+			item := f.items[i]
+			f.mu.Unlock()
+			return item, true, nil
+		}
+		if f.done {
+			f.mu.Unlock()
+			return flightItem{}, false, nil
+		}
+		wake := f.wake
+		f.mu.Unlock()
+
+		select {
+		case <-wake:
+		case <-ctx.Done():
+			return flightItem{}, false, ctx.Err()
+		}
+	}
+}
+
+// requestKey hashes the parts of a request that determine the model output.
+// Message IDs, context and metadata differ between users and are ignored.
+func requestKey(req *Request, stream bool) (string, error) {
+	type message struct {
+		Role  a2a.MessageRole  `json:"role"`
+		Parts a2a.ContentParts `json:"parts"`
+	}
+	key := struct {
+		Stream            bool              `json:"stream"`
+		Messages          []message         `json:"messages"`
+		Tools             []tool.Definition `json:"tools"`
+		Config            *GenerateConfig   `json:"config"`
+		SystemInstruction string            `json:"system_instruction"`
+	}{
+		Stream:            stream,
+		Tools:             req.Tools,
+		Config:            req.Config,
+		SystemInstruction: req.SystemInstruction,
+	}
+	for _, msg := range req.Messages {
+		if msg != nil {
+			key.Messages = append(key.Messages, message{Role: msg.Role, Parts: msg.Parts})
+		}
+	}
+
+	data, err := json.Marshal(key)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Ensure DedupLLM implements LLM.
+var _ LLM = (*DedupLLM)(nil)
@@ -0,0 +1,145 @@
+package model
+
+import (
+	"context"
+	"iter"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/tool"
+)
+
+type blockingLLM struct {
+	calls   atomic.Int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingLLM) Name() string       { return "blocking" }
+func (b *blockingLLM) Provider() Provider { return ProviderOpenAI }
+func (b *blockingLLM) Close() error       { return nil }
+
+func (b *blockingLLM) GenerateContent(ctx context.Context, req *Request, stream bool) iter.Seq2[*Response, error] {
+	return func(yield func(*Response, error) bool) {
+		b.calls.Add(1)
+		b.started <- struct{}{}
+		if !yield(&Response{Content: &Content{Parts: []a2a.Part{a2a.TextPart{Text: "Hel"}}}, Partial: true}, nil) {
+			return
+		}
+		select {
+		case <-b.release:
+		case <-ctx.Done():
+			yield(nil, ctx.Err())
+			return
+		}
+		yield(&Response{Content: &Content{Parts: []a2a.Part{a2a.TextPart{Text: "Hello"}}}}, nil)
+	}
+}
+
+func TestDedupLLMSharesConcurrentIdenticalCalls(t *testing.T) {
+	inner := &blockingLLM{started: make(chan struct{}, 2), release: make(chan struct{})}
+	llm := NewDedupLLM(inner)
+
+	newReq := func() *Request {
+		// Each user's message gets a fresh ID; only the content matters
+		return &Request{Messages: []*a2a.Message{a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "What's trending?"})}}
+	}
+
+	var wg sync.WaitGroup
+	results := make([][]string, 2)
+	run := func(i int) {
+		defer wg.Done()
+		for resp, err := range llm.GenerateContent(context.Background(), newReq(), true) {
+			if err != nil {
+				t.Errorf("GenerateContent() error = %v", err)
+				return
+			}
+			results[i] = append(results[i], resp.TextContent())
+		}
+	}
+
+	wg.Add(2)
+	go run(0)
+	<-inner.started
+	go run(1)
+
+	// Wait for the second request to subscribe before completing the call
+	deadline := time.Now().Add(time.Second)
+	for {
+		llm.mu.Lock()
+		subscribers := 0
+		for _, f := range llm.flights {
+			subscribers = f.subscribers
+		}
+		llm.mu.Unlock()
+		if subscribers == 2 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("second request did not join the in-flight call")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	close(inner.release)
+	wg.Wait()
+
+	if got := inner.calls.Load(); got != 1 {
+		t.Errorf("model calls = %d, want 1", got)
+	}
+	for i, got := range results {
+		if len(got) != 2 || got[0] != "Hel" || got[1] != "Hello" {
+			t.Errorf("subscriber %d responses = %v, want [Hel Hello]", i, got)
+		}
+	}
+}
+
+func TestDedupLLMCancelsAbandonedCall(t *testing.T) {
+	inner := &blockingLLM{started: make(chan struct{}, 2), release: make(chan struct{})}
+	llm := NewDedupLLM(inner)
+	req := &Request{Messages: []*a2a.Message{a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "Hi"})}}
+
+	for range llm.GenerateContent(context.Background(), req, true) {
+		break // Stop after the first chunk
+	}
+	<-inner.started
+
+	// A later identical request starts a fresh call
+	close(inner.release)
+	for _, err := range llm.GenerateContent(context.Background(), req, true) {
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+	}
+	if got := inner.calls.Load(); got != 2 {
+		t.Errorf("model calls = %d, want 2", got)
+	}
+}
+
+func TestCloneResponseIsolatesSubscribers(t *testing.T) {
+	resp := &Response{
+		Content: &Content{Parts: []a2a.Part{
+			a2a.TextPart{Text: "hello"},
+			a2a.DataPart{Data: map[string]any{"nested": map[string]any{"k": "v"}}},
+		}},
+		ToolCalls: []tool.ToolCall{{ID: "1", Name: "search", Args: map[string]any{"q": "go"}}},
+	}
+
+	clone := cloneResponse(resp)
+	clone.Content.Parts[0] = a2a.TextPart{Text: "HELLO"}
+	clone.Content.Parts[1].(a2a.DataPart).Data["nested"].(map[string]any)["k"] = "changed"
+	clone.ToolCalls[0].Args["q"] = "rust"
+
+	if got := resp.Content.Parts[0].(a2a.TextPart).Text; got != "hello" {
+		t.Errorf("original text = %q, want hello", got)
+	}
+	if got := resp.Content.Parts[1].(a2a.DataPart).Data["nested"].(map[string]any)["k"]; got != "v" {
+		t.Errorf("original data = %v, want v", got)
+	}
+	if got := resp.ToolCalls[0].Args["q"]; got != "go" {
+		t.Errorf("original tool args = %v, want go", got)
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"iter"
+	"strings"
 
 	"github.com/a2aproject/a2a-go/a2a"
 	"google.golang.org/genai"
@@ -57,8 +58,20 @@ type Config struct {
 
 	// MaxToolOutputLength limits the length of tool outputs.
 	MaxToolOutputLength int
+
+	// Roles overrides the role names sent for user and agent messages.
+	// Default: user, model.
+	Roles model.RoleMapping
+
+	// SystemMessages controls how mid-conversation system messages are sent.
+	// Gemini has no system role in contents, so native mode is not supported.
+	// Default: model.SystemMessagesMerge.
+	SystemMessages model.SystemMessageMode
 }
 
+// defaultRoles are the Gemini content role names.
+var defaultRoles = model.RoleMapping{User: "user", Agent: "model"}
+
 // geminiModel implements model.LLM for Gemini.
 type geminiModel struct {
 	client *genai.Client
@@ -74,6 +87,13 @@ func New(cfg Config) (model.LLM, error) {
 	if cfg.Model == "" {
 		cfg.Model = "gemini-2.0-flash"
 	}
+	if cfg.SystemMessages == model.SystemMessagesNative {
+		return nil, fmt.Errorf("system message mode %q is not supported by Gemini", cfg.SystemMessages)
+	}
+	if cfg.SystemMessages == "" {
+		cfg.SystemMessages = model.SystemMessagesMerge
+	}
+	cfg.Roles = cfg.Roles.WithDefaults(defaultRoles)
 
 	// Use context.Background() for initialization - constructors shouldn't require context
 	client, err := genai.NewClient(context.Background(), &genai.ClientConfig{
@@ -305,6 +325,7 @@ func (m *geminiModel) processStreamChunk(agg *model.StreamingAggregator, genResp
 
 // buildRequest converts Hector request to Gemini format.
 func (m *geminiModel) buildRequest(req *model.Request) ([]*genai.Content, *genai.Content) {
+	req = model.ApplySystemMessages(req, m.config.SystemMessages)
 	var contents []*genai.Content
 	var systemInstruction *genai.Content
 
@@ -413,14 +434,9 @@ func (m *geminiModel) messageToContent(msg *a2a.Message) *genai.Content {
 		return nil
 	}
 
-	role := "user"
-	if msg.Role == a2a.MessageRoleAgent {
-		role = "model"
-	}
-
 	return &genai.Content{
 		Parts: parts,
-		Role:  role,
+		Role:  m.config.Roles.Role(msg),
 	}
 }
 
@@ -469,7 +485,6 @@ func (m *geminiModel) buildConfig(cfg *model.GenerateConfig, systemInstruction *
 			}
 			config.ThinkingConfig = thinkingConfig
 		}
-		// TODO: Handle ResponseSchema for structured output
 	}
 
 	// Apply defaults from model config
@@ -509,6 +524,8 @@ func (m *geminiModel) buildTools(tools []tool.Definition) []*genai.Tool {
 }
 
 // toGenaiSchema converts a JSON schema to Gemini schema.
+// Gemini accepts an OpenAPI subset, so type unions with "null" become
+// Nullable and unsupported keywords are dropped.
 func toGenaiSchema(schema map[string]any) *genai.Schema {
 	if schema == nil {
 		return nil
@@ -517,12 +534,28 @@ func toGenaiSchema(schema map[string]any) *genai.Schema {
 	// Convert JSON schema to genai.Schema
 	s := &genai.Schema{}
 
-	if t, ok := schema["type"].(string); ok {
-		s.Type = genai.Type(t)
+	switch t := schema["type"].(type) {
+	case string:
+		s.Type = genai.Type(strings.ToUpper(t))
+	case []any:
+		for _, v := range t {
+			ts, _ := v.(string)
+			if ts == "null" {
+				s.Nullable = genai.Ptr(true)
+			} else if ts != "" && s.Type == "" {
+				s.Type = genai.Type(strings.ToUpper(ts))
+			}
+		}
+	}
+	if nullable, ok := schema["nullable"].(bool); ok && nullable {
+		s.Nullable = genai.Ptr(true)
 	}
 	if desc, ok := schema["description"].(string); ok {
 		s.Description = desc
 	}
+	if format, ok := schema["format"].(string); ok {
+		s.Format = format
+	}
 	if props, ok := schema["properties"].(map[string]any); ok {
 		s.Properties = make(map[string]*genai.Schema)
 		for name, prop := range props {
@@ -531,27 +564,84 @@ func toGenaiSchema(schema map[string]any) *genai.Schema {
 			}
 		}
 	}
-	if required, ok := schema["required"].([]any); ok {
-		for _, r := range required {
-			if rs, ok := r.(string); ok {
-				s.Required = append(s.Required, rs)
-			}
-		}
-	}
+	s.Required = schemaStrings(schema["required"])
 	if items, ok := schema["items"].(map[string]any); ok {
 		s.Items = toGenaiSchema(items)
 	}
-	if enum, ok := schema["enum"].([]any); ok {
-		for _, e := range enum {
-			if es, ok := e.(string); ok {
-				s.Enum = append(s.Enum, es)
+	for _, e := range schemaList(schema["enum"]) {
+		if es, ok := e.(string); ok {
+			s.Enum = append(s.Enum, es)
+		}
+	}
+	for _, key := range []string{"anyOf", "oneOf"} {
+		for _, sub := range schemaList(schema[key]) {
+			if subMap, ok := sub.(map[string]any); ok {
+				s.AnyOf = append(s.AnyOf, toGenaiSchema(subMap))
 			}
 		}
 	}
+	if v, ok := schemaNumber(schema["minimum"]); ok {
+		s.Minimum = &v
+	}
+	if v, ok := schemaNumber(schema["maximum"]); ok {
+		s.Maximum = &v
+	}
+	if v, ok := schemaNumber(schema["minItems"]); ok {
+		s.MinItems = genai.Ptr(int64(v))
+	}
+	if v, ok := schemaNumber(schema["maxItems"]); ok {
+		s.MaxItems = genai.Ptr(int64(v))
+	}
 
 	return s
 }
 
+// schemaList returns a JSON schema array keyword as []any, accepting the
+// typed slices produced by Go-built schemas.
+func schemaList(v any) []any {
+	switch list := v.(type) {
+	case []any:
+		return list
+	case []string:
+		out := make([]any, len(list))
+		for i, s := range list {
+			out[i] = s
+		}
+		return out
+	case []map[string]any:
+		out := make([]any, len(list))
+		for i, m := range list {
+			out[i] = m
+		}
+		return out
+	}
+	return nil
+}
+
+// schemaStrings returns the string entries of a JSON schema array keyword.
+func schemaStrings(v any) []string {
+	var out []string
+	for _, e := range schemaList(v) {
+		if s, ok := e.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// schemaNumber reads a numeric JSON schema keyword.
+func schemaNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
 // parseResponse converts Gemini response to Hector response.
 func (m *geminiModel) parseResponse(genResp *genai.GenerateContentResponse) (*model.Response, error) {
 	if len(genResp.Candidates) == 0 {
@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gemini
+
+import (
+	"encoding/json"
+	"testing"
+
+	"google.golang.org/genai"
+
+	"github.com/kadirpekel/hector/pkg/model"
+)
+
+func TestBuildConfigResponseSchema(t *testing.T) {
+	schema := map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"name":  map[string]any{"type": "string", "description": "Full name"},
+			"email": map[string]any{"type": []any{"string", "null"}, "format": "email"},
+			"tags": map[string]any{
+				"type":     "array",
+				"items":    map[string]any{"type": "string", "enum": []string{"a", "b"}},
+				"minItems": 1,
+			},
+			"score": map[string]any{"type": "number", "minimum": 0.0, "maximum": 1.0},
+		},
+		"required": []string{"name", "tags"},
+	}
+	m := &geminiModel{}
+	config := m.buildConfig(&model.GenerateConfig{ResponseSchema: schema}, nil, nil)
+
+	if config.ResponseMIMEType != "application/json" {
+		t.Errorf("ResponseMIMEType = %q", config.ResponseMIMEType)
+	}
+	s := config.ResponseSchema
+	if s == nil || s.Type != genai.TypeObject {
+		t.Fatalf("ResponseSchema = %+v", s)
+	}
+	if len(s.Required) != 2 || s.Required[0] != "name" || s.Required[1] != "tags" {
+		t.Errorf("Required = %v", s.Required)
+	}
+	if name := s.Properties["name"]; name.Type != genai.TypeString || name.Description != "Full name" {
+		t.Errorf("name = %+v", name)
+	}
+	if email := s.Properties["email"]; email.Type != genai.TypeString || email.Nullable == nil || !*email.Nullable || email.Format != "email" {
+		t.Errorf("email = %+v", email)
+	}
+	tags := s.Properties["tags"]
+	if tags.Type != genai.TypeArray || tags.MinItems == nil || *tags.MinItems != 1 {
+		t.Errorf("tags = %+v", tags)
+	}
+	if tags.Items == nil || len(tags.Items.Enum) != 2 {
+		t.Errorf("tags.items = %+v", tags.Items)
+	}
+	if score := s.Properties["score"]; score.Minimum == nil || *score.Minimum != 0 || score.Maximum == nil || *score.Maximum != 1 {
+		t.Errorf("score = %+v", score)
+	}
+}
+
+func TestBuildConfigKeepsResponseMIMEType(t *testing.T) {
+	m := &geminiModel{}
+	config := m.buildConfig(&model.GenerateConfig{
+		ResponseMIMEType: "text/x.enum",
+		ResponseSchema:   map[string]any{"type": "string", "enum": []any{"yes", "no"}},
+	}, nil, nil)
+	if config.ResponseMIMEType != "text/x.enum" {
+		t.Errorf("ResponseMIMEType = %q", config.ResponseMIMEType)
+	}
+}
+
+func TestParseResponseStructuredText(t *testing.T) {
+	m := &geminiModel{}
+	resp, err := m.parseResponse(&genai.GenerateContentResponse{
+		Candidates: []*genai.Candidate{{
+			Content:      genai.NewContentFromText(`{"name":"Ada","tags":["a"]}`, genai.RoleModel),
+			FinishReason: genai.FinishReasonStop,
+		}},
+	})
+	if err != nil {
+		t.Fatalf("parseResponse() error = %v", err)
+	}
+
+	var got struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+	if err := json.Unmarshal([]byte(resp.TextContent()), &got); err != nil {
+		t.Fatalf("response %q is not JSON: %v", resp.TextContent(), err)
+	}
+	if got.Name != "Ada" || len(got.Tags) != 1 {
+		t.Errorf("response = %+v", got)
+	}
+	if resp.FinishReason != model.FinishReasonStop {
+		t.Errorf("FinishReason = %v", resp.FinishReason)
+	}
+}
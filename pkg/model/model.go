@@ -24,10 +24,13 @@ package model
 
 import (
 	"context"
+	"fmt"
 	"iter"
+	"time"
 
 	"github.com/a2aproject/a2a-go/a2a"
 
+	"github.com/kadirpekel/hector/pkg/httpclient"
 	"github.com/kadirpekel/hector/pkg/tool"
 )
 
@@ -64,6 +67,23 @@ type LLM interface {
 	Close() error
 }
 
+// WithRequestTimeout bounds ctx by a request's total timeout.
+//
+// A zero timeout falls back to def for non-streaming requests. Streams get
+// no deadline, so a long but healthy stream isn't cut off; instead they
+// fail once no data arrives for def (see httpclient.WithIdleTimeout).
+// Providers bound connection setup separately (see
+// httpclient.WithConnectTimeout).
+func WithRequestTimeout(ctx context.Context, timeout, def time.Duration, stream bool) (context.Context, context.CancelFunc) {
+	if timeout <= 0 && !stream {
+		timeout = def
+	}
+	if timeout <= 0 {
+		return context.WithCancel(httpclient.WithIdleTimeout(ctx, def))
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
 // Provider identifies the LLM provider.
 // Used for model-specific message formatting and content processing.
 type Provider string
@@ -85,6 +105,10 @@ const (
 	// Follows OpenAI-compatible format.
 	ProviderOllama Provider = "ollama"
 
+	// ProviderCohere represents Cohere models (Command R)
+	// Tool results are matched to calls by name and parameters.
+	ProviderCohere Provider = "cohere"
+
 	// ProviderUnknown for unrecognized providers.
 	ProviderUnknown Provider = "unknown"
 )
@@ -102,6 +126,11 @@ type Request struct {
 
 	// SystemInstruction is prepended to the conversation.
 	SystemInstruction string
+
+	// ConversationID identifies the conversation this request belongs to.
+	// Providers that keep server-side conversation state (e.g., OpenAI stored
+	// responses) use it to chain turns. Empty means no chaining.
+	ConversationID string
 }
 
 // GenerateConfig contains configuration for generation.
@@ -143,6 +172,10 @@ type GenerateConfig struct {
 	// ThinkingBudget limits thinking tokens (model-specific).
 	ThinkingBudget int
 
+	// ReasoningSummary sets reasoning summary verbosity (OpenAI: none, auto,
+	// concise, detailed). Empty uses the provider's configured default.
+	ReasoningSummary string
+
 	// Metadata contains additional key-value pairs for LLM providers.
 	// Used for authentication tokens, custom headers, etc.
 	Metadata map[string]string
@@ -326,6 +359,23 @@ const (
 	FinishReasonError     FinishReason = "error"
 )
 
+// IncompleteToolCallError reports a tool call whose arguments were cut off
+// when the response stream ended and could not be repaired.
+type IncompleteToolCallError struct {
+	// ID is the tool call ID.
+	ID string
+
+	// Name is the tool name.
+	Name string
+
+	// Arguments is the partial arguments JSON received before the stream ended.
+	Arguments string
+}
+
+func (e *IncompleteToolCallError) Error() string {
+	return fmt.Sprintf("stream ended before the arguments of tool call %q (%s) were complete", e.Name, e.ID)
+}
+
 // TextContent extracts text from a response.
 func (r *Response) TextContent() string {
 	if r == nil || r.Content == nil {
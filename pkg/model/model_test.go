@@ -0,0 +1,35 @@
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kadirpekel/hector/pkg/httpclient"
+)
+
+func TestWithRequestTimeout(t *testing.T) {
+	tests := []struct {
+		name         string
+		timeout      time.Duration
+		stream       bool
+		wantDeadline bool
+	}{
+		{"non-streaming uses default", 0, false, true},
+		{"stream has no deadline by default", 0, true, false},
+		{"explicit timeout bounds stream", time.Minute, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := WithRequestTimeout(context.Background(), tt.timeout, 2*time.Minute, tt.stream)
+			defer cancel()
+			if _, ok := ctx.Deadline(); ok != tt.wantDeadline {
+				t.Errorf("has deadline = %v, want %v", ok, tt.wantDeadline)
+			}
+			// A stream without a deadline is still bounded while idle
+			if tt.stream && !tt.wantDeadline && httpclient.IdleTimeout(ctx) != 2*time.Minute {
+				t.Errorf("idle timeout = %v, want 2m", httpclient.IdleTimeout(ctx))
+			}
+		})
+	}
+}
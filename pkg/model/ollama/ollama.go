@@ -45,10 +45,11 @@ import (
 )
 
 const (
-	defaultBaseURL   = "http://localhost:11434"
-	defaultModel     = "llama3.2"
-	defaultTimeout   = 300 * time.Second // Ollama can be slow for first request
-	defaultKeepAlive = "5m"
+	defaultBaseURL        = "http://localhost:11434"
+	defaultModel          = "llama3.2"
+	defaultTimeout        = 300 * time.Second // Ollama can be slow for first request
+	defaultConnectTimeout = 10 * time.Second
+	defaultKeepAlive      = "5m"
 )
 
 // Config configures the Ollama client.
@@ -80,9 +81,13 @@ type Config struct {
 	// KeepAlive controls how long the model stays loaded (default: "5m")
 	KeepAlive string
 
-	// Timeout for HTTP requests
+	// Timeout bounds each request from connect to the last byte.
+	// Default: 300s for non-streaming requests; streams are unbounded.
 	Timeout time.Duration
 
+	// ConnectTimeout bounds dialing. Default: 10s.
+	ConnectTimeout time.Duration
+
 	// MaxRetries for HTTP requests with retry/backoff
 	MaxRetries int
 
@@ -91,8 +96,19 @@ type Config struct {
 
 	// MaxToolOutputLength limits the length of tool outputs.
 	MaxToolOutputLength int
+
+	// Roles overrides the role names sent for each message type.
+	// Default: user, assistant, system.
+	Roles model.RoleMapping
+
+	// SystemMessages controls how mid-conversation system messages are sent.
+	// Default: model.SystemMessagesNative.
+	SystemMessages model.SystemMessageMode
 }
 
+// defaultRoles are the Ollama chat role names.
+var defaultRoles = model.RoleMapping{User: "user", Agent: "assistant", System: "system"}
+
 // Option configures the Ollama client.
 type Option func(*Config)
 
@@ -128,6 +144,7 @@ func WithThinking() Option {
 // Implements model.LLM interface aligned with ADK-Go.
 type Client struct {
 	httpClient          *httpclient.Client
+	timeout             time.Duration
 	baseURL             string
 	modelName           string
 	temperature         *float64
@@ -139,6 +156,8 @@ type Client struct {
 	keepAlive           string
 	enableThinking      bool
 	maxToolOutputLength int
+	roles               model.RoleMapping
+	systemMessages      model.SystemMessageMode
 }
 
 // New creates a new Ollama client.
@@ -154,9 +173,9 @@ func New(cfg Config) (*Client, error) {
 		modelName = defaultModel
 	}
 
-	timeout := cfg.Timeout
-	if timeout == 0 {
-		timeout = defaultTimeout
+	connectTimeout := cfg.ConnectTimeout
+	if connectTimeout == 0 {
+		connectTimeout = defaultConnectTimeout
 	}
 
 	keepAlive := cfg.KeepAlive
@@ -169,15 +188,22 @@ func New(cfg Config) (*Client, error) {
 		maxRetries = 3 // Default retries for Ollama
 	}
 
+	systemMessages := cfg.SystemMessages
+	if systemMessages == "" {
+		systemMessages = model.SystemMessagesNative
+	}
+
 	// Use Hector's httpclient with retry/backoff for resilience
 	hc := httpclient.New(
-		httpclient.WithHTTPClient(&http.Client{Timeout: timeout}),
+		httpclient.WithHTTPClient(&http.Client{}),
+		httpclient.WithConnectTimeout(connectTimeout),
 		httpclient.WithMaxRetries(maxRetries),
 		httpclient.WithBaseDelay(2*time.Second),
 	)
 
 	return &Client{
 		httpClient:          hc,
+		timeout:             cfg.Timeout,
 		baseURL:             baseURL,
 		modelName:           modelName,
 		temperature:         cfg.Temperature,
@@ -189,6 +215,8 @@ func New(cfg Config) (*Client, error) {
 		keepAlive:           keepAlive,
 		enableThinking:      cfg.EnableThinking,
 		maxToolOutputLength: cfg.MaxToolOutputLength,
+		roles:               cfg.Roles.WithDefaults(defaultRoles),
+		systemMessages:      systemMessages,
 	}, nil
 }
 
@@ -212,11 +240,19 @@ func (c *Client) Provider() model.Provider {
 //   - Yields multiple partial Responses (Partial=true) for real-time UI updates
 //   - Finally yields aggregated Response (Partial=false) for session persistence
 func (c *Client) GenerateContent(ctx context.Context, req *model.Request, stream bool) iter.Seq2[*model.Response, error] {
-	if stream {
-		return c.generateStream(ctx, req)
-	}
-
 	return func(yield func(*model.Response, error) bool) {
+		ctx, cancel := model.WithRequestTimeout(ctx, c.timeout, defaultTimeout, stream)
+		defer cancel()
+
+		if stream {
+			for resp, err := range c.generateStream(ctx, req) {
+				if !yield(resp, err) {
+					return
+				}
+			}
+			return
+		}
+
 		resp, err := c.generate(ctx, req)
 		yield(resp, err)
 	}
@@ -467,6 +503,7 @@ func (c *Client) processAccumulatedToolCalls(state *ollamaStreamState, agg *mode
 
 // buildRequest creates an API request from model.Request.
 func (c *Client) buildRequest(req *model.Request, stream bool) *chatRequest {
+	req = model.ApplySystemMessages(req, c.systemMessages)
 	enableThinking := c.enableThinking || (req.Config != nil && req.Config.EnableThinking)
 
 	apiReq := &chatRequest{
@@ -545,7 +582,7 @@ func (c *Client) buildRequest(req *model.Request, stream bool) *chatRequest {
 	// Add system instruction as first message if present
 	if req.SystemInstruction != "" {
 		systemMsg := &chatMessage{
-			Role:    "system",
+			Role:    c.roles.System,
 			Content: req.SystemInstruction,
 		}
 		apiReq.Messages = append([]*chatMessage{systemMsg}, apiReq.Messages...)
@@ -561,13 +598,8 @@ func (c *Client) buildRequest(req *model.Request, stream bool) *chatRequest {
 
 // convertMessage converts an a2a.Message to Ollama format.
 func (c *Client) convertMessage(msg *a2a.Message) *chatMessage {
-	role := "user"
-	if msg.Role == a2a.MessageRoleAgent {
-		role = "assistant"
-	}
-
 	ollamaMsg := &chatMessage{
-		Role: role,
+		Role: c.roles.Role(msg),
 	}
 
 	var textParts []string
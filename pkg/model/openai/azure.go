@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultAzureAPIVersion is the Azure OpenAI API version used when none
+	// is configured. The Responses API requires 2025-03-01-preview or later.
+	DefaultAzureAPIVersion = "2025-04-01-preview"
+
+	// azureCognitiveServicesScope is the Entra ID scope for Azure OpenAI.
+	azureCognitiveServicesScope = "https://cognitiveservices.azure.com/.default"
+
+	// defaultAzureAuthorityHost is the Entra ID login endpoint.
+	defaultAzureAuthorityHost = "https://login.microsoftonline.com"
+
+	// azureTokenRefreshMargin refreshes cached tokens before they expire.
+	azureTokenRefreshMargin = 5 * time.Minute
+)
+
+// AzureConfig routes requests to an Azure OpenAI deployment.
+//
+// Azure serves the Responses API at
+// {endpoint}/openai/responses?api-version={version}, with the deployment
+// name in place of the model. Requests authenticate with the API key in the
+// api-key header, or with an Entra ID (AAD) bearer token when TokenSource
+// is set.
+type AzureConfig struct {
+	// Endpoint is the resource endpoint (e.g., "https://myres.openai.azure.com").
+	Endpoint string
+
+	// Deployment is the deployment name sent as the model.
+	// Default: Config.Model.
+	Deployment string
+
+	// APIVersion is the api-version query parameter.
+	// Default: DefaultAzureAPIVersion.
+	APIVersion string
+
+	// TokenSource returns Entra ID bearer tokens. If nil, the API key is used.
+	TokenSource TokenSource
+}
+
+// TokenSource returns a bearer token for a request.
+type TokenSource func(ctx context.Context) (string, error)
+
+// StaticToken returns a TokenSource that always returns token.
+func StaticToken(token string) TokenSource {
+	return func(context.Context) (string, error) {
+		return token, nil
+	}
+}
+
+// AzureClientCredentials returns a TokenSource that obtains Entra ID tokens
+// for a service principal using the OAuth2 client credentials flow. Tokens
+// are cached until shortly before they expire.
+func AzureClientCredentials(tenantID, clientID, clientSecret string) TokenSource {
+	cc := &azureClientCredentials{
+		tokenURL:     fmt.Sprintf("%s/%s/oauth2/v2.0/token", defaultAzureAuthorityHost, url.PathEscape(tenantID)),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		httpClient:   &http.Client{Timeout: 30 * time.Second},
+	}
+	return cc.token
+}
+
+type azureClientCredentials struct {
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	httpClient   *http.Client
+
+	mu      sync.Mutex
+	cached  string
+	expires time.Time
+}
+
+func (a *azureClientCredentials) token(ctx context.Context) (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.cached != "" && time.Now().Before(a.expires) {
+		return a.cached, nil
+	}
+
+	form := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {a.clientID},
+		"client_secret": {a.clientSecret},
+		"scope":         {azureCognitiveServicesScope},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get Azure AD token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to decode Azure AD token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || body.AccessToken == "" {
+		return "", fmt.Errorf("failed to get Azure AD token (status %d): %s", resp.StatusCode, body.ErrorDescription)
+	}
+
+	a.cached = body.AccessToken
+	a.expires = time.Now().Add(time.Duration(body.ExpiresIn)*time.Second - azureTokenRefreshMargin)
+	return a.cached, nil
+}
+
+// azureResponsesURL returns the Responses API URL of an Azure resource.
+func azureResponsesURL(endpoint, apiVersion string) string {
+	return strings.TrimSuffix(endpoint, "/") + "/openai/responses?api-version=" + url.QueryEscape(apiVersion)
+}
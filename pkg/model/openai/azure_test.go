@@ -0,0 +1,69 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/kadirpekel/hector/pkg/model"
+)
+
+func TestAzureDeploymentRouting(t *testing.T) {
+	var got *http.Request
+	var gotModel string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		var body struct {
+			Model string `json:"model"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		gotModel = body.Model
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"resp_1","status":"completed","output":[{"type":"message","role":"assistant","content":[{"type":"output_text","text":"hi"}]}]}`))
+	}))
+	defer server.Close()
+
+	generate := func(cfg Config) {
+		t.Helper()
+		client, err := New(cfg)
+		if err != nil {
+			t.Fatalf("New() error = %v", err)
+		}
+		req := &model.Request{Messages: []*a2a.Message{a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "hello"})}}
+		for _, err := range client.GenerateContent(context.Background(), req, false) {
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+		}
+	}
+
+	generate(Config{
+		APIKey: "azure-key",
+		Model:  "gpt-4o",
+		Azure:  &AzureConfig{Endpoint: server.URL + "/", Deployment: "gpt-4o-prod"},
+	})
+	if got.URL.Path != "/openai/responses" || got.URL.Query().Get("api-version") != DefaultAzureAPIVersion {
+		t.Errorf("request URL = %s, want /openai/responses?api-version=%s", got.URL, DefaultAzureAPIVersion)
+	}
+	if got.Header.Get("api-key") != "azure-key" || got.Header.Get("Authorization") != "" {
+		t.Errorf("auth headers = api-key %q, Authorization %q", got.Header.Get("api-key"), got.Header.Get("Authorization"))
+	}
+	if gotModel != "gpt-4o-prod" {
+		t.Errorf("model = %q, want deployment name", gotModel)
+	}
+
+	// Entra ID tokens replace the API key
+	generate(Config{
+		Model: "gpt-4o",
+		Azure: &AzureConfig{Endpoint: server.URL, APIVersion: "2025-03-01-preview", TokenSource: StaticToken("aad-token")},
+	})
+	if got.Header.Get("Authorization") != "Bearer aad-token" || got.Header.Get("api-key") != "" {
+		t.Errorf("auth headers = api-key %q, Authorization %q", got.Header.Get("api-key"), got.Header.Get("Authorization"))
+	}
+	if got.URL.Query().Get("api-version") != "2025-03-01-preview" || gotModel != "gpt-4o" {
+		t.Errorf("api-version = %q, model = %q", got.URL.Query().Get("api-version"), gotModel)
+	}
+}
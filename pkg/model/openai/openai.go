@@ -35,6 +35,7 @@ import (
 	"log/slog"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/a2aproject/a2a-go/a2a"
@@ -42,6 +43,7 @@ import (
 	"github.com/kadirpekel/hector/pkg/httpclient"
 	"github.com/kadirpekel/hector/pkg/model"
 	"github.com/kadirpekel/hector/pkg/tool"
+	"github.com/kadirpekel/hector/pkg/utils"
 )
 
 const (
@@ -49,6 +51,9 @@ const (
 	defaultModel   = "gpt-5"
 	defaultTimeout = 120 * time.Second
 
+	// defaultConnectTimeout bounds dialing and the TLS handshake.
+	defaultConnectTimeout = 10 * time.Second
+
 	// Reasoning effort thresholds
 	reasoningEffortLowThreshold    = 1024
 	reasoningEffortMediumThreshold = 8192
@@ -82,13 +87,44 @@ type Config struct {
 	MaxTokens           int
 	Temperature         *float64
 	BaseURL             string
-	Timeout             time.Duration
 	MaxRetries          int
 	MaxToolOutputLength int
 	EnableReasoning     bool
 	ReasoningBudget     int // Maps to reasoning.effort: low/medium/high
+
+	// Timeout bounds each request from connect to the last byte.
+	// Default: 120s for non-streaming requests; streams are unbounded.
+	Timeout time.Duration
+
+	// ConnectTimeout bounds dialing and the TLS handshake. Default: 10s.
+	ConnectTimeout time.Duration
+
+	// ReasoningSummary sets reasoning.summary verbosity when reasoning is
+	// enabled: none, auto, concise or detailed. Default: auto.
+	ReasoningSummary string
+
+	// EnableStoredResponses stores responses server-side (store: true) and
+	// chains follow-up turns via previous_response_id, sending only new messages.
+	EnableStoredResponses bool
+	// StoredResponseTTL is how long a stored response is reused before the
+	// full history is resent. Default: 30 days (OpenAI's retention period).
+	StoredResponseTTL time.Duration
+
+	// Roles overrides the role names sent for each message type.
+	// Default: user, assistant, system.
+	Roles model.RoleMapping
+	// SystemMessages controls how mid-conversation system messages are sent.
+	// Default: model.SystemMessagesNative.
+	SystemMessages model.SystemMessageMode
+
+	// Azure routes requests to an Azure OpenAI deployment instead of the
+	// OpenAI API. BaseURL is ignored when set.
+	Azure *AzureConfig
 }
 
+// defaultRoles are the Responses API role names.
+var defaultRoles = model.RoleMapping{User: "user", Agent: "assistant", System: "system"}
+
 // Option configures the OpenAI client.
 type Option func(*Config)
 
@@ -128,10 +164,19 @@ func WithReasoning(budget int) Option {
 	}
 }
 
+// WithStoredResponses enables response chaining via previous_response_id.
+func WithStoredResponses(ttl time.Duration) Option {
+	return func(c *Config) {
+		c.EnableStoredResponses = true
+		c.StoredResponseTTL = ttl
+	}
+}
+
 // Client is an OpenAI LLM implementation using the Responses API.
 // Implements model.LLM interface aligned with ADK-Go.
 type Client struct {
 	httpClient          *httpclient.Client
+	timeout             time.Duration
 	apiKey              string
 	baseURL             string
 	modelName           string
@@ -140,11 +185,24 @@ type Client struct {
 	temperature         *float64
 	enableReasoning     bool
 	reasoningBudget     int
+	reasoningSummary    string
+	responses           *responseChain // nil unless stored responses are enabled
+	roles               model.RoleMapping
+	systemMessages      model.SystemMessageMode
+
+	// Azure OpenAI: deployment sent as the model, and optional bearer tokens
+	azure       bool
+	deployment  string
+	tokenSource TokenSource
+
+	// summaryUnavailable is set once the API rejects reasoning summaries
+	// (e.g., unverified organization) so later requests skip them.
+	summaryUnavailable atomic.Bool
 }
 
 // New creates a new OpenAI client.
 func New(cfg Config) (*Client, error) {
-	if cfg.APIKey == "" {
+	if cfg.APIKey == "" && (cfg.Azure == nil || cfg.Azure.TokenSource == nil) {
 		return nil, fmt.Errorf("API key is required")
 	}
 
@@ -155,15 +213,38 @@ func New(cfg Config) (*Client, error) {
 	baseURL = strings.TrimSuffix(baseURL, "/")
 
 	modelName := cfg.Model
+	var deployment string
+	var tokenSource TokenSource
+	if cfg.Azure != nil {
+		if cfg.Azure.Endpoint == "" {
+			return nil, fmt.Errorf("azure endpoint is required")
+		}
+		deployment = cfg.Azure.Deployment
+		if deployment == "" {
+			deployment = modelName
+		}
+		if deployment == "" {
+			return nil, fmt.Errorf("azure deployment is required")
+		}
+		if modelName == "" {
+			modelName = deployment
+		}
+		apiVersion := cfg.Azure.APIVersion
+		if apiVersion == "" {
+			apiVersion = DefaultAzureAPIVersion
+		}
+		baseURL = azureResponsesURL(cfg.Azure.Endpoint, apiVersion)
+		tokenSource = cfg.Azure.TokenSource
+	}
 	if modelName == "" {
 		modelName = defaultModel
 	}
 
 	maxTokens := cfg.MaxTokens
 
-	timeout := cfg.Timeout
-	if timeout == 0 {
-		timeout = defaultTimeout
+	connectTimeout := cfg.ConnectTimeout
+	if connectTimeout == 0 {
+		connectTimeout = defaultConnectTimeout
 	}
 
 	maxRetries := cfg.MaxRetries
@@ -172,7 +253,8 @@ func New(cfg Config) (*Client, error) {
 	}
 
 	httpClient := httpclient.New(
-		httpclient.WithHTTPClient(&http.Client{Timeout: timeout}),
+		httpclient.WithHTTPClient(&http.Client{}),
+		httpclient.WithConnectTimeout(connectTimeout),
 		httpclient.WithMaxRetries(maxRetries),
 		httpclient.WithHeaderParser(httpclient.ParseOpenAIHeaders),
 	)
@@ -182,8 +264,27 @@ func New(cfg Config) (*Client, error) {
 		reasoningBudget = 8192 // Default to medium
 	}
 
+	reasoningSummary := cfg.ReasoningSummary
+	if reasoningSummary == "" {
+		reasoningSummary = defaultReasoningSummary
+	}
+	if !isValidReasoningSummary(reasoningSummary) {
+		return nil, fmt.Errorf("invalid reasoning summary %q (valid: none, auto, concise, detailed)", reasoningSummary)
+	}
+
+	systemMessages := cfg.SystemMessages
+	if systemMessages == "" {
+		systemMessages = model.SystemMessagesNative
+	}
+
+	var responses *responseChain
+	if cfg.EnableStoredResponses {
+		responses = newResponseChain(cfg.StoredResponseTTL)
+	}
+
 	return &Client{
 		httpClient:          httpClient,
+		timeout:             cfg.Timeout,
 		apiKey:              cfg.APIKey,
 		baseURL:             baseURL,
 		modelName:           modelName,
@@ -192,6 +293,13 @@ func New(cfg Config) (*Client, error) {
 		temperature:         cfg.Temperature,
 		enableReasoning:     cfg.EnableReasoning,
 		reasoningBudget:     reasoningBudget,
+		reasoningSummary:    reasoningSummary,
+		responses:           responses,
+		roles:               cfg.Roles.WithDefaults(defaultRoles),
+		systemMessages:      systemMessages,
+		azure:               cfg.Azure != nil,
+		deployment:          deployment,
+		tokenSource:         tokenSource,
 	}, nil
 }
 
@@ -215,11 +323,21 @@ func (c *Client) Provider() model.Provider {
 //   - Yields multiple partial Responses (Partial=true) for real-time UI updates
 //   - Finally yields aggregated Response (Partial=false) for session persistence
 func (c *Client) GenerateContent(ctx context.Context, req *model.Request, stream bool) iter.Seq2[*model.Response, error] {
-	if stream {
-		return c.generateStream(ctx, req)
-	}
+	req = model.ApplySystemMessages(req, c.systemMessages)
 
 	return func(yield func(*model.Response, error) bool) {
+		ctx, cancel := model.WithRequestTimeout(ctx, c.timeout, defaultTimeout, stream)
+		defer cancel()
+
+		if stream {
+			for resp, err := range c.generateStream(ctx, req) {
+				if !yield(resp, err) {
+					return
+				}
+			}
+			return
+		}
+
 		resp, err := c.generate(ctx, req)
 		yield(resp, err)
 	}
@@ -233,7 +351,38 @@ func (c *Client) Close() error {
 // generate performs non-streaming generation.
 func (c *Client) generate(ctx context.Context, req *model.Request) (*model.Response, error) {
 	apiReq := c.buildRequest(req, false)
+	chained := c.chainRequest(apiReq, req)
+
+	apiResp, err := c.send(ctx, apiReq)
+	if err != nil && chained && isPreviousResponseNotFound(err.Error()) {
+		// Stored response expired or was deleted - resend full history
+		slog.Debug("Stored response unavailable, resending full history",
+			"conversation", req.ConversationID)
+		c.responses.forget(req.ConversationID)
+		apiReq = c.buildRequest(req, false)
+		c.chainRequest(apiReq, req)
+		apiResp, err = c.send(ctx, apiReq)
+	}
+	if err != nil && c.dropReasoningSummary(apiReq, err) {
+		apiResp, err = c.send(ctx, apiReq)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.parseResponse(apiResp)
+	if err != nil {
+		return nil, err
+	}
 
+	if c.responses != nil {
+		c.responses.remember(c, req.ConversationID, apiResp.ID, req.Messages)
+	}
+	return result, nil
+}
+
+// send performs a non-streaming Responses API call.
+func (c *Client) send(ctx context.Context, apiReq *responsesRequest) (*responsesResponse, error) {
 	body, err := json.Marshal(apiReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
@@ -244,7 +393,9 @@ func (c *Client) generate(ctx context.Context, req *model.Request) (*model.Respo
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	c.setHeaders(httpReq)
+	if err := c.setHeaders(httpReq); err != nil {
+		return nil, err
+	}
 
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
@@ -270,7 +421,7 @@ func (c *Client) generate(ctx context.Context, req *model.Request) (*model.Respo
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return c.parseResponse(&apiResp)
+	return &apiResp, nil
 }
 
 // streamState holds state accumulated during SSE streaming.
@@ -282,6 +433,7 @@ type streamState struct {
 	functionCallName  string
 	functionCallArgs  strings.Builder
 	totalTokens       int
+	responseID        string
 	emittedCallIDs    map[string]bool
 }
 
@@ -302,6 +454,36 @@ func (s *streamState) resetFunctionCall() {
 	s.functionCallArgs.Reset()
 }
 
+// pendingFunctionCall returns the function call still in progress when the
+// stream ended (no function_call_arguments.done or output_item.done).
+// Arguments cut off between values are completed; arguments cut off inside
+// a string, number or literal return an IncompleteToolCallError rather than
+// running the tool with a truncated value. ok is false if no call was pending.
+func (s *streamState) pendingFunctionCall() (tc tool.ToolCall, ok bool, err error) {
+	if s.functionCallID == "" || s.functionCallName == "" || s.emittedCallIDs[s.functionCallID] {
+		return tool.ToolCall{}, false, nil
+	}
+	defer s.resetFunctionCall()
+
+	argsStr := s.functionCallArgs.String()
+	args := make(map[string]any)
+	if strings.TrimSpace(argsStr) != "" {
+		repaired, valid := utils.RepairJSON(argsStr)
+		if !valid || json.Unmarshal([]byte(repaired), &args) != nil {
+			return tool.ToolCall{}, false, &model.IncompleteToolCallError{
+				ID:        s.functionCallID,
+				Name:      s.functionCallName,
+				Arguments: argsStr,
+			}
+		}
+	}
+	slog.Warn("Stream ended before tool call completed, emitting it anyway",
+		"tool", s.functionCallName, "call_id", s.functionCallID)
+
+	s.emittedCallIDs[s.functionCallID] = true
+	return tool.ToolCall{ID: s.functionCallID, Name: s.functionCallName, Args: args}, true, nil
+}
+
 // generateStream performs streaming generation with aggregator.
 // This is the ADK-Go aligned streaming pattern.
 func (c *Client) generateStream(ctx context.Context, req *model.Request) iter.Seq2[*model.Response, error] {
@@ -309,43 +491,27 @@ func (c *Client) generateStream(ctx context.Context, req *model.Request) iter.Se
 
 	return func(yield func(*model.Response, error) bool) {
 		apiReq := c.buildRequest(req, true)
-
-		body, err := json.Marshal(apiReq)
-		if err != nil {
-			yield(nil, fmt.Errorf("failed to marshal request: %w", err))
-			return
+		chained := c.chainRequest(apiReq, req)
+
+		resp, err := c.openStream(ctx, apiReq)
+		if err != nil && chained && isPreviousResponseNotFound(err.Error()) {
+			// Stored response expired or was deleted - resend full history
+			slog.Debug("Stored response unavailable, resending full history",
+				"conversation", req.ConversationID)
+			c.responses.forget(req.ConversationID)
+			apiReq = c.buildRequest(req, true)
+			c.chainRequest(apiReq, req)
+			resp, err = c.openStream(ctx, apiReq)
 		}
-
-		httpReq, err := http.NewRequestWithContext(ctx, "POST", c.responsesURL(), bytes.NewReader(body))
-		if err != nil {
-			yield(nil, fmt.Errorf("failed to create request: %w", err))
-			return
+		if err != nil && c.dropReasoningSummary(apiReq, err) {
+			resp, err = c.openStream(ctx, apiReq)
 		}
-
-		c.setHeaders(httpReq)
-
-		resp, err := c.httpClient.Do(httpReq)
 		if err != nil {
-			// Even on error, try to read the response body for better error messages
-			if resp != nil {
-				defer resp.Body.Close()
-				bodyBytes, _ := io.ReadAll(resp.Body)
-				if len(bodyBytes) > 0 {
-					yield(nil, fmt.Errorf("request failed: %w - response: %s", err, string(bodyBytes)))
-					return
-				}
-			}
-			yield(nil, fmt.Errorf("request failed: %w", err))
+			yield(nil, err)
 			return
 		}
 		defer resp.Body.Close()
 
-		if resp.StatusCode != http.StatusOK {
-			bodyBytes, _ := io.ReadAll(resp.Body)
-			yield(nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(bodyBytes)))
-			return
-		}
-
 		// Parse SSE stream
 		reader := bufio.NewReader(resp.Body)
 		state := newStreamState()
@@ -402,6 +568,22 @@ func (c *Client) generateStream(ctx context.Context, req *model.Request) iter.Se
 			}
 		}
 
+		// Emit a tool call whose arguments were still streaming at EOF
+		if tc, ok, err := state.pendingFunctionCall(); err != nil {
+			yield(nil, err)
+			return
+		} else if ok {
+			for resp, err := range aggregator.ProcessToolCall(tc) {
+				if !yield(resp, err) {
+					return
+				}
+			}
+		}
+
+		if c.responses != nil {
+			c.responses.remember(c, req.ConversationID, state.responseID, req.Messages)
+		}
+
 		// Update aggregator with final state
 		if state.totalTokens > 0 {
 			aggregator.SetUsage(&model.Usage{
@@ -416,6 +598,45 @@ func (c *Client) generateStream(ctx context.Context, req *model.Request) iter.Se
 	}
 }
 
+// openStream starts a streaming Responses API call.
+// On success the caller owns the returned response body.
+func (c *Client) openStream(ctx context.Context, apiReq *responsesRequest) (*http.Response, error) {
+	body, err := json.Marshal(apiReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.responsesURL(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if err := c.setHeaders(httpReq); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		// Even on error, try to read the response body for better error messages
+		if resp != nil {
+			defer resp.Body.Close()
+			bodyBytes, _ := io.ReadAll(resp.Body)
+			if len(bodyBytes) > 0 {
+				return nil, fmt.Errorf("request failed: %w - response: %s", err, string(bodyBytes))
+			}
+		}
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	return resp, nil
+}
+
 // processStreamEvent processes a single SSE event through the aggregator.
 func (c *Client) processStreamEvent(
 	event map[string]any,
@@ -604,6 +825,8 @@ func (c *Client) processStreamEvent(
 		case eventResponseCompleted:
 			// Extract usage
 			if response, ok := event["response"].(map[string]any); ok {
+				// Only completed responses are safe to chain from
+				state.responseID, _ = response["id"].(string)
 				if usage, ok := response["usage"].(map[string]any); ok {
 					if total, ok := usage["total_tokens"].(float64); ok {
 						state.totalTokens = int(total)
@@ -636,6 +859,9 @@ func (c *Client) processStreamEvent(
 
 // responsesURL returns the URL for the OpenAI Responses API.
 func (c *Client) responsesURL() string {
+	if c.azure {
+		return c.baseURL // already the full deployment URL
+	}
 	if strings.HasSuffix(c.baseURL, "/v1") {
 		return c.baseURL + "/responses"
 	}
@@ -643,9 +869,30 @@ func (c *Client) responsesURL() string {
 }
 
 // setHeaders sets the required HTTP headers.
-func (c *Client) setHeaders(req *http.Request) {
+func (c *Client) setHeaders(req *http.Request) error {
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+
+	switch {
+	case c.tokenSource != nil:
+		token, err := c.tokenSource(req.Context())
+		if err != nil {
+			return fmt.Errorf("failed to get auth token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	case c.azure:
+		req.Header.Set("api-key", c.apiKey)
+	default:
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	return nil
+}
+
+// requestModel returns the model sent to the API: the deployment on Azure.
+func (c *Client) requestModel() string {
+	if c.deployment != "" {
+		return c.deployment
+	}
+	return c.modelName
 }
 
 // buildRequest creates an API request from model.Request.
@@ -653,7 +900,7 @@ func (c *Client) buildRequest(req *model.Request, stream bool) *responsesRequest
 	enableReasoning := c.enableReasoning || (req.Config != nil && req.Config.EnableThinking)
 
 	apiReq := &responsesRequest{
-		Model:  c.modelName,
+		Model:  c.requestModel(),
 		Stream: stream,
 	}
 
@@ -679,7 +926,7 @@ func (c *Client) buildRequest(req *model.Request, stream bool) *responsesRequest
 
 		apiReq.Reasoning = &reasoningConfig{
 			Effort:  effort,
-			Summary: "auto",
+			Summary: c.resolveReasoningSummary(req.Config),
 		}
 		// Request encrypted content for multi-turn reasoning
 		apiReq.Include = []string{"reasoning.encrypted_content"}
@@ -763,7 +1010,7 @@ func (c *Client) convertMessages(messages []*a2a.Message) []inputItem {
 			if textContent != "" {
 				items = append(items, inputItem{
 					Type:    "message",
-					Role:    "assistant",
+					Role:    c.roles.Agent,
 					Content: []map[string]any{{"type": "output_text", "text": textContent}},
 				})
 			}
@@ -782,12 +1029,9 @@ func (c *Client) convertMessages(messages []*a2a.Message) []inputItem {
 		}
 
 		// Regular message
-		role := "user"
-		if msg.Role == a2a.MessageRoleAgent {
-			role = "assistant"
-		}
+		role := c.roles.Role(msg)
 
-		content := c.extractContent(msg, role)
+		content := c.extractContent(msg)
 		if len(content) > 0 {
 			items = append(items, inputItem{
 				Type:    "message",
@@ -801,12 +1045,12 @@ func (c *Client) convertMessages(messages []*a2a.Message) []inputItem {
 }
 
 // extractContent extracts content parts from a message.
-func (c *Client) extractContent(msg *a2a.Message, role string) []map[string]any {
+func (c *Client) extractContent(msg *a2a.Message) []map[string]any {
 	var parts []map[string]any
 
 	// Determine text content type based on role
 	textType := "input_text"
-	if role == "assistant" {
+	if msg.Role == a2a.MessageRoleAgent {
 		textType = "output_text"
 	}
 
@@ -1138,6 +1382,9 @@ type responsesRequest struct {
 	Include         []string         `json:"include,omitempty"`
 	Stream          bool             `json:"stream,omitempty"`
 	Text            *textFormat      `json:"text,omitempty"`
+
+	Store              *bool  `json:"store,omitempty"`
+	PreviousResponseID string `json:"previous_response_id,omitempty"`
 }
 
 type reasoningConfig struct {
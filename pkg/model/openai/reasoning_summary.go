@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openai
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/kadirpekel/hector/pkg/model"
+)
+
+// Reasoning summary verbosity levels for reasoning.summary.
+const (
+	ReasoningSummaryNone     = "none"
+	ReasoningSummaryAuto     = "auto"
+	ReasoningSummaryConcise  = "concise"
+	ReasoningSummaryDetailed = "detailed"
+
+	defaultReasoningSummary = ReasoningSummaryAuto
+)
+
+func isValidReasoningSummary(summary string) bool {
+	switch summary {
+	case ReasoningSummaryNone, ReasoningSummaryAuto, ReasoningSummaryConcise, ReasoningSummaryDetailed:
+		return true
+	}
+	return false
+}
+
+// resolveReasoningSummary returns the reasoning.summary value for a request.
+// A per-request setting overrides the client default; "none" and a previously
+// rejected summary both omit the field.
+func (c *Client) resolveReasoningSummary(cfg *model.GenerateConfig) string {
+	if c.summaryUnavailable.Load() {
+		return ""
+	}
+
+	summary := c.reasoningSummary
+	if cfg != nil && isValidReasoningSummary(cfg.ReasoningSummary) {
+		summary = cfg.ReasoningSummary
+	}
+	if summary == ReasoningSummaryNone {
+		return ""
+	}
+	return summary
+}
+
+// dropReasoningSummary removes reasoning.summary from apiReq when err shows
+// the API refused it, so the request can be retried without it. Returns true
+// if the request was changed.
+func (c *Client) dropReasoningSummary(apiReq *responsesRequest, err error) bool {
+	if apiReq.Reasoning == nil || apiReq.Reasoning.Summary == "" {
+		return false
+	}
+	if !isReasoningSummaryRejected(err.Error()) {
+		return false
+	}
+
+	slog.Warn("Reasoning summaries unavailable, retrying without them",
+		"model", c.modelName,
+		"summary", apiReq.Reasoning.Summary)
+	c.summaryUnavailable.Store(true)
+	apiReq.Reasoning.Summary = ""
+	return true
+}
+
+// isReasoningSummaryRejected reports whether an API error body indicates that
+// reasoning summaries are not available, typically because the organization
+// is not verified.
+func isReasoningSummaryRejected(body string) bool {
+	lower := strings.ToLower(body)
+	if !strings.Contains(lower, "summar") && !strings.Contains(lower, "reasoning") {
+		return false
+	}
+	return strings.Contains(lower, "verif") ||
+		(strings.Contains(lower, "reasoning.summary") && strings.Contains(lower, "unsupported"))
+}
@@ -0,0 +1,53 @@
+package openai
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/kadirpekel/hector/pkg/model"
+)
+
+func TestReasoningSummary(t *testing.T) {
+	client, err := New(Config{APIKey: "sk-test", Model: "o3-mini", EnableReasoning: true, ReasoningSummary: "concise"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	req := &model.Request{Messages: []*a2a.Message{a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "Hi"})}}
+	if got := client.buildRequest(req, false).Reasoning.Summary; got != "concise" {
+		t.Errorf("summary = %q, want concise", got)
+	}
+
+	req.Config = &model.GenerateConfig{ReasoningSummary: "detailed"}
+	apiReq := client.buildRequest(req, false)
+	if got := apiReq.Reasoning.Summary; got != "detailed" {
+		t.Errorf("per-request summary = %q, want detailed", got)
+	}
+
+	orgErr := errors.New(`API error (status 400): {"error":{"message":"Your organization must be verified to generate reasoning summaries."}}`)
+	if !client.dropReasoningSummary(apiReq, orgErr) {
+		t.Fatal("Expected summary to be dropped on verification error")
+	}
+	if apiReq.Reasoning.Summary != "" {
+		t.Errorf("summary = %q after drop, want empty", apiReq.Reasoning.Summary)
+	}
+	if got := client.buildRequest(req, false).Reasoning.Summary; got != "" {
+		t.Errorf("summary = %q on later request, want empty", got)
+	}
+}
+
+func TestReasoningSummaryNone(t *testing.T) {
+	client, err := New(Config{APIKey: "sk-test", Model: "o3-mini", EnableReasoning: true, ReasoningSummary: "none"})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	apiReq := client.buildRequest(&model.Request{}, false)
+	if apiReq.Reasoning == nil || apiReq.Reasoning.Summary != "" {
+		t.Errorf("Expected reasoning without summary, got %+v", apiReq.Reasoning)
+	}
+	if client.dropReasoningSummary(apiReq, errors.New("organization must be verified")) {
+		t.Error("Expected no retry when no summary was requested")
+	}
+}
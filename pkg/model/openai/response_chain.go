@@ -0,0 +1,164 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openai
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/model"
+)
+
+// defaultStoredResponseTTL matches OpenAI's retention period for stored responses.
+const defaultStoredResponseTTL = 30 * 24 * time.Hour
+
+// storedResponse records the last stored response of a conversation.
+type storedResponse struct {
+	id string
+
+	// inputLen is the number of messages sent (in full or chained) for the response.
+	inputLen int
+
+	// inputHash fingerprints those messages so history edits (trimming,
+	// summarization, injected context) invalidate the chain.
+	inputHash string
+
+	storedAt time.Time
+}
+
+// responseChain tracks stored responses per conversation so follow-up turns
+// can reference them via previous_response_id instead of resending history.
+type responseChain struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]*storedResponse
+}
+
+func newResponseChain(ttl time.Duration) *responseChain {
+	if ttl <= 0 {
+		ttl = defaultStoredResponseTTL
+	}
+	return &responseChain{
+		ttl:     ttl,
+		entries: make(map[string]*storedResponse),
+	}
+}
+
+// lookup returns the stored response ID and the messages that still need to be
+// sent, or ok=false when the full history must be sent.
+//
+// The chain is only reused when the conversation grew strictly by appending:
+// the previous input is unchanged and is followed by the model's own reply.
+func (rc *responseChain) lookup(c *Client, conversationID string, messages []*a2a.Message) (id string, tail []*a2a.Message, ok bool) {
+	if conversationID == "" {
+		return "", nil, false
+	}
+
+	rc.mu.Lock()
+	entry, found := rc.entries[conversationID]
+	if found && time.Since(entry.storedAt) > rc.ttl {
+		delete(rc.entries, conversationID)
+		found = false
+	}
+	rc.mu.Unlock()
+
+	if !found || len(messages) <= entry.inputLen+1 {
+		return "", nil, false
+	}
+
+	reply := messages[entry.inputLen]
+	if reply == nil || reply.Role != a2a.MessageRoleAgent {
+		return "", nil, false
+	}
+
+	if c.hashMessages(messages[:entry.inputLen]) != entry.inputHash {
+		return "", nil, false
+	}
+
+	return entry.id, messages[entry.inputLen+1:], true
+}
+
+// remember records the stored response for a conversation.
+func (rc *responseChain) remember(c *Client, conversationID, responseID string, messages []*a2a.Message) {
+	if conversationID == "" || responseID == "" {
+		return
+	}
+
+	entry := &storedResponse{
+		id:        responseID,
+		inputLen:  len(messages),
+		inputHash: c.hashMessages(messages),
+		storedAt:  time.Now(),
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	// Drop expired entries so long-running servers don't accumulate dead sessions
+	for key, e := range rc.entries {
+		if time.Since(e.storedAt) > rc.ttl {
+			delete(rc.entries, key)
+		}
+	}
+	rc.entries[conversationID] = entry
+}
+
+// forget drops the stored response for a conversation.
+func (rc *responseChain) forget(conversationID string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	delete(rc.entries, conversationID)
+}
+
+// hashMessages fingerprints messages using their wire representation.
+func (c *Client) hashMessages(messages []*a2a.Message) string {
+	data, _ := json.Marshal(c.convertMessages(messages))
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// chainRequest rewrites apiReq to continue from the conversation's stored
+// response when possible. Returns true if the request was chained.
+func (c *Client) chainRequest(apiReq *responsesRequest, req *model.Request) bool {
+	if c.responses == nil {
+		return false
+	}
+
+	store := true
+	apiReq.Store = &store
+
+	id, tail, ok := c.responses.lookup(c, req.ConversationID, req.Messages)
+	if !ok {
+		return false
+	}
+
+	apiReq.PreviousResponseID = id
+	apiReq.Input = c.convertMessages(tail)
+	return true
+}
+
+// isPreviousResponseNotFound reports whether an API error body indicates that
+// the referenced stored response expired or was deleted.
+func isPreviousResponseNotFound(body string) bool {
+	return strings.Contains(body, "previous_response_not_found") ||
+		(strings.Contains(body, "previous_response_id") && strings.Contains(body, "not found"))
+}
@@ -0,0 +1,73 @@
+package openai
+
+import (
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/kadirpekel/hector/pkg/model"
+)
+
+func TestChainRequestSendsOnlyNewMessages(t *testing.T) {
+	client, err := New(Config{APIKey: "sk-test", Model: "gpt-4o", EnableStoredResponses: true})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	first := []*a2a.Message{
+		a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "Hi"}),
+	}
+	client.responses.remember(client, "s1:agent", "resp_1", first)
+
+	followUp := append(first,
+		a2a.NewMessage(a2a.MessageRoleAgent, a2a.TextPart{Text: "Hello!"}),
+		a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "How are you?"}),
+	)
+	req := &model.Request{Messages: followUp, ConversationID: "s1:agent"}
+
+	apiReq := client.buildRequest(req, false)
+	if !client.chainRequest(apiReq, req) {
+		t.Fatal("Expected request to be chained")
+	}
+	if apiReq.PreviousResponseID != "resp_1" {
+		t.Errorf("PreviousResponseID = %q, want resp_1", apiReq.PreviousResponseID)
+	}
+	inputs := apiReq.Input.([]inputItem)
+	if len(inputs) != 1 || inputs[0].Content[0]["text"] != "How are you?" {
+		t.Errorf("Expected only the new user message, got %+v", inputs)
+	}
+	if apiReq.Store == nil || !*apiReq.Store {
+		t.Error("Expected store to be enabled")
+	}
+}
+
+func TestChainRequestFallsBackWhenHistoryChanged(t *testing.T) {
+	client, err := New(Config{APIKey: "sk-test", Model: "gpt-4o", EnableStoredResponses: true})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	client.responses.remember(client, "s1:agent", "resp_1", []*a2a.Message{
+		a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "Hi"}),
+	})
+
+	// History was rewritten (e.g., trimmed), so the stored prefix no longer matches
+	req := &model.Request{
+		ConversationID: "s1:agent",
+		Messages: []*a2a.Message{
+			a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "Summary of earlier turns"}),
+			a2a.NewMessage(a2a.MessageRoleAgent, a2a.TextPart{Text: "Hello!"}),
+			a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "How are you?"}),
+		},
+	}
+
+	apiReq := client.buildRequest(req, false)
+	if client.chainRequest(apiReq, req) {
+		t.Fatal("Expected full history resend")
+	}
+	if apiReq.PreviousResponseID != "" {
+		t.Errorf("PreviousResponseID = %q, want empty", apiReq.PreviousResponseID)
+	}
+	if got := len(apiReq.Input.([]inputItem)); got != 3 {
+		t.Errorf("Expected 3 input items, got %d", got)
+	}
+}
@@ -0,0 +1,36 @@
+package openai
+
+import (
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/kadirpekel/hector/pkg/model"
+)
+
+func TestSystemMessageRoles(t *testing.T) {
+	client, err := New(Config{
+		APIKey: "sk-test",
+		Roles:  model.RoleMapping{System: "developer"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	req := model.ApplySystemMessages(&model.Request{
+		Messages: []*a2a.Message{
+			a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "Hi"}),
+			model.NewSystemMessage(a2a.TextPart{Text: "The user is now verified."}),
+		},
+	}, client.systemMessages)
+
+	inputs := client.buildRequest(req, false).Input.([]inputItem)
+	if len(inputs) != 2 {
+		t.Fatalf("Expected 2 input items, got %d", len(inputs))
+	}
+	if inputs[1].Role != "developer" {
+		t.Errorf("system message role = %q, want developer", inputs[1].Role)
+	}
+	if inputs[1].Content[0]["type"] != "input_text" {
+		t.Errorf("system message content type = %v, want input_text", inputs[1].Content[0]["type"])
+	}
+}
@@ -0,0 +1,109 @@
+package openai
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/kadirpekel/hector/pkg/model"
+)
+
+// truncatedToolCallStream streams a function call whose arguments stop at
+// args, then closes the stream without function_call_arguments.done.
+func truncatedToolCallStream(args string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: response.output_item.added\n")
+		fmt.Fprint(w, `data: {"item":{"type":"function_call","call_id":"call_1","name":"read_file"}}`+"\n\n")
+		for _, chunk := range strings.SplitAfter(args, ",") {
+			data, _ := json.Marshal(chunk)
+			fmt.Fprint(w, "event: response.function_call_arguments.delta\n")
+			fmt.Fprintf(w, "data: {\"delta\":%s}\n\n", data)
+		}
+	}
+}
+
+func streamToolCalls(t *testing.T, args string) (*model.Response, error) {
+	t.Helper()
+	server := httptest.NewServer(truncatedToolCallStream(args))
+	defer server.Close()
+
+	client, err := New(Config{APIKey: "sk-test", Model: "gpt-4o", BaseURL: server.URL})
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	req := &model.Request{Messages: []*a2a.Message{
+		a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "read it"}),
+	}}
+	var final *model.Response
+	for resp, err := range client.GenerateContent(context.Background(), req, true) {
+		if err != nil {
+			return nil, err
+		}
+		if resp != nil && !resp.Partial {
+			final = resp
+		}
+	}
+	return final, nil
+}
+
+func TestStreamEmitsToolCallTruncatedAtEOF(t *testing.T) {
+	tests := []struct {
+		name string
+		args string
+		want map[string]any
+	}{
+		{"complete", `{"path":"/tmp/a.txt"}`, map[string]any{"path": "/tmp/a.txt"}},
+		{"after comma", `{"path":"/tmp/a.txt",`, map[string]any{"path": "/tmp/a.txt"}},
+		{"after string value", `{"path":"/tmp/a.txt","lines":[1,2]`, map[string]any{"path": "/tmp/a.txt", "lines": []any{1.0, 2.0}}},
+		{"no arguments", ``, map[string]any{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			final, err := streamToolCalls(t, tt.args)
+			if err != nil {
+				t.Fatalf("GenerateContent() error = %v", err)
+			}
+			if final == nil || len(final.ToolCalls) != 1 {
+				t.Fatalf("expected 1 tool call, got %+v", final)
+			}
+			tc := final.ToolCalls[0]
+			if tc.ID != "call_1" || tc.Name != "read_file" {
+				t.Errorf("tool call = %s/%s", tc.ID, tc.Name)
+			}
+			if fmt.Sprint(tc.Args) != fmt.Sprint(tt.want) {
+				t.Errorf("args = %v, want %v", tc.Args, tt.want)
+			}
+		})
+	}
+}
+
+func TestStreamReportsUnrepairableToolCall(t *testing.T) {
+	// Completing any of these would run the tool with an invented or
+	// truncated value, e.g. a cut-off path
+	for _, args := range []string{
+		`{"path":}`,
+		`{"path":"/tmp/a.txt","pattern":"TODO`,
+		`{"path":"/tmp/a.txt","limit":1`,
+		`{"path":"/tmp/a.txt","recursive":fal`,
+		`{"path":`,
+	} {
+		_, err := streamToolCalls(t, args)
+
+		var incomplete *model.IncompleteToolCallError
+		if !errors.As(err, &incomplete) {
+			t.Errorf("%s: error = %v, want IncompleteToolCallError", args, err)
+			continue
+		}
+		if incomplete.Name != "read_file" || incomplete.Arguments != args {
+			t.Errorf("%s: error = %+v", args, incomplete)
+		}
+	}
+}
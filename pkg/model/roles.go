@@ -0,0 +1,220 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"strings"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+// SystemMessageMode controls how system messages found in the conversation
+// history are sent to a provider.
+//
+// A system message is an a2a.Message with an unspecified role that is
+// explicitly tagged as one (see NewSystemMessage). System messages that precede the conversation are always
+// merged into the system instruction; the mode applies to those that arrive
+// after the first turn.
+type SystemMessageMode string
+
+const (
+	// SystemMessagesMerge appends system messages to the system instruction.
+	// Works with every provider, but loses their position in the conversation.
+	SystemMessagesMerge SystemMessageMode = "merge"
+
+	// SystemMessagesUser sends system messages in place as user messages.
+	SystemMessagesUser SystemMessageMode = "user"
+
+	// SystemMessagesNative sends system messages in place using the
+	// provider's system role. Only for providers that accept it mid-conversation.
+	SystemMessagesNative SystemMessageMode = "native"
+)
+
+// systemMessagePrefix labels system messages sent with the user role.
+const systemMessagePrefix = "[System]\n"
+
+// MetaKeySystemMessage tags a message as a system message. Only server-side
+// code sets it; inbound messages must be normalized with NormalizeInbound so
+// clients cannot promote their text into the system instruction.
+const MetaKeySystemMessage = "hector:system"
+
+// RoleMapping maps message roles to the role names a provider expects.
+// Empty fields fall back to the provider's defaults.
+type RoleMapping struct {
+	// User is the role for user messages (e.g., "user").
+	User string
+
+	// Agent is the role for agent messages (e.g., "assistant", "model").
+	Agent string
+
+	// System is the role for system messages sent in native mode
+	// (e.g., "system", "developer").
+	System string
+}
+
+// WithDefaults returns the mapping with empty fields taken from defaults.
+func (m RoleMapping) WithDefaults(defaults RoleMapping) RoleMapping {
+	if m.User == "" {
+		m.User = defaults.User
+	}
+	if m.Agent == "" {
+		m.Agent = defaults.Agent
+	}
+	if m.System == "" {
+		m.System = defaults.System
+	}
+	return m
+}
+
+// Role returns the provider role for a message.
+func (m RoleMapping) Role(msg *a2a.Message) string {
+	switch {
+	case msg.Role == a2a.MessageRoleAgent:
+		return m.Agent
+	case IsSystemMessage(msg) && m.System != "":
+		return m.System
+	default:
+		return m.User
+	}
+}
+
+// NewSystemMessage creates a system message.
+func NewSystemMessage(parts ...a2a.Part) *a2a.Message {
+	msg := a2a.NewMessage(a2a.MessageRoleUnspecified, parts...)
+	msg.Metadata = map[string]any{MetaKeySystemMessage: true}
+	return msg
+}
+
+// IsSystemMessage reports whether msg is a system message: it has no role
+// and is tagged by NewSystemMessage. Untagged messages without a role are
+// treated as user messages.
+func IsSystemMessage(msg *a2a.Message) bool {
+	if msg == nil || msg.Role != a2a.MessageRoleUnspecified {
+		return false
+	}
+	tagged, _ := msg.Metadata[MetaKeySystemMessage].(bool)
+	return tagged
+}
+
+// NormalizeInbound returns a copy of a message received from a client with
+// the user role and without the system message tag, so that client text is
+// never treated as a system message. msg is not modified.
+func NormalizeInbound(msg *a2a.Message) *a2a.Message {
+	if msg == nil {
+		return nil
+	}
+	out := *msg
+	out.Role = a2a.MessageRoleUser
+	if _, ok := msg.Metadata[MetaKeySystemMessage]; ok {
+		out.Metadata = make(map[string]any, len(msg.Metadata)-1)
+		for k, v := range msg.Metadata {
+			if k != MetaKeySystemMessage {
+				out.Metadata[k] = v
+			}
+		}
+	}
+	return &out
+}
+
+// ApplySystemMessages rewrites the system messages of req according to mode
+// and returns the resulting request. req is not modified; it is returned
+// unchanged when it has no system messages.
+//
+// Leading system messages are merged into the system instruction. Later ones
+// are merged (SystemMessagesMerge), turned into user messages
+// (SystemMessagesUser) or kept for the provider to send with its system role
+// (SystemMessagesNative). An empty mode means SystemMessagesMerge.
+func ApplySystemMessages(req *Request, mode SystemMessageMode) *Request {
+	if req == nil {
+		return nil
+	}
+
+	hasSystem := false
+	for _, msg := range req.Messages {
+		if IsSystemMessage(msg) {
+			hasSystem = true
+			break
+		}
+	}
+	if !hasSystem {
+		return req
+	}
+
+	instructions := []string{}
+	if req.SystemInstruction != "" {
+		instructions = append(instructions, req.SystemInstruction)
+	}
+
+	messages := make([]*a2a.Message, 0, len(req.Messages))
+	leading := true
+	for _, msg := range req.Messages {
+		if !IsSystemMessage(msg) {
+			if msg != nil {
+				leading = false
+			}
+			messages = append(messages, msg)
+			continue
+		}
+
+		switch {
+		case leading || mode == "" || mode == SystemMessagesMerge:
+			if text := messageText(msg); text != "" {
+				instructions = append(instructions, text)
+			}
+		case mode == SystemMessagesUser:
+			messages = append(messages, systemAsUserMessage(msg))
+		default:
+			messages = append(messages, msg)
+		}
+	}
+
+	out := *req
+	out.SystemInstruction = strings.Join(instructions, "\n\n")
+	out.Messages = messages
+	return &out
+}
+
+// systemAsUserMessage converts a system message to a labeled user message.
+func systemAsUserMessage(msg *a2a.Message) *a2a.Message {
+	parts := make([]a2a.Part, 0, len(msg.Parts)+1)
+	parts = append(parts, a2a.TextPart{Text: systemMessagePrefix + messageText(msg)})
+	for _, part := range msg.Parts {
+		switch part.(type) {
+		case a2a.TextPart, *a2a.TextPart:
+		default:
+			parts = append(parts, part)
+		}
+	}
+	return a2a.NewMessage(a2a.MessageRoleUser, parts...)
+}
+
+// messageText joins the text parts of a message.
+func messageText(msg *a2a.Message) string {
+	var texts []string
+	for _, part := range msg.Parts {
+		switch p := part.(type) {
+		case a2a.TextPart:
+			if p.Text != "" {
+				texts = append(texts, p.Text)
+			}
+		case *a2a.TextPart:
+			if p != nil && p.Text != "" {
+				texts = append(texts, p.Text)
+			}
+		}
+	}
+	return strings.Join(texts, "\n")
+}
@@ -0,0 +1,253 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package model
+
+import (
+	"context"
+	"iter"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultShadowTimeout bounds how long a shadow call may run after the
+	// primary request has finished.
+	defaultShadowTimeout = 2 * time.Minute
+
+	// maxConcurrentShadowCalls caps in-flight shadow calls per wrapper.
+	// Requests beyond the cap skip the shadow call rather than queue.
+	maxConcurrentShadowCalls = 8
+)
+
+// ShadowMetrics records shadow call metrics.
+// observability.Recorder satisfies this interface.
+type ShadowMetrics interface {
+	RecordLLMCall(ctx context.Context, model, provider string, duration time.Duration)
+	RecordLLMTokens(ctx context.Context, model, provider string, inputTokens, outputTokens int)
+	RecordLLMError(ctx context.Context, model, provider, errorType string)
+}
+
+// ShadowConfig configures a ShadowLLM.
+type ShadowConfig struct {
+	// Similarity adds a word-overlap similarity score (0-1) between the
+	// primary and shadow outputs to the comparison log.
+	Similarity bool
+
+	// Timeout bounds each shadow call. Default: 2m.
+	Timeout time.Duration
+
+	// Metrics records shadow calls (optional).
+	Metrics ShadowMetrics
+}
+
+// ShadowLLM serves responses from a primary model while mirroring each
+// request to a shadow model in the background.
+//
+// The shadow's output is never returned. Once both calls finish, latency,
+// token usage and (optionally) output similarity are logged so the models
+// can be compared on real traffic before migrating.
+type ShadowLLM struct {
+	primary LLM
+	shadow  LLM
+	cfg     ShadowConfig
+	slots   chan struct{}
+}
+
+// NewShadowLLM wraps primary so every request is mirrored to shadow.
+func NewShadowLLM(primary, shadow LLM, cfg ShadowConfig) *ShadowLLM {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultShadowTimeout
+	}
+	return &ShadowLLM{
+		primary: primary,
+		shadow:  shadow,
+		cfg:     cfg,
+		slots:   make(chan struct{}, maxConcurrentShadowCalls),
+	}
+}
+
+// Name returns the primary model name.
+func (s *ShadowLLM) Name() string {
+	return s.primary.Name()
+}
+
+// Provider returns the primary model provider.
+func (s *ShadowLLM) Provider() Provider {
+	return s.primary.Provider()
+}
+
+// shadowOutcome is the result of one side of a shadow comparison.
+type shadowOutcome struct {
+	latency time.Duration
+	resp    *Response
+	err     error
+}
+
+// GenerateContent streams the primary model's responses unchanged and runs
+// the shadow model concurrently without affecting the caller.
+func (s *ShadowLLM) GenerateContent(ctx context.Context, req *Request, stream bool) iter.Seq2[*Response, error] {
+	return func(yield func(*Response, error) bool) {
+		var shadowDone chan shadowOutcome
+		select {
+		case s.slots <- struct{}{}:
+			shadowDone = make(chan shadowOutcome, 1)
+			go s.runShadow(context.WithoutCancel(ctx), req, shadowDone)
+		default:
+			slog.Debug("Skipping shadow call, too many in flight", "shadow", s.shadow.Name())
+		}
+
+		start := time.Now()
+		primary := shadowOutcome{}
+		for resp, err := range s.primary.GenerateContent(ctx, req, stream) {
+			if err != nil {
+				primary.err = err
+			} else if resp != nil && !resp.Partial {
+				primary.resp = resp
+			}
+			if !yield(resp, err) {
+				break
+			}
+		}
+		primary.latency = time.Since(start)
+
+		if shadowDone != nil {
+			go s.compare(primary, shadowDone)
+		}
+	}
+}
+
+// runShadow calls the shadow model and reports its outcome.
+func (s *ShadowLLM) runShadow(ctx context.Context, req *Request, done chan<- shadowOutcome) {
+	defer func() { <-s.slots }()
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	outcome := shadowOutcome{}
+	for resp, err := range s.shadow.GenerateContent(ctx, req, false) {
+		if err != nil {
+			outcome.err = err
+			break
+		}
+		if resp != nil && !resp.Partial {
+			outcome.resp = resp
+		}
+	}
+	outcome.latency = time.Since(start)
+
+	if m := s.cfg.Metrics; m != nil {
+		provider := string(s.shadow.Provider())
+		m.RecordLLMCall(ctx, s.shadow.Name(), provider, outcome.latency)
+		if outcome.err != nil {
+			m.RecordLLMError(ctx, s.shadow.Name(), provider, "shadow")
+		} else if outcome.resp != nil && outcome.resp.Usage != nil {
+			m.RecordLLMTokens(ctx, s.shadow.Name(), provider, outcome.resp.Usage.PromptTokens, outcome.resp.Usage.CompletionTokens)
+		}
+	}
+
+	done <- outcome
+}
+
+// compare logs the difference between the primary and shadow outcomes.
+func (s *ShadowLLM) compare(primary shadowOutcome, shadowDone <-chan shadowOutcome) {
+	shadow := <-shadowDone
+
+	attrs := []any{
+		"primary", s.primary.Name(),
+		"shadow", s.shadow.Name(),
+		"primary_latency_ms", primary.latency.Milliseconds(),
+		"shadow_latency_ms", shadow.latency.Milliseconds(),
+		"latency_delta_ms", (shadow.latency - primary.latency).Milliseconds(),
+	}
+
+	if primary.err != nil || shadow.err != nil {
+		attrs = append(attrs, "primary_error", errString(primary.err), "shadow_error", errString(shadow.err))
+		slog.Warn("Shadow model comparison failed", attrs...)
+		return
+	}
+
+	primaryTokens, shadowTokens := completionTokens(primary.resp), completionTokens(shadow.resp)
+	attrs = append(attrs,
+		"primary_tokens", primaryTokens,
+		"shadow_tokens", shadowTokens,
+		"token_delta", shadowTokens-primaryTokens,
+	)
+	if s.cfg.Similarity {
+		attrs = append(attrs, "similarity", Similarity(responseText(primary.resp), responseText(shadow.resp)))
+	}
+	slog.Info("Shadow model comparison", attrs...)
+}
+
+// Close closes the primary model. The shadow model is owned by its own
+// registration and closed there.
+func (s *ShadowLLM) Close() error {
+	return s.primary.Close()
+}
+
+// Similarity returns the Jaccard similarity (0-1) of the word sets of a and b.
+func Similarity(a, b string) float64 {
+	wordsA, wordsB := wordSet(a), wordSet(b)
+	if len(wordsA) == 0 && len(wordsB) == 0 {
+		return 1
+	}
+
+	shared := 0
+	for w := range wordsA {
+		if wordsB[w] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(wordsA)+len(wordsB)-shared)
+}
+
+func wordSet(s string) map[string]bool {
+	words := make(map[string]bool)
+	for _, w := range strings.Fields(strings.ToLower(s)) {
+		words[w] = true
+	}
+	return words
+}
+
+// responseText returns the text and tool call names of a response.
+func responseText(resp *Response) string {
+	if resp == nil {
+		return ""
+	}
+	parts := []string{resp.TextContent()}
+	for _, tc := range resp.ToolCalls {
+		parts = append(parts, tc.Name)
+	}
+	return strings.Join(parts, " ")
+}
+
+func completionTokens(resp *Response) int {
+	if resp == nil || resp.Usage == nil {
+		return 0
+	}
+	return resp.Usage.CompletionTokens
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// Ensure ShadowLLM implements LLM.
+var _ LLM = (*ShadowLLM)(nil)
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"context"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+type fakeLLM struct {
+	name  string
+	text  string
+	calls chan *Request
+}
+
+func (f *fakeLLM) Name() string       { return f.name }
+func (f *fakeLLM) Provider() Provider { return ProviderOpenAI }
+func (f *fakeLLM) Close() error       { return nil }
+
+func (f *fakeLLM) GenerateContent(ctx context.Context, req *Request, stream bool) iter.Seq2[*Response, error] {
+	return func(yield func(*Response, error) bool) {
+		if f.calls != nil {
+			f.calls <- req
+		}
+		yield(&Response{
+			Content: &Content{Parts: []a2a.Part{a2a.TextPart{Text: f.text}}},
+			Usage:   &Usage{CompletionTokens: 3},
+		}, nil)
+	}
+}
+
+func TestShadowLLMReturnsPrimaryAndMirrorsRequest(t *testing.T) {
+	primary := &fakeLLM{name: "a", text: "from primary"}
+	shadow := &fakeLLM{name: "b", text: "from shadow", calls: make(chan *Request, 1)}
+	llm := NewShadowLLM(primary, shadow, ShadowConfig{Similarity: true})
+
+	req := &Request{}
+	var got []string
+	for resp, err := range llm.GenerateContent(context.Background(), req, false) {
+		if err != nil {
+			t.Fatalf("GenerateContent() error = %v", err)
+		}
+		got = append(got, resp.TextContent())
+	}
+	if len(got) != 1 || got[0] != "from primary" {
+		t.Errorf("responses = %v, want [from primary]", got)
+	}
+
+	select {
+	case mirrored := <-shadow.calls:
+		if mirrored != req {
+			t.Error("shadow received a different request")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("shadow model was not called")
+	}
+
+	if llm.Name() != "a" {
+		t.Errorf("Name() = %q, want a", llm.Name())
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"", "", 1},
+		{"the cat sat", "The cat sat", 1},
+		{"the cat", "a dog", 0},
+		{"one two three", "one two four", 0.5},
+	}
+	for _, tt := range tests {
+		if got := Similarity(tt.a, tt.b); got != tt.want {
+			t.Errorf("Similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
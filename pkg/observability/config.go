@@ -17,6 +17,9 @@ package observability
 
 import (
 	"fmt"
+	"os"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -27,6 +30,54 @@ type Config struct {
 
 	// Metrics configures Prometheus metrics collection.
 	Metrics MetricsConfig `yaml:"metrics,omitempty"`
+
+	// Langfuse exports full LLM and tool call payloads to Langfuse.
+	Langfuse *LangfuseConfig `yaml:"langfuse,omitempty"`
+}
+
+// LangfuseConfig configures export of agent LLM and tool calls to Langfuse.
+// Each agent run becomes a trace with a generation per model call and a
+// span per tool call, including full prompts and responses.
+type LangfuseConfig struct {
+	// Enabled turns on the export.
+	// Default: false
+	Enabled bool `yaml:"enabled,omitempty"`
+
+	// Host is the Langfuse base URL.
+	// Default: $LANGFUSE_HOST, then https://cloud.langfuse.com
+	Host string `yaml:"host,omitempty"`
+
+	// PublicKey and SecretKey authenticate with the Langfuse project.
+	// Default: $LANGFUSE_PUBLIC_KEY and $LANGFUSE_SECRET_KEY
+	PublicKey string `yaml:"public_key,omitempty"`
+	SecretKey string `yaml:"secret_key,omitempty" sensitive:"true"`
+
+	// Agents limits the export to these agents. Empty exports all LLM
+	// agents.
+	Agents []string `yaml:"agents,omitempty"`
+
+	// RedactKeys are names of secret fields (e.g., "api_key", "password")
+	// whose values are removed from exported prompts, responses and tool
+	// arguments.
+	RedactKeys []string `yaml:"redact_keys,omitempty"`
+
+	// FlushInterval is how often queued events are sent.
+	// Default: 5s
+	FlushInterval time.Duration `yaml:"flush_interval,omitempty"`
+
+	// BatchSize is the most events sent in one request.
+	// Default: 50
+	BatchSize int `yaml:"batch_size,omitempty"`
+}
+
+// IsEnabled returns whether Langfuse export is enabled.
+func (c *LangfuseConfig) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// ExportsAgent reports whether calls of the named agent are exported.
+func (c *LangfuseConfig) ExportsAgent(name string) bool {
+	return c.IsEnabled() && (len(c.Agents) == 0 || slices.Contains(c.Agents, name))
 }
 
 // TracingConfig configures OpenTelemetry tracing.
@@ -36,11 +87,14 @@ type TracingConfig struct {
 	Enabled bool `yaml:"enabled,omitempty"`
 
 	// Exporter specifies the trace exporter type.
-	// Values: "otlp" (default), "jaeger", "zipkin", "stdout"
+	// Values: "otlp" (default, OTLP over gRPC), "otlphttp" (OTLP over
+	// HTTP), "jaeger", "zipkin", "stdout"
 	Exporter string `yaml:"exporter,omitempty"`
 
 	// Endpoint is the collector endpoint.
-	// For OTLP: "localhost:4317" (gRPC) or "localhost:4318" (HTTP)
+	// For OTLP: "localhost:4317" (gRPC)
+	// For OTLP/HTTP: "localhost:4318", or a URL such as
+	// "https://collector.example.com/v1/traces" (default path: /v1/traces)
 	// For Jaeger: "http://localhost:14268/api/traces"
 	// For Zipkin: "http://localhost:9411/api/pkg/spans"
 	Endpoint string `yaml:"endpoint,omitempty"`
@@ -57,12 +111,23 @@ type TracingConfig struct {
 	// ServiceVersion is the version of this service.
 	ServiceVersion string `yaml:"service_version,omitempty"`
 
-	// Insecure disables TLS for the exporter connection.
-	// Default: true (for local development)
+	// Insecure disables TLS for the exporter connection. For OTLP/HTTP
+	// endpoints given as URLs, the scheme decides instead.
+	// Default: true (for local development), false if TLS files are set
 	Insecure *bool `yaml:"insecure,omitempty"`
 
-	// Headers are additional headers to send with export requests.
-	Headers map[string]string `yaml:"headers,omitempty"`
+	// CAFile is a PEM file of CAs that verify the collector's certificate.
+	// Default: system roots
+	CAFile string `yaml:"ca_file,omitempty"`
+
+	// CertFile and KeyFile are a PEM client certificate and key for mutual
+	// TLS with the collector.
+	CertFile string `yaml:"cert_file,omitempty"`
+	KeyFile  string `yaml:"key_file,omitempty"`
+
+	// Headers are additional headers to send with export requests
+	// (e.g., an Authorization header for the collector).
+	Headers map[string]string `yaml:"headers,omitempty" sensitive:"true"`
 
 	// CapturePayloads enables capturing full LLM request/response in spans.
 	// Warning: This can produce large spans. Use only for debugging.
@@ -73,6 +138,11 @@ type TracingConfig struct {
 	// Default: true (when tracing is enabled)
 	DebugExporter *bool `yaml:"debug_exporter,omitempty"`
 
+	// Correlation adds the session, user and task IDs of the request to
+	// every span, so a trace can be found from a conversation.
+	// Default: true
+	Correlation *bool `yaml:"correlation,omitempty"`
+
 	// Timeout for exporter operations.
 	// Default: 10s
 	Timeout time.Duration `yaml:"timeout,omitempty"`
@@ -99,12 +169,21 @@ type MetricsConfig struct {
 
 	// ConstLabels are labels added to all metrics.
 	ConstLabels map[string]string `yaml:"const_labels,omitempty"`
+
+	// SessionLabels adds a session_id label to LLM call, token and cost
+	// metrics. Every session creates new series, so only enable it when
+	// sessions are few or the metrics backend handles high cardinality.
+	// Default: false
+	SessionLabels bool `yaml:"session_labels,omitempty"`
 }
 
 // SetDefaults applies default values to Config.
 func (c *Config) SetDefaults() {
 	c.Tracing.SetDefaults()
 	c.Metrics.SetDefaults()
+	if c.Langfuse.IsEnabled() {
+		c.Langfuse.SetDefaults()
+	}
 }
 
 // Validate checks the Config for errors.
@@ -115,6 +194,44 @@ func (c *Config) Validate() error {
 	if err := c.Metrics.Validate(); err != nil {
 		return fmt.Errorf("metrics: %w", err)
 	}
+	if c.Langfuse.IsEnabled() {
+		if err := c.Langfuse.Validate(); err != nil {
+			return fmt.Errorf("langfuse: %w", err)
+		}
+	}
+	return nil
+}
+
+// SetDefaults applies default values to LangfuseConfig.
+func (c *LangfuseConfig) SetDefaults() {
+	if c.Host == "" {
+		c.Host = os.Getenv("LANGFUSE_HOST")
+	}
+	if c.PublicKey == "" {
+		c.PublicKey = os.Getenv("LANGFUSE_PUBLIC_KEY")
+	}
+	if c.SecretKey == "" {
+		c.SecretKey = os.Getenv("LANGFUSE_SECRET_KEY")
+	}
+	if c.FlushInterval == 0 {
+		c.FlushInterval = 5 * time.Second
+	}
+	if c.BatchSize == 0 {
+		c.BatchSize = 50
+	}
+}
+
+// Validate checks LangfuseConfig for errors.
+func (c *LangfuseConfig) Validate() error {
+	if c.PublicKey == "" || c.SecretKey == "" {
+		return fmt.Errorf("public_key and secret_key are required (or set LANGFUSE_PUBLIC_KEY and LANGFUSE_SECRET_KEY)")
+	}
+	if c.FlushInterval < 0 {
+		return fmt.Errorf("flush_interval must be non-negative")
+	}
+	if c.BatchSize < 0 {
+		return fmt.Errorf("batch_size must be non-negative")
+	}
 	return nil
 }
 
@@ -130,10 +247,14 @@ func (c *TracingConfig) SetDefaults() {
 		c.Exporter = "otlp"
 	}
 	if c.Endpoint == "" {
-		c.Endpoint = DefaultOTLPEndpoint
+		if c.Exporter == "otlphttp" {
+			c.Endpoint = DefaultOTLPHTTPEndpoint
+		} else {
+			c.Endpoint = DefaultOTLPEndpoint
+		}
 	}
 	if c.Insecure == nil {
-		insecure := true
+		insecure := !c.hasTLSFiles()
 		c.Insecure = &insecure
 	}
 	if c.DebugExporter == nil && c.Enabled {
@@ -160,15 +281,27 @@ func (c *TracingConfig) Validate() error {
 	}
 
 	validExporters := map[string]bool{
-		"otlp": true, "jaeger": true, "zipkin": true, "stdout": true,
+		"otlp": true, "otlphttp": true, "jaeger": true, "zipkin": true, "stdout": true,
 	}
 	if !validExporters[c.Exporter] {
-		return fmt.Errorf("invalid exporter %q (valid: otlp, jaeger, zipkin, stdout)", c.Exporter)
+		return fmt.Errorf("invalid exporter %q (valid: otlp, otlphttp, jaeger, zipkin, stdout)", c.Exporter)
+	}
+
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return fmt.Errorf("cert_file and key_file must be set together")
+	}
+	if c.hasTLSFiles() && c.IsInsecure() && !strings.HasPrefix(c.Endpoint, "https://") {
+		return fmt.Errorf("ca_file, cert_file and key_file require insecure: false")
 	}
 
 	return nil
 }
 
+// hasTLSFiles reports whether any TLS file is configured.
+func (c *TracingConfig) hasTLSFiles() bool {
+	return c.CAFile != "" || c.CertFile != "" || c.KeyFile != ""
+}
+
 // IsDebugExporterEnabled returns whether the debug exporter should be enabled.
 func (c *TracingConfig) IsDebugExporterEnabled() bool {
 	if c.DebugExporter == nil {
@@ -177,6 +310,11 @@ func (c *TracingConfig) IsDebugExporterEnabled() bool {
 	return *c.DebugExporter
 }
 
+// IsCorrelationEnabled returns whether spans carry request correlation IDs.
+func (c *TracingConfig) IsCorrelationEnabled() bool {
+	return c.Correlation == nil || *c.Correlation
+}
+
 // IsInsecure returns whether to use insecure connection.
 func (c *TracingConfig) IsInsecure() bool {
 	if c.Insecure == nil {
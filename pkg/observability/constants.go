@@ -122,6 +122,9 @@ const (
 	// AttrHectorUserID is the user ID.
 	AttrHectorUserID = "hector.user_id"
 
+	// AttrHectorTaskID is the A2A task ID.
+	AttrHectorTaskID = "hector.task_id"
+
 	// AttrHectorEventID is the event ID within a session.
 	AttrHectorEventID = "hector.event_id"
 
@@ -142,6 +145,12 @@ const (
 
 	// AttrHectorThinkingLength is the total length of thinking content (chars).
 	AttrHectorThinkingLength = "hector.llm.thinking.length"
+
+	// AttrHectorPromptVersion is the version of the agent's instruction.
+	AttrHectorPromptVersion = "hector.prompt.version"
+
+	// AttrHectorPromptHash is the short hash of the agent's instruction.
+	AttrHectorPromptHash = "hector.prompt.hash"
 )
 
 // =============================================================================
@@ -278,6 +287,9 @@ const (
 	// DefaultOTLPEndpoint is the default OTLP endpoint.
 	DefaultOTLPEndpoint = "localhost:4317"
 
+	// DefaultOTLPHTTPEndpoint is the default OTLP/HTTP endpoint.
+	DefaultOTLPHTTPEndpoint = "localhost:4318"
+
 	// DefaultMetricsPath is the default Prometheus metrics endpoint.
 	DefaultMetricsPath = "/metrics"
 )
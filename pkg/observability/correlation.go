@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package observability
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Correlation identifies the conversation a request belongs to.
+//
+// The executor attaches it to the request context so spans and log lines
+// deep in the call chain (e.g., LLM provider calls) can be tied back to a
+// session, user and task. These IDs are high-cardinality: use them as span
+// or log attributes. Metrics only carry the session ID when
+// MetricsConfig.SessionLabels is enabled.
+type Correlation struct {
+	SessionID string
+	UserID    string
+	TaskID    string
+}
+
+type correlationKey struct{}
+
+// WithCorrelation returns a context carrying the correlation IDs.
+func WithCorrelation(ctx context.Context, c Correlation) context.Context {
+	return context.WithValue(ctx, correlationKey{}, c)
+}
+
+// CorrelationFromContext returns the correlation IDs carried by ctx.
+func CorrelationFromContext(ctx context.Context) (Correlation, bool) {
+	c, ok := ctx.Value(correlationKey{}).(Correlation)
+	return c, ok
+}
+
+type agentNameKey struct{}
+
+// WithAgentName returns a context attributing work to the named agent. LLM
+// agents set it around model calls so providers and metrics can tell which
+// agent made a call.
+func WithAgentName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, agentNameKey{}, name)
+}
+
+// AgentNameFromContext returns the agent name carried by ctx, or "".
+func AgentNameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(agentNameKey{}).(string)
+	return name
+}
+
+// Attributes returns the non-empty IDs as span attributes.
+func (c Correlation) Attributes() []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if c.SessionID != "" {
+		attrs = append(attrs, attribute.String(AttrHectorSessionID, c.SessionID))
+	}
+	if c.UserID != "" {
+		attrs = append(attrs, attribute.String(AttrHectorUserID, c.UserID))
+	}
+	if c.TaskID != "" {
+		attrs = append(attrs, attribute.String(AttrHectorTaskID, c.TaskID))
+	}
+	return attrs
+}
+
+// LogAttrs returns slog key-value pairs correlating a log line with its
+// conversation and, when a span is active, its trace.
+func LogAttrs(ctx context.Context) []any {
+	var attrs []any
+	if c, ok := CorrelationFromContext(ctx); ok {
+		if c.SessionID != "" {
+			attrs = append(attrs, "session_id", c.SessionID)
+		}
+		if c.TaskID != "" {
+			attrs = append(attrs, "task_id", c.TaskID)
+		}
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		attrs = append(attrs, "trace_id", sc.TraceID().String(), "span_id", sc.SpanID().String())
+	}
+	return attrs
+}
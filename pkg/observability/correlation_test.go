@@ -0,0 +1,43 @@
+package observability
+
+import (
+	"context"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStartAddsCorrelationAttributes(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	tracer := &Tracer{tracer: provider.Tracer("test"), correlate: true}
+
+	ctx := WithCorrelation(context.Background(), Correlation{SessionID: "s1", UserID: "u1", TaskID: "t1"})
+	_, span := tracer.StartLLMCall(ctx, "gpt-4o", 0, 0, 0)
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	got := make(map[string]string)
+	for _, attr := range spans[0].Attributes() {
+		got[string(attr.Key)] = attr.Value.Emit()
+	}
+	for key, want := range map[string]string{
+		AttrHectorSessionID: "s1",
+		AttrHectorUserID:    "u1",
+		AttrHectorTaskID:    "t1",
+	} {
+		if got[key] != want {
+			t.Errorf("%s = %q, want %q", key, got[key], want)
+		}
+	}
+}
+
+func TestLogAttrsWithoutCorrelation(t *testing.T) {
+	if attrs := LogAttrs(context.Background()); len(attrs) != 0 {
+		t.Errorf("LogAttrs() = %v, want none", attrs)
+	}
+}
@@ -0,0 +1,55 @@
+package observability
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegisterDBStats(t *testing.T) {
+	m, err := NewMetrics(&MetricsConfig{Enabled: true})
+	if err != nil {
+		t.Fatalf("NewMetrics() error = %v", err)
+	}
+
+	stats := map[string]sql.DBStats{
+		"postgres://db:5432/hector": {MaxOpenConnections: 25, OpenConnections: 7, InUse: 5, Idle: 2, WaitCount: 3, WaitDuration: 1500 * time.Millisecond},
+	}
+	if err := m.RegisterDBStats(func() map[string]sql.DBStats { return stats }); err != nil {
+		t.Fatalf("RegisterDBStats() error = %v", err)
+	}
+
+	families, err := m.Registry().Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	got := make(map[string]float64)
+	for _, f := range families {
+		if !strings.HasPrefix(f.GetName(), "hector_db_") {
+			continue
+		}
+		for _, metric := range f.GetMetric() {
+			switch {
+			case metric.GetGauge() != nil:
+				got[f.GetName()] = metric.GetGauge().GetValue()
+			case metric.GetCounter() != nil:
+				got[f.GetName()] = metric.GetCounter().GetValue()
+			}
+		}
+	}
+
+	want := map[string]float64{
+		"hector_db_max_open_connections":        25,
+		"hector_db_open_connections":            7,
+		"hector_db_in_use_connections":          5,
+		"hector_db_idle_connections":            2,
+		"hector_db_wait_count_total":            3,
+		"hector_db_wait_duration_seconds_total": 1.5,
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("%s = %v, want %v", name, got[name], value)
+		}
+	}
+}
@@ -0,0 +1,366 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package langfuse exports agent LLM and tool calls to Langfuse.
+//
+// A Client implements llmagent.Observer. Each agent run becomes a Langfuse
+// trace, each model call a generation with its full prompt and response,
+// and each tool call a span. Events are queued and sent in batches to the
+// ingestion API in the background.
+package langfuse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kadirpekel/hector/pkg/agent/llmagent"
+	"github.com/kadirpekel/hector/pkg/model"
+)
+
+const (
+	// DefaultHost is Langfuse Cloud.
+	DefaultHost = "https://cloud.langfuse.com"
+
+	// DefaultFlushInterval is how often queued events are sent.
+	DefaultFlushInterval = 5 * time.Second
+
+	// DefaultBatchSize is the most events sent in one request.
+	DefaultBatchSize = 50
+
+	// DefaultQueueSize is the most events waiting to be sent. Events
+	// beyond it are dropped.
+	DefaultQueueSize = 1000
+
+	// maxTrackedTraces bounds the set of traces already created.
+	maxTrackedTraces = 10000
+)
+
+// Config configures a Client.
+type Config struct {
+	// Host is the Langfuse base URL. Default: DefaultHost.
+	Host string
+
+	// PublicKey and SecretKey authenticate with the project.
+	PublicKey string
+	SecretKey string
+
+	// RedactKeys are names of secret fields, such as "api_key" or
+	// "password". Matching keys in tool arguments and results, and
+	// "key: value" or "key=value" pairs in prompt and response text,
+	// have their values replaced before export.
+	RedactKeys []string
+
+	// FlushInterval is how often queued events are sent.
+	// Default: DefaultFlushInterval.
+	FlushInterval time.Duration
+
+	// BatchSize is the most events sent in one request.
+	// Default: DefaultBatchSize.
+	BatchSize int
+
+	// QueueSize is the most events waiting to be sent.
+	// Default: DefaultQueueSize.
+	QueueSize int
+
+	// HTTPClient sends ingestion requests. Default: a client with a 30s
+	// timeout.
+	HTTPClient *http.Client
+}
+
+// Client exports LLM and tool calls to Langfuse.
+type Client struct {
+	cfg      Config
+	endpoint string
+	redactor *redactor
+
+	queue chan event
+	stop  chan struct{}
+	done  chan struct{}
+	once  sync.Once
+
+	mu          sync.Mutex
+	generations map[string]*generation
+	traces      map[string]struct{}
+}
+
+var _ llmagent.Observer = (*Client)(nil)
+
+// event is an ingestion API event.
+type event struct {
+	ID        string `json:"id"`
+	Timestamp string `json:"timestamp"`
+	Type      string `json:"type"`
+	Body      any    `json:"body"`
+}
+
+// generation tracks a model call between its start and end.
+type generation struct {
+	input           any
+	completionStart time.Time
+}
+
+// New creates a Client and starts sending events in the background.
+// Call Close to flush queued events.
+func New(cfg Config) (*Client, error) {
+	if cfg.PublicKey == "" || cfg.SecretKey == "" {
+		return nil, fmt.Errorf("langfuse: public key and secret key are required")
+	}
+	if cfg.Host == "" {
+		cfg.Host = DefaultHost
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultFlushInterval
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = DefaultQueueSize
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	c := &Client{
+		cfg:         cfg,
+		endpoint:    strings.TrimSuffix(cfg.Host, "/") + "/api/public/ingestion",
+		redactor:    newRedactor(cfg.RedactKeys),
+		queue:       make(chan event, cfg.QueueSize),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+		generations: make(map[string]*generation),
+		traces:      make(map[string]struct{}),
+	}
+	go c.run()
+	return c, nil
+}
+
+// OnLLMStart records the request of a model call.
+func (c *Client) OnLLMStart(_ context.Context, call llmagent.LLMCallInfo, req *model.Request) {
+	c.ensureTrace(call.CallInfo, call.StartTime)
+	c.mu.Lock()
+	c.generations[call.ID] = &generation{input: c.redactor.value(requestInput(req))}
+	c.mu.Unlock()
+}
+
+// OnLLMChunk records when the first streamed chunk arrived.
+func (c *Client) OnLLMChunk(_ context.Context, call llmagent.LLMCallInfo, _ *model.Response) {
+	c.mu.Lock()
+	if g := c.generations[call.ID]; g != nil && g.completionStart.IsZero() {
+		g.completionStart = time.Now()
+	}
+	c.mu.Unlock()
+}
+
+// OnLLMEnd queues the model call as a generation.
+func (c *Client) OnLLMEnd(_ context.Context, call llmagent.LLMCallInfo, resp *model.Response, err error) {
+	c.mu.Lock()
+	g := c.generations[call.ID]
+	delete(c.generations, call.ID)
+	c.mu.Unlock()
+	if g == nil {
+		g = &generation{}
+	}
+
+	end := time.Now()
+	body := map[string]any{
+		"id":        call.ID,
+		"traceId":   call.InvocationID,
+		"name":      call.AgentName,
+		"startTime": timestamp(call.StartTime),
+		"endTime":   timestamp(end),
+		"model":     call.Model,
+		"input":     g.input,
+		"metadata":  map[string]any{"provider": call.Provider, "stream": call.Stream},
+	}
+	if !g.completionStart.IsZero() {
+		body["completionStartTime"] = timestamp(g.completionStart)
+	}
+	if resp != nil {
+		body["output"] = c.redactor.value(responseOutput(resp))
+		if u := resp.Usage; u != nil {
+			body["usageDetails"] = map[string]int{
+				"input":  u.PromptTokens,
+				"output": u.CompletionTokens,
+				"total":  u.PromptTokens + u.CompletionTokens,
+			}
+		}
+	}
+	if err != nil {
+		body["level"] = "ERROR"
+		body["statusMessage"] = c.redactor.text(err.Error())
+	}
+	c.enqueue("generation-create", body, end)
+}
+
+// OnToolCall queues the tool call as a span.
+func (c *Client) OnToolCall(_ context.Context, call llmagent.ToolCallInfo) {
+	c.ensureTrace(call.CallInfo, call.StartTime)
+	body := map[string]any{
+		"id":        uuid.NewString(),
+		"traceId":   call.InvocationID,
+		"name":      "tool:" + call.Name,
+		"startTime": timestamp(call.StartTime),
+		"endTime":   timestamp(call.EndTime),
+		"input":     c.redactor.value(call.Args),
+		"output":    c.redactor.value(call.Result),
+		"metadata":  map[string]any{"agent": call.AgentName, "tool_call_id": call.ID},
+	}
+	if call.Err != nil {
+		body["level"] = "ERROR"
+		body["statusMessage"] = c.redactor.text(call.Err.Error())
+	}
+	c.enqueue("span-create", body, call.EndTime)
+}
+
+// Close sends queued events and stops the background sender.
+func (c *Client) Close() error {
+	c.once.Do(func() {
+		close(c.stop)
+		<-c.done
+	})
+	return nil
+}
+
+// ensureTrace queues a trace for the agent run the first time it is seen.
+func (c *Client) ensureTrace(info llmagent.CallInfo, start time.Time) {
+	c.mu.Lock()
+	if _, ok := c.traces[info.InvocationID]; ok {
+		c.mu.Unlock()
+		return
+	}
+	if len(c.traces) >= maxTrackedTraces {
+		clear(c.traces)
+	}
+	c.traces[info.InvocationID] = struct{}{}
+	c.mu.Unlock()
+
+	body := map[string]any{
+		"id":        info.InvocationID,
+		"name":      info.AgentName,
+		"timestamp": timestamp(start),
+	}
+	if info.SessionID != "" {
+		body["sessionId"] = info.SessionID
+	}
+	if info.UserID != "" {
+		body["userId"] = info.UserID
+	}
+	c.enqueue("trace-create", body, start)
+}
+
+// enqueue queues an event, dropping it if the queue is full.
+func (c *Client) enqueue(eventType string, body any, at time.Time) {
+	ev := event{ID: uuid.NewString(), Timestamp: timestamp(at), Type: eventType, Body: body}
+	select {
+	case c.queue <- ev:
+	default:
+		slog.Warn("Langfuse queue full, dropping event", "type", eventType)
+	}
+}
+
+// run sends batches until Close is called, then sends what is left.
+func (c *Client) run() {
+	defer close(c.done)
+
+	ticker := time.NewTicker(c.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	var batch []event
+	flush := func() {
+		if len(batch) > 0 {
+			c.send(batch)
+			batch = nil
+		}
+	}
+	for {
+		select {
+		case ev := <-c.queue:
+			batch = append(batch, ev)
+			if len(batch) >= c.cfg.BatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-c.stop:
+			for {
+				select {
+				case ev := <-c.queue:
+					batch = append(batch, ev)
+					if len(batch) >= c.cfg.BatchSize {
+						flush()
+					}
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// send posts a batch to the ingestion API. Failures are logged; events
+// are not retried.
+func (c *Client) send(batch []event) {
+	body, err := json.Marshal(map[string]any{"batch": batch})
+	if err != nil {
+		slog.Warn("Failed to encode Langfuse batch", "error", err)
+		return
+	}
+	req, err := http.NewRequest(http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		slog.Warn("Failed to create Langfuse request", "error", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(c.cfg.PublicKey, c.cfg.SecretKey)
+
+	resp, err := c.cfg.HTTPClient.Do(req)
+	if err != nil {
+		slog.Warn("Failed to send Langfuse batch", "events", len(batch), "error", err)
+		return
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		slog.Warn("Langfuse rejected batch", "events", len(batch), "status", resp.StatusCode, "body", string(msg))
+		return
+	}
+
+	// 207 Multi-Status lists the events that failed
+	var result struct {
+		Errors []struct {
+			ID      string `json:"id"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if json.NewDecoder(resp.Body).Decode(&result) == nil && len(result.Errors) > 0 {
+		slog.Warn("Langfuse rejected events", "failed", len(result.Errors), "events", len(batch), "error", result.Errors[0].Message)
+	}
+}
+
+func timestamp(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package langfuse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/agent/llmagent"
+	"github.com/kadirpekel/hector/pkg/model"
+)
+
+// collector is a fake ingestion API recording received events.
+type collector struct {
+	mu     sync.Mutex
+	events []event
+	body   strings.Builder
+	auth   bool
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, pass, ok := r.BasicAuth()
+	var payload struct {
+		Batch []json.RawMessage `json:"batch"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.auth = ok && user == "pk" && pass == "sk" && r.URL.Path == "/api/public/ingestion"
+	for _, raw := range payload.Batch {
+		var ev event
+		_ = json.Unmarshal(raw, &ev)
+		c.events = append(c.events, ev)
+		c.body.Write(raw)
+	}
+	w.WriteHeader(http.StatusMultiStatus)
+	_, _ = w.Write([]byte(`{"successes":[],"errors":[]}`))
+}
+
+func TestClientExportsCalls(t *testing.T) {
+	col := &collector{}
+	srv := httptest.NewServer(col)
+	defer srv.Close()
+
+	c, err := New(Config{
+		Host:          srv.URL,
+		PublicKey:     "pk",
+		SecretKey:     "sk",
+		RedactKeys:    []string{"api_key"},
+		FlushInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx := context.Background()
+	info := llmagent.CallInfo{InvocationID: "inv-1", SessionID: "s-1", UserID: "u-1", AgentName: "assistant"}
+	call := llmagent.LLMCallInfo{CallInfo: info, ID: "gen-1", Model: "gpt-4o", Provider: "openai", Stream: true, StartTime: time.Now()}
+	req := &model.Request{
+		SystemInstruction: "Be helpful.",
+		Messages:          []*a2a.Message{a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "my api_key: sk-live-123"})},
+	}
+	resp := &model.Response{
+		Content: &model.Content{Role: a2a.MessageRoleAgent, Parts: []a2a.Part{a2a.TextPart{Text: "Hello"}}},
+		Usage:   &model.Usage{PromptTokens: 10, CompletionTokens: 5},
+	}
+	c.OnLLMStart(ctx, call, req)
+	c.OnLLMChunk(ctx, call, resp)
+	c.OnLLMEnd(ctx, call, resp, nil)
+	c.OnToolCall(ctx, llmagent.ToolCallInfo{
+		CallInfo:  info,
+		ID:        "call-1",
+		Name:      "search",
+		Args:      map[string]any{"query": "weather", "api_key": "sk-live-456"},
+		Err:       errors.New("boom"),
+		StartTime: time.Now(),
+		EndTime:   time.Now(),
+	})
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	col.mu.Lock()
+	defer col.mu.Unlock()
+	if !col.auth {
+		t.Error("request missing basic auth or ingestion path")
+	}
+	var types []string
+	for _, ev := range col.events {
+		types = append(types, ev.Type)
+	}
+	if got := strings.Join(types, ","); got != "trace-create,generation-create,span-create" {
+		t.Errorf("event types = %s", got)
+	}
+
+	gen := col.events[1].Body.(map[string]any)
+	if gen["traceId"] != "inv-1" || gen["model"] != "gpt-4o" || gen["completionStartTime"] == nil {
+		t.Errorf("generation = %v", gen)
+	}
+	if usage, _ := gen["usageDetails"].(map[string]any); usage["total"] != float64(15) {
+		t.Errorf("usage = %v", gen["usageDetails"])
+	}
+	span := col.events[2].Body.(map[string]any)
+	if span["name"] != "tool:search" || span["level"] != "ERROR" {
+		t.Errorf("span = %v", span)
+	}
+
+	body := col.body.String()
+	if strings.Contains(body, "sk-live") {
+		t.Errorf("secret exported: %s", body)
+	}
+	if !strings.Contains(body, "weather") || !strings.Contains(body, "Be helpful.") {
+		t.Errorf("payload missing content: %s", body)
+	}
+}
+
+func TestNewRequiresKeys(t *testing.T) {
+	if _, err := New(Config{PublicKey: "pk"}); err == nil {
+		t.Error("expected error without secret key")
+	}
+}
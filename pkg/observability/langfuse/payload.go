@@ -0,0 +1,185 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package langfuse
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/model"
+)
+
+// redacted replaces the values of secret fields.
+const redacted = "[REDACTED]"
+
+// requestInput converts a model request to the chat format Langfuse
+// renders: a list of role/content messages, system prompt first.
+func requestInput(req *model.Request) any {
+	if req == nil {
+		return nil
+	}
+	var messages []map[string]any
+	if req.SystemInstruction != "" {
+		messages = append(messages, map[string]any{"role": "system", "content": req.SystemInstruction})
+	}
+	for _, msg := range req.Messages {
+		if msg == nil {
+			continue
+		}
+		messages = append(messages, map[string]any{"role": string(msg.Role), "content": partsContent(msg.Parts)})
+	}
+	if len(req.Tools) == 0 {
+		return messages
+	}
+	tools := make([]string, len(req.Tools))
+	for i, t := range req.Tools {
+		tools[i] = t.Name
+	}
+	return map[string]any{"messages": messages, "tools": tools}
+}
+
+// responseOutput converts a final model response to an assistant message.
+func responseOutput(resp *model.Response) any {
+	out := map[string]any{"role": "assistant"}
+	if resp.Content != nil {
+		out["content"] = partsContent(resp.Content.Parts)
+	}
+	if len(resp.ToolCalls) > 0 {
+		calls := make([]map[string]any, len(resp.ToolCalls))
+		for i, tc := range resp.ToolCalls {
+			calls[i] = map[string]any{"id": tc.ID, "name": tc.Name, "arguments": tc.Args}
+		}
+		out["tool_calls"] = calls
+	}
+	if resp.Thinking != nil && resp.Thinking.Content != "" {
+		out["thinking"] = resp.Thinking.Content
+	}
+	return out
+}
+
+// partsContent returns message parts as a string when they are all text,
+// and as a list of parts otherwise.
+func partsContent(parts []a2a.Part) any {
+	var texts []string
+	var items []any
+	allText := true
+	for _, part := range parts {
+		switch p := part.(type) {
+		case a2a.TextPart:
+			texts = append(texts, p.Text)
+			items = append(items, map[string]any{"type": "text", "text": p.Text})
+		case *a2a.TextPart:
+			texts = append(texts, p.Text)
+			items = append(items, map[string]any{"type": "text", "text": p.Text})
+		case a2a.DataPart:
+			allText = false
+			items = append(items, map[string]any{"type": "data", "data": p.Data})
+		case *a2a.DataPart:
+			allText = false
+			items = append(items, map[string]any{"type": "data", "data": p.Data})
+		case a2a.FilePart:
+			allText = false
+			items = append(items, fileItem(p))
+		case *a2a.FilePart:
+			allText = false
+			items = append(items, fileItem(*p))
+		}
+	}
+	if allText {
+		return strings.Join(texts, "")
+	}
+	return items
+}
+
+// fileItem describes a file part without its content.
+func fileItem(p a2a.FilePart) map[string]any {
+	item := map[string]any{"type": "file"}
+	switch f := p.File.(type) {
+	case a2a.FileBytes:
+		item["name"], item["mime_type"] = f.Name, f.MimeType
+	case a2a.FileURI:
+		item["name"], item["mime_type"], item["uri"] = f.Name, f.MimeType, f.URI
+	}
+	return item
+}
+
+// redactor replaces the values of secret fields in payloads.
+type redactor struct {
+	keys    map[string]bool
+	pattern *regexp.Regexp
+}
+
+func newRedactor(keys []string) *redactor {
+	r := &redactor{keys: make(map[string]bool)}
+	var alts []string
+	for _, k := range keys {
+		k = strings.TrimSpace(k)
+		if k == "" {
+			continue
+		}
+		r.keys[strings.ToLower(k)] = true
+		alts = append(alts, regexp.QuoteMeta(k))
+	}
+	if len(alts) > 0 {
+		// key: value, key=value, "key": "value"
+		r.pattern = regexp.MustCompile(`(?i)(\b(?:` + strings.Join(alts, "|") + `)\b["']?\s*[:=]\s*["']?)([^\s"',;}&]+)`)
+	}
+	return r
+}
+
+// text redacts "key: value" and "key=value" pairs in s.
+func (r *redactor) text(s string) string {
+	if r.pattern == nil {
+		return s
+	}
+	return r.pattern.ReplaceAllString(s, "${1}"+redacted)
+}
+
+// value returns a copy of v with secret map entries and text redacted.
+func (r *redactor) value(v any) any {
+	if r.pattern == nil {
+		return v
+	}
+	switch val := v.(type) {
+	case string:
+		return r.text(val)
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			if r.keys[strings.ToLower(k)] {
+				out[k] = redacted
+			} else {
+				out[k] = r.value(item)
+			}
+		}
+		return out
+	case []map[string]any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = r.value(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = r.value(item)
+		}
+		return out
+	}
+	return v
+}
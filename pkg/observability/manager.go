@@ -60,6 +60,7 @@ func NewManager(ctx context.Context, cfg *Config) (*Manager, error) {
 		if cfg.Tracing.CapturePayloads {
 			opts = append(opts, WithCapturePayloads(true))
 		}
+		opts = append(opts, WithSpanCorrelation(cfg.Tracing.IsCorrelationEnabled()))
 
 		tracer, err := NewTracer(ctx, &cfg.Tracing, opts...)
 		if err != nil {
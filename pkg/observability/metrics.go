@@ -16,7 +16,9 @@
 package observability
 
 import (
+	"context"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -39,12 +41,19 @@ type Metrics struct {
 	llmCallDuration *prometheus.HistogramVec
 	llmTokensInput  *prometheus.CounterVec
 	llmTokensOutput *prometheus.CounterVec
+	llmCost         *prometheus.CounterVec
 	llmErrors       *prometheus.CounterVec
+	llmCacheHits    *prometheus.CounterVec
+	llmCacheMisses  *prometheus.CounterVec
+
+	costMu   sync.RWMutex
+	costFunc LLMCostFunc
 
 	// Tool metrics
 	toolCalls        *prometheus.CounterVec
 	toolCallDuration *prometheus.HistogramVec
 	toolErrors       *prometheus.CounterVec
+	toolTruncated    *prometheus.CounterVec
 
 	// Memory/Index metrics
 	memorySearches  *prometheus.CounterVec
@@ -55,6 +64,7 @@ type Metrics struct {
 	sessionsCreated    *prometheus.CounterVec
 	sessionsActive     *prometheus.GaugeVec
 	sessionEventsTotal *prometheus.CounterVec
+	sessionsExpired    prometheus.Counter
 
 	// HTTP metrics
 	httpRequests     *prometheus.CounterVec
@@ -142,6 +152,8 @@ func (m *Metrics) initAgentMetrics() {
 }
 
 func (m *Metrics) initLLMMetrics() {
+	labels := m.llmLabelNames()
+
 	m.llmCalls = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Namespace: m.config.Namespace,
@@ -149,7 +161,7 @@ func (m *Metrics) initLLMMetrics() {
 			Name:      "calls_total",
 			Help:      "Total number of LLM API calls",
 		},
-		[]string{"model", "provider"},
+		labels,
 	)
 
 	m.llmCallDuration = prometheus.NewHistogramVec(
@@ -160,7 +172,7 @@ func (m *Metrics) initLLMMetrics() {
 			Help:      "LLM API call duration in seconds",
 			Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12), // 100ms to 204s
 		},
-		[]string{"model", "provider"},
+		labels,
 	)
 
 	m.llmTokensInput = prometheus.NewCounterVec(
@@ -170,7 +182,7 @@ func (m *Metrics) initLLMMetrics() {
 			Name:      "tokens_input_total",
 			Help:      "Total number of input tokens consumed",
 		},
-		[]string{"model", "provider"},
+		labels,
 	)
 
 	m.llmTokensOutput = prometheus.NewCounterVec(
@@ -180,7 +192,17 @@ func (m *Metrics) initLLMMetrics() {
 			Name:      "tokens_output_total",
 			Help:      "Total number of output tokens generated",
 		},
-		[]string{"model", "provider"},
+		labels,
+	)
+
+	m.llmCost = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: m.config.Namespace,
+			Subsystem: "llm",
+			Name:      "cost_total",
+			Help:      "Estimated LLM spend, in the currency of the configured pricing",
+		},
+		labels,
 	)
 
 	m.llmErrors = prometheus.NewCounterVec(
@@ -190,10 +212,52 @@ func (m *Metrics) initLLMMetrics() {
 			Name:      "errors_total",
 			Help:      "Total number of LLM API errors",
 		},
-		[]string{"model", "provider", "error_type"},
+		append(labels, "error_type"),
+	)
+
+	m.llmCacheHits = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: m.config.Namespace,
+			Subsystem: "llm",
+			Name:      "cache_hits_total",
+			Help:      "Total number of LLM responses served from the response cache",
+		},
+		[]string{"model", "provider"},
 	)
 
-	m.registry.MustRegister(m.llmCalls, m.llmCallDuration, m.llmTokensInput, m.llmTokensOutput, m.llmErrors)
+	m.llmCacheMisses = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: m.config.Namespace,
+			Subsystem: "llm",
+			Name:      "cache_misses_total",
+			Help:      "Total number of LLM requests not found in the response cache",
+		},
+		[]string{"model", "provider"},
+	)
+
+	m.registry.MustRegister(m.llmCalls, m.llmCallDuration, m.llmTokensInput, m.llmTokensOutput, m.llmCost,
+		m.llmErrors, m.llmCacheHits, m.llmCacheMisses)
+}
+
+// llmLabelNames returns the labels of LLM call, token, cost and error
+// metrics.
+func (m *Metrics) llmLabelNames() []string {
+	labels := []string{"model", "provider", "agent_name"}
+	if m.config.SessionLabels {
+		labels = append(labels, "session_id")
+	}
+	return labels
+}
+
+// llmLabelValues returns the values for llmLabelNames, taking the agent and
+// session from ctx.
+func (m *Metrics) llmLabelValues(ctx context.Context, model, provider string) []string {
+	values := []string{model, provider, AgentNameFromContext(ctx)}
+	if m.config.SessionLabels {
+		c, _ := CorrelationFromContext(ctx)
+		values = append(values, c.SessionID)
+	}
+	return values
 }
 
 func (m *Metrics) initToolMetrics() {
@@ -228,7 +292,17 @@ func (m *Metrics) initToolMetrics() {
 		[]string{"tool_name", "error_type"},
 	)
 
-	m.registry.MustRegister(m.toolCalls, m.toolCallDuration, m.toolErrors)
+	m.toolTruncated = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: m.config.Namespace,
+			Subsystem: "tool",
+			Name:      "output_truncated_total",
+			Help:      "Total number of tool results truncated to the output size limit",
+		},
+		[]string{"tool_name"},
+	)
+
+	m.registry.MustRegister(m.toolCalls, m.toolCallDuration, m.toolErrors, m.toolTruncated)
 }
 
 func (m *Metrics) initMemoryMetrics() {
@@ -297,7 +371,16 @@ func (m *Metrics) initSessionMetrics() {
 		[]string{"app_name", "event_type"},
 	)
 
-	m.registry.MustRegister(m.sessionsCreated, m.sessionsActive, m.sessionEventsTotal)
+	m.sessionsExpired = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: m.config.Namespace,
+			Subsystem: "session",
+			Name:      "expired_total",
+			Help:      "Total number of sessions deleted after exceeding their TTL",
+		},
+	)
+
+	m.registry.MustRegister(m.sessionsCreated, m.sessionsActive, m.sessionEventsTotal, m.sessionsExpired)
 }
 
 func (m *Metrics) initHTTPMetrics() {
@@ -466,30 +549,73 @@ func (m *Metrics) DecAgentActiveRuns(agentName string) {
 // LLM Metrics
 // =============================================================================
 
-// RecordLLMCall records an LLM API call.
-func (m *Metrics) RecordLLMCall(model, provider string, duration time.Duration) {
+// LLMCostFunc estimates the cost of token usage on a model. It returns 0
+// for models without pricing.
+type LLMCostFunc func(model string, inputTokens, outputTokens int) float64
+
+// SetLLMCostFunc sets how RecordLLMTokens prices token usage for the
+// cost_total metric. Without it, no cost is recorded.
+func (m *Metrics) SetLLMCostFunc(fn LLMCostFunc) {
 	if m == nil {
 		return
 	}
-	m.llmCalls.WithLabelValues(model, provider).Inc()
-	m.llmCallDuration.WithLabelValues(model, provider).Observe(duration.Seconds())
+	m.costMu.Lock()
+	m.costFunc = fn
+	m.costMu.Unlock()
 }
 
-// RecordLLMTokens records token usage.
-func (m *Metrics) RecordLLMTokens(model, provider string, inputTokens, outputTokens int) {
+// RecordLLMCall records an LLM API call. The agent and session are taken
+// from ctx.
+func (m *Metrics) RecordLLMCall(ctx context.Context, model, provider string, duration time.Duration) {
 	if m == nil {
 		return
 	}
-	m.llmTokensInput.WithLabelValues(model, provider).Add(float64(inputTokens))
-	m.llmTokensOutput.WithLabelValues(model, provider).Add(float64(outputTokens))
+	labels := m.llmLabelValues(ctx, model, provider)
+	m.llmCalls.WithLabelValues(labels...).Inc()
+	m.llmCallDuration.WithLabelValues(labels...).Observe(duration.Seconds())
+}
+
+// RecordLLMTokens records token usage and its estimated cost.
+func (m *Metrics) RecordLLMTokens(ctx context.Context, model, provider string, inputTokens, outputTokens int) {
+	if m == nil {
+		return
+	}
+	labels := m.llmLabelValues(ctx, model, provider)
+	m.llmTokensInput.WithLabelValues(labels...).Add(float64(inputTokens))
+	m.llmTokensOutput.WithLabelValues(labels...).Add(float64(outputTokens))
+
+	m.costMu.RLock()
+	costFunc := m.costFunc
+	m.costMu.RUnlock()
+	if costFunc != nil {
+		if cost := costFunc(model, inputTokens, outputTokens); cost > 0 {
+			m.llmCost.WithLabelValues(labels...).Add(cost)
+		}
+	}
 }
 
 // RecordLLMError records an LLM error.
-func (m *Metrics) RecordLLMError(model, provider, errorType string) {
+func (m *Metrics) RecordLLMError(ctx context.Context, model, provider, errorType string) {
+	if m == nil {
+		return
+	}
+	m.llmErrors.WithLabelValues(append(m.llmLabelValues(ctx, model, provider), errorType)...).Inc()
+}
+
+// RecordLLMCacheHit records a response served from the response cache.
+func (m *Metrics) RecordLLMCacheHit(model, provider string) {
 	if m == nil {
 		return
 	}
-	m.llmErrors.WithLabelValues(model, provider, errorType).Inc()
+	m.llmCacheHits.WithLabelValues(model, provider).Inc()
+}
+
+// RecordLLMCacheMiss records a request not found in the response cache.
+func (m *Metrics) RecordLLMCacheMiss(model, provider string) {
+	if m == nil {
+		return
+	}
+	m.llmCacheMisses.WithLabelValues(model, provider).Inc()
 }
 
 // =============================================================================
@@ -513,6 +639,14 @@ func (m *Metrics) RecordToolError(toolName, errorType string) {
 	m.toolErrors.WithLabelValues(toolName, errorType).Inc()
 }
 
+// RecordToolOutputTruncated records a tool result cut to the output size limit.
+func (m *Metrics) RecordToolOutputTruncated(toolName string) {
+	if m == nil {
+		return
+	}
+	m.toolTruncated.WithLabelValues(toolName).Inc()
+}
+
 // =============================================================================
 // Memory Metrics
 // =============================================================================
@@ -562,6 +696,14 @@ func (m *Metrics) RecordSessionEvent(appName, eventType string) {
 	m.sessionEventsTotal.WithLabelValues(appName, eventType).Inc()
 }
 
+// RecordSessionsExpired records sessions deleted by the expiry sweeper.
+func (m *Metrics) RecordSessionsExpired(count int) {
+	if m == nil {
+		return
+	}
+	m.sessionsExpired.Add(float64(count))
+}
+
 // =============================================================================
 // HTTP Metrics
 // =============================================================================
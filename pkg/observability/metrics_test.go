@@ -0,0 +1,106 @@
+package observability
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// gatherLLM returns the value of each hector_llm_ counter or histogram
+// sample count, keyed by metric name and sorted label values.
+func gatherLLM(t *testing.T, m *Metrics) map[string]float64 {
+	t.Helper()
+	families, err := m.Registry().Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	got := make(map[string]float64)
+	for _, f := range families {
+		if !strings.HasPrefix(f.GetName(), "hector_llm_") {
+			continue
+		}
+		for _, metric := range f.GetMetric() {
+			var labels []string
+			for _, l := range metric.GetLabel() {
+				labels = append(labels, l.GetName()+"="+l.GetValue())
+			}
+			key := f.GetName() + "{" + strings.Join(labels, ",") + "}"
+			switch {
+			case metric.GetCounter() != nil:
+				got[key] = metric.GetCounter().GetValue()
+			case metric.GetHistogram() != nil:
+				got[key] = float64(metric.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	return got
+}
+
+func TestLLMMetricsByAgent(t *testing.T) {
+	m, err := NewMetrics(&MetricsConfig{Enabled: true})
+	if err != nil {
+		t.Fatalf("NewMetrics() error = %v", err)
+	}
+	m.SetLLMCostFunc(func(model string, in, out int) float64 {
+		if model != "gpt-4o" {
+			return 0
+		}
+		return float64(in)/1000*0.0025 + float64(out)/1000*0.01
+	})
+
+	ctx := WithCorrelation(context.Background(), Correlation{SessionID: "s1"})
+	ctx = WithAgentName(ctx, "researcher")
+	m.RecordLLMCall(ctx, "gpt-4o", "openai", time.Second)
+	m.RecordLLMTokens(ctx, "gpt-4o", "openai", 1000, 500)
+	m.RecordLLMTokens(ctx, "llama3.2", "ollama", 100, 50)
+	m.RecordLLMError(context.Background(), "gpt-4o", "openai", "generation_error")
+
+	got := gatherLLM(t, m)
+	const labels = "{agent_name=researcher,model=gpt-4o,provider=openai}"
+	want := map[string]float64{
+		"hector_llm_calls_total" + labels:                                                               1,
+		"hector_llm_call_duration_seconds" + labels:                                                     1,
+		"hector_llm_tokens_input_total" + labels:                                                        1000,
+		"hector_llm_tokens_output_total" + labels:                                                       500,
+		"hector_llm_errors_total{agent_name=,error_type=generation_error,model=gpt-4o,provider=openai}": 1,
+	}
+	for key, value := range want {
+		if got[key] != value {
+			t.Errorf("%s = %v, want %v", key, got[key], value)
+		}
+	}
+	if cost := got["hector_llm_cost_total"+labels]; math.Abs(cost-0.0075) > 1e-9 {
+		t.Errorf("cost = %v, want 0.0075", cost)
+	}
+	for key := range got {
+		if strings.Contains(key, "session_id") {
+			t.Errorf("unexpected session label: %s", key)
+		}
+		if strings.HasPrefix(key, "hector_llm_cost_total") && strings.Contains(key, "llama3.2") {
+			t.Errorf("cost recorded for unpriced model: %s", key)
+		}
+	}
+}
+
+func TestLLMMetricsSessionLabels(t *testing.T) {
+	m, err := NewMetrics(&MetricsConfig{Enabled: true, SessionLabels: true})
+	if err != nil {
+		t.Fatalf("NewMetrics() error = %v", err)
+	}
+	m.SetLLMCostFunc(func(string, int, int) float64 { return 0.5 })
+
+	ctx := WithAgentName(WithCorrelation(context.Background(), Correlation{SessionID: "s1"}), "writer")
+	m.RecordLLMTokens(ctx, "claude", "anthropic", 10, 5)
+	m.RecordLLMError(ctx, "claude", "anthropic", "timeout")
+
+	got := gatherLLM(t, m)
+	const labels = "{agent_name=writer,model=claude,provider=anthropic,session_id=s1}"
+	if got["hector_llm_cost_total"+labels] != 0.5 {
+		t.Errorf("cost = %v, metrics = %v", got["hector_llm_cost_total"+labels], got)
+	}
+	if got["hector_llm_errors_total{agent_name=writer,error_type=timeout,model=claude,provider=anthropic,session_id=s1}"] != 1 {
+		t.Errorf("errors missing, metrics = %v", got)
+	}
+}
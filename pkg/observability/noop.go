@@ -100,13 +100,14 @@ func (NoopMetrics) IncAgentActiveRuns(_ string)                  {}
 func (NoopMetrics) DecAgentActiveRuns(_ string)                  {}
 
 // LLM metrics - no-op
-func (NoopMetrics) RecordLLMCall(_, _ string, _ time.Duration) {}
-func (NoopMetrics) RecordLLMTokens(_, _ string, _, _ int)      {}
-func (NoopMetrics) RecordLLMError(_, _, _ string)              {}
+func (NoopMetrics) RecordLLMCall(_ context.Context, _, _ string, _ time.Duration) {}
+func (NoopMetrics) RecordLLMTokens(_ context.Context, _, _ string, _, _ int)      {}
+func (NoopMetrics) RecordLLMError(_ context.Context, _, _, _ string)              {}
 
 // Tool metrics - no-op
 func (NoopMetrics) RecordToolCall(_ string, _ time.Duration) {}
 func (NoopMetrics) RecordToolError(_, _ string)              {}
+func (NoopMetrics) RecordToolOutputTruncated(_ string)       {}
 
 // Memory metrics - no-op
 func (NoopMetrics) RecordMemorySearch(_ string, _ time.Duration) {}
@@ -116,6 +117,7 @@ func (NoopMetrics) RecordMemoryIndexed(_ string, _ int)          {}
 func (NoopMetrics) RecordSessionCreated(_ string)     {}
 func (NoopMetrics) SetSessionsActive(_ string, _ int) {}
 func (NoopMetrics) RecordSessionEvent(_, _ string)    {}
+func (NoopMetrics) RecordSessionsExpired(_ int)       {}
 
 // HTTP metrics - no-op
 func (NoopMetrics) RecordHTTPRequest(_, _ string, _ int, _ time.Duration, _, _ int64) {}
@@ -148,13 +150,14 @@ type Recorder interface {
 	DecAgentActiveRuns(agentName string)
 
 	// LLM metrics
-	RecordLLMCall(model, provider string, duration time.Duration)
-	RecordLLMTokens(model, provider string, inputTokens, outputTokens int)
-	RecordLLMError(model, provider, errorType string)
+	RecordLLMCall(ctx context.Context, model, provider string, duration time.Duration)
+	RecordLLMTokens(ctx context.Context, model, provider string, inputTokens, outputTokens int)
+	RecordLLMError(ctx context.Context, model, provider, errorType string)
 
 	// Tool metrics
 	RecordToolCall(toolName string, duration time.Duration)
 	RecordToolError(toolName, errorType string)
+	RecordToolOutputTruncated(toolName string)
 
 	// Memory metrics
 	RecordMemorySearch(indexType string, duration time.Duration)
@@ -164,6 +167,7 @@ type Recorder interface {
 	RecordSessionCreated(appName string)
 	SetSessionsActive(appName string, count int)
 	RecordSessionEvent(appName, eventType string)
+	RecordSessionsExpired(count int)
 
 	// HTTP metrics
 	RecordHTTPRequest(method, path string, statusCode int, duration time.Duration, reqSize, respSize int64)
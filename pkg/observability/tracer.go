@@ -17,12 +17,18 @@ package observability
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
+	"net/url"
+	"os"
+	"strings"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
 	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
@@ -30,6 +36,7 @@ import (
 	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
 	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
@@ -39,6 +46,7 @@ type Tracer struct {
 	tracer         trace.Tracer
 	debugExporter  *DebugExporter
 	capturePayload bool
+	correlate      bool
 	serviceName    string
 }
 
@@ -59,6 +67,13 @@ func WithCapturePayloads(capture bool) TracerOption {
 	}
 }
 
+// WithSpanCorrelation adds request correlation IDs (see Correlation) to spans.
+func WithSpanCorrelation(enabled bool) TracerOption {
+	return func(t *Tracer) {
+		t.correlate = enabled
+	}
+}
+
 // NewTracer creates a new Tracer from configuration.
 func NewTracer(ctx context.Context, cfg *TracingConfig, opts ...TracerOption) (*Tracer, error) {
 	if cfg == nil || !cfg.Enabled {
@@ -130,6 +145,8 @@ func createExporter(ctx context.Context, cfg *TracingConfig) (sdktrace.SpanExpor
 	switch cfg.Exporter {
 	case "otlp":
 		return createOTLPExporter(ctx, cfg)
+	case "otlphttp":
+		return createOTLPHTTPExporter(ctx, cfg)
 	case "stdout":
 		return stdouttrace.New(stdouttrace.WithPrettyPrint())
 	case "jaeger", "zipkin":
@@ -150,6 +167,12 @@ func createOTLPExporter(ctx context.Context, cfg *TracingConfig) (*otlptrace.Exp
 	if cfg.IsInsecure() {
 		opts = append(opts, otlptracegrpc.WithDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())))
 		opts = append(opts, otlptracegrpc.WithInsecure())
+	} else if cfg.hasTLSFiles() {
+		tlsCfg, err := exporterTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(tlsCfg)))
 	}
 
 	if len(cfg.Headers) > 0 {
@@ -159,11 +182,84 @@ func createOTLPExporter(ctx context.Context, cfg *TracingConfig) (*otlptrace.Exp
 	return otlptracegrpc.New(ctx, opts...)
 }
 
+// createOTLPHTTPExporter creates an OTLP exporter that posts protobuf
+// payloads over HTTP. The endpoint is either host:port or a URL whose
+// scheme decides whether TLS is used.
+func createOTLPHTTPExporter(ctx context.Context, cfg *TracingConfig) (*otlptrace.Exporter, error) {
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithTimeout(cfg.Timeout),
+	}
+
+	secure := !cfg.IsInsecure()
+	if strings.Contains(cfg.Endpoint, "://") {
+		u, err := url.Parse(cfg.Endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("invalid endpoint %q: %w", cfg.Endpoint, err)
+		}
+		if u.Path == "" || u.Path == "/" {
+			u.Path = "/v1/traces"
+		}
+		opts = append(opts, otlptracehttp.WithEndpointURL(u.String()))
+		secure = u.Scheme == "https"
+	} else {
+		opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+		if !secure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+	}
+
+	if secure && cfg.hasTLSFiles() {
+		tlsCfg, err := exporterTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, otlptracehttp.WithTLSClientConfig(tlsCfg))
+	}
+
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+	}
+
+	return otlptracehttp.New(ctx, opts...)
+}
+
+// exporterTLSConfig builds the TLS configuration for the collector
+// connection from the configured CA and client certificate files.
+func exporterTLSConfig(cfg *TracingConfig) (*tls.Config, error) {
+	tlsCfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("ca_file %q contains no PEM certificates", cfg.CAFile)
+		}
+		tlsCfg.RootCAs = pool
+	}
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsCfg.Certificates = []tls.Certificate{cert}
+	}
+	return tlsCfg, nil
+}
+
 // Start begins a new span with the given name.
+// When correlation is enabled, the request's session, user and task IDs
+// from ctx are added as span attributes.
 func (t *Tracer) Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
 	if t == nil || t.tracer == nil {
 		return ctx, noopSpan()
 	}
+	if t.correlate {
+		if c, ok := CorrelationFromContext(ctx); ok {
+			opts = append(opts, trace.WithAttributes(c.Attributes()...))
+		}
+	}
 	return t.tracer.Start(ctx, spanName, opts...)
 }
 
@@ -0,0 +1,84 @@
+package observability
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestOTLPHTTPExporter(t *testing.T) {
+	type export struct {
+		method, path, contentType, auth string
+	}
+	exports := make(chan export, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		exports <- export{r.Method, r.URL.Path, r.Header.Get("Content-Type"), r.Header.Get("Authorization")}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	cfg := &TracingConfig{
+		Enabled:  true,
+		Exporter: "otlphttp",
+		Endpoint: collector.URL,
+		Headers:  map[string]string{"Authorization": "Bearer secret"},
+	}
+	cfg.SetDefaults()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	exporter, err := createExporter(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("createExporter() error = %v", err)
+	}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter))
+	_, span := provider.Tracer("test").Start(context.Background(), "test")
+	span.End()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := provider.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	select {
+	case got := <-exports:
+		want := export{http.MethodPost, "/v1/traces", "application/x-protobuf", "Bearer secret"}
+		if got != want {
+			t.Errorf("export = %+v, want %+v", got, want)
+		}
+	default:
+		t.Fatal("no spans exported")
+	}
+}
+
+func TestTracingConfigOTLPHTTPDefaults(t *testing.T) {
+	cfg := &TracingConfig{Enabled: true, Exporter: "otlphttp"}
+	cfg.SetDefaults()
+	if cfg.Endpoint != DefaultOTLPHTTPEndpoint || !cfg.IsInsecure() {
+		t.Errorf("endpoint = %q, insecure = %v", cfg.Endpoint, cfg.IsInsecure())
+	}
+
+	cfg = &TracingConfig{Enabled: true, Exporter: "otlphttp", CAFile: "ca.pem"}
+	cfg.SetDefaults()
+	if cfg.IsInsecure() {
+		t.Error("TLS files should default insecure to false")
+	}
+
+	insecure := true
+	for _, c := range []*TracingConfig{
+		{Enabled: true, Exporter: "otlphttp", CAFile: "ca.pem", Insecure: &insecure},
+		{Enabled: true, Exporter: "otlphttp", CertFile: "cert.pem"},
+		{Enabled: true, Exporter: "otlpweb"},
+	} {
+		c.SetDefaults()
+		if err := c.Validate(); err == nil {
+			t.Errorf("Validate(%+v) expected error", c)
+		}
+	}
+}
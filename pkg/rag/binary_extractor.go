@@ -47,12 +47,28 @@ type NativeParseResult struct {
 // Direct port from legacy pkg/context/extraction/binary_extractor.go
 type BinaryExtractor struct {
 	nativeParsers NativeParser
+	extensions    map[string]bool
 }
 
 // NewBinaryExtractor creates a new binary extractor.
+// If the parser reports its supported extensions, only those are claimed;
+// otherwise PDF, DOCX and XLSX are assumed.
 func NewBinaryExtractor(nativeParsers NativeParser) *BinaryExtractor {
+	extensions := map[string]bool{
+		".pdf":  true,
+		".docx": true,
+		".xlsx": true,
+	}
+	if lister, ok := nativeParsers.(interface{ GetSupportedExtensions() []string }); ok {
+		extensions = make(map[string]bool)
+		for _, ext := range lister.GetSupportedExtensions() {
+			extensions[ext] = true
+		}
+	}
+
 	return &BinaryExtractor{
 		nativeParsers: nativeParsers,
+		extensions:    extensions,
 	}
 }
 
@@ -63,13 +79,7 @@ func (be *BinaryExtractor) Name() string {
 
 // CanExtract checks if this extractor can handle the file.
 func (be *BinaryExtractor) CanExtract(path string, mimeType string) bool {
-	ext := strings.ToLower(filepath.Ext(path))
-	binaryExtensions := map[string]bool{
-		".pdf":  true,
-		".docx": true,
-		".xlsx": true,
-	}
-	return binaryExtensions[ext]
+	return be.extensions[strings.ToLower(filepath.Ext(path))]
 }
 
 // Extract uses native parsers to extract content from binary files.
@@ -185,7 +185,7 @@ type SearchRequest struct {
 	// TopK is the maximum number of results to return.
 	TopK int `json:"top_k,omitempty"`
 
-	// Threshold filters results below this score.
+	// Threshold filters vector matches below this similarity.
 	Threshold float32 `json:"threshold,omitempty"`
 
 	// Filter applies metadata filtering.
@@ -198,6 +198,7 @@ type SearchRequest struct {
 // SearchOptions configures search behavior.
 type SearchOptions struct {
 	// Mode specifies the search mode: "vector", "keyword", "hybrid".
+	// Empty uses the search engine's configured mode.
 	Mode string `json:"mode,omitempty"`
 
 	// EnableHyDE enables Hypothetical Document Embeddings.
@@ -236,7 +237,4 @@ func (r *SearchRequest) SetDefaults() {
 	if r.Options == nil {
 		r.Options = &SearchOptions{}
 	}
-	if r.Options.Mode == "" {
-		r.Options.Mode = "vector"
-	}
 }
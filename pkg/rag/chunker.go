@@ -15,7 +15,11 @@
 
 package rag
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/kadirpekel/hector/pkg/embedder"
+)
 
 // ChunkerStrategy identifies a chunking strategy.
 type ChunkerStrategy string
@@ -29,8 +33,8 @@ const (
 	// Better for retrieval as context is preserved at boundaries.
 	ChunkerOverlapping ChunkerStrategy = "overlapping"
 
-	// ChunkerSemantic splits at natural boundaries (paragraphs, sections).
-	// Best quality but more complex and slower.
+	// ChunkerSemantic splits where the topic changes, detected by
+	// embedding adjacent sentences. Best quality but slower.
 	ChunkerSemantic ChunkerStrategy = "semantic"
 )
 
@@ -93,18 +97,35 @@ type ChunkerConfig struct {
 	// PreserveWords avoids splitting in the middle of words.
 	// Default: true
 	PreserveWords bool `yaml:"preserve_words,omitempty"`
+
+	// Threshold is the cosine similarity between adjacent sentence windows
+	// below which the semantic strategy starts a new chunk. Higher values
+	// give smaller, more focused chunks.
+	// Default: 0.75
+	Threshold float64 `yaml:"threshold,omitempty"`
+
+	// MinDocumentSize is the length below which the semantic strategy
+	// chunks by size instead of embedding sentences.
+	// Default: 2000
+	MinDocumentSize int `yaml:"min_document_size,omitempty"`
+
+	// Embedder detects topic boundaries for the semantic strategy.
+	// Without one, semantic chunking falls back to overlapping chunks.
+	Embedder embedder.Embedder `yaml:"-"`
 }
 
 // DefaultChunkerConfig returns sensible defaults.
 func DefaultChunkerConfig() ChunkerConfig {
 	return ChunkerConfig{
-		Strategy:      ChunkerSimple,
-		Size:          1000,
-		Overlap:       200,
-		MinSize:       100,
-		MaxSize:       2000,
-		Separators:    []string{"\n\n", "\n", ". ", " "},
-		PreserveWords: true,
+		Strategy:        ChunkerSimple,
+		Size:            1000,
+		Overlap:         200,
+		MinSize:         100,
+		MaxSize:         2000,
+		Separators:      []string{"\n\n", "\n", ". ", " "},
+		PreserveWords:   true,
+		Threshold:       0.75,
+		MinDocumentSize: 2000,
 	}
 }
 
@@ -128,6 +149,12 @@ func (c *ChunkerConfig) SetDefaults() {
 	if len(c.Separators) == 0 {
 		c.Separators = []string{"\n\n", "\n", ". ", " "}
 	}
+	if c.Threshold <= 0 {
+		c.Threshold = 0.75
+	}
+	if c.MinDocumentSize <= 0 {
+		c.MinDocumentSize = 2000
+	}
 }
 
 // Validate checks the configuration for errors.
@@ -159,6 +186,10 @@ func (c *ChunkerConfig) Validate() error {
 		return fmt.Errorf("max_size (%d) must be at least size (%d)", c.MaxSize, c.Size)
 	}
 
+	if c.Threshold < 0 || c.Threshold > 1 {
+		return fmt.Errorf("threshold must be between 0 and 1, got %v", c.Threshold)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rag
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+)
+
+// semanticWindow is the number of sentences on each side of a gap that
+// are compared, smoothing out short asides.
+const semanticWindow = 2
+
+// sentenceSpan is a sentence's byte range, including trailing whitespace.
+type sentenceSpan struct {
+	start, end int
+}
+
+// chunkByTopic groups sentences into chunks. A new chunk starts at a gap
+// where the windows on either side are least similar locally and below
+// the threshold, or where the chunk would exceed MaxSize.
+func (c *SemanticChunker) chunkByTopic(content string, ctx *ChunkContext) ([]Chunk, error) {
+	spans := splitSentences(content)
+	if len(spans) < 2 {
+		return NewSimpleChunker(c.config).Chunk(content, ctx)
+	}
+
+	sentences := make([]string, len(spans))
+	for i, span := range spans {
+		sentences[i] = content[span.start:span.end]
+	}
+
+	// Chunk has no context; embedders apply their own request timeouts
+	embeddings, err := c.config.Embedder.EmbedBatch(context.Background(), sentences)
+	if err == nil && len(embeddings) != len(sentences) {
+		err = fmt.Errorf("embedder returned %d embeddings for %d sentences", len(embeddings), len(sentences))
+	}
+	if err != nil {
+		slog.Warn("Semantic chunking failed, falling back to fixed-size chunks", "error", err)
+		return NewSimpleChunker(c.config).Chunk(content, ctx)
+	}
+
+	// similarity[i] compares the windows before and after the gap
+	// following sentence i
+	similarity := make([]float64, len(spans)-1)
+	for i := range similarity {
+		left := sumVectors(embeddings[max(0, i-semanticWindow+1) : i+1])
+		right := sumVectors(embeddings[i+1 : min(len(spans), i+1+semanticWindow)])
+		similarity[i] = cosineSimilarity(left, right)
+	}
+	isBoundary := func(gap int) bool {
+		sim := similarity[gap]
+		return sim < c.config.Threshold &&
+			(gap == 0 || sim <= similarity[gap-1]) &&
+			(gap == len(similarity)-1 || sim <= similarity[gap+1])
+	}
+
+	var chunks []Chunk
+	groupStart := 0
+	for i := 1; i <= len(spans); i++ {
+		if i < len(spans) {
+			topicShift := spans[i].start-spans[groupStart].start >= c.config.MinSize && isBoundary(i-1)
+			if !topicShift && spans[i].end-spans[groupStart].start <= c.config.MaxSize {
+				continue
+			}
+		}
+
+		start, end := spans[groupStart].start, spans[i-1].end
+		chunks = append(chunks, Chunk{
+			Content:   content[start:end],
+			StartLine: 1 + strings.Count(content[:start], "\n"),
+			EndLine:   1 + strings.Count(content[:end-1], "\n"),
+			StartByte: start,
+			EndByte:   end,
+			Index:     len(chunks),
+			Context:   ctx,
+		})
+		groupStart = i
+	}
+
+	for i := range chunks {
+		chunks[i].Total = len(chunks)
+	}
+	return chunks, nil
+}
+
+// splitSentences splits content at sentence ends and line breaks, so
+// headings and list items are sentences of their own. Spans cover all of
+// content; leading whitespace joins the first sentence.
+func splitSentences(content string) []sentenceSpan {
+	var spans []sentenceSpan
+	start := 0
+	for i := 0; i < len(content); i++ {
+		switch content[i] {
+		case '\n':
+		case '.', '!', '?':
+			if i+1 < len(content) && content[i+1] != ' ' && content[i+1] != '\t' && content[i+1] != '\n' {
+				continue
+			}
+		default:
+			continue
+		}
+
+		end := i + 1
+		for end < len(content) && isSpaceByte(content[end]) {
+			end++
+		}
+		if strings.TrimSpace(content[start:end]) != "" {
+			spans = append(spans, sentenceSpan{start: start, end: end})
+			start = end
+		}
+		i = end - 1
+	}
+
+	if start < len(content) {
+		if len(spans) > 0 && strings.TrimSpace(content[start:]) == "" {
+			spans[len(spans)-1].end = len(content)
+		} else {
+			spans = append(spans, sentenceSpan{start: start, end: len(content)})
+		}
+	}
+	return spans
+}
+
+func isSpaceByte(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// sumVectors returns the element-wise sum of vectors.
+func sumVectors(vectors [][]float32) []float32 {
+	sum := make([]float32, len(vectors[0]))
+	for _, v := range vectors {
+		for i := range min(len(sum), len(v)) {
+			sum[i] += v[i]
+		}
+	}
+	return sum
+}
+
+// cosineSimilarity returns the cosine similarity of a and b, or 0 if
+// either is a zero vector or their lengths differ.
+func cosineSimilarity(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
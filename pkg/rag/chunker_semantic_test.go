@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rag
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// topicEmbedder embeds text as keyword counts per topic, so sentences on
+// the same topic point the same way.
+type topicEmbedder struct {
+	topics [][]string
+	fail   bool
+	calls  int // EmbedBatch requests, including those made by Embed
+}
+
+func (e *topicEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	vectors, err := e.EmbedBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], nil
+}
+
+func (e *topicEmbedder) EmbedBatch(_ context.Context, texts []string) ([][]float32, error) {
+	e.calls++
+	if e.fail {
+		return nil, errors.New("embedder unavailable")
+	}
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		text = strings.ToLower(text)
+		vectors[i] = make([]float32, len(e.topics))
+		for t, keywords := range e.topics {
+			for _, kw := range keywords {
+				vectors[i][t] += float32(strings.Count(text, kw))
+			}
+		}
+	}
+	return vectors, nil
+}
+
+func (e *topicEmbedder) Dimension() int { return len(e.topics) }
+func (e *topicEmbedder) Model() string  { return "topics" }
+func (e *topicEmbedder) Close() error   { return nil }
+
+var fixtureTopics = [][]string{
+	{"pasta", "boil", "sauce"},
+	{"planet", "jupiter", "saturn", "stars"},
+	{"network", "latency", "packet", "router"},
+}
+
+func TestSemanticChunker_SplitsByTopic(t *testing.T) {
+	content, err := os.ReadFile("testdata/multi_topic.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chunker, err := NewChunker(ChunkerConfig{
+		Strategy:        ChunkerSemantic,
+		Size:            200,
+		MinSize:         50,
+		MinDocumentSize: 500,
+		Embedder:        &topicEmbedder{topics: fixtureTopics},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chunks, err := chunker.Chunk(string(content), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	headings := []string{"# Cooking Pasta", "# Observing Planets", "# Network Latency"}
+	if len(chunks) != len(headings) {
+		for _, c := range chunks {
+			t.Logf("chunk %d (lines %d-%d): %q", c.Index, c.StartLine, c.EndLine, c.Content)
+		}
+		t.Fatalf("expected %d chunks, got %d", len(headings), len(chunks))
+	}
+
+	var joined strings.Builder
+	for i, chunk := range chunks {
+		if !strings.HasPrefix(chunk.Content, headings[i]) {
+			t.Errorf("chunk %d starts with %q, want %q", i, firstLine(chunk.Content), headings[i])
+		}
+		if chunk.Total != len(chunks) || chunk.Index != i {
+			t.Errorf("chunk %d has index %d, total %d", i, chunk.Index, chunk.Total)
+		}
+		if got := string(content[chunk.StartByte:chunk.EndByte]); got != chunk.Content {
+			t.Errorf("chunk %d byte range doesn't match its content", i)
+		}
+		joined.WriteString(chunk.Content)
+	}
+	if joined.String() != string(content) {
+		t.Error("chunks don't cover the document")
+	}
+	if chunks[1].StartLine != 8 || chunks[1].EndLine != 14 {
+		t.Errorf("second chunk spans lines %d-%d, want 8-14", chunks[1].StartLine, chunks[1].EndLine)
+	}
+}
+
+func TestSemanticChunker_MaxSize(t *testing.T) {
+	content, err := os.ReadFile("testdata/multi_topic.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A single topic throughout: only the size limit splits
+	chunker := NewSemanticChunker(ChunkerConfig{
+		Size:            150,
+		MinSize:         50,
+		MaxSize:         300,
+		MinDocumentSize: 500,
+		Embedder:        &topicEmbedder{topics: [][]string{{"the"}}},
+	})
+	chunks, err := chunker.Chunk(string(content), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chunks) < 3 {
+		t.Errorf("expected the size limit to split the document, got %d chunks", len(chunks))
+	}
+	for _, chunk := range chunks {
+		if len(chunk.Content) > 300 {
+			t.Errorf("chunk %d is %d bytes, want at most 300", chunk.Index, len(chunk.Content))
+		}
+	}
+}
+
+func TestSemanticChunker_FallsBackToFixedSize(t *testing.T) {
+	content, err := os.ReadFile("testdata/multi_topic.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := ChunkerConfig{Size: 200, MinSize: 50, MinDocumentSize: 500}
+	want, _ := NewSimpleChunker(cfg).Chunk(string(content), nil)
+
+	// Below the minimum document size
+	cfg.MinDocumentSize = len(content) + 1
+	cfg.Embedder = &topicEmbedder{topics: fixtureTopics}
+	got, _ := NewSemanticChunker(cfg).Chunk(string(content), nil)
+	if len(got) != len(want) {
+		t.Errorf("short document: got %d chunks, want %d fixed-size chunks", len(got), len(want))
+	}
+
+	// Embedding failure
+	cfg.MinDocumentSize = 500
+	cfg.Embedder = &topicEmbedder{fail: true}
+	got, err = NewSemanticChunker(cfg).Chunk(string(content), nil)
+	if err != nil || len(got) != len(want) {
+		t.Errorf("embedder failure: got %d chunks (%v), want %d fixed-size chunks", len(got), err, len(want))
+	}
+}
+
+func TestSplitSentences(t *testing.T) {
+	content := "\n# Title\nVersion 1.5 is out. Upgrade now! Why?\n\nDone"
+	var got []string
+	for _, span := range splitSentences(content) {
+		got = append(got, content[span.start:span.end])
+	}
+	want := []string{"\n# Title\n", "Version 1.5 is out. ", "Upgrade now! ", "Why?\n\n", "Done"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("splitSentences() = %q, want %q", got, want)
+	}
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
@@ -270,11 +270,16 @@ func (c *OverlappingChunker) Config() ChunkerConfig {
 // Ensure OverlappingChunker implements Chunker.
 var _ Chunker = (*OverlappingChunker)(nil)
 
-// SemanticChunker implements AST-aware chunking that respects code structure.
+// SemanticChunker implements chunking that respects topic and code structure.
 //
-// This is a direct port of legacy pkg/context/chunking/semantic_chunker.go.
-// It attempts to keep functions and types together when possible, using
-// metadata to identify semantic boundaries.
+// With an embedder configured, it splits prose where the topic changes:
+// sentences are embedded, and a new chunk starts where the sentences
+// before and after a gap are least similar and below the threshold.
+// Documents shorter than MinDocumentSize are chunked by size.
+//
+// With function or type metadata, it keeps functions and types together
+// (a direct port of legacy pkg/context/chunking/semantic_chunker.go).
+// Otherwise it falls back to overlapping chunks.
 //
 // Use when:
 //   - Chunking code files
@@ -311,9 +316,15 @@ func (c *SemanticChunker) Chunk(content string, ctx *ChunkContext) ([]Chunk, err
 		}}, nil
 	}
 
-	// If no context/metadata available, fall back to overlapping chunking
-	// This matches legacy behavior
+	// Without code structure, split by topic if an embedder is available,
+	// otherwise fall back to overlapping chunking (legacy behavior)
 	if ctx == nil || (ctx.FunctionName == "" && ctx.TypeName == "") {
+		if c.config.Embedder != nil {
+			if len(content) < c.config.MinDocumentSize {
+				return NewSimpleChunker(c.config).Chunk(content, ctx)
+			}
+			return c.chunkByTopic(content, ctx)
+		}
 		overlapping := NewOverlappingChunker(c.config)
 		return overlapping.Chunk(content, ctx)
 	}
@@ -65,15 +65,17 @@ type ExtractorRegistry struct {
 
 // NewExtractorRegistry creates a new extractor registry with default extractors.
 // Registers:
-//   - BinaryExtractor (priority 5): PDF, DOCX, XLSX via native parsers
+//   - BinaryExtractor (priority 5): PDF, DOCX, XLSX, HTML via native parsers
 //   - TextExtractor (priority 1): Plain text files
-func NewExtractorRegistry() *ExtractorRegistry {
+//
+// nativeFormats limits which formats are extracted natively (empty = all).
+func NewExtractorRegistry(nativeFormats ...string) *ExtractorRegistry {
 	reg := &ExtractorRegistry{
 		extractors: make([]ContentExtractor, 0),
 	}
 
-	// Register binary extractor with native parsers (PDF, DOCX, XLSX)
-	nativeParsers := NewNativeParserRegistry()
+	// Register binary extractor with native parsers (PDF, DOCX, XLSX, HTML)
+	nativeParsers := NewNativeParserRegistry(nativeFormats...)
 	reg.Register(NewBinaryExtractor(nativeParsers))
 
 	// Register default text extractor (lowest priority fallback)
@@ -166,18 +166,22 @@ func newSQLSourceFromConfig(cfg *config.SQLSourceConfig, deps *FactoryDeps) (*SQ
 }
 
 // NewChunkerFromConfig creates a chunker from configuration.
-func NewChunkerFromConfig(cfg *config.ChunkingConfig) (Chunker, error) {
+// emb detects topic boundaries for the semantic strategy and may be nil.
+func NewChunkerFromConfig(cfg *config.ChunkingConfig, emb embedder.Embedder) (Chunker, error) {
 	if cfg == nil {
 		return NewSimpleChunker(DefaultChunkerConfig()), nil
 	}
 
 	ragCfg := ChunkerConfig{
-		Strategy:      ChunkerStrategy(cfg.Strategy),
-		Size:          cfg.Size,
-		Overlap:       cfg.Overlap,
-		MinSize:       cfg.MinSize,
-		MaxSize:       cfg.MaxSize,
-		PreserveWords: cfg.PreserveWords != nil && *cfg.PreserveWords,
+		Strategy:        ChunkerStrategy(cfg.Strategy),
+		Size:            cfg.Size,
+		Overlap:         cfg.Overlap,
+		MinSize:         cfg.MinSize,
+		MaxSize:         cfg.MaxSize,
+		PreserveWords:   cfg.PreserveWords != nil && *cfg.PreserveWords,
+		Threshold:       cfg.Threshold,
+		MinDocumentSize: cfg.MinDocumentSize,
+		Embedder:        emb,
 	}
 
 	return NewChunker(ragCfg)
@@ -238,7 +242,7 @@ func NewSearchEngineFromConfig(
 	}
 
 	// Create chunker
-	chunker, err := NewChunkerFromConfig(storeCfg.Chunking)
+	chunker, err := NewChunkerFromConfig(storeCfg.Chunking, emb)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create chunker: %w", err)
 	}
@@ -254,17 +258,9 @@ func NewSearchEngineFromConfig(
 	}
 
 	// Create optional reranker
-	var reranker *Reranker
-	if storeCfg.Search != nil && storeCfg.Search.EnableRerank && storeCfg.Search.RerankLLM != "" {
-		llm, ok := deps.LLMs[storeCfg.Search.RerankLLM]
-		if !ok {
-			return nil, fmt.Errorf("rerank LLM %q not found", storeCfg.Search.RerankLLM)
-		}
-		maxResults := 20
-		if storeCfg.Search.RerankMaxResults > 0 {
-			maxResults = storeCfg.Search.RerankMaxResults
-		}
-		reranker = NewReranker(llm, maxResults)
+	reranker, rerankTopN, err := newRerankerFromConfig(storeCfg.Search, deps)
+	if err != nil {
+		return nil, err
 	}
 
 	// Create optional multi-query
@@ -305,10 +301,80 @@ func NewSearchEngineFromConfig(
 		DefaultThreshold: storeCfg.Search.Threshold,
 		HyDE:             hyde,
 		Reranker:         reranker,
+		RerankTopN:       rerankTopN,
 		MultiQuery:       multiQuery,
+		SearchMode:       storeCfg.SearchMode,
+		KeywordWeight:    storeCfg.KeywordWeight,
 	})
 }
 
+// newRerankerFromConfig creates the configured reranker and the number of
+// candidates it reranks. It returns a nil reranker when reranking is off.
+func newRerankerFromConfig(searchCfg *config.DocumentSearchConfig, deps *FactoryDeps) (Reranker, int, error) {
+	if searchCfg == nil {
+		return nil, 0, nil
+	}
+
+	// Legacy enable_rerank settings
+	if searchCfg.Reranker == nil {
+		if !searchCfg.EnableRerank || searchCfg.RerankLLM == "" {
+			return nil, 0, nil
+		}
+		llm, ok := deps.LLMs[searchCfg.RerankLLM]
+		if !ok {
+			return nil, 0, fmt.Errorf("rerank LLM %q not found", searchCfg.RerankLLM)
+		}
+		maxResults := 20
+		if searchCfg.RerankMaxResults > 0 {
+			maxResults = searchCfg.RerankMaxResults
+		}
+		return NewLLMReranker(llm, maxResults), 0, nil
+	}
+
+	rc := searchCfg.Reranker
+	topN := rc.TopN
+	if topN <= 0 {
+		topN = 20
+	}
+	switch rc.Type {
+	case "llm":
+		llm, ok := deps.LLMs[rc.Model]
+		if !ok {
+			return nil, 0, fmt.Errorf("rerank LLM %q not found", rc.Model)
+		}
+		return NewLLMReranker(llm, topN), topN, nil
+	case "cross_encoder":
+		reranker, err := NewCrossEncoderReranker(CrossEncoderConfig{
+			Endpoint: rc.Endpoint,
+			Model:    rc.Model,
+			APIKey:   rc.APIKey,
+		})
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to create cross-encoder reranker: %w", err)
+		}
+		return reranker, topN, nil
+	default:
+		return nil, 0, fmt.Errorf("unsupported reranker type: %s", rc.Type)
+	}
+}
+
+// searchOptionsFromConfig returns the search enhancements enabled in
+// searchCfg, or nil when none is.
+func searchOptionsFromConfig(searchCfg *config.DocumentSearchConfig) *SearchOptions {
+	if searchCfg == nil {
+		return nil
+	}
+	opts := &SearchOptions{
+		EnableHyDE:       searchCfg.EnableHyDE,
+		EnableRerank:     searchCfg.EnableRerank || searchCfg.Reranker != nil,
+		EnableMultiQuery: searchCfg.EnableMultiQuery,
+	}
+	if !opts.EnableHyDE && !opts.EnableRerank && !opts.EnableMultiQuery {
+		return nil
+	}
+	return opts
+}
+
 // NewDocumentStoreFromConfig creates a document store from configuration.
 func NewDocumentStoreFromConfig(
 	name string,
@@ -334,22 +400,17 @@ func NewDocumentStoreFromConfig(
 		return nil, fmt.Errorf("failed to create search engine: %w", err)
 	}
 
-	// Create chunker
-	chunker, err := NewChunkerFromConfig(storeCfg.Chunking)
-	if err != nil {
-		source.Close()
-		return nil, fmt.Errorf("failed to create chunker: %w", err)
-	}
-
 	// Build internal config
 	internalCfg := DocumentStoreConfig{
 		Name:                name,
 		Source:              source,
 		SearchEngine:        engine,
-		Chunker:             chunker,
+		Chunker:             engine.chunker,
 		Collection:          collection,
 		Watch:               storeCfg.Watch,
 		IncrementalIndexing: storeCfg.IncrementalIndexing,
+		NativeExtractors:    storeCfg.NativeExtractors,
+		Search:              searchOptionsFromConfig(storeCfg.Search),
 	}
 
 	// Wire through indexing config if present
@@ -421,7 +482,9 @@ func NewDocumentStoreFromConfig(
 }
 
 // NewVectorProviderFromConfig creates a vector provider from configuration.
-func NewVectorProviderFromConfig(cfg *config.VectorStoreConfig) (vector.Provider, error) {
+// The pgvector provider takes its connection from deps.DBPool; other
+// providers ignore deps, which may be nil.
+func NewVectorProviderFromConfig(cfg *config.VectorStoreConfig, deps *FactoryDeps) (vector.Provider, error) {
 	if cfg == nil {
 		// Default to embedded chromem
 		return vector.NewChromemProvider(vector.ChromemConfig{})
@@ -439,15 +502,13 @@ func NewVectorProviderFromConfig(cfg *config.VectorStoreConfig) (vector.Provider
 		if cfg.EnableTLS != nil {
 			useTLS = *cfg.EnableTLS
 		}
-		port := cfg.Port
-		if port == 0 {
-			port = 6334 // Qdrant gRPC port
-		}
 		return vector.NewQdrantProvider(vector.QdrantConfig{
-			Host:   cfg.Host,
-			Port:   port,
-			APIKey: cfg.APIKey,
-			UseTLS: useTLS,
+			Host:     cfg.Host,
+			Port:     cfg.Port,
+			APIKey:   cfg.APIKey,
+			UseTLS:   useTLS,
+			Protocol: cfg.Protocol,
+			Distance: cfg.Distance,
 		})
 
 	case "pinecone":
@@ -506,11 +567,43 @@ func NewVectorProviderFromConfig(cfg *config.VectorStoreConfig) (vector.Provider
 			UseTLS: useTLS,
 		})
 
+	case "pgvector":
+		return newPgvectorProviderFromConfig(cfg, deps)
+
 	default:
 		return nil, fmt.Errorf("unsupported vector store type: %s", cfg.Type)
 	}
 }
 
+// newPgvectorProviderFromConfig creates a pgvector provider using DBPool.
+func newPgvectorProviderFromConfig(cfg *config.VectorStoreConfig, deps *FactoryDeps) (vector.Provider, error) {
+	if deps == nil || deps.DBPool == nil {
+		return nil, fmt.Errorf("DBPool is required for pgvector")
+	}
+	if deps.Config == nil {
+		return nil, fmt.Errorf("Config is required for pgvector")
+	}
+
+	dbCfg, ok := deps.Config.Databases[cfg.Database]
+	if !ok {
+		return nil, fmt.Errorf("database %q not found in configuration", cfg.Database)
+	}
+	if dbCfg.Driver != "postgres" {
+		return nil, fmt.Errorf("pgvector requires a postgres database, %q uses %s", cfg.Database, dbCfg.Driver)
+	}
+
+	// Get connection from pool (shares connection with other components)
+	conn, err := deps.DBPool.Get(dbCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	return vector.NewPgvectorProvider(vector.PgvectorConfig{
+		DB:    conn,
+		Index: cfg.Index,
+	})
+}
+
 // DBPoolAdapter wraps config.DBPool to provide sql.DB connections.
 type DBPoolAdapter struct {
 	pool *config.DBPool
@@ -0,0 +1,283 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rag
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/kadirpekel/hector/pkg/vector"
+)
+
+// Search modes.
+const (
+	// SearchModeVector ranks chunks by embedding similarity only.
+	SearchModeVector = "vector"
+
+	// SearchModeKeyword ranks chunks by BM25 keyword relevance only.
+	SearchModeKeyword = "keyword"
+
+	// SearchModeHybrid fuses vector and keyword rankings with
+	// reciprocal rank fusion.
+	SearchModeHybrid = "hybrid"
+)
+
+// DefaultKeywordWeight is the default share of the keyword ranking in
+// hybrid search.
+const DefaultKeywordWeight = 0.5
+
+// BM25 parameters (standard values).
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// rrfK dampens the influence of top ranks in reciprocal rank fusion.
+// 60 is the value from the original RRF paper.
+const rrfK = 60
+
+// KeywordIndex is an in-memory BM25 index over chunk text.
+//
+// It gives keyword and hybrid search to vector providers that have no
+// native full-text search. Chunks are added as they are ingested, so the
+// index only covers chunks ingested by this process.
+type KeywordIndex struct {
+	mu       sync.RWMutex
+	docs     map[string]*keywordDoc
+	docFreq  map[string]int
+	totalLen int
+}
+
+type keywordDoc struct {
+	terms    map[string]int
+	length   int
+	content  string
+	metadata map[string]any
+}
+
+// NewKeywordIndex creates an empty keyword index.
+func NewKeywordIndex() *KeywordIndex {
+	return &KeywordIndex{
+		docs:    make(map[string]*keywordDoc),
+		docFreq: make(map[string]int),
+	}
+}
+
+// Add indexes a chunk, replacing any chunk with the same ID.
+func (k *KeywordIndex) Add(id, content string, metadata map[string]any) {
+	tokens := tokenize(content)
+	terms := make(map[string]int, len(tokens))
+	for _, t := range tokens {
+		terms[t]++
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	k.remove(id)
+	k.docs[id] = &keywordDoc{
+		terms:    terms,
+		length:   len(tokens),
+		content:  content,
+		metadata: metadata,
+	}
+	for t := range terms {
+		k.docFreq[t]++
+	}
+	k.totalLen += len(tokens)
+}
+
+// Delete removes a chunk by ID.
+func (k *KeywordIndex) Delete(id string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.remove(id)
+}
+
+// DeleteByFilter removes all chunks whose metadata matches the filter.
+func (k *KeywordIndex) DeleteByFilter(filter map[string]any) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for id, doc := range k.docs {
+		if matchesFilter(doc.metadata, filter) {
+			k.remove(id)
+		}
+	}
+}
+
+// Clear removes all chunks.
+func (k *KeywordIndex) Clear() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.docs = make(map[string]*keywordDoc)
+	k.docFreq = make(map[string]int)
+	k.totalLen = 0
+}
+
+// Len returns the number of indexed chunks.
+func (k *KeywordIndex) Len() int {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return len(k.docs)
+}
+
+// Search returns up to topK chunks matching the query, ranked by BM25.
+// Scores are normalized so the best match scores 1.0.
+func (k *KeywordIndex) Search(query string, topK int, filter map[string]any) []vector.Result {
+	queryTerms := uniqueTerms(tokenize(query))
+	if len(queryTerms) == 0 {
+		return nil
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if len(k.docs) == 0 {
+		return nil
+	}
+	n := float64(len(k.docs))
+	avgLen := float64(k.totalLen) / n
+
+	var results []vector.Result
+	for id, doc := range k.docs {
+		if len(filter) > 0 && !matchesFilter(doc.metadata, filter) {
+			continue
+		}
+		var score float64
+		for _, t := range queryTerms {
+			tf := float64(doc.terms[t])
+			if tf == 0 {
+				continue
+			}
+			df := float64(k.docFreq[t])
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			score += idf * tf * (bm25K1 + 1) / (tf + bm25K1*(1-bm25B+bm25B*float64(doc.length)/avgLen))
+		}
+		if score > 0 {
+			results = append(results, vector.Result{
+				ID:       id,
+				Score:    float32(score),
+				Content:  doc.content,
+				Metadata: doc.metadata,
+			})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID < results[j].ID
+	})
+	if topK > 0 && len(results) > topK {
+		results = results[:topK]
+	}
+	if len(results) > 0 {
+		top := results[0].Score
+		for i := range results {
+			results[i].Score /= top
+		}
+	}
+	return results
+}
+
+// remove deletes a chunk. Caller must hold the write lock.
+func (k *KeywordIndex) remove(id string) {
+	doc, ok := k.docs[id]
+	if !ok {
+		return
+	}
+	for t := range doc.terms {
+		if k.docFreq[t]--; k.docFreq[t] <= 0 {
+			delete(k.docFreq, t)
+		}
+	}
+	k.totalLen -= doc.length
+	delete(k.docs, id)
+}
+
+// FuseResults merges vector and keyword rankings with weighted reciprocal
+// rank fusion. keywordWeight (0-1) is the keyword ranking's share.
+//
+// Fused scores are normalized so a result ranked first by both searches
+// scores 1.0.
+func FuseResults(vectorResults, keywordResults []SearchResult, keywordWeight float64) []SearchResult {
+	fused := make(map[string]*SearchResult)
+	scores := make(map[string]float64)
+	var order []string
+
+	add := func(results []SearchResult, weight float64) {
+		for rank, r := range results {
+			if _, ok := fused[r.ID]; !ok {
+				r := r
+				fused[r.ID] = &r
+				order = append(order, r.ID)
+			}
+			scores[r.ID] += weight / float64(rrfK+rank+1)
+		}
+	}
+	add(vectorResults, 1-keywordWeight)
+	add(keywordResults, keywordWeight)
+
+	results := make([]SearchResult, 0, len(order))
+	for _, id := range order {
+		r := *fused[id]
+		r.Score = float32(scores[id] * (rrfK + 1))
+		results = append(results, r)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	return results
+}
+
+// tokenize splits text into lowercase terms. Letters, digits and
+// underscores form terms, so identifiers and error codes stay intact
+// ("ERR_TIMEOUT", "0x1f"), while other punctuation separates terms.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+}
+
+// uniqueTerms removes duplicate terms, keeping the first occurrence.
+func uniqueTerms(terms []string) []string {
+	seen := make(map[string]bool, len(terms))
+	out := terms[:0]
+	for _, t := range terms {
+		if !seen[t] {
+			seen[t] = true
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// matchesFilter reports whether metadata has every filter field with an
+// equal value.
+func matchesFilter(metadata, filter map[string]any) bool {
+	for key, want := range filter {
+		got, ok := metadata[key]
+		if !ok || fmt.Sprint(got) != fmt.Sprint(want) {
+			return false
+		}
+	}
+	return true
+}
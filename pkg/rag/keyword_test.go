@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rag
+
+import "testing"
+
+func TestKeywordIndexSearch(t *testing.T) {
+	idx := NewKeywordIndex()
+	idx.Add("a", "The server returned ERR_TIMEOUT after 30 seconds", map[string]any{"document_id": "logs"})
+	idx.Add("b", "Timeouts are configured per request", map[string]any{"document_id": "docs"})
+	idx.Add("c", "Nothing relevant here", map[string]any{"document_id": "docs"})
+
+	results := idx.Search("err_timeout", 10, nil)
+	if len(results) != 1 || results[0].ID != "a" || results[0].Score != 1 {
+		t.Fatalf("Search(err_timeout) = %+v, want only chunk a with score 1", results)
+	}
+
+	if got := idx.Search("request", 10, map[string]any{"document_id": "logs"}); len(got) != 0 {
+		t.Errorf("filtered search returned %+v, want none", got)
+	}
+
+	idx.DeleteByFilter(map[string]any{"document_id": "logs"})
+	if got := idx.Search("err_timeout", 10, nil); len(got) != 0 {
+		t.Errorf("search after delete returned %+v, want none", got)
+	}
+	if idx.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", idx.Len())
+	}
+}
+
+func TestFuseResults(t *testing.T) {
+	vectorResults := []SearchResult{{ID: "semantic"}, {ID: "both"}}
+	keywordResults := []SearchResult{{ID: "both"}, {ID: "exact"}}
+
+	fused := FuseResults(vectorResults, keywordResults, 0.5)
+	if len(fused) != 3 || fused[0].ID != "both" {
+		t.Fatalf("FuseResults() = %+v, want 3 results led by the chunk found by both", fused)
+	}
+
+	fused = FuseResults([]SearchResult{{ID: "x"}}, []SearchResult{{ID: "x"}}, 0.5)
+	if fused[0].Score < 0.999 || fused[0].Score > 1.001 {
+		t.Errorf("top-ranked in both scored %v, want 1", fused[0].Score)
+	}
+
+	fused = FuseResults(vectorResults, keywordResults, 0.9)
+	if fused[0].ID != "both" || fused[1].ID != "exact" {
+		t.Errorf("keyword-weighted fusion order = %v, %v", fused[0].ID, fused[1].ID)
+	}
+}
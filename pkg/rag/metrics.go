@@ -32,6 +32,7 @@ type IndexMetrics struct {
 	indexedDocs int64
 	skippedDocs int64
 	errorDocs   int64
+	embeddings  int64
 
 	// Timing
 	startTime time.Time
@@ -62,6 +63,7 @@ func (m *IndexMetrics) Reset() {
 	m.indexedDocs = 0
 	m.skippedDocs = 0
 	m.errorDocs = 0
+	m.embeddings = 0
 	m.startTime = time.Time{}
 	m.endTime = time.Time{}
 	m.searchCount = 0
@@ -104,6 +106,11 @@ func (m *IndexMetrics) IncrementErrors() {
 	atomic.AddInt64(&m.errorDocs, 1)
 }
 
+// AddEmbeddings adds n embedded chunks to the throughput count.
+func (m *IndexMetrics) AddEmbeddings(n int) {
+	atomic.AddInt64(&m.embeddings, int64(n))
+}
+
 // RecordSearch records a search operation with latency.
 func (m *IndexMetrics) RecordSearch(latency time.Duration) {
 	latencyNs := latency.Nanoseconds()
@@ -130,10 +137,11 @@ func (m *IndexMetrics) Snapshot() IndexMetricsSnapshot {
 
 	total := atomic.LoadInt64(&m.totalDocs)
 	indexed := atomic.LoadInt64(&m.indexedDocs)
+	embeddings := atomic.LoadInt64(&m.embeddings)
 	searchCount := atomic.LoadInt64(&m.searchCount)
 	searchLatencySum := atomic.LoadInt64(&m.searchLatencySum)
 
-	var docsPerSec float64
+	var docsPerSec, embeddingsPerSec float64
 	var avgSearchLatency time.Duration
 
 	if !m.startTime.IsZero() {
@@ -144,6 +152,7 @@ func (m *IndexMetrics) Snapshot() IndexMetricsSnapshot {
 		elapsed := endTime.Sub(m.startTime).Seconds()
 		if elapsed > 0 {
 			docsPerSec = float64(indexed) / elapsed
+			embeddingsPerSec = float64(embeddings) / elapsed
 		}
 	}
 
@@ -158,6 +167,8 @@ func (m *IndexMetrics) Snapshot() IndexMetricsSnapshot {
 		SkippedDocs:       atomic.LoadInt64(&m.skippedDocs),
 		ErrorDocs:         atomic.LoadInt64(&m.errorDocs),
 		DocsPerSecond:     docsPerSec,
+		Embeddings:        embeddings,
+		EmbeddingsPerSec:  embeddingsPerSec,
 		StartTime:         m.startTime,
 		EndTime:           m.endTime,
 		SearchCount:       searchCount,
@@ -175,6 +186,8 @@ type IndexMetricsSnapshot struct {
 	SkippedDocs       int64         `json:"skipped_docs"`
 	ErrorDocs         int64         `json:"error_docs"`
 	DocsPerSecond     float64       `json:"docs_per_second"`
+	Embeddings        int64         `json:"embeddings"`
+	EmbeddingsPerSec  float64       `json:"embeddings_per_second"`
 	StartTime         time.Time     `json:"start_time,omitempty"`
 	EndTime           time.Time     `json:"end_time,omitempty"`
 	SearchCount       int64         `json:"search_count"`
@@ -26,13 +26,26 @@ import (
 	"github.com/ledongthuc/pdf"
 	"github.com/nguyenthenguyen/docx"
 	"github.com/xuri/excelize/v2"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
 )
 
-// NativeParserRegistry manages native document parsers for PDF, DOCX, XLSX.
+// nativeFormatExtensions maps native extractor format names to file extensions.
+var nativeFormatExtensions = map[string][]string{
+	"pdf":  {".pdf"},
+	"docx": {".docx"},
+	"xlsx": {".xlsx"},
+	"html": {".html", ".htm"},
+}
+
+// NativeParserRegistry manages native document parsers for PDF, DOCX, XLSX, HTML.
 //
 // Ported from legacy pkg/context/native_parsers.go
 type NativeParserRegistry struct {
 	parsers []nativeParserImpl
+
+	// enabled restricts parsing to these extensions (nil = all).
+	enabled map[string]bool
 }
 
 // nativeParserImpl is the internal interface for individual parsers.
@@ -43,14 +56,26 @@ type nativeParserImpl interface {
 }
 
 // NewNativeParserRegistry creates a new native parser registry with built-in parsers.
-func NewNativeParserRegistry() *NativeParserRegistry {
+// formats limits which formats are parsed natively ("pdf", "docx", "xlsx", "html");
+// when empty, all built-in formats are enabled.
+func NewNativeParserRegistry(formats ...string) *NativeParserRegistry {
 	registry := &NativeParserRegistry{
 		parsers: make([]nativeParserImpl, 0),
 	}
 
+	if len(formats) > 0 {
+		registry.enabled = make(map[string]bool)
+		for _, format := range formats {
+			for _, ext := range nativeFormatExtensions[strings.ToLower(format)] {
+				registry.enabled[ext] = true
+			}
+		}
+	}
+
 	// Register built-in parsers
 	registry.parsers = append(registry.parsers, &pdfParser{})
 	registry.parsers = append(registry.parsers, &officeParser{})
+	registry.parsers = append(registry.parsers, &htmlParser{})
 
 	return registry
 }
@@ -71,6 +96,9 @@ func (r *NativeParserRegistry) ParseDocument(ctx context.Context, filePath strin
 
 // findParser returns the appropriate parser for the file.
 func (r *NativeParserRegistry) findParser(filePath string) nativeParserImpl {
+	if !r.isEnabled(strings.ToLower(filepath.Ext(filePath))) {
+		return nil
+	}
 	for _, parser := range r.parsers {
 		if parser.CanParse(filePath) {
 			return parser
@@ -85,7 +113,9 @@ func (r *NativeParserRegistry) GetSupportedExtensions() []string {
 
 	for _, parser := range r.parsers {
 		for _, ext := range parser.GetSupportedExtensions() {
-			extensions[ext] = true
+			if r.isEnabled(ext) {
+				extensions[ext] = true
+			}
 		}
 	}
 
@@ -97,6 +127,11 @@ func (r *NativeParserRegistry) GetSupportedExtensions() []string {
 	return result
 }
 
+// isEnabled reports whether native parsing is enabled for the extension.
+func (r *NativeParserRegistry) isEnabled(ext string) bool {
+	return r.enabled == nil || r.enabled[ext]
+}
+
 // Ensure NativeParserRegistry implements NativeParser.
 var _ NativeParser = (*NativeParserRegistry)(nil)
 
@@ -339,3 +374,112 @@ func columnLetter(index int) string {
 	}
 	return result
 }
+
+// =============================================================================
+// HTML Parser
+// =============================================================================
+
+// htmlParser extracts readable text from HTML documents.
+type htmlParser struct{}
+
+// htmlSkippedElements are elements whose content is never indexed.
+var htmlSkippedElements = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Template: true,
+	atom.Svg:      true,
+}
+
+// htmlBlockElements start a new line in the extracted text.
+var htmlBlockElements = map[atom.Atom]bool{
+	atom.P: true, atom.Div: true, atom.Br: true, atom.Li: true, atom.Tr: true,
+	atom.H1: true, atom.H2: true, atom.H3: true, atom.H4: true, atom.H5: true, atom.H6: true,
+	atom.Section: true, atom.Article: true, atom.Header: true, atom.Footer: true,
+	atom.Blockquote: true, atom.Pre: true, atom.Table: true, atom.Ul: true, atom.Ol: true,
+}
+
+func (p *htmlParser) CanParse(filePath string) bool {
+	ext := strings.ToLower(filepath.Ext(filePath))
+	return ext == ".html" || ext == ".htm"
+}
+
+func (p *htmlParser) GetSupportedExtensions() []string {
+	return []string{".html", ".htm"}
+}
+
+func (p *htmlParser) Parse(ctx context.Context, filePath string, fileSize int64) (*NativeParseResult, error) {
+	startTime := time.Now()
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return &NativeParseResult{
+			Success:          false,
+			Error:            fmt.Sprintf("failed to open HTML file: %v", err),
+			ProcessingTimeMs: time.Since(startTime).Milliseconds(),
+		}, nil
+	}
+	defer file.Close()
+
+	doc, err := html.Parse(file)
+	if err != nil {
+		return &NativeParseResult{
+			Success:          false,
+			Error:            fmt.Sprintf("failed to parse HTML: %v", err),
+			ProcessingTimeMs: time.Since(startTime).Milliseconds(),
+		}, nil
+	}
+
+	title := ""
+	var text strings.Builder
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			if n.DataAtom == atom.Title {
+				if title == "" && n.FirstChild != nil {
+					title = strings.TrimSpace(n.FirstChild.Data)
+				}
+				return
+			}
+			if htmlSkippedElements[n.DataAtom] {
+				return
+			}
+			if htmlBlockElements[n.DataAtom] {
+				text.WriteString("\n")
+			}
+		}
+		if n.Type == html.TextNode {
+			if s := strings.Join(strings.Fields(n.Data), " "); s != "" {
+				text.WriteString(s)
+				text.WriteString(" ")
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	var lines []string
+	for _, line := range strings.Split(text.String(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	if title == "" {
+		title = filepath.Base(filePath)
+	}
+	metadata := map[string]string{
+		"title": title,
+		"type":  "HTML Document",
+	}
+
+	return &NativeParseResult{
+		Success:          true,
+		Content:          strings.Join(lines, "\n"),
+		Title:            title,
+		Metadata:         metadata,
+		ProcessingTimeMs: time.Since(startTime).Milliseconds(),
+	}, nil
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rag
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHTMLParserExtractsVisibleText(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "page.html")
+	page := `<html><head><title>Release Notes</title><style>body{color:red}</style></head>
+<body><h1>Version 2</h1><p>Adds   native
+extraction.</p><script>alert("x")</script></body></html>`
+	if err := os.WriteFile(path, []byte(page), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := NewNativeParserRegistry().ParseDocument(context.Background(), path, int64(len(page)))
+	if err != nil {
+		t.Fatalf("ParseDocument() error = %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("ParseDocument() failed: %s", result.Error)
+	}
+	if result.Title != "Release Notes" {
+		t.Errorf("Title = %q, want Release Notes", result.Title)
+	}
+	if result.Content != "Version 2\nAdds native extraction." {
+		t.Errorf("Content = %q", result.Content)
+	}
+	if strings.Contains(result.Content, "alert") || strings.Contains(result.Content, "color") {
+		t.Errorf("Content includes script or style: %q", result.Content)
+	}
+}
+
+func TestNativeFormatsLimitExtraction(t *testing.T) {
+	reg := NewExtractorRegistry("pdf", "html")
+
+	tests := map[string]string{
+		"report.pdf":  "BinaryExtractor",
+		"page.htm":    "BinaryExtractor",
+		"notes.docx":  "",
+		"sheet.xlsx":  "",
+		"readme.md":   "",
+		"slides.pptx": "",
+	}
+	for path, want := range tests {
+		got := ""
+		for _, ext := range reg.extractors {
+			if ext.Priority() > 1 && ext.CanExtract(path, "") {
+				got = ext.Name()
+			}
+		}
+		if got != want {
+			t.Errorf("%s: extractor = %q, want %q", path, got, want)
+		}
+	}
+}
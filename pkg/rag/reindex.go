@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rag
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// IndexDiff counts the changes made by an indexing run.
+type IndexDiff struct {
+	// Added is the number of chunks indexed for new documents.
+	Added int `json:"added"`
+
+	// Updated is the number of chunks re-indexed for changed documents.
+	Updated int `json:"updated"`
+
+	// Deleted is the number of chunks removed with documents that are
+	// no longer in the source.
+	Deleted int `json:"deleted"`
+
+	// Unchanged is the number of documents skipped because their content
+	// hash matched the indexed version.
+	Unchanged int `json:"unchanged"`
+
+	// Failed is the number of documents that couldn't be indexed.
+	Failed int `json:"failed"`
+}
+
+// Changed reports whether any chunks were added, updated or deleted.
+func (d IndexDiff) Changed() bool {
+	return d.Added > 0 || d.Updated > 0 || d.Deleted > 0
+}
+
+func (d *IndexDiff) add(other IndexDiff) {
+	d.Added += other.Added
+	d.Updated += other.Updated
+	d.Deleted += other.Deleted
+	d.Unchanged += other.Unchanged
+	d.Failed += other.Failed
+}
+
+// indexedFile records the indexed version of a document.
+type indexedFile struct {
+	hash    string
+	modTime time.Time
+	chunks  int
+}
+
+// contentHashKey is the chunk metadata key holding the document's content hash.
+const contentHashKey = "content_hash"
+
+// contentHash returns the hex SHA-256 of a document's raw content.
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// Reindex brings the index up to date with the source.
+//
+// Only documents whose content hash changed are re-embedded; their old
+// chunks are replaced. Documents the store indexed before but the source
+// no longer has are deleted. After a restart, hashes are read back from
+// the vector store metadata, so unchanged documents aren't re-embedded
+// either. Documents removed while the store wasn't running are only
+// noticed by the watcher or a full rebuild.
+func (s *DocumentStore) Reindex(ctx context.Context) (IndexDiff, error) {
+	return s.index(ctx, true)
+}
+
+// OnReindex sets a function called with the changes made each time file
+// watching re-indexes documents.
+func (s *DocumentStore) OnReindex(fn func(IndexDiff)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onReindex = fn
+}
+
+// reindexDocument indexes doc unless force is false and its content is
+// unchanged, replacing the chunks of any previous version.
+func (s *DocumentStore) reindexDocument(ctx context.Context, doc Document, force bool) (IndexDiff, error) {
+	hash := contentHash(doc.Content)
+	modTime := documentModTime(doc)
+
+	s.mu.RLock()
+	prev, known := s.indexedDocs[doc.ID]
+	s.mu.RUnlock()
+	stored := false
+	if !known {
+		prev.hash, prev.chunks, known = s.engine.storedDocument(ctx, doc.ID)
+		stored = known
+	}
+
+	if known && !force && prev.hash == hash {
+		if stored {
+			// The vectors survived a restart but the keyword index didn't
+			s.restoreKeywords(ctx, doc)
+		}
+		s.recordIndexed(doc.ID, indexedFile{hash: hash, modTime: modTime, chunks: prev.chunks})
+		return IndexDiff{Unchanged: 1}, nil
+	}
+
+	if known {
+		if err := s.engine.DeleteDocument(ctx, doc.ID); err != nil {
+			return IndexDiff{}, fmt.Errorf("failed to delete previous version: %w", err)
+		}
+	}
+
+	if doc.Metadata == nil {
+		doc.Metadata = make(map[string]any)
+	}
+	doc.Metadata[contentHashKey] = hash
+	chunks, err := s.indexSingleDocument(ctx, doc)
+	if err != nil {
+		if known {
+			s.forgetIndexed(doc.ID)
+		}
+		return IndexDiff{}, err
+	}
+	s.recordIndexed(doc.ID, indexedFile{hash: hash, modTime: modTime, chunks: chunks})
+
+	if known {
+		return IndexDiff{Updated: chunks}, nil
+	}
+	return IndexDiff{Added: chunks}, nil
+}
+
+// removeDocument deletes a document's chunks from the index.
+func (s *DocumentStore) removeDocument(ctx context.Context, docID string) (IndexDiff, error) {
+	s.mu.RLock()
+	prev := s.indexedDocs[docID]
+	s.mu.RUnlock()
+
+	if err := s.engine.DeleteDocument(ctx, docID); err != nil {
+		return IndexDiff{}, err
+	}
+	s.forgetIndexed(docID)
+	return IndexDiff{Deleted: prev.chunks}, nil
+}
+
+func (s *DocumentStore) recordIndexed(docID string, file indexedFile) {
+	s.mu.Lock()
+	s.indexedDocs[docID] = file
+	s.mu.Unlock()
+}
+
+func (s *DocumentStore) forgetIndexed(docID string) {
+	s.mu.Lock()
+	delete(s.indexedDocs, docID)
+	s.mu.Unlock()
+}
+
+// documentModTime returns the modification time from a document's
+// metadata, or the zero time.
+func documentModTime(doc Document) time.Time {
+	if mt, ok := doc.Metadata["last_modified"].(int64); ok {
+		return time.Unix(mt, 0)
+	}
+	return time.Time{}
+}
+
+// metadataInt reads an integer from vector store metadata, which some
+// providers return as a float or a string.
+func metadataInt(v any) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	case string:
+		i, _ := strconv.Atoi(n)
+		return i
+	}
+	return 0
+}
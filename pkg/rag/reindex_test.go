@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rag
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kadirpekel/hector/pkg/vector"
+)
+
+func newReindexTestStore(t *testing.T, dir string, provider vector.Provider) *DocumentStore {
+	t.Helper()
+	source, err := NewDirectorySourceFromConfig(DirectorySourceConfig{Path: dir, Include: []string{"*.md"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine, err := NewSearchEngine(SearchEngineConfig{
+		Provider:   provider,
+		Embedder:   &topicEmbedder{topics: [][]string{{"a"}, {"e"}, {"o"}}},
+		Chunker:    NewSimpleChunker(ChunkerConfig{Size: 40}),
+		Collection: "docs",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := NewDocumentStore(DocumentStoreConfig{
+		Name:                "docs",
+		Source:              source,
+		SearchEngine:        engine,
+		IncrementalIndexing: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDocumentStoreReindex(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.md"), "Alpha line one\nAlpha line two\nAlpha line three\n")
+	writeFile(t, filepath.Join(dir, "b.md"), "Beta notes\n")
+
+	provider, err := vector.NewChromemProvider(vector.ChromemConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := newReindexTestStore(t, dir, provider)
+
+	diff, err := store.Reindex(ctx)
+	if err != nil {
+		t.Fatalf("Reindex() error = %v", err)
+	}
+	if diff.Added != 3 || diff.Updated != 0 || diff.Unchanged != 0 {
+		t.Fatalf("first Reindex() = %+v, want 3 added chunks", diff)
+	}
+
+	// Saving without changes is not re-embedded
+	writeFile(t, filepath.Join(dir, "b.md"), "Beta notes\n")
+	diff, _ = store.Reindex(ctx)
+	if diff.Changed() || diff.Unchanged != 2 {
+		t.Errorf("unchanged Reindex() = %+v, want 2 unchanged documents", diff)
+	}
+
+	writeFile(t, filepath.Join(dir, "a.md"), "Alpha rewritten\n")
+	diff, _ = store.Reindex(ctx)
+	if diff.Updated != 1 || diff.Added != 0 || diff.Unchanged != 1 {
+		t.Errorf("Reindex() after edit = %+v, want 1 updated chunk", diff)
+	}
+	results, err := provider.SearchWithFilter(ctx, "docs", []float32{1, 1, 1}, 10, map[string]any{"document_id": filepath.Join(dir, "a.md")})
+	if err != nil || len(results) != 1 {
+		t.Errorf("chunks of edited document = %d (%v), want 1", len(results), err)
+	}
+
+	if err := os.Remove(filepath.Join(dir, "b.md")); err != nil {
+		t.Fatal(err)
+	}
+	diff, _ = store.Reindex(ctx)
+	if diff.Deleted != 1 || diff.Unchanged != 1 {
+		t.Errorf("Reindex() after delete = %+v, want 1 deleted chunk", diff)
+	}
+
+	// A new store over the same vector store reads hashes back from metadata
+	restarted := newReindexTestStore(t, dir, provider)
+	diff, _ = restarted.Reindex(ctx)
+	if diff.Changed() || diff.Unchanged != 1 {
+		t.Errorf("Reindex() after restart = %+v, want 1 unchanged document", diff)
+	}
+}
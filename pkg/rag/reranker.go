@@ -28,7 +28,16 @@ import (
 	"github.com/kadirpekel/hector/pkg/model"
 )
 
-// Reranker re-ranks search results using an LLM.
+// Reranker reorders search candidates by relevance to the query.
+//
+// The search engine fetches the top-N candidates from the vector store,
+// passes them to the reranker and trims the reordered results to top-k.
+// Implementations replace the original similarity scores with their own.
+type Reranker interface {
+	Rerank(ctx context.Context, query string, results []SearchResult) (*RerankResult, error)
+}
+
+// LLMReranker re-ranks search results using an LLM.
 //
 // Reranking improves search quality by:
 //   - Using deeper semantic understanding than vector similarity
@@ -41,12 +50,12 @@ import (
 //   - Only practical for small result sets (10-20 items)
 //
 // Derived from legacy pkg/context/reranking/reranker.go
-type Reranker struct {
+type LLMReranker struct {
 	llm        model.LLM
 	maxResults int
 }
 
-// RerankConfig configures the reranker.
+// RerankResult contains reranked search results.
 type RerankResult struct {
 	// Results are the reranked search results.
 	Results []SearchResult
@@ -67,12 +76,12 @@ type RankingDecision struct {
 	Reason string `json:"reason,omitempty"`
 }
 
-// NewReranker creates a new reranker.
-func NewReranker(llm model.LLM, maxResults int) *Reranker {
+// NewLLMReranker creates a new LLM-based reranker.
+func NewLLMReranker(llm model.LLM, maxResults int) *LLMReranker {
 	if maxResults <= 0 {
 		maxResults = 20
 	}
-	return &Reranker{
+	return &LLMReranker{
 		llm:        llm,
 		maxResults: maxResults,
 	}
@@ -89,7 +98,7 @@ func NewReranker(llm model.LLM, maxResults int) *Reranker {
 // After reranking:
 //   - Scores are position-based (1st=1.0, 2nd=0.95, etc.)
 //   - Original vector similarity scores are replaced
-func (r *Reranker) Rerank(ctx context.Context, query string, results []SearchResult) (*RerankResult, error) {
+func (r *LLMReranker) Rerank(ctx context.Context, query string, results []SearchResult) (*RerankResult, error) {
 	if r.llm == nil {
 		return nil, fmt.Errorf("LLM is required for reranking")
 	}
@@ -159,7 +168,7 @@ func (r *Reranker) Rerank(ctx context.Context, query string, results []SearchRes
 }
 
 // buildRerankPrompt creates the prompt for reranking.
-func (r *Reranker) buildRerankPrompt(query string, results []SearchResult) string {
+func (r *LLMReranker) buildRerankPrompt(query string, results []SearchResult) string {
 	var sb strings.Builder
 
 	sb.WriteString(fmt.Sprintf(`Given the query: "%s"
@@ -186,7 +195,7 @@ Only include the JSON array, no other text.`)
 }
 
 // parseRankings extracts ranking decisions from LLM response.
-func (r *Reranker) parseRankings(response string, numResults int) ([]RankingDecision, error) {
+func (r *LLMReranker) parseRankings(response string, numResults int) ([]RankingDecision, error) {
 	// Find JSON array in response
 	start := strings.Index(response, "[")
 	end := strings.LastIndex(response, "]")
@@ -230,7 +239,7 @@ func (r *Reranker) parseRankings(response string, numResults int) ([]RankingDeci
 }
 
 // applyRankings reorders results based on rankings.
-func (r *Reranker) applyRankings(results []SearchResult, rankings []RankingDecision) []SearchResult {
+func (r *LLMReranker) applyRankings(results []SearchResult, rankings []RankingDecision) []SearchResult {
 	reranked := make([]SearchResult, len(rankings))
 
 	for i, ranking := range rankings {
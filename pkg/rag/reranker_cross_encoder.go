@@ -0,0 +1,210 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rag
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// CrossEncoderReranker re-ranks search results with a cross-encoder
+// served over HTTP.
+//
+// Cross-encoders score each query-document pair jointly, which is more
+// accurate than comparing embeddings and much cheaper than an LLM. The
+// reranker POSTs the query and candidate texts to the endpoint:
+//
+//	{"model": "...", "query": "...", "documents": ["...", ...], "top_n": 3}
+//
+// and accepts either the Cohere/Jina style response
+//
+//	{"results": [{"index": 2, "relevance_score": 0.93}, ...]}
+//
+// or a bare list as returned by Hugging Face text-embeddings-inference:
+//
+//	[{"index": 2, "score": 0.93}, ...]
+type CrossEncoderReranker struct {
+	client   *http.Client
+	endpoint string
+	model    string
+	apiKey   string
+}
+
+// CrossEncoderConfig configures the cross-encoder reranker.
+type CrossEncoderConfig struct {
+	// Endpoint is the rerank URL, e.g. http://localhost:8080/rerank (required).
+	Endpoint string
+
+	// Model is sent with each request for services hosting several models.
+	Model string
+
+	// APIKey is sent as a bearer token when set.
+	APIKey string
+
+	// Timeout for rerank requests (default: 30s).
+	Timeout time.Duration
+}
+
+type crossEncoderRequest struct {
+	Model     string   `json:"model,omitempty"`
+	Query     string   `json:"query"`
+	Documents []string `json:"documents"`
+	Texts     []string `json:"texts"`
+	TopN      int      `json:"top_n"`
+}
+
+type crossEncoderScore struct {
+	Index          int      `json:"index"`
+	RelevanceScore *float64 `json:"relevance_score"`
+	Score          *float64 `json:"score"`
+}
+
+// NewCrossEncoderReranker creates a new cross-encoder reranker.
+func NewCrossEncoderReranker(cfg CrossEncoderConfig) (*CrossEncoderReranker, error) {
+	if cfg.Endpoint == "" {
+		return nil, fmt.Errorf("endpoint is required")
+	}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	return &CrossEncoderReranker{
+		client:   &http.Client{Timeout: timeout},
+		endpoint: cfg.Endpoint,
+		model:    cfg.Model,
+		apiKey:   cfg.APIKey,
+	}, nil
+}
+
+// Rerank orders results by the cross-encoder's relevance scores.
+//
+// Results the service doesn't score are dropped. On request failure the
+// original order is returned, like the LLM reranker does.
+func (r *CrossEncoderReranker) Rerank(ctx context.Context, query string, results []SearchResult) (*RerankResult, error) {
+	if len(results) == 0 {
+		return &RerankResult{Results: results}, nil
+	}
+
+	scores, err := r.score(ctx, query, results)
+	if err != nil {
+		slog.Warn("Cross-encoder reranking failed, returning original order", "error", err)
+		return &RerankResult{Results: results}, nil
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		return scores[i].score > scores[j].score
+	})
+
+	reranked := make([]SearchResult, 0, len(scores))
+	seen := make(map[int]bool, len(scores))
+	for _, s := range scores {
+		if s.index < 0 || s.index >= len(results) || seen[s.index] {
+			continue
+		}
+		seen[s.index] = true
+		result := results[s.index]
+		result.Score = float32(s.score)
+		reranked = append(reranked, result)
+	}
+
+	slog.Debug("Reranked search results with cross-encoder",
+		"query", query,
+		"original_count", len(results),
+		"reranked_count", len(reranked))
+
+	return &RerankResult{Results: reranked}, nil
+}
+
+type indexScore struct {
+	index int
+	score float64
+}
+
+// score sends the candidates to the endpoint and returns their scores.
+func (r *CrossEncoderReranker) score(ctx context.Context, query string, results []SearchResult) ([]indexScore, error) {
+	docs := make([]string, len(results))
+	for i, result := range results {
+		docs[i] = result.Content
+	}
+
+	// Send the texts under both names so Cohere/Jina style services and
+	// text-embeddings-inference accept the same request
+	body, err := json.Marshal(crossEncoderRequest{
+		Model:     r.model,
+		Query:     query,
+		Documents: docs,
+		Texts:     docs,
+		TopN:      len(docs),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if r.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+r.apiKey)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send rerank request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("rerank endpoint returned status %d: %s", resp.StatusCode, string(data))
+	}
+
+	var raw []crossEncoderScore
+	if err := json.Unmarshal(data, &raw); err != nil {
+		var wrapped struct {
+			Results []crossEncoderScore `json:"results"`
+		}
+		if err := json.Unmarshal(data, &wrapped); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		raw = wrapped.Results
+	}
+
+	scores := make([]indexScore, 0, len(raw))
+	for _, s := range raw {
+		switch {
+		case s.RelevanceScore != nil:
+			scores = append(scores, indexScore{index: s.Index, score: *s.RelevanceScore})
+		case s.Score != nil:
+			scores = append(scores, indexScore{index: s.Index, score: *s.Score})
+		}
+	}
+	if len(scores) == 0 {
+		return nil, fmt.Errorf("response contains no scores")
+	}
+	return scores, nil
+}
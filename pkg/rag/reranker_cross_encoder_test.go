@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rag
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCrossEncoderReranker(t *testing.T) {
+	candidates := []SearchResult{
+		{ID: "a", Content: "Go channels", Score: 0.9},
+		{ID: "b", Content: "Rate limiting in Go", Score: 0.8},
+		{ID: "c", Content: "Cooking pasta", Score: 0.7},
+	}
+
+	responses := map[string]string{
+		"cohere": `{"results": [{"index": 1, "relevance_score": 0.95}, {"index": 0, "relevance_score": 0.4}, {"index": 2, "relevance_score": 0.01}]}`,
+		"tei":    `[{"index": 1, "score": 0.95}, {"index": 0, "score": 0.4}, {"index": 2, "score": 0.01}]`,
+	}
+	for name, body := range responses {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var req crossEncoderRequest
+				if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+					t.Errorf("decode request: %v", err)
+				}
+				if req.Query != "rate limits" || len(req.Documents) != 3 || req.Model != "bge" {
+					t.Errorf("request = %+v", req)
+				}
+				if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+					t.Errorf("Authorization = %q", got)
+				}
+				w.Write([]byte(body))
+			}))
+			defer srv.Close()
+
+			reranker, err := NewCrossEncoderReranker(CrossEncoderConfig{Endpoint: srv.URL, Model: "bge", APIKey: "secret"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := reranker.Rerank(context.Background(), "rate limits", candidates)
+			if err != nil {
+				t.Fatalf("Rerank() error = %v", err)
+			}
+			got := result.Results
+			if len(got) != 3 || got[0].ID != "b" || got[1].ID != "a" || got[2].ID != "c" {
+				t.Fatalf("order = %+v", got)
+			}
+			if got[0].Score != 0.95 {
+				t.Errorf("score = %v, want 0.95", got[0].Score)
+			}
+		})
+	}
+}
+
+func TestCrossEncoderRerankerKeepsOrderOnFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "model loading", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	reranker, err := NewCrossEncoderReranker(CrossEncoderConfig{Endpoint: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	candidates := []SearchResult{{ID: "a"}, {ID: "b"}}
+	result, err := reranker.Rerank(context.Background(), "q", candidates)
+	if err != nil || len(result.Results) != 2 || result.Results[0].ID != "a" {
+		t.Errorf("Rerank() = %+v, %v; want original order", result, err)
+	}
+
+	if _, err := NewCrossEncoderReranker(CrossEncoderConfig{}); err == nil {
+		t.Error("expected error for missing endpoint")
+	}
+}
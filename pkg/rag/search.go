@@ -41,7 +41,7 @@ const (
 // It combines:
 //   - Document ingestion with chunking
 //   - Vector similarity search
-//   - Optional hybrid search (vector + keyword)
+//   - Optional keyword and hybrid search (vector + BM25 keyword)
 //   - Optional query enhancement (HyDE, multi-query)
 //   - Optional reranking
 //
@@ -55,9 +55,13 @@ type SearchEngine struct {
 
 	// Optional enhancement components
 	hyde       *HyDE
-	reranker   *Reranker
+	reranker   Reranker
 	multiQuery *MultiQueryExpander
 
+	// keywords indexes chunk text for keyword and hybrid search when the
+	// provider has no native keyword search. Nil in vector mode.
+	keywords *KeywordIndex
+
 	mu sync.RWMutex
 }
 
@@ -78,17 +82,30 @@ type SearchEngineConfig struct {
 	// DefaultTopK is the default number of results (default: 10).
 	DefaultTopK int
 
-	// DefaultThreshold filters results below this score (default: 0.0).
+	// DefaultThreshold filters vector matches below this similarity
+	// (default: 0.0). Keyword and fused scores are not filtered.
 	DefaultThreshold float32
 
 	// HyDE for hypothetical document embedding (optional).
 	HyDE *HyDE
 
-	// Reranker for LLM-based result reranking (optional).
-	Reranker *Reranker
+	// Reranker reorders candidates before the top-k cut (optional).
+	Reranker Reranker
+
+	// RerankTopN is the number of candidates fetched and passed to the
+	// reranker (default: 3x top-k, at most 100).
+	RerankTopN int
 
 	// MultiQuery for query expansion (optional).
 	MultiQuery *MultiQueryExpander
+
+	// SearchMode is the default search mode: "vector" (default), "keyword"
+	// or "hybrid".
+	SearchMode string
+
+	// KeywordWeight is the keyword ranking's share (0-1) in hybrid search
+	// (default: 0.5).
+	KeywordWeight float64
 }
 
 // NewSearchEngine creates a new search engine.
@@ -115,13 +132,31 @@ func NewSearchEngine(cfg SearchEngineConfig) (*SearchEngine, error) {
 		cfg.DefaultTopK = 10
 	}
 
+	switch cfg.SearchMode {
+	case "":
+		cfg.SearchMode = SearchModeVector
+	case SearchModeVector, SearchModeKeyword, SearchModeHybrid:
+	default:
+		return nil, fmt.Errorf("invalid search mode %q (valid: vector, keyword, hybrid)", cfg.SearchMode)
+	}
+	if cfg.KeywordWeight <= 0 || cfg.KeywordWeight > 1 {
+		cfg.KeywordWeight = DefaultKeywordWeight
+	}
+
+	// Keep a local keyword index unless the provider searches keywords natively
+	var keywords *KeywordIndex
+	if _, native := cfg.Provider.(vector.KeywordSearcher); !native && cfg.SearchMode != SearchModeVector {
+		keywords = NewKeywordIndex()
+	}
+
 	slog.Info("Created RAG search engine",
 		"provider", cfg.Provider.Name(),
 		"collection", collection,
 		"chunker", chunker.Strategy(),
 		"hyde_enabled", cfg.HyDE != nil,
 		"reranker_enabled", cfg.Reranker != nil,
-		"multiquery_enabled", cfg.MultiQuery != nil)
+		"multiquery_enabled", cfg.MultiQuery != nil,
+		"search_mode", cfg.SearchMode)
 
 	return &SearchEngine{
 		provider:   cfg.Provider,
@@ -132,6 +167,7 @@ func NewSearchEngine(cfg SearchEngineConfig) (*SearchEngine, error) {
 		hyde:       cfg.HyDE,
 		reranker:   cfg.Reranker,
 		multiQuery: cfg.MultiQuery,
+		keywords:   keywords,
 	}, nil
 }
 
@@ -144,16 +180,19 @@ func NewSearchEngine(cfg SearchEngineConfig) (*SearchEngine, error) {
 //
 // Document ID should be stable across re-indexing to enable updates.
 func (e *SearchEngine) IngestDocument(ctx context.Context, doc Document) error {
+	_, err := e.ingestDocument(ctx, doc)
+	return err
+}
+
+// ingestDocument indexes a document and returns the number of chunks stored.
+func (e *SearchEngine) ingestDocument(ctx context.Context, doc Document) (int, error) {
 	if doc.ID == "" {
-		return fmt.Errorf("document ID is required")
+		return 0, fmt.Errorf("document ID is required")
 	}
 	if doc.Content == "" {
-		return nil // Skip empty documents
+		return 0, nil // Skip empty documents
 	}
 
-	e.mu.Lock()
-	defer e.mu.Unlock()
-
 	// Create chunk context from document metadata
 	chunkCtx := &ChunkContext{
 		FilePath: doc.SourcePath,
@@ -165,63 +204,51 @@ func (e *SearchEngine) IngestDocument(ctx context.Context, doc Document) error {
 	// Split document into chunks
 	chunks, err := e.chunker.Chunk(doc.Content, chunkCtx)
 	if err != nil {
-		return fmt.Errorf("failed to chunk document: %w", err)
+		return 0, fmt.Errorf("failed to chunk document: %w", err)
 	}
 
 	if len(chunks) == 0 {
-		return nil
+		return 0, nil
+	}
+
+	// Embed all chunks in batched requests, outside the lock so documents
+	// indexed concurrently overlap their embedding calls. A failure is
+	// returned rather than skipped so the caller can retry rate limits.
+	texts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		texts[i] = chunk.Content
 	}
+	embeddings, err := e.embedder.EmbedBatch(ctx, texts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to embed chunks: %w", err)
+	}
+	if len(embeddings) != len(chunks) {
+		return 0, fmt.Errorf("embedder returned %d embeddings for %d chunks", len(embeddings), len(chunks))
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
 
 	// Index each chunk
 	indexed := 0
-	for _, chunk := range chunks {
+	for i, chunk := range chunks {
 		// Generate chunk ID
 		chunkID := fmt.Sprintf("%s:chunk:%d", doc.ID, chunk.Index)
 
-		// Generate embedding
-		embedding, err := e.embedder.Embed(ctx, chunk.Content)
-		if err != nil {
-			slog.Warn("Failed to embed chunk",
-				"document_id", doc.ID,
-				"chunk_index", chunk.Index,
-				"error", err)
-			continue
-		}
-
 		// Prepare metadata
-		metadata := make(map[string]any)
-		for k, v := range doc.Metadata {
-			metadata[k] = v
-		}
-		metadata["document_id"] = doc.ID
-		metadata["chunk_index"] = chunk.Index
-		metadata["chunk_total"] = chunk.Total
-		metadata["start_line"] = chunk.StartLine
-		metadata["end_line"] = chunk.EndLine
-		metadata["content"] = chunk.Content
-		if doc.Title != "" {
-			metadata["title"] = doc.Title
-		}
-		if doc.SourcePath != "" {
-			metadata["source_path"] = doc.SourcePath
-		}
-		if chunk.Context != nil {
-			if chunk.Context.FunctionName != "" {
-				metadata["function_name"] = chunk.Context.FunctionName
-			}
-			if chunk.Context.TypeName != "" {
-				metadata["type_name"] = chunk.Context.TypeName
-			}
-		}
+		metadata := chunkMetadata(doc, chunk)
 
 		// Upsert to vector store
-		if err := e.provider.Upsert(ctx, e.collection, chunkID, embedding, metadata); err != nil {
+		if err := e.provider.Upsert(ctx, e.collection, chunkID, embeddings[i], metadata); err != nil {
 			slog.Warn("Failed to upsert chunk",
 				"document_id", doc.ID,
 				"chunk_index", chunk.Index,
 				"error", err)
 			continue
 		}
+		if e.keywords != nil {
+			e.keywords.Add(chunkID, chunk.Content, metadata)
+		}
 
 		indexed++
 	}
@@ -231,9 +258,84 @@ func (e *SearchEngine) IngestDocument(ctx context.Context, doc Document) error {
 		"chunks_total", len(chunks),
 		"chunks_indexed", indexed)
 
+	return indexed, nil
+}
+
+// storedDocument returns the content hash and chunk count stored with a
+// document's chunks, and whether the vector store has the document.
+func (e *SearchEngine) storedDocument(ctx context.Context, documentID string) (string, int, bool) {
+	dim := e.embedder.Dimension()
+	if dim <= 0 {
+		return "", 0, false
+	}
+
+	// Any non-zero vector works; the filter selects the document
+	probe := make([]float32, dim)
+	probe[0] = 1
+	results, err := e.provider.SearchWithFilter(ctx, e.collection, probe, 1, map[string]any{"document_id": documentID})
+	if err != nil || len(results) == 0 {
+		return "", 0, false
+	}
+
+	metadata := results[0].Metadata
+	hash, _ := metadata[contentHashKey].(string)
+	return hash, metadataInt(metadata["chunk_total"]), true
+}
+
+// indexKeywords adds a document's chunks to the local keyword index without
+// embedding them. It restores keyword search for documents that are already
+// in the vector store, e.g. when indexing resumes from a checkpoint.
+func (e *SearchEngine) indexKeywords(doc Document) error {
+	if e.keywords == nil || doc.ID == "" || doc.Content == "" {
+		return nil
+	}
+
+	chunkCtx := &ChunkContext{
+		FilePath: doc.SourcePath,
+	}
+	if lang, ok := doc.Metadata["language"].(string); ok {
+		chunkCtx.Language = lang
+	}
+	chunks, err := e.chunker.Chunk(doc.Content, chunkCtx)
+	if err != nil {
+		return fmt.Errorf("failed to chunk document: %w", err)
+	}
+
+	for _, chunk := range chunks {
+		e.keywords.Add(fmt.Sprintf("%s:chunk:%d", doc.ID, chunk.Index), chunk.Content, chunkMetadata(doc, chunk))
+	}
 	return nil
 }
 
+// chunkMetadata builds the metadata stored with a chunk.
+func chunkMetadata(doc Document, chunk Chunk) map[string]any {
+	metadata := make(map[string]any)
+	for k, v := range doc.Metadata {
+		metadata[k] = v
+	}
+	metadata["document_id"] = doc.ID
+	metadata["chunk_index"] = chunk.Index
+	metadata["chunk_total"] = chunk.Total
+	metadata["start_line"] = chunk.StartLine
+	metadata["end_line"] = chunk.EndLine
+	metadata["content"] = chunk.Content
+	if doc.Title != "" {
+		metadata["title"] = doc.Title
+	}
+	if doc.SourcePath != "" {
+		metadata["source_path"] = doc.SourcePath
+	}
+	if chunk.Context != nil {
+		if chunk.Context.FunctionName != "" {
+			metadata["function_name"] = chunk.Context.FunctionName
+		}
+		if chunk.Context.TypeName != "" {
+			metadata["type_name"] = chunk.Context.TypeName
+		}
+	}
+	return metadata
+}
+
 // IngestDocuments indexes multiple documents concurrently.
 func (e *SearchEngine) IngestDocuments(ctx context.Context, docs []Document) error {
 	if len(docs) == 0 {
@@ -339,7 +441,10 @@ func (e *SearchEngine) Search(ctx context.Context, req SearchRequest) (*SearchRe
 	searchResults := CombineResults(allResultSets)
 
 	// Apply reranking if enabled
-	if e.reranker != nil && req.Options != nil && req.Options.EnableRerank && len(searchResults) > 0 {
+	if e.rerankEnabled(req) && len(searchResults) > 0 {
+		if n := e.config.RerankTopN; n > 0 && len(searchResults) > n {
+			searchResults = searchResults[:n]
+		}
 		reranked, err := e.reranker.Rerank(ctx, req.Query, searchResults)
 		if err != nil {
 			slog.Warn("Reranking failed", "error", err)
@@ -363,36 +468,101 @@ func (e *SearchEngine) Search(ctx context.Context, req SearchRequest) (*SearchRe
 	}, nil
 }
 
+// rerankEnabled reports whether req's results go through the reranker.
+func (e *SearchEngine) rerankEnabled(req SearchRequest) bool {
+	return e.reranker != nil && req.Options != nil && req.Options.EnableRerank
+}
+
 // searchSingle performs a single search query.
 func (e *SearchEngine) searchSingle(ctx context.Context, query, collection string, req SearchRequest) ([]SearchResult, error) {
+	// Search stores (get more than topK for reranking)
+	fetchK := req.TopK
+	if e.rerankEnabled(req) {
+		if e.config.RerankTopN > 0 {
+			fetchK = max(req.TopK, e.config.RerankTopN)
+		} else {
+			fetchK = min(req.TopK*3, 100) // Fetch more for reranking
+		}
+	}
+
+	switch e.searchMode(req) {
+	case SearchModeKeyword:
+		// The threshold is a similarity; keyword relevance has no such scale
+		return e.keywordSearch(ctx, query, collection, req, fetchK, 0)
+
+	case SearchModeHybrid:
+		// Fuse deeper rankings so results found by only one search still
+		// compete. The threshold filters vector matches before fusion;
+		// fused ranks are not similarities.
+		fetchK = min(fetchK*2, 100)
+		vectorResults, err := e.vectorSearch(ctx, query, collection, req, fetchK, req.Threshold)
+		if err != nil {
+			return nil, err
+		}
+		keywordResults, err := e.keywordSearch(ctx, query, collection, req, fetchK, 0)
+		if err != nil {
+			slog.Warn("Keyword search failed, using vector results only", "error", err)
+			keywordResults = nil
+		}
+
+		fused := FuseResults(vectorResults, keywordResults, e.config.KeywordWeight)
+		slog.Debug("Hybrid search results",
+			"query", req.Query,
+			"vector", len(vectorResults),
+			"keyword", len(keywordResults),
+			"fused", len(fused))
+		return fused, nil
+
+	default:
+		return e.vectorSearch(ctx, query, collection, req, fetchK, req.Threshold)
+	}
+}
+
+// searchMode resolves the search mode for a request. Modes that need
+// keyword search fall back to vector search when no keyword index exists.
+func (e *SearchEngine) searchMode(req SearchRequest) string {
+	mode := e.config.SearchMode
+	if req.Options != nil && req.Options.Mode != "" {
+		mode = req.Options.Mode
+	}
+	if mode != SearchModeKeyword && mode != SearchModeHybrid {
+		return SearchModeVector
+	}
+	if _, native := e.provider.(vector.KeywordSearcher); !native && e.keywords == nil {
+		slog.Debug("Keyword index not available, using vector search", "mode", mode)
+		return SearchModeVector
+	}
+	return mode
+}
+
+// vectorSearch ranks chunks by embedding similarity.
+func (e *SearchEngine) vectorSearch(ctx context.Context, query, collection string, req SearchRequest, fetchK int, threshold float32) ([]SearchResult, error) {
 	// Determine what to embed (query or hypothetical doc)
 	textToEmbed := query
+	embed := func(ctx context.Context, text string) ([]float32, error) {
+		return embedder.EmbedQuery(ctx, e.embedder, text)
+	}
 	if e.hyde != nil && req.Options != nil && req.Options.EnableHyDE {
 		hypothetical, err := e.hyde.GenerateHypotheticalDocument(ctx, query)
 		if err != nil {
 			slog.Warn("HyDE generation failed, using original query", "error", err)
 		} else {
+			// A hypothetical document is embedded as a document
 			textToEmbed = hypothetical
+			embed = e.embedder.Embed
 		}
 	}
 
 	// Generate embedding
-	queryEmbedding, err := e.embedder.Embed(ctx, textToEmbed)
+	queryEmbedding, err := embed(ctx, textToEmbed)
 	if err != nil {
 		return nil, fmt.Errorf("failed to embed query: %w", err)
 	}
 
-	// Search vector store (get more than topK for reranking)
-	fetchK := req.TopK
-	if e.reranker != nil && req.Options != nil && req.Options.EnableRerank {
-		fetchK = req.TopK * 3 // Fetch more for reranking
-		if fetchK > 100 {
-			fetchK = 100
-		}
-	}
-
 	var results []vector.Result
-	if len(req.Filter) > 0 {
+	if ts, ok := e.provider.(vector.ThresholdSearcher); ok && threshold > 0 {
+		results, err = ts.SearchWithThreshold(ctx, collection, queryEmbedding, fetchK, req.Filter, threshold)
+	} else if len(req.Filter) > 0 {
 		results, err = e.provider.SearchWithFilter(ctx, collection, queryEmbedding, fetchK, req.Filter)
 	} else {
 		results, err = e.provider.Search(ctx, collection, queryEmbedding, fetchK)
@@ -401,12 +571,34 @@ func (e *SearchEngine) searchSingle(ctx context.Context, query, collection strin
 		return nil, fmt.Errorf("search failed: %w", err)
 	}
 
-	// Convert results
+	return e.toSearchResults(req.Query, results, threshold), nil
+}
+
+// keywordSearch ranks chunks by keyword relevance, using the provider's
+// native keyword search if it has one.
+func (e *SearchEngine) keywordSearch(ctx context.Context, query, collection string, req SearchRequest, fetchK int, threshold float32) ([]SearchResult, error) {
+	var results []vector.Result
+	if ks, ok := e.provider.(vector.KeywordSearcher); ok {
+		var err error
+		results, err = ks.KeywordSearch(ctx, collection, query, fetchK, req.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("keyword search failed: %w", err)
+		}
+	} else {
+		results = e.keywords.Search(query, fetchK, req.Filter)
+	}
+
+	return e.toSearchResults(req.Query, results, threshold), nil
+}
+
+// toSearchResults converts provider results, dropping those scoring below
+// the threshold.
+func (e *SearchEngine) toSearchResults(query string, results []vector.Result, threshold float32) []SearchResult {
 	searchResults := make([]SearchResult, 0, len(results))
 	filteredCount := 0
 	for _, r := range results {
 		// Apply threshold filter
-		if req.Threshold > 0 && r.Score < req.Threshold {
+		if threshold > 0 && r.Score < threshold {
 			filteredCount++
 			continue
 		}
@@ -447,19 +639,19 @@ func (e *SearchEngine) searchSingle(ctx context.Context, query, collection strin
 		minScore := searchResults[len(searchResults)-1].Score
 		maxScore := searchResults[0].Score
 		slog.Debug("Search results",
-			"query", req.Query,
+			"query", query,
 			"returned", len(searchResults),
 			"filtered_by_threshold", filteredCount,
-			"threshold", req.Threshold,
+			"threshold", threshold,
 			"score_range", fmt.Sprintf("%.3f-%.3f", minScore, maxScore))
 	} else if filteredCount > 0 {
 		slog.Debug("All results filtered by threshold",
-			"query", req.Query,
+			"query", query,
 			"filtered", filteredCount,
-			"threshold", req.Threshold)
+			"threshold", threshold)
 	}
 
-	return searchResults, nil
+	return searchResults
 }
 
 // DeleteDocument removes a document and all its chunks from the index.
@@ -475,6 +667,9 @@ func (e *SearchEngine) DeleteDocument(ctx context.Context, documentID string) er
 	if err := e.provider.DeleteByFilter(ctx, e.collection, filter); err != nil {
 		return fmt.Errorf("failed to delete document: %w", err)
 	}
+	if e.keywords != nil {
+		e.keywords.DeleteByFilter(filter)
+	}
 
 	slog.Debug("Deleted document from index", "document_id", documentID)
 	return nil
@@ -488,6 +683,9 @@ func (e *SearchEngine) Clear(ctx context.Context) error {
 	if err := e.provider.DeleteCollection(ctx, e.collection); err != nil {
 		return fmt.Errorf("failed to clear collection: %w", err)
 	}
+	if e.keywords != nil {
+		e.keywords.Clear()
+	}
 
 	slog.Info("Cleared RAG index", "collection", e.collection)
 	return nil
@@ -543,6 +741,9 @@ func (e *SearchEngine) DeleteByFilter(ctx context.Context, filter map[string]any
 	if err := e.provider.DeleteByFilter(ctx, e.collection, filter); err != nil {
 		return fmt.Errorf("failed to delete by filter: %w", err)
 	}
+	if e.keywords != nil {
+		e.keywords.DeleteByFilter(filter)
+	}
 	return nil
 }
 
@@ -566,6 +767,10 @@ func (e *SearchEngine) Status() map[string]any {
 	status["config"] = map[string]any{
 		"default_top_k":     e.config.DefaultTopK,
 		"default_threshold": e.config.DefaultThreshold,
+		"search_mode":       e.config.SearchMode,
+	}
+	if e.keywords != nil {
+		status["keyword_index_chunks"] = e.keywords.Len()
 	}
 
 	return status
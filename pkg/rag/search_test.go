@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package rag
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kadirpekel/hector/pkg/vector"
+)
+
+func TestIngestDocumentEmbedsChunksInOneBatch(t *testing.T) {
+	ctx := context.Background()
+	provider, err := vector.NewChromemProvider(vector.ChromemConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	emb := &topicEmbedder{topics: [][]string{{"a"}, {"e"}, {"o"}}}
+	engine, err := NewSearchEngine(SearchEngineConfig{
+		Provider:   provider,
+		Embedder:   emb,
+		Chunker:    NewSimpleChunker(ChunkerConfig{Size: 40}),
+		Collection: "docs",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc := Document{ID: "a.md", Content: "Alpha line one\nAlpha line two\nAlpha line three\n"}
+	chunks, err := engine.ingestDocument(ctx, doc)
+	if err != nil {
+		t.Fatalf("ingestDocument() error = %v", err)
+	}
+	if chunks < 2 {
+		t.Fatalf("ingestDocument() = %d chunks, want several", chunks)
+	}
+	if emb.calls != 1 {
+		t.Errorf("embedder requests = %d, want 1 for %d chunks", emb.calls, chunks)
+	}
+
+	emb.fail = true
+	if _, err := engine.ingestDocument(ctx, doc); err == nil {
+		t.Error("ingestDocument() with failing embedder: want error so the document is retried")
+	}
+}
+
+func TestSearchThresholdAppliesToVectorScoresOnly(t *testing.T) {
+	ctx := context.Background()
+	provider, err := vector.NewChromemProvider(vector.ChromemConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine, err := NewSearchEngine(SearchEngineConfig{
+		Provider:   provider,
+		Embedder:   &topicEmbedder{topics: fixtureTopics},
+		Chunker:    NewSimpleChunker(ChunkerConfig{Size: 200}),
+		Collection: "docs",
+		SearchMode: SearchModeHybrid,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, doc := range []Document{
+		{ID: "saturn.md", Content: "Saturn is a planet with rings"},
+		{ID: "router.md", Content: "The router logs ERR_TIMEOUT"},
+	} {
+		if _, err := engine.ingestDocument(ctx, doc); err != nil {
+			t.Fatalf("ingestDocument(%s) error = %v", doc.ID, err)
+		}
+	}
+
+	search := func(mode, query string) map[string]bool {
+		t.Helper()
+		resp, err := engine.Search(ctx, SearchRequest{
+			Query:     query,
+			Threshold: 0.9,
+			Options:   &SearchOptions{Mode: mode},
+		})
+		if err != nil {
+			t.Fatalf("Search(%s) error = %v", mode, err)
+		}
+		found := make(map[string]bool)
+		for _, r := range resp.Results {
+			found[r.DocumentID] = true
+		}
+		return found
+	}
+
+	// The router chunk has no vector similarity to the query but matches a
+	// keyword, so neither its fused nor its BM25 score is held to the threshold
+	if found := search(SearchModeHybrid, "saturn ERR_TIMEOUT"); !found["saturn.md"] || !found["router.md"] {
+		t.Errorf("hybrid results = %v, want both documents", found)
+	}
+	if found := search(SearchModeKeyword, "saturn planet rings router"); !found["router.md"] {
+		t.Errorf("keyword results = %v, want router.md", found)
+	}
+	if found := search(SearchModeVector, "saturn ERR_TIMEOUT"); found["router.md"] {
+		t.Errorf("vector results = %v, want dissimilar router.md filtered", found)
+	}
+}
@@ -51,8 +51,9 @@ type DocumentStore struct {
 	// Options
 	watchEnabled        bool
 	incrementalIndexing bool
-	indexedDocs         map[string]time.Time
+	indexedDocs         map[string]indexedFile
 	watchCancel         context.CancelFunc
+	onReindex           func(IndexDiff)
 
 	// File watching (for directory sources)
 	watcher *FileWatcher
@@ -118,6 +119,10 @@ type DocumentStoreConfig struct {
 
 	// RetryConfig for transient failure handling (optional).
 	RetryConfig *RetryConfig
+
+	// NativeExtractors limits which formats are extracted natively
+	// ("pdf", "docx", "xlsx", "html"). Empty means all.
+	NativeExtractors []string
 }
 
 // NewDocumentStore creates a new document store.
@@ -182,7 +187,7 @@ func NewDocumentStore(cfg DocumentStoreConfig) (*DocumentStore, error) {
 	return &DocumentStore{
 		name:                  cfg.Name,
 		source:                cfg.Source,
-		extractor:             NewExtractorRegistry(),
+		extractor:             NewExtractorRegistry(cfg.NativeExtractors...),
 		engine:                cfg.SearchEngine,
 		chunker:               chunker,
 		collection:            collection,
@@ -190,7 +195,7 @@ func NewDocumentStore(cfg DocumentStoreConfig) (*DocumentStore, error) {
 		sourcePath:            sourcePath,
 		watchEnabled:          cfg.Watch,
 		incrementalIndexing:   cfg.IncrementalIndexing,
-		indexedDocs:           make(map[string]time.Time),
+		indexedDocs:           make(map[string]indexedFile),
 		maxConcurrentIndexing: maxConcurrent,
 		retryer:               retryer,
 		progressTracker:       progressTracker,
@@ -220,8 +225,18 @@ func (s *DocumentStore) Collection() string {
 // worker pool for concurrent indexing (like legacy indexingSemaphore).
 // Supports checkpoint/resume for interrupted indexing.
 //
+// With incremental indexing, documents whose content is unchanged are
+// skipped; see Reindex.
+//
 // Direct port from legacy pkg/context/document_store_indexing.go
 func (s *DocumentStore) Index(ctx context.Context) error {
+	_, err := s.index(ctx, s.incrementalIndexing)
+	return err
+}
+
+// index indexes all documents from the source. When incremental, it skips
+// unchanged documents and deletes those no longer in the source.
+func (s *DocumentStore) index(ctx context.Context, incremental bool) (IndexDiff, error) {
 	slog.Info("Starting document indexing",
 		"store", s.name,
 		"workers", s.maxConcurrentIndexing)
@@ -261,6 +276,8 @@ func (s *DocumentStore) Index(ctx context.Context) error {
 
 	// Track counts atomically for concurrent updates
 	var indexed, skipped, total, errors int64
+	var diff IndexDiff
+	var diffMu sync.Mutex
 
 	// Track found documents for cleanup (like legacy foundDocs)
 	foundDocs := make(map[string]bool)
@@ -294,7 +311,7 @@ func (s *DocumentStore) Index(ctx context.Context) error {
 		select {
 		case <-ctx.Done():
 			wg.Wait()
-			return ctx.Err()
+			return diff, ctx.Err()
 
 		case doc, ok := <-docChan:
 			if !ok {
@@ -310,8 +327,8 @@ func (s *DocumentStore) Index(ctx context.Context) error {
 				finalErrors := atomic.LoadInt64(&errors)
 
 				// Clean up deleted files (like legacy cleanupDeletedFiles)
-				if s.source.Type() == "directory" && s.incrementalIndexing {
-					s.cleanupDeletedFiles(ctx, foundDocs)
+				if s.source.Type() == "directory" && incremental {
+					diff.Deleted += s.cleanupDeletedFiles(ctx, foundDocs)
 				}
 
 				// Clear checkpoint on successful completion
@@ -325,10 +342,15 @@ func (s *DocumentStore) Index(ctx context.Context) error {
 					"indexed", finalIndexed,
 					"skipped", finalSkipped,
 					"errors", finalErrors,
+					"chunks_added", diff.Added,
+					"chunks_updated", diff.Updated,
+					"chunks_deleted", diff.Deleted,
+					"embeddings", diff.Added+diff.Updated,
 					"elapsed", elapsed,
-					"docs_per_sec", float64(finalIndexed)/elapsed.Seconds())
+					"docs_per_sec", float64(finalIndexed)/elapsed.Seconds(),
+					"embeddings_per_sec", float64(diff.Added+diff.Updated)/elapsed.Seconds())
 
-				return nil
+				return diff, nil
 			}
 
 			atomic.AddInt64(&total, 1)
@@ -352,12 +374,9 @@ func (s *DocumentStore) Index(ctx context.Context) error {
 				continue
 			}
 
-			// Get modification time for checkpoint/incremental checks
-			modTime := time.Time{}
+			// Get modification time for checkpoint checks
+			modTime := documentModTime(doc)
 			fileSize := int64(0)
-			if mt, ok := doc.Metadata["last_modified"].(int64); ok {
-				modTime = time.Unix(mt, 0)
-			}
 			if sz, ok := doc.Metadata["size"].(int64); ok {
 				fileSize = sz
 			}
@@ -365,21 +384,7 @@ func (s *DocumentStore) Index(ctx context.Context) error {
 			// Check checkpoint for already-processed files (like legacy)
 			if s.source.Type() == "directory" && checkpoint != nil {
 				if !s.checkpointManager.ShouldProcessFile(doc.ID, fileSize, modTime) {
-					atomic.AddInt64(&skipped, 1)
-					s.metrics.IncrementSkipped()
-					s.progressTracker.IncrementSkipped()
-					s.progressTracker.IncrementProcessed()
-					continue
-				}
-			}
-
-			// Check if document needs re-indexing (incremental)
-			if s.incrementalIndexing && checkpoint == nil {
-				s.mu.RLock()
-				lastIndexed, exists := s.indexedDocs[doc.ID]
-				s.mu.RUnlock()
-
-				if exists && !modTime.After(lastIndexed) {
+					s.restoreKeywords(ctx, doc)
 					atomic.AddInt64(&skipped, 1)
 					s.metrics.IncrementSkipped()
 					s.progressTracker.IncrementSkipped()
@@ -402,11 +407,34 @@ func (s *DocumentStore) Index(ctx context.Context) error {
 				// Update progress with current file
 				s.progressTracker.SetCurrentFile(doc.ID)
 
-				// Index with retry
+				// Index with retry, skipping unchanged content when incremental
+				var docDiff IndexDiff
 				err := s.retryer.Do(ctx, "index_document", func() error {
-					return s.indexSingleDocument(ctx, doc)
+					var err error
+					docDiff, err = s.reindexDocument(ctx, doc, !incremental)
+					return err
 				})
 
+				diffMu.Lock()
+				if err != nil {
+					diff.Failed++
+				} else {
+					diff.add(docDiff)
+				}
+				diffMu.Unlock()
+				if err == nil {
+					s.metrics.AddEmbeddings(docDiff.Added + docDiff.Updated)
+				}
+
+				if err == nil && docDiff.Unchanged > 0 {
+					atomic.AddInt64(&skipped, 1)
+					s.metrics.IncrementSkipped()
+					s.progressTracker.IncrementSkipped()
+					s.progressTracker.IncrementProcessed()
+					s.checkpointManager.RecordFile(doc.ID, fileSize, modTime, "indexed")
+					return
+				}
+
 				if err != nil {
 					atomic.AddInt64(&errors, 1)
 					s.metrics.IncrementErrors()
@@ -425,11 +453,6 @@ func (s *DocumentStore) Index(ctx context.Context) error {
 				s.progressTracker.IncrementIndexed()
 				s.progressTracker.IncrementProcessed()
 
-				// Track indexed document
-				s.mu.Lock()
-				s.indexedDocs[doc.ID] = time.Now()
-				s.mu.Unlock()
-
 				// Record in checkpoint (like legacy)
 				s.checkpointManager.RecordFile(doc.ID, fileSize, modTime, "indexed")
 
@@ -440,12 +463,47 @@ func (s *DocumentStore) Index(ctx context.Context) error {
 	}
 }
 
-// indexSingleDocument extracts and indexes a single document.
-func (s *DocumentStore) indexSingleDocument(ctx context.Context, doc Document) error {
+// indexSingleDocument extracts and indexes a single document, returning
+// the number of chunks stored.
+func (s *DocumentStore) indexSingleDocument(ctx context.Context, doc Document) (int, error) {
+	doc, err := s.extractDocument(ctx, doc)
+	if err != nil {
+		return 0, err
+	}
+
+	// Index document
+	chunks, err := s.engine.ingestDocument(ctx, doc)
+	if err != nil {
+		return 0, fmt.Errorf("indexing failed: %w", err)
+	}
+
+	return chunks, nil
+}
+
+// restoreKeywords adds a checkpointed document to the engine's keyword
+// index. Its vectors are already stored, but the keyword index is kept in
+// memory and starts empty on every run.
+func (s *DocumentStore) restoreKeywords(ctx context.Context, doc Document) {
+	if s.engine.keywords == nil {
+		return
+	}
+	doc, err := s.extractDocument(ctx, doc)
+	if err == nil {
+		err = s.engine.indexKeywords(doc)
+	}
+	if err != nil {
+		slog.Warn("Failed to restore keyword index for document",
+			"document", doc.ID,
+			"error", err)
+	}
+}
+
+// extractDocument extracts a document's text content.
+func (s *DocumentStore) extractDocument(ctx context.Context, doc Document) (Document, error) {
 	// Extract content
 	extracted, err := s.extractor.Extract(ctx, doc)
 	if err != nil {
-		return fmt.Errorf("extraction failed: %w", err)
+		return doc, fmt.Errorf("extraction failed: %w", err)
 	}
 
 	// Update document with extracted content
@@ -463,12 +521,7 @@ func (s *DocumentStore) indexSingleDocument(ctx context.Context, doc Document) e
 	}
 	doc.Metadata["collection"] = s.collection
 
-	// Index document
-	if err := s.engine.IngestDocument(ctx, doc); err != nil {
-		return fmt.Errorf("indexing failed: %w", err)
-	}
-
-	return nil
+	return doc, nil
 }
 
 // Search searches for documents.
@@ -583,65 +636,66 @@ func (s *DocumentStore) processEvents(ctx context.Context, events <-chan Documen
 			if !ok {
 				return
 			}
-			s.handleEvent(ctx, event)
+			diff := s.handleEvent(ctx, event)
+
+			// Report events the watcher delivered together as one change
+			open := true
+			for open {
+				select {
+				case event, open = <-events:
+					if open {
+						diff.add(s.handleEvent(ctx, event))
+					}
+				default:
+					open = false
+				}
+			}
+
+			s.mu.RLock()
+			onReindex := s.onReindex
+			s.mu.RUnlock()
+			if onReindex != nil {
+				onReindex(diff)
+			}
 		}
 	}
 }
 
 // handleEvent processes a single document event.
-func (s *DocumentStore) handleEvent(ctx context.Context, event DocumentEvent) {
+func (s *DocumentStore) handleEvent(ctx context.Context, event DocumentEvent) IndexDiff {
 	switch event.Type {
 	case DocumentEventCreate, DocumentEventUpdate:
-		// Extract and index
-		doc := event.Document
-		extracted, err := s.extractor.Extract(ctx, doc)
+		// Saving a file without changes only touches its modification time
+		diff, err := s.reindexDocument(ctx, event.Document, false)
 		if err != nil {
-			slog.Warn("Failed to extract content on change",
-				"document", doc.ID,
-				"error", err)
-			return
-		}
-
-		if extracted.Content != "" {
-			doc.Content = extracted.Content
-		}
-		if doc.Metadata == nil {
-			doc.Metadata = make(map[string]any)
-		}
-		doc.Metadata["collection"] = s.collection
-
-		if err := s.engine.IngestDocument(ctx, doc); err != nil {
 			slog.Warn("Failed to index document on change",
-				"document", doc.ID,
+				"document", event.Document.ID,
 				"error", err)
-			return
+			return IndexDiff{Failed: 1}
 		}
 
-		s.mu.Lock()
-		s.indexedDocs[doc.ID] = time.Now()
-		s.mu.Unlock()
-
 		slog.Debug("Indexed document on change",
-			"document", doc.ID,
-			"event", event.Type)
+			"document", event.Document.ID,
+			"event", event.Type,
+			"unchanged", diff.Unchanged > 0)
+		return diff
 
 	case DocumentEventDelete:
-		if err := s.engine.DeleteDocument(ctx, event.Document.ID); err != nil {
+		diff, err := s.removeDocument(ctx, event.Document.ID)
+		if err != nil {
 			slog.Warn("Failed to delete document on change",
 				"document", event.Document.ID,
 				"error", err)
-			return
+			return IndexDiff{Failed: 1}
 		}
 
-		s.mu.Lock()
-		delete(s.indexedDocs, event.Document.ID)
-		s.mu.Unlock()
-
 		slog.Debug("Deleted document on change", "document", event.Document.ID)
+		return diff
 
 	case DocumentEventError:
 		slog.Warn("Document source error", "error", event.Error)
 	}
+	return IndexDiff{}
 }
 
 // StopWatching stops watching for changes.
@@ -667,7 +721,7 @@ func (s *DocumentStore) StopWatching() {
 // Clear removes all indexed documents.
 func (s *DocumentStore) Clear(ctx context.Context) error {
 	s.mu.Lock()
-	s.indexedDocs = make(map[string]time.Time)
+	s.indexedDocs = make(map[string]indexedFile)
 	s.mu.Unlock()
 
 	return s.engine.Clear(ctx)
@@ -781,10 +835,8 @@ func (s *DocumentStore) RefreshDocument(ctx context.Context, docID string) error
 			return fmt.Errorf("failed to re-index document: %w", err)
 		}
 
-		// Update indexed timestamp
-		s.mu.Lock()
-		s.indexedDocs[docID] = time.Now()
-		s.mu.Unlock()
+		// Track the document; its hash is recorded on the next re-index
+		s.recordIndexed(docID, indexedFile{modTime: time.Now()})
 
 		slog.Info("Refreshed document", "store", s.name, "document", docID)
 		return nil
@@ -800,15 +852,16 @@ func (s *DocumentStore) GetSearchEngine() *SearchEngine {
 	return s.engine
 }
 
-// cleanupDeletedFiles removes indexed documents that no longer exist in the source.
+// cleanupDeletedFiles removes indexed documents that no longer exist in
+// the source and returns the number of chunks deleted.
 //
 // Direct port from legacy pkg/context/document_store.go (cleanupDeletedFiles)
-func (s *DocumentStore) cleanupDeletedFiles(ctx context.Context, foundDocs map[string]bool) {
+func (s *DocumentStore) cleanupDeletedFiles(ctx context.Context, foundDocs map[string]bool) int {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	deletedCount := 0
-	for docID := range s.indexedDocs {
+	deletedCount, deletedChunks := 0, 0
+	for docID, file := range s.indexedDocs {
 		if !foundDocs[docID] {
 			// Document no longer exists in source, delete from index
 			if err := s.engine.DeleteDocument(ctx, docID); err != nil {
@@ -819,6 +872,7 @@ func (s *DocumentStore) cleanupDeletedFiles(ctx context.Context, foundDocs map[s
 			}
 			delete(s.indexedDocs, docID)
 			deletedCount++
+			deletedChunks += file.chunks
 		}
 	}
 
@@ -827,4 +881,5 @@ func (s *DocumentStore) cleanupDeletedFiles(ctx context.Context, foundDocs map[s
 			"store", s.name,
 			"deleted_count", deletedCount)
 	}
+	return deletedChunks
 }
@@ -17,9 +17,9 @@
 //
 // Features:
 //   - Multi-layer time windows (minute, hour, day, week, month)
-//   - Dual tracking (token count AND request count)
+//   - Token, request count and cost tracking
 //   - Flexible scopes (per-session or per-user)
-//   - Multiple storage backends (in-memory and SQL)
+//   - Multiple storage backends (in-memory, SQL and Redis)
 //   - Atomic check-and-record operations
 //   - Detailed usage statistics
 //
@@ -42,7 +42,7 @@
 //	rate_limiting:
 //	  enabled: true
 //	  scope: "session"  # or "user"
-//	  backend: "memory"  # or "sql"
+//	  backend: "memory"  # or "sql", "redis"
 //	  limits:
 //	    - type: token
 //	      window: day
@@ -50,6 +50,13 @@
 //	    - type: count
 //	      window: minute
 //	      limit: 60
+//	    - type: cost
+//	      window: month
+//	      limit: 100  # US dollars
+//	  pricing:
+//	    gpt-4o:
+//	      input_per_million: 2.50
+//	      output_per_million: 10.00
 //
 // # Time Windows
 //
@@ -63,9 +70,27 @@
 //
 //   - token: Track token usage (LLM API tokens, cost control)
 //   - count: Track request count (rate throttling, DDoS protection)
+//   - cost: Track spend in US dollars, priced per model from input and
+//     output tokens (budgets). Record with CheckAndRecordUsage to pass
+//     the model and token split.
 //
 // # Scopes
 //
 //   - session: Each session has independent quotas
 //   - user: All sessions for a user share quotas
+//
+// # Tiers
+//
+// NewTieredRateLimiter applies a different Config per tier, such as free
+// and pro users. A TierResolver picks the tier of each identifier, e.g.
+// StateTierResolver("user:tier") reads it from session state; identifiers
+// without a known tier get the DefaultTier configuration:
+//
+//	limiter, err := ratelimit.NewTieredRateLimiter(map[string]ratelimit.Config{
+//	    ratelimit.DefaultTier: freeConfig,
+//	    "pro":                 proConfig,
+//	}, ratelimit.StateTierResolver("user:tier"), store)
+//
+// Tiers share usage, so a user changing tier keeps the usage recorded so
+// far, measured against the new tier's limits.
 package ratelimit
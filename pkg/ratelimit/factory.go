@@ -16,6 +16,7 @@
 package ratelimit
 
 import (
+	"crypto/tls"
 	"fmt"
 
 	"github.com/kadirpekel/hector/pkg/config"
@@ -40,6 +41,9 @@ import (
 //	    - type: token
 //	      window: day
 //	      limit: 100000
+//
+// With backend "redis", limits are shared by every replica using the
+// server given in the redis block.
 func NewRateLimiterFromConfig(cfg *config.Config, pool *config.DBPool) (RateLimiter, error) {
 	rateLimitCfg := cfg.RateLimiting
 	if rateLimitCfg == nil || !rateLimitCfg.IsEnabled() {
@@ -77,29 +81,35 @@ func NewRateLimiterFromConfig(cfg *config.Config, pool *config.DBPool) (RateLimi
 		if err != nil {
 			return nil, fmt.Errorf("failed to create SQL store: %w", err)
 		}
+	case "redis":
+		redisCfg := rateLimitCfg.Redis
+		if redisCfg == nil {
+			redisCfg = &config.RateLimitRedisConfig{}
+		}
+		opts := RedisConfig{
+			Addr:      redisCfg.Address,
+			Username:  redisCfg.Username,
+			Password:  redisCfg.Password,
+			DB:        redisCfg.DB,
+			KeyPrefix: redisCfg.KeyPrefix,
+			Timeout:   redisCfg.Timeout.Duration(),
+		}
+		if redisCfg.TLS {
+			opts.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+
+		var err error
+		store, err = NewRedisStore(opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Redis store: %w", err)
+		}
 	case "memory", "":
 		store = NewMemoryStore()
 	default:
 		return nil, fmt.Errorf("unsupported rate limit backend: %s", rateLimitCfg.Backend)
 	}
 
-	// Convert config limits to LimitRules
-	limits := make([]LimitRule, len(rateLimitCfg.Limits))
-	for i, l := range rateLimitCfg.Limits {
-		limits[i] = LimitRule{
-			Type:   ParseLimitType(l.Type),
-			Window: ParseTimeWindow(l.Window),
-			Limit:  l.Limit,
-		}
-	}
-
-	// Create limiter config
-	limiterCfg := &Config{
-		Enabled: rateLimitCfg.IsEnabled(),
-		Limits:  limits,
-	}
-
-	return NewRateLimiter(limiterCfg, store)
+	return NewRateLimiter(limiterConfig(rateLimitCfg), store)
 }
 
 // NewRateLimiterFromConfigWithStore creates a RateLimiter with a custom store.
@@ -113,7 +123,11 @@ func NewRateLimiterFromConfigWithStore(cfg *config.RateLimitConfig, store Store)
 		return nil, fmt.Errorf("store is required")
 	}
 
-	// Convert config limits to LimitRules
+	return NewRateLimiter(limiterConfig(cfg), store)
+}
+
+// limiterConfig converts rate limiting configuration to a limiter Config.
+func limiterConfig(cfg *config.RateLimitConfig) *Config {
 	limits := make([]LimitRule, len(cfg.Limits))
 	for i, l := range cfg.Limits {
 		limits[i] = LimitRule{
@@ -121,15 +135,26 @@ func NewRateLimiterFromConfigWithStore(cfg *config.RateLimitConfig, store Store)
 			Window: ParseTimeWindow(l.Window),
 			Limit:  l.Limit,
 		}
+		// Cost limits are configured in dollars
+		if limits[i].Type == LimitTypeCost {
+			limits[i].Limit = USD(float64(l.Limit))
+		}
 	}
 
-	// Create limiter config
-	limiterCfg := &Config{
-		Enabled: cfg.IsEnabled(),
-		Limits:  limits,
+	var pricing Pricing
+	if len(cfg.Pricing) > 0 {
+		pricing = make(Pricing, len(cfg.Pricing))
+		for model, p := range cfg.Pricing {
+			pricing[model] = ModelPrice{InputPerMillion: p.InputPerMillion, OutputPerMillion: p.OutputPerMillion}
+		}
 	}
 
-	return NewRateLimiter(limiterCfg, store)
+	return &Config{
+		Enabled:      cfg.IsEnabled(),
+		Limits:       limits,
+		OnStoreError: StoreErrorPolicy(cfg.OnStoreError),
+		Pricing:      pricing,
+	}
 }
 
 // ScopeFromConfig returns the rate limiting scope from configuration.
@@ -32,10 +32,18 @@ type RateLimiter interface {
 	// Use this after an operation completes to record the actual usage.
 	Record(ctx context.Context, scope Scope, identifier string, tokenCount int64, requestCount int64) error
 
+	// RecordUsage records the usage of an operation, including the model
+	// and token split used to charge cost limits.
+	RecordUsage(ctx context.Context, scope Scope, identifier string, usage RequestUsage) error
+
 	// CheckAndRecord checks limits and records usage in a single atomic operation.
 	// This is the recommended method for most use cases as it prevents race conditions.
 	CheckAndRecord(ctx context.Context, scope Scope, identifier string, tokenCount int64, requestCount int64) (*CheckResult, error)
 
+	// CheckAndRecordUsage is CheckAndRecord for the usage of an operation,
+	// including the model and token split used to charge cost limits.
+	CheckAndRecordUsage(ctx context.Context, scope Scope, identifier string, usage RequestUsage) (*CheckResult, error)
+
 	// GetUsage returns current usage statistics for an identifier.
 	// Returns usage for all configured limits.
 	GetUsage(ctx context.Context, scope Scope, identifier string) ([]Usage, error)
@@ -78,9 +86,22 @@ type Store interface {
 	Close() error
 }
 
+// AtomicStore is a Store that can check and record usage for all limits
+// in one atomic operation, consistent across every process sharing the
+// store. DefaultRateLimiter uses it for CheckAndRecord when available.
+type AtomicStore interface {
+	Store
+
+	// CheckAndIncrement checks the usage of every limit and, if none is
+	// exceeded, increments each by its amount. It returns whether usage was
+	// recorded and the usage of each limit, in order, after the increment.
+	CheckAndIncrement(ctx context.Context, scope Scope, identifier string, limits []LimitRule, amounts []int64) (bool, []WindowUsage, error)
+}
+
 // Ensure interface compliance at compile time.
 var (
 	_ RateLimiter = (*DefaultRateLimiter)(nil)
 	_ Store       = (*MemoryStore)(nil)
 	_ Store       = (*SQLStore)(nil)
+	_ AtomicStore = (*RedisStore)(nil)
 )
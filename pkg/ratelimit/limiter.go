@@ -18,7 +18,9 @@ package ratelimit
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -29,25 +31,38 @@ type Config struct {
 
 	// Limits defines the rate limit rules.
 	Limits []LimitRule
+
+	// OnStoreError decides requests in CheckAndRecord while the store
+	// fails. Default: StoreErrorAllow.
+	OnStoreError StoreErrorPolicy
+
+	// Pricing prices token usage for cost limits. Required when any limit
+	// has type LimitTypeCost.
+	Pricing Pricing
 }
 
 // LimitRule defines a single rate limit rule.
 type LimitRule struct {
-	// Type is the limit type (token or count).
+	// Type is the limit type (token, count or cost).
 	Type LimitType
 
 	// Window is the time window for this limit.
 	Window TimeWindow
 
-	// Limit is the maximum allowed in the window.
+	// Limit is the maximum allowed in the window. Cost limits are in
+	// micro-dollars; use USD to convert.
 	Limit int64
 }
 
 // DefaultRateLimiter implements the RateLimiter interface.
 type DefaultRateLimiter struct {
-	config *Config
-	store  Store
-	mu     sync.RWMutex
+	tiers    map[string]*Config
+	resolver TierResolver
+	store    Store
+	mu       sync.RWMutex
+
+	// degraded is set while the store fails, to log mode changes once
+	degraded atomic.Bool
 }
 
 // NewRateLimiter creates a new rate limiter with the given configuration and store.
@@ -60,105 +75,120 @@ func NewRateLimiter(cfg *Config, store Store) (*DefaultRateLimiter, error) {
 		return nil, fmt.Errorf("store is required")
 	}
 
-	// Validate config
-	for i, limit := range cfg.Limits {
-		if limit.Type == "" {
-			return nil, fmt.Errorf("limit[%d]: type is required", i)
-		}
-		if limit.Window == "" {
-			return nil, fmt.Errorf("limit[%d]: window is required", i)
-		}
-		if limit.Limit <= 0 {
-			return nil, fmt.Errorf("limit[%d]: limit must be positive", i)
-		}
+	if err := validateConfig(cfg); err != nil {
+		return nil, err
 	}
 
 	return &DefaultRateLimiter{
-		config: cfg,
-		store:  store,
+		tiers: map[string]*Config{DefaultTier: cfg},
+		store: store,
 	}, nil
 }
 
-// Check verifies if the operation is allowed without recording usage.
-func (rl *DefaultRateLimiter) Check(ctx context.Context, scope Scope, identifier string) (*CheckResult, error) {
-	if !rl.config.Enabled {
-		return &CheckResult{Allowed: true}, nil
+// NewTieredRateLimiter creates a rate limiter applying a different
+// configuration per tier. The resolver picks the tier of each identifier;
+// identifiers it resolves to an empty or unknown tier get the DefaultTier
+// configuration, which is required.
+func NewTieredRateLimiter(tiers map[string]Config, resolver TierResolver, store Store) (*DefaultRateLimiter, error) {
+	if _, ok := tiers[DefaultTier]; !ok {
+		return nil, fmt.Errorf("tier %q is required", DefaultTier)
 	}
 
-	if identifier == "" {
-		return nil, fmt.Errorf("identifier cannot be empty")
+	if resolver == nil {
+		return nil, fmt.Errorf("tier resolver is required")
 	}
 
-	rl.mu.RLock()
-	defer rl.mu.RUnlock()
+	if store == nil {
+		return nil, fmt.Errorf("store is required")
+	}
 
-	result := &CheckResult{
-		Allowed: true,
-		Usages:  make([]Usage, 0, len(rl.config.Limits)),
+	configs := make(map[string]*Config, len(tiers))
+	for tier, cfg := range tiers {
+		if err := validateConfig(&cfg); err != nil {
+			return nil, fmt.Errorf("tier %q: %w", tier, err)
+		}
+		configs[tier] = &cfg
 	}
 
-	now := time.Now()
-	var earliestRetry *time.Time
+	return &DefaultRateLimiter{
+		tiers:    configs,
+		resolver: resolver,
+		store:    store,
+	}, nil
+}
 
-	for _, limit := range rl.config.Limits {
-		current, windowEnd, err := rl.store.GetUsage(ctx, scope, identifier, limit.Type, limit.Window)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get usage for %s/%s: %w", limit.Type, limit.Window, err)
+// validateConfig validates the limits, pricing and store error policy.
+func validateConfig(cfg *Config) error {
+	for i, limit := range cfg.Limits {
+		if limit.Type == "" {
+			return fmt.Errorf("limit[%d]: type is required", i)
 		}
-
-		// If window has expired, reset to 0
-		if windowEnd.Before(now) {
-			current = 0
-			windowEnd = now.Add(limit.Window.Duration())
+		if limit.Window == "" {
+			return fmt.Errorf("limit[%d]: window is required", i)
 		}
-
-		remaining := limit.Limit - current
-		if remaining < 0 {
-			remaining = 0
+		if limit.Limit <= 0 {
+			return fmt.Errorf("limit[%d]: limit must be positive", i)
 		}
+		if limit.Type == LimitTypeCost && len(cfg.Pricing) == 0 {
+			return fmt.Errorf("limit[%d]: cost limits require pricing", i)
+		}
+	}
 
-		percentage := float64(current) / float64(limit.Limit) * 100
+	if err := cfg.Pricing.validate(); err != nil {
+		return err
+	}
 
-		usage := Usage{
-			LimitType:  limit.Type,
-			Window:     limit.Window,
-			Current:    current,
-			Limit:      limit.Limit,
-			WindowEnd:  windowEnd,
-			Remaining:  remaining,
-			Percentage: percentage,
-		}
+	switch cfg.OnStoreError {
+	case "", StoreErrorAllow, StoreErrorDeny:
+	default:
+		return fmt.Errorf("invalid store error policy %q (valid: allow, deny)", cfg.OnStoreError)
+	}
 
-		result.Usages = append(result.Usages, usage)
+	return nil
+}
 
-		// Check if limit is exceeded (strictly greater than)
-		if current > limit.Limit {
-			result.Allowed = false
-			if result.Reason == "" {
-				result.Reason = fmt.Sprintf("%s limit exceeded for %s window (%d/%d)",
-					limit.Type, limit.Window, current, limit.Limit)
-			}
-			// Track earliest retry time
-			if earliestRetry == nil || windowEnd.Before(*earliestRetry) {
-				earliestRetry = &windowEnd
+// configFor returns the configuration of the identifier's tier.
+func (rl *DefaultRateLimiter) configFor(ctx context.Context, scope Scope, identifier string) *Config {
+	if rl.resolver != nil {
+		if tier := rl.resolver(ctx, scope, identifier); tier != "" {
+			if cfg, ok := rl.tiers[tier]; ok {
+				return cfg
 			}
+			slog.Debug("Unknown rate limit tier, using default", "tier", tier, "identifier", identifier)
 		}
 	}
+	return rl.tiers[DefaultTier]
+}
 
-	// Set retry after if any limit was exceeded
-	if !result.Allowed && earliestRetry != nil {
-		retryDuration := time.Until(*earliestRetry)
-		if retryDuration > 0 {
-			result.RetryAfter = &retryDuration
-		}
+// Check verifies if the operation is allowed without recording usage.
+func (rl *DefaultRateLimiter) Check(ctx context.Context, scope Scope, identifier string) (*CheckResult, error) {
+	cfg := rl.configFor(ctx, scope, identifier)
+	if !cfg.Enabled {
+		return &CheckResult{Allowed: true}, nil
 	}
 
-	return result, nil
+	if identifier == "" {
+		return nil, fmt.Errorf("identifier cannot be empty")
+	}
+
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+
+	return rl.checkUnlocked(ctx, cfg, scope, identifier)
 }
 
 // Record records actual usage (tokens and/or count).
+//
+// Tokens are priced as input tokens of the default pricing entry; use
+// RecordUsage to charge cost limits by model.
 func (rl *DefaultRateLimiter) Record(ctx context.Context, scope Scope, identifier string, tokenCount int64, requestCount int64) error {
-	if !rl.config.Enabled {
+	return rl.RecordUsage(ctx, scope, identifier, RequestUsage{InputTokens: tokenCount, Requests: requestCount})
+}
+
+// RecordUsage records the usage of an operation.
+func (rl *DefaultRateLimiter) RecordUsage(ctx context.Context, scope Scope, identifier string, usage RequestUsage) error {
+	cfg := rl.configFor(ctx, scope, identifier)
+	if !cfg.Enabled {
 		return nil
 	}
 
@@ -169,47 +199,107 @@ func (rl *DefaultRateLimiter) Record(ctx context.Context, scope Scope, identifie
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
-	return rl.recordUnlocked(ctx, scope, identifier, tokenCount, requestCount)
+	return rl.recordUnlocked(ctx, cfg, scope, identifier, rl.amounts(cfg, usage))
 }
 
 // CheckAndRecord checks limits and records usage in a single atomic operation.
+//
+// When the store fails, the request is allowed or denied according to
+// Config.OnStoreError and the result is marked Degraded; no error is returned.
+//
+// Tokens are priced as input tokens of the default pricing entry; use
+// CheckAndRecordUsage to charge cost limits by model.
 func (rl *DefaultRateLimiter) CheckAndRecord(ctx context.Context, scope Scope, identifier string, tokenCount int64, requestCount int64) (*CheckResult, error) {
-	if !rl.config.Enabled {
+	return rl.CheckAndRecordUsage(ctx, scope, identifier, RequestUsage{InputTokens: tokenCount, Requests: requestCount})
+}
+
+// CheckAndRecordUsage checks limits and records the usage of an operation
+// in a single atomic operation, like CheckAndRecord.
+//
+// The tier is resolved once, so the check and the record apply the same
+// limits even if the identifier changes tier meanwhile.
+func (rl *DefaultRateLimiter) CheckAndRecordUsage(ctx context.Context, scope Scope, identifier string, usage RequestUsage) (*CheckResult, error) {
+	cfg := rl.configFor(ctx, scope, identifier)
+	if !cfg.Enabled {
 		return &CheckResult{Allowed: true}, nil
 	}
 
+	amounts := rl.amounts(cfg, usage)
+
+	// Stores that check and record atomically need no process-local lock
+	if store, ok := rl.store.(AtomicStore); ok {
+		// The store denies by the same rule as resultFromWindows
+		_, windows, err := store.CheckAndIncrement(ctx, scope, identifier, cfg.Limits, amounts)
+		if err != nil {
+			return rl.storeErrorResult(cfg, identifier, err), nil
+		}
+		rl.storeRecovered()
+		return rl.resultFromWindows(cfg, windows), nil
+	}
+
 	// Lock for atomic check-and-record
 	rl.mu.Lock()
 	defer rl.mu.Unlock()
 
 	// First check current state
-	result, err := rl.checkUnlocked(ctx, scope, identifier)
+	result, err := rl.checkUnlocked(ctx, cfg, scope, identifier)
 	if err != nil {
-		return nil, err
+		return rl.storeErrorResult(cfg, identifier, err), nil
 	}
 
 	// If not allowed, return without recording
 	if !result.Allowed {
+		rl.storeRecovered()
 		return result, nil
 	}
 
 	// Record usage
-	if err := rl.recordUnlocked(ctx, scope, identifier, tokenCount, requestCount); err != nil {
-		return nil, fmt.Errorf("failed to record usage: %w", err)
+	if err := rl.recordUnlocked(ctx, cfg, scope, identifier, amounts); err != nil {
+		return rl.storeErrorResult(cfg, identifier, fmt.Errorf("failed to record usage: %w", err)), nil
 	}
 
 	// Re-check to update usage stats in result
-	result, err = rl.checkUnlocked(ctx, scope, identifier)
+	result, err = rl.checkUnlocked(ctx, cfg, scope, identifier)
 	if err != nil {
-		return nil, err
+		return rl.storeErrorResult(cfg, identifier, err), nil
 	}
 
+	rl.storeRecovered()
 	return result, nil
 }
 
+// storeErrorResult decides a request the store failed on, by the store
+// error policy. Entering degraded mode is logged once.
+func (rl *DefaultRateLimiter) storeErrorResult(cfg *Config, identifier string, err error) *CheckResult {
+	deny := cfg.OnStoreError == StoreErrorDeny
+
+	if rl.degraded.CompareAndSwap(false, true) {
+		if deny {
+			slog.Error("Rate limit store unavailable, denying requests until it recovers", "error", err)
+		} else {
+			slog.Warn("Rate limit store unavailable, allowing requests without limits until it recovers", "error", err)
+		}
+	} else {
+		slog.Debug("Rate limit store still unavailable", "identifier", identifier, "error", err)
+	}
+
+	if deny {
+		return &CheckResult{Allowed: false, Reason: "rate limit store unavailable", Degraded: true}
+	}
+	return &CheckResult{Allowed: true, Degraded: true}
+}
+
+// storeRecovered leaves degraded mode after the store responds again.
+func (rl *DefaultRateLimiter) storeRecovered() {
+	if rl.degraded.CompareAndSwap(true, false) {
+		slog.Info("Rate limit store recovered, enforcing limits again")
+	}
+}
+
 // GetUsage returns current usage statistics for an identifier.
 func (rl *DefaultRateLimiter) GetUsage(ctx context.Context, scope Scope, identifier string) ([]Usage, error) {
-	if !rl.config.Enabled {
+	cfg := rl.configFor(ctx, scope, identifier)
+	if !cfg.Enabled {
 		return []Usage{}, nil
 	}
 
@@ -220,42 +310,11 @@ func (rl *DefaultRateLimiter) GetUsage(ctx context.Context, scope Scope, identif
 	rl.mu.RLock()
 	defer rl.mu.RUnlock()
 
-	usages := make([]Usage, 0, len(rl.config.Limits))
-	now := time.Now()
-
-	for _, limit := range rl.config.Limits {
-		current, windowEnd, err := rl.store.GetUsage(ctx, scope, identifier, limit.Type, limit.Window)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get usage for %s/%s: %w", limit.Type, limit.Window, err)
-		}
-
-		// If window has expired, reset to 0
-		if windowEnd.Before(now) {
-			current = 0
-			windowEnd = now.Add(limit.Window.Duration())
-		}
-
-		remaining := limit.Limit - current
-		if remaining < 0 {
-			remaining = 0
-		}
-
-		percentage := float64(current) / float64(limit.Limit) * 100
-
-		usage := Usage{
-			LimitType:  limit.Type,
-			Window:     limit.Window,
-			Current:    current,
-			Limit:      limit.Limit,
-			WindowEnd:  windowEnd,
-			Remaining:  remaining,
-			Percentage: percentage,
-		}
-
-		usages = append(usages, usage)
+	result, err := rl.checkUnlocked(ctx, cfg, scope, identifier)
+	if err != nil {
+		return nil, err
 	}
-
-	return usages, nil
+	return result.Usages, nil
 }
 
 // Reset resets usage for an identifier.
@@ -279,20 +338,30 @@ func (rl *DefaultRateLimiter) ResetExpired(ctx context.Context, before time.Time
 }
 
 // checkUnlocked is the unlocked version of Check (for internal use).
-func (rl *DefaultRateLimiter) checkUnlocked(ctx context.Context, scope Scope, identifier string) (*CheckResult, error) {
+func (rl *DefaultRateLimiter) checkUnlocked(ctx context.Context, cfg *Config, scope Scope, identifier string) (*CheckResult, error) {
+	windows := make([]WindowUsage, len(cfg.Limits))
+	for i, limit := range cfg.Limits {
+		current, windowEnd, err := rl.store.GetUsage(ctx, scope, identifier, limit.Type, limit.Window)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get usage for %s/%s: %w", limit.Type, limit.Window, err)
+		}
+		windows[i] = WindowUsage{Current: current, WindowEnd: windowEnd}
+	}
+	return rl.resultFromWindows(cfg, windows), nil
+}
+
+// resultFromWindows builds a check result from the usage of each limit.
+func (rl *DefaultRateLimiter) resultFromWindows(cfg *Config, windows []WindowUsage) *CheckResult {
 	result := &CheckResult{
 		Allowed: true,
-		Usages:  make([]Usage, 0, len(rl.config.Limits)),
+		Usages:  make([]Usage, 0, len(cfg.Limits)),
 	}
 
 	now := time.Now()
 	var earliestRetry *time.Time
 
-	for _, limit := range rl.config.Limits {
-		current, windowEnd, err := rl.store.GetUsage(ctx, scope, identifier, limit.Type, limit.Window)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get usage for %s/%s: %w", limit.Type, limit.Window, err)
-		}
+	for i, limit := range cfg.Limits {
+		current, windowEnd := windows[i].Current, windows[i].WindowEnd
 
 		// If window has expired, reset to 0
 		if windowEnd.Before(now) {
@@ -316,13 +385,20 @@ func (rl *DefaultRateLimiter) checkUnlocked(ctx context.Context, scope Scope, id
 			Remaining:  remaining,
 			Percentage: percentage,
 		}
+		if limit.Type == LimitTypeCost {
+			usage.Cost = toUSD(current)
+			usage.CostLimit = toUSD(limit.Limit)
+		}
 
 		result.Usages = append(result.Usages, usage)
 
 		// Check if limit is exceeded (strictly greater than)
 		if current > limit.Limit {
 			result.Allowed = false
-			if result.Reason == "" {
+			if result.Reason == "" && limit.Type == LimitTypeCost {
+				result.Reason = fmt.Sprintf("cost limit exceeded for %s window ($%.2f/$%.2f)",
+					limit.Window, usage.Cost, usage.CostLimit)
+			} else if result.Reason == "" {
 				result.Reason = fmt.Sprintf("%s limit exceeded for %s window (%d/%d)",
 					limit.Type, limit.Window, current, limit.Limit)
 			}
@@ -341,24 +417,16 @@ func (rl *DefaultRateLimiter) checkUnlocked(ctx context.Context, scope Scope, id
 		}
 	}
 
-	return result, nil
+	return result
 }
 
-// recordUnlocked is the unlocked version of Record (for internal use).
-func (rl *DefaultRateLimiter) recordUnlocked(ctx context.Context, scope Scope, identifier string, tokenCount int64, requestCount int64) error {
+// recordUnlocked is the unlocked version of Record (for internal use). It
+// records the amount for each limit, in order.
+func (rl *DefaultRateLimiter) recordUnlocked(ctx context.Context, cfg *Config, scope Scope, identifier string, amounts []int64) error {
 	now := time.Now()
 
-	for _, limit := range rl.config.Limits {
-		var amount int64
-		switch limit.Type {
-		case LimitTypeToken:
-			amount = tokenCount
-		case LimitTypeCount:
-			amount = requestCount
-		default:
-			continue
-		}
-
+	for i, limit := range cfg.Limits {
+		amount := amounts[i]
 		if amount <= 0 {
 			continue
 		}
@@ -386,9 +454,30 @@ func (rl *DefaultRateLimiter) recordUnlocked(ctx context.Context, scope Scope, i
 	return nil
 }
 
-// IsEnabled returns whether rate limiting is enabled.
+// amounts returns the usage to record against each limit, in order.
+func (rl *DefaultRateLimiter) amounts(cfg *Config, usage RequestUsage) []int64 {
+	amounts := make([]int64, len(cfg.Limits))
+	for i, limit := range cfg.Limits {
+		switch limit.Type {
+		case LimitTypeToken:
+			amounts[i] = usage.Tokens()
+		case LimitTypeCount:
+			amounts[i] = usage.Requests
+		case LimitTypeCost:
+			amounts[i] = cfg.Pricing.Cost(usage.Model, usage.InputTokens, usage.OutputTokens)
+		}
+	}
+	return amounts
+}
+
+// IsEnabled returns whether rate limiting is enabled for any tier.
 func (rl *DefaultRateLimiter) IsEnabled() bool {
-	return rl.config.Enabled
+	for _, cfg := range rl.tiers {
+		if cfg.Enabled {
+			return true
+		}
+	}
+	return false
 }
 
 // Store returns the underlying store (for testing).
@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// failingStore is a store whose backend is unreachable.
+type failingStore struct {
+	*MemoryStore
+	fail bool
+}
+
+var errStoreDown = errors.New("connection refused")
+
+func (s *failingStore) GetUsage(ctx context.Context, scope Scope, identifier string, limitType LimitType, window TimeWindow) (int64, time.Time, error) {
+	if s.fail {
+		return 0, time.Time{}, errStoreDown
+	}
+	return s.MemoryStore.GetUsage(ctx, scope, identifier, limitType, window)
+}
+
+func TestCheckAndRecordStoreErrorPolicy(t *testing.T) {
+	tests := []struct {
+		policy      StoreErrorPolicy
+		wantAllowed bool
+		wantStatus  int
+	}{
+		{"", true, http.StatusOK},
+		{StoreErrorAllow, true, http.StatusOK},
+		{StoreErrorDeny, false, http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			store := &failingStore{MemoryStore: NewMemoryStore(), fail: true}
+			limiter, err := NewRateLimiter(&Config{
+				Enabled:      true,
+				Limits:       []LimitRule{{Type: LimitTypeCount, Window: WindowMinute, Limit: 10}},
+				OnStoreError: tt.policy,
+			}, store)
+			if err != nil {
+				t.Fatalf("NewRateLimiter() error = %v", err)
+			}
+
+			result, err := limiter.CheckAndRecord(context.Background(), ScopeSession, "s1", 0, 1)
+			if err != nil {
+				t.Fatalf("CheckAndRecord() error = %v", err)
+			}
+			if result.Allowed != tt.wantAllowed || !result.Degraded {
+				t.Errorf("result = %+v, want allowed=%v degraded", result, tt.wantAllowed)
+			}
+
+			handler := SimpleMiddleware(limiter)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPost, "/agents/a", nil)
+			req.Header.Set("X-Session-ID", "s1")
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+
+			// Limits apply again once the store recovers
+			store.fail = false
+			result, err = limiter.CheckAndRecord(context.Background(), ScopeSession, "s1", 0, 1)
+			if err != nil || !result.Allowed || result.Degraded || len(result.Usages) != 1 {
+				t.Errorf("after recovery: result = %+v, err = %v", result, err)
+			}
+		})
+	}
+}
+
+func TestNewRateLimiterRejectsUnknownStoreErrorPolicy(t *testing.T) {
+	_, err := NewRateLimiter(&Config{Enabled: true, OnStoreError: "retry"}, NewMemoryStore())
+	if err == nil {
+		t.Fatal("NewRateLimiter() error = nil, want invalid policy")
+	}
+}
+
+func TestCheckAndRecordUsageChargesCost(t *testing.T) {
+	limiter, err := NewRateLimiter(&Config{
+		Enabled: true,
+		Limits:  []LimitRule{{Type: LimitTypeCost, Window: WindowMonth, Limit: USD(1)}},
+		Pricing: Pricing{
+			"gpt-4o":            {InputPerMillion: 2.50, OutputPerMillion: 10},
+			DefaultPricingModel: {InputPerMillion: 1, OutputPerMillion: 1},
+		},
+	}, NewMemoryStore())
+	if err != nil {
+		t.Fatalf("NewRateLimiter() error = %v", err)
+	}
+	ctx := context.Background()
+
+	// 100k input and 50k output tokens of gpt-4o cost $0.75
+	usage := RequestUsage{Model: "gpt-4o", InputTokens: 100_000, OutputTokens: 50_000, Requests: 1}
+	result, err := limiter.CheckAndRecordUsage(ctx, ScopeUser, "u1", usage)
+	if err != nil {
+		t.Fatalf("CheckAndRecordUsage() error = %v", err)
+	}
+	u := result.GetUsage(LimitTypeCost, WindowMonth)
+	if !result.Allowed || u == nil || u.Cost != 0.75 || u.CostLimit != 1 || u.Current != USD(0.75) {
+		t.Fatalf("result = %+v", result)
+	}
+
+	// Unlisted models use the default entry: $0.50 crosses the budget
+	result, _ = limiter.CheckAndRecordUsage(ctx, ScopeUser, "u1", RequestUsage{Model: "other", InputTokens: 500_000, Requests: 1})
+	if result.Allowed || result.Reason != "cost limit exceeded for month window ($1.25/$1.00)" {
+		t.Errorf("result = %+v, want cost limit exceeded", result)
+	}
+
+	usages, err := limiter.GetUsage(ctx, ScopeUser, "u1")
+	if err != nil || len(usages) != 1 || usages[0].Cost != 1.25 {
+		t.Errorf("GetUsage() = %+v, %v", usages, err)
+	}
+}
+
+func TestNewRateLimiterRequiresPricingForCostLimits(t *testing.T) {
+	limits := []LimitRule{{Type: LimitTypeCost, Window: WindowDay, Limit: USD(10)}}
+	if _, err := NewRateLimiter(&Config{Enabled: true, Limits: limits}, NewMemoryStore()); err == nil {
+		t.Error("NewRateLimiter() error = nil, want pricing required")
+	}
+	pricing := Pricing{"gpt-4o": {InputPerMillion: -1}}
+	if _, err := NewRateLimiter(&Config{Enabled: true, Limits: limits, Pricing: pricing}, NewMemoryStore()); err == nil {
+		t.Error("NewRateLimiter() error = nil, want negative price rejected")
+	}
+}
+
+func TestTieredRateLimiter(t *testing.T) {
+	tiers := map[string]string{"alice": "pro", "bob": "", "carol": "enterprise"}
+	limiter, err := NewTieredRateLimiter(map[string]Config{
+		DefaultTier: {Enabled: true, Limits: []LimitRule{{Type: LimitTypeCount, Window: WindowMinute, Limit: 1}}},
+		"pro":       {Enabled: true, Limits: []LimitRule{{Type: LimitTypeCount, Window: WindowMinute, Limit: 3}}},
+	}, func(_ context.Context, _ Scope, identifier string) string {
+		return tiers[identifier]
+	}, NewMemoryStore())
+	if err != nil {
+		t.Fatalf("NewTieredRateLimiter() error = %v", err)
+	}
+
+	// Limits deny the request crossing them, so a limit of n allows n requests
+	for identifier, want := range map[string]int{"alice": 3, "bob": 1, "carol": 1} {
+		allowed := 0
+		for range 5 {
+			result, err := limiter.CheckAndRecord(context.Background(), ScopeUser, identifier, 0, 1)
+			if err != nil {
+				t.Fatalf("CheckAndRecord() error = %v", err)
+			}
+			if result.Allowed {
+				allowed++
+			}
+		}
+		if allowed != want {
+			t.Errorf("%s: allowed %d requests, want %d", identifier, allowed, want)
+		}
+	}
+
+	// Upgrading applies the new tier's limits to the usage so far
+	tiers["bob"] = "pro"
+	if result, _ := limiter.Check(context.Background(), ScopeUser, "bob"); !result.Allowed {
+		t.Errorf("after upgrade: result = %+v, want allowed", result)
+	}
+}
+
+func TestNewTieredRateLimiterRequiresDefaultTier(t *testing.T) {
+	resolver := func(context.Context, Scope, string) string { return "" }
+	_, err := NewTieredRateLimiter(map[string]Config{"pro": {Enabled: true}}, resolver, NewMemoryStore())
+	if err == nil {
+		t.Error("NewTieredRateLimiter() error = nil, want default tier required")
+	}
+}
@@ -116,7 +116,8 @@ func Middleware(cfg MiddlewareConfig) func(http.Handler) http.Handler {
 			result, err := cfg.Limiter.CheckAndRecord(ctx, scope, identifier, tokenCount, 1)
 			if err != nil {
 				slog.Error("Rate limit check failed", "error", err, "identifier", identifier)
-				// On error, allow the request (fail open)
+				// On error, allow the request (fail open). DefaultRateLimiter
+				// applies its store error policy instead of returning errors.
 				next.ServeHTTP(w, r)
 				return
 			}
@@ -149,10 +150,22 @@ func UsageFromContext(ctx context.Context) *CheckResult {
 	return nil
 }
 
-// defaultOnLimited sends a default 429 response.
+// defaultOnLimited sends a default 429 response, or 503 when the request
+// was denied because the store is unavailable.
 func defaultOnLimited(w http.ResponseWriter, r *http.Request, result *CheckResult) {
 	w.Header().Set("Content-Type", "application/json")
 
+	if result.Degraded {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": map[string]interface{}{
+				"code":    "rate_limit_unavailable",
+				"message": result.Reason,
+			},
+		})
+		return
+	}
+
 	// Add retry-after header
 	if result.RetryAfter != nil && *result.RetryAfter > 0 {
 		w.Header().Set("Retry-After", strconv.FormatInt(int64(result.RetryAfter.Seconds()), 10))
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+)
+
+// DefaultPricingModel is the pricing entry used for models without one.
+const DefaultPricingModel = "default"
+
+// microsPerUSD is the number of micro-dollars in a dollar. Cost limits
+// are tracked in micro-dollars, so stores keep integer counters.
+const microsPerUSD = 1_000_000
+
+// USD converts an amount in US dollars to micro-dollars, the unit of cost
+// limits and cost usage.
+func USD(dollars float64) int64 {
+	return int64(math.Round(dollars * microsPerUSD))
+}
+
+// toUSD converts an amount in micro-dollars to US dollars.
+func toUSD(micros int64) float64 {
+	return float64(micros) / microsPerUSD
+}
+
+// ModelPrice is the price of a model's tokens.
+type ModelPrice struct {
+	// InputPerMillion is the price of one million input tokens in US dollars.
+	InputPerMillion float64
+
+	// OutputPerMillion is the price of one million output tokens in US dollars.
+	OutputPerMillion float64
+}
+
+// Pricing maps model names to token prices. The DefaultPricingModel
+// entry, if present, prices models without an entry of their own; other
+// models cost nothing.
+type Pricing map[string]ModelPrice
+
+// Cost returns the price of the tokens in micro-dollars.
+func (p Pricing) Cost(model string, inputTokens, outputTokens int64) int64 {
+	price, ok := p[model]
+	if !ok {
+		if price, ok = p[DefaultPricingModel]; !ok {
+			return 0
+		}
+	}
+	// A price per million tokens is the price per token in micro-dollars
+	return int64(math.Round(float64(inputTokens)*price.InputPerMillion + float64(outputTokens)*price.OutputPerMillion))
+}
+
+// validate checks that no price is negative.
+func (p Pricing) validate() error {
+	for model, price := range p {
+		if price.InputPerMillion < 0 || price.OutputPerMillion < 0 {
+			return fmt.Errorf("pricing for model %q must not be negative", model)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,336 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultRedisKeyPrefix prefixes the keys of a RedisStore by default.
+const DefaultRedisKeyPrefix = "hector:ratelimit:"
+
+// RedisConfig configures a RedisStore.
+type RedisConfig struct {
+	// Addr is the host:port of the Redis server. Default: "localhost:6379"
+	Addr string
+
+	// Username and Password authenticate with AUTH (optional).
+	// Username requires Redis 6 ACLs.
+	Username string
+	Password string
+
+	// DB is the Redis database number.
+	DB int
+
+	// TLS enables TLS with the given configuration (optional).
+	TLS *tls.Config
+
+	// KeyPrefix prefixes every key, to isolate tenants or deployments
+	// sharing a server. Default: DefaultRedisKeyPrefix
+	KeyPrefix string
+
+	// DialTimeout bounds connecting to the server. Default: 5s
+	DialTimeout time.Duration
+
+	// Timeout bounds each command unless the context has an earlier
+	// deadline, so a server that stops responding fails requests instead
+	// of blocking them. Default: 2s
+	Timeout time.Duration
+
+	// MaxIdleConns is the number of idle connections kept open. Default: 10
+	MaxIdleConns int
+}
+
+// RedisStore is a Redis-based implementation of Store, shared by every
+// replica using the same server.
+//
+// Each limit window is a counter key that expires with its window, so
+// Redis reclaims the memory of expired windows itself. Updates run as Lua
+// scripts, and CheckAndIncrement checks and records every limit in one
+// script, so concurrent requests on different replicas can't overshoot
+// a limit together.
+//
+// The store talks to a single server; Redis Cluster is not supported, as
+// MOVED and ASK redirects are not followed.
+type RedisStore struct {
+	client *redisClient
+	prefix string
+}
+
+// NewRedisStore connects to Redis and creates a store.
+func NewRedisStore(cfg RedisConfig) (*RedisStore, error) {
+	if cfg.Addr == "" {
+		cfg.Addr = "localhost:6379"
+	}
+	if cfg.KeyPrefix == "" {
+		cfg.KeyPrefix = DefaultRedisKeyPrefix
+	}
+	if cfg.DialTimeout <= 0 {
+		cfg.DialTimeout = 5 * time.Second
+	}
+	if cfg.MaxIdleConns <= 0 {
+		cfg.MaxIdleConns = 10
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 2 * time.Second
+	}
+
+	s := &RedisStore{
+		client: &redisClient{
+			addr:        cfg.Addr,
+			username:    cfg.Username,
+			password:    cfg.Password,
+			db:          cfg.DB,
+			tlsConfig:   cfg.TLS,
+			dialTimeout: cfg.DialTimeout,
+			timeout:     cfg.Timeout,
+			maxIdle:     cfg.MaxIdleConns,
+		},
+		prefix: cfg.KeyPrefix,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.DialTimeout)
+	defer cancel()
+	if _, err := s.client.do(ctx, "PING"); err != nil {
+		s.client.close()
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	return s, nil
+}
+
+// getUsageScript returns {amount, ttl in ms} of a window key, or {0, -2}
+// if there is none.
+var getUsageScript = newRedisScript(`
+local v = redis.call('GET', KEYS[1])
+if not v then
+  return {0, -2}
+end
+return {tonumber(v), redis.call('PTTL', KEYS[1])}
+`)
+
+// incrementScript adds ARGV[1] to a window key, starting the window of
+// ARGV[2] ms if the key is new. It returns {amount, ttl in ms}.
+var incrementScript = newRedisScript(`
+local amount = redis.call('INCRBY', KEYS[1], ARGV[1])
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl < 0 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[2])
+  ttl = tonumber(ARGV[2])
+end
+return {amount, ttl}
+`)
+
+// checkAndIncrementScript checks every window key against its limit and,
+// if none is exceeded, adds the amounts. ARGV holds limit, window ms and
+// amount per key. It returns {allowed, amount1, ttl1, amount2, ttl2, ...}
+// with ttl -2 for windows that haven't started.
+var checkAndIncrementScript = newRedisScript(`
+local current, ttl = {}, {}
+local allowed = 1
+for i = 1, #KEYS do
+  local v = redis.call('GET', KEYS[i])
+  if v then
+    current[i] = tonumber(v)
+    ttl[i] = redis.call('PTTL', KEYS[i])
+  else
+    current[i] = 0
+    ttl[i] = -2
+  end
+  if current[i] > tonumber(ARGV[i*3-2]) then
+    allowed = 0
+  end
+end
+if allowed == 1 then
+  for i = 1, #KEYS do
+    local amount = tonumber(ARGV[i*3])
+    if amount > 0 then
+      current[i] = redis.call('INCRBY', KEYS[i], amount)
+      if ttl[i] < 0 then
+        redis.call('PEXPIRE', KEYS[i], ARGV[i*3-1])
+        ttl[i] = tonumber(ARGV[i*3-1])
+      end
+    end
+  end
+end
+local result = {allowed}
+for i = 1, #KEYS do
+  result[#result+1] = current[i]
+  result[#result+1] = ttl[i]
+end
+return result
+`)
+
+// GetUsage gets current usage for a specific limit.
+func (s *RedisStore) GetUsage(ctx context.Context, scope Scope, identifier string, limitType LimitType, window TimeWindow) (int64, time.Time, error) {
+	reply, err := getUsageScript.run(ctx, s.client, []string{s.key(scope, identifier, limitType, window)})
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to query usage: %w", err)
+	}
+	amount, windowEnd, err := parseWindowReply(reply, 0, window, time.Now())
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to query usage: %w", err)
+	}
+	return amount, windowEnd, nil
+}
+
+// IncrementUsage increments usage for a specific limit.
+func (s *RedisStore) IncrementUsage(ctx context.Context, scope Scope, identifier string, limitType LimitType, window TimeWindow, amount int64) (int64, time.Time, error) {
+	reply, err := incrementScript.run(ctx, s.client, []string{s.key(scope, identifier, limitType, window)},
+		amount, window.Duration().Milliseconds())
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to update usage: %w", err)
+	}
+	newAmount, windowEnd, err := parseWindowReply(reply, 0, window, time.Now())
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to update usage: %w", err)
+	}
+	return newAmount, windowEnd, nil
+}
+
+// SetUsage sets usage for a specific limit. The key expires at windowEnd.
+func (s *RedisStore) SetUsage(ctx context.Context, scope Scope, identifier string, limitType LimitType, window TimeWindow, amount int64, windowEnd time.Time) error {
+	key := s.key(scope, identifier, limitType, window)
+
+	var err error
+	if ttl := time.Until(windowEnd).Milliseconds(); ttl > 0 {
+		_, err = s.client.do(ctx, "SET", key, amount, "PX", ttl)
+	} else {
+		_, err = s.client.do(ctx, "DEL", key)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to set usage: %w", err)
+	}
+	return nil
+}
+
+// DeleteUsage deletes usage records for an identifier.
+func (s *RedisStore) DeleteUsage(ctx context.Context, scope Scope, identifier string) error {
+	pattern := escapeRedisPattern(s.prefix+s.tag(scope, identifier)) + ":*"
+
+	cursor := "0"
+	for {
+		reply, err := s.client.do(ctx, "SCAN", cursor, "MATCH", pattern, "COUNT", 100)
+		if err != nil {
+			return fmt.Errorf("failed to delete usage: %w", err)
+		}
+		page, ok := reply.([]any)
+		if !ok || len(page) != 2 {
+			return fmt.Errorf("failed to delete usage: unexpected SCAN reply")
+		}
+		next, _ := page[0].([]byte)
+		keys, _ := page[1].([]any)
+
+		if len(keys) > 0 {
+			if _, err := s.client.do(ctx, append([]any{"DEL"}, keys...)...); err != nil {
+				return fmt.Errorf("failed to delete usage: %w", err)
+			}
+		}
+
+		cursor = string(next)
+		if cursor == "0" || cursor == "" {
+			return nil
+		}
+	}
+}
+
+// DeleteExpired is a no-op: window keys expire in Redis on their own.
+func (s *RedisStore) DeleteExpired(ctx context.Context, before time.Time) error {
+	return nil
+}
+
+// CheckAndIncrement checks every limit and, if none is exceeded, records
+// the usage, in one atomic script.
+func (s *RedisStore) CheckAndIncrement(ctx context.Context, scope Scope, identifier string, limits []LimitRule, amounts []int64) (bool, []WindowUsage, error) {
+	keys := make([]string, len(limits))
+	args := make([]any, 0, 3*len(limits))
+	for i, limit := range limits {
+		keys[i] = s.key(scope, identifier, limit.Type, limit.Window)
+		args = append(args, limit.Limit, limit.Window.Duration().Milliseconds(), amounts[i])
+	}
+
+	reply, err := checkAndIncrementScript.run(ctx, s.client, keys, args...)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to check and record usage: %w", err)
+	}
+	values, ok := reply.([]any)
+	if !ok || len(values) != 1+2*len(limits) {
+		return false, nil, fmt.Errorf("failed to check and record usage: unexpected reply %v", reply)
+	}
+	allowed, err := redisInt(values[0])
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to check and record usage: %w", err)
+	}
+
+	now := time.Now()
+	usages := make([]WindowUsage, len(limits))
+	for i, limit := range limits {
+		amount, windowEnd, err := parseWindowReply(values, 1+2*i, limit.Window, now)
+		if err != nil {
+			return false, nil, fmt.Errorf("failed to check and record usage: %w", err)
+		}
+		usages[i] = WindowUsage{Current: amount, WindowEnd: windowEnd}
+	}
+	return allowed == 1, usages, nil
+}
+
+// Close closes the connections to Redis.
+func (s *RedisStore) Close() error {
+	return s.client.close()
+}
+
+// key returns the window key of a limit. Keys of one scope and identifier
+// share a {hash tag}, which keeps the keys of one script in one hash slot.
+func (s *RedisStore) key(scope Scope, identifier string, limitType LimitType, window TimeWindow) string {
+	return s.prefix + s.tag(scope, identifier) + ":" + string(limitType) + ":" + string(window)
+}
+
+func (s *RedisStore) tag(scope Scope, identifier string) string {
+	// Braces would end the hash tag early
+	r := strings.NewReplacer("{", "%7B", "}", "%7D")
+	return "{" + string(scope) + ":" + r.Replace(identifier) + "}"
+}
+
+// parseWindowReply reads the {amount, ttl in ms} pair at values[i] (or the
+// reply itself). A negative ttl means the window hasn't started.
+func parseWindowReply(reply any, i int, window TimeWindow, now time.Time) (int64, time.Time, error) {
+	values, ok := reply.([]any)
+	if !ok || len(values) < i+2 {
+		return 0, time.Time{}, fmt.Errorf("unexpected reply %v", reply)
+	}
+	amount, err := redisInt(values[i])
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	ttl, err := redisInt(values[i+1])
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	if ttl < 0 {
+		return amount, now.Add(window.Duration()), nil
+	}
+	return amount, now.Add(time.Duration(ttl) * time.Millisecond), nil
+}
+
+// escapeRedisPattern escapes glob characters for SCAN MATCH.
+func escapeRedisPattern(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+	return r.Replace(s)
+}
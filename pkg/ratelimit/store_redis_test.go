@@ -0,0 +1,370 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis is a Redis server speaking RESP2 for the commands RedisStore
+// uses. Lua scripts are emulated by their Go equivalents.
+type fakeRedis struct {
+	t        *testing.T
+	password string
+
+	mu      sync.Mutex
+	values  map[string]int64
+	expires map[string]time.Time
+	scripts map[string]bool // SHAs loaded by EVAL
+	evals   int
+	stalled bool // Read commands without replying
+}
+
+func newFakeRedis(t *testing.T, password string) (*fakeRedis, string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	f := &fakeRedis{
+		t:        t,
+		password: password,
+		values:   make(map[string]int64),
+		expires:  make(map[string]time.Time),
+		scripts:  make(map[string]bool),
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f, ln.Addr().String()
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r, w := bufio.NewReader(conn), bufio.NewWriter(conn)
+	authed := f.password == ""
+	for {
+		req, err := readRedisReply(r)
+		if err != nil {
+			return
+		}
+		items, _ := req.([]any)
+		args := make([]string, len(items))
+		for i, item := range items {
+			b, _ := item.([]byte)
+			args[i] = string(b)
+		}
+
+		var reply any
+		switch {
+		case args[0] == "AUTH":
+			if args[len(args)-1] == f.password {
+				authed = true
+				reply = "OK"
+			} else {
+				reply = redisError("WRONGPASS invalid password")
+			}
+		case !authed:
+			reply = redisError("NOAUTH Authentication required.")
+		default:
+			reply = f.exec(args)
+		}
+		f.mu.Lock()
+		stalled := f.stalled
+		f.mu.Unlock()
+		if stalled {
+			continue
+		}
+		writeFakeReply(w, reply)
+		w.Flush()
+	}
+}
+
+func writeFakeReply(w *bufio.Writer, reply any) {
+	switch v := reply.(type) {
+	case string:
+		fmt.Fprintf(w, "+%s\r\n", v)
+	case redisError:
+		fmt.Fprintf(w, "-%s\r\n", v)
+	case int64:
+		fmt.Fprintf(w, ":%d\r\n", v)
+	case []byte:
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(v), v)
+	case []any:
+		fmt.Fprintf(w, "*%d\r\n", len(v))
+		for _, item := range v {
+			writeFakeReply(w, item)
+		}
+	}
+}
+
+func (f *fakeRedis) exec(args []string) any {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for key, exp := range f.expires {
+		if time.Now().After(exp) {
+			delete(f.values, key)
+			delete(f.expires, key)
+		}
+	}
+
+	switch args[0] {
+	case "PING":
+		return "PONG"
+	case "SELECT":
+		return "OK"
+	case "SET":
+		n, _ := strconv.ParseInt(args[2], 10, 64)
+		f.values[args[1]] = n
+		delete(f.expires, args[1])
+		if len(args) == 5 && args[3] == "PX" {
+			ms, _ := strconv.Atoi(args[4])
+			f.expires[args[1]] = time.Now().Add(time.Duration(ms) * time.Millisecond)
+		}
+		return "OK"
+	case "DEL":
+		var n int64
+		for _, key := range args[1:] {
+			if _, ok := f.values[key]; ok {
+				n++
+			}
+			delete(f.values, key)
+			delete(f.expires, key)
+		}
+		return n
+	case "SCAN":
+		var keys []any
+		for key := range f.values {
+			if ok, _ := path.Match(args[3], key); ok {
+				keys = append(keys, []byte(key))
+			}
+		}
+		return []any{[]byte("0"), keys}
+	case "EVALSHA":
+		if !f.scripts[args[1]] {
+			return redisError("NOSCRIPT No matching script. Please use EVAL.")
+		}
+		return f.eval(args[1], args[2:])
+	case "EVAL":
+		f.evals++
+		sha := newRedisScript(args[1]).sha
+		f.scripts[sha] = true
+		return f.eval(sha, args[2:])
+	}
+	return redisError("ERR unknown command '" + args[0] + "'")
+}
+
+func (f *fakeRedis) pttl(key string) int64 {
+	if _, ok := f.values[key]; !ok {
+		return -2
+	}
+	exp, ok := f.expires[key]
+	if !ok {
+		return -1
+	}
+	return time.Until(exp).Milliseconds()
+}
+
+func (f *fakeRedis) incr(key string, amount int64, windowMs string) (int64, int64) {
+	f.values[key] += amount
+	ttl := f.pttl(key)
+	if ttl < 0 {
+		ms, _ := strconv.ParseInt(windowMs, 10, 64)
+		f.expires[key] = time.Now().Add(time.Duration(ms) * time.Millisecond)
+		ttl = ms
+	}
+	return f.values[key], ttl
+}
+
+// eval emulates the store's scripts.
+func (f *fakeRedis) eval(sha string, args []string) any {
+	numKeys, _ := strconv.Atoi(args[0])
+	keys, argv := args[1:1+numKeys], args[1+numKeys:]
+
+	switch sha {
+	case getUsageScript.sha:
+		if _, ok := f.values[keys[0]]; !ok {
+			return []any{int64(0), int64(-2)}
+		}
+		return []any{f.values[keys[0]], f.pttl(keys[0])}
+	case incrementScript.sha:
+		amount, _ := strconv.ParseInt(argv[0], 10, 64)
+		v, ttl := f.incr(keys[0], amount, argv[1])
+		return []any{v, ttl}
+	case checkAndIncrementScript.sha:
+		allowed := int64(1)
+		for i, key := range keys {
+			limit, _ := strconv.ParseInt(argv[i*3], 10, 64)
+			if f.values[key] > limit {
+				allowed = 0
+			}
+		}
+		result := []any{allowed}
+		for i, key := range keys {
+			amount, _ := strconv.ParseInt(argv[i*3+2], 10, 64)
+			if allowed == 1 && amount > 0 {
+				f.incr(key, amount, argv[i*3+1])
+			}
+			result = append(result, f.values[key], f.pttl(key))
+		}
+		return result
+	}
+	f.t.Errorf("unexpected script %s", sha)
+	return redisError("ERR unknown script")
+}
+
+func TestRedisStore(t *testing.T) {
+	fake, addr := newFakeRedis(t, "secret")
+	ctx := context.Background()
+
+	if _, err := NewRedisStore(RedisConfig{Addr: addr, Password: "wrong"}); err == nil {
+		t.Fatal("expected authentication error")
+	}
+
+	store, err := NewRedisStore(RedisConfig{Addr: addr, Password: "secret", KeyPrefix: "tenant-a:"})
+	if err != nil {
+		t.Fatalf("NewRedisStore() error = %v", err)
+	}
+	defer store.Close()
+
+	amount, windowEnd, err := store.GetUsage(ctx, ScopeUser, "alice", LimitTypeToken, WindowMinute)
+	if err != nil || amount != 0 {
+		t.Fatalf("GetUsage() = %d, %v", amount, err)
+	}
+	if d := time.Until(windowEnd); d < 59*time.Second || d > time.Minute {
+		t.Errorf("new window ends in %v, want 1m", d)
+	}
+
+	store.IncrementUsage(ctx, ScopeUser, "alice", LimitTypeToken, WindowMinute, 100)
+	amount, _, err = store.IncrementUsage(ctx, ScopeUser, "alice", LimitTypeToken, WindowMinute, 50)
+	if err != nil || amount != 150 {
+		t.Fatalf("IncrementUsage() = %d, %v", amount, err)
+	}
+	if fake.evals != 2 {
+		t.Errorf("EVAL calls = %d, want one per script before EVALSHA takes over", fake.evals)
+	}
+
+	// Keys carry the prefix and expire with their window
+	key := "tenant-a:{user:alice}:token:minute"
+	fake.mu.Lock()
+	value, ttl := fake.values[key], fake.pttl(key)
+	fake.mu.Unlock()
+	if value != 150 || ttl <= 0 || ttl > time.Minute.Milliseconds() {
+		t.Errorf("key %q = %d with ttl %dms", key, value, ttl)
+	}
+
+	if err := store.SetUsage(ctx, ScopeUser, "alice", LimitTypeCount, WindowHour, 7, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("SetUsage() error = %v", err)
+	}
+	if amount, _, _ := store.GetUsage(ctx, ScopeUser, "alice", LimitTypeCount, WindowHour); amount != 7 {
+		t.Errorf("usage after SetUsage = %d, want 7", amount)
+	}
+
+	store.IncrementUsage(ctx, ScopeUser, "alice*", LimitTypeToken, WindowMinute, 1)
+	if err := store.DeleteUsage(ctx, ScopeUser, "alice"); err != nil {
+		t.Fatalf("DeleteUsage() error = %v", err)
+	}
+	if amount, _, _ := store.GetUsage(ctx, ScopeUser, "alice", LimitTypeToken, WindowMinute); amount != 0 {
+		t.Errorf("usage after DeleteUsage = %d, want 0", amount)
+	}
+	if amount, _, _ := store.GetUsage(ctx, ScopeUser, "alice*", LimitTypeToken, WindowMinute); amount != 1 {
+		t.Errorf("DeleteUsage removed another identifier's usage")
+	}
+}
+
+func TestRedisStoreCheckAndRecord(t *testing.T) {
+	_, addr := newFakeRedis(t, "")
+	store, err := NewRedisStore(RedisConfig{Addr: addr})
+	if err != nil {
+		t.Fatalf("NewRedisStore() error = %v", err)
+	}
+	defer store.Close()
+
+	limiter, err := NewRateLimiter(&Config{
+		Enabled: true,
+		Limits: []LimitRule{
+			{Type: LimitTypeToken, Window: WindowDay, Limit: 1000},
+			{Type: LimitTypeCount, Window: WindowMinute, Limit: 3},
+		},
+	}, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for i := range 3 {
+		result, err := limiter.CheckAndRecord(ctx, ScopeSession, "s1", 100, 1)
+		if err != nil || !result.Allowed {
+			t.Fatalf("request %d: allowed = %v, err = %v", i+1, result.Allowed, err)
+		}
+	}
+
+	// Like the other stores, the request that goes over the limit is
+	// recorded and denied; later requests are denied without recording
+	for i := range 2 {
+		result, err := limiter.CheckAndRecord(ctx, ScopeSession, "s1", 100, 1)
+		if err != nil || result.Allowed || result.RetryAfter == nil {
+			t.Fatalf("request %d: result = %+v, err = %v", i+4, result, err)
+		}
+		if u := result.GetUsage(LimitTypeToken, WindowDay); u == nil || u.Current != 400 {
+			t.Errorf("request %d: token usage = %+v, want 400", i+4, u)
+		}
+	}
+
+	// Another identifier is unaffected
+	if result, _ := limiter.CheckAndRecord(ctx, ScopeSession, "s2", 100, 1); !result.Allowed {
+		t.Error("other session was limited")
+	}
+}
+
+func TestRedisStoreTimesOutOnUnresponsiveServer(t *testing.T) {
+	fake, addr := newFakeRedis(t, "")
+	store, err := NewRedisStore(RedisConfig{Addr: addr, Timeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewRedisStore() error = %v", err)
+	}
+	defer store.Close()
+
+	fake.mu.Lock()
+	fake.stalled = true
+	fake.mu.Unlock()
+
+	// Request contexts carry no deadline; the command timeout still applies
+	start := time.Now()
+	if _, _, err := store.GetUsage(context.Background(), ScopeUser, "alice", LimitTypeToken, WindowMinute); err == nil {
+		t.Fatal("expected a timeout error")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("GetUsage() returned after %v", elapsed)
+	}
+}
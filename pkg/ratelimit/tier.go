@@ -0,0 +1,48 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+)
+
+// DefaultTier is the tier applied to identifiers without a known tier.
+const DefaultTier = "default"
+
+// TierResolver returns the tier of an identifier, such as "free" or
+// "pro". An empty tier selects DefaultTier.
+type TierResolver func(ctx context.Context, scope Scope, identifier string) string
+
+// StateTierResolver returns a resolver reading the tier from the session
+// state key (e.g. "user:tier") when limits are checked within an agent
+// invocation, whose context carries session state. Elsewhere, and when
+// the key is unset, it selects DefaultTier.
+func StateTierResolver(key string) TierResolver {
+	return func(ctx context.Context, _ Scope, _ string) string {
+		rctx, ok := ctx.(agent.ReadonlyContext)
+		if !ok || rctx.ReadonlyState() == nil {
+			return ""
+		}
+		v, err := rctx.ReadonlyState().Get(key)
+		if err != nil || v == nil {
+			return ""
+		}
+		return fmt.Sprint(v)
+	}
+}
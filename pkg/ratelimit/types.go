@@ -30,6 +30,17 @@ const (
 	ScopeUser Scope = "user"
 )
 
+// StoreErrorPolicy decides requests while the store is unavailable.
+type StoreErrorPolicy string
+
+const (
+	// StoreErrorAllow allows requests without enforcing limits (fail open).
+	StoreErrorAllow StoreErrorPolicy = "allow"
+
+	// StoreErrorDeny rejects requests (fail closed).
+	StoreErrorDeny StoreErrorPolicy = "deny"
+)
+
 // TimeWindow represents a rate limiting time window.
 type TimeWindow string
 
@@ -82,6 +93,10 @@ const (
 
 	// LimitTypeCount tracks request count.
 	LimitTypeCount LimitType = "count"
+
+	// LimitTypeCost tracks spend, priced per model from token usage.
+	// Amounts are in micro-dollars (see USD).
+	LimitTypeCost LimitType = "cost"
 )
 
 // String returns the string representation of the limit type.
@@ -114,6 +129,8 @@ func ParseLimitType(s string) LimitType {
 		return LimitTypeToken
 	case "count":
 		return LimitTypeCount
+	case "cost":
+		return LimitTypeCost
 	default:
 		return LimitType(s)
 	}
@@ -133,7 +150,7 @@ func ParseScope(s string) Scope {
 
 // Usage represents current usage for a specific limit.
 type Usage struct {
-	// LimitType is the type of limit (token or count).
+	// LimitType is the type of limit (token, count or cost).
 	LimitType LimitType `json:"limit_type"`
 
 	// Window is the time window for this usage.
@@ -153,6 +170,44 @@ type Usage struct {
 
 	// Percentage is the usage percentage (0-100+).
 	Percentage float64 `json:"percentage"`
+
+	// Cost is the spend in the window in US dollars (cost limits only).
+	Cost float64 `json:"cost,omitempty"`
+
+	// CostLimit is the maximum spend in the window in US dollars (cost
+	// limits only).
+	CostLimit float64 `json:"cost_limit,omitempty"`
+}
+
+// RequestUsage is the usage of one operation. Tokens are charged against
+// token limits, requests against count limits and the priced tokens
+// against cost limits.
+type RequestUsage struct {
+	// Model is the model that served the operation, used for pricing.
+	Model string
+
+	// InputTokens is the number of prompt tokens.
+	InputTokens int64
+
+	// OutputTokens is the number of completion tokens.
+	OutputTokens int64
+
+	// Requests is the number of requests, usually 1.
+	Requests int64
+}
+
+// Tokens returns the total number of tokens.
+func (u RequestUsage) Tokens() int64 {
+	return u.InputTokens + u.OutputTokens
+}
+
+// WindowUsage is the usage of one limit in its current window.
+type WindowUsage struct {
+	// Current is the usage in the window.
+	Current int64
+
+	// WindowEnd is when the window ends.
+	WindowEnd time.Time
 }
 
 // CheckResult represents the result of a rate limit check.
@@ -168,6 +223,10 @@ type CheckResult struct {
 
 	// RetryAfter indicates how long to wait before retrying (if denied).
 	RetryAfter *time.Duration `json:"retry_after,omitempty"`
+
+	// Degraded is set when the store was unavailable and the result was
+	// decided by the store error policy. Usages are empty then.
+	Degraded bool `json:"degraded,omitempty"`
 }
 
 // IsExceeded returns true if any limit is exceeded.
@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"iter"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+)
+
+// Handlers are typed callbacks for RunWithHandlers. Nil handlers are skipped.
+//
+// Streamed (partial) text and thinking arrive with partial=true; the complete
+// text of each model response follows with partial=false, so handlers that
+// print chunks should ignore the non-partial call, and vice versa.
+type Handlers struct {
+	// OnEvent receives every event before the typed handlers run.
+	OnEvent func(event *agent.Event)
+
+	// OnText receives answer text (never reasoning).
+	OnText func(text string, partial bool)
+
+	// OnThinking receives the model's reasoning.
+	OnThinking func(text string, partial bool)
+
+	// OnToolCall receives each tool call the model makes.
+	OnToolCall func(call agent.ToolCallState)
+
+	// OnToolResult receives each tool execution result.
+	OnToolResult func(result agent.ToolResultState)
+
+	// OnTransfer receives the name of the agent control is transferred to.
+	OnTransfer func(agentName string)
+
+	// OnError receives the error that ended the run.
+	OnError func(err error)
+
+	// OnDone receives the last final response once the run completes
+	// without error. The event is nil if the agent produced no final response.
+	OnDone func(final *agent.Event)
+}
+
+// RunWithHandlers runs the agent like Run and dispatches each event to the
+// matching handler instead of yielding it. It returns the error that ended
+// the run, if any, after passing it to OnError.
+//
+// Example:
+//
+//	err := r.RunWithHandlers(ctx, "user1", "session1", content, agent.RunConfig{}, runner.Handlers{
+//	    OnText: func(text string, partial bool) {
+//	        if partial {
+//	            fmt.Print(text)
+//	        }
+//	    },
+//	    OnToolCall: func(call agent.ToolCallState) { fmt.Println("calling", call.Name) },
+//	})
+func (r *Runner) RunWithHandlers(ctx context.Context, userID, sessionID string, content *agent.Content, cfg agent.RunConfig, h Handlers) error {
+	return dispatchEvents(r.Run(ctx, userID, sessionID, content, cfg), h)
+}
+
+// dispatchEvents drains events into h.
+func dispatchEvents(events iter.Seq2[*agent.Event, error], h Handlers) error {
+	var final *agent.Event
+	for event, err := range events {
+		if err != nil {
+			if h.OnError != nil {
+				h.OnError(err)
+			}
+			return err
+		}
+		if event == nil {
+			continue
+		}
+
+		if h.OnEvent != nil {
+			h.OnEvent(event)
+		}
+		if h.OnThinking != nil {
+			if thinking := event.ThinkingContent(); thinking != "" {
+				h.OnThinking(thinking, event.Partial)
+			}
+		}
+		if h.OnText != nil {
+			if text := event.TextContent(); text != "" {
+				h.OnText(text, event.Partial)
+			}
+		}
+
+		// Partial events may preview tool calls; report them once, when final
+		if !event.Partial && h.OnToolCall != nil {
+			for _, call := range event.ToolCalls {
+				h.OnToolCall(call)
+			}
+		}
+		if h.OnToolResult != nil {
+			for _, result := range event.ToolResults {
+				h.OnToolResult(result)
+			}
+		}
+		if h.OnTransfer != nil && event.Actions.TransferToAgent != "" {
+			h.OnTransfer(event.Actions.TransferToAgent)
+		}
+
+		if event.IsFinalResponse() {
+			final = event
+		}
+	}
+
+	if h.OnDone != nil {
+		h.OnDone(final)
+	}
+	return nil
+}
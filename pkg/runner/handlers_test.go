@@ -0,0 +1,58 @@
+package runner
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+)
+
+func TestDispatchEvents(t *testing.T) {
+	chunk := &agent.Event{Partial: true, Message: a2a.NewMessage(a2a.MessageRoleAgent, a2a.TextPart{Text: "Hel"})}
+	call := &agent.Event{ToolCalls: []agent.ToolCallState{{ID: "c1", Name: "search"}}}
+	result := &agent.Event{ToolResults: []agent.ToolResultState{{ToolCallID: "c1", Status: "success"}}}
+	final := &agent.Event{
+		Message:  a2a.NewMessage(a2a.MessageRoleAgent, a2a.TextPart{Text: "Hello"}),
+		Thinking: &agent.ThinkingState{Content: "greet"},
+	}
+	events := func(yield func(*agent.Event, error) bool) {
+		for _, ev := range []*agent.Event{chunk, call, result, final} {
+			if !yield(ev, nil) {
+				return
+			}
+		}
+	}
+
+	var texts, thinking, calls []string
+	var done *agent.Event
+	err := dispatchEvents(events, Handlers{
+		OnText:     func(text string, partial bool) { texts = append(texts, text) },
+		OnThinking: func(text string, partial bool) { thinking = append(thinking, text) },
+		OnToolCall: func(c agent.ToolCallState) { calls = append(calls, c.Name) },
+		OnDone:     func(ev *agent.Event) { done = ev },
+	})
+	if err != nil {
+		t.Fatalf("dispatchEvents() error = %v", err)
+	}
+	if len(texts) != 2 || texts[0] != "Hel" || texts[1] != "Hello" {
+		t.Errorf("texts = %v, want [Hel Hello]", texts)
+	}
+	if len(thinking) != 1 || len(calls) != 1 || calls[0] != "search" {
+		t.Errorf("thinking = %v, calls = %v", thinking, calls)
+	}
+	if done != final {
+		t.Error("OnDone did not receive the final response")
+	}
+
+	boom := errors.New("boom")
+	var gotErr error
+	err = dispatchEvents(func(yield func(*agent.Event, error) bool) { yield(nil, boom) }, Handlers{
+		OnError: func(err error) { gotErr = err },
+		OnDone:  func(*agent.Event) { t.Error("OnDone called after error") },
+	})
+	if !errors.Is(err, boom) || !errors.Is(gotErr, boom) {
+		t.Errorf("error = %v, OnError got %v", err, gotErr)
+	}
+}
@@ -55,6 +55,11 @@ type Config struct {
 	// CheckpointManager handles execution state checkpointing (optional).
 	// Enables fault tolerance and HITL workflow recovery.
 	CheckpointManager CheckpointManager
+
+	// SessionIDGenerator returns the ID of a new session when the caller
+	// does not supply one (optional). Default: the session service's
+	// generator (random UUIDs).
+	SessionIDGenerator func() string
 }
 
 // ArtifactService defines the interface for artifact storage.
@@ -100,6 +105,7 @@ type Runner struct {
 	artifactService   ArtifactService
 	indexService      IndexService
 	checkpointManager CheckpointManager
+	newSessionID      func() string
 	parents           ParentMap
 }
 
@@ -124,6 +130,7 @@ func New(cfg Config) (*Runner, error) {
 		artifactService:   cfg.ArtifactService,
 		indexService:      cfg.IndexService,
 		checkpointManager: cfg.CheckpointManager,
+		newSessionID:      cfg.SessionIDGenerator,
 		parents:           parents,
 	}, nil
 }
@@ -149,6 +156,8 @@ func (r *Runner) Run(ctx context.Context, userID, sessionID string, content *age
 		//   1. clearTempState - Clean up temp keys
 		//   2. indexSession - Build search index (data already in SessionService)
 		//   3. checkAndSummarize - Working memory management
+		//   4. updateLongTermMemory - Remember user facts across sessions
+		//   5. clearCheckpoint - Drop the checkpoint of a completed invocation
 
 		// 1. Clear temp keys after invocation completes (adk-go pattern)
 		defer r.clearTempState(sess)
@@ -160,6 +169,9 @@ func (r *Runner) Run(ctx context.Context, userID, sessionID string, content *age
 		// 3. Check and perform summarization if needed (legacy hector pattern)
 		defer r.checkAndSummarize(ctx, sess, agentToRun)
 
+		// 4. Extract durable user facts from the turn into user-scoped state
+		defer r.updateLongTermMemory(ctx, sess, agentToRun)
+
 		// Create scoped memory adapter for this invocation
 		// The adapter bridges IndexService to agent.Memory interface
 		var mem agent.Memory
@@ -185,9 +197,19 @@ func (r *Runner) Run(ctx context.Context, userID, sessionID string, content *age
 			return
 		}
 
+		// 5. Drop the invocation's checkpoint once it completes cleanly
+		completed := false
+		defer func() {
+			if completed {
+				r.clearCheckpoint(ctx, sess, invCtx.InvocationID())
+			}
+		}()
+
 		// Run agent and yield events
+		failed := false
 		for event, err := range agentToRun.Run(invCtx) {
 			if err != nil {
+				failed = true
 				if !yield(event, err) {
 					return
 				}
@@ -210,6 +232,22 @@ func (r *Runner) Run(ctx context.Context, userID, sessionID string, content *age
 				return
 			}
 		}
+		completed = !failed
+	}
+}
+
+// clearCheckpoint removes the checkpoint of a completed invocation so it is
+// not resumed on the next startup.
+func (r *Runner) clearCheckpoint(ctx context.Context, sess session.Session, invocationID string) {
+	if !r.IsCheckpointEnabled() {
+		return
+	}
+
+	if err := r.checkpointManager.ClearCheckpoint(ctx, r.appName, sess.UserID(), sess.ID(), invocationID); err != nil {
+		slog.Warn("Failed to clear checkpoint",
+			"session_id", sess.ID(),
+			"invocation_id", invocationID,
+			"error", err)
 	}
 }
 
@@ -281,6 +319,46 @@ func (r *Runner) checkAndSummarize(ctx context.Context, sess session.Session, ag
 	}
 }
 
+// updateLongTermMemory extracts durable user facts from the latest turn and
+// persists them to user-scoped state, shared by all of the user's sessions.
+// Nothing is remembered for anonymous callers, who all share one user ID.
+func (r *Runner) updateLongTermMemory(ctx context.Context, sess session.Session, ag agent.Agent) {
+	if agent.IsAnonymousUser(sess.UserID()) {
+		return
+	}
+
+	ltmProvider, ok := ag.(memory.LongTermMemoryProvider)
+	if !ok {
+		return // Agent doesn't support long-term memory
+	}
+
+	ltm := ltmProvider.LongTermMemory()
+	if ltm == nil {
+		return // No long-term memory configured
+	}
+
+	var events []*agent.Event
+	for ev := range sess.Events().All() {
+		events = append(events, ev)
+	}
+
+	factsEvent, err := ltm.Update(ctx, sess.State(), events)
+	if err != nil {
+		slog.Warn("Long-term memory update failed",
+			"session_id", sess.ID(),
+			"error", err)
+		return
+	}
+
+	if factsEvent != nil {
+		if err := r.sessionService.AppendEvent(ctx, sess, factsEvent); err != nil {
+			slog.Error("Failed to persist long-term memory facts",
+				"session_id", sess.ID(),
+				"error", err)
+		}
+	}
+}
+
 // clearTempState removes all temp: prefixed keys from session state.
 // This follows adk-go's pattern where temporary state is discarded after each invocation.
 func (r *Runner) clearTempState(sess session.Session) {
@@ -307,6 +385,10 @@ func (r *Runner) ListAgents() []agent.Agent {
 }
 
 func (r *Runner) getOrCreateSession(ctx context.Context, userID, sessionID string) (session.Session, error) {
+	if sessionID == "" && r.newSessionID != nil {
+		sessionID = r.newSessionID()
+	}
+
 	resp, err := r.sessionService.Get(ctx, &session.GetRequest{
 		AppName:   r.appName,
 		UserID:    userID,
@@ -393,7 +475,9 @@ func (r *Runner) isWorkflowSubAgent(ag agent.Agent) bool {
 	t := parent.Type()
 	if t == agent.TypeSequentialAgent ||
 		t == agent.TypeParallelAgent ||
-		t == agent.TypeLoopAgent {
+		t == agent.TypeLoopAgent ||
+		t == agent.TypeConditionalAgent ||
+		t == agent.TypeMapReduceAgent {
 		slog.Debug("Parent is workflow agent, skipping sub-agent",
 			"agent", ag.Name(),
 			"parent", parent.Name(),
@@ -0,0 +1,113 @@
+package runner
+
+import (
+	"context"
+	"iter"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/memory"
+	"github.com/kadirpekel/hector/pkg/session"
+)
+
+func TestRunUsesSessionIDGenerator(t *testing.T) {
+	var sessionID string
+	ag, err := agent.New(agent.Config{
+		Name: "echo",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*agent.Event, error] {
+			return func(yield func(*agent.Event, error) bool) {
+				sessionID = ctx.Session().ID()
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create agent: %v", err)
+	}
+
+	r, err := New(Config{
+		AppName:            "app",
+		Agent:              ag,
+		SessionService:     session.InMemoryService(),
+		SessionIDGenerator: func() string { return "sess-1" },
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for _, err := range r.Run(context.Background(), "user", "", nil, agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	}
+	if sessionID != "sess-1" {
+		t.Errorf("session ID = %q, want sess-1", sessionID)
+	}
+
+	// Client-supplied IDs take precedence
+	for _, err := range r.Run(context.Background(), "user", "mine", nil, agent.RunConfig{}) {
+		if err != nil {
+			t.Fatalf("Run() error = %v", err)
+		}
+	}
+	if sessionID != "mine" {
+		t.Errorf("session ID = %q, want mine", sessionID)
+	}
+}
+
+// factsAgent is an agent with long-term memory.
+type factsAgent struct {
+	agent.Agent
+	ltm *memory.LongTermMemory
+}
+
+func (a *factsAgent) LongTermMemory() *memory.LongTermMemory { return a.ltm }
+
+// countingExtractor counts the turns it extracts facts from.
+type countingExtractor struct{ calls int }
+
+func (e *countingExtractor) ExtractFacts(context.Context, []*agent.Event, []string) ([]string, error) {
+	e.calls++
+	return nil, nil
+}
+
+func TestLongTermMemorySkipsAnonymousUser(t *testing.T) {
+	base, err := agent.New(agent.Config{
+		Name: "echo",
+		Run: func(ctx agent.InvocationContext) iter.Seq2[*agent.Event, error] {
+			return func(yield func(*agent.Event, error) bool) {}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	extractor := &countingExtractor{}
+	ltm, err := memory.NewLongTermMemory(memory.LongTermMemoryConfig{Extractor: extractor})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := New(Config{AppName: "app", Agent: &factsAgent{Agent: base, ltm: ltm}, SessionService: session.InMemoryService()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		userID string
+		calls  int
+	}{
+		{agent.AnonymousUserID, 0},
+		{"alice", 1},
+	} {
+		extractor.calls = 0
+		content := &agent.Content{Role: a2a.MessageRoleUser, Parts: []a2a.Part{a2a.TextPart{Text: "My name is Bob"}}}
+		for _, err := range r.Run(context.Background(), tt.userID, "", content, agent.RunConfig{}) {
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		if extractor.calls != tt.calls {
+			t.Errorf("user %q: extracted facts %d times, want %d", tt.userID, extractor.calls, tt.calls)
+		}
+	}
+}
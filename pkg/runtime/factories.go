@@ -16,6 +16,7 @@
 package runtime
 
 import (
+	"database/sql"
 	"fmt"
 	"time"
 
@@ -28,6 +29,8 @@ import (
 	"github.com/kadirpekel/hector/pkg/tool"
 	"github.com/kadirpekel/hector/pkg/tool/commandtool"
 	"github.com/kadirpekel/hector/pkg/tool/filetool"
+	"github.com/kadirpekel/hector/pkg/tool/pintool"
+	"github.com/kadirpekel/hector/pkg/tool/sqltool"
 	"github.com/kadirpekel/hector/pkg/tool/todotool"
 	"github.com/kadirpekel/hector/pkg/tool/webtool"
 )
@@ -149,11 +152,22 @@ func createFunctionToolset(name string, cfg *config.ToolConfig) (tool.Toolset, e
 		// Use defaults
 		t, err = webtool.NewWebRequest(nil)
 
+	case "http_request":
+		t, err = webtool.NewHTTPRequest(webtool.HTTPRequestConfig{
+			AllowedHosts:     cfg.AllowedHosts,
+			Timeout:          cfg.Timeout.Duration(),
+			MaxResponseBytes: cfg.MaxResponseBytes,
+		})
+
 	case "todo_write":
 		// TodoManager is stateless - create a new one for each toolset
 		todoManager := todotool.NewTodoManager()
 		t, err = todoManager.Tool()
 
+	case "pin_context":
+		// Pinned notes live in session state - no per-toolset state needed
+		t, err = pintool.New()
+
 	default:
 		return nil, fmt.Errorf("unknown function tool handler: %s", cfg.Handler)
 	}
@@ -172,6 +186,36 @@ func createFunctionToolset(name string, cfg *config.ToolConfig) (tool.Toolset, e
 	return &singleToolset{name: name, tool: t}, nil
 }
 
+// createSQLQueryToolset creates the sql_query tool. Connections come from
+// the shared database pool and are opened on first use.
+func createSQLQueryToolset(name string, cfg *config.ToolConfig, rootCfg *config.Config, pool *config.DBPool) (tool.Toolset, error) {
+	if pool == nil {
+		return nil, fmt.Errorf("DBPool is required for sql_query")
+	}
+
+	t, err := sqltool.NewSQLQuery(sqltool.SQLQueryConfig{
+		Databases: cfg.Databases,
+		Open: func(dbName string) (*sql.DB, error) {
+			dbCfg, ok := rootCfg.GetDatabase(dbName)
+			if !ok {
+				return nil, fmt.Errorf("database %q not found", dbName)
+			}
+			return pool.Get(dbCfg)
+		},
+		MaxRows:     cfg.MaxRows,
+		Timeout:     cfg.Timeout.Duration(),
+		AllowWrites: config.BoolValue(cfg.AllowWrites, false),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if config.BoolValue(cfg.RequireApproval, false) {
+		t = withApprovalRequired(t, cfg.ApprovalPrompt)
+	}
+	return &singleToolset{name: name, tool: t}, nil
+}
+
 // approvalRequiredTool wraps a CallableTool to return RequiresApproval() = true.
 // This is used for tools that need HITL (human-in-the-loop) approval before execution.
 // The actual HITL flow is handled by the agent flow, not the tool.
@@ -204,8 +248,8 @@ type WorkingMemoryFactoryOptions struct {
 	// ModelName is the LLM model name for token counting.
 	ModelName string
 
-	// SummarizerLLM is the LLM to use for summarization (summary_buffer only).
-	// If nil and strategy is summary_buffer, summarization is disabled.
+	// SummarizerLLM is the LLM to use for summarization (summary_buffer and
+	// summary_window only). If nil for those strategies, summarization is disabled.
 	SummarizerLLM model.LLM
 }
 
@@ -228,8 +272,8 @@ func DefaultWorkingMemoryFactory(opts WorkingMemoryFactoryOptions) (memory.Worki
 	// Use builder as foundation
 	b := builder.WorkingMemoryFromConfig(cfg).ModelName(opts.ModelName)
 
-	// Set summarizer LLM for summary_buffer strategy
-	if cfg.Strategy == "summary_buffer" && opts.SummarizerLLM != nil {
+	// Set summarizer LLM for summarization strategies
+	if (cfg.Strategy == "summary_buffer" || cfg.Strategy == "summary_window") && opts.SummarizerLLM != nil {
 		b = b.WithLLM(opts.SummarizerLLM)
 	}
 
@@ -0,0 +1,349 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtime
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/kadirpekel/hector/pkg/builder"
+	"github.com/kadirpekel/hector/pkg/config"
+	"github.com/kadirpekel/hector/pkg/tool/mcptoolset"
+)
+
+// BuildPlan describes what New would build from a config, without building
+// it. Entries are sorted by name; agents are listed in build order.
+type BuildPlan struct {
+	LLMs           []LLMPlan           `json:"llms"`
+	Embedders      []EmbedderPlan      `json:"embedders"`
+	VectorStores   []VectorStorePlan   `json:"vector_stores"`
+	Tools          []ToolPlan          `json:"tools"`
+	DocumentStores []DocumentStorePlan `json:"document_stores"`
+	Agents         []AgentPlan         `json:"agents"`
+}
+
+// LLMPlan describes an LLM provider.
+type LLMPlan struct {
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+
+	// Shadow is the LLM that requests are mirrored to, if any.
+	Shadow string `json:"shadow,omitempty"`
+}
+
+// EmbedderPlan describes an embedding provider.
+type EmbedderPlan struct {
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+}
+
+// VectorStorePlan describes a vector database provider.
+type VectorStorePlan struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// ToolPlan describes a configured toolset.
+type ToolPlan struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+
+	// Handler is the built-in function, for function tools.
+	Handler string `json:"handler,omitempty"`
+
+	// Server is the MCP server URL or command line, for MCP tools.
+	Server string `json:"server,omitempty"`
+
+	// Tools lists the exposed tool names when they are known from config.
+	Tools []string `json:"tools,omitempty"`
+
+	// Lazy is set for MCP toolsets without a filter: their tools are
+	// listed by the server on first use, which Plan does not connect to.
+	Lazy bool `json:"lazy,omitempty"`
+
+	// Disabled toolsets are not built and not given to any agent.
+	Disabled bool `json:"disabled,omitempty"`
+
+	RequiresApproval bool     `json:"requires_approval,omitempty"`
+	AllowedAgents    []string `json:"allowed_agents,omitempty"`
+}
+
+// DocumentStorePlan describes a document store and what it is built on.
+type DocumentStorePlan struct {
+	Name        string `json:"name"`
+	Source      string `json:"source"`
+	VectorStore string `json:"vector_store,omitempty"`
+	Embedder    string `json:"embedder,omitempty"`
+	Watch       bool   `json:"watch,omitempty"`
+}
+
+// AgentPlan describes an agent and how it is wired.
+type AgentPlan struct {
+	Name string `json:"name"`
+
+	// Type is "llm", "remote" or a workflow type such as "sequential".
+	Type string `json:"type"`
+
+	LLM string `json:"llm,omitempty"`
+
+	// URL is the remote A2A server, for remote agents.
+	URL string `json:"url,omitempty"`
+
+	// Tools are the toolsets the agent gets after resolution, including
+	// single tools picked from MCP toolsets and allowed_agents filtering.
+	Tools []AgentToolPlan `json:"tools,omitempty"`
+
+	// DocumentStores are the stores the agent can search.
+	DocumentStores []string `json:"document_stores,omitempty"`
+
+	SubAgents  []string `json:"sub_agents,omitempty"`
+	AgentTools []string `json:"agent_tools,omitempty"`
+}
+
+// AgentToolPlan is a toolset given to an agent. Tool is set when the agent
+// listed a single tool that resolved through an MCP toolset.
+type AgentToolPlan struct {
+	Toolset string `json:"toolset"`
+	Tool    string `json:"tool,omitempty"`
+}
+
+// Plan resolves the dependency graph of a loaded config the way New does
+// and describes the result. It makes no network calls: MCP servers are
+// not contacted, and toolsets that list their tools on first use are
+// reported as lazy. Errors match the ones New would return for the same
+// wiring problems (unknown LLM, tool or agent references, cycles).
+func Plan(cfg *config.Config) (*BuildPlan, error) {
+	p := &BuildPlan{}
+
+	for _, name := range sortedKeys(cfg.LLMs) {
+		llmCfg := cfg.LLMs[name]
+		p.LLMs = append(p.LLMs, LLMPlan{
+			Name:     name,
+			Provider: string(llmCfg.Provider),
+			Model:    llmCfg.Model,
+			Shadow:   llmCfg.Shadow,
+		})
+	}
+	for _, name := range sortedKeys(cfg.Embedders) {
+		embCfg := cfg.Embedders[name]
+		p.Embedders = append(p.Embedders, EmbedderPlan{Name: name, Provider: embCfg.Provider, Model: embCfg.Model})
+	}
+	for _, name := range sortedKeys(cfg.VectorStores) {
+		p.VectorStores = append(p.VectorStores, VectorStorePlan{Name: name, Type: cfg.VectorStores[name].Type})
+	}
+
+	mcpToolsets := make(map[string]*mcptoolset.Toolset)
+	for _, name := range sortedKeys(cfg.Tools) {
+		toolCfg := cfg.Tools[name]
+		tp := ToolPlan{
+			Name:             name,
+			Type:             string(toolCfg.Type),
+			Disabled:         !toolCfg.IsEnabled(),
+			RequiresApproval: toolCfg.NeedsApproval(),
+			AllowedAgents:    toolCfg.AllowedAgents,
+		}
+		switch toolCfg.Type {
+		case config.ToolTypeFunction:
+			tp.Handler = toolCfg.Handler
+		case config.ToolTypeMCP:
+			tp.Server = toolCfg.URL
+			if tp.Server == "" {
+				tp.Server = strings.TrimSpace(toolCfg.Command + " " + strings.Join(toolCfg.Args, " "))
+			}
+			for _, serverName := range toolCfg.Filter {
+				if !slices.Contains(toolCfg.Exclude, serverName) {
+					tp.Tools = append(tp.Tools, toolCfg.Prefix+serverName)
+				}
+			}
+			tp.Lazy = len(toolCfg.Filter) == 0
+			if !tp.Disabled {
+				// Building an MCP toolset does not connect to the server
+				ts, err := builder.MCPFromConfig(name, toolCfg).Build()
+				if err != nil {
+					return nil, fmt.Errorf("tool %q: %w", name, err)
+				}
+				mcpToolsets[name] = ts
+			}
+		}
+		p.Tools = append(p.Tools, tp)
+	}
+
+	for _, name := range sortedKeys(cfg.DocumentStores) {
+		dsCfg := cfg.DocumentStores[name]
+		dp := DocumentStorePlan{Name: name, VectorStore: dsCfg.VectorStore, Embedder: dsCfg.Embedder, Watch: dsCfg.Watch}
+		if dsCfg.Source != nil {
+			dp.Source = dsCfg.Source.Type
+		}
+		p.DocumentStores = append(p.DocumentStores, dp)
+	}
+
+	agents, err := planAgents(cfg, mcpToolsets)
+	if err != nil {
+		return nil, err
+	}
+	p.Agents = agents
+
+	return p, nil
+}
+
+// planAgents orders agents the way buildAgents creates them (workflow
+// agents after their sub-agents) and resolves each agent's wiring.
+func planAgents(cfg *config.Config, mcpToolsets map[string]*mcptoolset.Toolset) ([]AgentPlan, error) {
+	pending := sortedKeys(cfg.Agents)
+	built := make(map[string]bool, len(pending))
+	var plans []AgentPlan
+
+	for len(pending) > 0 {
+		var nextPending []string
+		for _, name := range pending {
+			agentCfg := cfg.Agents[name]
+			if isWorkflowAgentType(agentCfg.Type) && !allBuilt(agentCfg.SubAgents, built) {
+				nextPending = append(nextPending, name)
+				continue
+			}
+
+			ap, err := planAgent(cfg, name, agentCfg, mcpToolsets)
+			if err != nil {
+				return nil, err
+			}
+			plans = append(plans, ap)
+			built[name] = true
+		}
+
+		if len(nextPending) == len(pending) {
+			return nil, fmt.Errorf("failed to build agents: dependency cycle or missing dependencies for: %v", nextPending)
+		}
+		pending = nextPending
+	}
+
+	// Config-based multi-agent links must point at existing agents
+	for _, ap := range plans {
+		if isWorkflowAgentType(ap.Type) {
+			continue
+		}
+		for _, subName := range ap.SubAgents {
+			if !built[subName] {
+				return nil, fmt.Errorf("agent %q: sub_agent %q not found", ap.Name, subName)
+			}
+		}
+		for _, agToolName := range ap.AgentTools {
+			if !built[agToolName] {
+				return nil, fmt.Errorf("agent %q: agent_tool %q not found", ap.Name, agToolName)
+			}
+		}
+	}
+
+	return plans, nil
+}
+
+func planAgent(cfg *config.Config, name string, agentCfg *config.AgentConfig, mcpToolsets map[string]*mcptoolset.Toolset) (AgentPlan, error) {
+	ap := AgentPlan{
+		Name:       name,
+		Type:       agentCfg.Type,
+		SubAgents:  agentCfg.SubAgents,
+		AgentTools: agentCfg.AgentTools,
+	}
+
+	switch {
+	case isWorkflowAgentType(agentCfg.Type):
+		return ap, nil
+	case isRemoteAgentType(agentCfg.Type):
+		ap.URL = agentCfg.URL
+		return ap, nil
+	}
+
+	if ap.Type == "" {
+		ap.Type = "llm"
+	}
+	if _, ok := cfg.LLMs[agentCfg.LLM]; !ok {
+		return ap, fmt.Errorf("agent %q: llm %q not found", name, agentCfg.LLM)
+	}
+	ap.LLM = agentCfg.LLM
+
+	// nil/omitted = all enabled toolsets, [] = none, [...] = scoped
+	var tools []AgentToolPlan
+	if agentCfg.Tools == nil {
+		for _, toolName := range sortedKeys(cfg.Tools) {
+			if cfg.Tools[toolName].IsEnabled() {
+				tools = append(tools, AgentToolPlan{Toolset: toolName})
+			}
+		}
+	} else {
+		for _, toolName := range agentCfg.Tools {
+			tp, err := planToolRef(cfg, toolName, mcpToolsets)
+			if err != nil {
+				return ap, fmt.Errorf("agent %q: %w", name, err)
+			}
+			tools = append(tools, tp)
+		}
+	}
+	for _, tp := range tools {
+		if cfg.Tools[tp.Toolset].AllowsAgent(name) {
+			ap.Tools = append(ap.Tools, tp)
+		}
+	}
+
+	// Search access: nil = all stores, [] = none, [...] = the listed ones that exist
+	if agentCfg.DocumentStores == nil {
+		ap.DocumentStores = sortedKeys(cfg.DocumentStores)
+	} else {
+		for _, storeName := range *agentCfg.DocumentStores {
+			if _, ok := cfg.DocumentStores[storeName]; ok {
+				ap.DocumentStores = append(ap.DocumentStores, storeName)
+			}
+		}
+	}
+
+	return ap, nil
+}
+
+// planToolRef resolves a tool name listed by an agent like resolveToolset.
+func planToolRef(cfg *config.Config, toolName string, mcpToolsets map[string]*mcptoolset.Toolset) (AgentToolPlan, error) {
+	if toolCfg, ok := cfg.Tools[toolName]; ok && toolCfg.IsEnabled() {
+		return AgentToolPlan{Toolset: toolName}, nil
+	}
+	for _, tsName := range sortedKeys(mcpToolsets) {
+		if mcpToolsets[tsName].Exposes(toolName) {
+			return AgentToolPlan{Toolset: tsName, Tool: toolName}, nil
+		}
+	}
+	return AgentToolPlan{}, fmt.Errorf("tool %q not found", toolName)
+}
+
+func allBuilt(names []string, built map[string]bool) bool {
+	for _, name := range names {
+		if !built[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedKeys returns the keys of a config section with non-nil entries, sorted.
+func sortedKeys[T any](m map[string]*T) []string {
+	keys := make([]string, 0, len(m))
+	for k, v := range m {
+		if v != nil {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
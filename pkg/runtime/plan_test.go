@@ -0,0 +1,117 @@
+package runtime
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kadirpekel/hector/pkg/config"
+)
+
+func planTestConfig() *config.Config {
+	disabled := false
+	return &config.Config{
+		LLMs: map[string]*config.LLMConfig{
+			"default": {Provider: "openai", Model: "gpt-4o"},
+		},
+		Tools: map[string]*config.ToolConfig{
+			"gh":      {Type: config.ToolTypeMCP, URL: "http://localhost:9/mcp"},
+			"fs":      {Type: config.ToolTypeMCP, Transport: "stdio", Command: "fs-server", Filter: []string{"read_file", "write_file"}, Exclude: []string{"write_file"}},
+			"off":     {Type: config.ToolTypeFunction, Handler: "web_request", Enabled: &disabled},
+			"private": {Type: config.ToolTypeFunction, Handler: "grep_search", AllowedAgents: []string{"b"}},
+		},
+		Agents: map[string]*config.AgentConfig{
+			"a":    {LLM: "default", Tools: []string{"gh", "create_issue", "private"}},
+			"b":    {LLM: "default"},
+			"pipe": {Type: "sequential", SubAgents: []string{"a", "b"}},
+		},
+	}
+}
+
+func TestPlan(t *testing.T) {
+	plan, err := Plan(planTestConfig())
+	if err != nil {
+		t.Fatalf("Plan: %v", err)
+	}
+
+	tools := make(map[string]ToolPlan)
+	for _, tp := range plan.Tools {
+		tools[tp.Name] = tp
+	}
+	if !tools["gh"].Lazy {
+		t.Error("MCP toolset without a filter should be lazy")
+	}
+	if fs := tools["fs"]; fs.Lazy || !reflect.DeepEqual(fs.Tools, []string{"read_file"}) {
+		t.Errorf("fs = %+v, want filtered tools [read_file]", fs)
+	}
+	if !tools["off"].Disabled {
+		t.Error("off should be disabled")
+	}
+
+	var order []string
+	agents := make(map[string]AgentPlan)
+	for _, ap := range plan.Agents {
+		order = append(order, ap.Name)
+		agents[ap.Name] = ap
+	}
+	if got := strings.Join(order, ","); got != "a,b,pipe" {
+		t.Errorf("build order = %s, want a,b,pipe", got)
+	}
+
+	// Listed tools resolve implicitly through MCP; allowed_agents filters.
+	wantA := []AgentToolPlan{{Toolset: "gh"}, {Toolset: "gh", Tool: "create_issue"}}
+	if !reflect.DeepEqual(agents["a"].Tools, wantA) {
+		t.Errorf("a tools = %+v, want %+v", agents["a"].Tools, wantA)
+	}
+	// Omitted tools mean all enabled toolsets.
+	wantB := []AgentToolPlan{{Toolset: "fs"}, {Toolset: "gh"}, {Toolset: "private"}}
+	if !reflect.DeepEqual(agents["b"].Tools, wantB) {
+		t.Errorf("b tools = %+v, want %+v", agents["b"].Tools, wantB)
+	}
+}
+
+func TestPlanErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(cfg *config.Config)
+		wantErr string
+	}{
+		{
+			name:    "unknown llm",
+			mutate:  func(cfg *config.Config) { cfg.Agents["b"].LLM = "missing" },
+			wantErr: `agent "b": llm "missing" not found`,
+		},
+		{
+			name: "disabled tool listed",
+			mutate: func(cfg *config.Config) {
+				cfg.Agents["b"].Tools = []string{"off"}
+				cfg.Tools["gh"].Filter = []string{"create_issue"}
+			},
+			wantErr: `agent "b": tool "off" not found`,
+		},
+		{
+			name: "workflow cycle",
+			mutate: func(cfg *config.Config) {
+				cfg.Agents["loop"] = &config.AgentConfig{Type: "loop", SubAgents: []string{"pipe2"}}
+				cfg.Agents["pipe2"] = &config.AgentConfig{Type: "sequential", SubAgents: []string{"loop"}}
+			},
+			wantErr: "dependency cycle",
+		},
+		{
+			name:    "unknown agent tool",
+			mutate:  func(cfg *config.Config) { cfg.Agents["b"].AgentTools = []string{"ghost"} },
+			wantErr: `agent_tool "ghost" not found`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := planTestConfig()
+			tt.mutate(cfg)
+			_, err := Plan(cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
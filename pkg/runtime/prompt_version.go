@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runtime
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/config"
+)
+
+// promptHashLength is the number of hex digits kept of an instruction hash.
+const promptHashLength = 12
+
+// promptVersions numbers the instructions of each agent across reloads.
+// Numbering starts over when the process restarts; the hash identifies an
+// instruction across restarts.
+type promptVersions struct {
+	mu     sync.Mutex
+	agents map[string]promptRevision
+}
+
+// promptRevision is the current instruction of an agent.
+type promptRevision struct {
+	hash   string
+	number int
+}
+
+func newPromptVersions() *promptVersions {
+	return &promptVersions{agents: make(map[string]promptRevision)}
+}
+
+// resolve returns the prompt version of an agent's instruction, or nil when
+// versioning is disabled. With config.PromptVersionAuto, the version moves
+// to the next number whenever the instruction hash changes.
+func (p *promptVersions) resolve(agentName, label, instruction string) *agent.PromptVersion {
+	if label == "" {
+		return nil
+	}
+
+	sum := sha256.Sum256([]byte(instruction))
+	hash := hex.EncodeToString(sum[:])[:promptHashLength]
+	if label != config.PromptVersionAuto {
+		return &agent.PromptVersion{Version: label, Hash: hash}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	rev := p.agents[agentName]
+	if rev.hash != hash {
+		rev = promptRevision{hash: hash, number: rev.number + 1}
+		p.agents[agentName] = rev
+		if rev.number > 1 {
+			slog.Info("Agent instruction changed", "agent", agentName, "version", rev.number, "hash", hash)
+		}
+	}
+	return &agent.PromptVersion{Version: fmt.Sprintf("v%d", rev.number), Hash: hash}
+}
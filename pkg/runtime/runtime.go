@@ -23,6 +23,8 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -39,6 +41,7 @@ import (
 	"github.com/kadirpekel/hector/pkg/memory"
 	"github.com/kadirpekel/hector/pkg/model"
 	"github.com/kadirpekel/hector/pkg/observability"
+	"github.com/kadirpekel/hector/pkg/observability/langfuse"
 	"github.com/kadirpekel/hector/pkg/rag"
 	"github.com/kadirpekel/hector/pkg/runner"
 	"github.com/kadirpekel/hector/pkg/session"
@@ -63,6 +66,8 @@ type Runtime struct {
 	checkpoint    *checkpoint.Manager    // Checkpoint/recovery manager
 	dbPool        *config.DBPool         // Shared database pool for SQL backends
 	observability *observability.Manager // Tracing and metrics
+	langfuse      *langfuse.Client       // Langfuse export, if enabled
+	sweeper       *session.Sweeper       // Deletes expired sessions, if a TTL is set
 
 	// RAG/Document Store components
 	vectorProviders map[string]vector.Provider    // Vector database providers
@@ -77,6 +82,9 @@ type Runtime struct {
 	subAgents   map[string][]agent.Agent // Sub-agents per agent name (Pattern 1: transfer)
 	agentTools  map[string][]agent.Agent // Agents as tools per agent name (Pattern 2: delegation)
 	directTools map[string][]tool.Tool   // Direct tools per agent name
+
+	// Instruction versions per agent, kept across reloads
+	promptVersions *promptVersions
 }
 
 // LLMFactory creates an LLM from config.
@@ -193,6 +201,7 @@ func New(cfg *config.Config, opts ...Option) (*Runtime, error) {
 		llmFactory:      DefaultLLMFactory,
 		embedderFactory: DefaultEmbedderFactory,
 		toolsetFactory:  DefaultToolsetFactory,
+		promptVersions:  newPromptVersions(),
 	}
 
 	for _, opt := range opts {
@@ -208,6 +217,29 @@ func New(cfg *config.Config, opts ...Option) (*Runtime, error) {
 		r.observability = obs
 	}
 
+	// Export LLM and tool call payloads to Langfuse
+	if obs := cfg.Server.Observability; obs != nil && obs.Langfuse.IsEnabled() {
+		lf, err := langfuse.New(langfuse.Config{
+			Host:          obs.Langfuse.Host,
+			PublicKey:     obs.Langfuse.PublicKey,
+			SecretKey:     obs.Langfuse.SecretKey,
+			RedactKeys:    obs.Langfuse.RedactKeys,
+			FlushInterval: obs.Langfuse.FlushInterval,
+			BatchSize:     obs.Langfuse.BatchSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize langfuse: %w", err)
+		}
+		r.langfuse = lf
+	}
+
+	// Export connection pool statistics of the shared database pool
+	if r.dbPool != nil && r.observability != nil {
+		if err := r.observability.Metrics().RegisterDBStats(r.dbPool.Stats); err != nil {
+			slog.Warn("Failed to register database pool metrics", "error", err)
+		}
+	}
+
 	// Create session service from config if not provided
 	if r.sessions == nil {
 		sessionSvc, err := session.NewSessionServiceFromConfig(cfg, r.dbPool)
@@ -217,6 +249,19 @@ func New(cfg *config.Config, opts ...Option) (*Runtime, error) {
 		r.sessions = sessionSvc
 	}
 
+	// Delete sessions idle longer than the configured TTL
+	if sc := cfg.Server.Sessions; sc != nil && sc.TTL > 0 {
+		sweeper, err := session.NewSweeper(r.sessions, session.SweeperConfig{
+			TTL:      sc.TTL.Duration(),
+			Interval: sc.SweepInterval.Duration(),
+			OnSweep:  r.observability.Metrics().RecordSessionsExpired,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create session sweeper: %w", err)
+		}
+		r.sweeper = sweeper
+	}
+
 	// Create checkpoint manager if configured and not provided
 	if r.checkpoint == nil && cfg.Server.Checkpoint != nil {
 		cpCfg := &checkpoint.Config{
@@ -283,6 +328,10 @@ func New(cfg *config.Config, opts ...Option) (*Runtime, error) {
 		return nil, fmt.Errorf("failed to build agents: %w", err)
 	}
 
+	if r.sweeper != nil {
+		r.sweeper.Start()
+	}
+
 	return r, nil
 }
 
@@ -302,9 +351,135 @@ func (r *Runtime) buildLLMs() error {
 		slog.Debug("Created LLM", "name", name, "provider", cfg.Provider, "model", cfg.Model)
 	}
 
+	r.applyShadowLLMs(r.cfg, r.llms)
+	applyDedupLLMs(r.cfg, r.llms)
+	r.applyLLMPricing(r.cfg)
+	return r.applyCachingLLMs(r.cfg, r.llms)
+}
+
+// applyLLMPricing prices token usage for the LLM cost metric using the
+// pricing of the configured LLMs.
+func (r *Runtime) applyLLMPricing(cfg *config.Config) {
+	if r.observability == nil {
+		return
+	}
+	pricing := cfg.ModelPricing()
+	r.observability.Metrics().SetLLMCostFunc(func(model string, inputTokens, outputTokens int) float64 {
+		return pricing[model].Cost(inputTokens, outputTokens)
+	})
+}
+
+// applyShadowLLMs wraps LLMs that configure a shadow so their requests are
+// mirrored to the shadow LLM. Shadows always receive the unwrapped LLM.
+func (r *Runtime) applyShadowLLMs(cfg *config.Config, llms map[string]model.LLM) {
+	base := maps.Clone(llms)
+	for name, llmCfg := range cfg.LLMs {
+		if llmCfg == nil || llmCfg.Shadow == "" {
+			continue
+		}
+		primary, ok := base[name]
+		shadow, shadowOK := base[llmCfg.Shadow]
+		if !ok || !shadowOK {
+			continue
+		}
+
+		shadowCfg := model.ShadowConfig{Similarity: llmCfg.ShadowSimilarity}
+		if r.observability != nil {
+			if m := r.observability.Metrics(); m != nil {
+				shadowCfg.Metrics = m
+			}
+		}
+		llms[name] = model.NewShadowLLM(primary, shadow, shadowCfg)
+		slog.Info("Shadowing LLM", "llm", name, "shadow", llmCfg.Shadow)
+	}
+}
+
+// applyDedupLLMs wraps LLMs that enable deduplication. It runs after
+// applyShadowLLMs so a shared call is mirrored to the shadow only once.
+func applyDedupLLMs(cfg *config.Config, llms map[string]model.LLM) {
+	for name, llmCfg := range cfg.LLMs {
+		if llmCfg == nil || !llmCfg.Deduplicate {
+			continue
+		}
+		if llm, ok := llms[name]; ok {
+			llms[name] = model.NewDedupLLM(llm)
+		}
+	}
+}
+
+// applyCachingLLMs wraps every LLM with the response cache if llm_cache is
+// enabled. It runs last so cache hits skip shadowing and deduplication.
+func (r *Runtime) applyCachingLLMs(cfg *config.Config, llms map[string]model.LLM) error {
+	cacheCfg := cfg.LLMCache
+	if !cacheCfg.IsEnabled() {
+		return nil
+	}
+
+	var cache model.ResponseCache
+	switch cacheCfg.Backend {
+	case "sql":
+		if r.dbPool == nil {
+			return fmt.Errorf("llm_cache: DBPool is required for SQL backend")
+		}
+		dbCfg, ok := cfg.GetDatabase(cacheCfg.SQLDatabase)
+		if !ok {
+			return fmt.Errorf("llm_cache: database %q not found", cacheCfg.SQLDatabase)
+		}
+		db, err := r.dbPool.Get(dbCfg)
+		if err != nil {
+			return fmt.Errorf("llm_cache: failed to get database connection: %w", err)
+		}
+		sqlCache, err := model.NewSQLResponseCache(db, dbCfg.Dialect())
+		if err != nil {
+			return fmt.Errorf("llm_cache: %w", err)
+		}
+		if ttl := cacheCfg.TTL.Duration(); ttl > 0 {
+			sqlCache.StartPruning(min(max(ttl, time.Minute), time.Hour))
+		}
+		cache = sqlCache
+	case "memory", "":
+		cache = model.NewMemoryResponseCache(cacheCfg.MaxEntries)
+	default:
+		return fmt.Errorf("llm_cache: unsupported backend: %s", cacheCfg.Backend)
+	}
+
+	var metrics model.CacheMetrics
+	if r.observability != nil {
+		if m := r.observability.Metrics(); m != nil {
+			metrics = m
+		}
+	}
+
+	for name, llm := range llms {
+		llms[name] = model.NewCachingLLM(llm, model.CacheConfig{
+			Cache:        cache,
+			TTL:          cacheCfg.TTL.Duration(),
+			ReplayStream: cacheCfg.ReplayStream,
+			Scope:        llmCacheScope(cfg.LLMs[name]),
+			Metrics:      metrics,
+		})
+	}
+	slog.Info("Caching LLM responses", "backend", cacheCfg.Backend, "llms", len(llms))
 	return nil
 }
 
+// llmCacheScope describes the LLM settings that change its output but are
+// not part of the requests, so responses cached under other settings are not
+// served.
+func llmCacheScope(cfg *config.LLMConfig) string {
+	if cfg == nil {
+		return ""
+	}
+	scope := fmt.Sprintf("max_tokens=%d", cfg.MaxTokens)
+	if cfg.Temperature != nil {
+		scope += fmt.Sprintf(" temperature=%g", *cfg.Temperature)
+	}
+	if cfg.Thinking != nil && config.BoolValue(cfg.Thinking.Enabled, false) {
+		scope += fmt.Sprintf(" thinking=%d", cfg.Thinking.BudgetTokens)
+	}
+	return scope
+}
+
 // buildEmbedders creates Embedder instances from config.
 func (r *Runtime) buildEmbedders() error {
 	for name, cfg := range r.cfg.Embedders {
@@ -326,12 +501,18 @@ func (r *Runtime) buildEmbedders() error {
 
 // buildVectorProviders creates vector provider instances from config.
 func (r *Runtime) buildVectorProviders() error {
+	// pgvector stores share connections from the database pool
+	deps := &rag.FactoryDeps{
+		DBPool: r.dbPool,
+		Config: r.cfg,
+	}
+
 	for name, cfg := range r.cfg.VectorStores {
 		if cfg == nil {
 			continue
 		}
 
-		provider, err := rag.NewVectorProviderFromConfig(cfg)
+		provider, err := rag.NewVectorProviderFromConfig(cfg, deps)
 		if err != nil {
 			return fmt.Errorf("vector_store %q: %w", name, err)
 		}
@@ -394,7 +575,15 @@ func (r *Runtime) buildToolsets() error {
 			continue
 		}
 
-		ts, err := r.toolsetFactory(name, cfg)
+		var ts tool.Toolset
+		var err error
+		if cfg.Type == config.ToolTypeFunction && cfg.Handler == "sql_query" {
+			// sql_query needs the runtime's databases, which toolset
+			// factories do not see
+			ts, err = createSQLQueryToolset(name, cfg, r.cfg, r.dbPool)
+		} else {
+			ts, err = r.toolsetFactory(name, cfg)
+		}
 		if err != nil {
 			return fmt.Errorf("tool %q: %w", name, err)
 		}
@@ -638,20 +827,8 @@ func (r *Runtime) resolveToolset(toolName string) (tool.Toolset, error) {
 				continue
 			}
 
-			allowed := false
-			if len(toolCfg.Filter) > 0 {
-				for _, allowedTool := range toolCfg.Filter {
-					if allowedTool == toolName {
-						allowed = true
-						break
-					}
-				}
-			} else {
-				// No filter = promiscuous (allows any tool)
-				allowed = true
-			}
-
-			if allowed {
+			// Without a filter any tool name (with the prefix) is allowed
+			if mcpTS.Exposes(toolName) {
 				return mcpTS.WithFilter([]string{toolName}), nil
 			}
 		}
@@ -663,7 +840,7 @@ func (r *Runtime) resolveToolset(toolName string) (tool.Toolset, error) {
 // isWorkflowAgentType returns true if the type is a workflow agent type.
 func isWorkflowAgentType(t string) bool {
 	switch t {
-	case "sequential", "parallel", "loop":
+	case "sequential", "parallel", "loop", "conditional", "map_reduce":
 		return true
 	default:
 		return false
@@ -677,18 +854,34 @@ func isRemoteAgentType(t string) bool {
 
 // createWorkflowAgent creates a workflow agent from config.
 func (r *Runtime) createWorkflowAgent(name string, cfg *config.AgentConfig, subAgents []agent.Agent) (agent.Agent, error) {
+	var retry *workflowagent.RetryConfig
+	if cfg.Retry != nil {
+		retry = &workflowagent.RetryConfig{
+			MaxRetries: cfg.Retry.Retries(),
+			BaseDelay:  cfg.Retry.BaseDelay.Duration(),
+			MaxDelay:   cfg.Retry.MaxDelay.Duration(),
+			Jitter:     cfg.Retry.Jitter,
+		}
+	}
+
+	timeout := cfg.RunTimeout()
+
 	switch cfg.Type {
 	case "sequential":
 		return workflowagent.NewSequential(workflowagent.SequentialConfig{
 			Name:        name,
 			Description: cfg.Description,
 			SubAgents:   subAgents,
+			Retry:       retry,
+			Timeout:     timeout,
 		})
 	case "parallel":
 		return workflowagent.NewParallel(workflowagent.ParallelConfig{
 			Name:        name,
 			Description: cfg.Description,
 			SubAgents:   subAgents,
+			Retry:       retry,
+			Timeout:     timeout,
 		})
 	case "loop":
 		return workflowagent.NewLoop(workflowagent.LoopConfig{
@@ -696,7 +889,67 @@ func (r *Runtime) createWorkflowAgent(name string, cfg *config.AgentConfig, subA
 			Description:   cfg.Description,
 			SubAgents:     subAgents,
 			MaxIterations: cfg.MaxIterations,
+			Retry:         retry,
+			Timeout:       timeout,
 		})
+	case "conditional":
+		byName := make(map[string]agent.Agent, len(subAgents))
+		for _, sub := range subAgents {
+			byName[sub.Name()] = sub
+		}
+		resolve := func(subName string) (agent.Agent, error) {
+			if sub, ok := byName[subName]; ok {
+				return sub, nil
+			}
+			return nil, fmt.Errorf("agent %q is not a sub-agent of %q", subName, name)
+		}
+
+		condCfg := workflowagent.ConditionalConfig{
+			Name:        name,
+			Description: cfg.Description,
+			Retry:       retry,
+			Timeout:     timeout,
+		}
+		for _, c := range cfg.Cases {
+			sub, err := resolve(c.Agent)
+			if err != nil {
+				return nil, err
+			}
+			condCfg.Cases = append(condCfg.Cases, workflowagent.ConditionalCase{When: c.When, Agent: sub})
+		}
+		if cfg.Default != "" {
+			sub, err := resolve(cfg.Default)
+			if err != nil {
+				return nil, err
+			}
+			condCfg.Default = sub
+		}
+		return workflowagent.NewConditional(condCfg)
+	case "map_reduce":
+		mrCfg := workflowagent.MapReduceConfig{
+			Name:           name,
+			Description:    cfg.Description,
+			InputKey:       cfg.InputKey,
+			OutputKey:      cfg.OutputKey,
+			MaxConcurrency: cfg.MaxConcurrency,
+			Retry:          retry,
+			Timeout:        timeout,
+		}
+		for _, sub := range subAgents {
+			if sub.Name() == cfg.Mapper {
+				mrCfg.Mapper = sub
+			}
+			if cfg.Reducer != "" && sub.Name() == cfg.Reducer {
+				mrCfg.Reducer = sub
+			}
+		}
+		if mrCfg.Mapper == nil {
+			return nil, fmt.Errorf("agent %q is not a sub-agent of %q", cfg.Mapper, name)
+		}
+		if cfg.Reducer != "" && mrCfg.Reducer == nil {
+			return nil, fmt.Errorf("agent %q is not a sub-agent of %q", cfg.Reducer, name)
+		}
+		return workflowagent.NewMapReduce(mrCfg)
 	default:
 		return nil, fmt.Errorf("unknown workflow agent type: %s", cfg.Type)
 	}
@@ -722,18 +975,47 @@ func (r *Runtime) createRemoteAgent(name string, cfg *config.AgentConfig) (agent
 		}
 	}
 
-	return remoteagent.NewA2A(remoteagent.Config{
+	remoteCfg := remoteagent.Config{
 		Name:            name,
 		Description:     cfg.Description,
 		URL:             cfg.URL,
 		AgentCardSource: agentCardSource,
 		Headers:         cfg.Headers,
 		Timeout:         timeout,
-	})
+	}
+	if cfg.Retry != nil {
+		remoteCfg.Retry = &remoteagent.RetryConfig{
+			MaxAttempts: cfg.Retry.Retries() + 1,
+			BaseDelay:   cfg.Retry.BaseDelay.Duration(),
+			Multiplier:  cfg.Retry.Multiplier,
+			MaxDelay:    cfg.Retry.MaxDelay.Duration(),
+			RetryOn:     cfg.Retry.RetryOn,
+		}
+	}
+
+	if cb := cfg.CircuitBreaker; cb != nil {
+		remoteCfg.CircuitBreaker = &remoteagent.CircuitBreakerConfig{
+			FailureThreshold: cb.FailureThreshold,
+			Cooldown:         cb.Cooldown.Duration(),
+			HalfOpenMax:      cb.HalfOpenMax,
+		}
+	}
+
+	return remoteagent.NewA2A(remoteCfg)
 }
 
 // createLLMAgent creates an LLM agent from config.
 func (r *Runtime) createLLMAgent(name string, cfg *config.AgentConfig, llm model.LLM, toolsets []tool.Toolset) (agent.Agent, error) {
+	// Drop toolsets that do not allow this agent
+	toolsets = slices.DeleteFunc(slices.Clone(toolsets), func(ts tool.Toolset) bool {
+		toolCfg, ok := r.cfg.Tools[ts.Name()]
+		if !ok || toolCfg == nil || toolCfg.AllowsAgent(name) {
+			return false
+		}
+		slog.Debug("Tool not allowed for agent", "tool", ts.Name(), "agent", name)
+		return true
+	})
+
 	// Collect direct tools (injected via WithTool/WithTools)
 	var tools []tool.Tool
 	if directTools, ok := r.directTools[name]; ok {
@@ -767,6 +1049,8 @@ func (r *Runtime) createLLMAgent(name string, cfg *config.AgentConfig, llm model
 			EnableExitTool:        config.BoolValue(cfg.Reasoning.EnableExitTool, false),
 			EnableEscalateTool:    config.BoolValue(cfg.Reasoning.EnableEscalateTool, false),
 			CompletionInstruction: cfg.Reasoning.CompletionInstruction,
+			RetryOnEmpty:          config.BoolValue(cfg.Reasoning.RetryOnEmpty, false),
+			EmptyRetryInstruction: cfg.Reasoning.EmptyRetryInstruction,
 		}
 	}
 
@@ -775,7 +1059,8 @@ func (r *Runtime) createLLMAgent(name string, cfg *config.AgentConfig, llm model
 	if llmCfg, ok := r.cfg.LLMs[cfg.LLM]; ok && llmCfg != nil {
 		if llmCfg.Thinking != nil && config.BoolValue(llmCfg.Thinking.Enabled, false) {
 			generateConfig = &model.GenerateConfig{
-				EnableThinking: true,
+				EnableThinking:   true,
+				ReasoningSummary: llmCfg.Thinking.Summary,
 			}
 			if llmCfg.Thinking.BudgetTokens > 0 {
 				generateConfig.ThinkingBudget = llmCfg.Thinking.BudgetTokens
@@ -807,9 +1092,9 @@ func (r *Runtime) createLLMAgent(name string, cfg *config.AgentConfig, llm model
 			modelName = llmCfg.Model
 		}
 
-		// Resolve summarizer LLM for summary_buffer strategy
+		// Resolve summarizer LLM for summarization strategies
 		var summarizerLLM model.LLM
-		if cfg.Context.Strategy == "summary_buffer" {
+		if cfg.Context.Strategy == "summary_buffer" || cfg.Context.Strategy == "summary_window" {
 			if cfg.Context.SummarizerLLM != "" {
 				// Use explicitly configured summarizer LLM
 				summarizerLLM = r.llms[cfg.Context.SummarizerLLM]
@@ -835,6 +1120,30 @@ func (r *Runtime) createLLMAgent(name string, cfg *config.AgentConfig, llm model
 		}
 	}
 
+	// Build long-term memory of user facts if enabled
+	var longTermMemory *memory.LongTermMemory
+	if cfg.Memory.IsLongTermEnabled() {
+		ltmCfg := cfg.Memory.LongTerm
+		extractorLLM := llm
+		if ltmCfg.ExtractorLLM != "" {
+			extractorLLM = r.llms[ltmCfg.ExtractorLLM]
+			if extractorLLM == nil {
+				return nil, fmt.Errorf("extractor llm %q not found for long-term memory", ltmCfg.ExtractorLLM)
+			}
+		}
+		extractor, err := memory.NewLLMFactExtractor(memory.LLMFactExtractorConfig{LLM: extractorLLM})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create fact extractor: %w", err)
+		}
+		longTermMemory, err = memory.NewLongTermMemory(memory.LongTermMemoryConfig{
+			Extractor: extractor,
+			MaxFacts:  ltmCfg.MaxFacts,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create long-term memory: %w", err)
+		}
+	}
+
 	// Build RAG context provider if IncludeContext is enabled
 	var contextProvider llmagent.ContextProvider
 	if config.BoolValue(cfg.IncludeContext, false) {
@@ -844,29 +1153,164 @@ func (r *Runtime) createLLMAgent(name string, cfg *config.AgentConfig, llm model
 		}
 	}
 
+	// Chain recall from the user's past sessions after the RAG context
+	if cfg.Context != nil && cfg.Context.Recall != nil && config.BoolValue(cfg.Context.Recall.Enabled, false) {
+		recall, err := llmagent.NewRecallContextProvider(llmagent.RecallConfig{
+			Index:    r.index,
+			TopK:     cfg.Context.Recall.TopK,
+			MinScore: cfg.Context.Recall.MinScore,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create recall: %w", err)
+		}
+		contextProvider = llmagent.ChainContextProviders(contextProvider, recall)
+		slog.Debug("Past session recall enabled for agent", "agent", name)
+	}
+
 	// Get metrics recorder from observability manager
 	var metricsRecorder observability.Recorder
 	if r.observability != nil {
 		metricsRecorder = r.observability.Metrics()
 	}
 
+	// Resolve result summarization for toolsets that configure it
+	var toolSummarization map[string]llmagent.ToolResultSummarization
+	for _, ts := range toolsets {
+		toolCfg, ok := r.cfg.Tools[ts.Name()]
+		if !ok || toolCfg == nil || toolCfg.SummarizeIfOverTokens <= 0 {
+			continue
+		}
+		summarizerLLM := llm
+		if toolCfg.SummarizerLLM != "" {
+			summarizerLLM = r.llms[toolCfg.SummarizerLLM]
+			if summarizerLLM == nil {
+				slog.Warn("Summarizer LLM not found, tool result summarization disabled",
+					"summarizer_llm", toolCfg.SummarizerLLM,
+					"tool", ts.Name(),
+					"agent", name)
+				continue
+			}
+		}
+		if toolSummarization == nil {
+			toolSummarization = make(map[string]llmagent.ToolResultSummarization)
+		}
+		toolSummarization[ts.Name()] = llmagent.ToolResultSummarization{
+			MaxTokens: toolCfg.SummarizeIfOverTokens,
+			LLM:       summarizerLLM,
+		}
+	}
+
+	// Resolve error handling for toolsets that configure it
+	var toolErrorPolicies map[string]llmagent.ToolErrorPolicy
+	for _, ts := range toolsets {
+		toolCfg, ok := r.cfg.Tools[ts.Name()]
+		if !ok || toolCfg == nil || toolCfg.OnError == "" {
+			continue
+		}
+		if toolErrorPolicies == nil {
+			toolErrorPolicies = make(map[string]llmagent.ToolErrorPolicy)
+		}
+		toolErrorPolicies[ts.Name()] = llmagent.ToolErrorPolicy{
+			Mode:       llmagent.ToolErrorMode(toolCfg.OnError),
+			MaxRetries: toolCfg.MaxRetries,
+			Backoff:    toolCfg.RetryBackoff.Duration(),
+		}
+	}
+
+	// Resolve timeout and output limits for toolsets that configure them
+	var toolLimits map[string]llmagent.ToolLimits
+	for _, ts := range toolsets {
+		toolCfg, ok := r.cfg.Tools[ts.Name()]
+		if !ok || toolCfg == nil || (toolCfg.Timeout == 0 && toolCfg.MaxOutputBytes == 0) {
+			continue
+		}
+		if toolLimits == nil {
+			toolLimits = make(map[string]llmagent.ToolLimits)
+		}
+		toolLimits[ts.Name()] = llmagent.ToolLimits{
+			Timeout:        toolCfg.Timeout.Duration(),
+			MaxOutputBytes: toolCfg.MaxOutputBytes,
+		}
+	}
+
+	var promptBudget *llmagent.PromptBudget
+	if cfg.PromptBudget != nil {
+		promptBudget = &llmagent.PromptBudget{
+			ContextWindow: cfg.PromptBudget.ContextWindow,
+			MaxFraction:   cfg.PromptBudget.MaxFraction,
+			Action:        llmagent.PromptBudgetAction(cfg.PromptBudget.Action),
+		}
+	}
+
+	var responseLanguage *llmagent.ResponseLanguage
+	if cfg.ResponseLanguage != nil {
+		responseLanguage = &llmagent.ResponseLanguage{
+			Language:   cfg.ResponseLanguage.Language,
+			Verify:     config.BoolValue(cfg.ResponseLanguage.Verify, false),
+			MaxRetries: cfg.ResponseLanguage.MaxRetries,
+		}
+	}
+
+	// Build scope guardrail to refuse off-topic requests without an LLM call
+	var beforeAgentCallbacks []agent.BeforeAgentCallback
+	if cfg.Scope != nil {
+		beforeAgentCallbacks = append(beforeAgentCallbacks, llmagent.NewScopeGuard(llmagent.ScopeConfig{
+			AllowedTopics:  cfg.Scope.AllowedTopics,
+			RefusalMessage: cfg.Scope.RefusalMessage,
+			Threshold:      cfg.Scope.Threshold,
+			Embedder:       r.embedders[cfg.Scope.Embedder],
+		}))
+	}
+
+	// Checkpoint around tool calls so a crashed run resumes without
+	// re-running completed tools
+	var beforeToolCallbacks []llmagent.BeforeToolCallback
+	var afterToolCallbacks []llmagent.AfterToolCallback
+	if r.checkpoint != nil && r.checkpoint.ShouldCheckpointOnError() {
+		beforeToolCallbacks = append(beforeToolCallbacks, r.checkpoint.BeforeToolCallback())
+		afterToolCallbacks = append(afterToolCallbacks, r.checkpoint.AfterToolCallback())
+	}
+
 	return llmagent.New(llmagent.Config{
-		Name:            name,
-		Description:     cfg.Description,
-		Model:           llm,
-		Instruction:     cfg.GetSystemPrompt(),
-		Toolsets:        toolsets,
-		Tools:           tools,
-		SubAgents:       subAgents,
-		EnableStreaming: config.BoolValue(cfg.Streaming, false),
-		Reasoning:       reasoning,
-		GenerateConfig:  generateConfig,
-		WorkingMemory:   workingMemory,
-		ContextProvider: contextProvider,
-		MetricsRecorder: metricsRecorder,
+		Name:                    name,
+		Description:             cfg.Description,
+		Model:                   llm,
+		Instruction:             cfg.GetSystemPrompt(),
+		Toolsets:                toolsets,
+		Tools:                   tools,
+		SubAgents:               subAgents,
+		EnableStreaming:         config.BoolValue(cfg.Streaming, false),
+		Reasoning:               reasoning,
+		GenerateConfig:          generateConfig,
+		WorkingMemory:           workingMemory,
+		LongTermMemory:          longTermMemory,
+		ContextProvider:         contextProvider,
+		AttachSources:           cfg.Context != nil && config.BoolValue(cfg.Context.AttachSources, false),
+		MetricsRecorder:         metricsRecorder,
+		Tracer:                  r.observability.Tracer(),
+		BeforeAgentCallbacks:    beforeAgentCallbacks,
+		BeforeToolCallbacks:     beforeToolCallbacks,
+		AfterToolCallbacks:      afterToolCallbacks,
+		ToolResultSummarization: toolSummarization,
+		ToolErrorPolicies:       toolErrorPolicies,
+		ToolLimits:              toolLimits,
+		PromptBudget:            promptBudget,
+		ResponseLanguage:        responseLanguage,
+		PromptVersion:           r.promptVersions.resolve(name, cfg.PromptVersion, cfg.GetSystemPrompt()),
+		Timeout:                 cfg.RunTimeout(),
+		Observers:               r.agentObservers(name),
 	})
 }
 
+// agentObservers returns the observers of the named agent's LLM and tool
+// calls.
+func (r *Runtime) agentObservers(name string) []llmagent.Observer {
+	if r.langfuse == nil || r.cfg.Server.Observability == nil || !r.cfg.Server.Observability.Langfuse.ExportsAgent(name) {
+		return nil
+	}
+	return []llmagent.Observer{r.langfuse}
+}
+
 // GetAgent returns an agent by name.
 func (r *Runtime) GetAgent(name string) (agent.Agent, bool) {
 	r.mu.RLock()
@@ -947,6 +1391,10 @@ func (r *Runtime) Close() error {
 
 	var errs []error
 
+	if r.sweeper != nil {
+		r.sweeper.Stop()
+	}
+
 	// Shutdown observability first (flush traces/metrics)
 	if r.observability != nil {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -955,6 +1403,11 @@ func (r *Runtime) Close() error {
 			errs = append(errs, fmt.Errorf("observability: %w", err))
 		}
 	}
+	if r.langfuse != nil {
+		if err := r.langfuse.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("langfuse: %w", err))
+		}
+	}
 
 	// Close document stores
 	for name, store := range r.documentStores {
@@ -1023,6 +1476,12 @@ func (r *Runtime) Reload(newCfg *config.Config) error {
 		}
 		newLLMs[name] = llm
 	}
+	r.applyShadowLLMs(newCfg, newLLMs)
+	applyDedupLLMs(newCfg, newLLMs)
+	if err := r.applyCachingLLMs(newCfg, newLLMs); err != nil {
+		r.cfg = oldCfg // Rollback
+		return err
+	}
 
 	// Build new embedders
 	newEmbedders := make(map[string]embedder.Embedder)
@@ -1099,6 +1558,7 @@ func (r *Runtime) Reload(newCfg *config.Config) error {
 	r.llms = newLLMs
 	r.embedders = newEmbedders
 	r.agents = newAgents
+	r.applyLLMPricing(newCfg)
 
 	// 4. Cleanup old resources after grace period
 	go func() {
@@ -1324,7 +1784,8 @@ func (r *Runtime) DefaultRunnerConfig() (*runner.Config, error) {
 	}, nil
 }
 
-// NewAuthValidator creates a JWT validator from the server auth config.
+// NewAuthValidator creates a JWT and/or API key validator from the server
+// auth config.
 // Returns nil if authentication is not enabled.
 func (r *Runtime) NewAuthValidator() (auth.TokenValidator, error) {
 	if r.cfg.Server.Auth == nil || !r.cfg.Server.Auth.IsEnabled() {
@@ -1336,13 +1797,16 @@ func (r *Runtime) NewAuthValidator() (auth.TokenValidator, error) {
 		return nil, fmt.Errorf("failed to create auth validator: %w", err)
 	}
 
-	if validator != nil {
+	if validator != nil && r.cfg.Server.Auth.HasJWT() {
 		slog.Info("JWT authentication enabled",
 			"jwks_url", r.cfg.Server.Auth.JWKSURL,
 			"issuer", r.cfg.Server.Auth.Issuer,
 			"audience", r.cfg.Server.Auth.Audience,
 		)
 	}
+	if keys, ok := validator.(*auth.APIKeyValidator); ok {
+		slog.Info("API key authentication enabled", "header", keys.Header())
+	}
 
 	return validator, nil
 }
@@ -1406,6 +1870,18 @@ func (r *Runtime) StartDocumentStoreWatching(ctx context.Context) error {
 	r.mu.RUnlock()
 
 	for name, store := range stores {
+		store.OnReindex(func(diff rag.IndexDiff) {
+			if !diff.Changed() && diff.Failed == 0 {
+				return
+			}
+			slog.Info("Re-indexed changed documents",
+				"name", name,
+				"chunks_added", diff.Added,
+				"chunks_updated", diff.Updated,
+				"chunks_deleted", diff.Deleted,
+				"unchanged_docs", diff.Unchanged,
+				"failed_docs", diff.Failed)
+		})
 		if err := store.StartWatching(ctx); err != nil {
 			slog.Warn("Failed to start watching document store",
 				"name", name,
@@ -1451,11 +1927,23 @@ func (r *Runtime) createSearchToolForAgent(agentName string, cfg *config.AgentCo
 		return nil
 	}
 
+	// Per-store search defaults (top_k, min_score)
+	storeDefaults := make(map[string]searchtool.StoreDefaults)
+	for name := range validStores {
+		if storeCfg, ok := r.cfg.DocumentStores[name]; ok && storeCfg != nil && storeCfg.SearchDefaults != nil {
+			storeDefaults[name] = searchtool.StoreDefaults{
+				TopK:     storeCfg.SearchDefaults.TopK,
+				MinScore: storeCfg.SearchDefaults.MinScore,
+			}
+		}
+	}
+
 	return searchtool.New(searchtool.Config{
 		Stores:          validStores,
 		AvailableStores: availableStores,
 		MaxLimit:        50,
 		DefaultLimit:    10,
+		StoreDefaults:   storeDefaults,
 	})
 }
 
@@ -1520,7 +2008,12 @@ func (r *Runtime) createContextProviderForAgent(agentName string, cfg *config.Ag
 	// Return a context provider function that queries document stores
 	return func(ctx agent.ReadonlyContext, query string) (string, error) {
 		// ReadonlyContext embeds context.Context, so we can use it directly
-		return r.searchRAGContext(ctx, validStores, query, maxDocs, maxContentLen)
+		ragContext, sources, err := r.searchRAGContext(ctx, validStores, query, maxDocs, maxContentLen)
+		if err != nil {
+			return "", err
+		}
+		llmagent.RecordContextSources(ctx, sources...)
+		return ragContext, nil
 	}
 }
 
@@ -1534,7 +2027,8 @@ type ragSearchResult struct {
 
 // searchRAGContext searches document stores and formats results as context.
 // Follows legacy format: "[Data source: storeName (description)] content"
-func (r *Runtime) searchRAGContext(ctx context.Context, stores []*rag.DocumentStore, query string, maxDocs, maxContentLen int) (string, error) {
+// The sources of the included results are returned alongside.
+func (r *Runtime) searchRAGContext(ctx context.Context, stores []*rag.DocumentStore, query string, maxDocs, maxContentLen int) (string, []agent.ContextSource, error) {
 	var allResults []ragSearchResult
 
 	// Search all stores (like legacy SearchAllStores)
@@ -1561,7 +2055,7 @@ func (r *Runtime) searchRAGContext(ctx context.Context, stores []*rag.DocumentSt
 	}
 
 	if len(allResults) == 0 {
-		return "", nil
+		return "", nil, nil
 	}
 
 	// Limit total results (like legacy: cap to maxDocs)
@@ -1573,7 +2067,10 @@ func (r *Runtime) searchRAGContext(ctx context.Context, stores []*rag.DocumentSt
 	var contextBuilder strings.Builder
 	contextBuilder.WriteString("Relevant context from documents:\n")
 
+	sources := make([]agent.ContextSource, 0, len(allResults))
 	for _, item := range allResults {
+		sources = append(sources, ragContextSource(item))
+
 		content := item.result.Content
 		// Truncate content if needed (like legacy)
 		if len(content) > maxContentLen {
@@ -1588,7 +2085,31 @@ func (r *Runtime) searchRAGContext(ctx context.Context, stores []*rag.DocumentSt
 		}
 	}
 
-	return contextBuilder.String(), nil
+	return contextBuilder.String(), sources, nil
+}
+
+// ragContextSource describes a search result as a response source.
+// Title and path come from the document metadata when the source set them.
+func ragContextSource(item ragSearchResult) agent.ContextSource {
+	src := agent.ContextSource{
+		Store:      item.storeName,
+		DocumentID: item.result.DocumentID,
+		Score:      item.result.Score,
+	}
+	meta := item.result.Metadata
+	if title, ok := meta["title"].(string); ok {
+		src.Title = title
+	}
+	for _, key := range []string{"path", "file_path", "url"} {
+		if path, ok := meta[key].(string); ok && path != "" {
+			src.Path = path
+			break
+		}
+	}
+	if src.Title == "" {
+		src.Title = src.DocumentID
+	}
+	return src
 }
 
 // buildStoreDescription creates a human-readable description from store config and status.
@@ -0,0 +1,176 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+
+	"github.com/kadirpekel/hector/pkg/config"
+)
+
+// batchRequest is the body of POST /agents/{name}/messages:batch.
+type batchRequest struct {
+	// Messages are sent independently; each carries its own context ID.
+	Messages []*a2a.Message `json:"messages"`
+
+	// Configuration applies to every message (optional).
+	Configuration *a2a.MessageSendConfig `json:"configuration,omitempty"`
+}
+
+// batchItem is the outcome of one batch message. Exactly one of Result and
+// Error is set.
+type batchItem struct {
+	Index  int                   `json:"index"`
+	Result a2a.SendMessageResult `json:"result,omitempty"`
+	Error  *batchItemError       `json:"error,omitempty"`
+}
+
+// batchItemError describes a failed batch message. Status is the HTTP status
+// the message would have received on its own.
+type batchItemError struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+// handleBatch sends a batch of messages to the agent.
+//
+// Messages are processed as message/send calls with at most MaxConcurrency
+// in flight. A failed message is reported in its item and does not fail the
+// batch. Results are returned as JSON in request order; with
+// "Accept: text/event-stream" each item is streamed as SSE when it
+// completes, followed by a final "done" event.
+func (s *HTTPServer) handleBatch(w http.ResponseWriter, r *http.Request, handler a2asrv.RequestHandler, cfg *config.BatchConfig) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if cfg == nil {
+		cfg = &config.BatchConfig{}
+		cfg.SetDefaults()
+	}
+
+	var req batchRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	if len(req.Messages) == 0 {
+		writeJSONError(w, http.StatusBadRequest, "messages must not be empty")
+		return
+	}
+	if len(req.Messages) > cfg.MaxItems {
+		writeJSONError(w, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("batch has %d messages, the maximum is %d", len(req.Messages), cfg.MaxItems))
+		return
+	}
+
+	streaming := strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+	var flusher http.Flusher
+	if streaming {
+		var ok bool
+		if flusher, ok = w.(http.Flusher); !ok {
+			writeJSONError(w, http.StatusInternalServerError, "Streaming not supported")
+			return
+		}
+	}
+
+	items := runBatch(r.Context(), handler, &req, cfg.MaxConcurrency)
+
+	if !streaming {
+		results := make([]batchItem, len(req.Messages))
+		for item := range items {
+			results[item.Index] = item
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"results": results})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	failed := 0
+	for item := range items {
+		if item.Error != nil {
+			failed++
+		}
+		data, err := json.Marshal(item)
+		if err != nil {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "data: %s\n\n", data)
+		flusher.Flush()
+	}
+	data, _ := json.Marshal(map[string]int{"total": len(req.Messages), "failed": failed})
+	_, _ = fmt.Fprintf(w, "event: done\ndata: %s\n\n", data)
+	flusher.Flush()
+}
+
+// runBatch sends each message with at most concurrency in flight and
+// delivers the items as they complete. The channel closes when all are done.
+func runBatch(ctx context.Context, handler a2asrv.RequestHandler, req *batchRequest, concurrency int) <-chan batchItem {
+	if concurrency <= 0 || concurrency > len(req.Messages) {
+		concurrency = len(req.Messages)
+	}
+
+	indexes := make(chan int)
+	items := make(chan batchItem)
+
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				items <- sendBatchMessage(ctx, handler, i, req.Messages[i], req.Configuration)
+			}
+		}()
+	}
+
+	go func() {
+		for i := range req.Messages {
+			indexes <- i
+		}
+		close(indexes)
+		wg.Wait()
+		close(items)
+	}()
+
+	return items
+}
+
+// sendBatchMessage sends one batch message and reports its outcome.
+func sendBatchMessage(ctx context.Context, handler a2asrv.RequestHandler, index int, msg *a2a.Message, sendCfg *a2a.MessageSendConfig) batchItem {
+	if msg == nil {
+		return batchItem{Index: index, Error: &batchItemError{Status: http.StatusBadRequest, Message: "message is required"}}
+	}
+	if msg.ID == "" {
+		msg.ID = a2a.NewMessageID()
+	}
+
+	result, err := handler.OnSendMessage(ctx, &a2a.MessageSendParams{Message: msg, Config: sendCfg})
+	if err != nil {
+		return batchItem{Index: index, Error: &batchItemError{Status: resumeErrorStatus(err), Message: err.Error()}}
+	}
+	return batchItem{Index: index, Result: result}
+}
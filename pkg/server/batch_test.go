@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+
+	"github.com/kadirpekel/hector/pkg/config"
+)
+
+// echoHandler answers message/send with the message text, failing on "fail".
+type echoHandler struct {
+	a2asrv.RequestHandler
+	inFlight, maxInFlight atomic.Int32
+}
+
+func (h *echoHandler) OnSendMessage(ctx context.Context, params *a2a.MessageSendParams) (a2a.SendMessageResult, error) {
+	n := h.inFlight.Add(1)
+	defer h.inFlight.Add(-1)
+	for {
+		m := h.maxInFlight.Load()
+		if n <= m || h.maxInFlight.CompareAndSwap(m, n) {
+			break
+		}
+	}
+
+	text := params.Message.Parts[0].(a2a.TextPart).Text
+	if text == "fail" {
+		return nil, errors.New("boom")
+	}
+	return a2a.NewMessage(a2a.MessageRoleAgent, a2a.TextPart{Text: text}), nil
+}
+
+func TestHandleBatch(t *testing.T) {
+	body := `{"messages": [
+		{"role": "user", "parts": [{"kind": "text", "text": "one"}]},
+		{"role": "user", "parts": [{"kind": "text", "text": "fail"}]},
+		{"role": "user", "parts": [{"kind": "text", "text": "three"}]}
+	]}`
+	h := &echoHandler{}
+	cfg := &config.BatchConfig{MaxItems: 3, MaxConcurrency: 2}
+
+	rec := httptest.NewRecorder()
+	(&HTTPServer{}).handleBatch(rec, httptest.NewRequest(http.MethodPost, "/agents/a/messages:batch", strings.NewReader(body)), h, cfg)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+
+	var resp struct {
+		Results []struct {
+			Index  int             `json:"index"`
+			Result json.RawMessage `json:"result"`
+			Error  *batchItemError `json:"error"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if len(resp.Results) != 3 {
+		t.Fatalf("got %d results, want 3", len(resp.Results))
+	}
+	for i, item := range resp.Results {
+		if item.Index != i {
+			t.Errorf("results[%d].index = %d", i, item.Index)
+		}
+	}
+	if !strings.Contains(string(resp.Results[2].Result), "three") {
+		t.Errorf("results[2].result = %s, want three", resp.Results[2].Result)
+	}
+	if resp.Results[1].Error == nil || resp.Results[1].Error.Status != http.StatusInternalServerError {
+		t.Errorf("results[1].error = %+v, want status 500", resp.Results[1].Error)
+	}
+	if n := h.maxInFlight.Load(); n > 2 {
+		t.Errorf("max in flight = %d, want <= 2", n)
+	}
+
+	// Oversized batches are refused
+	cfg.MaxItems = 2
+	rec = httptest.NewRecorder()
+	(&HTTPServer{}).handleBatch(rec, httptest.NewRequest(http.MethodPost, "/agents/a/messages:batch", strings.NewReader(body)), h, cfg)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413", rec.Code)
+	}
+}
+
+func TestHandleBatchStream(t *testing.T) {
+	body := `{"messages": [{"role": "user", "parts": [{"kind": "text", "text": "one"}]}, null]}`
+	req := httptest.NewRequest(http.MethodPost, "/agents/a/messages:batch", strings.NewReader(body))
+	req.Header.Set("Accept", "text/event-stream")
+
+	rec := httptest.NewRecorder()
+	(&HTTPServer{}).handleBatch(rec, req, &echoHandler{}, nil)
+
+	out := rec.Body.String()
+	if got := strings.Count(out, "data: {\"index\""); got != 2 {
+		t.Errorf("got %d item events, want 2:\n%s", got, out)
+	}
+	if !strings.Contains(out, "event: done\ndata: {\"failed\":1,\"total\":2}") {
+		t.Errorf("missing done event:\n%s", out)
+	}
+}
@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/auth"
+	"github.com/kadirpekel/hector/pkg/checkpoint"
+	"github.com/kadirpekel/hector/pkg/session"
+)
+
+// checkpointBrowser lists and restores session checkpoints. It is
+// implemented by checkpoint.Manager.
+type checkpointBrowser interface {
+	List(ctx context.Context, appName, userID, sessionID string) ([]checkpoint.CheckpointInfo, error)
+	Restore(ctx context.Context, appName, userID, sessionID, checkpointID string) error
+}
+
+// SessionCheckpoints is the response of GET /agents/{name}/sessions/{id}/checkpoints.
+type SessionCheckpoints struct {
+	SessionID   string                      `json:"session_id"`
+	Checkpoints []checkpoint.CheckpointInfo `json:"checkpoints"`
+}
+
+// checkpointsOf returns the executor's checkpoint manager, or nil when
+// checkpointing is not enabled.
+func checkpointsOf(executor *Executor) checkpointBrowser {
+	if executor == nil {
+		return nil
+	}
+	mgr := executor.config.RunnerConfig.CheckpointManager
+	if mgr == nil || !mgr.IsEnabled() {
+		return nil
+	}
+	browser, _ := mgr.(checkpointBrowser)
+	return browser
+}
+
+// handleListCheckpoints returns a session's checkpoints, newest first. The
+// session owner is the caller's user (see requestUserID).
+func (s *HTTPServer) handleListCheckpoints(w http.ResponseWriter, r *http.Request, executor *Executor, sessionID string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	browser := checkpointsOf(executor)
+	if browser == nil {
+		writeJSONError(w, http.StatusNotFound, "Checkpointing is not enabled")
+		return
+	}
+
+	infos, err := browser.List(r.Context(), executor.config.RunnerConfig.AppName, s.requestUserID(r), sessionID)
+	if err != nil {
+		writeCheckpointError(w, err, sessionID)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(SessionCheckpoints{SessionID: sessionID, Checkpoints: infos})
+}
+
+// handleRestoreCheckpoint rolls a session back to one of its checkpoints.
+func (s *HTTPServer) handleRestoreCheckpoint(w http.ResponseWriter, r *http.Request, executor *Executor, sessionID, checkpointID string) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	browser := checkpointsOf(executor)
+	if browser == nil {
+		writeJSONError(w, http.StatusNotFound, "Checkpointing is not enabled")
+		return
+	}
+
+	if err := browser.Restore(r.Context(), executor.config.RunnerConfig.AppName, s.requestUserID(r), sessionID, checkpointID); err != nil {
+		writeCheckpointError(w, err, sessionID)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"session_id":    sessionID,
+		"checkpoint_id": checkpointID,
+		"restored":      true,
+	})
+}
+
+// writeCheckpointError maps checkpoint errors to HTTP statuses.
+func writeCheckpointError(w http.ResponseWriter, err error, sessionID string) {
+	switch {
+	case errors.Is(err, session.ErrSessionNotFound):
+		writeJSONError(w, http.StatusNotFound, "Session not found: "+sessionID)
+	case errors.Is(err, checkpoint.ErrCheckpointNotFound):
+		writeJSONError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, checkpoint.ErrRestoreUnsupported):
+		writeJSONError(w, http.StatusNotImplemented, err.Error())
+	default:
+		writeJSONError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// requestUserID returns the session owner of a request, matching the
+// user_id message metadata. With auth enabled it is the caller's subject
+// (anonymous callers get the shared anonymous user); otherwise it is taken
+// from the user_id query parameter (default: "default").
+func (s *HTTPServer) requestUserID(r *http.Request) string {
+	if s.authValidator != nil {
+		if claims := auth.ClaimsFromContext(r.Context()); claims != nil && claims.Subject != "" {
+			return claims.Subject
+		}
+		return agent.AnonymousUserID
+	}
+	if userID := r.URL.Query().Get(auth.MetaKeyUserID); userID != "" {
+		return userID
+	}
+	return agent.AnonymousUserID
+}
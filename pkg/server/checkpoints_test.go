@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kadirpekel/hector/pkg/checkpoint"
+	"github.com/kadirpekel/hector/pkg/runner"
+	"github.com/kadirpekel/hector/pkg/session"
+)
+
+func TestCheckpointEndpoints(t *testing.T) {
+	ctx := context.Background()
+	sessions := session.InMemoryService()
+	enabled := true
+	cpCfg := &checkpoint.Config{Enabled: &enabled}
+	cpCfg.SetDefaults()
+	mgr := checkpoint.NewManager(cpCfg, sessions)
+	executor := NewExecutor(ExecutorConfig{RunnerConfig: runner.Config{AppName: "app", SessionService: sessions, CheckpointManager: mgr}})
+
+	if err := executor.prepareSession(ctx, invocationMeta{userID: "default", sessionID: "s1"}); err != nil {
+		t.Fatalf("prepareSession() error = %v", err)
+	}
+	if err := mgr.SaveCheckpoint(ctx, checkpoint.NewState("task-1", "s1", "default", "app", "hi", "a", "inv-1")); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &HTTPServer{}
+	rec := httptest.NewRecorder()
+	s.handleListCheckpoints(rec, httptest.NewRequest(http.MethodGet, "/agents/a/sessions/s1/checkpoints", nil), executor, "s1")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("list status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	var got SessionCheckpoints
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(got.Checkpoints) != 1 || got.Checkpoints[0].ID != "task-1" {
+		t.Fatalf("checkpoints = %+v", got.Checkpoints)
+	}
+
+	rec = httptest.NewRecorder()
+	s.handleRestoreCheckpoint(rec, httptest.NewRequest(http.MethodPost, "/agents/a/sessions/s1/checkpoints/task-1/restore", nil), executor, "s1", "task-1")
+	if rec.Code != http.StatusOK {
+		t.Errorf("restore status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	s.handleRestoreCheckpoint(rec, httptest.NewRequest(http.MethodPost, "/agents/a/sessions/s1/checkpoints/nope/restore", nil), executor, "s1", "nope")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown checkpoint status = %d, want 404", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	s.handleListCheckpoints(rec, httptest.NewRequest(http.MethodGet, "/agents/a/sessions/missing/checkpoints", nil), executor, "missing")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("unknown session status = %d, want 404", rec.Code)
+	}
+
+	// Without checkpointing the endpoints are not available
+	plain := NewExecutor(ExecutorConfig{RunnerConfig: runner.Config{AppName: "app", SessionService: sessions}})
+	rec = httptest.NewRecorder()
+	s.handleListCheckpoints(rec, httptest.NewRequest(http.MethodGet, "/agents/a/sessions/s1/checkpoints", nil), plain, "s1")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("disabled status = %d, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"iter"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+)
+
+// contextIDHandler assigns context IDs to new conversations.
+//
+// a2a-go generates a random context ID for messages that carry neither a
+// context ID nor a task ID. Setting the ID on the message before it reaches
+// the handler lets the executor's generator take its place.
+type contextIDHandler struct {
+	a2asrv.RequestHandler
+	generate func() string
+}
+
+// newContextIDHandler wraps handler when a generator is configured.
+func newContextIDHandler(handler a2asrv.RequestHandler, generate func() string) a2asrv.RequestHandler {
+	if generate == nil {
+		return handler
+	}
+	return &contextIDHandler{RequestHandler: handler, generate: generate}
+}
+
+func (h *contextIDHandler) OnSendMessage(ctx context.Context, params *a2a.MessageSendParams) (a2a.SendMessageResult, error) {
+	h.assign(params)
+	return h.RequestHandler.OnSendMessage(ctx, params)
+}
+
+func (h *contextIDHandler) OnSendMessageStream(ctx context.Context, params *a2a.MessageSendParams) iter.Seq2[a2a.Event, error] {
+	h.assign(params)
+	return h.RequestHandler.OnSendMessageStream(ctx, params)
+}
+
+// assign sets a generated context ID on messages that start a conversation.
+func (h *contextIDHandler) assign(params *a2a.MessageSendParams) {
+	if params == nil || params.Message == nil {
+		return
+	}
+	msg := params.Message
+	if msg.ContextID == "" && msg.TaskID == "" {
+		msg.ContextID = h.generate()
+	}
+}
@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultShutdownGrace is used when the server config has no shutdown_grace.
+// With streamStopTimeout it stays within the 30s termination grace period
+// Kubernetes gives pods by default.
+const defaultShutdownGrace = 20 * time.Second
+
+// streamStopTimeout bounds how long shutdown waits for streaming handlers
+// to return once they are cancelled at the end of the grace period.
+const streamStopTimeout = 5 * time.Second
+
+// errServerShuttingDown cancels streams still open when the shutdown grace
+// period ends.
+var errServerShuttingDown = errors.New("server shutting down")
+
+// streamTracker tracks in-flight streaming responses and WebSocket sessions
+// so shutdown can wait for them to finish and end the ones that outlive the
+// grace period.
+type streamTracker struct {
+	wg        sync.WaitGroup
+	drain     chan struct{}
+	drainOnce sync.Once
+	stop      chan struct{}
+	stopOnce  sync.Once
+}
+
+func newStreamTracker() *streamTracker {
+	return &streamTracker{drain: make(chan struct{}), stop: make(chan struct{})}
+}
+
+// middleware registers streaming requests with the tracker.
+//
+// A stream is cancelled when stopAll is called, like streamDurationMiddleware
+// does at the maximum duration: the task keeps running and a final "done"
+// event tells the client why the stream closed.
+func (t *streamTracker) middleware(next http.Handler) http.Handler {
+	if t == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info := requestInfoFrom(r)
+		if !info.streaming {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		t.wg.Add(1)
+		defer t.wg.Done()
+
+		ctx, cancel := context.WithCancelCause(r.Context())
+		defer cancel(nil)
+		go func() {
+			select {
+			case <-t.stop:
+				cancel(errServerShuttingDown)
+			case <-ctx.Done():
+			}
+		}()
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+
+		if !errors.Is(context.Cause(ctx), errServerShuttingDown) {
+			return
+		}
+		slog.Debug("Closing stream at shutdown", "path", r.URL.Path)
+		writeStreamCloseEvent(w, "done", info.id, errServerShuttingDown.Error(), map[string]any{
+			"reason":        "server_shutdown",
+			"resume_method": "tasks/resubscribe",
+		})
+	})
+}
+
+// trackWebSocket registers a WebSocket session. http.Server.Shutdown
+// neither waits for nor closes hijacked connections, so the session watches
+// drain, closed when shutdown begins, and stop, closed when the grace period
+// ends. done must be called when the session ends.
+func (t *streamTracker) trackWebSocket() (drain, stop <-chan struct{}, done func()) {
+	if t == nil {
+		return nil, nil, func() {}
+	}
+	t.wg.Add(1)
+	return t.drain, t.stop, t.wg.Done
+}
+
+// beginDrain tells WebSocket sessions to close once their open streams
+// finish.
+func (t *streamTracker) beginDrain() {
+	t.drainOnce.Do(func() { close(t.drain) })
+}
+
+// wait blocks until all tracked streams have finished or the timeout
+// elapses, and reports whether they all finished.
+func (t *streamTracker) wait(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// stopAll cancels all in-flight streams and WebSocket sessions and waits for
+// their handlers to send the final event, up to streamStopTimeout.
+func (t *streamTracker) stopAll() bool {
+	t.stopOnce.Do(func() { close(t.stop) })
+	return t.wait(streamStopTimeout)
+}
@@ -0,0 +1,187 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/kadirpekel/hector/pkg/config"
+)
+
+// startDrainServer serves handler behind the stream tracker and returns the
+// server and its URL.
+func startDrainServer(t *testing.T, grace time.Duration, handler http.Handler) (*HTTPServer, string) {
+	t.Helper()
+	s := &HTTPServer{
+		serverCfg: &config.ServerConfig{ShutdownGrace: config.Duration(grace)},
+		streams:   newStreamTracker(),
+	}
+	s.server = &http.Server{Handler: classifyRequestMiddleware(s.streams.middleware(handler))}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = s.server.Serve(ln) }()
+	t.Cleanup(func() { _ = s.server.Close() })
+	return s, "http://" + ln.Addr().String()
+}
+
+// openStream starts a message/stream request and waits for its first event.
+func openStream(t *testing.T, url string) (*http.Response, *bufio.Reader) {
+	t.Helper()
+	body := `{"jsonrpc":"2.0","id":3,"method":"message/stream","params":{}}`
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	reader := bufio.NewReader(resp.Body)
+	if _, err := reader.ReadString('\n'); err != nil {
+		t.Fatalf("reading first event: %v", err)
+	}
+	return resp, reader
+}
+
+func TestShutdownWaitsForStreams(t *testing.T) {
+	release := make(chan struct{})
+	stream := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {\"n\":1}\n\n"))
+		w.(http.Flusher).Flush()
+		<-release
+		_, _ = w.Write([]byte("data: {\"n\":2}\n\n"))
+	})
+	s, url := startDrainServer(t, 5*time.Second, stream)
+	_, reader := openStream(t, url)
+
+	shutdownErr := make(chan error, 1)
+	go func() { shutdownErr <- s.Shutdown(context.Background()) }()
+
+	select {
+	case err := <-shutdownErr:
+		t.Fatalf("Shutdown returned with a stream in flight: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	rest, _ := io.ReadAll(reader)
+	if !strings.Contains(string(rest), `"n":2`) {
+		t.Errorf("stream was cut before finishing, got %q", rest)
+	}
+	if strings.Contains(string(rest), "event: done") {
+		t.Errorf("stream finished within grace should not get a done event, got %q", rest)
+	}
+	if err := <-shutdownErr; err != nil {
+		t.Errorf("Shutdown: %v", err)
+	}
+}
+
+func TestShutdownEndsStreamsAfterGrace(t *testing.T) {
+	stream := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {}\n\n"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+	s, url := startDrainServer(t, 50*time.Millisecond, stream)
+	_, reader := openStream(t, url)
+
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown: %v", err)
+	}
+
+	rest, _ := io.ReadAll(reader)
+	out := string(rest)
+	if !strings.Contains(out, "event: done") || !strings.Contains(out, `"id":3`) {
+		t.Errorf("missing final done event, got %q", out)
+	}
+	if !strings.Contains(out, "server_shutdown") {
+		t.Errorf("done event should give the shutdown reason, got %q", out)
+	}
+}
+
+// openDrainWebSocket serves the WebSocket protocol behind the stream tracker
+// and connects to it.
+func openDrainWebSocket(t *testing.T, grace time.Duration, h *streamHandler) (*HTTPServer, *websocket.Conn) {
+	t.Helper()
+	var s *HTTPServer
+	s, url := startDrainServer(t, grace, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.handleWebSocket(w, r, h)
+	}))
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(url, "http"), nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return s, conn
+}
+
+// readCloseCode reads until the server closes the socket and returns the
+// close code.
+func readCloseCode(t *testing.T, conn *websocket.Conn) int {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		_, _, err := conn.ReadMessage()
+		var closeErr *websocket.CloseError
+		if errors.As(err, &closeErr) {
+			return closeErr.Code
+		}
+		if err != nil {
+			t.Fatalf("ReadMessage() error = %v, want a close frame", err)
+		}
+	}
+}
+
+func TestShutdownClosesIdleWebSockets(t *testing.T) {
+	s, conn := openDrainWebSocket(t, 5*time.Second, &streamHandler{})
+
+	start := time.Now()
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown: %v", err)
+	}
+	if code := readCloseCode(t, conn); code != websocket.CloseGoingAway {
+		t.Errorf("close code = %d, want %d", code, websocket.CloseGoingAway)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Shutdown took %v with only an idle socket open", elapsed)
+	}
+}
+
+func TestShutdownEndsWebSocketStreamsAfterGrace(t *testing.T) {
+	h := &streamHandler{cancelled: make(chan struct{})}
+	s, conn := openDrainWebSocket(t, 50*time.Millisecond, h)
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(streamRequest(1, "wait"))); err != nil {
+		t.Fatal(err)
+	}
+	// The stream is open once a duplicate id is refused
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(streamRequest(1, "hi"))); err != nil {
+		t.Fatal(err)
+	}
+	if f := readFrame(t, conn); f.Error == nil {
+		t.Fatalf("duplicate id frame = %+v", f)
+	}
+
+	shutdownErr := make(chan error, 1)
+	go func() { shutdownErr <- s.Shutdown(context.Background()) }()
+
+	f := readFrame(t, conn)
+	if f.Method != wsMethodStreamEnd || string(f.Params.ID) != "1" || f.Params.Reason != "server_shutdown" {
+		t.Errorf("final frame = %+v, want stream/end with reason server_shutdown", f)
+	}
+	if code := readCloseCode(t, conn); code != websocket.CloseGoingAway {
+		t.Errorf("close code = %d, want %d", code, websocket.CloseGoingAway)
+	}
+	if err := <-shutdownErr; err != nil {
+		t.Errorf("Shutdown: %v", err)
+	}
+}
@@ -24,6 +24,8 @@ import (
 	"github.com/a2aproject/a2a-go/a2asrv"
 
 	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/auth"
+	"github.com/kadirpekel/hector/pkg/checkpoint"
 )
 
 // Metadata keys for A2A events
@@ -39,6 +41,9 @@ type invocationMeta struct {
 	userID    string
 	sessionID string
 	eventMeta map[string]any
+
+	// streamUsage requests live usage updates for this invocation
+	streamUsage bool
 }
 
 func toInvocationMeta(reqCtx *a2asrv.RequestContext) invocationMeta {
@@ -53,16 +58,18 @@ func toInvocationMeta(reqCtx *a2asrv.RequestContext) invocationMeta {
 	meta.sessionID = reqCtx.ContextID
 	slog.Debug("Using a2asrv context as session", "sessionID", meta.sessionID, "taskID", string(reqCtx.TaskID))
 
-	// Extract user ID from message metadata
+	// Extract user ID from message metadata. With auth enabled the auth
+	// interceptor has bound it to the caller's subject.
 	if reqCtx.Message != nil && reqCtx.Message.Metadata != nil {
-		if uid, ok := reqCtx.Message.Metadata["user_id"].(string); ok {
+		if uid, ok := reqCtx.Message.Metadata[auth.MetaKeyUserID].(string); ok {
 			meta.userID = uid
 		}
+		meta.streamUsage, _ = reqCtx.Message.Metadata[metaKeyStreamUsage].(bool)
 	}
 
 	// Default user ID
 	if meta.userID == "" {
-		meta.userID = "default"
+		meta.userID = agent.AnonymousUserID
 	}
 
 	return meta
@@ -79,6 +86,15 @@ type eventProcessor struct {
 	// responseID is created once first artifact is sent
 	responseID a2a.ArtifactID
 
+	// sources are the documents retrieved for the final response
+	sources []agent.ContextSource
+
+	// promptVersion is the instruction version of the last model response
+	promptVersion *agent.PromptVersion
+
+	// usage meters the turn's tokens when live usage updates are enabled
+	usage *usageMeter
+
 	// terminalEvents holds potential terminal events by state
 	terminalEvents map[a2a.TaskState]*a2a.TaskStatusUpdateEvent
 }
@@ -97,6 +113,12 @@ func (p *eventProcessor) process(ctx context.Context, event *agent.Event) (*a2a.
 	}
 
 	p.updateTerminalActions(event)
+	if len(event.Sources) > 0 {
+		p.sources = event.Sources
+	}
+	if pv := event.PromptVersion(); pv != nil {
+		p.promptVersion = pv
+	}
 
 	eventMeta := p.makeEventMeta(event)
 
@@ -112,14 +134,18 @@ func (p *eventProcessor) process(ctx context.Context, event *agent.Event) (*a2a.
 			"longRunningToolIDs", len(event.LongRunningToolIDs),
 			"requireInput", event.Actions.RequireInput,
 			"inputPrompt", event.Actions.InputPrompt)
-		// Build status message with prompt if available
-		var statusMsg *a2a.Message
+		// Build status message with prompt and approval requests if available
+		var statusParts []a2a.Part
 		if event.Actions.InputPrompt != "" {
-			statusMsg = a2a.NewMessageForTask(
-				a2a.MessageRoleAgent,
-				p.reqCtx,
-				a2a.TextPart{Text: event.Actions.InputPrompt},
-			)
+			statusParts = append(statusParts, a2a.TextPart{Text: event.Actions.InputPrompt})
+		}
+		approvals := p.approvalRequests(event.Actions.ApprovalRequests)
+		for _, approval := range approvals {
+			statusParts = append(statusParts, a2a.DataPart{Data: approval})
+		}
+		var statusMsg *a2a.Message
+		if len(statusParts) > 0 {
+			statusMsg = a2a.NewMessageForTask(a2a.MessageRoleAgent, p.reqCtx, statusParts...)
 		}
 
 		ev := a2a.NewStatusUpdateEvent(p.reqCtx, a2a.TaskStateInputRequired, statusMsg)
@@ -141,6 +167,13 @@ func (p *eventProcessor) process(ctx context.Context, event *agent.Event) (*a2a.
 		if event.Actions.InputPrompt != "" {
 			ev.Metadata["input_prompt"] = event.Actions.InputPrompt
 		}
+		if len(approvals) > 0 {
+			approvalMeta := make([]any, len(approvals))
+			for i, approval := range approvals {
+				approvalMeta[i] = approval
+			}
+			ev.Metadata["approval_requests"] = approvalMeta
+		}
 
 		p.terminalEvents[a2a.TaskStateInputRequired] = ev
 	}
@@ -180,6 +213,36 @@ func (p *eventProcessor) process(ctx context.Context, event *agent.Event) (*a2a.
 	return result, nil
 }
 
+// approvalRequests builds the structured approval request for each paused
+// tool call, including the resume token the client sends back with its decision.
+func (p *eventProcessor) approvalRequests(requests []agent.ToolApprovalRequest) []map[string]any {
+	if len(requests) == 0 {
+		return nil
+	}
+
+	result := make([]map[string]any, 0, len(requests))
+	for _, req := range requests {
+		token := &checkpoint.ResumeToken{
+			TaskID:     string(p.reqCtx.TaskID),
+			SessionID:  p.reqCtx.ContextID,
+			ToolCallID: req.ToolCallID,
+			ToolName:   req.ToolName,
+		}
+		args := req.Args
+		if args == nil {
+			args = map[string]any{}
+		}
+		result = append(result, map[string]any{
+			"type":         "tool_approval_request",
+			"tool_call_id": req.ToolCallID,
+			"tool_name":    req.ToolName,
+			"arguments":    args,
+			"resume_token": token.Encode(),
+		})
+	}
+	return result
+}
+
 func (p *eventProcessor) makeTerminalEvents() []a2a.Event {
 	result := make([]a2a.Event, 0, 2)
 
@@ -193,7 +256,7 @@ func (p *eventProcessor) makeTerminalEvents() []a2a.Event {
 	// Check for failure or input required (in priority order)
 	for _, state := range []a2a.TaskState{a2a.TaskStateFailed, a2a.TaskStateInputRequired} {
 		if ev, ok := p.terminalEvents[state]; ok {
-			ev.Metadata = p.setActionsMeta(ev.Metadata)
+			ev.Metadata = p.setUsageMeta(p.setActionsMeta(ev.Metadata))
 			result = append(result, ev)
 			return result
 		}
@@ -202,7 +265,13 @@ func (p *eventProcessor) makeTerminalEvents() []a2a.Event {
 	// Default: completed
 	ev := a2a.NewStatusUpdateEvent(p.reqCtx, a2a.TaskStateCompleted, nil)
 	ev.Final = true
-	ev.Metadata = p.setActionsMeta(maps.Clone(p.meta.eventMeta))
+	ev.Metadata = p.setUsageMeta(p.setActionsMeta(maps.Clone(p.meta.eventMeta)))
+	if len(p.sources) > 0 {
+		ev.Metadata["sources"] = sourcesMeta(p.sources)
+	}
+	if p.promptVersion != nil {
+		ev.Metadata[agent.MetaKeyPromptVersion] = promptVersionMeta(p.promptVersion)
+	}
 	result = append(result, ev)
 
 	return result
@@ -283,9 +352,49 @@ func (p *eventProcessor) makeEventMeta(event *agent.Event) map[string]any {
 		meta["tool_results"] = toolResults
 	}
 
+	// Sources - documents retrieved as context for the response
+	if len(event.Sources) > 0 {
+		meta["sources"] = sourcesMeta(event.Sources)
+	}
+
+	// Prompt version - instruction that produced the response
+	if pv := event.PromptVersion(); pv != nil {
+		meta[agent.MetaKeyPromptVersion] = promptVersionMeta(pv)
+	}
+
 	return meta
 }
 
+// promptVersionMeta converts a prompt version to A2A metadata.
+func promptVersionMeta(pv *agent.PromptVersion) map[string]any {
+	return map[string]any{
+		"version": pv.Version,
+		"hash":    pv.Hash,
+	}
+}
+
+// sourcesMeta converts context sources to A2A metadata.
+func sourcesMeta(sources []agent.ContextSource) []any {
+	result := make([]any, len(sources))
+	for i, src := range sources {
+		m := map[string]any{
+			"store": src.Store,
+			"score": src.Score,
+		}
+		if src.DocumentID != "" {
+			m["document_id"] = src.DocumentID
+		}
+		if src.Title != "" {
+			m["title"] = src.Title
+		}
+		if src.Path != "" {
+			m["path"] = src.Path
+		}
+		result[i] = m
+	}
+	return result
+}
+
 func (p *eventProcessor) setActionsMeta(meta map[string]any) map[string]any {
 	if meta == nil {
 		meta = make(map[string]any)
@@ -301,6 +410,14 @@ func (p *eventProcessor) setActionsMeta(meta map[string]any) map[string]any {
 	return meta
 }
 
+// setUsageMeta adds the turn's final token usage when usage is metered.
+func (p *eventProcessor) setUsageMeta(meta map[string]any) map[string]any {
+	if p.usage != nil {
+		meta[metaKeyUsage] = p.usage.meta()
+	}
+	return meta
+}
+
 func toFailedStatusEvent(reqCtx *a2asrv.RequestContext, cause error, meta map[string]any) *a2a.TaskStatusUpdateEvent {
 	msg := a2a.NewMessageForTask(a2a.MessageRoleAgent, reqCtx, a2a.TextPart{Text: cause.Error()})
 	ev := a2a.NewStatusUpdateEvent(reqCtx, a2a.TaskStateFailed, msg)
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+)
+
+func TestEventProcessorStampsPromptVersion(t *testing.T) {
+	p := newEventProcessor(&a2asrv.RequestContext{TaskID: "task-1", ContextID: "ctx-1"}, invocationMeta{})
+
+	// Restored from a persisted session, the version is a plain map
+	event := &agent.Event{
+		Author:         "assistant",
+		Message:        a2a.NewMessage(a2a.MessageRoleAgent, a2a.TextPart{Text: "Hi"}),
+		CustomMetadata: map[string]any{agent.MetaKeyPromptVersion: map[string]any{"version": "v2", "hash": "3f2a9c0d1e4b"}},
+	}
+	want := map[string]any{"version": "v2", "hash": "3f2a9c0d1e4b"}
+
+	meta := p.makeEventMeta(event)
+	if got, _ := meta[agent.MetaKeyPromptVersion].(map[string]any); got["version"] != want["version"] || got["hash"] != want["hash"] {
+		t.Errorf("event prompt_version = %v, want %v", meta[agent.MetaKeyPromptVersion], want)
+	}
+
+	if _, err := p.process(t.Context(), event); err != nil {
+		t.Fatalf("process() error = %v", err)
+	}
+	events := p.makeTerminalEvents()
+	final, ok := events[len(events)-1].(*a2a.TaskStatusUpdateEvent)
+	if !ok || final.Status.State != a2a.TaskStateCompleted {
+		t.Fatalf("last event = %#v, want completed status", events[len(events)-1])
+	}
+	if got, _ := final.Metadata[agent.MetaKeyPromptVersion].(map[string]any); got["version"] != "v2" {
+		t.Errorf("terminal prompt_version = %v, want v2", final.Metadata[agent.MetaKeyPromptVersion])
+	}
+}
@@ -42,6 +42,7 @@ import (
 	"github.com/a2aproject/a2a-go/a2asrv/eventqueue"
 
 	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/observability"
 	"github.com/kadirpekel/hector/pkg/runner"
 	"github.com/kadirpekel/hector/pkg/session"
 )
@@ -53,6 +54,17 @@ type ExecutorConfig struct {
 
 	// RunConfig contains runtime configuration for agent execution.
 	RunConfig agent.RunConfig
+
+	// ContextIDGenerator returns the context ID of a new conversation when
+	// the client sends neither a context ID nor a task ID (optional). The
+	// context ID doubles as the session ID. Default: a2a-go's generator
+	// (random UUIDs).
+	ContextIDGenerator func() string
+
+	// StreamUsage emits live token usage updates while a turn streams, as
+	// working status updates with "usage" metadata. Requests can also opt
+	// in with "stream_usage": true in the message metadata.
+	StreamUsage bool
 }
 
 // Executor implements a2asrv.AgentExecutor to bridge Hector agents to A2A.
@@ -65,6 +77,9 @@ type ExecutorConfig struct {
 //   - On LLM error: emit TaskStatusUpdateEvent with TaskStateFailed
 //   - On long-running tool: emit TaskStatusUpdateEvent with TaskStateInputRequired
 //   - On success: emit TaskStatusUpdateEvent with TaskStateCompleted
+//   - With StreamUsage: periodically emit TaskStatusUpdateEvent with
+//     TaskStateWorking and estimated "usage" metadata; terminal status
+//     events carry the final usage
 type Executor struct {
 	config ExecutorConfig
 }
@@ -125,6 +140,13 @@ func (e *Executor) Execute(ctx context.Context, reqCtx *a2asrv.RequestContext, q
 	// Extract user/session info from request context
 	meta := toInvocationMeta(reqCtx)
 
+	// Carry session/user/task IDs down to spans and logs (e.g., LLM calls)
+	ctx = observability.WithCorrelation(ctx, observability.Correlation{
+		SessionID: meta.sessionID,
+		UserID:    meta.userID,
+		TaskID:    string(reqCtx.TaskID),
+	})
+
 	// Prepare session
 	if err := e.prepareSession(ctx, meta); err != nil {
 		event := toFailedStatusEvent(reqCtx, err, meta.eventMeta)
@@ -202,7 +224,15 @@ func (e *Executor) Cancel(ctx context.Context, reqCtx *a2asrv.RequestContext, qu
 func (e *Executor) process(ctx context.Context, r *runner.Runner, processor *eventProcessor, content *agent.Content, q eventqueue.Queue) error {
 	meta := processor.meta
 
+	usage := make(usageTracker)
+	defer e.recordUsage(ctx, meta, usage)
+
+	if e.config.StreamUsage || meta.streamUsage {
+		processor.usage = newUsageMeter(usage)
+	}
+
 	for event, err := range r.Run(ctx, meta.userID, meta.sessionID, content, e.config.RunConfig) {
+		usage.add(event)
 		if err != nil {
 			failedEvent := processor.makeFailedEvent(fmt.Errorf("agent run failed: %w", err), nil)
 			if writeErr := q.Write(ctx, failedEvent); writeErr != nil {
@@ -225,6 +255,12 @@ func (e *Executor) process(ctx context.Context, r *runner.Runner, processor *eve
 				return fmt.Errorf("failed to write event: %w", err)
 			}
 		}
+
+		if processor.usage != nil && processor.usage.observe(event) {
+			if err := q.Write(ctx, processor.usage.updateEvent(processor.reqCtx)); err != nil {
+				return fmt.Errorf("failed to write usage update: %w", err)
+			}
+		}
 	}
 
 	// Write terminal events
@@ -16,17 +16,22 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	_ "embed"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"net"
 	"net/http"
 	"os"
+	"slices"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/a2aproject/a2a-go/a2a"
@@ -38,6 +43,7 @@ import (
 	"github.com/kadirpekel/hector/pkg/auth"
 	"github.com/kadirpekel/hector/pkg/config"
 	"github.com/kadirpekel/hector/pkg/observability"
+	"github.com/kadirpekel/hector/pkg/task"
 	"google.golang.org/grpc"
 )
 
@@ -60,7 +66,7 @@ type HTTPServer struct {
 	//nolint:unused // Reserved for future use
 	grpcListener net.Listener
 
-	// TaskStore for persistent task storage (nil = in-memory)
+	// TaskStore shared by all agents (defaults to task.InMemoryTaskStore)
 	taskStore a2asrv.TaskStore
 
 	// Auth: JWT validator and a2a-go interceptor
@@ -72,12 +78,17 @@ type HTTPServer struct {
 
 	// Per-agent: JSON-RPC handler + agent card handler (both from a2a-go)
 	agentJSONRPCHandlers map[string]http.Handler
+	agentRequestHandlers map[string]a2asrv.RequestHandler
+	agentExecutors       map[string]*Executor
 	agentCardHandlers    map[string]http.Handler
 	agentCards           map[string]*a2a.AgentCard
 
 	// Per-agent: gRPC handlers (only when Transport == TransportGRPC)
 	agentGRPCHandlers map[string]*a2agrpc.Handler
 
+	// In-flight SSE streams, drained on shutdown
+	streams *streamTracker
+
 	// Studio mode: config file path and studio mode flag
 	configPath string
 	studioMode bool
@@ -88,7 +99,7 @@ type HTTPServer struct {
 type HTTPServerOption func(*HTTPServer)
 
 // WithTaskStore sets the task store for persistent task storage.
-// If not set, a2a-go uses its internal in-memory store.
+// If not set, tasks are kept in a task.InMemoryTaskStore.
 func WithTaskStore(store a2asrv.TaskStore) HTTPServerOption {
 	return func(s *HTTPServer) {
 		s.taskStore = store
@@ -123,15 +134,21 @@ func NewHTTPServer(appCfg *config.Config, executors map[string]*Executor, opts .
 		serverCfg:            serverCfg,
 		appCfg:               appCfg,
 		agentJSONRPCHandlers: make(map[string]http.Handler),
+		agentRequestHandlers: make(map[string]a2asrv.RequestHandler),
+		agentExecutors:       make(map[string]*Executor),
 		agentCardHandlers:    make(map[string]http.Handler),
 		agentCards:           make(map[string]*a2a.AgentCard),
 		agentGRPCHandlers:    make(map[string]*a2agrpc.Handler),
+		streams:              newStreamTracker(),
 	}
 
 	// Apply options
 	for _, opt := range opts {
 		opt(s)
 	}
+	if s.taskStore == nil {
+		s.taskStore = task.NewInMemoryTaskStore()
+	}
 
 	// Build handlers using a2a-go native functions
 	s.buildAgentHandlers(executors)
@@ -141,7 +158,7 @@ func NewHTTPServer(appCfg *config.Config, executors map[string]*Executor, opts .
 
 // buildAgentHandlers creates a2a-go native handlers for each configured agent.
 func (s *HTTPServer) buildAgentHandlers(executors map[string]*Executor) {
-	baseURL := "http://" + s.serverCfg.Address()
+	baseURL := s.serverCfg.URL()
 
 	// Create auth interceptor if validator is configured
 	if s.authValidator != nil {
@@ -166,18 +183,20 @@ func (s *HTTPServer) buildAgentHandlers(executors map[string]*Executor) {
 		}
 
 		// Create a2a-go native JSON-RPC handler with agent's executor
-		// Include TaskStore if configured for persistent task storage
-		var handlerOpts []a2asrv.RequestHandlerOption
-		if s.taskStore != nil {
-			handlerOpts = append(handlerOpts, a2asrv.WithTaskStore(s.taskStore))
-		}
+		// All agents share one task store so tasks can be listed together;
+		// each sees only its own tasks
+		handlerOpts := []a2asrv.RequestHandlerOption{a2asrv.WithTaskStore(&agentTaskStore{TaskStore: s.taskStore, agent: name})}
 
 		// Add auth interceptor to bridge HTTP auth to a2a-go CallContext
 		if s.authInterceptor != nil {
 			handlerOpts = append(handlerOpts, a2asrv.WithCallInterceptor(s.authInterceptor))
 		}
 
-		requestHandler := a2asrv.NewHandler(executor, handlerOpts...)
+		requestHandler := newMethodFilterHandler(a2asrv.NewHandler(executor, handlerOpts...), s.serverCfg.A2A)
+		requestHandler = newContentModeHandler(requestHandler, agentCfg)
+		requestHandler = newContextIDHandler(requestHandler, executor.config.ContextIDGenerator)
+		s.agentRequestHandlers[name] = requestHandler
+		s.agentExecutors[name] = executor
 
 		// Create transport-specific handlers based on config
 		if s.serverCfg.Transport == config.TransportGRPC {
@@ -189,7 +208,7 @@ func (s *HTTPServer) buildAgentHandlers(executors map[string]*Executor) {
 		}
 
 		// Create a2a-go native agent card handler
-		s.agentCardHandlers[name] = a2asrv.NewStaticAgentCardHandler(card)
+		s.agentCardHandlers[name] = withStaticHeaders(a2asrv.NewStaticAgentCardHandler(card), s.cardHeaders(agentCfg))
 	}
 }
 
@@ -220,6 +239,9 @@ func (s *HTTPServer) buildAgentCard(name string, cfg *config.AgentConfig, url st
 			Description: cfg.Description,
 			Tags:        []string{"general", "assistant"},
 		}}
+		if len(cfg.Tags) > 0 {
+			skills[0].Tags = cfg.Tags
+		}
 	}
 
 	// Version handling
@@ -238,9 +260,10 @@ func (s *HTTPServer) buildAgentCard(name string, cfg *config.AgentConfig, url st
 		DefaultOutputModes: outputModes,
 		Skills:             skills,
 		Capabilities: a2a.AgentCapabilities{
-			Streaming:              true,
+			Streaming:              s.serverCfg.A2A.IsMethodEnabled("message/stream"),
 			PushNotifications:      false,
 			StateTransitionHistory: false,
+			Extensions:             discoveryExtensions(cfg),
 		},
 		PreferredTransport: a2a.TransportProtocolJSONRPC,
 		Provider: &a2a.AgentProvider{
@@ -251,21 +274,51 @@ func (s *HTTPServer) buildAgentCard(name string, cfg *config.AgentConfig, url st
 
 	// Add security schemes when auth is enabled (A2A spec section 5.5)
 	if s.authValidator != nil && s.serverCfg.Auth != nil && s.serverCfg.Auth.IsEnabled() {
-		card.SecuritySchemes = a2a.NamedSecuritySchemes{
-			"BearerAuth": a2a.HTTPAuthSecurityScheme{
+		card.SecuritySchemes = a2a.NamedSecuritySchemes{}
+		if s.serverCfg.Auth.HasJWT() {
+			card.SecuritySchemes["BearerAuth"] = a2a.HTTPAuthSecurityScheme{
 				Scheme:       "bearer",
 				BearerFormat: "JWT",
 				Description:  "JWT Bearer token authentication",
-			},
+			}
+			card.Security = append(card.Security, a2a.SecurityRequirements{"BearerAuth": a2a.SecuritySchemeScopes{}})
 		}
-		card.Security = []a2a.SecurityRequirements{
-			{"BearerAuth": a2a.SecuritySchemeScopes{}},
+		if keys, ok := s.authValidator.(*auth.APIKeyValidator); ok {
+			card.SecuritySchemes["APIKeyAuth"] = a2a.APIKeySecurityScheme{
+				In:          a2a.APIKeySecuritySchemeInHeader,
+				Name:        keys.Header(),
+				Description: "Static API key authentication",
+			}
+			card.Security = append(card.Security, a2a.SecurityRequirements{"APIKeyAuth": a2a.SecuritySchemeScopes{}})
 		}
 	}
 
 	return card
 }
 
+// discoveryExtensionURI identifies the agent card extension that carries
+// Hector's discovery tags and category.
+const discoveryExtensionURI = "https://github.com/kadirpekel/hector/extensions/discovery"
+
+// discoveryExtensions advertises the agent's tags and category on its card.
+func discoveryExtensions(cfg *config.AgentConfig) []a2a.AgentExtension {
+	if len(cfg.Tags) == 0 && cfg.Category == "" {
+		return nil
+	}
+	params := map[string]any{}
+	if len(cfg.Tags) > 0 {
+		params["tags"] = cfg.Tags
+	}
+	if cfg.Category != "" {
+		params["category"] = cfg.Category
+	}
+	return []a2a.AgentExtension{{
+		URI:         discoveryExtensionURI,
+		Description: "Discovery tags and category",
+		Params:      params,
+	}}
+}
+
 // buildAgentSkills converts config skills to A2A skills.
 func (s *HTTPServer) buildAgentSkills(cfg *config.AgentConfig) []a2a.AgentSkill {
 	var skills []a2a.AgentSkill
@@ -292,7 +345,7 @@ func (s *HTTPServer) Start(ctx context.Context) error {
 	// Auth middleware: validates JWT and stores claims in context
 	// Must be applied before CORS so OPTIONS preflight requests pass through
 	if s.authValidator != nil {
-		excludedPaths := []string{"/health", "/.well-known/agent-card.json", "/agents", "/agents/"}
+		excludedPaths := []string{"/", "/health", "/.well-known/agent-card.json", "/agents", "/agents/"}
 		if s.serverCfg.Auth != nil && len(s.serverCfg.Auth.ExcludedPaths) > 0 {
 			excludedPaths = s.serverCfg.Auth.ExcludedPaths
 			// Ensure defaults are always preserved unless explicitly overridden?
@@ -301,7 +354,7 @@ func (s *HTTPServer) Start(ctx context.Context) error {
 			// Note: User config should control this, but we need /agents/ excluded to support public visibility logic
 			// If user provides custom list, they might lock themselves out of public agents if they don't include /agents/
 			// Safest approach: Append critical internal exclusions to user list
-			defaults := []string{"/agents", "/agents/"}
+			defaults := []string{"/", "/health", "/agents", "/agents/"}
 			for _, d := range defaults {
 				found := false
 				for _, u := range excludedPaths {
@@ -331,19 +384,43 @@ func (s *HTTPServer) Start(ctx context.Context) error {
 		handler = observability.HTTPMiddleware(s.observability.Tracer(), s.observability.Metrics())(handler)
 	}
 
+	httpCfg := s.serverCfg.HTTP
+	if httpCfg == nil {
+		httpCfg = &config.HTTPConfig{}
+		httpCfg.SetDefaults()
+	}
+
+	// Close SSE streams that exceed the configured maximum duration
+	handler = streamDurationMiddleware(handler, time.Duration(httpCfg.MaxStreamDuration))
+
+	// Track SSE streams so shutdown can drain them
+	handler = s.streams.middleware(handler)
+
+	// Lift the write timeout for SSE streams, which last the whole agent run
+	handler = streamingDeadlineMiddleware(handler)
+
+	// Classify streaming requests once for the middlewares above
+	handler = classifyRequestMiddleware(handler)
+
 	s.server = &http.Server{
-		Addr:         s.serverCfg.Address(),
-		Handler:      handler,
-		ReadTimeout:  30 * time.Second,
-		WriteTimeout: 120 * time.Second,
-		IdleTimeout:  120 * time.Second,
+		Addr:              s.serverCfg.Address(),
+		Handler:           handler,
+		ReadTimeout:       time.Duration(httpCfg.ReadTimeout),
+		ReadHeaderTimeout: time.Duration(httpCfg.ReadHeaderTimeout),
+		WriteTimeout:      time.Duration(httpCfg.WriteTimeout),
+		IdleTimeout:       time.Duration(httpCfg.IdleTimeout),
+	}
+
+	ln, err := s.listen()
+	if err != nil {
+		return err
 	}
 
 	slog.Info("HTTP server starting", "address", s.serverCfg.Address())
 
 	errCh := make(chan error, 1)
 	go func() {
-		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := s.server.Serve(ln); err != nil && err != http.ErrServerClosed {
 			errCh <- err
 		}
 		close(errCh)
@@ -357,17 +434,76 @@ func (s *HTTPServer) Start(ctx context.Context) error {
 	}
 }
 
+// listen binds the HTTP listener to a TCP address or a Unix domain socket.
+func (s *HTTPServer) listen() (net.Listener, error) {
+	path := s.serverCfg.UnixSocket()
+	if path == "" {
+		return net.Listen("tcp", s.serverCfg.Address())
+	}
+
+	// Remove a socket left behind by an unclean shutdown. A socket that
+	// still accepts connections belongs to a running server and is kept.
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		conn, err := net.DialTimeout("unix", path, time.Second)
+		if err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("unix socket %s is in use by another process", path)
+		}
+		if !errors.Is(err, syscall.ECONNREFUSED) {
+			return nil, fmt.Errorf("failed to check socket %s: %w", path, err)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("failed to remove stale socket %s: %w", path, err)
+		}
+	}
+
+	// The socket file is removed again when the listener is closed
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on unix socket %s: %w", path, err)
+	}
+	return ln, nil
+}
+
 // Shutdown gracefully shuts down the server(s).
+//
+// New requests are refused while in-flight ones, including SSE streams and
+// WebSocket sessions, get up to server.shutdown_grace to finish. Streams
+// still open after that are ended with a final "done" event, or a stream/end
+// notification and a close frame on a WebSocket, before the remaining
+// connections close.
 func (s *HTTPServer) Shutdown(ctx context.Context) error {
-	shutdownCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	grace := time.Duration(s.serverCfg.ShutdownGrace)
+	if grace <= 0 {
+		grace = defaultShutdownGrace
+	}
+	shutdownCtx, cancel := context.WithTimeout(ctx, grace)
 	defer cancel()
 
 	var errs []error
 
 	// Shutdown HTTP server
 	if s.server != nil {
-		slog.Info("HTTP server shutting down")
-		if err := s.server.Shutdown(shutdownCtx); err != nil {
+		slog.Info("HTTP server shutting down", "grace", grace)
+		if s.streams != nil {
+			s.streams.beginDrain()
+		}
+		err := s.server.Shutdown(shutdownCtx)
+		if err == nil && s.streams != nil {
+			// Shutdown does not wait for hijacked WebSocket connections
+			deadline, _ := shutdownCtx.Deadline()
+			if !s.streams.wait(time.Until(deadline)) {
+				err = context.DeadlineExceeded
+			}
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			slog.Warn("Shutdown grace period ended, closing open streams", "grace", grace)
+			if s.streams != nil && !s.streams.stopAll() {
+				slog.Warn("Streams did not stop in time, closing connections")
+			}
+			err = s.server.Close()
+		}
+		if err != nil {
 			errs = append(errs, fmt.Errorf("HTTP shutdown error: %w", err))
 		}
 	}
@@ -402,6 +538,11 @@ func (s *HTTPServer) Address() string {
 	return s.serverCfg.Address()
 }
 
+// URL returns the base URL advertised to clients.
+func (s *HTTPServer) URL() string {
+	return s.serverCfg.URL()
+}
+
 // GRPCAddress returns the gRPC server address (if enabled).
 func (s *HTTPServer) GRPCAddress() string {
 	if s.serverCfg.Transport == config.TransportGRPC {
@@ -414,6 +555,7 @@ func (s *HTTPServer) GRPCAddress() string {
 // A2A spec compliant paths:
 //   - GET  /.well-known/agent-card.json  → Default agent card (a2a-go native)
 //   - GET  /agents                       → Discovery (Hector extension)
+//   - GET  /tasks                        → Task listing (Hector extension)
 //   - GET  /agents/{name}                → Agent card (a2a-go native)
 //   - POST /agents/{name}                → JSON-RPC (a2a-go native)
 //   - GET  /agents/{name}/.well-known/agent-card.json → Agent card (a2a-go native)
@@ -449,6 +591,13 @@ func (s *HTTPServer) setupRoutes() *http.ServeMux {
 	// Per-agent routes using a2a-go native handlers
 	mux.HandleFunc("/agents/", s.handleAgentRoutes)
 
+	// Task listing across agents - Hector extension
+	mux.HandleFunc("/tasks", s.handleListTasks)
+
+	// OpenAI-compatible chat completions, with agents as models
+	mux.HandleFunc("/v1/chat/completions", s.handleChatCompletions)
+	mux.HandleFunc("/v1/models", s.handleModels)
+
 	return mux
 }
 
@@ -520,6 +669,44 @@ func (s *HTTPServer) handleGetSchema(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// cardHeaders returns the static headers for an agent card, or for the
+// discovery endpoint when cfg is nil: server.a2a.card_headers overridden by
+// the agent's card_headers.
+func (s *HTTPServer) cardHeaders(cfg *config.AgentConfig) map[string]string {
+	headers := make(map[string]string)
+	if s.serverCfg.A2A != nil {
+		maps.Copy(headers, s.serverCfg.A2A.CardHeaders)
+	}
+	if cfg != nil {
+		maps.Copy(headers, cfg.CardHeaders)
+
+		// Internal and scoped cards require auth, so shared caches must key on it
+		if (cfg.Visibility == "internal" || len(cfg.RequiredScopes) > 0) && len(headers) > 0 {
+			if _, ok := headers["Vary"]; !ok {
+				headers["Vary"] = "Authorization"
+			}
+		}
+	}
+	return headers
+}
+
+// withStaticHeaders wraps handler to set headers on every response.
+func withStaticHeaders(handler http.Handler, headers map[string]string) http.Handler {
+	if len(headers) == 0 {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		setStaticHeaders(w, headers)
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func setStaticHeaders(w http.ResponseWriter, headers map[string]string) {
+	for name, value := range headers {
+		w.Header().Set(name, value)
+	}
+}
+
 // handleDefaultAgentCard serves the default agent's card at the server-level well-known path.
 // Per A2A spec 5.3: "https://{server_domain}/.well-known/agent-card.json"
 // For multi-agent servers, this returns the first configured agent.
@@ -536,7 +723,8 @@ func (s *HTTPServer) handleDefaultAgentCard(w http.ResponseWriter, r *http.Reque
 }
 
 // handleDiscovery returns all agents (Hector extension).
-// Filters agents based on their visibility configuration and authentication status.
+// Filters agents based on their visibility, required scopes and the caller's credentials.
+// ?tag= (repeatable, all must match) and ?category= narrow the list.
 func (s *HTTPServer) handleDiscovery(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
@@ -547,22 +735,10 @@ func (s *HTTPServer) handleDiscovery(w http.ResponseWriter, r *http.Request) {
 	defer s.mu.RUnlock()
 
 	// Check authentication (soft check)
-	isAuthenticated := false
-	if s.authValidator != nil {
-		authHeader := r.Header.Get("Authorization")
-		if authHeader != "" {
-			token := strings.TrimPrefix(authHeader, "Bearer ")
-			if _, err := s.authValidator.ValidateToken(r.Context(), token); err == nil {
-				isAuthenticated = true
-			}
-		}
-	} else {
-		// No auth configured = effectively authenticated (or no auth concept)
-		// But in "visibility" context, if auth is disabled, "internal" implies "hidden from public"?
-		// Or "internal" assumes trusted network?
-		// Common pattern: If Auth OFF, everything is public/trusted.
-		isAuthenticated = true
-	}
+	claims := s.requestClaims(r)
+
+	query := r.URL.Query()
+	tags, category := query["tag"], query.Get("category")
 
 	agents := make([]*a2a.AgentCard, 0, len(s.agentCards))
 	for name, card := range s.agentCards {
@@ -570,30 +746,25 @@ func (s *HTTPServer) handleDiscovery(w http.ResponseWriter, r *http.Request) {
 		if !ok {
 			continue // Should not happen
 		}
-
-		visibility := cfg.Visibility
-		if visibility == "" {
-			visibility = "public"
+		if !matchesDiscoveryFilter(cfg, tags, category) {
+			continue
 		}
 
-		switch visibility {
-		case "public":
-			// Always visible
-			agents = append(agents, card)
-		case "internal":
-			// Visible only if authenticated
-			if isAuthenticated {
-				agents = append(agents, card)
-			}
-		case "private":
-			// Never visible in discovery
-			// (Hidden from list, but potentially callable internally)
-		default:
-			// Default to public behavior
-			agents = append(agents, card)
+		// Hide agents the caller could not call
+		if s.agentAccessStatus(cfg, claims) != 0 {
+			continue
 		}
+		agents = append(agents, card)
 	}
 
+	setStaticHeaders(w, s.cardHeaders(nil))
+	if s.authValidator != nil {
+		// The list depends on the caller, so shared caches must key on it
+		w.Header().Add("Vary", "Authorization")
+		if keys, ok := s.authValidator.(*auth.APIKeyValidator); ok {
+			w.Header().Add("Vary", keys.Header())
+		}
+	}
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(map[string]any{
 		"agents": agents,
@@ -601,6 +772,61 @@ func (s *HTTPServer) handleDiscovery(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// requestClaims authenticates the request without rejecting it. It returns
+// nil when auth is disabled or the credentials are missing or invalid.
+func (s *HTTPServer) requestClaims(r *http.Request) *auth.Claims {
+	if s.authValidator == nil {
+		return nil
+	}
+	if claims := auth.ClaimsFromContext(r.Context()); claims != nil {
+		return claims // Already validated by the auth middleware
+	}
+	claims, err := auth.AuthenticateRequest(r, s.authValidator)
+	if err != nil {
+		return nil
+	}
+	return claims
+}
+
+// agentAccessStatus returns the HTTP status denying the caller access to an
+// agent, or 0 when access is allowed:
+//   - private agents are never reachable (404)
+//   - internal and scoped agents require credentials (401)
+//   - scoped agents require every required scope (403)
+//
+// With auth disabled everything but private agents is trusted.
+func (s *HTTPServer) agentAccessStatus(cfg *config.AgentConfig, claims *auth.Claims) int {
+	if cfg.Visibility == "private" {
+		return http.StatusNotFound
+	}
+	if s.authValidator == nil {
+		return 0
+	}
+	if cfg.Visibility != "internal" && len(cfg.RequiredScopes) == 0 {
+		return 0
+	}
+	if claims == nil {
+		return http.StatusUnauthorized
+	}
+	if !claims.HasScopes(cfg.RequiredScopes...) {
+		return http.StatusForbidden
+	}
+	return 0
+}
+
+// matchesDiscoveryFilter reports whether an agent has all tags and the category.
+func matchesDiscoveryFilter(cfg *config.AgentConfig, tags []string, category string) bool {
+	if category != "" && cfg.Category != category {
+		return false
+	}
+	for _, tag := range tags {
+		if !slices.Contains(cfg.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
 // handleAgentRoutes routes to a2a-go native handlers.
 func (s *HTTPServer) handleAgentRoutes(w http.ResponseWriter, r *http.Request) {
 	// Parse: /agents/{name}[/...]
@@ -628,46 +854,63 @@ func (s *HTTPServer) handleAgentRoutes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Check Access Control based on Visibility
-	cfg, ok := s.appCfg.Agents[agentName]
-	if ok {
-		switch cfg.Visibility {
-		case "private":
+	// Check Access Control based on Visibility and required scopes
+	claims := s.requestClaims(r)
+	if cfg, ok := s.appCfg.Agents[agentName]; ok {
+		switch s.agentAccessStatus(cfg, claims) {
+		case http.StatusNotFound:
 			// Private agents are hidden from HTTP entirely.
 			// Treat as 404 to avoid leaking existence.
 			s.mu.RUnlock()
 			http.NotFound(w, r)
 			return
-		case "internal":
-			// Internal agents require authentication
-			if s.authValidator != nil {
-				authHeader := r.Header.Get("Authorization")
-				authorized := false
-				if authHeader != "" {
-					token := strings.TrimPrefix(authHeader, "Bearer ")
-					if _, err := s.authValidator.ValidateToken(r.Context(), token); err == nil {
-						authorized = true
-					}
-				}
-				if !authorized {
-					s.mu.RUnlock()
-					http.Error(w, "Unauthorized: agent is internal", http.StatusUnauthorized)
-					return
-				}
-			}
-		case "public", "":
-			// Public access allowed (no check needed)
+		case http.StatusUnauthorized:
+			s.mu.RUnlock()
+			http.Error(w, "Unauthorized: agent requires authentication", http.StatusUnauthorized)
+			return
+		case http.StatusForbidden:
+			s.mu.RUnlock()
+			http.Error(w, "Forbidden: missing required scopes", http.StatusForbidden)
+			return
 		}
 	}
 
 	cardHandler := s.agentCardHandlers[agentName]
+	requestHandler := s.agentRequestHandlers[agentName]
+	executor := s.agentExecutors[agentName]
+	appCfg := s.appCfg
+	batchCfg := s.serverCfg.Batch
 	s.mu.RUnlock()
 
+	// /agents/ skips the auth middleware, so carry the caller's claims to
+	// the a2a-go interceptor and the task store
+	if claims != nil {
+		r = r.WithContext(auth.ContextWithClaims(r.Context(), claims))
+	}
+
 	switch {
 	case subPath == "" || subPath == "/":
 		// POST: JSON-RPC (a2a-go native handler)
 		// GET: Agent card (a2a-go native handler)
 		if r.Method == http.MethodPost {
+			method, id, params, err := parseJSONRPCRequest(w, r)
+			if err != nil {
+				if maxErr := (*http.MaxBytesError)(nil); errors.As(err, &maxErr) {
+					http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+					return
+				}
+				http.Error(w, "Failed to read request body", http.StatusBadRequest)
+				return
+			}
+			switch method {
+			case methodTasksList:
+				s.handleJSONRPCListTasks(w, r, agentName, id, params)
+				return
+			case methodTasksGet:
+				// Polling clients tolerate a lagging replica; the task
+				// manager's own reads stay on the primary
+				r = r.WithContext(config.WithReplicaReads(r.Context()))
+			}
 			jsonRPCHandler.ServeHTTP(w, r)
 			return
 		}
@@ -681,6 +924,39 @@ func (s *HTTPServer) handleAgentRoutes(w http.ResponseWriter, r *http.Request) {
 		// A2A spec: /.well-known/agent-card.json (a2a-go native handler)
 		cardHandler.ServeHTTP(w, r)
 
+	case subPath == "/resume":
+		// HITL: approve or deny a paused tool call by resume token
+		s.handleResume(w, r, requestHandler)
+
+	case subPath == "/ws":
+		// Bidirectional JSON-RPC streaming over a WebSocket
+		s.handleWebSocket(w, r, requestHandler)
+
+	case subPath == "/messages:batch":
+		// Send many messages in one request with bounded concurrency
+		s.handleBatch(w, r, requestHandler, batchCfg)
+
+	case strings.HasPrefix(subPath, "/sessions/") && strings.HasSuffix(subPath, "/usage"):
+		// Token usage and estimated cost of a session
+		sessionID := strings.TrimSuffix(strings.TrimPrefix(subPath, "/sessions/"), "/usage")
+		if sessionID == "" || strings.Contains(sessionID, "/") {
+			http.NotFound(w, r)
+			return
+		}
+		s.handleSessionUsage(w, r, executor, appCfg.ModelPricing(), sessionID)
+
+	case strings.HasPrefix(subPath, "/sessions/") && strings.Contains(subPath, "/checkpoints"):
+		// Checkpoint listing and manual restore of a session
+		parts := strings.Split(strings.TrimPrefix(subPath, "/sessions/"), "/")
+		switch {
+		case len(parts) == 2 && parts[0] != "" && parts[1] == "checkpoints":
+			s.handleListCheckpoints(w, r, executor, parts[0])
+		case len(parts) == 4 && parts[0] != "" && parts[1] == "checkpoints" && parts[2] != "" && parts[3] == "restore":
+			s.handleRestoreCheckpoint(w, r, executor, parts[0], parts[2])
+		default:
+			http.NotFound(w, r)
+		}
+
 	default:
 		http.NotFound(w, r)
 	}
@@ -689,12 +965,16 @@ func (s *HTTPServer) handleAgentRoutes(w http.ResponseWriter, r *http.Request) {
 // corsMiddleware adds CORS headers.
 func (s *HTTPServer) corsMiddleware(next http.Handler) http.Handler {
 	cors := s.serverCfg.CORS
+	allowHeaders := "Content-Type, Authorization"
+	if keys, ok := s.authValidator.(*auth.APIKeyValidator); ok {
+		allowHeaders += ", " + keys.Header()
+	}
 	if cors == nil {
 		// Default permissive CORS for development
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Allow-Headers", allowHeaders)
 
 			if r.Method == http.MethodOptions {
 				w.WriteHeader(http.StatusNoContent)
@@ -717,7 +997,7 @@ func (s *HTTPServer) corsMiddleware(next http.Handler) http.Handler {
 		}
 
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", allowHeaders)
 		if config.BoolValue(cors.AllowCredentials, false) {
 			w.Header().Set("Access-Control-Allow-Credentials", "true")
 		}
@@ -745,6 +1025,161 @@ func (s *HTTPServer) loggingMiddleware(next http.Handler) http.Handler {
 	})
 }
 
+// streamingMethods are JSON-RPC methods that respond with an SSE stream.
+var streamingMethods = map[string]bool{
+	"message/stream":    true,
+	"tasks/resubscribe": true,
+}
+
+// streamingDeadlineMiddleware clears the server write deadline for streaming
+// requests. The configured WriteTimeout bounds non-streaming responses, but an
+// SSE stream stays open for the entire agent run and must not be cut off.
+func streamingDeadlineMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requestInfoFrom(r).streaming {
+			if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+				slog.Debug("Could not clear write deadline for streaming request", "path", r.URL.Path, "error", err)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// errStreamDurationExceeded cancels a stream that reached MaxStreamDuration.
+var errStreamDurationExceeded = errors.New("maximum stream duration exceeded")
+
+// streamDurationErrorCode is the JSON-RPC error code of the final event sent
+// when a stream reaches its maximum duration.
+const streamDurationErrorCode = -32000
+
+// streamDurationMiddleware closes streaming responses after maxDuration.
+//
+// The request context is cancelled when the limit is reached, which ends the
+// stream without stopping the task: a2a-go runs executions detached from the
+// request. A final JSON-RPC error event tells the client why the stream
+// closed so it can continue with tasks/resubscribe.
+func streamDurationMiddleware(next http.Handler, maxDuration time.Duration) http.Handler {
+	if maxDuration <= 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info := requestInfoFrom(r)
+		if !info.streaming {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, cancel := context.WithTimeoutCause(r.Context(), maxDuration, errStreamDurationExceeded)
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+
+		if !errors.Is(context.Cause(ctx), errStreamDurationExceeded) {
+			return
+		}
+		slog.Debug("Closing stream at maximum duration", "path", r.URL.Path, "max_stream_duration", maxDuration)
+		writeStreamDurationEvent(w, info.id, maxDuration)
+	})
+}
+
+// writeStreamDurationEvent sends the final event of a stream closed at its
+// maximum duration.
+func writeStreamDurationEvent(w http.ResponseWriter, id json.RawMessage, maxDuration time.Duration) {
+	writeStreamCloseEvent(w, "stream_closed", id, errStreamDurationExceeded.Error(), map[string]any{
+		"reason":              "max_stream_duration",
+		"max_stream_duration": maxDuration.String(),
+		"resume_method":       "tasks/resubscribe",
+	})
+}
+
+// writeStreamCloseEvent sends a final SSE event carrying a JSON-RPC error
+// that tells the client why the server closed the stream.
+func writeStreamCloseEvent(w http.ResponseWriter, event string, id json.RawMessage, reason string, data map[string]any) {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	payload, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"error": map[string]any{
+			"code":    streamDurationErrorCode,
+			"message": "stream closed: " + reason,
+			"data":    data,
+		},
+	})
+	if err != nil {
+		return
+	}
+	_, _ = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload)
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// requestInfo is what the outer middlewares need to know about a request.
+// It is read from the body once, by classifyRequestMiddleware.
+type requestInfo struct {
+	// streaming is set for requests answered with an SSE stream
+	streaming bool
+
+	// id is the JSON-RPC request id, if any
+	id json.RawMessage
+}
+
+type requestInfoKey struct{}
+
+// classifyRequestMiddleware reads POST bodies once, capped at
+// maxRequestBodyBytes, and stores the resulting requestInfo in the request
+// context for the stream middlewares. The body is restored so downstream
+// handlers can read it. It runs before authentication, so an oversized body
+// is rejected here rather than buffered.
+func classifyRequestMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info, err := classifyRequest(w, r)
+		if maxErr := (*http.MaxBytesError)(nil); errors.As(err, &maxErr) {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestInfoKey{}, info)))
+	})
+}
+
+// classifyRequest reads the body of a POST request and reports whether it
+// is a JSON-RPC streaming call or a streaming chat completion.
+func classifyRequest(w http.ResponseWriter, r *http.Request) (requestInfo, error) {
+	var info requestInfo
+	if r.Method != http.MethodPost || r.Body == nil {
+		return info, nil
+	}
+	info.streaming = strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes))
+	_ = r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return info, err
+	}
+
+	var payload struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Stream bool            `json:"stream"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return info, nil
+	}
+	info.id = payload.ID
+	if streamingMethods[payload.Method] || (r.URL.Path == "/v1/chat/completions" && payload.Stream) {
+		info.streaming = true
+	}
+	return info, nil
+}
+
+// requestInfoFrom returns the requestInfo stored by classifyRequestMiddleware.
+func requestInfoFrom(r *http.Request) requestInfo {
+	info, _ := r.Context().Value(requestInfoKey{}).(requestInfo)
+	return info
+}
+
 // UpdateExecutors atomically updates configuration and agent executors (for hot-reload).
 func (s *HTTPServer) UpdateExecutors(cfg *config.Config, executors map[string]*Executor) {
 	s.mu.Lock()
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kadirpekel/hector/pkg/config"
+)
+
+func TestListenUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hector.sock")
+
+	// A stale socket from a previous run must not prevent binding
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("failed to create stale socket: %v", err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	cfg := &config.ServerConfig{Listen: "unix://" + path}
+	cfg.SetDefaults()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if got := cfg.URL(); got != "http://localhost" {
+		t.Errorf("URL() = %q, want http://localhost", got)
+	}
+
+	s := &HTTPServer{serverCfg: cfg}
+	ln, err := s.listen()
+	if err != nil {
+		t.Fatalf("listen() error = %v", err)
+	}
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})}
+	go srv.Serve(ln)
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", path)
+		},
+	}}
+	resp, err := client.Get("http://localhost/health")
+	if err != nil {
+		t.Fatalf("request over socket failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Errorf("status = %d, want 204", resp.StatusCode)
+	}
+
+	// A socket a running server still accepts on is not taken over
+	if _, err := s.listen(); err == nil || !strings.Contains(err.Error(), "in use") {
+		t.Errorf("listen() on a live socket error = %v, want in use", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("live socket file removed: %v", err)
+	}
+
+	srv.Close()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("socket file not removed on close: %v", err)
+	}
+}
+
+func TestListenAddressOverridesHostAndPort(t *testing.T) {
+	cfg := &config.ServerConfig{Listen: "127.0.0.1:9090"}
+	cfg.SetDefaults()
+	if got := cfg.Address(); got != "127.0.0.1:9090" {
+		t.Errorf("Address() = %q, want 127.0.0.1:9090", got)
+	}
+
+	for _, listen := range []string{"unix://", "localhost", "localhost:http"} {
+		cfg := &config.ServerConfig{Listen: listen}
+		cfg.SetDefaults()
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("Validate(%q) succeeded, want error", listen)
+		}
+	}
+}
+
+func TestClassifyRequestMiddleware(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		body      string
+		streaming bool
+	}{
+		{"json-rpc stream", "/agents/assistant", `{"jsonrpc":"2.0","id":1,"method":"message/stream"}`, true},
+		{"json-rpc send", "/agents/assistant", `{"jsonrpc":"2.0","id":1,"method":"message/send"}`, false},
+		{"chat completion stream", "/v1/chat/completions", `{"model":"assistant","stream":true}`, true},
+		{"chat completion", "/v1/chat/completions", `{"model":"assistant"}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var info requestInfo
+			var body []byte
+			handler := classifyRequestMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				info = requestInfoFrom(r)
+				body, _ = io.ReadAll(r.Body)
+			}))
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body)))
+			if info.streaming != tt.streaming {
+				t.Errorf("streaming = %v, want %v", info.streaming, tt.streaming)
+			}
+			if string(body) != tt.body {
+				t.Errorf("downstream body = %q, want it restored", body)
+			}
+		})
+	}
+
+	// Oversized bodies are rejected before they reach authentication
+	called := false
+	handler := classifyRequestMiddleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { called = true }))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/agents/assistant", strings.NewReader(strings.Repeat("x", maxRequestBodyBytes+1))))
+	if rec.Code != http.StatusRequestEntityTooLarge || called {
+		t.Errorf("oversized body: status %d, handler called %v; want 413 and no call", rec.Code, called)
+	}
+}
+
+func TestStreamDurationMiddleware(t *testing.T) {
+	stream := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = w.Write([]byte("data: {}\n\n"))
+		<-r.Context().Done()
+	})
+	handler := classifyRequestMiddleware(streamDurationMiddleware(stream, 20*time.Millisecond))
+
+	body := `{"jsonrpc":"2.0","id":7,"method":"message/stream","params":{}}`
+	req := httptest.NewRequest(http.MethodPost, "/agents/assistant", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	out := rec.Body.String()
+	if !strings.Contains(out, "event: stream_closed") || !strings.Contains(out, `"id":7`) {
+		t.Errorf("missing final stream_closed event, got %q", out)
+	}
+	if !strings.Contains(out, "tasks/resubscribe") {
+		t.Errorf("final event should point to tasks/resubscribe, got %q", out)
+	}
+
+	// Non-streaming requests are not limited
+	req = httptest.NewRequest(http.MethodGet, "/health", nil)
+	rec = httptest.NewRecorder()
+	streamDurationMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Deadline(); ok {
+			t.Error("non-streaming request got a deadline")
+		}
+	}), time.Millisecond).ServeHTTP(rec, req)
+}
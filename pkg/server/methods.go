@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"iter"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+
+	"github.com/kadirpekel/hector/pkg/config"
+)
+
+// methodFilterHandler refuses A2A methods disabled in the server config with
+// a2a.ErrUnsupportedOperation, which every transport maps to its standard
+// "unsupported operation" error.
+type methodFilterHandler struct {
+	a2asrv.RequestHandler
+	cfg *config.A2AConfig
+}
+
+// newMethodFilterHandler wraps handler when any method is disabled.
+func newMethodFilterHandler(handler a2asrv.RequestHandler, cfg *config.A2AConfig) a2asrv.RequestHandler {
+	if cfg == nil || len(cfg.DisabledMethods) == 0 {
+		return handler
+	}
+	return &methodFilterHandler{RequestHandler: handler, cfg: cfg}
+}
+
+func (h *methodFilterHandler) OnGetTask(ctx context.Context, query *a2a.TaskQueryParams) (*a2a.Task, error) {
+	if !h.cfg.IsMethodEnabled("tasks/get") {
+		return nil, a2a.ErrUnsupportedOperation
+	}
+	return h.RequestHandler.OnGetTask(ctx, query)
+}
+
+func (h *methodFilterHandler) OnCancelTask(ctx context.Context, id *a2a.TaskIDParams) (*a2a.Task, error) {
+	if !h.cfg.IsMethodEnabled("tasks/cancel") {
+		return nil, a2a.ErrUnsupportedOperation
+	}
+	return h.RequestHandler.OnCancelTask(ctx, id)
+}
+
+func (h *methodFilterHandler) OnResubscribeToTask(ctx context.Context, id *a2a.TaskIDParams) iter.Seq2[a2a.Event, error] {
+	if !h.cfg.IsMethodEnabled("tasks/resubscribe") {
+		return unsupportedEvents
+	}
+	return h.RequestHandler.OnResubscribeToTask(ctx, id)
+}
+
+func (h *methodFilterHandler) OnSendMessageStream(ctx context.Context, message *a2a.MessageSendParams) iter.Seq2[a2a.Event, error] {
+	if !h.cfg.IsMethodEnabled("message/stream") {
+		return unsupportedEvents
+	}
+	return h.RequestHandler.OnSendMessageStream(ctx, message)
+}
+
+func (h *methodFilterHandler) OnGetTaskPushConfig(ctx context.Context, params *a2a.GetTaskPushConfigParams) (*a2a.TaskPushConfig, error) {
+	if !h.cfg.IsMethodEnabled("tasks/pushNotificationConfig/get") {
+		return nil, a2a.ErrUnsupportedOperation
+	}
+	return h.RequestHandler.OnGetTaskPushConfig(ctx, params)
+}
+
+func (h *methodFilterHandler) OnListTaskPushConfig(ctx context.Context, params *a2a.ListTaskPushConfigParams) ([]*a2a.TaskPushConfig, error) {
+	if !h.cfg.IsMethodEnabled("tasks/pushNotificationConfig/list") {
+		return nil, a2a.ErrUnsupportedOperation
+	}
+	return h.RequestHandler.OnListTaskPushConfig(ctx, params)
+}
+
+func (h *methodFilterHandler) OnSetTaskPushConfig(ctx context.Context, params *a2a.TaskPushConfig) (*a2a.TaskPushConfig, error) {
+	if !h.cfg.IsMethodEnabled("tasks/pushNotificationConfig/set") {
+		return nil, a2a.ErrUnsupportedOperation
+	}
+	return h.RequestHandler.OnSetTaskPushConfig(ctx, params)
+}
+
+func (h *methodFilterHandler) OnDeleteTaskPushConfig(ctx context.Context, params *a2a.DeleteTaskPushConfigParams) error {
+	if !h.cfg.IsMethodEnabled("tasks/pushNotificationConfig/delete") {
+		return a2a.ErrUnsupportedOperation
+	}
+	return h.RequestHandler.OnDeleteTaskPushConfig(ctx, params)
+}
+
+func (h *methodFilterHandler) OnGetExtendedAgentCard(ctx context.Context) (*a2a.AgentCard, error) {
+	if !h.cfg.IsMethodEnabled("agent/getAuthenticatedExtendedCard") {
+		return nil, a2a.ErrUnsupportedOperation
+	}
+	return h.RequestHandler.OnGetExtendedAgentCard(ctx)
+}
+
+// unsupportedEvents is the event stream of a disabled streaming method.
+func unsupportedEvents(yield func(a2a.Event, error) bool) {
+	yield(nil, a2a.ErrUnsupportedOperation)
+}
+
+var _ a2asrv.RequestHandler = (*methodFilterHandler)(nil)
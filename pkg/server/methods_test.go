@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/config"
+)
+
+func TestMethodFilterHandler(t *testing.T) {
+	cfg := &config.A2AConfig{DisabledMethods: []string{"tasks/get", "message/stream"}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	// Disabled methods must not reach the wrapped handler (nil here)
+	h := newMethodFilterHandler(nil, cfg)
+
+	if _, err := h.OnGetTask(context.Background(), &a2a.TaskQueryParams{ID: "t1"}); !errors.Is(err, a2a.ErrUnsupportedOperation) {
+		t.Errorf("OnGetTask() error = %v, want ErrUnsupportedOperation", err)
+	}
+	for _, err := range h.OnSendMessageStream(context.Background(), &a2a.MessageSendParams{}) {
+		if !errors.Is(err, a2a.ErrUnsupportedOperation) {
+			t.Errorf("OnSendMessageStream() error = %v, want ErrUnsupportedOperation", err)
+		}
+	}
+}
+
+func TestA2AConfigRejectsRequiredMethods(t *testing.T) {
+	cfg := &config.A2AConfig{DisabledMethods: []string{"message/send"}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Expected error when disabling message/send")
+	}
+}
+
+func TestAgentCardReflectsDisabledStreaming(t *testing.T) {
+	appCfg := &config.Config{
+		Server: config.ServerConfig{A2A: &config.A2AConfig{DisabledMethods: []string{"message/stream"}}},
+		Agents: map[string]*config.AgentConfig{"assistant": {}},
+	}
+	s := NewHTTPServer(appCfg, nil)
+
+	if s.agentCards["assistant"].Capabilities.Streaming {
+		t.Error("Expected streaming capability to be disabled")
+	}
+}
+
+func TestContentModeHandler(t *testing.T) {
+	cfg := &config.AgentConfig{InputModes: []string{"text/plain"}, OutputModes: []string{"text/*"}}
+
+	// Rejected requests must not reach the wrapped handler (nil here)
+	h := newContentModeHandler(nil, cfg)
+
+	image := a2a.FilePart{File: a2a.FileBytes{FileMeta: a2a.FileMeta{MimeType: "image/png"}, Bytes: "iVBORw0KGgo="}}
+	params := &a2a.MessageSendParams{Message: a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "Describe"}, image)}
+	if _, err := h.OnSendMessage(context.Background(), params); !errors.Is(err, a2a.ErrUnsupportedContentType) {
+		t.Errorf("OnSendMessage() error = %v, want ErrUnsupportedContentType", err)
+	}
+
+	params = &a2a.MessageSendParams{
+		Message: a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "Hi"}),
+		Config:  &a2a.MessageSendConfig{AcceptedOutputModes: []string{"audio/mpeg"}},
+	}
+	for _, err := range h.OnSendMessageStream(context.Background(), params) {
+		if !errors.Is(err, a2a.ErrUnsupportedContentType) {
+			t.Errorf("OnSendMessageStream() error = %v, want ErrUnsupportedContentType", err)
+		}
+	}
+
+	if status := resumeErrorStatus(h.(*contentModeHandler).check(params)); status != 415 {
+		t.Errorf("resumeErrorStatus() = %d, want 415", status)
+	}
+
+	ok := &a2a.MessageSendParams{
+		Message: a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "Hi"}, a2a.DataPart{Data: map[string]any{"type": "tool_approval"}}),
+		Config:  &a2a.MessageSendConfig{AcceptedOutputModes: []string{"text/plain; charset=utf-8"}},
+	}
+	if err := h.(*contentModeHandler).check(ok); err != nil {
+		t.Errorf("check() error = %v, want nil", err)
+	}
+
+	// No declared modes: permissive, handler is not wrapped
+	if got := newContentModeHandler(nil, &config.AgentConfig{}); got != nil {
+		t.Errorf("Expected unwrapped handler, got %T", got)
+	}
+}
+
+func TestContextIDHandler(t *testing.T) {
+	if h := newContextIDHandler(nil, nil); h != nil {
+		t.Error("Expected no wrapper without a generator")
+	}
+
+	h := &contextIDHandler{generate: func() string { return "ctx-1" }}
+
+	fresh := &a2a.MessageSendParams{Message: a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "Hi"})}
+	h.assign(fresh)
+	if fresh.Message.ContextID != "ctx-1" {
+		t.Errorf("ContextID = %q, want ctx-1", fresh.Message.ContextID)
+	}
+
+	existing := &a2a.MessageSendParams{Message: a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "Hi"})}
+	existing.Message.ContextID = "client"
+	h.assign(existing)
+	if existing.Message.ContextID != "client" {
+		t.Errorf("ContextID = %q, want client", existing.Message.ContextID)
+	}
+
+	continued := &a2a.MessageSendParams{Message: a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "Hi"})}
+	continued.Message.TaskID = "task-1"
+	h.assign(continued)
+	if continued.Message.ContextID != "" {
+		t.Errorf("ContextID = %q, want empty for task continuations", continued.Message.ContextID)
+	}
+}
@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"mime"
+	"strings"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+
+	"github.com/kadirpekel/hector/pkg/config"
+)
+
+// contentModeHandler enforces the agent's declared input and output modes.
+//
+// Messages with parts outside InputModes, or whose accepted output modes
+// don't overlap OutputModes, are refused with a2a.ErrUnsupportedContentType.
+// JSON-RPC maps it to "incompatible content types"; REST endpoints to 415.
+type contentModeHandler struct {
+	a2asrv.RequestHandler
+	inputModes  []string
+	outputModes []string
+}
+
+// newContentModeHandler wraps handler when the agent declares any modes.
+// Agents without declared modes accept everything.
+func newContentModeHandler(handler a2asrv.RequestHandler, cfg *config.AgentConfig) a2asrv.RequestHandler {
+	if cfg == nil || (len(cfg.InputModes) == 0 && len(cfg.OutputModes) == 0) {
+		return handler
+	}
+	return &contentModeHandler{
+		RequestHandler: handler,
+		inputModes:     cfg.InputModes,
+		outputModes:    cfg.OutputModes,
+	}
+}
+
+func (h *contentModeHandler) OnSendMessage(ctx context.Context, params *a2a.MessageSendParams) (a2a.SendMessageResult, error) {
+	if err := h.check(params); err != nil {
+		return nil, err
+	}
+	return h.RequestHandler.OnSendMessage(ctx, params)
+}
+
+func (h *contentModeHandler) OnSendMessageStream(ctx context.Context, params *a2a.MessageSendParams) iter.Seq2[a2a.Event, error] {
+	if err := h.check(params); err != nil {
+		return func(yield func(a2a.Event, error) bool) {
+			yield(nil, err)
+		}
+	}
+	return h.RequestHandler.OnSendMessageStream(ctx, params)
+}
+
+// check validates the message parts and the client's accepted output modes.
+func (h *contentModeHandler) check(params *a2a.MessageSendParams) error {
+	if params == nil {
+		return nil
+	}
+
+	if len(h.inputModes) > 0 && params.Message != nil {
+		for _, part := range params.Message.Parts {
+			if isControlPart(part) {
+				continue
+			}
+			mimeType := partMimeType(part)
+			if !matchesAnyMode(mimeType, h.inputModes) {
+				return fmt.Errorf("%w: input %s is not accepted by this agent (accepts %s)",
+					a2a.ErrUnsupportedContentType, mimeType, strings.Join(h.inputModes, ", "))
+			}
+		}
+	}
+
+	if len(h.outputModes) > 0 && params.Config != nil && len(params.Config.AcceptedOutputModes) > 0 {
+		for _, accepted := range params.Config.AcceptedOutputModes {
+			if matchesAnyMode(accepted, h.outputModes) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: none of the accepted output modes (%s) is produced by this agent (produces %s)",
+			a2a.ErrUnsupportedContentType,
+			strings.Join(params.Config.AcceptedOutputModes, ", "),
+			strings.Join(h.outputModes, ", "))
+	}
+
+	return nil
+}
+
+// isControlPart reports whether a part drives the protocol rather than
+// carrying user content (e.g., tool approvals), so modes don't apply to it.
+func isControlPart(part a2a.Part) bool {
+	dp, ok := part.(a2a.DataPart)
+	if !ok {
+		return false
+	}
+	partType, _ := dp.Data["type"].(string)
+	return partType == "tool_approval"
+}
+
+// partMimeType returns the MIME type of a message part.
+func partMimeType(part a2a.Part) string {
+	switch p := part.(type) {
+	case a2a.TextPart:
+		return "text/plain"
+	case a2a.DataPart:
+		return "application/json"
+	case a2a.FilePart:
+		switch f := p.File.(type) {
+		case a2a.FileBytes:
+			if f.MimeType != "" {
+				return f.MimeType
+			}
+		case a2a.FileURI:
+			if f.MimeType != "" {
+				return f.MimeType
+			}
+		}
+	}
+	return "application/octet-stream"
+}
+
+// matchesAnyMode reports whether mimeType matches one of modes. Both sides
+// may use wildcards ("image/*", "*/*"); parameters such as charset are ignored.
+func matchesAnyMode(mimeType string, modes []string) bool {
+	for _, mode := range modes {
+		if matchesMode(mimeType, mode) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesMode(a, b string) bool {
+	aType, aSub := splitMediaType(a)
+	bType, bSub := splitMediaType(b)
+	if aType == "*" || bType == "*" {
+		return true
+	}
+	if aType != bType {
+		return false
+	}
+	return aSub == "*" || bSub == "*" || aSub == bSub
+}
+
+func splitMediaType(s string) (string, string) {
+	if mediaType, _, err := mime.ParseMediaType(s); err == nil {
+		s = mediaType
+	}
+	s = strings.ToLower(strings.TrimSpace(s))
+	typ, sub, found := strings.Cut(s, "/")
+	if !found {
+		return typ, "*"
+	}
+	return typ, sub
+}
+
+var _ a2asrv.RequestHandler = (*contentModeHandler)(nil)
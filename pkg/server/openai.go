@@ -0,0 +1,571 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+
+	"github.com/kadirpekel/hector/pkg/auth"
+)
+
+// OpenAI-compatible chat completions.
+//
+// POST /v1/chat/completions runs the agent named by "model" as one A2A
+// message/stream call, so tooling built for the OpenAI chat API can talk to
+// Hector agents. The last message must come from the user and becomes the
+// A2A message; earlier messages are passed along as a transcript, since each
+// request starts a new conversation. Tools run on the server: the agent's
+// tool calls are reported as tool_calls for visibility, and the finish
+// reason stays "stop".
+
+// chatCompletionRequest is the body of POST /v1/chat/completions.
+type chatCompletionRequest struct {
+	Model         string             `json:"model"`
+	Messages      []chatMessage      `json:"messages"`
+	Stream        bool               `json:"stream"`
+	StreamOptions *chatStreamOptions `json:"stream_options,omitempty"`
+	User          string             `json:"user,omitempty"`
+}
+
+type chatStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// chatMessage is a message of the request. Content is a string or an array
+// of text and image_url parts.
+type chatMessage struct {
+	Role       string          `json:"role"`
+	Content    json.RawMessage `json:"content"`
+	ToolCalls  []chatToolCall  `json:"tool_calls,omitempty"`
+	ToolCallID string          `json:"tool_call_id,omitempty"`
+}
+
+type chatContentPart struct {
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	ImageURL *struct {
+		URL string `json:"url"`
+	} `json:"image_url,omitempty"`
+}
+
+type chatToolCall struct {
+	// Index is set in stream deltas only.
+	Index    *int             `json:"index,omitempty"`
+	ID       string           `json:"id"`
+	Type     string           `json:"type"`
+	Function chatFunctionCall `json:"function"`
+}
+
+type chatFunctionCall struct {
+	Name      string `json:"name"`
+	Arguments string `json:"arguments"`
+}
+
+type chatCompletion struct {
+	ID      string       `json:"id"`
+	Object  string       `json:"object"`
+	Created int64        `json:"created"`
+	Model   string       `json:"model"`
+	Choices []chatChoice `json:"choices"`
+	Usage   *chatUsage   `json:"usage,omitempty"`
+}
+
+// chatChoice is a choice of a completion (Message) or of a chunk (Delta).
+type chatChoice struct {
+	Index        int                  `json:"index"`
+	Message      *chatResponseMessage `json:"message,omitempty"`
+	Delta        *chatDelta           `json:"delta,omitempty"`
+	FinishReason *string              `json:"finish_reason"`
+}
+
+type chatResponseMessage struct {
+	Role      string         `json:"role"`
+	Content   string         `json:"content"`
+	ToolCalls []chatToolCall `json:"tool_calls,omitempty"`
+}
+
+type chatDelta struct {
+	Role      string         `json:"role,omitempty"`
+	Content   string         `json:"content,omitempty"`
+	ToolCalls []chatToolCall `json:"tool_calls,omitempty"`
+}
+
+type chatUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// handleChatCompletions serves POST /v1/chat/completions.
+func (s *HTTPServer) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeOpenAIError(w, http.StatusMethodNotAllowed, "invalid_request_error", "Method not allowed")
+		return
+	}
+
+	var req chatCompletionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeOpenAIError(w, http.StatusBadRequest, "invalid_request_error", "Invalid request body: "+err.Error())
+		return
+	}
+	claims := s.requestClaims(r)
+	handler, status := s.openAIAgent(req.Model, claims)
+	switch status {
+	case http.StatusNotFound:
+		writeOpenAIError(w, http.StatusNotFound, "invalid_request_error", fmt.Sprintf("The model %q does not exist", req.Model))
+		return
+	case http.StatusUnauthorized:
+		writeOpenAIError(w, http.StatusUnauthorized, "authentication_error", "Unauthorized: agent requires authentication")
+		return
+	case http.StatusForbidden:
+		writeOpenAIError(w, http.StatusForbidden, "permission_error", "Forbidden: missing required scopes")
+		return
+	}
+	if claims != nil {
+		r = r.WithContext(auth.ContextWithClaims(r.Context(), claims))
+	}
+	msg, err := toChatA2AMessage(req.Messages, req.User)
+	if err != nil {
+		writeOpenAIError(w, http.StatusBadRequest, "invalid_request_error", err.Error())
+		return
+	}
+
+	completion := &chatCompletion{
+		ID:      "chatcmpl-" + msg.ID,
+		Created: time.Now().Unix(),
+		Model:   req.Model,
+	}
+	events := handler.OnSendMessageStream(r.Context(), &a2a.MessageSendParams{Message: msg})
+
+	if req.Stream {
+		includeUsage := req.StreamOptions != nil && req.StreamOptions.IncludeUsage
+		streamChatCompletion(w, r, completion, events, includeUsage)
+		return
+	}
+
+	turn := newChatTurn()
+	reply := &chatResponseMessage{Role: "assistant"}
+	var content strings.Builder
+	for event, err := range events {
+		if err != nil {
+			writeOpenAIError(w, resumeErrorStatus(err), "server_error", err.Error())
+			return
+		}
+		text, calls := turn.observe(event)
+		content.WriteString(text)
+		reply.ToolCalls = append(reply.ToolCalls, calls...)
+	}
+	if turn.failure != "" {
+		writeOpenAIError(w, http.StatusInternalServerError, "server_error", turn.failure)
+		return
+	}
+	reply.Content = content.String()
+
+	stop := "stop"
+	completion.Object = "chat.completion"
+	completion.Choices = []chatChoice{{Message: reply, FinishReason: &stop}}
+	completion.Usage = turn.usage
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(completion)
+}
+
+// streamChatCompletion sends the turn as chat.completion.chunk SSE events,
+// ending with "data: [DONE]".
+func streamChatCompletion(w http.ResponseWriter, r *http.Request, completion *chatCompletion, events iter.Seq2[a2a.Event, error], includeUsage bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeOpenAIError(w, http.StatusInternalServerError, "server_error", "Streaming not supported")
+		return
+	}
+	// The stream lasts the whole agent run, like the A2A SSE endpoints
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		slog.Debug("Could not clear write deadline for chat completion stream", "path", r.URL.Path, "error", err)
+	}
+
+	completion.Object = "chat.completion.chunk"
+	send := func(v any) {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return
+		}
+		_, _ = fmt.Fprintf(w, "data: %s\n\n", data)
+		flusher.Flush()
+	}
+	chunk := func(delta *chatDelta, finishReason *string) {
+		c := *completion
+		c.Choices = []chatChoice{{Delta: delta, FinishReason: finishReason}}
+		send(c)
+	}
+
+	turn := newChatTurn()
+	started := false
+	toolCalls := 0
+	for event, err := range events {
+		if err != nil {
+			if !started {
+				writeOpenAIError(w, resumeErrorStatus(err), "server_error", err.Error())
+				return
+			}
+			send(openAIError("server_error", err.Error()))
+			return
+		}
+		if !started {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+			chunk(&chatDelta{Role: "assistant"}, nil)
+			started = true
+		}
+
+		text, calls := turn.observe(event)
+		for i := range calls {
+			index := toolCalls
+			calls[i].Index = &index
+			toolCalls++
+		}
+		if text != "" || len(calls) > 0 {
+			chunk(&chatDelta{Content: text, ToolCalls: calls}, nil)
+		}
+	}
+	if !started {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+	}
+	if turn.failure != "" {
+		send(openAIError("server_error", turn.failure))
+		return
+	}
+
+	stop := "stop"
+	chunk(&chatDelta{}, &stop)
+	if includeUsage {
+		c := *completion
+		c.Choices = []chatChoice{}
+		c.Usage = turn.usage
+		send(c)
+	}
+	_, _ = fmt.Fprint(w, "data: [DONE]\n\n")
+	flusher.Flush()
+}
+
+// chatTurn follows the A2A events of an agent turn and extracts what an
+// OpenAI client sees: text, tool calls, usage and failure.
+type chatTurn struct {
+	// streamed is set once partial text arrives, so the complete response
+	// that follows a streamed model call is not sent twice
+	streamed bool
+
+	toolCallIDs map[string]bool
+	usage       *chatUsage
+	failure     string
+}
+
+func newChatTurn() *chatTurn {
+	return &chatTurn{toolCallIDs: make(map[string]bool)}
+}
+
+// observe returns the text and new tool calls carried by an event.
+func (t *chatTurn) observe(event a2a.Event) (string, []chatToolCall) {
+	switch e := event.(type) {
+	case *a2a.Message:
+		return partsText(e.Parts), nil
+
+	case *a2a.TaskArtifactUpdateEvent:
+		calls := t.toolCalls(e.Metadata["tool_calls"])
+		text := partsText(e.Artifact.Parts)
+		if partial, _ := e.Metadata["partial"].(bool); partial {
+			t.streamed = t.streamed || text != ""
+			return text, calls
+		}
+		// A complete model response repeats the text streamed before it
+		if t.streamed {
+			text = ""
+		}
+		t.streamed = false
+		return text, calls
+
+	case *a2a.TaskStatusUpdateEvent:
+		if usage, ok := e.Metadata[metaKeyUsage]; ok {
+			t.setUsage(usage)
+		}
+		var text string
+		if e.Status.Message != nil {
+			text = partsText(e.Status.Message.Parts)
+		}
+		switch e.Status.State {
+		case a2a.TaskStateFailed, a2a.TaskStateRejected:
+			t.failure = text
+			if t.failure == "" {
+				t.failure = "agent run " + string(e.Status.State)
+			}
+		case a2a.TaskStateInputRequired:
+			// The prompt asking the user for input
+			return text, nil
+		}
+	}
+	return "", nil
+}
+
+// toolCalls converts tool_calls event metadata, skipping calls already seen.
+func (t *chatTurn) toolCalls(meta any) []chatToolCall {
+	if meta == nil {
+		return nil
+	}
+	var calls []struct {
+		ID   string         `json:"id"`
+		Name string         `json:"name"`
+		Args map[string]any `json:"args"`
+	}
+	if data, err := json.Marshal(meta); err != nil || json.Unmarshal(data, &calls) != nil {
+		return nil
+	}
+
+	var result []chatToolCall
+	for _, c := range calls {
+		if c.ID == "" || t.toolCallIDs[c.ID] {
+			continue
+		}
+		t.toolCallIDs[c.ID] = true
+		args, _ := json.Marshal(c.Args)
+		if c.Args == nil {
+			args = []byte("{}")
+		}
+		result = append(result, chatToolCall{
+			ID:       c.ID,
+			Type:     "function",
+			Function: chatFunctionCall{Name: c.Name, Arguments: string(args)},
+		})
+	}
+	return result
+}
+
+// setUsage reads usage metadata (see usageMeter.meta). The last update of a
+// turn holds the usage reported by the model provider.
+func (t *chatTurn) setUsage(meta any) {
+	var usage struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	}
+	if data, err := json.Marshal(meta); err != nil || json.Unmarshal(data, &usage) != nil {
+		return
+	}
+	t.usage = &chatUsage{
+		PromptTokens:     usage.InputTokens,
+		CompletionTokens: usage.OutputTokens,
+		TotalTokens:      usage.InputTokens + usage.OutputTokens,
+	}
+}
+
+func partsText(parts []a2a.Part) string {
+	var sb strings.Builder
+	for _, part := range parts {
+		if tp, ok := part.(a2a.TextPart); ok {
+			sb.WriteString(tp.Text)
+		}
+	}
+	return sb.String()
+}
+
+// toChatA2AMessage converts the chat messages into the A2A message sent to
+// the agent. Usage metering is requested so the final event carries the
+// token usage of the turn.
+func toChatA2AMessage(messages []chatMessage, user string) (*a2a.Message, error) {
+	if len(messages) == 0 {
+		return nil, errors.New("messages must not be empty")
+	}
+	last := messages[len(messages)-1]
+	if last.Role != "user" {
+		return nil, fmt.Errorf("the last message must have role user, got %q", last.Role)
+	}
+	parts, err := chatContentParts(last.Content)
+	if err != nil {
+		return nil, err
+	}
+	if history := chatTranscript(messages[:len(messages)-1]); history != "" {
+		parts = append([]a2a.Part{a2a.TextPart{Text: history}}, parts...)
+	}
+
+	msg := a2a.NewMessage(a2a.MessageRoleUser, parts...)
+	msg.Metadata = map[string]any{metaKeyStreamUsage: true}
+	if user != "" {
+		msg.Metadata[auth.MetaKeyUserID] = user
+	}
+	return msg, nil
+}
+
+// chatContentParts converts message content to A2A parts. Images given as
+// data URLs are sent inline; other URLs are sent by reference.
+func chatContentParts(raw json.RawMessage) ([]a2a.Part, error) {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		if text == "" {
+			return nil, errors.New("the last message has no content")
+		}
+		return []a2a.Part{a2a.TextPart{Text: text}}, nil
+	}
+
+	var items []chatContentPart
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, errors.New("content must be a string or an array of content parts")
+	}
+	parts := make([]a2a.Part, 0, len(items))
+	for _, item := range items {
+		switch item.Type {
+		case "text":
+			parts = append(parts, a2a.TextPart{Text: item.Text})
+		case "image_url":
+			if item.ImageURL == nil || item.ImageURL.URL == "" {
+				return nil, errors.New("image_url content part has no url")
+			}
+			part, err := imageURLPart(item.ImageURL.URL)
+			if err != nil {
+				return nil, err
+			}
+			parts = append(parts, part)
+		default:
+			return nil, fmt.Errorf("unsupported content part type %q", item.Type)
+		}
+	}
+	if len(parts) == 0 {
+		return nil, errors.New("the last message has no content")
+	}
+	return parts, nil
+}
+
+func imageURLPart(url string) (a2a.Part, error) {
+	rest, ok := strings.CutPrefix(url, "data:")
+	if !ok {
+		return a2a.FilePart{File: a2a.FileURI{URI: url}}, nil
+	}
+	mimeType, data, ok := strings.Cut(rest, ";base64,")
+	if !ok {
+		return nil, errors.New("image data URLs must be base64 encoded")
+	}
+	if _, err := base64.StdEncoding.DecodeString(data); err != nil {
+		return nil, fmt.Errorf("invalid image data URL: %w", err)
+	}
+	return a2a.FilePart{File: a2a.FileBytes{FileMeta: a2a.FileMeta{MimeType: mimeType}, Bytes: data}}, nil
+}
+
+// chatTranscript renders earlier messages as text for the agent.
+func chatTranscript(messages []chatMessage) string {
+	if len(messages) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("Conversation so far:\n")
+	for _, m := range messages {
+		if text := chatContentText(m.Content); text != "" {
+			role := m.Role
+			if m.ToolCallID != "" {
+				role += " (" + m.ToolCallID + ")"
+			}
+			fmt.Fprintf(&sb, "\n%s: %s", role, text)
+		}
+		for _, call := range m.ToolCalls {
+			fmt.Fprintf(&sb, "\n%s called %s(%s) as %s", m.Role, call.Function.Name, call.Function.Arguments, call.ID)
+		}
+	}
+	return sb.String()
+}
+
+// chatContentText returns the text of message content, noting images.
+func chatContentText(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	var items []chatContentPart
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return ""
+	}
+	texts := make([]string, 0, len(items))
+	for _, item := range items {
+		switch item.Type {
+		case "text":
+			texts = append(texts, item.Text)
+		case "image_url":
+			texts = append(texts, "[image]")
+		}
+	}
+	return strings.Join(texts, " ")
+}
+
+// handleModels serves GET /v1/models, listing the agents the caller can
+// call through the chat completions endpoint.
+func (s *HTTPServer) handleModels(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeOpenAIError(w, http.StatusMethodNotAllowed, "invalid_request_error", "Method not allowed")
+		return
+	}
+
+	claims := s.requestClaims(r)
+	s.mu.RLock()
+	names := make([]string, 0, len(s.agentRequestHandlers))
+	for name := range s.agentRequestHandlers {
+		if cfg, ok := s.appCfg.Agents[name]; ok && s.agentAccessStatus(cfg, claims) != 0 {
+			continue
+		}
+		names = append(names, name)
+	}
+	s.mu.RUnlock()
+	slices.Sort(names)
+
+	models := make([]map[string]any, len(names))
+	for i, name := range names {
+		models[i] = map[string]any{"id": name, "object": "model", "created": 0, "owned_by": "hector"}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"object": "list", "data": models})
+}
+
+// openAIAgent returns the request handler of the agent named by model, or
+// the HTTP status denying the caller access to it, as for /agents/{name}.
+func (s *HTTPServer) openAIAgent(model string, claims *auth.Claims) (a2asrv.RequestHandler, int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	handler, ok := s.agentRequestHandlers[model]
+	if !ok {
+		return nil, http.StatusNotFound
+	}
+	if cfg, ok := s.appCfg.Agents[model]; ok {
+		if status := s.agentAccessStatus(cfg, claims); status != 0 {
+			return nil, status
+		}
+	}
+	return handler, 0
+}
+
+func openAIError(errType, msg string) map[string]any {
+	return map[string]any{"error": map[string]any{"message": msg, "type": errType}}
+}
+
+func writeOpenAIError(w http.ResponseWriter, status int, errType, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(openAIError(errType, msg))
+}
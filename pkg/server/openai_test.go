@@ -0,0 +1,255 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+
+	"github.com/kadirpekel/hector/pkg/auth"
+	"github.com/kadirpekel/hector/pkg/config"
+)
+
+// chatHandler replays a streamed model response with one tool call.
+type chatHandler struct {
+	a2asrv.RequestHandler
+	params *a2a.MessageSendParams
+}
+
+func (h *chatHandler) OnSendMessageStream(ctx context.Context, params *a2a.MessageSendParams) iter.Seq2[a2a.Event, error] {
+	h.params = params
+	reqCtx := &a2asrv.RequestContext{TaskID: "task-1", ContextID: "ctx-1"}
+	artifact := func(text string, meta map[string]any) a2a.Event {
+		ev := a2a.NewArtifactEvent(reqCtx, a2a.TextPart{Text: text})
+		ev.Metadata = meta
+		return ev
+	}
+	toolCalls := []map[string]any{{"id": "call_1", "name": "search", "args": map[string]any{"q": "go"}}}
+	done := a2a.NewStatusUpdateEvent(reqCtx, a2a.TaskStateCompleted, nil)
+	done.Final = true
+	done.Metadata = map[string]any{metaKeyUsage: map[string]any{"input_tokens": 12, "output_tokens": 5, "estimated": false}}
+
+	events := []a2a.Event{
+		&a2a.Task{ID: "task-1", ContextID: "ctx-1", Status: a2a.TaskStatus{State: a2a.TaskStateSubmitted}},
+		artifact("", map[string]any{"partial": false, "tool_calls": toolCalls}),
+		artifact("", map[string]any{"partial": false, "tool_calls": toolCalls}),
+		artifact("Hel", map[string]any{"partial": true}),
+		artifact("lo", map[string]any{"partial": true}),
+		artifact("Hello", map[string]any{"partial": false}),
+		done,
+	}
+	return func(yield func(a2a.Event, error) bool) {
+		for _, ev := range events {
+			if !yield(ev, nil) {
+				return
+			}
+		}
+	}
+}
+
+func chatServer(h a2asrv.RequestHandler, visibility string) *HTTPServer {
+	return &HTTPServer{
+		appCfg:               &config.Config{Agents: map[string]*config.AgentConfig{"assistant": {Visibility: visibility}}},
+		agentRequestHandlers: map[string]a2asrv.RequestHandler{"assistant": h},
+	}
+}
+
+func postChat(s *HTTPServer, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.handleChatCompletions(rec, httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(body)))
+	return rec
+}
+
+func TestChatCompletions(t *testing.T) {
+	h := &chatHandler{}
+	rec := postChat(chatServer(h, ""), `{"model": "assistant", "user": "u1", "messages": [
+		{"role": "system", "content": "Be brief."},
+		{"role": "user", "content": "Hi"},
+		{"role": "assistant", "content": "Hello!"},
+		{"role": "user", "content": [{"type": "text", "text": "Search go"}, {"type": "image_url", "image_url": {"url": "data:image/png;base64,iVBORw=="}}]}
+	]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+
+	var resp chatCompletion
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Object != "chat.completion" || resp.Model != "assistant" || len(resp.Choices) != 1 {
+		t.Fatalf("response = %s", rec.Body)
+	}
+	msg := resp.Choices[0].Message
+	if msg.Content != "Hello" || *resp.Choices[0].FinishReason != "stop" {
+		t.Errorf("message = %+v", msg)
+	}
+	if len(msg.ToolCalls) != 1 || msg.ToolCalls[0].Function.Name != "search" || msg.ToolCalls[0].Function.Arguments != `{"q":"go"}` {
+		t.Errorf("tool calls = %+v", msg.ToolCalls)
+	}
+	if resp.Usage == nil || resp.Usage.PromptTokens != 12 || resp.Usage.CompletionTokens != 5 || resp.Usage.TotalTokens != 17 {
+		t.Errorf("usage = %+v", resp.Usage)
+	}
+
+	sent := h.params.Message
+	if len(sent.Parts) != 3 || sent.Metadata["user_id"] != "u1" || sent.Metadata[metaKeyStreamUsage] != true {
+		t.Fatalf("sent message = %+v", sent)
+	}
+	history := sent.Parts[0].(a2a.TextPart).Text
+	if !strings.Contains(history, "system: Be brief.") || !strings.Contains(history, "assistant: Hello!") {
+		t.Errorf("history = %q", history)
+	}
+	if file, ok := sent.Parts[2].(a2a.FilePart).File.(a2a.FileBytes); !ok || file.MimeType != "image/png" {
+		t.Errorf("image part = %+v", sent.Parts[2])
+	}
+}
+
+func TestChatCompletionsStream(t *testing.T) {
+	rec := postChat(chatServer(&chatHandler{}, ""), `{"model": "assistant", "stream": true, "stream_options": {"include_usage": true},
+		"messages": [{"role": "user", "content": "Hi"}]}`)
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+
+	var chunks []chatCompletion
+	var done bool
+	for _, line := range strings.Split(rec.Body.String(), "\n") {
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+		if data == "[DONE]" {
+			done = true
+			continue
+		}
+		var c chatCompletion
+		if err := json.Unmarshal([]byte(data), &c); err != nil {
+			t.Fatalf("chunk %q: %v", data, err)
+		}
+		chunks = append(chunks, c)
+	}
+	if !done {
+		t.Error("stream did not end with [DONE]")
+	}
+
+	var content strings.Builder
+	var toolCalls []chatToolCall
+	var finish string
+	for _, c := range chunks[:len(chunks)-1] {
+		if c.Object != "chat.completion.chunk" || len(c.Choices) != 1 {
+			t.Fatalf("chunk = %+v", c)
+		}
+		content.WriteString(c.Choices[0].Delta.Content)
+		toolCalls = append(toolCalls, c.Choices[0].Delta.ToolCalls...)
+		if f := c.Choices[0].FinishReason; f != nil {
+			finish = *f
+		}
+	}
+	if chunks[0].Choices[0].Delta.Role != "assistant" {
+		t.Errorf("first delta = %+v", chunks[0].Choices[0].Delta)
+	}
+	if content.String() != "Hello" || finish != "stop" {
+		t.Errorf("content = %q, finish reason = %q", content.String(), finish)
+	}
+	if len(toolCalls) != 1 || toolCalls[0].Index == nil || *toolCalls[0].Index != 0 || toolCalls[0].ID != "call_1" {
+		t.Errorf("tool calls = %+v", toolCalls)
+	}
+	if last := chunks[len(chunks)-1]; len(last.Choices) != 0 || last.Usage == nil || last.Usage.TotalTokens != 17 {
+		t.Errorf("usage chunk = %+v", last)
+	}
+}
+
+func TestChatCompletionsRejectsInvalidRequests(t *testing.T) {
+	tests := []struct {
+		visibility, body string
+		status           int
+	}{
+		{"", `{"model": "missing", "messages": [{"role": "user", "content": "Hi"}]}`, http.StatusNotFound},
+		{"private", `{"model": "assistant", "messages": [{"role": "user", "content": "Hi"}]}`, http.StatusNotFound},
+		{"", `{"model": "assistant", "messages": []}`, http.StatusBadRequest},
+		{"", `{"model": "assistant", "messages": [{"role": "assistant", "content": "Hi"}]}`, http.StatusBadRequest},
+		{"", `{"model": "assistant", "messages": [{"role": "user", "content": [{"type": "audio"}]}]}`, http.StatusBadRequest},
+		{"", `{"model": "assistant"`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		rec := postChat(chatServer(&chatHandler{}, tt.visibility), tt.body)
+		if rec.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.body, rec.Code, tt.status)
+		}
+		if !strings.Contains(rec.Body.String(), `"error"`) {
+			t.Errorf("%s: body = %s", tt.body, rec.Body)
+		}
+	}
+}
+
+func TestChatCompletionsAccessControl(t *testing.T) {
+	validator, err := auth.NewAPIKeyValidator("", []auth.APIKey{
+		{Key: "basic", Subject: "basic"},
+		{Key: "reader", Subject: "reader", Scopes: []string{"reports:read"}},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &HTTPServer{
+		appCfg: &config.Config{Agents: map[string]*config.AgentConfig{
+			"pub":    {Visibility: "public"},
+			"int":    {Visibility: "internal"},
+			"scoped": {Visibility: "public", RequiredScopes: []string{"reports:read"}},
+		}},
+		agentRequestHandlers: map[string]a2asrv.RequestHandler{"pub": &chatHandler{}, "int": &chatHandler{}, "scoped": &chatHandler{}},
+		authValidator:        validator,
+	}
+
+	tests := []struct {
+		model, key string
+		status     int
+	}{
+		{"pub", "", http.StatusOK},
+		{"int", "", http.StatusUnauthorized},
+		{"int", "basic", http.StatusOK},
+		{"scoped", "", http.StatusUnauthorized},
+		{"scoped", "basic", http.StatusForbidden},
+		{"scoped", "reader", http.StatusOK},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions",
+			strings.NewReader(`{"model": "`+tt.model+`", "messages": [{"role": "user", "content": "Hi"}]}`))
+		if tt.key != "" {
+			req.Header.Set(auth.DefaultAPIKeyHeader, tt.key)
+		}
+		rec := httptest.NewRecorder()
+		s.handleChatCompletions(rec, req)
+		if rec.Code != tt.status {
+			t.Errorf("model %s with key %q: status = %d, want %d", tt.model, tt.key, rec.Code, tt.status)
+		}
+	}
+
+	for key, want := range map[string]string{"": "pub", "basic": "int,pub", "reader": "int,pub,scoped"} {
+		req := httptest.NewRequest(http.MethodGet, "/v1/models", nil)
+		if key != "" {
+			req.Header.Set(auth.DefaultAPIKeyHeader, key)
+		}
+		rec := httptest.NewRecorder()
+		s.handleModels(rec, req)
+		var resp struct {
+			Data []struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		var ids []string
+		for _, m := range resp.Data {
+			ids = append(ids, m.ID)
+		}
+		if got := strings.Join(ids, ","); got != want {
+			t.Errorf("models with key %q = %s, want %s", key, got, want)
+		}
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/a2aproject/a2a-go/a2a"
 
 	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/model"
 )
 
 // toHectorContent converts an A2A message to Hector content.
@@ -27,20 +28,17 @@ func toHectorContent(msg *a2a.Message) (*agent.Content, error) {
 		return nil, nil
 	}
 
+	// Client messages are always user messages, never system messages
+	msg = model.NormalizeInbound(msg)
 	content := &agent.Content{
-		Parts: msg.Parts,
-		Role:  toHectorRole(msg.Role),
+		Parts:    msg.Parts,
+		Role:     msg.Role,
+		Metadata: msg.Metadata,
 	}
 
 	return content, nil
 }
 
-// toHectorRole converts A2A message role to Hector role.
-func toHectorRole(role a2a.MessageRole) a2a.MessageRole {
-	// A2A roles map directly
-	return role
-}
-
 // ApprovalResponse represents an approval decision from the user.
 type ApprovalResponse struct {
 	// Decision is "approve" or "deny"
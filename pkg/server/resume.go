@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+
+	"github.com/kadirpekel/hector/pkg/checkpoint"
+)
+
+// resumeRequest is the body of POST /agents/{name}/resume.
+type resumeRequest struct {
+	// ResumeToken comes from a tool_approval_request in the input-required event.
+	ResumeToken string `json:"resume_token"`
+
+	// Decision is "approve" or "deny".
+	Decision string `json:"decision"`
+}
+
+// handleResume resumes a task paused for tool approval.
+//
+// The approval is sent to the agent as a tool_approval message on the paused
+// task, so an approved tool runs and a denied one is skipped. With
+// "Accept: text/event-stream" the resumed task's events are streamed as SSE;
+// otherwise the final result is returned as JSON.
+func (s *HTTPServer) handleResume(w http.ResponseWriter, r *http.Request, handler a2asrv.RequestHandler) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req resumeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	if req.Decision != "approve" && req.Decision != "deny" {
+		writeJSONError(w, http.StatusBadRequest, `decision must be "approve" or "deny"`)
+		return
+	}
+	token, err := checkpoint.ParseResumeToken(req.ResumeToken)
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	params := &a2a.MessageSendParams{Message: newApprovalMessage(token, req.Decision)}
+
+	if !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		result, err := handler.OnSendMessage(r.Context(), params)
+		if err != nil {
+			writeJSONError(w, resumeErrorStatus(err), err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(result)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSONError(w, http.StatusInternalServerError, "Streaming not supported")
+		return
+	}
+
+	started := false
+	for event, err := range handler.OnSendMessageStream(r.Context(), params) {
+		if err != nil {
+			if errors.Is(context.Cause(r.Context()), errStreamDurationExceeded) {
+				return // streamDurationMiddleware sends the final event
+			}
+			if !started {
+				writeJSONError(w, resumeErrorStatus(err), err.Error())
+				return
+			}
+			data, _ := json.Marshal(map[string]string{"error": err.Error()})
+			_, _ = fmt.Fprintf(w, "event: error\ndata: %s\n\n", data)
+			flusher.Flush()
+			return
+		}
+
+		if !started {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.Header().Set("Cache-Control", "no-cache")
+			started = true
+		}
+		data, err := json.Marshal(event)
+		if err != nil {
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "data: %s\n\n", data)
+		flusher.Flush()
+	}
+}
+
+// newApprovalMessage builds the tool_approval message that continues the
+// paused task (see ExtractApprovalResponse).
+func newApprovalMessage(token *checkpoint.ResumeToken, decision string) *a2a.Message {
+	msg := a2a.NewMessage(a2a.MessageRoleUser, a2a.DataPart{
+		Data: map[string]any{
+			"type":         "tool_approval",
+			"decision":     decision,
+			"tool_call_id": token.ToolCallID,
+			"tool_name":    token.ToolName,
+			"task_id":      token.TaskID,
+		},
+	})
+	msg.TaskID = a2a.TaskID(token.TaskID)
+	msg.ContextID = token.SessionID
+	return msg
+}
+
+// resumeErrorStatus maps a2a errors to HTTP status codes.
+func resumeErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, a2a.ErrTaskNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, a2a.ErrInvalidParams), errors.Is(err, a2a.ErrInvalidRequest):
+		return http.StatusBadRequest
+	case errors.Is(err, a2a.ErrUnsupportedContentType):
+		return http.StatusUnsupportedMediaType
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeJSONError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
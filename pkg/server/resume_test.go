@@ -0,0 +1,82 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/checkpoint"
+)
+
+// recordingHandler captures the message sent by the resume endpoint.
+type recordingHandler struct {
+	a2asrv.RequestHandler
+	params *a2a.MessageSendParams
+}
+
+func (h *recordingHandler) OnSendMessage(ctx context.Context, params *a2a.MessageSendParams) (a2a.SendMessageResult, error) {
+	h.params = params
+	return &a2a.Task{ID: params.Message.TaskID, ContextID: params.Message.ContextID}, nil
+}
+
+func TestApprovalRequestsCarryResumeToken(t *testing.T) {
+	p := newEventProcessor(&a2asrv.RequestContext{TaskID: "task-1", ContextID: "ctx-1"}, invocationMeta{})
+
+	reqs := p.approvalRequests([]agent.ToolApprovalRequest{
+		{ToolCallID: "call-1", ToolName: "execute_command", Args: map[string]any{"command": "ls"}},
+	})
+	if len(reqs) != 1 || reqs[0]["tool_name"] != "execute_command" {
+		t.Fatalf("approvalRequests() = %v", reqs)
+	}
+
+	token, err := checkpoint.ParseResumeToken(reqs[0]["resume_token"].(string))
+	if err != nil {
+		t.Fatalf("ParseResumeToken() error = %v", err)
+	}
+	want := checkpoint.ResumeToken{TaskID: "task-1", SessionID: "ctx-1", ToolCallID: "call-1", ToolName: "execute_command"}
+	if *token != want {
+		t.Errorf("token = %+v, want %+v", *token, want)
+	}
+}
+
+func TestHandleResumeSendsApproval(t *testing.T) {
+	token := (&checkpoint.ResumeToken{TaskID: "task-1", SessionID: "ctx-1", ToolCallID: "call-1", ToolName: "execute_command"}).Encode()
+	handler := &recordingHandler{}
+	s := &HTTPServer{}
+
+	body := `{"resume_token":"` + token + `","decision":"deny"}`
+	rec := httptest.NewRecorder()
+	s.handleResume(rec, httptest.NewRequest(http.MethodPost, "/agents/a/resume", strings.NewReader(body)), handler)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+	msg := handler.params.Message
+	if msg.TaskID != "task-1" || msg.ContextID != "ctx-1" {
+		t.Errorf("message task/context = %s/%s", msg.TaskID, msg.ContextID)
+	}
+	approval := ExtractApprovalResponse(msg)
+	if approval == nil || approval.Decision != "deny" || approval.ToolCallID != "call-1" {
+		t.Errorf("approval = %+v", approval)
+	}
+}
+
+func TestHandleResumeRejectsInvalidInput(t *testing.T) {
+	s := &HTTPServer{}
+	for _, body := range []string{
+		`{"resume_token":"not-a-token","decision":"approve"}`,
+		`{"resume_token":"x","decision":"maybe"}`,
+	} {
+		rec := httptest.NewRecorder()
+		s.handleResume(rec, httptest.NewRequest(http.MethodPost, "/agents/a/resume", strings.NewReader(body)), &recordingHandler{})
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", body, rec.Code)
+		}
+	}
+}
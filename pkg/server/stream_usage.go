@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/utils"
+)
+
+const (
+	// metaKeyUsage holds token usage in status update metadata.
+	metaKeyUsage = "usage"
+
+	// metaKeyStreamUsage requests live usage updates in message metadata.
+	metaKeyStreamUsage = "stream_usage"
+
+	// usageUpdateTokens is how many estimated output tokens accumulate
+	// between live usage updates.
+	usageUpdateTokens = 50
+)
+
+// usageMeter tracks the token usage of a turn while it streams.
+//
+// Completed model calls count with the usage the provider reported. Output
+// of the call in progress is estimated from the streamed text, and replaced
+// by the reported usage once the call completes.
+type usageMeter struct {
+	counter *utils.TokenCounter
+	usage   usageTracker
+
+	// estimated is the estimated output of the model call in progress
+	estimated int
+
+	// reported is the output total of the last update
+	reported int
+}
+
+func newUsageMeter(usage usageTracker) *usageMeter {
+	// An unknown model falls back to a general-purpose encoding
+	counter, _ := utils.NewTokenCounter("")
+	return &usageMeter{counter: counter, usage: usage}
+}
+
+// observe accounts for an event and reports whether a live update is due.
+// The event's authoritative usage must already be added to the tracker.
+func (m *usageMeter) observe(event *agent.Event) bool {
+	if event == nil {
+		return false
+	}
+	if !event.Partial {
+		if event.Usage != nil {
+			m.estimated = 0
+		}
+		return false
+	}
+
+	text := event.TextContent() + event.ThinkingContent()
+	if text == "" {
+		return false
+	}
+	if m.counter != nil {
+		m.estimated += m.counter.Count(text)
+	} else {
+		m.estimated += utils.EstimateTokens(text)
+	}
+
+	_, output := m.totals()
+	if output-m.reported < usageUpdateTokens {
+		return false
+	}
+	m.reported = output
+	return true
+}
+
+// totals returns the turn's input and output tokens so far.
+func (m *usageMeter) totals() (input, output int) {
+	for _, u := range m.usage {
+		input += u.InputTokens
+		output += u.OutputTokens
+	}
+	return input, output + m.estimated
+}
+
+// meta returns the usage metadata of an update. Estimated is set while any
+// output is still estimated.
+func (m *usageMeter) meta() map[string]any {
+	input, output := m.totals()
+	return map[string]any{
+		"input_tokens":  input,
+		"output_tokens": output,
+		"estimated":     m.estimated > 0,
+	}
+}
+
+// updateEvent builds a live usage update for the task.
+func (m *usageMeter) updateEvent(reqCtx *a2asrv.RequestContext) *a2a.TaskStatusUpdateEvent {
+	ev := a2a.NewStatusUpdateEvent(reqCtx, a2a.TaskStateWorking, nil)
+	ev.Metadata = map[string]any{metaKeyUsage: m.meta()}
+	return ev
+}
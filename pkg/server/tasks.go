@@ -0,0 +1,323 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+
+	"github.com/kadirpekel/hector/pkg/auth"
+	"github.com/kadirpekel/hector/pkg/task"
+)
+
+// methodTasksList is the JSON-RPC method listing tasks. a2a-go does not
+// serve it, so it is handled before requests reach the a2a-go handler.
+const methodTasksList = "tasks/list"
+
+// methodTasksGet is the JSON-RPC method reading one task.
+const methodTasksGet = "tasks/get"
+
+// maxRequestBodyBytes caps the JSON-RPC body buffered to route a request.
+const maxRequestBodyBytes = 10 << 20
+
+// listTasksParams are the params of a tasks/list JSON-RPC request.
+type listTasksParams struct {
+	ContextID     string     `json:"contextId,omitempty"`
+	PageSize      int        `json:"pageSize,omitempty"`
+	PageToken     string     `json:"pageToken,omitempty"`
+	States        []string   `json:"states,omitempty"`
+	CreatedAfter  *time.Time `json:"createdAfter,omitempty"`
+	CreatedBefore *time.Time `json:"createdBefore,omitempty"`
+}
+
+// listTasksResult is the result of a tasks/list JSON-RPC request.
+type listTasksResult struct {
+	Tasks         []*a2a.Task `json:"tasks"`
+	NextPageToken string      `json:"nextPageToken,omitempty"`
+}
+
+// restListTasksResponse is the body of a GET /tasks response.
+type restListTasksResponse struct {
+	Tasks         []*a2a.Task `json:"tasks"`
+	NextPageToken string      `json:"next_page_token,omitempty"`
+}
+
+// parseTaskStates parses state filters. Each value may hold several
+// comma-separated states.
+func parseTaskStates(values []string) ([]a2a.TaskState, error) {
+	var states []a2a.TaskState
+	for _, v := range values {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name == "" {
+				continue
+			}
+			state, err := task.ParseTaskState(name)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", a2a.ErrInvalidParams, err)
+			}
+			states = append(states, state)
+		}
+	}
+	return states, nil
+}
+
+// listTasks returns a page of the caller's tasks of the given agents from
+// the task store. Authenticated callers list their own tasks; anonymous
+// callers must name a context and list only anonymous tasks in it. Listing
+// needs a store that implements task.Lister, such as the SQL or in-memory
+// task store.
+func (s *HTTPServer) listTasks(ctx context.Context, req *task.ListRequest, agents []string) (*task.ListResponse, error) {
+	if !s.serverCfg.A2A.IsMethodEnabled(methodTasksList) {
+		return nil, a2a.ErrUnsupportedOperation
+	}
+	lister, ok := s.taskStore.(task.Lister)
+	if !ok {
+		return nil, fmt.Errorf("%w: the task store does not support listing", a2a.ErrUnsupportedOperation)
+	}
+	if req.PageSize < 0 {
+		return nil, fmt.Errorf("%w: page size must be non-negative", a2a.ErrInvalidParams)
+	}
+	owner := taskOwner(ctx)
+	if owner == "" && req.ContextID == "" {
+		return nil, fmt.Errorf("%w: context id is required without authentication", a2a.ErrInvalidParams)
+	}
+	if len(agents) == 0 {
+		// An empty agent filter would list every agent
+		return &task.ListResponse{Tasks: []*a2a.Task{}}, nil
+	}
+	req.Agents = agents
+	req.Owner = &owner
+
+	resp, err := lister.List(ctx, req)
+	if errors.Is(err, task.ErrInvalidPageToken) {
+		return nil, fmt.Errorf("%w: %v", a2a.ErrInvalidParams, err)
+	}
+	return resp, err
+}
+
+// listableAgents returns the agents whose tasks the caller may list: those
+// it could call.
+func (s *HTTPServer) listableAgents(claims *auth.Claims) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var agents []string
+	for name := range s.agentRequestHandlers {
+		if cfg, ok := s.appCfg.Agents[name]; ok && s.agentAccessStatus(cfg, claims) == 0 {
+			agents = append(agents, name)
+		}
+	}
+	slices.Sort(agents)
+	return agents
+}
+
+// handleListTasks serves GET /tasks?context_id=&state=&since=&until=&page_size=&page_token=.
+// Tasks are returned newest first; pass next_page_token back as
+// page_token to fetch the next page. state may be repeated or
+// comma-separated; since and until are RFC 3339 times. The list spans the
+// agents the caller may call. With auth enabled the route always requires
+// credentials, whatever excluded_paths says; disabling tasks/list removes it.
+func (s *HTTPServer) handleListTasks(w http.ResponseWriter, r *http.Request) {
+	if !s.serverCfg.A2A.IsMethodEnabled(methodTasksList) {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	claims := s.requestClaims(r)
+	if s.authValidator != nil && claims == nil {
+		writeJSONError(w, http.StatusUnauthorized, "Unauthorized")
+		return
+	}
+	if claims != nil {
+		r = r.WithContext(auth.ContextWithClaims(r.Context(), claims))
+	}
+
+	q := r.URL.Query()
+	req := &task.ListRequest{
+		ContextID: q.Get("context_id"),
+		PageToken: q.Get("page_token"),
+	}
+	if v := q.Get("page_size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "page_size must be an integer")
+			return
+		}
+		req.PageSize = n
+	}
+	states, err := parseTaskStates(q["state"])
+	if err != nil {
+		writeJSONError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.States = states
+	for param, dst := range map[string]*time.Time{"since": &req.CreatedAfter, "until": &req.CreatedBefore} {
+		v := q.Get(param)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, param+" must be an RFC 3339 time")
+			return
+		}
+		*dst = t
+	}
+
+	resp, err := s.listTasks(r.Context(), req, s.listableAgents(claims))
+	if err != nil {
+		status := resumeErrorStatus(err)
+		if errors.Is(err, a2a.ErrUnsupportedOperation) {
+			status = http.StatusNotImplemented
+		}
+		writeJSONError(w, status, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(restListTasksResponse{Tasks: resp.Tasks, NextPageToken: resp.NextPageToken})
+}
+
+// handleJSONRPCListTasks serves a tasks/list JSON-RPC request sent to an
+// agent, listing that agent's tasks.
+func (s *HTTPServer) handleJSONRPCListTasks(w http.ResponseWriter, r *http.Request, agentName string, id json.RawMessage, params json.RawMessage) {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	resp := wsResponse{JSONRPC: "2.0", ID: id}
+
+	var p listTasksParams
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			resp.Error = newWSError(fmt.Errorf("%w: %v", a2a.ErrInvalidParams, err))
+		}
+	}
+	var req *task.ListRequest
+	if resp.Error == nil {
+		states, err := parseTaskStates(p.States)
+		if err != nil {
+			resp.Error = newWSError(err)
+		}
+		req = &task.ListRequest{
+			ContextID: p.ContextID,
+			PageSize:  p.PageSize,
+			PageToken: p.PageToken,
+			States:    states,
+		}
+		if p.CreatedAfter != nil {
+			req.CreatedAfter = *p.CreatedAfter
+		}
+		if p.CreatedBefore != nil {
+			req.CreatedBefore = *p.CreatedBefore
+		}
+	}
+	if resp.Error == nil {
+		result, err := s.listTasks(r.Context(), req, []string{agentName})
+		if err != nil {
+			resp.Error = newWSError(err)
+		} else {
+			resp.Result = listTasksResult{Tasks: result.Tasks, NextPageToken: result.NextPageToken}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// parseJSONRPCRequest returns the method, id and params of a JSON-RPC
+// request, or an empty method if r is not one. The body is read up to
+// maxRequestBodyBytes and restored so downstream handlers can read it; a
+// larger body returns an *http.MaxBytesError.
+func parseJSONRPCRequest(w http.ResponseWriter, r *http.Request) (method string, id, params json.RawMessage, err error) {
+	if r.Body == nil {
+		return "", nil, nil, nil
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes))
+	_ = r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	var payload struct {
+		Method string          `json:"method"`
+		ID     json.RawMessage `json:"id"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return "", nil, nil, nil
+	}
+	return payload.Method, payload.ID, payload.Params, nil
+}
+
+// agentTaskStore is the view of the shared task store given to one agent's
+// request handler. It stamps new tasks with the agent and the calling
+// subject, and hides tasks of other agents or callers, so tasks/get,
+// tasks/cancel and continuations cannot reach them.
+type agentTaskStore struct {
+	a2asrv.TaskStore
+	agent string
+}
+
+// Save stamps the task's attribution on its first save.
+func (s *agentTaskStore) Save(ctx context.Context, t *a2a.Task) error {
+	if t != nil {
+		if _, _, ok := task.Attribution(t); !ok {
+			if t.Metadata == nil {
+				t.Metadata = make(map[string]any)
+			}
+			t.Metadata[task.MetaKeyAgent] = s.agent
+			t.Metadata[task.MetaKeyOwner] = taskOwner(ctx)
+		}
+	}
+	return s.TaskStore.Save(ctx, t)
+}
+
+// Get returns a2a.ErrTaskNotFound for tasks of another agent or caller.
+// Tasks saved before attribution are returned as before.
+func (s *agentTaskStore) Get(ctx context.Context, taskID a2a.TaskID) (*a2a.Task, error) {
+	t, err := s.TaskStore.Get(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if agent, owner, ok := task.Attribution(t); ok && (agent != s.agent || owner != taskOwner(ctx)) {
+		return nil, a2a.ErrTaskNotFound
+	}
+	return t, nil
+}
+
+// taskOwner returns the authenticated subject of a request, or "" for
+// anonymous callers.
+func taskOwner(ctx context.Context) string {
+	if claims := auth.ClaimsFromContext(ctx); claims != nil {
+		return claims.Subject
+	}
+	return ""
+}
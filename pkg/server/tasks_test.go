@@ -0,0 +1,332 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	_ "github.com/mattn/go-sqlite3"
+
+	"github.com/kadirpekel/hector/pkg/auth"
+	"github.com/kadirpekel/hector/pkg/config"
+	"github.com/kadirpekel/hector/pkg/task"
+)
+
+func newTaskListServer(t *testing.T, tasks int) http.Handler {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "tasks.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store, err := task.NewSQLTaskStore(db, "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range tasks {
+		err := store.Save(context.Background(), &a2a.Task{
+			ID:        a2a.TaskID(fmt.Sprintf("task-%d", i)),
+			ContextID: "ctx",
+			Status:    a2a.TaskStatus{State: a2a.TaskStateCompleted},
+			Metadata:  map[string]any{task.MetaKeyAgent: "pub", task.MetaKeyOwner: ""},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := &config.Config{Agents: map[string]*config.AgentConfig{"pub": {Name: "pub"}}}
+	return NewHTTPServer(cfg, map[string]*Executor{"pub": {}}, WithTaskStore(store)).setupRoutes()
+}
+
+func TestListTasksREST(t *testing.T) {
+	handler := newTaskListServer(t, 5)
+
+	var ids []string
+	token := ""
+	for range 5 {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/tasks?context_id=ctx&page_size=2&page_token="+url.QueryEscape(token), nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+		}
+		var resp restListTasksResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		for _, task := range resp.Tasks {
+			ids = append(ids, string(task.ID))
+		}
+		if token = resp.NextPageToken; token == "" {
+			break
+		}
+	}
+	if strings.Join(ids, ",") != "task-4,task-3,task-2,task-1,task-0" {
+		t.Errorf("listed %v", ids)
+	}
+
+	for query, want := range map[string]int{"page_size=x": http.StatusBadRequest, "page_token=bad": http.StatusBadRequest} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/tasks?context_id=ctx&"+query, nil))
+		if w.Code != want {
+			t.Errorf("?%s status = %d, want %d", query, w.Code, want)
+		}
+	}
+}
+
+func TestListTasksJSONRPC(t *testing.T) {
+	handler := newTaskListServer(t, 3)
+
+	call := func(params string) wsResponse {
+		body := `{"jsonrpc":"2.0","id":7,"method":"tasks/list","params":` + params + `}`
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("POST", "/agents/pub", strings.NewReader(body)))
+		var resp struct {
+			wsResponse
+			Result *listTasksResult `json:"result"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid response %q: %v", w.Body.String(), err)
+		}
+		resp.wsResponse.Result = resp.Result
+		return resp.wsResponse
+	}
+
+	if resp := call(`{}`); resp.Error == nil || resp.Error.Code != -32602 {
+		t.Errorf("anonymous list without context error = %+v, want invalid params", resp.Error)
+	}
+
+	first := call(`{"contextId":"ctx","pageSize":2}`)
+	page, ok := first.Result.(*listTasksResult)
+	if first.Error != nil || !ok || len(page.Tasks) != 2 || page.NextPageToken == "" || string(first.ID) != "7" {
+		t.Fatalf("first page = %+v", first)
+	}
+	second := call(`{"contextId":"ctx","pageSize":2,"pageToken":"` + page.NextPageToken + `"}`)
+	page, ok = second.Result.(*listTasksResult)
+	if second.Error != nil || !ok || len(page.Tasks) != 1 || page.Tasks[0].ID != "task-0" || page.NextPageToken != "" {
+		t.Fatalf("second page = %+v", second)
+	}
+
+	if resp := call(`{"contextId":"ctx","pageToken":"bad"}`); resp.Error == nil || resp.Error.Code != -32602 {
+		t.Errorf("bad token error = %+v, want invalid params", resp.Error)
+	}
+	if resp := call(`{"states":["done"]}`); resp.Error == nil || resp.Error.Code != -32602 {
+		t.Errorf("bad state error = %+v, want invalid params", resp.Error)
+	}
+	filtered := call(`{"contextId":"ctx","states":["completed"],"createdBefore":"2000-01-01T00:00:00Z"}`)
+	if page, ok := filtered.Result.(*listTasksResult); filtered.Error != nil || !ok || len(page.Tasks) != 0 {
+		t.Errorf("filtered = %+v, want no tasks created before 2000", filtered)
+	}
+}
+
+// getOnlyTaskStore is a task store that cannot list tasks.
+type getOnlyTaskStore struct{ a2asrv.TaskStore }
+
+func TestListTasksUnsupported(t *testing.T) {
+	store := getOnlyTaskStore{task.NewInMemoryTaskStore()}
+	handler := NewHTTPServer(&config.Config{}, nil, WithTaskStore(store)).setupRoutes()
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/tasks", nil))
+	if w.Code != http.StatusNotImplemented {
+		t.Errorf("status = %d, want 501 without a listable task store", w.Code)
+	}
+}
+
+func TestListTasksFilters(t *testing.T) {
+	store := task.NewInMemoryTaskStore()
+	for id, state := range map[string]a2a.TaskState{"f": a2a.TaskStateFailed, "c": a2a.TaskStateCanceled, "ok": a2a.TaskStateCompleted} {
+		err := store.Save(context.Background(), &a2a.Task{
+			ID:        a2a.TaskID(id),
+			ContextID: "ctx",
+			Status:    a2a.TaskStatus{State: state},
+			Metadata:  map[string]any{task.MetaKeyAgent: "pub", task.MetaKeyOwner: ""},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := &config.Config{Agents: map[string]*config.AgentConfig{"pub": {Name: "pub"}}}
+	handler := NewHTTPServer(cfg, map[string]*Executor{"pub": {}}, WithTaskStore(store)).setupRoutes()
+
+	list := func(query string) (int, []string) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/tasks?context_id=ctx&"+query, nil))
+		var resp restListTasksResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		var ids []string
+		for _, task := range resp.Tasks {
+			ids = append(ids, string(task.ID))
+		}
+		slices.Sort(ids)
+		return w.Code, ids
+	}
+
+	hourAgo := url.QueryEscape(time.Now().Add(-time.Hour).Format(time.RFC3339))
+	tests := []struct {
+		query  string
+		status int
+		want   string
+	}{
+		{"state=failed", http.StatusOK, "f"},
+		{"state=failed,canceled", http.StatusOK, "c,f"},
+		{"state=failed&state=completed&since=" + hourAgo, http.StatusOK, "f,ok"},
+		{"until=" + hourAgo, http.StatusOK, ""},
+		{"state=done", http.StatusBadRequest, ""},
+		{"since=yesterday", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		status, ids := list(tt.query)
+		if status != tt.status || strings.Join(ids, ",") != tt.want {
+			t.Errorf("?%s = %d %v, want %d %q", tt.query, status, ids, tt.status, tt.want)
+		}
+	}
+}
+
+func TestListTasksScopedToAgentAndCaller(t *testing.T) {
+	store := task.NewInMemoryTaskStore()
+	for _, tc := range []struct{ id, agent, owner string }{
+		{"pub-alice", "pub", "alice"},
+		{"pub-bob", "pub", "bob"},
+		{"pub-anon", "pub", ""},
+		{"int-alice", "int", "alice"},
+		{"priv-alice", "priv", "alice"},
+	} {
+		err := store.Save(context.Background(), &a2a.Task{
+			ID:        a2a.TaskID(tc.id),
+			ContextID: "ctx",
+			Status:    a2a.TaskStatus{State: a2a.TaskStateCompleted},
+			Metadata:  map[string]any{task.MetaKeyAgent: tc.agent, task.MetaKeyOwner: tc.owner},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg := &config.Config{
+		Agents: map[string]*config.AgentConfig{
+			"pub":  {Name: "pub", Visibility: "public"},
+			"int":  {Name: "int", Visibility: "internal"},
+			"priv": {Name: "priv", Visibility: "private"},
+		},
+		Server: config.ServerConfig{
+			Auth: &config.AuthConfig{Enabled: true, APIKeys: &config.APIKeysConfig{}},
+		},
+	}
+	validator, err := auth.NewAPIKeyValidator("", []auth.APIKey{{Key: "alice-key", Subject: "alice"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	executors := map[string]*Executor{"pub": {}, "int": {}, "priv": {}}
+	handler := NewHTTPServer(cfg, executors, WithTaskStore(store), WithAuthValidator(validator)).setupRoutes()
+
+	do := func(req *http.Request, key string) (int, []string) {
+		if key != "" {
+			req.Header.Set(auth.DefaultAPIKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		var resp struct {
+			Tasks  []*a2a.Task      `json:"tasks"`
+			Result *listTasksResult `json:"result"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		if resp.Result != nil {
+			resp.Tasks = resp.Result.Tasks
+		}
+		var ids []string
+		for _, task := range resp.Tasks {
+			ids = append(ids, string(task.ID))
+		}
+		slices.Sort(ids)
+		return w.Code, ids
+	}
+	rpc := func(agent, params string) *http.Request {
+		body := `{"jsonrpc":"2.0","id":1,"method":"tasks/list","params":` + params + `}`
+		return httptest.NewRequest("POST", "/agents/"+agent, strings.NewReader(body))
+	}
+
+	tests := []struct {
+		name   string
+		req    *http.Request
+		key    string
+		status int
+		want   string
+	}{
+		{"GET /tasks anonymous", httptest.NewRequest("GET", "/tasks?context_id=ctx", nil), "", http.StatusUnauthorized, ""},
+		{"GET /tasks", httptest.NewRequest("GET", "/tasks", nil), "alice-key", http.StatusOK, "int-alice,pub-alice"},
+		{"tasks/list public agent", rpc("pub", `{}`), "alice-key", http.StatusOK, "pub-alice"},
+		{"tasks/list internal agent", rpc("int", `{}`), "alice-key", http.StatusOK, "int-alice"},
+		{"tasks/list anonymous", rpc("pub", `{"contextId":"ctx"}`), "", http.StatusOK, "pub-anon"},
+		{"tasks/list private agent", rpc("priv", `{}`), "alice-key", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		status, ids := do(tt.req, tt.key)
+		if status != tt.status || strings.Join(ids, ",") != tt.want {
+			t.Errorf("%s = %d %v, want %d %q", tt.name, status, ids, tt.status, tt.want)
+		}
+	}
+
+	// Other agents' and callers' tasks are hidden from task lookups too
+	scoped := &agentTaskStore{TaskStore: store, agent: "pub"}
+	aliceCtx := auth.ContextWithClaims(context.Background(), &auth.Claims{Subject: "alice"})
+	if _, err := scoped.Get(aliceCtx, "pub-alice"); err != nil {
+		t.Errorf("Get(own task) error = %v", err)
+	}
+	for _, id := range []a2a.TaskID{"pub-bob", "int-alice"} {
+		if _, err := scoped.Get(aliceCtx, id); !errors.Is(err, a2a.ErrTaskNotFound) {
+			t.Errorf("Get(%s) error = %v, want ErrTaskNotFound", id, err)
+		}
+	}
+}
+
+func TestListTasksDisabled(t *testing.T) {
+	cfg := &config.Config{
+		Agents: map[string]*config.AgentConfig{"pub": {Name: "pub"}},
+		Server: config.ServerConfig{A2A: &config.A2AConfig{DisabledMethods: []string{methodTasksList}}},
+	}
+	handler := NewHTTPServer(cfg, map[string]*Executor{"pub": {}}).setupRoutes()
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/tasks?context_id=ctx", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404 with tasks/list disabled", w.Code)
+	}
+}
+
+func TestAgentRequestBodyLimit(t *testing.T) {
+	cfg := &config.Config{Agents: map[string]*config.AgentConfig{"pub": {Name: "pub"}}}
+	handler := NewHTTPServer(cfg, map[string]*Executor{"pub": {}}).setupRoutes()
+	body := strings.NewReader(`{"jsonrpc":"2.0","params":"` + strings.Repeat("x", maxRequestBodyBytes) + `"}`)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("POST", "/agents/pub", body))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413 for an oversized body", w.Code)
+	}
+}
+
+func TestAgentTaskStoreStampsAttribution(t *testing.T) {
+	scoped := &agentTaskStore{TaskStore: task.NewInMemoryTaskStore(), agent: "pub"}
+	ctx := auth.ContextWithClaims(context.Background(), &auth.Claims{Subject: "alice"})
+	if err := scoped.Save(ctx, &a2a.Task{ID: "t1", ContextID: "ctx"}); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := scoped.Get(ctx, "t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if agent, owner, ok := task.Attribution(stored); !ok || agent != "pub" || owner != "alice" {
+		t.Errorf("Attribution() = %q, %q, %v, want pub, alice", agent, owner, ok)
+	}
+	if _, err := scoped.Get(context.Background(), "t1"); !errors.Is(err, a2a.ErrTaskNotFound) {
+		t.Errorf("anonymous Get() error = %v, want ErrTaskNotFound", err)
+	}
+}
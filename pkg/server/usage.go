@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/config"
+	"github.com/kadirpekel/hector/pkg/session"
+)
+
+// usageStateKey is the session state key holding accumulated token usage
+// per model, as map[model]ModelUsage.
+const usageStateKey = "_usage"
+
+// ModelUsage is the token usage of a single model within a session.
+type ModelUsage struct {
+	InputTokens   int     `json:"input_tokens"`
+	OutputTokens  int     `json:"output_tokens"`
+	EstimatedCost float64 `json:"estimated_cost"`
+}
+
+// SessionUsage is the response of GET /agents/{name}/sessions/{id}/usage.
+type SessionUsage struct {
+	SessionID     string                 `json:"session_id"`
+	InputTokens   int                    `json:"input_tokens"`
+	OutputTokens  int                    `json:"output_tokens"`
+	EstimatedCost float64                `json:"estimated_cost"`
+	Models        map[string]*ModelUsage `json:"models"`
+}
+
+// usageTracker accumulates token usage reported by the events of a turn.
+type usageTracker map[string]*ModelUsage
+
+func (u usageTracker) add(event *agent.Event) {
+	if event == nil || event.Partial || event.Usage == nil {
+		return
+	}
+	m, ok := u[event.Usage.Model]
+	if !ok {
+		m = &ModelUsage{}
+		u[event.Usage.Model] = m
+	}
+	m.InputTokens += event.Usage.InputTokens
+	m.OutputTokens += event.Usage.OutputTokens
+}
+
+// recordUsage adds the turn's token usage to the session state.
+func (e *Executor) recordUsage(ctx context.Context, meta invocationMeta, usage usageTracker) {
+	if len(usage) == 0 {
+		return
+	}
+	// Usage was already spent, so record it even if the request was canceled
+	ctx = context.WithoutCancel(ctx)
+
+	service := e.config.RunnerConfig.SessionService
+	resp, err := service.Get(ctx, &session.GetRequest{
+		AppName:   e.config.RunnerConfig.AppName,
+		UserID:    meta.userID,
+		SessionID: meta.sessionID,
+	})
+	if err != nil {
+		slog.Warn("Failed to record session usage", "session_id", meta.sessionID, "error", err)
+		return
+	}
+
+	total := loadUsage(resp.Session.State())
+	for model, m := range usage {
+		t, ok := total[model]
+		if !ok {
+			t = &ModelUsage{}
+			total[model] = t
+		}
+		t.InputTokens += m.InputTokens
+		t.OutputTokens += m.OutputTokens
+	}
+
+	// Set the state directly for stores that don't apply event state deltas
+	// (in-memory); the event persists it for those that do (SQL).
+	value := usageStateValue(total)
+	_ = resp.Session.State().Set(usageStateKey, value)
+
+	event := agent.NewEvent("")
+	event.Author = agent.AuthorSystem
+	event.Actions.StateDelta[usageStateKey] = value
+	if err := service.AppendEvent(ctx, resp.Session, event); err != nil {
+		slog.Warn("Failed to record session usage", "session_id", meta.sessionID, "error", err)
+	}
+}
+
+// loadUsage reads accumulated usage from session state. The value may have
+// been round-tripped through JSON by persistent session stores.
+func loadUsage(state agent.ReadonlyState) usageTracker {
+	usage := make(usageTracker)
+	if state == nil {
+		return usage
+	}
+	value, err := state.Get(usageStateKey)
+	if err != nil || value == nil {
+		return usage
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return usage
+	}
+	_ = json.Unmarshal(data, &usage)
+	return usage
+}
+
+// usageStateValue converts usage to plain maps so it survives any session store.
+func usageStateValue(usage usageTracker) map[string]any {
+	value := make(map[string]any, len(usage))
+	for model, m := range usage {
+		value[model] = map[string]any{
+			"input_tokens":  m.InputTokens,
+			"output_tokens": m.OutputTokens,
+		}
+	}
+	return value
+}
+
+// handleSessionUsage returns a session's token usage and estimated cost,
+// broken down by model. The session owner is the caller's user (see
+// requestUserID).
+func (s *HTTPServer) handleSessionUsage(w http.ResponseWriter, r *http.Request, executor *Executor, pricing map[string]*config.PricingConfig, sessionID string) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if executor == nil {
+		writeJSONError(w, http.StatusNotFound, "Agent has no executor")
+		return
+	}
+
+	// A read-only report, so a lagging replica is acceptable
+	resp, err := executor.config.RunnerConfig.SessionService.Get(config.WithReplicaReads(r.Context()), &session.GetRequest{
+		AppName:   executor.config.RunnerConfig.AppName,
+		UserID:    s.requestUserID(r),
+		SessionID: sessionID,
+	})
+	if err != nil {
+		writeJSONError(w, http.StatusNotFound, "Session not found: "+sessionID)
+		return
+	}
+
+	result := SessionUsage{SessionID: sessionID, Models: loadUsage(resp.Session.State())}
+	for model, m := range result.Models {
+		m.EstimatedCost = pricing[model].Cost(m.InputTokens, m.OutputTokens)
+		result.InputTokens += m.InputTokens
+		result.OutputTokens += m.OutputTokens
+		result.EstimatedCost += m.EstimatedCost
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
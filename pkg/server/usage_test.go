@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/config"
+	"github.com/kadirpekel/hector/pkg/runner"
+	"github.com/kadirpekel/hector/pkg/session"
+)
+
+func TestSessionUsageAccumulatesAcrossTurns(t *testing.T) {
+	ctx := context.Background()
+	sessions := session.InMemoryService()
+	executor := NewExecutor(ExecutorConfig{RunnerConfig: runner.Config{AppName: "app", SessionService: sessions}})
+	meta := invocationMeta{userID: "default", sessionID: "s1"}
+	if err := executor.prepareSession(ctx, meta); err != nil {
+		t.Fatalf("prepareSession() error = %v", err)
+	}
+
+	for range 2 {
+		usage := make(usageTracker)
+		usage.add(&agent.Event{Usage: &agent.TokenUsage{Model: "gpt-4o", InputTokens: 1000, OutputTokens: 500}})
+		usage.add(&agent.Event{Usage: &agent.TokenUsage{Model: "llama3.2", InputTokens: 200, OutputTokens: 100}})
+		usage.add(&agent.Event{Partial: true, Usage: &agent.TokenUsage{Model: "gpt-4o", InputTokens: 1}})
+		executor.recordUsage(ctx, meta, usage)
+	}
+
+	pricing := (&config.Config{LLMs: map[string]*config.LLMConfig{
+		"default": {Model: "gpt-4o", Pricing: &config.PricingConfig{InputPer1K: 0.0025, OutputPer1K: 0.01}},
+		"local":   {Model: "llama3.2"},
+	}}).ModelPricing()
+
+	rec := httptest.NewRecorder()
+	s := &HTTPServer{}
+	s.handleSessionUsage(rec, httptest.NewRequest(http.MethodGet, "/agents/a/sessions/s1/usage", nil), executor, pricing, "s1")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	var got SessionUsage
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got.InputTokens != 2400 || got.OutputTokens != 1200 {
+		t.Errorf("tokens = %d/%d, want 2400/1200", got.InputTokens, got.OutputTokens)
+	}
+	gpt := got.Models["gpt-4o"]
+	if gpt == nil || gpt.InputTokens != 2000 || gpt.OutputTokens != 1000 {
+		t.Fatalf("gpt-4o usage = %+v", gpt)
+	}
+	if math.Abs(gpt.EstimatedCost-0.015) > 1e-9 || math.Abs(got.EstimatedCost-0.015) > 1e-9 {
+		t.Errorf("estimated cost = %v (total %v), want 0.015", gpt.EstimatedCost, got.EstimatedCost)
+	}
+	if got.Models["llama3.2"].EstimatedCost != 0 {
+		t.Errorf("unpriced model cost = %v, want 0", got.Models["llama3.2"].EstimatedCost)
+	}
+}
+
+func TestSessionUsageUnknownSession(t *testing.T) {
+	executor := NewExecutor(ExecutorConfig{RunnerConfig: runner.Config{AppName: "app", SessionService: session.InMemoryService()}})
+
+	rec := httptest.NewRecorder()
+	s := &HTTPServer{}
+	s.handleSessionUsage(rec, httptest.NewRequest(http.MethodGet, "/agents/a/sessions/missing/usage", nil), executor, nil, "missing")
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want 404", rec.Code)
+	}
+}
+
+func TestUsageMeterStreamsEstimates(t *testing.T) {
+	usage := make(usageTracker)
+	meter := newUsageMeter(usage)
+
+	chunk := &agent.Event{Partial: true, Message: a2a.NewMessage(a2a.MessageRoleAgent, a2a.TextPart{Text: strings.Repeat("token ", 30)})}
+	updates := 0
+	for range 4 {
+		if meter.observe(chunk) {
+			updates++
+		}
+	}
+	if updates == 0 || updates == 4 {
+		t.Errorf("updates = %d, want periodic updates", updates)
+	}
+	if m := meter.meta(); m["estimated"] != true || m["output_tokens"].(int) < usageUpdateTokens {
+		t.Errorf("streaming meta = %v", m)
+	}
+
+	// The reported usage replaces the estimate when the model call completes
+	final := &agent.Event{Usage: &agent.TokenUsage{Model: "gpt-4o", InputTokens: 40, OutputTokens: 118}}
+	usage.add(final)
+	meter.observe(final)
+	m := meter.meta()
+	if m["estimated"] != false || m["input_tokens"] != 40 || m["output_tokens"] != 118 {
+		t.Errorf("final meta = %v", m)
+	}
+}
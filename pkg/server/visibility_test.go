@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http/httptest"
+	"slices"
 	"testing"
 
 	"github.com/kadirpekel/hector/pkg/auth"
@@ -132,5 +133,205 @@ func TestAgentVisibility(t *testing.T) {
 		// Case D: Private Agent - Blocked always (404/403)
 		// We verify it returns 404 as if it doesn't exist
 		checkRequest(t, "GET", "/agents/priv", "valid", 404)
+
+		// Case E: Session checkpoint routes share the agent's gating
+		checkRequest(t, "GET", "/agents/int/sessions/s1/checkpoints", "", 401)
+		checkRequest(t, "POST", "/agents/int/sessions/s1/checkpoints/task-1/restore", "", 401)
+		checkRequest(t, "POST", "/agents/priv/sessions/s1/checkpoints/task-1/restore", "valid", 404)
 	})
 }
+
+func TestDiscoveryTagFilter(t *testing.T) {
+	cfg := &config.Config{
+		Agents: map[string]*config.AgentConfig{
+			"coder":   {Name: "coder", Tags: []string{"coding", "internal"}, Category: "engineering"},
+			"support": {Name: "support", Tags: []string{"support"}},
+		},
+	}
+	handler := NewHTTPServer(cfg, map[string]*Executor{"coder": {}, "support": {}}).setupRoutes()
+
+	discover := func(query string) []string {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/agents"+query, nil))
+		var resp struct {
+			Agents []struct {
+				Name string `json:"name"`
+			} `json:"agents"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("Failed to parse response: %v", err)
+		}
+		var names []string
+		for _, a := range resp.Agents {
+			names = append(names, a.Name)
+		}
+		return names
+	}
+
+	if got := discover("?tag=coding"); len(got) != 1 || got[0] != "coder" {
+		t.Errorf("?tag=coding = %v, want [coder]", got)
+	}
+	if got := discover("?tag=coding&tag=support"); len(got) != 0 {
+		t.Errorf("?tag=coding&tag=support = %v, want none", got)
+	}
+	if got := discover("?category=engineering"); len(got) != 1 || got[0] != "coder" {
+		t.Errorf("?category=engineering = %v, want [coder]", got)
+	}
+
+	ext := NewHTTPServer(cfg, nil).agentCards["coder"].Capabilities.Extensions
+	if len(ext) != 1 || ext[0].Params["category"] != "engineering" {
+		t.Errorf("card extensions = %+v, want discovery extension", ext)
+	}
+}
+
+func TestCardHeaders(t *testing.T) {
+	cfg := &config.Config{
+		Server: config.ServerConfig{A2A: &config.A2AConfig{CardHeaders: map[string]string{
+			"Cache-Control": "max-age=300",
+			"X-Fleet":       "prod",
+		}}},
+		Agents: map[string]*config.AgentConfig{
+			"pub": {Name: "pub", CardHeaders: map[string]string{"Cache-Control": "max-age=60"}},
+		},
+	}
+	handler := NewHTTPServer(cfg, map[string]*Executor{"pub": {}}).setupRoutes()
+
+	tests := []struct {
+		path, cacheControl string
+	}{
+		{"/agents/pub/.well-known/agent-card.json", "max-age=60"},
+		{"/agents", "max-age=300"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+		if got := w.Header().Get("Cache-Control"); got != tt.cacheControl {
+			t.Errorf("%s: Cache-Control = %q, want %q", tt.path, got, tt.cacheControl)
+		}
+		if got := w.Header().Get("X-Fleet"); got != "prod" {
+			t.Errorf("%s: X-Fleet = %q, want prod", tt.path, got)
+		}
+	}
+}
+
+func TestAgentVisibilityAPIKey(t *testing.T) {
+	cfg := &config.Config{
+		Agents: map[string]*config.AgentConfig{
+			"int": {Visibility: "internal", Name: "int"},
+		},
+		Server: config.ServerConfig{
+			Host: "0.0.0.0",
+			Port: 8080,
+			Auth: &config.AuthConfig{
+				Enabled: true,
+				APIKeys: &config.APIKeysConfig{Keys: []config.APIKeyConfig{{Key: "secret", Subject: "svc"}}},
+			},
+		},
+	}
+	validator, err := auth.NewAPIKeyValidator("", []auth.APIKey{{Key: "secret", Subject: "svc"}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewHTTPServer(cfg, map[string]*Executor{"int": {}}, WithAuthValidator(validator))
+	handler := srv.setupRoutes()
+
+	for key, want := range map[string]int{"": 401, "wrong": 401, "secret": 200} {
+		req := httptest.NewRequest("GET", "/agents/int", nil)
+		if key != "" {
+			req.Header.Set(auth.DefaultAPIKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("X-API-Key %q: status %d, want %d", key, w.Code, want)
+		}
+	}
+
+	// The agent card advertises the API key scheme
+	card := srv.agentCards["int"]
+	if card == nil || card.SecuritySchemes["APIKeyAuth"] == nil {
+		t.Errorf("agent card missing APIKeyAuth scheme: %+v", card)
+	}
+	if _, ok := card.SecuritySchemes["BearerAuth"]; ok {
+		t.Error("BearerAuth advertised without JWT configured")
+	}
+}
+
+func TestAgentRequiredScopes(t *testing.T) {
+	cfg := &config.Config{
+		Agents: map[string]*config.AgentConfig{
+			"pub":    {Visibility: "public", Name: "pub"},
+			"int":    {Visibility: "internal", Name: "int"},
+			"scoped": {Visibility: "public", Name: "scoped", RequiredScopes: []string{"reports:read"}},
+		},
+		Server: config.ServerConfig{
+			Host: "0.0.0.0",
+			Port: 8080,
+			Auth: &config.AuthConfig{
+				Enabled: true,
+				APIKeys: &config.APIKeysConfig{},
+			},
+		},
+	}
+	validator, err := auth.NewAPIKeyValidator("", []auth.APIKey{
+		{Key: "basic", Subject: "basic"},
+		{Key: "reader", Subject: "reader", Scopes: []string{"reports:read"}},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	executors := map[string]*Executor{"pub": {}, "int": {}, "scoped": {}}
+	handler := NewHTTPServer(cfg, executors, WithAuthValidator(validator)).setupRoutes()
+
+	do := func(path, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		if key != "" {
+			req.Header.Set(auth.DefaultAPIKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	access := []struct {
+		agent, key string
+		want       int
+	}{
+		{"pub", "", 200},
+		{"pub", "basic", 200},
+		{"int", "", 401},
+		{"int", "basic", 200},
+		{"scoped", "", 401},
+		{"scoped", "basic", 403},
+		{"scoped", "reader", 200},
+	}
+	for _, tc := range access {
+		if w := do("/agents/"+tc.agent, tc.key); w.Code != tc.want {
+			t.Errorf("GET /agents/%s with key %q: status %d, want %d", tc.agent, tc.key, w.Code, tc.want)
+		}
+	}
+
+	discovery := map[string][]string{
+		"":       {"pub"},
+		"basic":  {"int", "pub"},
+		"reader": {"int", "pub", "scoped"},
+	}
+	for key, want := range discovery {
+		var resp struct {
+			Agents []struct {
+				Name string `json:"name"`
+			} `json:"agents"`
+		}
+		if err := json.Unmarshal(do("/agents", key).Body.Bytes(), &resp); err != nil {
+			t.Fatalf("key %q: %v", key, err)
+		}
+		var got []string
+		for _, a := range resp.Agents {
+			got = append(got, a.Name)
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, want) {
+			t.Errorf("discovery with key %q = %v, want %v", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,385 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/gorilla/websocket"
+)
+
+// WebSocket protocol (GET /agents/{name}/ws)
+//
+// The socket carries JSON-RPC 2.0 objects in text frames, one object per
+// frame, so a browser can start runs, send follow-ups and cancel tasks over
+// a single connection. Client frames are requests:
+//
+//	{"jsonrpc": "2.0", "id": 1, "method": "message/stream", "params": {"message": {...}}}
+//
+// message/stream and tasks/resubscribe open a stream; tasks/cancel is
+// answered with a single response. Each event of a stream is sent as a
+// response carrying the request id, with the same payload as the data of an
+// SSE event on the JSON-RPC endpoint:
+//
+//	{"jsonrpc": "2.0", "id": 1, "result": {"kind": "status-update", ...}}
+//
+// A stream ends with an error response, or with a stream/end notification
+// once its last event has been sent:
+//
+//	{"jsonrpc": "2.0", "method": "stream/end", "params": {"id": 1}}
+//
+// Streams run concurrently and are told apart by their ids, which must be
+// unique among the open streams. A follow-up is a new message/stream with the
+// taskId or contextId of the earlier run. Closing the socket ends every open
+// stream; as with SSE, the task itself keeps running until it completes or
+// is cancelled with tasks/cancel.
+//
+// At shutdown the server refuses new streams and closes the socket with a
+// 1001 (going away) close frame once the open streams end. Streams still
+// open when the shutdown grace period ends get a stream/end notification
+// with the same reason as the SSE "done" event:
+//
+//	{"jsonrpc": "2.0", "method": "stream/end", "params": {"id": 1, "reason": "server_shutdown", "resume_method": "tasks/resubscribe"}}
+
+const (
+	// wsMethodStreamEnd is the notification sent after the last event of a stream.
+	wsMethodStreamEnd = "stream/end"
+
+	// wsPingInterval keeps idle connections open through proxies, like the
+	// SSE keep-alive of the JSON-RPC endpoint.
+	wsPingInterval = 30 * time.Second
+
+	// wsWriteTimeout bounds a single frame write to a slow client.
+	wsWriteTimeout = 10 * time.Second
+
+	// wsMaxFrameSize bounds a client frame, which may carry file parts.
+	wsMaxFrameSize = 16 << 20
+
+	// wsCloseTimeout bounds each step of closing a session at shutdown:
+	// cancelled streams sending their last notification, and the client
+	// answering the close frame.
+	wsCloseTimeout = time.Second
+)
+
+// wsRequest is a JSON-RPC request frame sent by the client.
+type wsRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+}
+
+// wsResponse is a JSON-RPC response frame. Exactly one of Result and Error
+// is set.
+type wsResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *wsError        `json:"error,omitempty"`
+}
+
+// wsNotification is a JSON-RPC notification frame sent by the server.
+type wsNotification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+type wsError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// wsErrorCodes maps A2A errors to the codes used by the JSON-RPC endpoint.
+var wsErrorCodes = []struct {
+	err  error
+	code int
+}{
+	{a2a.ErrParseError, -32700},
+	{a2a.ErrInvalidRequest, -32600},
+	{a2a.ErrMethodNotFound, -32601},
+	{a2a.ErrInvalidParams, -32602},
+	{a2a.ErrTaskNotFound, -32001},
+	{a2a.ErrTaskNotCancelable, -32002},
+	{a2a.ErrPushNotificationNotSupported, -32003},
+	{a2a.ErrUnsupportedOperation, -32004},
+	{a2a.ErrUnsupportedContentType, -32005},
+	{a2a.ErrInvalidAgentResponse, -32006},
+}
+
+func newWSError(err error) *wsError {
+	for _, c := range wsErrorCodes {
+		if errors.Is(err, c.err) {
+			return &wsError{Code: c.code, Message: err.Error()}
+		}
+	}
+	return &wsError{Code: -32603, Message: err.Error()}
+}
+
+// wsSession serves one WebSocket connection.
+type wsSession struct {
+	ctx     context.Context
+	conn    *websocket.Conn
+	handler a2asrv.RequestHandler
+
+	// writeMu serializes frame writes; the connection allows one writer.
+	writeMu sync.Mutex
+
+	mu       sync.Mutex
+	streams  map[string]bool // ids of open streams
+	draining bool            // the server is shutting down; no new streams
+	wg       sync.WaitGroup
+}
+
+// handleWebSocket upgrades the request and serves the WebSocket protocol
+// until the client disconnects. Open streams are cancelled and drained
+// before it returns.
+func (s *HTTPServer) handleWebSocket(w http.ResponseWriter, r *http.Request, handler a2asrv.RequestHandler) {
+	upgrader := websocket.Upgrader{CheckOrigin: s.allowWebSocketOrigin}
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already replied with an HTTP error
+		slog.Debug("WebSocket upgrade failed", "path", r.URL.Path, "error", err)
+		return
+	}
+	defer conn.Close()
+	conn.SetReadLimit(wsMaxFrameSize)
+
+	drain, stop, done := s.streams.trackWebSocket()
+	defer done()
+
+	ctx, cancel := context.WithCancelCause(r.Context())
+	sess := &wsSession{ctx: ctx, conn: conn, handler: handler, streams: make(map[string]bool)}
+	defer func() {
+		cancel(nil)
+		sess.wg.Wait()
+	}()
+
+	go sess.keepAlive()
+	go sess.closeOnShutdown(drain, stop, cancel)
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			if !websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				slog.Debug("WebSocket connection closed", "path", r.URL.Path, "error", err)
+			}
+			return
+		}
+		sess.dispatch(data)
+	}
+}
+
+// allowWebSocketOrigin applies the CORS allowed origins to WebSocket
+// handshakes. Without CORS config any origin is allowed, like corsMiddleware.
+func (s *HTTPServer) allowWebSocketOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || s.serverCfg == nil || s.serverCfg.CORS == nil {
+		return true
+	}
+	allowed := s.serverCfg.CORS.AllowedOrigins
+	return slices.Contains(allowed, "*") || slices.Contains(allowed, origin)
+}
+
+// dispatch handles one client frame.
+func (sess *wsSession) dispatch(data []byte) {
+	var req wsRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		sess.reply(nil, nil, fmt.Errorf("%w: %v", a2a.ErrParseError, err))
+		return
+	}
+	if req.JSONRPC != "2.0" || len(req.ID) == 0 || string(req.ID) == "null" {
+		sess.reply(req.ID, nil, fmt.Errorf("%w: jsonrpc must be \"2.0\" and id is required", a2a.ErrInvalidRequest))
+		return
+	}
+
+	switch req.Method {
+	case "message/stream":
+		var params a2a.MessageSendParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			sess.reply(req.ID, nil, fmt.Errorf("%w: %v", a2a.ErrInvalidParams, err))
+			return
+		}
+		sess.stream(req.ID, func(ctx context.Context) iter.Seq2[a2a.Event, error] {
+			return sess.handler.OnSendMessageStream(ctx, &params)
+		})
+
+	case "tasks/resubscribe":
+		var params a2a.TaskIDParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			sess.reply(req.ID, nil, fmt.Errorf("%w: %v", a2a.ErrInvalidParams, err))
+			return
+		}
+		sess.stream(req.ID, func(ctx context.Context) iter.Seq2[a2a.Event, error] {
+			return sess.handler.OnResubscribeToTask(ctx, &params)
+		})
+
+	case "tasks/cancel":
+		var params a2a.TaskIDParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			sess.reply(req.ID, nil, fmt.Errorf("%w: %v", a2a.ErrInvalidParams, err))
+			return
+		}
+		task, err := sess.handler.OnCancelTask(sess.ctx, &params)
+		sess.reply(req.ID, task, err)
+
+	default:
+		sess.reply(req.ID, nil, fmt.Errorf("%w: %s", a2a.ErrMethodNotFound, req.Method))
+	}
+}
+
+// stream sends the events of a new stream in the background.
+func (sess *wsSession) stream(id json.RawMessage, events func(context.Context) iter.Seq2[a2a.Event, error]) {
+	key := string(id)
+	sess.mu.Lock()
+	if sess.draining {
+		sess.mu.Unlock()
+		sess.reply(id, nil, errServerShuttingDown)
+		return
+	}
+	if sess.streams[key] {
+		sess.mu.Unlock()
+		sess.reply(id, nil, fmt.Errorf("%w: id %s is used by an open stream", a2a.ErrInvalidRequest, key))
+		return
+	}
+	sess.streams[key] = true
+	// Add under mu, so closeOnShutdown's Wait never races a new stream
+	sess.wg.Add(1)
+	sess.mu.Unlock()
+
+	go func() {
+		defer sess.wg.Done()
+		defer func() {
+			sess.mu.Lock()
+			delete(sess.streams, key)
+			sess.mu.Unlock()
+		}()
+
+		for event, err := range events(sess.ctx) {
+			if sess.ctx.Err() != nil {
+				break
+			}
+			if err != nil {
+				sess.reply(id, nil, err)
+				return
+			}
+			if err := sess.reply(id, event, nil); err != nil {
+				return
+			}
+		}
+
+		params := map[string]any{"id": id}
+		if sess.ctx.Err() != nil {
+			if !errors.Is(context.Cause(sess.ctx), errServerShuttingDown) {
+				return // the socket is closed
+			}
+			params["reason"] = "server_shutdown"
+			params["resume_method"] = "tasks/resubscribe"
+		}
+		_ = sess.write(wsNotification{JSONRPC: "2.0", Method: wsMethodStreamEnd, Params: params})
+	}()
+}
+
+// reply sends a response for the request with the given id.
+func (sess *wsSession) reply(id json.RawMessage, result any, err error) error {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	resp := wsResponse{JSONRPC: "2.0", ID: id}
+	if err != nil {
+		resp.Error = newWSError(err)
+	} else {
+		resp.Result = result
+	}
+	return sess.write(resp)
+}
+
+func (sess *wsSession) write(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	sess.writeMu.Lock()
+	defer sess.writeMu.Unlock()
+	_ = sess.conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	return sess.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// closeOnShutdown closes the session at server shutdown: once its open
+// streams end after drain, or by cancelling them when stop is closed at the
+// end of the grace period.
+func (sess *wsSession) closeOnShutdown(drain, stop <-chan struct{}, cancel context.CancelCauseFunc) {
+	select {
+	case <-drain:
+	case <-sess.ctx.Done():
+		return
+	}
+	sess.mu.Lock()
+	sess.draining = true
+	sess.mu.Unlock()
+
+	idle := make(chan struct{})
+	go func() {
+		sess.wg.Wait()
+		close(idle)
+	}()
+
+	select {
+	case <-idle:
+	case <-stop:
+		slog.Debug("Closing WebSocket streams at shutdown")
+		cancel(errServerShuttingDown)
+		select {
+		case <-idle:
+		case <-time.After(wsCloseTimeout):
+		}
+	case <-sess.ctx.Done():
+		return
+	}
+
+	// The read loop ends when the client answers the close frame, or at
+	// the read deadline
+	msg := websocket.FormatCloseMessage(websocket.CloseGoingAway, errServerShuttingDown.Error())
+	_ = sess.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsWriteTimeout))
+	_ = sess.conn.SetReadDeadline(time.Now().Add(wsCloseTimeout))
+}
+
+// keepAlive pings the client until the session ends.
+func (sess *wsSession) keepAlive() {
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-sess.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := sess.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+	"github.com/gorilla/websocket"
+
+	"github.com/kadirpekel/hector/pkg/config"
+)
+
+// streamHandler streams the message text back word by word. A message
+// reading "wait" blocks until its stream is cancelled.
+type streamHandler struct {
+	a2asrv.RequestHandler
+	cancelled chan struct{}
+}
+
+func (h *streamHandler) OnSendMessageStream(ctx context.Context, params *a2a.MessageSendParams) iter.Seq2[a2a.Event, error] {
+	return func(yield func(a2a.Event, error) bool) {
+		text := params.Message.Parts[0].(a2a.TextPart).Text
+		if text == "wait" {
+			<-ctx.Done()
+			close(h.cancelled)
+			yield(nil, ctx.Err())
+			return
+		}
+		for _, word := range strings.Fields(text) {
+			if !yield(a2a.NewMessage(a2a.MessageRoleAgent, a2a.TextPart{Text: word}), nil) {
+				return
+			}
+		}
+	}
+}
+
+func (h *streamHandler) OnCancelTask(ctx context.Context, id *a2a.TaskIDParams) (*a2a.Task, error) {
+	return nil, a2a.ErrTaskNotFound
+}
+
+func dialWebSocket(t *testing.T, h a2asrv.RequestHandler) *websocket.Conn {
+	t.Helper()
+	s := &HTTPServer{serverCfg: &config.ServerConfig{}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.handleWebSocket(w, r, h)
+	}))
+	t.Cleanup(srv.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func streamRequest(id int, text string) string {
+	return `{"jsonrpc": "2.0", "id": ` + strconv.Itoa(id) + `, "method": "message/stream", "params": {"message": {"role": "user", "messageId": "m", "parts": [{"kind": "text", "text": "` + text + `"}]}}}`
+}
+
+type wsFrame struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params struct {
+		ID     json.RawMessage `json:"id"`
+		Reason string          `json:"reason"`
+	} `json:"params"`
+	Result struct {
+		Parts []struct {
+			Text string `json:"text"`
+		} `json:"parts"`
+	} `json:"result"`
+	Error *wsError `json:"error"`
+}
+
+func readFrame(t *testing.T, conn *websocket.Conn) wsFrame {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var f wsFrame
+	if err := conn.ReadJSON(&f); err != nil {
+		t.Fatalf("ReadJSON() error = %v", err)
+	}
+	return f
+}
+
+func TestWebSocketStreamsEvents(t *testing.T) {
+	conn := dialWebSocket(t, &streamHandler{})
+
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(streamRequest(1, "hello there"))); err != nil {
+		t.Fatal(err)
+	}
+	var words []string
+	for range 2 {
+		f := readFrame(t, conn)
+		if string(f.ID) != "1" || f.Error != nil || len(f.Result.Parts) != 1 {
+			t.Fatalf("frame = %+v", f)
+		}
+		words = append(words, f.Result.Parts[0].Text)
+	}
+	if strings.Join(words, " ") != "hello there" {
+		t.Errorf("words = %v", words)
+	}
+	if f := readFrame(t, conn); f.Method != wsMethodStreamEnd || string(f.Params.ID) != "1" {
+		t.Errorf("end frame = %+v", f)
+	}
+
+	// A follow-up on the same socket
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(streamRequest(2, "again"))); err != nil {
+		t.Fatal(err)
+	}
+	if f := readFrame(t, conn); string(f.ID) != "2" || f.Result.Parts[0].Text != "again" {
+		t.Errorf("follow-up frame = %+v", f)
+	}
+}
+
+func TestWebSocketErrors(t *testing.T) {
+	conn := dialWebSocket(t, &streamHandler{})
+
+	tests := []struct {
+		frame string
+		code  int
+	}{
+		{`not json`, -32700},
+		{`{"jsonrpc": "2.0", "method": "message/stream"}`, -32600},
+		{`{"jsonrpc": "2.0", "id": 1, "method": "message/send"}`, -32601},
+		{`{"jsonrpc": "2.0", "id": 2, "method": "message/stream", "params": []}`, -32602},
+		{`{"jsonrpc": "2.0", "id": 3, "method": "tasks/cancel", "params": {"id": "t"}}`, -32001},
+	}
+	for _, tt := range tests {
+		if err := conn.WriteMessage(websocket.TextMessage, []byte(tt.frame)); err != nil {
+			t.Fatal(err)
+		}
+		if f := readFrame(t, conn); f.Error == nil || f.Error.Code != tt.code {
+			t.Errorf("%s: error = %+v, want code %d", tt.frame, f.Error, tt.code)
+		}
+	}
+}
+
+func TestWebSocketCloseCancelsStreams(t *testing.T) {
+	h := &streamHandler{cancelled: make(chan struct{})}
+	conn := dialWebSocket(t, h)
+
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(streamRequest(1, "wait"))); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.WriteMessage(websocket.TextMessage, []byte(streamRequest(1, "hi"))); err != nil {
+		t.Fatal(err)
+	}
+	if f := readFrame(t, conn); f.Error == nil || f.Error.Code != -32600 {
+		t.Errorf("duplicate id error = %+v", f.Error)
+	}
+
+	conn.Close()
+	select {
+	case <-h.cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream was not cancelled when the socket closed")
+	}
+}
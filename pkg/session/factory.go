@@ -59,9 +59,16 @@ func NewSessionServiceFromConfig(cfg *config.Config, pool *config.DBPool) (Servi
 		return nil, fmt.Errorf("database %q not found", dbName)
 	}
 
-	db, err := pool.Get(dbCfg)
+	db, err := pool.GetRouted(dbCfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get database connection: %w", err)
 	}
-	return NewSQLSessionService(db, dbCfg.Dialect())
+	svc, err := NewSQLSessionService(db.Primary(), dbCfg.Dialect())
+	if err != nil {
+		return nil, err
+	}
+	if db.HasReplicas() {
+		svc.replicas = db
+	}
+	return svc, nil
 }
@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import "context"
+
+// Rewinder is implemented by session services that can roll a session back
+// to an earlier point in its history.
+type Rewinder interface {
+	// Rewind atomically truncates the session's events to the first
+	// NumEvents and replaces its session-scoped state. App- and user-scoped
+	// state is shared with other sessions and left untouched.
+	Rewind(ctx context.Context, req *RewindRequest) error
+}
+
+// RewindRequest contains parameters for rewinding a session.
+type RewindRequest struct {
+	AppName   string
+	UserID    string
+	SessionID string
+
+	// NumEvents is the number of leading events to keep.
+	NumEvents int
+
+	// State replaces the session-scoped state. Keys with the app:, user:
+	// or temp: prefix are ignored.
+	State map[string]any
+}
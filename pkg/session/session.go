@@ -242,6 +242,28 @@ func (s *memoryState) ClearTempKeys() {
 	}
 }
 
+// replaceSessionKeys replaces all session-scoped keys with those of state,
+// keeping app- and user-scoped keys.
+func (s *memoryState) replaceSessionKeys(state map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key := range s.data {
+		if !isSharedKey(key) {
+			delete(s.data, key)
+		}
+	}
+	for key, val := range state {
+		if !isSharedKey(key) && !strings.HasPrefix(key, KeyPrefixTemp) {
+			s.data[key] = val
+		}
+	}
+}
+
+// isSharedKey reports whether key is app- or user-scoped.
+func isSharedKey(key string) bool {
+	return strings.HasPrefix(key, KeyPrefixApp) || strings.HasPrefix(key, KeyPrefixUser)
+}
+
 // memoryEvents is an in-memory Events implementation.
 type memoryEvents struct {
 	events []*agent.Event
@@ -281,6 +303,15 @@ func (e *memoryEvents) append(event *agent.Event) {
 	e.events = append(e.events, event)
 }
 
+// truncate keeps the first n events.
+func (e *memoryEvents) truncate(n int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if n >= 0 && n < len(e.events) {
+		e.events = e.events[:n]
+	}
+}
+
 // InMemoryService returns an in-memory session service.
 // Useful for testing and development.
 func InMemoryService() Service {
@@ -374,7 +405,44 @@ func (s *inMemoryService) Delete(ctx context.Context, req *DeleteRequest) error
 	return nil
 }
 
+// DeleteExpired deletes sessions last updated before the given time.
+func (s *inMemoryService) DeleteExpired(ctx context.Context, before time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := 0
+	for key, session := range s.sessions {
+		if session.LastUpdateTime().Before(before) {
+			delete(s.sessions, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// Rewind truncates the session's events and replaces its session-scoped state.
+func (s *inMemoryService) Rewind(ctx context.Context, req *RewindRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := s.sessionKey(req.AppName, req.UserID, req.SessionID)
+	ms, ok := s.sessions[key]
+	if !ok {
+		return ErrSessionNotFound
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.events.truncate(req.NumEvents)
+	ms.state.replaceSessionKeys(req.State)
+	ms.lastUpdateTime = time.Now()
+	return nil
+}
+
 var (
+	_ Expirer      = (*inMemoryService)(nil)
+	_ Rewinder     = (*inMemoryService)(nil)
 	_ Session      = (*memorySession)(nil)
 	_ agent.State  = (*memoryState)(nil)
 	_ agent.Events = (*memoryEvents)(nil)
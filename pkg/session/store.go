@@ -19,6 +19,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"maps"
@@ -29,6 +30,7 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/config"
 
 	// SQL drivers
 	_ "github.com/go-sql-driver/mysql"
@@ -41,6 +43,16 @@ import (
 type SQLSessionService struct {
 	db      *sql.DB
 	dialect string
+
+	// replicas serves List, and Get for contexts marked with
+	// config.WithReplicaReads, when read replicas are configured
+	replicas *config.RoutedDB
+}
+
+// queryer runs read queries on a primary or replica connection.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
 }
 
 // sessionRow maps to the sessions table.
@@ -110,6 +122,11 @@ CREATE TABLE IF NOT EXISTS sessions (
 const createSessionsIndexSQL = `
 CREATE INDEX IF NOT EXISTS idx_sessions_user ON sessions(app_name, user_id)`
 
+// idx_sessions_updated_at serves expiry: updated_at is the time of the
+// session's last event.
+const createSessionsUpdatedAtIndexSQL = `
+CREATE INDEX IF NOT EXISTS idx_sessions_updated_at ON sessions(updated_at)`
+
 const createAppStatesSchemaSQL = `
 CREATE TABLE IF NOT EXISTS app_states (
     app_name VARCHAR(255) PRIMARY KEY,
@@ -200,6 +217,7 @@ func (s *SQLSessionService) initSchema() error {
 	statements := []string{
 		createSessionsSchemaSQL,
 		createSessionsIndexSQL,
+		createSessionsUpdatedAtIndexSQL,
 		createAppStatesSchemaSQL,
 		createUserStatesSchemaSQL,
 		createEventsSchemaSQL,
@@ -225,22 +243,36 @@ func (s *SQLSessionService) Close() error {
 // Service Implementation
 // =============================================================================
 
-// Get retrieves an existing session.
+// Get retrieves an existing session from the primary.
+// For contexts marked with config.WithReplicaReads, the session is read
+// from a replica instead; a session not yet replicated is read from the
+// primary.
 func (s *SQLSessionService) Get(ctx context.Context, req *GetRequest) (*GetResponse, error) {
+	if s.replicas == nil || !config.ReplicaReadsAllowed(ctx) {
+		return s.get(ctx, s.db, req)
+	}
+	resp, err := s.get(ctx, s.replicas.Replica(), req)
+	if errors.Is(err, ErrSessionNotFound) {
+		return s.get(ctx, s.db, req)
+	}
+	return resp, err
+}
+
+func (s *SQLSessionService) get(ctx context.Context, q queryer, req *GetRequest) (*GetResponse, error) {
 	// No mutex needed - DB handles concurrent reads
 	// Fetch session
-	session, err := s.getSession(ctx, req.AppName, req.UserID, req.SessionID)
+	session, err := s.getSession(ctx, q, req.AppName, req.UserID, req.SessionID)
 	if err != nil {
 		return nil, err
 	}
 
 	// Fetch and merge states
-	appState, err := s.getAppState(ctx, req.AppName)
+	appState, err := s.getAppState(ctx, q, req.AppName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get app state: %w", err)
 	}
 
-	userState, err := s.getUserState(ctx, req.AppName, req.UserID)
+	userState, err := s.getUserState(ctx, q, req.AppName, req.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user state: %w", err)
 	}
@@ -250,7 +282,7 @@ func (s *SQLSessionService) Get(ctx context.Context, req *GetRequest) (*GetRespo
 	session.state = newMemoryState(mergedState)
 
 	// Load events with optional filtering
-	events, err := s.getEventsFiltered(ctx, req.AppName, req.UserID, req.SessionID, req.NumRecentEvents, req.After)
+	events, err := s.getEventsFiltered(ctx, q, req.AppName, req.UserID, req.SessionID, req.NumRecentEvents, req.After)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get events: %w", err)
 	}
@@ -311,8 +343,8 @@ func (s *SQLSessionService) Create(ctx context.Context, req *CreateRequest) (*Cr
 	}
 
 	// Fetch merged state for response (outside transaction)
-	appState, _ := s.getAppState(ctx, req.AppName)
-	userState, _ := s.getUserState(ctx, req.AppName, req.UserID)
+	appState, _ := s.getAppState(ctx, s.db, req.AppName)
+	userState, _ := s.getUserState(ctx, s.db, req.AppName, req.UserID)
 	mergedState := mergeStates(appState, userState, sessionState)
 
 	session := &memorySession{
@@ -431,7 +463,8 @@ func (s *SQLSessionService) AppendEvent(ctx context.Context, session Session, ev
 	return nil
 }
 
-// List returns sessions matching the filter criteria.
+// List returns sessions matching the filter criteria, reading from a
+// replica if configured.
 func (s *SQLSessionService) List(ctx context.Context, req *ListRequest) (*ListResponse, error) {
 	// No mutex needed - DB handles concurrent reads
 	query := `SELECT app_name, user_id, id, state_json, created_at, updated_at 
@@ -447,7 +480,7 @@ func (s *SQLSessionService) List(ctx context.Context, req *ListRequest) (*ListRe
 		query = convertToPostgresPlaceholders(query)
 	}
 
-	rows, err := s.db.QueryContext(ctx, query, args...)
+	rows, err := s.reader().QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list sessions: %w", err)
 	}
@@ -494,11 +527,145 @@ func (s *SQLSessionService) Delete(ctx context.Context, req *DeleteRequest) erro
 	return nil
 }
 
+// reader returns the connection for a read: a replica if configured,
+// otherwise the primary.
+func (s *SQLSessionService) reader() queryer {
+	if s.replicas == nil {
+		return s.db
+	}
+	return s.replicas.Replica()
+}
+
+// expireBatchSize is the most expired sessions selected per query.
+const expireBatchSize = 500
+
+// DeleteExpired deletes sessions last updated before the given time, along
+// with their events. A session updated while the sweep runs is kept.
+func (s *SQLSessionService) DeleteExpired(ctx context.Context, before time.Time) (int, error) {
+	selectQuery := `SELECT app_name, user_id, id FROM sessions WHERE updated_at < ? LIMIT ?`
+	if s.dialect == "postgres" {
+		selectQuery = convertToPostgresPlaceholders(selectQuery)
+	}
+
+	deleted := 0
+	for {
+		keys, err := s.expiredSessionKeys(ctx, selectQuery, before)
+		if err != nil {
+			return deleted, err
+		}
+		for _, key := range keys {
+			ok, err := s.deleteExpiredSession(ctx, key, before)
+			if err != nil {
+				return deleted, err
+			}
+			if ok {
+				deleted++
+			}
+		}
+		if len(keys) < expireBatchSize {
+			return deleted, nil
+		}
+	}
+}
+
+// expiredSessionKeys returns a batch of (app_name, user_id, id) keys of
+// sessions last updated before the given time.
+func (s *SQLSessionService) expiredSessionKeys(ctx context.Context, query string, before time.Time) ([][3]string, error) {
+	rows, err := s.db.QueryContext(ctx, query, before, expireBatchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expired sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var keys [][3]string
+	for rows.Next() {
+		var key [3]string
+		if err := rows.Scan(&key[0], &key[1], &key[2]); err != nil {
+			return nil, fmt.Errorf("failed to scan expired session: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// deleteExpiredSession deletes a session and its events if it is still
+// expired. It reports whether the session was deleted.
+func (s *SQLSessionService) deleteExpiredSession(ctx context.Context, key [3]string, before time.Time) (bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `DELETE FROM sessions WHERE app_name = ? AND user_id = ? AND id = ? AND updated_at < ?`
+	eventQuery := `DELETE FROM session_events WHERE app_name = ? AND user_id = ? AND session_id = ?`
+	if s.dialect == "postgres" {
+		query = convertToPostgresPlaceholders(query)
+		eventQuery = convertToPostgresPlaceholders(eventQuery)
+	}
+
+	result, err := tx.ExecContext(ctx, query, key[0], key[1], key[2], before)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete expired session: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, eventQuery, key[0], key[1], key[2]); err != nil {
+		return false, fmt.Errorf("failed to delete expired session events: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return true, nil
+}
+
+// Rewind truncates the session's events and replaces its session-scoped
+// state in one transaction. Event sequence numbers start at 1, so the first
+// NumEvents events are those numbered up to NumEvents.
+func (s *SQLSessionService) Rewind(ctx context.Context, req *RewindRequest) error {
+	_, _, sessionState := extractStateDeltas(req.State)
+	stateJSON, err := json.Marshal(sessionState)
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	query := `UPDATE sessions SET state_json = ?, updated_at = ? WHERE app_name = ? AND user_id = ? AND id = ?`
+	eventQuery := `DELETE FROM session_events WHERE app_name = ? AND user_id = ? AND session_id = ? AND sequence_num > ?`
+	if s.dialect == "postgres" {
+		query = convertToPostgresPlaceholders(query)
+		eventQuery = convertToPostgresPlaceholders(eventQuery)
+	}
+
+	result, err := tx.ExecContext(ctx, query, string(stateJSON), time.Now(), req.AppName, req.UserID, req.SessionID)
+	if err != nil {
+		return fmt.Errorf("failed to update session state: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrSessionNotFound
+	}
+	if _, err := tx.ExecContext(ctx, eventQuery, req.AppName, req.UserID, req.SessionID, req.NumEvents); err != nil {
+		return fmt.Errorf("failed to delete session events: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
 // =============================================================================
 // Helper Methods
 // =============================================================================
 
-func (s *SQLSessionService) getSession(ctx context.Context, appName, userID, sessionID string) (*memorySession, error) {
+func (s *SQLSessionService) getSession(ctx context.Context, q queryer, appName, userID, sessionID string) (*memorySession, error) {
 	query := `SELECT app_name, user_id, id, state_json, created_at, updated_at 
               FROM sessions WHERE app_name = ? AND user_id = ? AND id = ?`
 	if s.dialect == "postgres" {
@@ -506,7 +673,7 @@ func (s *SQLSessionService) getSession(ctx context.Context, appName, userID, ses
 	}
 
 	var row sessionRow
-	err := s.db.QueryRowContext(ctx, query, appName, userID, sessionID).Scan(
+	err := q.QueryRowContext(ctx, query, appName, userID, sessionID).Scan(
 		&row.AppName, &row.UserID, &row.ID, &row.StateJSON, &row.CreatedAt, &row.UpdatedAt)
 	if err == sql.ErrNoRows {
 		return nil, ErrSessionNotFound
@@ -539,14 +706,14 @@ func (s *SQLSessionService) rowToSession(row *sessionRow) (*memorySession, error
 	}, nil
 }
 
-func (s *SQLSessionService) getAppState(ctx context.Context, appName string) (map[string]any, error) {
+func (s *SQLSessionService) getAppState(ctx context.Context, q queryer, appName string) (map[string]any, error) {
 	query := `SELECT state_json FROM app_states WHERE app_name = ?`
 	if s.dialect == "postgres" {
 		query = convertToPostgresPlaceholders(query)
 	}
 
 	var stateJSON string
-	err := s.db.QueryRowContext(ctx, query, appName).Scan(&stateJSON)
+	err := q.QueryRowContext(ctx, query, appName).Scan(&stateJSON)
 	if err == sql.ErrNoRows {
 		return make(map[string]any), nil
 	}
@@ -561,14 +728,14 @@ func (s *SQLSessionService) getAppState(ctx context.Context, appName string) (ma
 	return state, nil
 }
 
-func (s *SQLSessionService) getUserState(ctx context.Context, appName, userID string) (map[string]any, error) {
+func (s *SQLSessionService) getUserState(ctx context.Context, q queryer, appName, userID string) (map[string]any, error) {
 	query := `SELECT state_json FROM user_states WHERE app_name = ? AND user_id = ?`
 	if s.dialect == "postgres" {
 		query = convertToPostgresPlaceholders(query)
 	}
 
 	var stateJSON string
-	err := s.db.QueryRowContext(ctx, query, appName, userID).Scan(&stateJSON)
+	err := q.QueryRowContext(ctx, query, appName, userID).Scan(&stateJSON)
 	if err == sql.ErrNoRows {
 		return make(map[string]any), nil
 	}
@@ -586,7 +753,7 @@ func (s *SQLSessionService) getUserState(ctx context.Context, appName, userID st
 //nolint:unused // Reserved for future use
 func (s *SQLSessionService) upsertAppState(ctx context.Context, appName string, delta map[string]any) error {
 	// Get existing state
-	existing, _ := s.getAppState(ctx, appName)
+	existing, _ := s.getAppState(ctx, s.db, appName)
 	maps.Copy(existing, delta)
 
 	stateJSON, err := json.Marshal(existing)
@@ -748,10 +915,10 @@ func (s *SQLSessionService) touchSessionTx(ctx context.Context, tx *sql.Tx, appN
 
 //nolint:unused // Reserved for future use
 func (s *SQLSessionService) getEvents(ctx context.Context, appName, userID, sessionID string) ([]*agent.Event, error) {
-	return s.getEventsFiltered(ctx, appName, userID, sessionID, 0, time.Time{})
+	return s.getEventsFiltered(ctx, s.db, appName, userID, sessionID, 0, time.Time{})
 }
 
-func (s *SQLSessionService) getEventsFiltered(ctx context.Context, appName, userID, sessionID string, numRecent int, after time.Time) ([]*agent.Event, error) {
+func (s *SQLSessionService) getEventsFiltered(ctx context.Context, q queryer, appName, userID, sessionID string, numRecent int, after time.Time) ([]*agent.Event, error) {
 	// Column list for SELECT
 	cols := `id, app_name, user_id, session_id, author, invocation_id, branch,
               role, content_json, state_delta_json, artifact_delta_json,
@@ -798,7 +965,7 @@ func (s *SQLSessionService) getEventsFiltered(ctx context.Context, appName, user
 		query = convertToPostgresPlaceholders(query)
 	}
 
-	rows, err := s.db.QueryContext(ctx, query, args...)
+	rows, err := q.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -1202,4 +1369,8 @@ func parseA2APart(raw json.RawMessage) (a2a.Part, error) {
 }
 
 // Compile-time interface check
-var _ Service = (*SQLSessionService)(nil)
+var (
+	_ Service  = (*SQLSessionService)(nil)
+	_ Expirer  = (*SQLSessionService)(nil)
+	_ Rewinder = (*SQLSessionService)(nil)
+)
@@ -0,0 +1,139 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/config"
+)
+
+func TestSQLSessionServiceReadReplica(t *testing.T) {
+	dir := t.TempDir()
+	pool := config.NewDBPool()
+	defer pool.Close()
+
+	db, err := pool.GetRouted(&config.DatabaseConfig{
+		Driver:       "sqlite",
+		Database:     filepath.Join(dir, "primary.db"),
+		ReadReplicas: []string{filepath.Join(dir, "replica.db")},
+	})
+	if err != nil {
+		t.Fatalf("GetRouted() error = %v", err)
+	}
+	svc, err := NewSQLSessionService(db.Primary(), "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc.replicas = db
+	replica, err := NewSQLSessionService(db.Replica(), "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	if _, err := svc.Create(ctx, &CreateRequest{AppName: "app", UserID: "user", SessionID: "written"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := replica.Create(ctx, &CreateRequest{AppName: "app", UserID: "user", SessionID: "replicated"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reads are served by the replica
+	list, err := svc.List(ctx, &ListRequest{AppName: "app", UserID: "user"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Sessions) != 1 || list.Sessions[0].ID() != "replicated" {
+		t.Errorf("List() = %d sessions, want only the replicated one", len(list.Sessions))
+	}
+
+	// Gets read the primary unless the caller tolerates stale reads
+	if _, err := svc.Get(ctx, &GetRequest{AppName: "app", UserID: "user", SessionID: "replicated"}); err == nil {
+		t.Error("Get(replicated) read from the replica without WithReplicaReads")
+	}
+	replicaCtx := config.WithReplicaReads(ctx)
+	if _, err := svc.Get(replicaCtx, &GetRequest{AppName: "app", UserID: "user", SessionID: "replicated"}); err != nil {
+		t.Errorf("Get(replicated) with replica reads error = %v", err)
+	}
+
+	// A session the replica has not seen yet is read from the primary
+	resp, err := svc.Get(replicaCtx, &GetRequest{AppName: "app", UserID: "user", SessionID: "written"})
+	if err != nil || resp.Session.ID() != "written" {
+		t.Errorf("Get(written) = %v, %v", resp, err)
+	}
+}
+
+func TestRewind(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "sessions.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	sqlService, err := NewSQLSessionService(db, "sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, svc := range map[string]Service{"memory": InMemoryService(), "sql": sqlService} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			created, err := svc.Create(ctx, &CreateRequest{AppName: "app", UserID: "user", SessionID: "s1"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, delta := range []map[string]any{{"step": "one"}, {"step": "two", "user:tier": "pro"}, {"step": "three"}} {
+				event := agent.NewEvent("inv")
+				event.Actions.StateDelta = delta
+				for k, v := range delta {
+					_ = created.Session.State().Set(k, v)
+				}
+				if err := svc.AppendEvent(ctx, created.Session, event); err != nil {
+					t.Fatalf("AppendEvent(%d) error = %v", i, err)
+				}
+			}
+
+			err = svc.(Rewinder).Rewind(ctx, &RewindRequest{
+				AppName:   "app",
+				UserID:    "user",
+				SessionID: "s1",
+				NumEvents: 1,
+				State:     map[string]any{"step": "one", "user:tier": "free"},
+			})
+			if err != nil {
+				t.Fatalf("Rewind() error = %v", err)
+			}
+
+			resp, err := svc.Get(ctx, &GetRequest{AppName: "app", UserID: "user", SessionID: "s1"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n := resp.Session.Events().Len(); n != 1 {
+				t.Errorf("events = %d, want 1", n)
+			}
+			if step, _ := resp.Session.State().Get("step"); step != "one" {
+				t.Errorf("step = %v, want one", step)
+			}
+			// User-scoped state is shared with other sessions and kept
+			if tier, _ := resp.Session.State().Get("user:tier"); tier != "pro" {
+				t.Errorf("user:tier = %v, want pro", tier)
+			}
+
+			// New events continue after the kept ones
+			if err := svc.AppendEvent(ctx, resp.Session, agent.NewEvent("inv")); err != nil {
+				t.Fatalf("AppendEvent() after rewind error = %v", err)
+			}
+			resp, _ = svc.Get(ctx, &GetRequest{AppName: "app", UserID: "user", SessionID: "s1"})
+			if n := resp.Session.Events().Len(); n != 2 {
+				t.Errorf("events after append = %d, want 2", n)
+			}
+
+			err = svc.(Rewinder).Rewind(ctx, &RewindRequest{AppName: "app", UserID: "user", SessionID: "missing"})
+			if err != ErrSessionNotFound {
+				t.Errorf("Rewind(missing) error = %v, want ErrSessionNotFound", err)
+			}
+		})
+	}
+}
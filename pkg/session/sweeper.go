@@ -0,0 +1,138 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package session
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Expirer is implemented by session services that can delete idle sessions.
+type Expirer interface {
+	// DeleteExpired deletes sessions last updated before the given time and
+	// returns how many were deleted.
+	DeleteExpired(ctx context.Context, before time.Time) (int, error)
+}
+
+// SweeperConfig configures a Sweeper.
+type SweeperConfig struct {
+	// TTL is how long a session may go without new events before it is
+	// deleted. Required.
+	TTL time.Duration
+
+	// Interval is how often expired sessions are deleted.
+	// Default: TTL
+	Interval time.Duration
+
+	// OnSweep is called after each sweep with the number of sessions
+	// deleted. Optional.
+	OnSweep func(deleted int)
+}
+
+// Sweeper periodically deletes sessions idle longer than a TTL.
+type Sweeper struct {
+	expirer Expirer
+	cfg     SweeperConfig
+
+	mu   sync.Mutex
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewSweeper creates a Sweeper for svc, which must implement Expirer.
+// Call Start to begin sweeping.
+func NewSweeper(svc Service, cfg SweeperConfig) (*Sweeper, error) {
+	expirer, ok := svc.(Expirer)
+	if !ok {
+		return nil, fmt.Errorf("session service %T does not support expiry", svc)
+	}
+	if cfg.TTL <= 0 {
+		return nil, fmt.Errorf("session TTL must be positive")
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = cfg.TTL
+	}
+	return &Sweeper{expirer: expirer, cfg: cfg}, nil
+}
+
+// Start begins sweeping in the background. It is a no-op if the sweeper
+// is already running.
+func (s *Sweeper) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	go s.run(s.stop, s.done)
+}
+
+// Stop stops sweeping and waits for a running sweep to finish.
+func (s *Sweeper) Stop() {
+	s.mu.Lock()
+	stop, done := s.stop, s.done
+	s.stop, s.done = nil, nil
+	s.mu.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// Sweep deletes the sessions that have expired and returns how many were
+// deleted.
+func (s *Sweeper) Sweep(ctx context.Context) (int, error) {
+	deleted, err := s.expirer.DeleteExpired(ctx, time.Now().Add(-s.cfg.TTL))
+	if s.cfg.OnSweep != nil {
+		s.cfg.OnSweep(deleted)
+	}
+	return deleted, err
+}
+
+func (s *Sweeper) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			deleted, err := s.Sweep(ctx)
+			if err != nil && ctx.Err() == nil {
+				slog.Warn("Failed to delete expired sessions", "deleted", deleted, "error", err)
+			} else if deleted > 0 {
+				slog.Debug("Deleted expired sessions", "deleted", deleted, "ttl", s.cfg.TTL)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
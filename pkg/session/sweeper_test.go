@@ -0,0 +1,139 @@
+package session
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+)
+
+// createSessions creates an "old" and a "new" session, each with one event.
+func createSessions(t *testing.T, svc Service) {
+	t.Helper()
+	ctx := context.Background()
+	for _, id := range []string{"old", "new"} {
+		resp, err := svc.Create(ctx, &CreateRequest{AppName: "app", UserID: "user", SessionID: id})
+		if err != nil {
+			t.Fatalf("Create(%s) error = %v", id, err)
+		}
+		event := agent.NewEvent("inv-" + id)
+		event.Author = "user"
+		event.Message = a2a.NewMessage(a2a.MessageRoleUser, a2a.TextPart{Text: "hi"})
+		if err := svc.AppendEvent(ctx, resp.Session, event); err != nil {
+			t.Fatalf("AppendEvent(%s) error = %v", id, err)
+		}
+	}
+}
+
+func sweep(t *testing.T, svc Service) int {
+	t.Helper()
+	var swept int
+	sweeper, err := NewSweeper(svc, SweeperConfig{TTL: time.Hour, OnSweep: func(n int) { swept += n }})
+	if err != nil {
+		t.Fatalf("NewSweeper() error = %v", err)
+	}
+	deleted, err := sweeper.Sweep(context.Background())
+	if err != nil {
+		t.Fatalf("Sweep() error = %v", err)
+	}
+	if swept != deleted {
+		t.Errorf("OnSweep got %d, Sweep returned %d", swept, deleted)
+	}
+	return deleted
+}
+
+func assertSwept(t *testing.T, svc Service) {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := svc.Get(ctx, &GetRequest{AppName: "app", UserID: "user", SessionID: "old"}); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("old session: err = %v, want ErrSessionNotFound", err)
+	}
+	resp, err := svc.Get(ctx, &GetRequest{AppName: "app", UserID: "user", SessionID: "new"})
+	if err != nil {
+		t.Fatalf("new session: err = %v", err)
+	}
+	if n := resp.Session.Events().Len(); n != 1 {
+		t.Errorf("new session events = %d, want 1", n)
+	}
+}
+
+func TestSweeperInMemory(t *testing.T) {
+	svc := InMemoryService()
+	createSessions(t, svc)
+	svc.(*inMemoryService).sessions["app:user:old"].lastUpdateTime = time.Now().Add(-2 * time.Hour)
+
+	if deleted := sweep(t, svc); deleted != 1 {
+		t.Errorf("deleted = %d, want 1", deleted)
+	}
+	assertSwept(t, svc)
+}
+
+func TestSweeperSQL(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "sessions.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	svc, err := NewSQLSessionService(db, "sqlite")
+	if err != nil {
+		t.Fatalf("NewSQLSessionService() error = %v", err)
+	}
+	createSessions(t, svc)
+	if _, err := db.Exec(`UPDATE sessions SET updated_at = ? WHERE id = 'old'`, time.Now().Add(-2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	if deleted := sweep(t, svc); deleted != 1 {
+		t.Errorf("deleted = %d, want 1", deleted)
+	}
+	assertSwept(t, svc)
+
+	var events int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM session_events WHERE session_id = 'old'`).Scan(&events); err != nil {
+		t.Fatal(err)
+	}
+	if events != 0 {
+		t.Errorf("events of expired session = %d, want 0", events)
+	}
+}
+
+func TestSweeperStartStop(t *testing.T) {
+	swept := make(chan int, 1)
+	sweeper, err := NewSweeper(InMemoryService(), SweeperConfig{
+		TTL:      time.Hour,
+		Interval: time.Millisecond,
+		OnSweep: func(n int) {
+			select {
+			case swept <- n:
+			default:
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewSweeper() error = %v", err)
+	}
+	sweeper.Start()
+	select {
+	case <-swept:
+	case <-time.After(time.Second):
+		t.Error("sweeper did not run")
+	}
+	sweeper.Stop()
+	sweeper.Stop()
+}
+
+func TestNewSweeperValidation(t *testing.T) {
+	if _, err := NewSweeper(InMemoryService(), SweeperConfig{}); err == nil {
+		t.Error("expected error without TTL")
+	}
+	if _, err := NewSweeper(struct{ Service }{}, SweeperConfig{TTL: time.Hour}); err == nil {
+		t.Error("expected error for service without expiry")
+	}
+}
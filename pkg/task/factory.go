@@ -61,9 +61,16 @@ func NewTaskStoreFromConfig(cfg *config.Config, pool *config.DBPool) (a2asrv.Tas
 		return nil, fmt.Errorf("database %q not found", dbName)
 	}
 
-	db, err := pool.Get(dbCfg)
+	db, err := pool.GetRouted(dbCfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get database connection: %w", err)
 	}
-	return NewSQLTaskStore(db, dbCfg.Dialect())
+	store, err := newSQLTaskStore(db.Primary(), dbCfg.Dialect())
+	if err != nil {
+		return nil, err
+	}
+	if db.HasReplicas() {
+		store.replicas = db
+	}
+	return store, nil
 }
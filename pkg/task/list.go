@@ -0,0 +1,294 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+)
+
+const (
+	// DefaultListPageSize is the page size used when none is requested.
+	DefaultListPageSize = 50
+
+	// MaxListPageSize is the largest page size served.
+	MaxListPageSize = 100
+)
+
+// Task metadata keys attributing a task to the agent that ran it and the
+// caller that created it. The server stamps them on a task's first save;
+// the owner is the authenticated subject, or "" for anonymous callers.
+const (
+	MetaKeyAgent = "hector:agent"
+	MetaKeyOwner = "hector:owner"
+)
+
+// ErrInvalidPageToken is returned when a page token was not issued by List.
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// ErrInvalidTaskState is returned by ParseTaskState for an unknown state.
+var ErrInvalidTaskState = errors.New("invalid task state")
+
+// ListableStates are the task states accepted as list filters.
+var ListableStates = []a2a.TaskState{
+	a2a.TaskStateSubmitted,
+	a2a.TaskStateWorking,
+	a2a.TaskStateInputRequired,
+	a2a.TaskStateAuthRequired,
+	a2a.TaskStateCompleted,
+	a2a.TaskStateCanceled,
+	a2a.TaskStateFailed,
+	a2a.TaskStateRejected,
+	a2a.TaskStateUnknown,
+}
+
+// ParseTaskState parses a state filter such as "failed" or "input-required".
+func ParseTaskState(s string) (a2a.TaskState, error) {
+	state := a2a.TaskState(s)
+	if !slices.Contains(ListableStates, state) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidTaskState, s)
+	}
+	return state, nil
+}
+
+// ListRequest selects a page of tasks, newest first.
+type ListRequest struct {
+	// ContextID limits the list to tasks of one context. Optional.
+	ContextID string
+
+	// Agents limits the list to tasks of any of these agents. Optional.
+	Agents []string
+
+	// Owner limits the list to tasks created by one caller. Optional; a
+	// pointer to "" lists only tasks of anonymous callers.
+	Owner *string
+
+	// PageSize is the most tasks returned. Default: DefaultListPageSize,
+	// capped at MaxListPageSize.
+	PageSize int
+
+	// PageToken continues from the NextPageToken of a previous page.
+	PageToken string
+
+	// States limits the list to tasks in any of these states. Optional.
+	States []a2a.TaskState
+
+	// CreatedAfter limits the list to tasks created at or after this
+	// time. Optional.
+	CreatedAfter time.Time
+
+	// CreatedBefore limits the list to tasks created before this time.
+	// Optional.
+	CreatedBefore time.Time
+}
+
+func (r *ListRequest) pageSize() int {
+	if r.PageSize <= 0 {
+		return DefaultListPageSize
+	}
+	return min(r.PageSize, MaxListPageSize)
+}
+
+// matches reports whether a task passes the state and time filters.
+func (r *ListRequest) matches(state a2a.TaskState, createdAt time.Time) bool {
+	if len(r.States) > 0 && !slices.Contains(r.States, state) {
+		return false
+	}
+	if !r.CreatedAfter.IsZero() && createdAt.Before(r.CreatedAfter) {
+		return false
+	}
+	if !r.CreatedBefore.IsZero() && !createdAt.Before(r.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+// matchesAttribution reports whether a task passes the agent and owner
+// filters.
+func (r *ListRequest) matchesAttribution(agent, owner string) bool {
+	if len(r.Agents) > 0 && !slices.Contains(r.Agents, agent) {
+		return false
+	}
+	return r.Owner == nil || *r.Owner == owner
+}
+
+// Attribution returns the agent and owner stamped on a task, and whether
+// the task carries them at all (tasks saved before attribution do not).
+func Attribution(t *a2a.Task) (agent, owner string, ok bool) {
+	if t == nil || t.Metadata == nil {
+		return "", "", false
+	}
+	agent, ok = t.Metadata[MetaKeyAgent].(string)
+	owner, _ = t.Metadata[MetaKeyOwner].(string)
+	return agent, owner, ok
+}
+
+// ListResponse is a page of tasks.
+type ListResponse struct {
+	Tasks []*a2a.Task
+
+	// NextPageToken fetches the next page. Empty on the last page.
+	NextPageToken string
+}
+
+// Lister is implemented by task stores that can list tasks.
+type Lister interface {
+	List(ctx context.Context, req *ListRequest) (*ListResponse, error)
+}
+
+// pageCursor is the position after the last task of a page. Tasks are
+// ordered by (created_at, id) descending, so the cursor stays valid while
+// new tasks are created.
+type pageCursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        string    `json:"i"`
+}
+
+func (c pageCursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// after reports whether a task comes after the cursor in list order.
+func (c pageCursor) after(createdAt time.Time, id string) bool {
+	return createdAt.Before(c.CreatedAt) || (createdAt.Equal(c.CreatedAt) && id < c.ID)
+}
+
+func decodePageCursor(token string) (*pageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	var c pageCursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == "" || c.CreatedAt.IsZero() {
+		return nil, ErrInvalidPageToken
+	}
+	return &c, nil
+}
+
+// List returns a page of tasks, newest first (implements Lister).
+//
+// Pages are selected by keyset on (created_at, id) rather than by offset,
+// so deep pages cost the same as the first and tasks created while paging
+// do not shift later pages.
+func (s *SQLTaskStore) List(ctx context.Context, req *ListRequest) (*ListResponse, error) {
+	pageSize := req.pageSize()
+
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		if s.dialect == "postgres" {
+			return fmt.Sprintf("$%d", len(args))
+		}
+		return "?"
+	}
+
+	query := `
+SELECT id, context_id, status_json, history_json, artifacts_json, metadata_json, created_at, updated_at
+FROM a2a_tasks
+WHERE 1 = 1`
+	if req.ContextID != "" {
+		query += ` AND context_id = ` + arg(req.ContextID)
+	}
+	if len(req.Agents) > 0 {
+		placeholders := make([]string, len(req.Agents))
+		for i, agent := range req.Agents {
+			placeholders[i] = arg(agent)
+		}
+		query += ` AND agent IN (` + strings.Join(placeholders, ", ") + `)`
+	}
+	if req.Owner != nil {
+		query += ` AND owner = ` + arg(*req.Owner)
+	}
+	if len(req.States) > 0 {
+		placeholders := make([]string, len(req.States))
+		for i, state := range req.States {
+			placeholders[i] = arg(string(state))
+		}
+		query += ` AND state IN (` + strings.Join(placeholders, ", ") + `)`
+	}
+	// created_at is written in local time; SQLite compares timestamps as
+	// text, so bounds are converted to match
+	if !req.CreatedAfter.IsZero() {
+		query += ` AND created_at >= ` + arg(req.CreatedAfter.Local())
+	}
+	if !req.CreatedBefore.IsZero() {
+		query += ` AND created_at < ` + arg(req.CreatedBefore.Local())
+	}
+	if req.PageToken != "" {
+		cursor, err := decodePageCursor(req.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		query += fmt.Sprintf(` AND (created_at < %s OR (created_at = %s AND id < %s))`,
+			arg(cursor.CreatedAt), arg(cursor.CreatedAt), arg(cursor.ID))
+	}
+	// Fetch one extra row to learn whether another page follows
+	query += `
+ORDER BY created_at DESC, id DESC
+LIMIT ` + arg(pageSize+1)
+
+	db := s.db
+	if s.replicas != nil {
+		db = s.replicas.Replica()
+	}
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var taskRows []taskStoreRow
+	for rows.Next() {
+		var row taskStoreRow
+		if err := rows.Scan(
+			&row.ID, &row.ContextID, &row.StatusJSON,
+			&row.HistoryJSON, &row.ArtifactsJSON, &row.MetadataJSON,
+			&row.CreatedAt, &row.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		taskRows = append(taskRows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+
+	resp := &ListResponse{Tasks: make([]*a2a.Task, 0, min(len(taskRows), pageSize))}
+	if len(taskRows) > pageSize {
+		taskRows = taskRows[:pageSize]
+		last := taskRows[pageSize-1]
+		resp.NextPageToken = pageCursor{CreatedAt: last.CreatedAt, ID: last.ID}.encode()
+	}
+	for i := range taskRows {
+		task, err := s.rowToTask(&taskRows[i])
+		if err != nil {
+			return nil, err
+		}
+		resp.Tasks = append(resp.Tasks, task)
+	}
+	return resp, nil
+}
+
+var _ Lister = (*SQLTaskStore)(nil)
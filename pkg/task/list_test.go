@@ -0,0 +1,271 @@
+package task
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+)
+
+func newTestStore(t *testing.T) *SQLTaskStore {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "tasks.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	store, err := newSQLTaskStore(db, "sqlite")
+	if err != nil {
+		t.Fatalf("newSQLTaskStore() error = %v", err)
+	}
+	return store
+}
+
+func saveTasks(t *testing.T, store a2asrv.TaskStore, contextID string, ids ...string) {
+	t.Helper()
+	saveTasksInState(t, store, contextID, a2a.TaskStateCompleted, ids...)
+}
+
+func saveTasksInState(t *testing.T, store a2asrv.TaskStore, contextID string, state a2a.TaskState, ids ...string) {
+	t.Helper()
+	for _, id := range ids {
+		task := &a2a.Task{ID: a2a.TaskID(id), ContextID: contextID, Status: a2a.TaskStatus{State: state}}
+		if err := store.Save(context.Background(), task); err != nil {
+			t.Fatalf("Save(%s) error = %v", id, err)
+		}
+	}
+}
+
+func TestSQLTaskStoreListPaginates(t *testing.T) {
+	store := newTestStore(t)
+	var ids []string
+	for i := range 25 {
+		ids = append(ids, fmt.Sprintf("task-%02d", i))
+	}
+	saveTasks(t, store, "ctx", ids...)
+
+	ctx := context.Background()
+	var got []string
+	req := &ListRequest{PageSize: 10}
+	for page := 0; ; page++ {
+		resp, err := store.List(ctx, req)
+		if err != nil {
+			t.Fatalf("List() page %d error = %v", page, err)
+		}
+		for _, task := range resp.Tasks {
+			got = append(got, string(task.ID))
+		}
+		if page == 0 {
+			// Tasks created while paging do not shift later pages
+			saveTasks(t, store, "ctx", "task-new")
+		}
+		if resp.NextPageToken == "" {
+			break
+		}
+		req.PageToken = resp.NextPageToken
+	}
+
+	if len(got) != 25 {
+		t.Fatalf("listed %d tasks, want 25: %v", len(got), got)
+	}
+	for i, id := range got {
+		if want := ids[len(ids)-1-i]; id != want {
+			t.Errorf("task %d = %s, want %s (newest first)", i, id, want)
+		}
+	}
+}
+
+func TestSQLTaskStoreListFilters(t *testing.T) {
+	store := newTestStore(t)
+	saveTasks(t, store, "a", "a1", "a2", "a3")
+	saveTasks(t, store, "b", "b1")
+
+	ctx := context.Background()
+	resp, err := store.List(ctx, &ListRequest{ContextID: "a", PageSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Tasks) != 2 || resp.NextPageToken == "" {
+		t.Fatalf("first page = %d tasks, token %q", len(resp.Tasks), resp.NextPageToken)
+	}
+	resp, err = store.List(ctx, &ListRequest{ContextID: "a", PageSize: 2, PageToken: resp.NextPageToken})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Tasks) != 1 || resp.Tasks[0].ID != "a1" || resp.NextPageToken != "" {
+		t.Errorf("last page = %v, token %q", resp.Tasks, resp.NextPageToken)
+	}
+
+	if _, err := store.List(ctx, &ListRequest{PageToken: "not-a-token"}); !errors.Is(err, ErrInvalidPageToken) {
+		t.Errorf("List() with bad token error = %v, want ErrInvalidPageToken", err)
+	}
+}
+
+type listableStore interface {
+	a2asrv.TaskStore
+	Lister
+}
+
+func TestListFiltersByStateAndTime(t *testing.T) {
+	stores := map[string]func(t *testing.T) listableStore{
+		"sql":      func(t *testing.T) listableStore { return newTestStore(t) },
+		"inmemory": func(t *testing.T) listableStore { return NewInMemoryTaskStore() },
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := newStore(t)
+			saveTasksInState(t, store, "ctx", a2a.TaskStateFailed, "old-failed")
+			time.Sleep(20 * time.Millisecond)
+			mid := time.Now()
+			saveTasksInState(t, store, "ctx", a2a.TaskStateFailed, "f1", "f2")
+			saveTasksInState(t, store, "ctx", a2a.TaskStateCanceled, "c1")
+			saveTasksInState(t, store, "ctx", a2a.TaskStateCompleted, "ok")
+
+			list := func(req *ListRequest) []string {
+				t.Helper()
+				var ids []string
+				for {
+					resp, err := store.List(context.Background(), req)
+					if err != nil {
+						t.Fatalf("List() error = %v", err)
+					}
+					for _, task := range resp.Tasks {
+						ids = append(ids, string(task.ID))
+					}
+					if resp.NextPageToken == "" {
+						return ids
+					}
+					req.PageToken = resp.NextPageToken
+				}
+			}
+
+			tests := []struct {
+				name string
+				req  *ListRequest
+				want string
+			}{
+				{"state", &ListRequest{States: []a2a.TaskState{a2a.TaskStateFailed}}, "f2,f1,old-failed"},
+				{"states", &ListRequest{States: []a2a.TaskState{a2a.TaskStateFailed, a2a.TaskStateCanceled}, PageSize: 1}, "c1,f2,f1,old-failed"},
+				{"since", &ListRequest{States: []a2a.TaskState{a2a.TaskStateFailed}, CreatedAfter: mid.UTC()}, "f2,f1"},
+				{"until", &ListRequest{CreatedBefore: mid}, "old-failed"},
+				{"no match", &ListRequest{States: []a2a.TaskState{a2a.TaskStateWorking}}, ""},
+			}
+			for _, tt := range tests {
+				if got := strings.Join(list(tt.req), ","); got != tt.want {
+					t.Errorf("%s: listed %q, want %q", tt.name, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestListFiltersByAgentAndOwner(t *testing.T) {
+	stores := map[string]func(t *testing.T) listableStore{
+		"sql":      func(t *testing.T) listableStore { return newTestStore(t) },
+		"inmemory": func(t *testing.T) listableStore { return NewInMemoryTaskStore() },
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := newStore(t)
+			for _, tc := range []struct{ id, agent, owner string }{
+				{"a-alice", "a", "alice"},
+				{"a-bob", "a", "bob"},
+				{"a-anon", "a", ""},
+				{"b-alice", "b", "alice"},
+			} {
+				task := &a2a.Task{
+					ID:        a2a.TaskID(tc.id),
+					ContextID: "ctx",
+					Status:    a2a.TaskStatus{State: a2a.TaskStateCompleted},
+					Metadata:  map[string]any{MetaKeyAgent: tc.agent, MetaKeyOwner: tc.owner},
+				}
+				if err := store.Save(context.Background(), task); err != nil {
+					t.Fatal(err)
+				}
+			}
+			saveTasks(t, store, "ctx", "legacy")
+
+			alice, anonymous := "alice", ""
+			tests := []struct {
+				name string
+				req  *ListRequest
+				want []string
+			}{
+				{"agent", &ListRequest{Agents: []string{"a"}}, []string{"a-alice", "a-anon", "a-bob"}},
+				{"agents and owner", &ListRequest{Agents: []string{"a", "b"}, Owner: &alice}, []string{"a-alice", "b-alice"}},
+				{"anonymous owner", &ListRequest{Agents: []string{"a"}, Owner: &anonymous}, []string{"a-anon"}},
+				{"unfiltered", &ListRequest{}, []string{"a-alice", "a-anon", "a-bob", "b-alice", "legacy"}},
+			}
+			for _, tt := range tests {
+				resp, err := store.List(context.Background(), tt.req)
+				if err != nil {
+					t.Fatalf("%s: List() error = %v", tt.name, err)
+				}
+				var got []string
+				for _, task := range resp.Tasks {
+					got = append(got, string(task.ID))
+				}
+				slices.Sort(got)
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("%s: listed %v, want %v", tt.name, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSQLTaskStoreMigratesStateColumn(t *testing.T) {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "tasks.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	// Table as created before the state column existed
+	_, err = db.Exec(`CREATE TABLE a2a_tasks (
+    id VARCHAR(255) PRIMARY KEY,
+    context_id VARCHAR(255) NOT NULL,
+    status_json TEXT NOT NULL,
+    history_json TEXT,
+    artifacts_json TEXT,
+    metadata_json TEXT,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	_, err = db.Exec(`INSERT INTO a2a_tasks VALUES ('t1', 'ctx', '{"state":"failed"}', '[]', '[]', '{}', ?, ?)`, now, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := newSQLTaskStore(db, "sqlite")
+	if err != nil {
+		t.Fatalf("newSQLTaskStore() error = %v", err)
+	}
+	resp, err := store.List(context.Background(), &ListRequest{States: []a2a.TaskState{a2a.TaskStateFailed}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Tasks) != 1 || resp.Tasks[0].ID != "t1" {
+		t.Errorf("failed tasks after migration = %v", resp.Tasks)
+	}
+}
+
+func TestParseTaskState(t *testing.T) {
+	if state, err := ParseTaskState("input-required"); err != nil || state != a2a.TaskStateInputRequired {
+		t.Errorf("ParseTaskState(input-required) = %q, %v", state, err)
+	}
+	if _, err := ParseTaskState("done"); !errors.Is(err, ErrInvalidTaskState) {
+		t.Errorf("ParseTaskState(done) error = %v, want ErrInvalidTaskState", err)
+	}
+}
@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package task
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/a2aproject/a2a-go/a2a"
+	"github.com/a2aproject/a2a-go/a2asrv"
+)
+
+// InMemoryTaskStore implements a2asrv.TaskStore and Lister in memory.
+// Tasks are copied on Save and Get, so callers never share a stored task.
+type InMemoryTaskStore struct {
+	mu    sync.RWMutex
+	tasks map[a2a.TaskID]*memoryTask
+}
+
+// memoryTask is a stored task with its creation time, used for ordering.
+type memoryTask struct {
+	data      []byte
+	state     a2a.TaskState
+	contextID string
+	agent     string
+	owner     string
+	createdAt time.Time
+}
+
+// NewInMemoryTaskStore creates an empty in-memory task store.
+func NewInMemoryTaskStore() *InMemoryTaskStore {
+	return &InMemoryTaskStore{tasks: make(map[a2a.TaskID]*memoryTask)}
+}
+
+// Save stores a copy of task (implements a2asrv.TaskStore).
+func (s *InMemoryTaskStore) Save(_ context.Context, task *a2a.Task) error {
+	if task == nil {
+		return fmt.Errorf("task is required")
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to serialize task: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	createdAt := time.Now()
+	if existing, ok := s.tasks[task.ID]; ok {
+		createdAt = existing.createdAt
+	}
+	agent, owner, _ := Attribution(task)
+	s.tasks[task.ID] = &memoryTask{
+		data:      data,
+		state:     task.Status.State,
+		contextID: task.ContextID,
+		agent:     agent,
+		owner:     owner,
+		createdAt: createdAt,
+	}
+	return nil
+}
+
+// Get returns a copy of a stored task (implements a2asrv.TaskStore).
+func (s *InMemoryTaskStore) Get(_ context.Context, taskID a2a.TaskID) (*a2a.Task, error) {
+	s.mu.RLock()
+	stored, ok := s.tasks[taskID]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, a2a.ErrTaskNotFound
+	}
+	return stored.task()
+}
+
+// List returns a page of tasks, newest first (implements Lister).
+// Filters are applied in Go over all stored tasks.
+func (s *InMemoryTaskStore) List(_ context.Context, req *ListRequest) (*ListResponse, error) {
+	pageSize := req.pageSize()
+	var cursor *pageCursor
+	if req.PageToken != "" {
+		var err error
+		if cursor, err = decodePageCursor(req.PageToken); err != nil {
+			return nil, err
+		}
+	}
+
+	type entry struct {
+		id a2a.TaskID
+		*memoryTask
+	}
+	s.mu.RLock()
+	var matched []entry
+	for id, stored := range s.tasks {
+		if req.ContextID != "" && stored.contextID != req.ContextID {
+			continue
+		}
+		if !req.matches(stored.state, stored.createdAt) || !req.matchesAttribution(stored.agent, stored.owner) {
+			continue
+		}
+		if cursor != nil && !cursor.after(stored.createdAt, string(id)) {
+			continue
+		}
+		matched = append(matched, entry{id: id, memoryTask: stored})
+	}
+	s.mu.RUnlock()
+
+	slices.SortFunc(matched, func(a, b entry) int {
+		if c := b.createdAt.Compare(a.createdAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.id, a.id)
+	})
+
+	resp := &ListResponse{Tasks: make([]*a2a.Task, 0, min(len(matched), pageSize))}
+	if len(matched) > pageSize {
+		matched = matched[:pageSize]
+		last := matched[pageSize-1]
+		resp.NextPageToken = pageCursor{CreatedAt: last.createdAt, ID: string(last.id)}.encode()
+	}
+	for _, e := range matched {
+		task, err := e.task()
+		if err != nil {
+			return nil, err
+		}
+		resp.Tasks = append(resp.Tasks, task)
+	}
+	return resp, nil
+}
+
+func (m *memoryTask) task() (*a2a.Task, error) {
+	var task a2a.Task
+	if err := json.Unmarshal(m.data, &task); err != nil {
+		return nil, fmt.Errorf("failed to deserialize task: %w", err)
+	}
+	return &task, nil
+}
+
+// Compile-time interface compliance checks
+var (
+	_ a2asrv.TaskStore = (*InMemoryTaskStore)(nil)
+	_ Lister           = (*InMemoryTaskStore)(nil)
+)
@@ -19,6 +19,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
@@ -26,6 +27,8 @@ import (
 	"github.com/a2aproject/a2a-go/a2a"
 	"github.com/a2aproject/a2a-go/a2asrv"
 
+	"github.com/kadirpekel/hector/pkg/config"
+
 	_ "github.com/go-sql-driver/mysql"
 	_ "github.com/lib/pq"
 	_ "github.com/mattn/go-sqlite3"
@@ -36,12 +39,19 @@ import (
 type SQLTaskStore struct {
 	db      *sql.DB
 	dialect string
+
+	// replicas serves List, and Get for contexts marked with
+	// config.WithReplicaReads, when read replicas are configured
+	replicas *config.RoutedDB
 }
 
 // taskStoreRow represents a database row for an a2a.Task.
 type taskStoreRow struct {
 	ID            string
 	ContextID     string
+	State         string
+	Agent         string
+	Owner         string
 	StatusJSON    string
 	HistoryJSON   string
 	ArtifactsJSON string
@@ -57,6 +67,9 @@ const (
 CREATE TABLE IF NOT EXISTS a2a_tasks (
     id VARCHAR(255) PRIMARY KEY,
     context_id VARCHAR(255) NOT NULL,
+    state VARCHAR(32) NOT NULL DEFAULT '',
+    agent VARCHAR(255) NOT NULL DEFAULT '',
+    owner VARCHAR(255) NOT NULL DEFAULT '',
     status_json TEXT NOT NULL,
     history_json TEXT,
     artifacts_json TEXT,
@@ -70,12 +83,41 @@ CREATE INDEX IF NOT EXISTS idx_a2a_tasks_context_id ON a2a_tasks(context_id)`
 
 	createTaskStoreUpdatedAtIndexSQL = `
 CREATE INDEX IF NOT EXISTS idx_a2a_tasks_updated_at ON a2a_tasks(updated_at)`
+
+	// createTaskStoreCreatedAtIndexSQL serves keyset pagination in List.
+	createTaskStoreCreatedAtIndexSQL = `
+CREATE INDEX IF NOT EXISTS idx_a2a_tasks_created_at_id ON a2a_tasks(created_at, id)`
+
+	// createTaskStoreStateIndexSQL serves state filters in List.
+	createTaskStoreStateIndexSQL = `
+CREATE INDEX IF NOT EXISTS idx_a2a_tasks_state_created_at ON a2a_tasks(state, created_at)`
+
+	// createTaskStoreAgentIndexSQL serves agent and owner filters in List.
+	createTaskStoreAgentIndexSQL = `
+CREATE INDEX IF NOT EXISTS idx_a2a_tasks_agent_owner_created_at ON a2a_tasks(agent, owner, created_at)`
+
+	// addTaskStoreAgentColumnSQL and addTaskStoreOwnerColumnSQL upgrade
+	// tables created before tasks were attributed. Older tasks keep empty
+	// values and are not listed by agent.
+	addTaskStoreAgentColumnSQL = `
+ALTER TABLE a2a_tasks ADD COLUMN agent VARCHAR(255) NOT NULL DEFAULT ''`
+	addTaskStoreOwnerColumnSQL = `
+ALTER TABLE a2a_tasks ADD COLUMN owner VARCHAR(255) NOT NULL DEFAULT ''`
+
+	// addTaskStoreStateColumnSQL upgrades tables created before the state
+	// column existed.
+	addTaskStoreStateColumnSQL = `
+ALTER TABLE a2a_tasks ADD COLUMN state VARCHAR(32) NOT NULL DEFAULT ''`
 )
 
 // NewSQLTaskStore creates a new SQL-based TaskStore implementing a2asrv.TaskStore.
 // The db connection should be shared with other services using the same database
 // to prevent SQLite "database is locked" errors.
 func NewSQLTaskStore(db *sql.DB, dialect string) (a2asrv.TaskStore, error) {
+	return newSQLTaskStore(db, dialect)
+}
+
+func newSQLTaskStore(db *sql.DB, dialect string) (*SQLTaskStore, error) {
 	if db == nil {
 		return nil, fmt.Errorf("database connection is required")
 	}
@@ -124,6 +166,86 @@ func (s *SQLTaskStore) initSchema() error {
 		return fmt.Errorf("failed to create updated_at index: %w", err)
 	}
 
+	if _, err := s.db.ExecContext(ctx, createTaskStoreCreatedAtIndexSQL); err != nil {
+		return fmt.Errorf("failed to create created_at index: %w", err)
+	}
+
+	if err := s.migrateStateColumn(ctx); err != nil {
+		return err
+	}
+
+	if _, err := s.db.ExecContext(ctx, createTaskStoreStateIndexSQL); err != nil {
+		return fmt.Errorf("failed to create state index: %w", err)
+	}
+
+	if err := s.migrateAttributionColumns(ctx); err != nil {
+		return err
+	}
+
+	if _, err := s.db.ExecContext(ctx, createTaskStoreAgentIndexSQL); err != nil {
+		return fmt.Errorf("failed to create agent index: %w", err)
+	}
+
+	return nil
+}
+
+// migrateStateColumn adds the state column to an existing a2a_tasks table
+// and fills it from each task's stored status.
+func (s *SQLTaskStore) migrateStateColumn(ctx context.Context) error {
+	if rows, err := s.db.QueryContext(ctx, `SELECT state FROM a2a_tasks WHERE 1 = 0`); err == nil {
+		return rows.Close()
+	}
+	if _, err := s.db.ExecContext(ctx, addTaskStoreStateColumnSQL); err != nil {
+		return fmt.Errorf("failed to add state column: %w", err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT id, status_json FROM a2a_tasks`)
+	if err != nil {
+		return fmt.Errorf("failed to read task states: %w", err)
+	}
+	states := make(map[string]string)
+	for rows.Next() {
+		var id, statusJSON string
+		if err := rows.Scan(&id, &statusJSON); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan task state: %w", err)
+		}
+		var status a2a.TaskStatus
+		if err := json.Unmarshal([]byte(statusJSON), &status); err == nil {
+			states[id] = string(status.State)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read task states: %w", err)
+	}
+
+	query := `UPDATE a2a_tasks SET state = ? WHERE id = ?`
+	if s.dialect == "postgres" {
+		query = `UPDATE a2a_tasks SET state = $1 WHERE id = $2`
+	}
+	for id, state := range states {
+		if _, err := s.db.ExecContext(ctx, query, state, id); err != nil {
+			return fmt.Errorf("failed to backfill task state: %w", err)
+		}
+	}
+	slog.Info("Added state column to a2a_tasks", "tasks", len(states))
+	return nil
+}
+
+// migrateAttributionColumns adds the agent and owner columns to an existing
+// a2a_tasks table.
+func (s *SQLTaskStore) migrateAttributionColumns(ctx context.Context) error {
+	if rows, err := s.db.QueryContext(ctx, `SELECT agent, owner FROM a2a_tasks WHERE 1 = 0`); err == nil {
+		return rows.Close()
+	}
+	if _, err := s.db.ExecContext(ctx, addTaskStoreAgentColumnSQL); err != nil {
+		return fmt.Errorf("failed to add agent column: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, addTaskStoreOwnerColumnSQL); err != nil {
+		return fmt.Errorf("failed to add owner column: %w", err)
+	}
+	slog.Info("Added agent and owner columns to a2a_tasks")
 	return nil
 }
 
@@ -158,10 +280,13 @@ func (s *SQLTaskStore) Save(ctx context.Context, task *a2a.Task) error {
 	// Use UPSERT: INSERT ... ON CONFLICT UPDATE (PostgreSQL) or INSERT ... ON DUPLICATE KEY UPDATE (MySQL)
 	// For SQLite, use INSERT OR REPLACE
 	query := `
-INSERT INTO a2a_tasks (id, context_id, status_json, history_json, artifacts_json, metadata_json, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+INSERT INTO a2a_tasks (id, context_id, state, agent, owner, status_json, history_json, artifacts_json, metadata_json, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 ON DUPLICATE KEY UPDATE
     context_id = VALUES(context_id),
+    state = VALUES(state),
+    agent = VALUES(agent),
+    owner = VALUES(owner),
     status_json = VALUES(status_json),
     history_json = VALUES(history_json),
     artifacts_json = VALUES(artifacts_json),
@@ -170,10 +295,13 @@ ON DUPLICATE KEY UPDATE
 `
 	if s.dialect == "postgres" {
 		query = `
-INSERT INTO a2a_tasks (id, context_id, status_json, history_json, artifacts_json, metadata_json, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+INSERT INTO a2a_tasks (id, context_id, state, agent, owner, status_json, history_json, artifacts_json, metadata_json, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 ON CONFLICT (id) DO UPDATE SET
     context_id = EXCLUDED.context_id,
+    state = EXCLUDED.state,
+    agent = EXCLUDED.agent,
+    owner = EXCLUDED.owner,
     status_json = EXCLUDED.status_json,
     history_json = EXCLUDED.history_json,
     artifacts_json = EXCLUDED.artifacts_json,
@@ -184,10 +312,13 @@ ON CONFLICT (id) DO UPDATE SET
 		// SQLite 3.24+ supports ON CONFLICT (UPSERT)
 		// This preserves created_at on update unlike INSERT OR REPLACE
 		query = `
-INSERT INTO a2a_tasks (id, context_id, status_json, history_json, artifacts_json, metadata_json, created_at, updated_at)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+INSERT INTO a2a_tasks (id, context_id, state, agent, owner, status_json, history_json, artifacts_json, metadata_json, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 ON CONFLICT(id) DO UPDATE SET
     context_id = excluded.context_id,
+    state = excluded.state,
+    agent = excluded.agent,
+    owner = excluded.owner,
     status_json = excluded.status_json,
     history_json = excluded.history_json,
     artifacts_json = excluded.artifacts_json,
@@ -197,7 +328,7 @@ ON CONFLICT(id) DO UPDATE SET
 	}
 
 	args := []interface{}{
-		row.ID, row.ContextID, row.StatusJSON,
+		row.ID, row.ContextID, row.State, row.Agent, row.Owner, row.StatusJSON,
 		row.HistoryJSON, row.ArtifactsJSON, row.MetadataJSON,
 		row.CreatedAt, row.UpdatedAt,
 	}
@@ -210,8 +341,20 @@ ON CONFLICT(id) DO UPDATE SET
 	return nil
 }
 
-// Get retrieves a task by ID (implements a2asrv.TaskStore).
+// Get retrieves a task by ID from the primary (implements a2asrv.TaskStore).
+// For contexts marked with config.WithReplicaReads, the task is read from a
+// replica instead; a task not yet replicated is read from the primary.
 func (s *SQLTaskStore) Get(ctx context.Context, taskID a2a.TaskID) (*a2a.Task, error) {
+	if s.replicas != nil && config.ReplicaReadsAllowed(ctx) {
+		task, err := s.get(ctx, s.replicas.Replica(), taskID)
+		if !errors.Is(err, a2a.ErrTaskNotFound) {
+			return task, err
+		}
+	}
+	return s.get(ctx, s.db, taskID)
+}
+
+func (s *SQLTaskStore) get(ctx context.Context, db *sql.DB, taskID a2a.TaskID) (*a2a.Task, error) {
 	slog.Debug("TaskStore.Get called", "taskID", taskID)
 
 	query := `
@@ -228,7 +371,7 @@ WHERE id = $1
 	}
 
 	var row taskStoreRow
-	err := s.db.QueryRowContext(ctx, query, string(taskID)).Scan(
+	err := db.QueryRowContext(ctx, query, string(taskID)).Scan(
 		&row.ID, &row.ContextID, &row.StatusJSON,
 		&row.HistoryJSON, &row.ArtifactsJSON, &row.MetadataJSON,
 		&row.CreatedAt, &row.UpdatedAt,
@@ -309,9 +452,14 @@ func (s *SQLTaskStore) taskToRow(task *a2a.Task) (*taskStoreRow, error) {
 		metadataJSON = []byte("{}")
 	}
 
+	agent, owner, _ := Attribution(task)
+
 	return &taskStoreRow{
 		ID:            string(task.ID),
 		ContextID:     task.ContextID,
+		State:         string(task.Status.State),
+		Agent:         agent,
+		Owner:         owner,
 		StatusJSON:    string(statusJSON),
 		HistoryJSON:   string(historyJSON),
 		ArtifactsJSON: string(artifactsJSON),
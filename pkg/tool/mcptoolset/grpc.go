@@ -0,0 +1,197 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mcptoolset
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/kadirpekel/hector/pkg/httpclient"
+)
+
+// gRPC methods of an MCP server. Each one takes the params of the MCP
+// method of the same name and returns its result, both carried as a
+// google.protobuf.Struct:
+//
+//	service MCP {
+//	  rpc Initialize(google.protobuf.Struct) returns (google.protobuf.Struct);
+//	  rpc ListTools(google.protobuf.Struct) returns (google.protobuf.Struct);
+//	  rpc CallTool(google.protobuf.Struct) returns (google.protobuf.Struct);
+//	}
+const (
+	// GRPCServiceName is the full name of the MCP gRPC service.
+	GRPCServiceName = "mcp.v1.MCP"
+
+	grpcInitializeMethod = "/" + GRPCServiceName + "/Initialize"
+	grpcListToolsMethod  = "/" + GRPCServiceName + "/ListTools"
+	grpcCallToolMethod   = "/" + GRPCServiceName + "/CallTool"
+)
+
+// GRPCConfig configures the gRPC transport.
+type GRPCConfig struct {
+	// Address is the server address (e.g., "localhost:50051").
+	Address string
+
+	// TLS secures the connection. Nil connects without TLS.
+	TLS *httpclient.TLSConfig
+
+	// Metadata is sent with every call (e.g., {"authorization": "Bearer ..."}).
+	Metadata map[string]string
+
+	// DialOptions are added to the options the connection is created with.
+	DialOptions []grpc.DialOption
+}
+
+// GRPCOption configures the gRPC transport.
+type GRPCOption func(*GRPCConfig)
+
+// WithTLS secures the gRPC connection.
+func WithTLS(tls *httpclient.TLSConfig) GRPCOption {
+	return func(c *GRPCConfig) {
+		c.TLS = tls
+	}
+}
+
+// WithMetadata adds metadata sent with every gRPC call.
+func WithMetadata(md map[string]string) GRPCOption {
+	return func(c *GRPCConfig) {
+		if c.Metadata == nil {
+			c.Metadata = make(map[string]string, len(md))
+		}
+		for k, v := range md {
+			c.Metadata[k] = v
+		}
+	}
+}
+
+// WithDialOptions adds options the gRPC connection is created with.
+func WithDialOptions(opts ...grpc.DialOption) GRPCOption {
+	return func(c *GRPCConfig) {
+		c.DialOptions = append(c.DialOptions, opts...)
+	}
+}
+
+// NewGRPCConfig creates a GRPCConfig for address with the given options.
+func NewGRPCConfig(address string, opts ...GRPCOption) *GRPCConfig {
+	cfg := &GRPCConfig{Address: address}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// grpcCredentials returns the transport credentials of cfg.
+func grpcCredentials(cfg *GRPCConfig) (credentials.TransportCredentials, error) {
+	if cfg.TLS == nil {
+		return insecure.NewCredentials(), nil
+	}
+	transport, err := httpclient.ConfigureTLS(cfg.TLS)
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(transport.TLSClientConfig), nil
+}
+
+// connectGRPC opens the gRPC connection and lists the server's tools.
+func (t *Toolset) connectGRPC(ctx context.Context) error {
+	opts := append([]grpc.DialOption{grpc.WithTransportCredentials(t.grpcCreds)}, t.cfg.GRPC.DialOptions...)
+	conn, err := grpc.NewClient(t.cfg.GRPC.Address, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create gRPC client: %w", err)
+	}
+	t.grpcConn = conn
+
+	if _, err := t.invokeGRPC(ctx, conn, grpcInitializeMethod, map[string]any{
+		"protocolVersion": "2024-11-05",
+		"clientInfo": map[string]any{
+			"name":    "hector",
+			"version": "2.0.0-alpha",
+		},
+		"capabilities": map[string]any{},
+	}); err != nil {
+		t.closeGRPC()
+		return fmt.Errorf("failed to initialize MCP: %w", err)
+	}
+
+	result, err := t.invokeGRPC(ctx, conn, grpcListToolsMethod, nil)
+	if err != nil {
+		t.closeGRPC()
+		return fmt.Errorf("failed to list tools: %w", err)
+	}
+	tools, err := t.toolsFromList(result, transportGRPC)
+	if err != nil {
+		t.closeGRPC()
+		return err
+	}
+
+	t.tools = tools
+	t.connected = true
+
+	slog.Info("Connected to MCP server (gRPC)",
+		"name", t.cfg.Name,
+		"address", t.cfg.GRPC.Address,
+		"tools", len(tools),
+	)
+
+	return nil
+}
+
+// invokeGRPC calls an MCP method over the gRPC connection.
+func (t *Toolset) invokeGRPC(ctx context.Context, conn *grpc.ClientConn, method string, params map[string]any) (map[string]any, error) {
+	req, err := structpb.NewStruct(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode request: %w", err)
+	}
+	if len(t.cfg.GRPC.Metadata) > 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, metadataPairs(t.cfg.GRPC.Metadata)...)
+	}
+
+	var resp structpb.Struct
+	if err := conn.Invoke(ctx, method, req, &resp); err != nil {
+		slog.Debug("MCP gRPC request failed",
+			"source", t.cfg.Name,
+			"address", t.cfg.GRPC.Address,
+			"method", method,
+			"error", err.Error())
+		return nil, err
+	}
+	return resp.AsMap(), nil
+}
+
+// closeGRPC closes the gRPC connection, if any.
+func (t *Toolset) closeGRPC() error {
+	if t.grpcConn == nil {
+		return nil
+	}
+	err := t.grpcConn.Close()
+	t.grpcConn = nil
+	return err
+}
+
+func metadataPairs(md map[string]string) []string {
+	pairs := make([]string, 0, 2*len(md))
+	for k, v := range md {
+		pairs = append(pairs, k, v)
+	}
+	return pairs
+}
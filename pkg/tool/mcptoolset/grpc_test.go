@@ -0,0 +1,144 @@
+package mcptoolset
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/kadirpekel/hector/pkg/httpclient"
+)
+
+// startGRPCServer serves the MCP gRPC service in memory with an "echo" and
+// a "fail" tool, requiring the given authorization metadata if not empty.
+func startGRPCServer(t *testing.T, authorization string) *bufconn.Listener {
+	t.Helper()
+
+	handle := func(method func(params map[string]any) (map[string]any, error)) grpc.MethodHandler {
+		return func(_ any, ctx context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+			md, _ := metadata.FromIncomingContext(ctx)
+			if got := md.Get("authorization"); authorization != "" && (len(got) != 1 || got[0] != authorization) {
+				return nil, status.Error(codes.Unauthenticated, "bad token")
+			}
+			var req structpb.Struct
+			if err := dec(&req); err != nil {
+				return nil, err
+			}
+			result, err := method(req.AsMap())
+			if err != nil {
+				return nil, err
+			}
+			return structpb.NewStruct(result)
+		}
+	}
+
+	text := func(s string) []any { return []any{map[string]any{"type": "text", "text": s}} }
+	desc := grpc.ServiceDesc{
+		ServiceName: GRPCServiceName,
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{
+			{MethodName: "Initialize", Handler: handle(func(map[string]any) (map[string]any, error) {
+				return map[string]any{"protocolVersion": "2024-11-05"}, nil
+			})},
+			{MethodName: "ListTools", Handler: handle(func(map[string]any) (map[string]any, error) {
+				return map[string]any{"tools": []any{
+					map[string]any{"name": "echo", "description": "Echoes text", "inputSchema": map[string]any{"type": "object"}},
+					map[string]any{"name": "fail", "description": "Always fails"},
+				}}, nil
+			})},
+			{MethodName: "CallTool", Handler: handle(func(params map[string]any) (map[string]any, error) {
+				switch params["name"] {
+				case "fail":
+					return map[string]any{"isError": true, "content": text("boom")}, nil
+				case "echo":
+				default:
+					return nil, status.Errorf(codes.NotFound, "unknown tool %v", params["name"])
+				}
+				args, _ := params["arguments"].(map[string]any)
+				return map[string]any{"content": text(args["text"].(string))}, nil
+			})},
+		},
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	srv.RegisterService(&desc, nil)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+	return lis
+}
+
+func bufDialer(lis *bufconn.Listener) GRPCOption {
+	return WithDialOptions(grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	}))
+}
+
+func TestGRPCTransport(t *testing.T) {
+	lis := startGRPCServer(t, "Bearer secret")
+	ts, err := New(Config{
+		Name: "remote-tools",
+		GRPC: NewGRPCConfig("passthrough:///bufnet",
+			WithMetadata(map[string]string{"authorization": "Bearer secret"}),
+			bufDialer(lis),
+		),
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer ts.Close()
+
+	tools, err := ts.Tools(nil)
+	if err != nil {
+		t.Fatalf("Tools() error = %v", err)
+	}
+	if len(tools) != 2 || tools[0].Name() != "echo" || tools[0].(*mcpToolWrapper).Schema()["type"] != "object" {
+		t.Fatalf("tools = %+v", tools)
+	}
+
+	echo := tools[0].(*mcpToolWrapper)
+	got, err := echo.Call(nil, map[string]any{"text": "hello"})
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	if got["result"] != "hello" {
+		t.Errorf("echo result = %v", got)
+	}
+
+	got, err = tools[1].(*mcpToolWrapper).Call(nil, nil)
+	if err != nil || got["error"] != "boom" {
+		t.Errorf("fail result = %v, %v", got, err)
+	}
+}
+
+func TestGRPCTransportErrors(t *testing.T) {
+	lis := startGRPCServer(t, "Bearer secret")
+	ts, err := New(Config{
+		Name: "remote-tools",
+		GRPC: NewGRPCConfig("passthrough:///bufnet", bufDialer(lis)),
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if _, err := ts.Tools(nil); err == nil || !strings.Contains(err.Error(), "Unauthenticated") {
+		t.Errorf("Tools() error = %v, want Unauthenticated", err)
+	}
+
+	if _, err := New(Config{Name: "remote-tools", Transport: "grpc"}); err == nil {
+		t.Error("expected error for missing address")
+	}
+	_, err = New(Config{
+		Name: "remote-tools",
+		GRPC: NewGRPCConfig("localhost:50051", WithTLS(&httpclient.TLSConfig{CACertificate: "/nonexistent/ca.pem"})),
+	})
+	if err == nil {
+		t.Error("expected error for unreadable CA certificate")
+	}
+}
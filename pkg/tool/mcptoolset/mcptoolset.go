@@ -24,6 +24,7 @@
 // Transport Support:
 //   - stdio: Uses mcp-go library for subprocess communication
 //   - sse, streamable-http: Uses Hector's httpclient with retry/backoff
+//   - grpc: Uses a persistent gRPC connection (see GRPCServiceName)
 package mcptoolset
 
 import (
@@ -40,12 +41,21 @@ import (
 
 	"github.com/mark3labs/mcp-go/client"
 	"github.com/mark3labs/mcp-go/mcp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	"github.com/kadirpekel/hector/pkg/agent"
 	"github.com/kadirpekel/hector/pkg/httpclient"
 	"github.com/kadirpekel/hector/pkg/tool"
 )
 
+// Transports of an MCP toolset.
+const (
+	transportStdio = "stdio"
+	transportHTTP  = "http" // sse or streamable-http
+	transportGRPC  = "grpc"
+)
+
 const (
 	// DefaultSSEResponseTimeout is the default timeout for reading SSE responses
 	// Set to 5 minutes to accommodate long-running operations
@@ -60,7 +70,7 @@ type Config struct {
 	// URL is the MCP server URL (for HTTP transports).
 	URL string
 
-	// Transport specifies the MCP transport (sse, streamable-http, stdio).
+	// Transport specifies the MCP transport (sse, streamable-http, stdio, grpc).
 	Transport string
 
 	// Command for stdio transport.
@@ -72,9 +82,20 @@ type Config struct {
 	// Env for stdio transport.
 	Env map[string]string
 
-	// Filter limits which tools are exposed.
+	// GRPC configures the grpc transport.
+	GRPC *GRPCConfig
+
+	// Filter limits which tools are exposed, by their names on the server.
 	Filter []string
 
+	// Exclude hides tools, by their names on the server. It applies after
+	// Filter.
+	Exclude []string
+
+	// Prefix is prepended to the name of every exposed tool (e.g., "fs_"
+	// exposes read_file as fs_read_file). Calls use the server's name.
+	Prefix string
+
 	// MaxRetries for HTTP requests (default: 3).
 	MaxRetries int
 
@@ -87,19 +108,37 @@ type Toolset struct {
 	cfg Config
 
 	mu         sync.Mutex
-	client     *client.Client     // For stdio transport
-	httpClient *httpclient.Client // For HTTP transports
-	sessionID  string             // For streamable-http transport
+	client     *client.Client                   // For stdio transport
+	httpClient *httpclient.Client               // For HTTP transports
+	grpcConn   *grpc.ClientConn                 // For grpc transport
+	grpcCreds  credentials.TransportCredentials // Resolved by New
+	sessionID  string                           // For streamable-http transport
 	sessionMu  sync.RWMutex
 	tools      []tool.Tool
 	connected  bool
 	filterSet  map[string]bool
+	excludeSet map[string]bool
 }
 
 // New creates a new MCP toolset.
 func New(cfg Config) (*Toolset, error) {
-	if cfg.URL == "" && cfg.Command == "" {
-		return nil, fmt.Errorf("either url or command is required")
+	if cfg.GRPC != nil {
+		cfg.Transport = transportGRPC
+	}
+	if cfg.Transport == transportGRPC && (cfg.GRPC == nil || cfg.GRPC.Address == "") {
+		return nil, fmt.Errorf("address is required for grpc transport")
+	}
+	if cfg.URL == "" && cfg.Command == "" && cfg.GRPC == nil {
+		return nil, fmt.Errorf("either url, command or grpc address is required")
+	}
+
+	// Resolve TLS up front so a bad certificate fails the build
+	var creds credentials.TransportCredentials
+	if cfg.GRPC != nil {
+		var err error
+		if creds, err = grpcCredentials(cfg.GRPC); err != nil {
+			return nil, fmt.Errorf("invalid grpc TLS config: %w", err)
+		}
 	}
 
 	var filterSet map[string]bool
@@ -109,6 +148,10 @@ func New(cfg Config) (*Toolset, error) {
 			filterSet[name] = true
 		}
 	}
+	excludeSet := make(map[string]bool, len(cfg.Exclude))
+	for _, name := range cfg.Exclude {
+		excludeSet[name] = true
+	}
 
 	// Set defaults
 	if cfg.MaxRetries == 0 {
@@ -119,11 +162,30 @@ func New(cfg Config) (*Toolset, error) {
 	}
 
 	return &Toolset{
-		cfg:       cfg,
-		filterSet: filterSet,
+		cfg:        cfg,
+		grpcCreds:  creds,
+		filterSet:  filterSet,
+		excludeSet: excludeSet,
 	}, nil
 }
 
+// allows reports whether the server's tool name passes the filter and
+// exclusions.
+func (t *Toolset) allows(name string) bool {
+	if t.filterSet != nil && !t.filterSet[name] {
+		return false
+	}
+	return !t.excludeSet[name]
+}
+
+// Exposes reports whether the toolset exposes a tool under the given name,
+// which includes the prefix. It does not connect to the server, so the
+// tool may still turn out not to exist.
+func (t *Toolset) Exposes(name string) bool {
+	serverName, ok := strings.CutPrefix(name, t.cfg.Prefix)
+	return ok && serverName != "" && t.allows(serverName)
+}
+
 // Name returns the toolset name.
 func (t *Toolset) Name() string {
 	return t.cfg.Name
@@ -187,7 +249,10 @@ func (f *filteredToolset) Tools(ctx agent.ReadonlyContext) ([]tool.Tool, error)
 // connect establishes the MCP connection.
 func (t *Toolset) connect(ctx context.Context) error {
 	// Use different connection strategies based on transport
-	if t.cfg.Command != "" || t.cfg.Transport == "stdio" {
+	if t.cfg.Transport == transportGRPC {
+		return t.connectGRPC(ctx)
+	}
+	if t.cfg.Command != "" || t.cfg.Transport == transportStdio {
 		return t.connectStdio(ctx)
 	}
 	return t.connectHTTP(ctx)
@@ -235,16 +300,16 @@ func (t *Toolset) connectStdio(ctx context.Context) error {
 	var tools []tool.Tool
 	for _, mcpTool := range listResp.Tools {
 		// Apply filter
-		if t.filterSet != nil && !t.filterSet[mcpTool.Name] {
+		if !t.allows(mcpTool.Name) {
 			continue
 		}
 
 		tools = append(tools, &mcpToolWrapper{
-			toolset:  t,
-			name:     mcpTool.Name,
-			desc:     mcpTool.Description,
-			schema:   convertSchema(mcpTool.InputSchema),
-			useStdio: true,
+			toolset:   t,
+			name:      mcpTool.Name,
+			desc:      mcpTool.Description,
+			schema:    convertSchema(mcpTool.InputSchema),
+			transport: transportStdio,
 		})
 	}
 
@@ -297,15 +362,35 @@ func (t *Toolset) connectHTTP(ctx context.Context) error {
 		return fmt.Errorf("MCP list error: %s", listResp.Error.Message)
 	}
 
-	// Parse tools from result
-	resultMap, ok := listResp.Result.(map[string]any)
+	tools, err := t.toolsFromList(listResp.Result, transportHTTP)
+	if err != nil {
+		return err
+	}
+
+	t.tools = tools
+	t.connected = true
+
+	slog.Info("Connected to MCP server (HTTP)",
+		"name", t.cfg.Name,
+		"url", t.cfg.URL,
+		"transport", t.cfg.Transport,
+		"tools", len(tools),
+	)
+
+	return nil
+}
+
+// toolsFromList converts a tools/list result into tools called over the
+// given transport, applying the filter and exclusions.
+func (t *Toolset) toolsFromList(result any, transport string) ([]tool.Tool, error) {
+	resultMap, ok := result.(map[string]any)
 	if !ok {
-		return fmt.Errorf("unexpected result type from tools/list")
+		return nil, fmt.Errorf("unexpected result type from tools/list")
 	}
 
 	toolsList, ok := resultMap["tools"].([]any)
 	if !ok {
-		return fmt.Errorf("missing tools in tools/list response")
+		return nil, fmt.Errorf("missing tools in tools/list response")
 	}
 
 	// Convert to tool.Tool
@@ -320,7 +405,7 @@ func (t *Toolset) connectHTTP(ctx context.Context) error {
 		desc, _ := toolMap["description"].(string)
 
 		// Apply filter
-		if t.filterSet != nil && !t.filterSet[name] {
+		if !t.allows(name) {
 			continue
 		}
 
@@ -331,25 +416,14 @@ func (t *Toolset) connectHTTP(ctx context.Context) error {
 		}
 
 		tools = append(tools, &mcpToolWrapper{
-			toolset:  t,
-			name:     name,
-			desc:     desc,
-			schema:   schema,
-			useStdio: false,
+			toolset:   t,
+			name:      name,
+			desc:      desc,
+			schema:    schema,
+			transport: transport,
 		})
 	}
-
-	t.tools = tools
-	t.connected = true
-
-	slog.Info("Connected to MCP server (HTTP)",
-		"name", t.cfg.Name,
-		"url", t.cfg.URL,
-		"transport", t.cfg.Transport,
-		"tools", len(tools),
-	)
-
-	return nil
+	return tools, nil
 }
 
 // JSON-RPC types
@@ -543,6 +617,12 @@ func (t *Toolset) Close() error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
 
+	if t.grpcConn != nil {
+		err := t.closeGRPC()
+		t.connected = false
+		t.tools = nil
+		return err
+	}
 	if t.client != nil {
 		err := t.client.Close()
 		t.client = nil
@@ -559,15 +639,17 @@ func (t *Toolset) Close() error {
 
 // mcpToolWrapper wraps an MCP tool as tool.CallableTool.
 type mcpToolWrapper struct {
-	toolset  *Toolset
-	name     string
-	desc     string
-	schema   map[string]any
-	useStdio bool
+	toolset   *Toolset
+	name      string
+	desc      string
+	schema    map[string]any
+	transport string // transportStdio, transportHTTP or transportGRPC
 }
 
+// Name returns the exposed name; w.name is the server's name, which calls
+// are dispatched with.
 func (w *mcpToolWrapper) Name() string {
-	return w.name
+	return w.toolset.cfg.Prefix + w.name
 }
 
 func (w *mcpToolWrapper) Description() string {
@@ -587,10 +669,14 @@ func (w *mcpToolWrapper) Schema() map[string]any {
 }
 
 func (w *mcpToolWrapper) Call(ctx tool.Context, args map[string]any) (map[string]any, error) {
-	if w.useStdio {
+	switch w.transport {
+	case transportStdio:
 		return w.callStdio(ctx, args)
+	case transportGRPC:
+		return w.callGRPC(ctx, args)
+	default:
+		return w.callHTTP(ctx, args)
 	}
-	return w.callHTTP(ctx, args)
 }
 
 // callStdio executes tool via mcp-go client (for stdio transport).
@@ -643,12 +729,42 @@ func (w *mcpToolWrapper) callHTTP(ctx tool.Context, args map[string]any) (map[st
 		}, nil
 	}
 
-	// Parse result
+	return parseCallResult(resp.Result), nil
+}
+
+// callGRPC executes tool via the gRPC connection (for grpc transport).
+func (w *mcpToolWrapper) callGRPC(ctx tool.Context, args map[string]any) (map[string]any, error) {
+	w.toolset.mu.Lock()
+	conn := w.toolset.grpcConn
+	w.toolset.mu.Unlock()
+
+	if conn == nil {
+		return nil, fmt.Errorf("MCP client not connected")
+	}
+
+	bgCtx := context.Background()
+	if ctx != nil {
+		bgCtx = ctx
+	}
+
+	result, err := w.toolset.invokeGRPC(bgCtx, conn, grpcCallToolMethod, map[string]any{
+		"name":      w.name,
+		"arguments": args,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("MCP call failed: %w", err)
+	}
+
+	return parseCallResult(result), nil
+}
+
+// parseCallResult converts a decoded tools/call result into a map.
+func parseCallResult(raw any) map[string]any {
 	result := make(map[string]any)
-	resultMap, ok := resp.Result.(map[string]any)
+	resultMap, ok := raw.(map[string]any)
 	if !ok {
-		result["result"] = resp.Result
-		return result, nil
+		result["result"] = raw
+		return result
 	}
 
 	// Check for error
@@ -666,7 +782,7 @@ func (w *mcpToolWrapper) callHTTP(ctx tool.Context, args map[string]any) (map[st
 		if result["error"] == nil {
 			result["error"] = "unknown error"
 		}
-		return result, nil
+		return result
 	}
 
 	// Collect text content
@@ -688,7 +804,7 @@ func (w *mcpToolWrapper) callHTTP(ctx tool.Context, args map[string]any) (map[st
 		}
 	}
 
-	return result, nil
+	return result
 }
 
 // parseToolResponse parses MCP tool response into a map.
@@ -0,0 +1,63 @@
+package mcptoolset
+
+import (
+	"testing"
+)
+
+func TestToolsetPrefixAndExclude(t *testing.T) {
+	lis := startGRPCServer(t, "")
+	ts, err := New(Config{
+		Name:    "remote-tools",
+		GRPC:    NewGRPCConfig("passthrough:///bufnet", bufDialer(lis)),
+		Exclude: []string{"fail"},
+		Prefix:  "rt_",
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer ts.Close()
+
+	tools, err := ts.Tools(nil)
+	if err != nil {
+		t.Fatalf("Tools() error = %v", err)
+	}
+	if len(tools) != 1 || tools[0].Name() != "rt_echo" {
+		t.Fatalf("tools = %v, want [rt_echo]", tools)
+	}
+
+	// The prefix is stripped when calling the server
+	got, err := tools[0].(*mcpToolWrapper).Call(nil, map[string]any{"text": "hi"})
+	if err != nil || got["result"] != "hi" {
+		t.Errorf("Call() = %v, %v", got, err)
+	}
+
+	for name, want := range map[string]bool{
+		"rt_echo":    true,
+		"rt_other":   true,
+		"rt_fail":    false,
+		"echo":       false,
+		"rt_":        false,
+		"other_echo": false,
+	} {
+		if got := ts.Exposes(name); got != want {
+			t.Errorf("Exposes(%q) = %v, want %v", name, got, want)
+		}
+	}
+
+	filtered, err := ts.WithFilter([]string{"rt_echo"}).Tools(nil)
+	if err != nil || len(filtered) != 1 {
+		t.Errorf("WithFilter() tools = %v, %v", filtered, err)
+	}
+}
+
+func TestToolsetFilter(t *testing.T) {
+	ts, err := New(Config{Name: "fs", URL: "http://localhost:9000", Filter: []string{"read_file", "write_file"}, Exclude: []string{"write_file"}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	for name, want := range map[string]bool{"read_file": true, "write_file": false, "delete_file": false} {
+		if got := ts.Exposes(name); got != want {
+			t.Errorf("Exposes(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package pintool provides the pin_context tool, which lets an agent keep
+// important facts in context even after working memory trims the history.
+package pintool
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kadirpekel/hector/pkg/memory"
+	"github.com/kadirpekel/hector/pkg/tool"
+	"github.com/kadirpekel/hector/pkg/tool/functiontool"
+)
+
+// PinContextArgs defines the parameters for pinning context.
+type PinContextArgs struct {
+	Content string `json:"content" jsonschema:"required,description=Fact or instruction that must stay in context for the rest of the conversation"`
+}
+
+// New creates a pin_context tool using FunctionTool.
+// Pinned notes are stored in session state under memory.PinnedStateKey and
+// injected into every subsequent request, regardless of history trimming.
+func New() (tool.CallableTool, error) {
+	return functiontool.NewWithValidation(
+		functiontool.Config{
+			Name:        "pin_context",
+			Description: "Pin an important fact, constraint, or user preference so it stays in context for the rest of the conversation, even after older messages are trimmed.",
+		},
+		pinContext,
+		func(args PinContextArgs) error {
+			if strings.TrimSpace(args.Content) == "" {
+				return fmt.Errorf("content cannot be empty")
+			}
+			return nil
+		},
+	)
+}
+
+func pinContext(ctx tool.Context, args PinContextArgs) (map[string]any, error) {
+	content := strings.TrimSpace(args.Content)
+
+	existing := memory.PinnedNotes(ctx.ReadonlyState())
+	for _, note := range existing {
+		if note == content {
+			return map[string]any{
+				"pinned": true,
+				"count":  len(existing),
+			}, nil
+		}
+	}
+
+	notes := make([]string, 0, len(existing)+1)
+	notes = append(notes, existing...)
+	notes = append(notes, content)
+
+	if err := ctx.State().Set(memory.PinnedStateKey, notes); err != nil {
+		return nil, fmt.Errorf("failed to pin context: %w", err)
+	}
+
+	return map[string]any{
+		"pinned": true,
+		"count":  len(notes),
+	}, nil
+}
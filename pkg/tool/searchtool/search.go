@@ -50,6 +50,7 @@ type SearchTool struct {
 	availableStores []string // Store names this agent can access (empty = all)
 	maxLimit        int
 	defaultLimit    int
+	storeDefaults   map[string]StoreDefaults
 }
 
 // Config configures the search tool.
@@ -68,6 +69,20 @@ type Config struct {
 	// DefaultLimit is the default results when limit not specified.
 	// Default: 10
 	DefaultLimit int
+
+	// StoreDefaults overrides search defaults per store name.
+	StoreDefaults map[string]StoreDefaults
+}
+
+// StoreDefaults configures search defaults for a single store.
+type StoreDefaults struct {
+	// TopK is used when the agent doesn't specify a limit.
+	// Zero falls back to the tool's DefaultLimit.
+	TopK int
+
+	// MinScore drops vector matches with a similarity below this value.
+	// Zero disables filtering.
+	MinScore float32
 }
 
 // New creates a new search tool.
@@ -87,6 +102,7 @@ func New(cfg Config) *SearchTool {
 		availableStores: cfg.AvailableStores,
 		maxLimit:        cfg.MaxLimit,
 		defaultLimit:    cfg.DefaultLimit,
+		storeDefaults:   cfg.StoreDefaults,
 	}
 
 	return t
@@ -145,15 +161,15 @@ func (t *SearchTool) Call(ctx tool.Context, args map[string]any) (map[string]any
 		return nil, fmt.Errorf("query parameter is required")
 	}
 
-	// Parse limit
-	limit := t.defaultLimit
+	// Parse limit (0 = use store defaults)
+	limit := 0
 	if l, ok := args["limit"].(float64); ok {
 		limit = int(l)
 	} else if l, ok := args["limit"].(int); ok {
 		limit = l
 	}
-	if limit <= 0 {
-		limit = t.defaultLimit
+	if limit < 0 {
+		limit = 0
 	}
 	if limit > t.maxLimit {
 		limit = t.maxLimit
@@ -222,6 +238,7 @@ type SearchResult struct {
 }
 
 // performSearch executes the search across stores.
+// A zero limit applies each store's default top_k.
 func (t *SearchTool) performSearch(ctx context.Context, query string, requestedStores []string, limit int) (*SearchResponse, error) {
 	start := time.Now()
 
@@ -234,14 +251,27 @@ func (t *SearchTool) performSearch(ctx context.Context, query string, requestedS
 	// Search each store
 	var allResults []SearchResult
 	var storesUsed []string
+	resultLimit := limit
 
 	for storeName, store := range storesToSearch {
+		defaults := t.storeDefaults[storeName]
+
+		topK := limit
+		if topK == 0 {
+			topK = t.defaultLimit
+			if defaults.TopK > 0 {
+				topK = min(defaults.TopK, t.maxLimit)
+			}
+			resultLimit = max(resultLimit, topK)
+		}
+
 		// Add timeout per store
 		searchCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 
 		results, err := store.Search(searchCtx, rag.SearchRequest{
-			Query: query,
-			TopK:  limit,
+			Query:     query,
+			TopK:      topK,
+			Threshold: defaults.MinScore,
 		})
 		cancel()
 
@@ -295,8 +325,8 @@ func (t *SearchTool) performSearch(ctx context.Context, query string, requestedS
 	})
 
 	// Apply limit
-	if len(allResults) > limit {
-		allResults = allResults[:limit]
+	if len(allResults) > resultLimit {
+		allResults = allResults[:resultLimit]
 	}
 
 	return &SearchResponse{
@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package searchtool
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kadirpekel/hector/pkg/rag"
+	"github.com/kadirpekel/hector/pkg/vector"
+)
+
+// wordEmbedder embeds text as the counts of a fixed set of words.
+type wordEmbedder struct{ words []string }
+
+func (e *wordEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	vectors, err := e.EmbedBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vectors[0], nil
+}
+
+func (e *wordEmbedder) EmbedBatch(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, text := range texts {
+		vectors[i] = make([]float32, len(e.words))
+		for w, word := range e.words {
+			vectors[i][w] = float32(strings.Count(strings.ToLower(text), word))
+		}
+	}
+	return vectors, nil
+}
+
+func (e *wordEmbedder) Dimension() int { return len(e.words) }
+func (e *wordEmbedder) Model() string  { return "words" }
+func (e *wordEmbedder) Close() error   { return nil }
+
+// newTestStore returns a store holding two planet notes and a router note.
+func newTestStore(t *testing.T, name string) *rag.DocumentStore {
+	t.Helper()
+	ctx := context.Background()
+	provider, err := vector.NewChromemProvider(vector.ChromemConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	engine, err := rag.NewSearchEngine(rag.SearchEngineConfig{
+		Provider:   provider,
+		Embedder:   &wordEmbedder{words: []string{"planet", "router"}},
+		Collection: name,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = engine.IngestDocuments(ctx, []rag.Document{
+		{ID: name + "-saturn", Content: "Saturn is a planet"},
+		{ID: name + "-jupiter", Content: "Jupiter is a planet"},
+		{ID: name + "-router", Content: "The router dropped packets"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, err := rag.NewDocumentStore(rag.DocumentStoreConfig{
+		Name:         name,
+		Source:       rag.NewCollectionSource(name),
+		SearchEngine: engine,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestSearchAppliesStoreDefaults(t *testing.T) {
+	stores := map[string]*rag.DocumentStore{
+		"docs":  newTestStore(t, "docs"),
+		"notes": newTestStore(t, "notes"),
+	}
+	search := func(defaults map[string]StoreDefaults, requested []string, limit int) map[string]int {
+		t.Helper()
+		st := New(Config{Stores: stores, StoreDefaults: defaults})
+		resp, err := st.performSearch(context.Background(), "planet", requested, limit)
+		if err != nil {
+			t.Fatalf("performSearch() error = %v", err)
+		}
+		counts := make(map[string]int)
+		for _, r := range resp.Results {
+			counts[r.StoreName]++
+		}
+		return counts
+	}
+
+	// top_k applies when the agent passes no limit; other stores keep the
+	// tool's default
+	counts := search(map[string]StoreDefaults{"docs": {TopK: 1}}, nil, 0)
+	if counts["docs"] != 1 || counts["notes"] != 3 {
+		t.Errorf("results per store = %v, want docs:1 notes:3", counts)
+	}
+
+	// An explicit limit overrides top_k
+	counts = search(map[string]StoreDefaults{"docs": {TopK: 1}}, []string{"docs"}, 2)
+	if counts["docs"] != 2 {
+		t.Errorf("results per store = %v, want docs:2", counts)
+	}
+
+	// min_score drops the dissimilar router note from that store only
+	counts = search(map[string]StoreDefaults{"notes": {MinScore: 0.5}}, nil, 0)
+	if counts["docs"] != 3 || counts["notes"] != 2 {
+		t.Errorf("results per store = %v, want docs:3 notes:2", counts)
+	}
+}
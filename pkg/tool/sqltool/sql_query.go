@@ -0,0 +1,271 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sqltool provides the sql_query tool, which lets agents run
+// read-only SQL against configured databases.
+package sqltool
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kadirpekel/hector/pkg/tool"
+	"github.com/kadirpekel/hector/pkg/tool/functiontool"
+)
+
+const (
+	defaultMaxRows = 100
+	defaultTimeout = 30 * time.Second
+)
+
+// writeKeywords modify data or schema. Since a read-only query must
+// start with SELECT or WITH, they can only appear in data-modifying CTEs
+// and SELECT INTO, which are rejected too.
+var writeKeywords = []string{
+	"insert", "update", "delete", "merge", "into",
+	"create", "alter", "drop", "truncate",
+}
+
+// SQLQueryArgs defines the parameters of a sql_query call.
+type SQLQueryArgs struct {
+	Database string   `json:"database" jsonschema:"required,description=Name of the database to query"`
+	Query    string   `json:"query" jsonschema:"required,description=SQL query. Use placeholders (? or $1 depending on the database) for values"`
+	Args     []string `json:"args,omitempty" jsonschema:"description=Values for the query placeholders in order (the database converts them to the column types)"`
+}
+
+// SQLQueryConfig defines configuration for the sql_query tool.
+type SQLQueryConfig struct {
+	// Databases lists the database names the tool may query.
+	Databases []string
+
+	// Open returns the connection for a database name. It is called on
+	// each query, so connections are only opened when first used.
+	Open func(name string) (*sql.DB, error)
+
+	// MaxRows caps the rows returned per query. Default: 100.
+	MaxRows int
+
+	// Timeout bounds each query. Default: 30s.
+	Timeout time.Duration
+
+	// AllowWrites permits statements other than SELECT.
+	AllowWrites bool
+}
+
+// NewSQLQuery creates the sql_query tool. Queries run through the
+// connections returned by cfg.Open and return their columns and rows.
+func NewSQLQuery(cfg SQLQueryConfig) (tool.CallableTool, error) {
+	if len(cfg.Databases) == 0 {
+		return nil, fmt.Errorf("sql_query requires at least one database")
+	}
+	if cfg.Open == nil {
+		return nil, fmt.Errorf("sql_query requires a connection opener")
+	}
+	if cfg.MaxRows <= 0 {
+		cfg.MaxRows = defaultMaxRows
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultTimeout
+	}
+
+	description := "Run a read-only SQL SELECT query and return the columns and rows as JSON."
+	if cfg.AllowWrites {
+		description = "Run a SQL statement and return the columns and rows as JSON."
+	}
+	description += fmt.Sprintf(" Databases: %s. At most %d rows are returned.", strings.Join(cfg.Databases, ", "), cfg.MaxRows)
+
+	return functiontool.NewWithValidation(
+		functiontool.Config{
+			Name:        "sql_query",
+			Description: description,
+		},
+		func(ctx tool.Context, args SQLQueryArgs) (map[string]any, error) {
+			return sqlQueryImpl(ctx, cfg, args)
+		},
+		func(args SQLQueryArgs) error {
+			if !slices.Contains(cfg.Databases, args.Database) {
+				return fmt.Errorf("unknown database %q (available: %s)", args.Database, strings.Join(cfg.Databases, ", "))
+			}
+			if strings.TrimSpace(args.Query) == "" {
+				return fmt.Errorf("query is required")
+			}
+			if !cfg.AllowWrites {
+				return checkReadOnly(args.Query)
+			}
+			return nil
+		},
+	)
+}
+
+func sqlQueryImpl(ctx context.Context, cfg SQLQueryConfig, args SQLQueryArgs) (map[string]any, error) {
+	db, err := cfg.Open(args.Database)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database %q: %w", args.Database, err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+
+	params := make([]any, len(args.Args))
+	for i, v := range args.Args {
+		params[i] = v
+	}
+
+	// checkReadOnly only catches mistakes; without allow_writes the
+	// database enforces it, and the transaction is never committed
+	var q interface {
+		QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	} = db
+	if !cfg.AllowWrites {
+		tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to begin read-only transaction: %w", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+		q = tx
+	}
+
+	rows, err := q.QueryContext(ctx, args.Query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+
+	result := [][]any{}
+	truncated := false
+	for rows.Next() {
+		if len(result) == cfg.MaxRows {
+			truncated = true
+			break
+		}
+		values := make([]any, len(columns))
+		ptrs := make([]any, len(columns))
+		for i := range values {
+			ptrs[i] = &values[i]
+		}
+		if err := rows.Scan(ptrs...); err != nil {
+			return nil, fmt.Errorf("failed to read row: %w", err)
+		}
+		for i, v := range values {
+			// Drivers return text columns as bytes
+			if b, ok := v.([]byte); ok {
+				values[i] = string(b)
+			}
+		}
+		result = append(result, values)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+
+	out := map[string]any{
+		"columns":   columns,
+		"rows":      result,
+		"row_count": len(result),
+		"truncated": truncated,
+	}
+	if truncated {
+		out["note"] = fmt.Sprintf("Result truncated to the first %d rows. Add a LIMIT, filter or aggregate to see the rest.", cfg.MaxRows)
+	}
+	return out, nil
+}
+
+// checkReadOnly returns an error unless query is a single SELECT (or
+// WITH ... SELECT) statement. It is a fast first check, not a sandbox:
+// queries also run in a read-only transaction that is rolled back, and a
+// database user with read-only grants is still recommended.
+func checkReadOnly(query string) error {
+	words, statements := scanSQL(query)
+	if statements > 1 {
+		return fmt.Errorf("only one statement is allowed per query")
+	}
+	if len(words) == 0 || (words[0] != "select" && words[0] != "with") {
+		return fmt.Errorf("only SELECT queries are allowed")
+	}
+	for _, w := range words {
+		if slices.Contains(writeKeywords, w) {
+			return fmt.Errorf("only SELECT queries are allowed (found %s)", strings.ToUpper(w))
+		}
+	}
+	return nil
+}
+
+// scanSQL returns the lower-cased keywords and identifiers of query,
+// skipping string literals, quoted identifiers and comments, and the
+// number of non-empty statements.
+func scanSQL(query string) (words []string, statements int) {
+	inStatement := false
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := strings.IndexByte(query[i+1:], c)
+			if end < 0 {
+				i = len(query)
+			} else {
+				i += end + 2
+			}
+			inStatement = true
+		case strings.HasPrefix(query[i:], "--"):
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				end = len(query) - i
+			}
+			i += end
+		case strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				i = len(query)
+			} else {
+				i += end + 4
+			}
+		case c == ';':
+			if inStatement {
+				statements++
+			}
+			inStatement = false
+			i++
+		case isWordByte(c):
+			start := i
+			for i < len(query) && isWordByte(query[i]) {
+				i++
+			}
+			words = append(words, strings.ToLower(query[start:i]))
+			inStatement = true
+		default:
+			if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+				inStatement = true
+			}
+			i++
+		}
+	}
+	if inStatement {
+		statements++
+	}
+	return words, statements
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,205 @@
+package sqltool
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/kadirpekel/hector/pkg/tool"
+)
+
+// testContext is a tool.Context serving only cancellation.
+type testContext struct {
+	tool.Context
+	ctx context.Context
+}
+
+func (c testContext) Deadline() (time.Time, bool) { return c.ctx.Deadline() }
+func (c testContext) Done() <-chan struct{}       { return c.ctx.Done() }
+func (c testContext) Err() error                  { return c.ctx.Err() }
+func (c testContext) Value(key any) any           { return c.ctx.Value(key) }
+
+func newTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "warehouse.db"))
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Exec(`CREATE TABLE orders (id INTEGER, region TEXT, total REAL)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	for i := 1; i <= 5; i++ {
+		if _, err := db.Exec(`INSERT INTO orders VALUES (?, ?, ?)`, i, fmt.Sprintf("r%d", i%2), float64(i)*1.5); err != nil {
+			t.Fatalf("Failed to insert row: %v", err)
+		}
+	}
+	return db
+}
+
+func newTestTool(t *testing.T, db *sql.DB, allowWrites bool) tool.CallableTool {
+	t.Helper()
+	sqlTool, err := NewSQLQuery(SQLQueryConfig{
+		Databases:   []string{"warehouse"},
+		Open:        func(string) (*sql.DB, error) { return db, nil },
+		MaxRows:     3,
+		AllowWrites: allowWrites,
+	})
+	if err != nil {
+		t.Fatalf("NewSQLQuery() error = %v", err)
+	}
+	return sqlTool
+}
+
+func TestSQLQuery(t *testing.T) {
+	sqlTool := newTestTool(t, newTestDB(t), false)
+	ctx := testContext{ctx: context.Background()}
+
+	result, err := sqlTool.Call(ctx, map[string]any{
+		"database": "warehouse",
+		"query":    "SELECT id, region FROM orders WHERE total > ? ORDER BY id",
+		"args":     []any{"2"},
+	})
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	if cols := result["columns"].([]string); strings.Join(cols, ",") != "id,region" {
+		t.Errorf("columns = %v", cols)
+	}
+	rows := result["rows"].([][]any)
+	if len(rows) != 3 || rows[0][0] != int64(2) || rows[0][1] != "r0" {
+		t.Errorf("rows = %v", rows)
+	}
+	if result["truncated"] != true || !strings.Contains(result["note"].(string), "first 3 rows") {
+		t.Errorf("truncated = %v, note = %v", result["truncated"], result["note"])
+	}
+
+	result, err = sqlTool.Call(ctx, map[string]any{
+		"database": "warehouse",
+		"query":    "SELECT count(*) AS n FROM orders",
+	})
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	if result["truncated"] != false || result["note"] != nil {
+		t.Errorf("truncated = %v, note = %v", result["truncated"], result["note"])
+	}
+}
+
+func TestSQLQueryRejectsWrites(t *testing.T) {
+	db := newTestDB(t)
+	ctx := testContext{ctx: context.Background()}
+	readOnly := newTestTool(t, db, false)
+
+	for _, query := range []string{
+		"DELETE FROM orders",
+		"SELECT 1; DROP TABLE orders",
+		"WITH gone AS (DELETE FROM orders RETURNING id) SELECT * FROM gone",
+		"SELECT * INTO backup FROM orders",
+	} {
+		if _, err := readOnly.Call(ctx, map[string]any{"database": "warehouse", "query": query}); err == nil {
+			t.Errorf("Call(%q) expected error", query)
+		}
+	}
+	if _, err := readOnly.Call(ctx, map[string]any{"database": "other", "query": "SELECT 1"}); err == nil {
+		t.Error("expected error for unknown database")
+	}
+
+	writable := newTestTool(t, db, true)
+	if _, err := writable.Call(ctx, map[string]any{"database": "warehouse", "query": "DELETE FROM orders WHERE id = ?", "args": []any{"1"}}); err != nil {
+		t.Errorf("Call() with allow writes error = %v", err)
+	}
+}
+
+// txRecordingDriver wraps the SQLite driver and records the options of
+// every transaction begun, since SQLite ignores TxOptions.ReadOnly.
+type txRecordingDriver struct {
+	sqlite3.SQLiteDriver
+	mu  sync.Mutex
+	txs []driver.TxOptions
+}
+
+func (d *txRecordingDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.SQLiteDriver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &txRecordingConn{Conn: conn, driver: d}, nil
+}
+
+type txRecordingConn struct {
+	driver.Conn
+	driver *txRecordingDriver
+}
+
+func (c *txRecordingConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	c.driver.mu.Lock()
+	c.driver.txs = append(c.driver.txs, opts)
+	c.driver.mu.Unlock()
+	return c.Conn.(driver.ConnBeginTx).BeginTx(ctx, opts)
+}
+
+func TestSQLQueryRunsInReadOnlyTransaction(t *testing.T) {
+	drv := &txRecordingDriver{}
+	db := sql.OpenDB(driverConnector{drv, filepath.Join(t.TempDir(), "warehouse.db")})
+	t.Cleanup(func() { db.Close() })
+	ctx := testContext{ctx: context.Background()}
+
+	if _, err := newTestTool(t, db, false).Call(ctx, map[string]any{"database": "warehouse", "query": "SELECT 1"}); err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	if len(drv.txs) != 1 || !drv.txs[0].ReadOnly {
+		t.Errorf("transactions = %+v, want one read-only", drv.txs)
+	}
+
+	// Writable tools run statements directly
+	if _, err := newTestTool(t, db, true).Call(ctx, map[string]any{"database": "warehouse", "query": "SELECT 1"}); err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	if len(drv.txs) != 1 {
+		t.Errorf("transactions = %d, want no new transaction with allow writes", len(drv.txs))
+	}
+}
+
+// driverConnector opens a DSN with a specific driver instance.
+type driverConnector struct {
+	driver driver.Driver
+	dsn    string
+}
+
+func (c driverConnector) Connect(context.Context) (driver.Conn, error) { return c.driver.Open(c.dsn) }
+func (c driverConnector) Driver() driver.Driver                        { return c.driver }
+
+func TestCheckReadOnly(t *testing.T) {
+	for _, query := range []string{
+		"SELECT * FROM orders",
+		"  select id from orders;  ",
+		"-- monthly totals\nWITH m AS (SELECT region FROM orders) SELECT * FROM m",
+		"SELECT 'DELETE; DROP TABLE x' AS note",
+		`SELECT "update" FROM audit /* insert */`,
+		"SELECT last_update FROM orders",
+	} {
+		if err := checkReadOnly(query); err != nil {
+			t.Errorf("checkReadOnly(%q) error = %v", query, err)
+		}
+	}
+	for _, query := range []string{
+		"",
+		"-- only a comment",
+		"UPDATE orders SET total = 0",
+		"select 1; select 2",
+		"SELECT * FROM orders FOR UPDATE",
+	} {
+		if err := checkReadOnly(query); err == nil {
+			t.Errorf("checkReadOnly(%q) expected error", query)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webtool
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/kadirpekel/hector/pkg/httpclient"
+	"github.com/kadirpekel/hector/pkg/tool"
+	"github.com/kadirpekel/hector/pkg/tool/functiontool"
+)
+
+const (
+	defaultHTTPRequestTimeout = 30 * time.Second
+	defaultMaxResponseBytes   = 64 * 1024
+	maxHTTPRequestRedirects   = 10
+)
+
+// HTTPRequestArgs defines the parameters of an http_request call.
+type HTTPRequestArgs struct {
+	Method  string            `json:"method,omitempty" jsonschema:"description=HTTP method,default=GET,enum=GET|POST|PUT|DELETE|PATCH|HEAD|OPTIONS"`
+	URL     string            `json:"url" jsonschema:"required,description=The http or https URL to request"`
+	Headers map[string]string `json:"headers,omitempty" jsonschema:"description=Request headers as key-value pairs"`
+	Body    string            `json:"body,omitempty" jsonschema:"description=Request body"`
+}
+
+// HTTPRequestConfig defines configuration for the http_request tool.
+type HTTPRequestConfig struct {
+	// AllowedHosts limits which hosts can be requested. Entries match
+	// exactly or, with a "*." prefix, any subdomain. Redirects are checked
+	// too. Empty allows any host with a public address; loopback, private
+	// and link-local addresses need an allowlist.
+	AllowedHosts []string
+
+	// Timeout bounds the whole request. Default: 30s.
+	Timeout time.Duration
+
+	// MaxResponseBytes caps the returned body; longer bodies are
+	// truncated. Default: 64KB.
+	MaxResponseBytes int64
+}
+
+// NewHTTPRequest creates the http_request tool, which performs a single
+// HTTP request and returns its status, headers and body.
+func NewHTTPRequest(cfg HTTPRequestConfig) (tool.CallableTool, error) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = defaultHTTPRequestTimeout
+	}
+	if cfg.MaxResponseBytes <= 0 {
+		cfg.MaxResponseBytes = defaultMaxResponseBytes
+	}
+
+	// Without an allowlist, addresses are checked when dialing so that
+	// hostnames and redirects resolving to internal addresses are caught
+	// too. Proxies are bypassed, as they would dial on the tool's behalf.
+	var transport http.RoundTripper
+	if len(cfg.AllowedHosts) == 0 {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.Proxy = nil
+		t.DialContext = (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Control:   checkDialAddress,
+		}).DialContext
+		transport = t
+	}
+
+	// Requests are not retried: the tool may be asked to POST, and the
+	// model sees failures and can decide to try again.
+	hc := httpclient.New(
+		httpclient.WithHTTPClient(&http.Client{
+			Transport: transport,
+			Timeout:   cfg.Timeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if len(via) >= maxHTTPRequestRedirects {
+					return fmt.Errorf("stopped after %d redirects", maxHTTPRequestRedirects)
+				}
+				return checkHost(cfg.AllowedHosts, req.URL)
+			},
+		}),
+		httpclient.WithMaxRetries(0),
+	)
+
+	return functiontool.NewWithValidation(
+		functiontool.Config{
+			Name:        "http_request",
+			Description: "Send an HTTP request to a REST API. Returns the status code, response headers and body (truncated if large).",
+		},
+		func(ctx tool.Context, args HTTPRequestArgs) (map[string]any, error) {
+			return httpRequestImpl(ctx, cfg, hc, args)
+		},
+		func(args HTTPRequestArgs) error {
+			u, err := url.Parse(args.URL)
+			if err != nil {
+				return fmt.Errorf("invalid URL: %w", err)
+			}
+			if u.Scheme != "http" && u.Scheme != "https" {
+				return fmt.Errorf("unsupported URL scheme %q (use http or https)", u.Scheme)
+			}
+			return checkHost(cfg.AllowedHosts, u)
+		},
+	)
+}
+
+func httpRequestImpl(ctx tool.Context, cfg HTTPRequestConfig, hc *httpclient.Client, args HTTPRequestArgs) (map[string]any, error) {
+	method := "GET"
+	if args.Method != "" {
+		method = strings.ToUpper(args.Method)
+	}
+
+	var body io.Reader
+	if args.Body != "" {
+		body = strings.NewReader(args.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, args.URL, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	for k, v := range args.Headers {
+		req.Header.Set(k, v)
+	}
+
+	// httpclient reports non-2xx statuses as errors alongside the
+	// response; the status is returned to the model instead.
+	resp, err := hc.Do(req)
+	if resp == nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, cfg.MaxResponseBytes+1))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	truncated := int64(len(content)) > cfg.MaxResponseBytes
+	if truncated {
+		content = content[:cfg.MaxResponseBytes]
+	}
+
+	headers := make(map[string]string, len(resp.Header))
+	for k, v := range resp.Header {
+		headers[k] = strings.Join(v, ", ")
+	}
+
+	return map[string]any{
+		"status":    resp.StatusCode,
+		"headers":   headers,
+		"body":      string(content),
+		"truncated": truncated,
+	}, nil
+}
+
+// checkHost returns an error unless u's host is in allowed. An empty
+// allowlist permits any host except internal IP literals; hostnames are
+// checked once resolved, by checkDialAddress.
+func checkHost(allowed []string, u *url.URL) error {
+	if len(allowed) == 0 {
+		if addr, err := netip.ParseAddr(u.Hostname()); err == nil && isInternalAddr(addr) {
+			return fmt.Errorf("host not allowed: %s is an internal address (list it in allowed_hosts)", u.Hostname())
+		}
+		return nil
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, pattern := range allowed {
+		if matchesDomain(host, strings.ToLower(pattern)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("host not allowed: %s", u.Hostname())
+}
+
+// checkDialAddress rejects connections to internal addresses.
+func checkDialAddress(network, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", address, err)
+	}
+	if isInternalAddr(addrPort.Addr()) {
+		return fmt.Errorf("address not allowed: %s is an internal address (list the host in allowed_hosts)", addrPort.Addr())
+	}
+	return nil
+}
+
+// isInternalAddr reports whether addr is loopback, private, link-local
+// or otherwise not publicly routable.
+func isInternalAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() ||
+		addr.IsUnspecified() || cgnatPrefix.Contains(addr)
+}
+
+// cgnatPrefix is the shared address space of carrier-grade NAT (RFC 6598),
+// which cloud providers also use for internal endpoints.
+var cgnatPrefix = netip.MustParsePrefix("100.64.0.0/10")
@@ -0,0 +1,163 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webtool_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kadirpekel/hector/pkg/agent"
+	"github.com/kadirpekel/hector/pkg/tool/webtool"
+)
+
+type mockContext struct{ context.Context }
+
+func (m *mockContext) FunctionCallID() string       { return "test-call" }
+func (m *mockContext) Actions() *agent.EventActions { return nil }
+func (m *mockContext) SearchMemory(ctx context.Context, query string) (*agent.MemorySearchResponse, error) {
+	return nil, nil
+}
+func (m *mockContext) Artifacts() agent.Artifacts         { return nil }
+func (m *mockContext) State() agent.State                 { return nil }
+func (m *mockContext) InvocationID() string               { return "test-inv" }
+func (m *mockContext) AgentName() string                  { return "test-agent" }
+func (m *mockContext) UserContent() *agent.Content        { return nil }
+func (m *mockContext) ReadonlyState() agent.ReadonlyState { return nil }
+func (m *mockContext) UserID() string                     { return "test-user" }
+func (m *mockContext) AppName() string                    { return "test-app" }
+func (m *mockContext) SessionID() string                  { return "test-session" }
+func (m *mockContext) Branch() string                     { return "" }
+
+func newContext() *mockContext { return &mockContext{Context: context.Background()} }
+
+func TestHTTPRequest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Method", r.Method)
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Write([]byte(r.Header.Get("X-Token") + ":" + string(body) + ":" + strings.Repeat("x", 100)))
+	}))
+	defer server.Close()
+
+	httpTool, err := webtool.NewHTTPRequest(webtool.HTTPRequestConfig{
+		AllowedHosts:     []string{"127.0.0.1"},
+		MaxResponseBytes: 20,
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPRequest() error = %v", err)
+	}
+
+	result, err := httpTool.Call(newContext(), map[string]any{
+		"method":  "post",
+		"url":     server.URL + "/items",
+		"headers": map[string]any{"X-Token": "secret"},
+		"body":    "hello",
+	})
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	if result["status"] != http.StatusOK {
+		t.Errorf("status = %v, want 200", result["status"])
+	}
+	if got := result["body"]; got != "secret:hello:xxxxxxx" {
+		t.Errorf("body = %q", got)
+	}
+	if result["truncated"] != true {
+		t.Errorf("truncated = %v, want true", result["truncated"])
+	}
+	if headers := result["headers"].(map[string]string); headers["X-Method"] != "POST" {
+		t.Errorf("headers = %v", headers)
+	}
+
+	// Error statuses are results, not failures
+	result, err = httpTool.Call(newContext(), map[string]any{"url": server.URL + "/missing"})
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	if result["status"] != http.StatusNotFound {
+		t.Errorf("status = %v, want 404", result["status"])
+	}
+}
+
+func TestHTTPRequestAllowedHosts(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("internal"))
+	}))
+	defer target.Close()
+	// localhost resolves to the same server under a name that is not allowed
+	internalURL := strings.Replace(target.URL, "127.0.0.1", "localhost", 1)
+	redirector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, internalURL, http.StatusFound)
+	}))
+	defer redirector.Close()
+
+	httpTool, err := webtool.NewHTTPRequest(webtool.HTTPRequestConfig{
+		AllowedHosts: []string{"127.0.0.1", "*.example.com"},
+		Timeout:      5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewHTTPRequest() error = %v", err)
+	}
+
+	for _, url := range []string{
+		internalURL,
+		"http://example.com/",
+		"http://169.254.169.254/latest/meta-data",
+		"file:///etc/passwd",
+	} {
+		if _, err := httpTool.Call(newContext(), map[string]any{"url": url}); err == nil {
+			t.Errorf("Call(%q) expected error", url)
+		}
+	}
+
+	_, err = httpTool.Call(newContext(), map[string]any{"url": redirector.URL})
+	if err == nil || !strings.Contains(err.Error(), "host not allowed: localhost") {
+		t.Errorf("redirect error = %v", err)
+	}
+}
+
+func TestHTTPRequestBlocksInternalAddressesWithoutAllowlist(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("internal"))
+	}))
+	defer target.Close()
+
+	httpTool, err := webtool.NewHTTPRequest(webtool.HTTPRequestConfig{Timeout: 5 * time.Second})
+	if err != nil {
+		t.Fatalf("NewHTTPRequest() error = %v", err)
+	}
+
+	for _, url := range []string{
+		target.URL,
+		// A hostname is checked once resolved
+		strings.Replace(target.URL, "127.0.0.1", "localhost", 1),
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.0.0.1/",
+		"http://[::1]/",
+	} {
+		_, err := httpTool.Call(newContext(), map[string]any{"url": url})
+		if err == nil || !strings.Contains(err.Error(), "internal address") {
+			t.Errorf("Call(%q) error = %v, want internal address error", url, err)
+		}
+	}
+}
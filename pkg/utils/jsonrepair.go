@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package utils
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// RepairJSON completes JSON that was cut off mid-stream (e.g., tool call
+// arguments from an interrupted response) by closing its open objects and
+// arrays. Only cuts between values are repaired: input that ends inside a
+// string, after a key, or in a number or literal that may be partial is
+// rejected, since completing it would invent or change a value.
+//
+//	RepairJSON(`{"a": 1, "b": [true,`) // {"a": 1, "b": [true]}, true
+//	RepairJSON(`{"path": "/tmp/fo`)    // "", false
+func RepairJSON(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", false
+	}
+	if json.Valid([]byte(s)) {
+		return s, true
+	}
+	candidate, ok := completeJSON(s)
+	if !ok || !json.Valid([]byte(candidate)) {
+		return "", false
+	}
+	return candidate, true
+}
+
+// completeJSON closes the open containers of a JSON prefix. It returns
+// false if the prefix does not end after a complete value.
+func completeJSON(s string) (string, bool) {
+	var stack []byte
+	inString, escaped := false, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			if c == '\\' {
+				escaped = true
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '{':
+			stack = append(stack, '}')
+		case c == '[':
+			stack = append(stack, ']')
+		case c == '}' || c == ']':
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+		}
+	}
+	if inString {
+		return "", false
+	}
+
+	s = strings.TrimRight(s, " \t\r\n")
+	switch {
+	case strings.HasSuffix(s, ","):
+		// A comma follows a complete value
+		s = strings.TrimRight(strings.TrimSuffix(s, ","), " \t\r\n")
+	case strings.HasSuffix(s, "\""), strings.HasSuffix(s, "}"), strings.HasSuffix(s, "]"),
+		strings.HasSuffix(s, "{"), strings.HasSuffix(s, "["),
+		strings.HasSuffix(s, "true"), strings.HasSuffix(s, "false"), strings.HasSuffix(s, "null"):
+		// Ends after a complete value or at an empty container
+	default:
+		// After a key, or in a number or literal that may be partial
+		return "", false
+	}
+
+	var b strings.Builder
+	b.WriteString(s)
+	for i := len(stack) - 1; i >= 0; i-- {
+		b.WriteByte(stack[i])
+	}
+	return b.String(), true
+}
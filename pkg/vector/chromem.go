@@ -43,7 +43,7 @@ import (
 // Limitations:
 //   - Single-process only (no distributed search)
 //   - Memory-bound (all vectors in RAM)
-//   - No hybrid search support
+//   - No native keyword search (hybrid search uses the RAG keyword index)
 //
 // For production at scale, consider Qdrant or other external providers.
 type ChromemProvider struct {
@@ -229,7 +229,7 @@ func (p *ChromemProvider) SearchWithFilter(ctx context.Context, collection strin
 
 	// Cap topK to collection count (chromem requires nResults <= document count)
 	docCount := col.Count()
-	slog.Info("ChromemProvider search",
+	slog.Debug("ChromemProvider search",
 		"collection", collection,
 		"doc_count", docCount,
 		"requested_topK", topK)
@@ -47,6 +47,10 @@ const (
 	// ProviderWeaviate uses Weaviate vector database.
 	// Supports GraphQL queries and hybrid search.
 	ProviderWeaviate ProviderType = "weaviate"
+
+	// ProviderPgvector uses PostgreSQL with the pgvector extension.
+	// Reuses an existing Postgres database instead of a separate service.
+	ProviderPgvector ProviderType = "pgvector"
 )
 
 // ProviderConfig is the configuration for creating vector providers.
@@ -71,6 +75,9 @@ type ProviderConfig struct {
 
 	// Chroma configuration (used when Type == "chroma").
 	Chroma *ChromaConfig `yaml:"chroma,omitempty"`
+
+	// Pgvector configuration (used when Type == "pgvector").
+	Pgvector *PgvectorConfig `yaml:"pgvector,omitempty"`
 }
 
 // SetDefaults applies default values.
@@ -129,6 +136,11 @@ func (c *ProviderConfig) Validate() error {
 			return fmt.Errorf("chroma host is required")
 		}
 		return nil
+	case ProviderPgvector:
+		if c.Pgvector == nil || c.Pgvector.DB == nil {
+			return fmt.Errorf("pgvector database connection is required")
+		}
+		return nil
 	case "":
 		return fmt.Errorf("provider type is required")
 	default:
@@ -180,6 +192,12 @@ func NewProvider(cfg *ProviderConfig) (Provider, error) {
 		}
 		return NewChromaProvider(*cfg.Chroma)
 
+	case ProviderPgvector:
+		if cfg.Pgvector == nil {
+			return nil, fmt.Errorf("pgvector configuration is required")
+		}
+		return NewPgvectorProvider(*cfg.Pgvector)
+
 	default:
 		return nil, fmt.Errorf("unknown provider type: %q", cfg.Type)
 	}
@@ -0,0 +1,351 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vector
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// PgvectorConfig configures the pgvector provider.
+type PgvectorConfig struct {
+	// DB is a connection to a PostgreSQL database with the pgvector
+	// extension available, usually shared through config.DBPool.
+	DB *sql.DB `yaml:"-"`
+
+	// TablePrefix prefixes the table of each collection.
+	// Default: "hector_vectors_"
+	TablePrefix string `yaml:"table_prefix,omitempty"`
+
+	// Index is the approximate nearest neighbor index: "hnsw" (default)
+	// or "ivfflat".
+	Index string `yaml:"index,omitempty"`
+}
+
+// pgvectorMaxIndexDimension is the most dimensions the hnsw and ivfflat
+// indexes of pgvector support.
+const pgvectorMaxIndexDimension = 2000
+
+// PgvectorProvider implements Provider using PostgreSQL with pgvector.
+//
+// Each collection is a table holding an ID, an embedding column and the
+// metadata as JSONB. The table is created on first use, sized by the
+// first vector indexed, with a cosine distance index on the embeddings
+// and a GIN index on the metadata for filtering. Embeddings of more than
+// 2000 dimensions can't be indexed, so their searches scan the table.
+//
+// Filters match metadata by JSONB containment, so values compare by JSON
+// type: "1" doesn't match 1.
+type PgvectorProvider struct {
+	db     *sql.DB
+	prefix string
+	index  string
+
+	// tables caches the tables known to exist
+	mu     sync.Mutex
+	tables map[string]bool
+}
+
+// NewPgvectorProvider creates a new pgvector provider.
+func NewPgvectorProvider(cfg PgvectorConfig) (*PgvectorProvider, error) {
+	if cfg.DB == nil {
+		return nil, fmt.Errorf("database connection is required for pgvector")
+	}
+	if cfg.TablePrefix == "" {
+		cfg.TablePrefix = "hector_vectors_"
+	}
+	switch cfg.Index {
+	case "":
+		cfg.Index = "hnsw"
+	case "hnsw", "ivfflat":
+	default:
+		return nil, fmt.Errorf("invalid pgvector index %q (valid: hnsw, ivfflat)", cfg.Index)
+	}
+
+	return &PgvectorProvider{
+		db:     cfg.DB,
+		prefix: cfg.TablePrefix,
+		index:  cfg.Index,
+		tables: make(map[string]bool),
+	}, nil
+}
+
+// Name returns the provider name.
+func (p *PgvectorProvider) Name() string {
+	return "pgvector"
+}
+
+// Upsert adds or updates a document with its vector.
+func (p *PgvectorProvider) Upsert(ctx context.Context, collection string, id string, vector []float32, metadata map[string]any) error {
+	if len(vector) == 0 {
+		return fmt.Errorf("vector is required")
+	}
+
+	table := p.tableName(collection)
+	if err := p.ensureTable(ctx, table, len(vector)); err != nil {
+		return err
+	}
+
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	query := fmt.Sprintf(`INSERT INTO %s (id, embedding, metadata) VALUES ($1, $2::vector, $3::jsonb)
+		ON CONFLICT (id) DO UPDATE SET embedding = EXCLUDED.embedding, metadata = EXCLUDED.metadata`, quoteIdent(table))
+	if _, err := p.db.ExecContext(ctx, query, id, vectorLiteral(vector), string(metadataJSON)); err != nil {
+		return fmt.Errorf("failed to upsert vector: %w", err)
+	}
+
+	return nil
+}
+
+// Search finds the most similar vectors.
+func (p *PgvectorProvider) Search(ctx context.Context, collection string, vector []float32, topK int) ([]Result, error) {
+	return p.SearchWithFilter(ctx, collection, vector, topK, nil)
+}
+
+// SearchWithFilter combines vector similarity with metadata filtering.
+func (p *PgvectorProvider) SearchWithFilter(ctx context.Context, collection string, vector []float32, topK int, filter map[string]any) ([]Result, error) {
+	table := p.tableName(collection)
+	exists, err := p.tableExists(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return []Result{}, nil
+	}
+
+	args := []any{vectorLiteral(vector), topK}
+	where := ""
+	if len(filter) > 0 {
+		filterJSON, err := json.Marshal(filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal filter: %w", err)
+		}
+		where = "WHERE metadata @> $3::jsonb"
+		args = append(args, string(filterJSON))
+	}
+
+	query := fmt.Sprintf(`SELECT id, metadata, 1 - (embedding <=> $1::vector) AS score
+		FROM %s %s ORDER BY embedding <=> $1::vector LIMIT $2`, quoteIdent(table), where)
+	rows, err := p.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]Result, 0, topK)
+	for rows.Next() {
+		var r Result
+		var metadataJSON []byte
+		var score float64
+		if err := rows.Scan(&r.ID, &metadataJSON, &score); err != nil {
+			return nil, fmt.Errorf("failed to scan result: %w", err)
+		}
+		if err := json.Unmarshal(metadataJSON, &r.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to decode metadata of %s: %w", r.ID, err)
+		}
+		r.Score = float32(score)
+		r.Content, _ = r.Metadata["content"].(string)
+		results = append(results, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
+	}
+
+	return results, nil
+}
+
+// Delete removes a document by ID.
+func (p *PgvectorProvider) Delete(ctx context.Context, collection string, id string) error {
+	table := p.tableName(collection)
+	exists, err := p.tableExists(ctx, table)
+	if err != nil || !exists {
+		return err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE id = $1", quoteIdent(table))
+	if _, err := p.db.ExecContext(ctx, query, id); err != nil {
+		return fmt.Errorf("failed to delete vector: %w", err)
+	}
+	return nil
+}
+
+// DeleteByFilter removes all documents matching the filter.
+func (p *PgvectorProvider) DeleteByFilter(ctx context.Context, collection string, filter map[string]any) error {
+	if len(filter) == 0 {
+		return fmt.Errorf("filter is required for delete by filter")
+	}
+
+	table := p.tableName(collection)
+	exists, err := p.tableExists(ctx, table)
+	if err != nil || !exists {
+		return err
+	}
+
+	filterJSON, err := json.Marshal(filter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal filter: %w", err)
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s WHERE metadata @> $1::jsonb", quoteIdent(table))
+	if _, err := p.db.ExecContext(ctx, query, string(filterJSON)); err != nil {
+		return fmt.Errorf("failed to delete by filter: %w", err)
+	}
+	return nil
+}
+
+// CreateCollection creates the table of a collection.
+func (p *PgvectorProvider) CreateCollection(ctx context.Context, collection string, vectorDimension int) error {
+	if vectorDimension <= 0 {
+		return fmt.Errorf("vector dimension must be positive")
+	}
+	return p.ensureTable(ctx, p.tableName(collection), vectorDimension)
+}
+
+// DeleteCollection drops the table of a collection.
+func (p *PgvectorProvider) DeleteCollection(ctx context.Context, collection string) error {
+	table := p.tableName(collection)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, err := p.db.ExecContext(ctx, "DROP TABLE IF EXISTS "+quoteIdent(table)); err != nil {
+		return fmt.Errorf("failed to drop table %s: %w", table, err)
+	}
+	delete(p.tables, table)
+	return nil
+}
+
+// Close is a no-op; the connection belongs to the database pool.
+func (p *PgvectorProvider) Close() error {
+	return nil
+}
+
+// ensureTable creates the table and its indexes if they don't exist.
+func (p *PgvectorProvider) ensureTable(ctx context.Context, table string, dimension int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.tables[table] {
+		return nil
+	}
+
+	// Creating the extension needs privileges; it may exist already
+	if _, err := p.db.ExecContext(ctx, "CREATE EXTENSION IF NOT EXISTS vector"); err != nil {
+		slog.Debug("Could not create pgvector extension", "error", err)
+	}
+
+	if dimension > pgvectorMaxIndexDimension {
+		slog.Warn("pgvector cannot index embeddings of this size; searches will scan the whole table",
+			"table", table, "dimension", dimension, "max_indexed_dimension", pgvectorMaxIndexDimension)
+	}
+	for _, stmt := range p.tableStatements(table, dimension) {
+		if _, err := p.db.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to create table %s: %w", table, err)
+		}
+	}
+
+	p.tables[table] = true
+	return nil
+}
+
+// tableStatements returns the statements creating the table and its
+// indexes. The embedding index is left out above pgvectorMaxIndexDimension,
+// where creating it fails.
+func (p *PgvectorProvider) tableStatements(table string, dimension int) []string {
+	statements := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			id TEXT PRIMARY KEY,
+			embedding vector(%d) NOT NULL,
+			metadata JSONB NOT NULL DEFAULT '{}'
+		)`, quoteIdent(table), dimension),
+	}
+	if dimension <= pgvectorMaxIndexDimension {
+		indexMethod := "hnsw (embedding vector_cosine_ops)"
+		if p.index == "ivfflat" {
+			indexMethod = "ivfflat (embedding vector_cosine_ops) WITH (lists = 100)"
+		}
+		statements = append(statements, fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING %s",
+			quoteIdent(table+"_embedding_idx"), quoteIdent(table), indexMethod))
+	}
+	return append(statements, fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING gin (metadata jsonb_path_ops)",
+		quoteIdent(table+"_metadata_idx"), quoteIdent(table)))
+}
+
+// tableExists reports whether the table exists.
+func (p *PgvectorProvider) tableExists(ctx context.Context, table string) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.tables[table] {
+		return true, nil
+	}
+
+	var exists bool
+	if err := p.db.QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL", quoteIdent(table)).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to look up table %s: %w", table, err)
+	}
+	if exists {
+		p.tables[table] = true
+	}
+	return exists, nil
+}
+
+// tableName returns the table of a collection: the prefix and the
+// collection name, lower-cased with other characters than letters,
+// digits and underscores replaced by underscores.
+func (p *PgvectorProvider) tableName(collection string) string {
+	b := []byte(strings.ToLower(p.prefix + collection))
+	for i, c := range b {
+		if !(c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z')) {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+// quoteIdent quotes a SQL identifier.
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// vectorLiteral formats a vector in pgvector's text format.
+func vectorLiteral(vector []float32) string {
+	var b strings.Builder
+	b.WriteByte('[')
+	for i, v := range vector {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatFloat(float64(v), 'g', -1, 32))
+	}
+	b.WriteByte(']')
+	return b.String()
+}
+
+// Ensure PgvectorProvider implements Provider.
+var _ Provider = (*PgvectorProvider)(nil)
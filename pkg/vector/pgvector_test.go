@@ -0,0 +1,31 @@
+package vector
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+)
+
+func TestPgvectorSkipsEmbeddingIndexAboveMaxDimension(t *testing.T) {
+	p, err := NewPgvectorProvider(PgvectorConfig{DB: &sql.DB{}, Index: "ivfflat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		dimension int
+		indexed   bool
+	}{
+		{1536, true},
+		{2000, true},
+		{3072, false},
+	} {
+		stmts := strings.Join(p.tableStatements("docs", tt.dimension), "\n")
+		if got := strings.Contains(stmts, "USING ivfflat"); got != tt.indexed {
+			t.Errorf("dimension %d: embedding index = %v, want %v", tt.dimension, got, tt.indexed)
+		}
+		if !strings.Contains(stmts, "USING gin") {
+			t.Errorf("dimension %d: missing metadata index", tt.dimension)
+		}
+	}
+}
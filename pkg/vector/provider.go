@@ -132,6 +132,29 @@ type Provider interface {
 	io.Closer
 }
 
+// KeywordSearcher is implemented by providers with native keyword
+// (full-text) search.
+//
+// The RAG search engine uses it for keyword and hybrid search. Providers
+// without it get keyword matching over stored chunk text instead.
+type KeywordSearcher interface {
+	// KeywordSearch finds documents matching the query terms.
+	//
+	// Returns results ordered by keyword relevance (highest first).
+	// The filter has the same semantics as SearchWithFilter and may be nil.
+	KeywordSearch(ctx context.Context, collection string, query string, topK int, filter map[string]any) ([]Result, error)
+}
+
+// ThresholdSearcher is implemented by providers that can drop results
+// below a score threshold while searching, rather than after.
+//
+// The RAG search engine uses it for vector searches with a threshold.
+type ThresholdSearcher interface {
+	// SearchWithThreshold searches like SearchWithFilter, returning only
+	// results scoring at least threshold. The filter may be nil.
+	SearchWithThreshold(ctx context.Context, collection string, vector []float32, topK int, filter map[string]any, threshold float32) ([]Result, error)
+}
+
 // Result represents a single search result.
 //
 // Results are returned ordered by Score (highest first).
@@ -158,7 +181,7 @@ type Result struct {
 // Config is the base configuration for all vector providers.
 type Config struct {
 	// Type identifies the provider implementation.
-	// Values: "chromem", "qdrant", "chroma", "pinecone", "milvus", "weaviate"
+	// Values: "chromem", "qdrant", "chroma", "pinecone", "milvus", "weaviate", "pgvector"
 	Type string `yaml:"type"`
 
 	// Collection is the default collection name.
@@ -16,13 +16,38 @@
 package vector
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
 
+	"github.com/google/uuid"
 	"github.com/qdrant/go-client/qdrant"
 )
 
+// Qdrant protocols.
+const (
+	// QdrantProtocolGRPC talks to Qdrant's gRPC API (default port 6334).
+	QdrantProtocolGRPC = "grpc"
+
+	// QdrantProtocolREST talks to Qdrant's HTTP API (default port 6333).
+	QdrantProtocolREST = "rest"
+)
+
+// QdrantDistances are the accepted distance metrics.
+var QdrantDistances = []string{"cosine", "dot", "euclid", "manhattan"}
+
+// qdrantIDField stores the document ID given by the caller. Qdrant point
+// IDs must be UUIDs or integers, so points are keyed by a UUID derived
+// from it.
+const qdrantIDField = "hector_id"
+
+// qdrantIDNamespace namespaces the UUIDs derived from document IDs.
+var qdrantIDNamespace = uuid.MustParse("5d1bb7c4-1f2b-4a4e-9a53-8f0c2e6e7a31")
+
 // QdrantConfig configures the Qdrant vector provider.
 //
 // Direct port from legacy pkg/databases/qdrant.go
@@ -30,7 +55,7 @@ type QdrantConfig struct {
 	// Host is the Qdrant server hostname.
 	Host string `yaml:"host"`
 
-	// Port is the Qdrant gRPC port (default: 6334).
+	// Port is the Qdrant port (default: 6334 for gRPC, 6333 for REST).
 	Port int `yaml:"port"`
 
 	// APIKey for authenticated access (optional).
@@ -38,14 +63,41 @@ type QdrantConfig struct {
 
 	// UseTLS enables TLS connections.
 	UseTLS bool `yaml:"use_tls,omitempty"`
+
+	// Protocol selects the API: "grpc" (default) or "rest".
+	Protocol string `yaml:"protocol,omitempty"`
+
+	// Distance is the metric for new collections: "cosine" (default),
+	// "dot", "euclid" or "manhattan". Existing collections keep theirs.
+	Distance string `yaml:"distance,omitempty"`
+}
+
+// qdrantTransport is one of Qdrant's APIs. Points are addressed by their
+// Qdrant point ID; payloads are plain JSON values.
+type qdrantTransport interface {
+	collectionExists(ctx context.Context, collection string) (bool, error)
+	createCollection(ctx context.Context, collection string, size int, distance string) error
+	deleteCollection(ctx context.Context, collection string) error
+	upsert(ctx context.Context, collection, pointID string, vector []float32, payload map[string]any) error
+	search(ctx context.Context, collection string, vector []float32, limit int, filter map[string]any, threshold float32) ([]Result, error)
+	deletePoint(ctx context.Context, collection, pointID string) error
+	deleteByFilter(ctx context.Context, collection string, filter map[string]any) error
+	close() error
 }
 
 // QdrantProvider implements Provider using Qdrant vector database.
 //
+// Collections are created on first use with the vector size of the first
+// upserted vector. Metadata is stored as the point payload and can be
+// filtered on by exact match of strings, integers and booleans.
+//
 // Direct port from legacy pkg/databases/qdrant.go
 type QdrantProvider struct {
-	client *qdrant.Client
-	config QdrantConfig
+	transport qdrantTransport
+	config    QdrantConfig
+
+	// collections caches collections known to exist
+	collections sync.Map
 }
 
 // NewQdrantProvider creates a new Qdrant provider.
@@ -53,28 +105,49 @@ func NewQdrantProvider(cfg QdrantConfig) (*QdrantProvider, error) {
 	if cfg.Host == "" {
 		cfg.Host = "localhost"
 	}
-	if cfg.Port == 0 {
-		cfg.Port = 6334 // Qdrant gRPC port
+	if cfg.Protocol == "" {
+		cfg.Protocol = QdrantProtocolGRPC
+	}
+	if cfg.Distance == "" {
+		cfg.Distance = "cosine"
+	}
+	if !slices.Contains(QdrantDistances, cfg.Distance) {
+		return nil, fmt.Errorf("invalid qdrant distance %q (valid: %s)", cfg.Distance, strings.Join(QdrantDistances, ", "))
 	}
 
-	client, err := qdrant.NewClient(&qdrant.Config{
-		Host:   cfg.Host,
-		Port:   cfg.Port,
-		APIKey: cfg.APIKey,
-		UseTLS: cfg.UseTLS,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to create Qdrant client for %s:%d: %w\n"+
-			"  TIP: Troubleshooting:\n"+
-			"     - Ensure Qdrant is running\n"+
-			"     - Verify host and port configuration\n"+
-			"     - For Docker: start Qdrant container (docker run -p 6333:6333 -p 6334:6334 qdrant/qdrant)",
-			cfg.Host, cfg.Port, err)
+	var transport qdrantTransport
+	switch cfg.Protocol {
+	case QdrantProtocolGRPC:
+		if cfg.Port == 0 {
+			cfg.Port = 6334 // Qdrant gRPC port
+		}
+		client, err := qdrant.NewClient(&qdrant.Config{
+			Host:   cfg.Host,
+			Port:   cfg.Port,
+			APIKey: cfg.APIKey,
+			UseTLS: cfg.UseTLS,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Qdrant client for %s:%d: %w\n"+
+				"  TIP: Troubleshooting:\n"+
+				"     - Ensure Qdrant is running\n"+
+				"     - Verify host and port configuration (gRPC listens on 6334, REST on 6333)\n"+
+				"     - For Docker: start Qdrant container (docker run -p 6333:6333 -p 6334:6334 qdrant/qdrant)",
+				cfg.Host, cfg.Port, err)
+		}
+		transport = &qdrantGRPC{client: client}
+	case QdrantProtocolREST:
+		if cfg.Port == 0 {
+			cfg.Port = 6333 // Qdrant REST port
+		}
+		transport = newQdrantREST(cfg)
+	default:
+		return nil, fmt.Errorf("invalid qdrant protocol %q (valid: grpc, rest)", cfg.Protocol)
 	}
 
 	return &QdrantProvider{
-		client: client,
-		config: cfg,
+		transport: transport,
+		config:    cfg,
 	}, nil
 }
 
@@ -85,96 +158,55 @@ func (p *QdrantProvider) Name() string {
 
 // Upsert adds or updates a document with its vector.
 func (p *QdrantProvider) Upsert(ctx context.Context, collection string, id string, vector []float32, metadata map[string]any) error {
-	// Check if collection exists, create if not
-	exists, err := p.client.CollectionExists(ctx, collection)
-	if err != nil {
-		return fmt.Errorf("failed to check collection existence: %w", err)
+	if err := p.ensureCollection(ctx, collection, len(vector)); err != nil {
+		return err
 	}
 
-	if !exists {
-		err = p.client.CreateCollection(ctx, &qdrant.CreateCollection{
-			CollectionName: collection,
-			VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
-				Size:     uint64(len(vector)),
-				Distance: qdrant.Distance_Cosine,
-			}),
-		})
-		if err != nil && !strings.Contains(err.Error(), "already exists") {
-			return fmt.Errorf("failed to create collection: %w", err)
-		}
-	}
-
-	// Convert metadata to Qdrant payload
-	payload := make(map[string]*qdrant.Value)
-	for key, value := range metadata {
-		val, err := qdrant.NewValue(value)
-		if err != nil {
-			return fmt.Errorf("failed to convert metadata value for key %s: %w", key, err)
-		}
-		payload[key] = val
-	}
-
-	point := &qdrant.PointStruct{
-		Id:      qdrant.NewID(id),
-		Vectors: qdrant.NewVectors(vector...),
-		Payload: payload,
+	payload, err := qdrantPayload(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to convert metadata: %w", err)
 	}
+	payload[qdrantIDField] = id
 
-	_, err = p.client.Upsert(ctx, &qdrant.UpsertPoints{
-		CollectionName: collection,
-		Points:         []*qdrant.PointStruct{point},
-	})
-	if err != nil {
+	if err := p.transport.upsert(ctx, collection, qdrantPointID(id), vector, payload); err != nil {
 		return fmt.Errorf("failed to upsert point: %w", err)
 	}
-
 	return nil
 }
 
 // Search finds the most similar vectors.
 func (p *QdrantProvider) Search(ctx context.Context, collection string, vector []float32, topK int) ([]Result, error) {
-	return p.SearchWithFilter(ctx, collection, vector, topK, nil)
+	return p.SearchWithThreshold(ctx, collection, vector, topK, nil, 0)
 }
 
 // SearchWithFilter combines vector similarity with metadata filtering.
 func (p *QdrantProvider) SearchWithFilter(ctx context.Context, collection string, vector []float32, topK int, filter map[string]any) ([]Result, error) {
-	searchRequest := &qdrant.SearchPoints{
-		CollectionName: collection,
-		Vector:         vector,
-		Limit:          uint64(topK),
-		WithPayload:    qdrant.NewWithPayload(true),
-		WithVectors:    qdrant.NewWithVectors(true),
-	}
-
-	if len(filter) > 0 {
-		searchRequest.Filter = buildQdrantFilter(filter)
-	}
+	return p.SearchWithThreshold(ctx, collection, vector, topK, filter, 0)
+}
 
-	pointsClient := p.client.GetPointsClient()
-	searchResult, err := pointsClient.Search(ctx, searchRequest)
+// SearchWithThreshold searches like SearchWithFilter, leaving out results
+// scoring worse than threshold on the server (implements ThresholdSearcher).
+func (p *QdrantProvider) SearchWithThreshold(ctx context.Context, collection string, vector []float32, topK int, filter map[string]any, threshold float32) ([]Result, error) {
+	results, err := p.transport.search(ctx, collection, vector, topK, filter, threshold)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search points: %w", err)
 	}
-
-	return convertQdrantResults(searchResult.Result), nil
+	for i := range results {
+		r := &results[i]
+		if id, ok := r.Metadata[qdrantIDField].(string); ok {
+			r.ID = id
+			delete(r.Metadata, qdrantIDField)
+		}
+		if content, ok := r.Metadata["content"].(string); ok {
+			r.Content = content
+		}
+	}
+	return results, nil
 }
 
 // Delete removes a document by ID.
 func (p *QdrantProvider) Delete(ctx context.Context, collection string, id string) error {
-	deletePoints := &qdrant.DeletePoints{
-		CollectionName: collection,
-		Points: &qdrant.PointsSelector{
-			PointsSelectorOneOf: &qdrant.PointsSelector_Points{
-				Points: &qdrant.PointsIdsList{
-					Ids: []*qdrant.PointId{
-						{PointIdOptions: &qdrant.PointId_Uuid{Uuid: id}},
-					},
-				},
-			},
-		},
-	}
-	_, err := p.client.Delete(ctx, deletePoints)
-	if err != nil {
+	if err := p.transport.deletePoint(ctx, collection, qdrantPointID(id)); err != nil {
 		return fmt.Errorf("failed to delete point %s: %w", id, err)
 	}
 	return nil
@@ -182,93 +214,238 @@ func (p *QdrantProvider) Delete(ctx context.Context, collection string, id strin
 
 // DeleteByFilter removes all documents matching the filter.
 func (p *QdrantProvider) DeleteByFilter(ctx context.Context, collection string, filter map[string]any) error {
-	qdrantFilter := buildQdrantFilter(filter)
+	if err := p.transport.deleteByFilter(ctx, collection, filter); err != nil {
+		return fmt.Errorf("failed to delete by filter: %w", err)
+	}
+	return nil
+}
 
-	deletePoints := &qdrant.DeletePoints{
-		CollectionName: collection,
-		Points: &qdrant.PointsSelector{
-			PointsSelectorOneOf: &qdrant.PointsSelector_Filter{
-				Filter: qdrantFilter,
-			},
-		},
+// CreateCollection creates a new collection.
+func (p *QdrantProvider) CreateCollection(ctx context.Context, collection string, vectorDimension int) error {
+	return p.ensureCollection(ctx, collection, vectorDimension)
+}
+
+// DeleteCollection removes a collection.
+func (p *QdrantProvider) DeleteCollection(ctx context.Context, collection string) error {
+	p.collections.Delete(collection)
+	if err := p.transport.deleteCollection(ctx, collection); err != nil {
+		return fmt.Errorf("failed to delete collection: %w", err)
 	}
+	return nil
+}
 
-	_, err := p.client.Delete(ctx, deletePoints)
+// Close closes the Qdrant client.
+func (p *QdrantProvider) Close() error {
+	return p.transport.close()
+}
+
+// ensureCollection creates a collection with the configured distance if it
+// does not exist yet.
+func (p *QdrantProvider) ensureCollection(ctx context.Context, collection string, size int) error {
+	if _, ok := p.collections.Load(collection); ok {
+		return nil
+	}
+
+	exists, err := p.transport.collectionExists(ctx, collection)
 	if err != nil {
-		return fmt.Errorf("failed to delete by filter: %w", err)
+		return fmt.Errorf("failed to check collection existence: %w", err)
 	}
+	if !exists {
+		err := p.transport.createCollection(ctx, collection, size, p.config.Distance)
+		if err != nil && !strings.Contains(err.Error(), "already exists") {
+			return fmt.Errorf("failed to create collection: %w", err)
+		}
+	}
+	p.collections.Store(collection, struct{}{})
 	return nil
 }
 
-// CreateCollection creates a new collection.
-func (p *QdrantProvider) CreateCollection(ctx context.Context, collection string, vectorDimension int) error {
-	exists, err := p.client.CollectionExists(ctx, collection)
+// qdrantPointID returns the point UUID for a document ID. IDs that are
+// UUIDs already are used as-is.
+func qdrantPointID(id string) string {
+	if parsed, err := uuid.Parse(id); err == nil {
+		return parsed.String()
+	}
+	return uuid.NewSHA1(qdrantIDNamespace, []byte(id)).String()
+}
+
+// qdrantPayload converts metadata to plain JSON values, so that any
+// serializable value can be stored. Whole numbers become int64 so they
+// can be matched as integers.
+func qdrantPayload(metadata map[string]any) (map[string]any, error) {
+	data, err := json.Marshal(metadata)
 	if err != nil {
-		return fmt.Errorf("failed to check collection: %w", err)
+		return nil, err
 	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var payload map[string]any
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, err
+	}
+	if payload == nil {
+		payload = make(map[string]any)
+	}
+	for key, value := range payload {
+		payload[key] = qdrantNumbers(value)
+	}
+	return payload, nil
+}
 
-	if exists {
-		return nil
+func qdrantNumbers(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case []any:
+		for i := range v {
+			v[i] = qdrantNumbers(v[i])
+		}
+	case map[string]any:
+		for key := range v {
+			v[key] = qdrantNumbers(v[key])
+		}
 	}
+	return value
+}
 
-	err = p.client.CreateCollection(ctx, &qdrant.CreateCollection{
+// qdrantGRPC is the gRPC transport.
+type qdrantGRPC struct {
+	client *qdrant.Client
+}
+
+func (t *qdrantGRPC) collectionExists(ctx context.Context, collection string) (bool, error) {
+	return t.client.CollectionExists(ctx, collection)
+}
+
+func (t *qdrantGRPC) createCollection(ctx context.Context, collection string, size int, distance string) error {
+	return t.client.CreateCollection(ctx, &qdrant.CreateCollection{
 		CollectionName: collection,
 		VectorsConfig: qdrant.NewVectorsConfig(&qdrant.VectorParams{
-			Size:     uint64(vectorDimension),
-			Distance: qdrant.Distance_Cosine,
+			Size:     uint64(size),
+			Distance: qdrantGRPCDistance(distance),
 		}),
 	})
+}
+
+func (t *qdrantGRPC) deleteCollection(ctx context.Context, collection string) error {
+	return t.client.DeleteCollection(ctx, collection)
+}
+
+func (t *qdrantGRPC) upsert(ctx context.Context, collection, pointID string, vector []float32, payload map[string]any) error {
+	values, err := qdrant.TryValueMap(payload)
 	if err != nil {
-		return fmt.Errorf("failed to create collection: %w", err)
+		return err
 	}
-	return nil
+	wait := true
+	_, err = t.client.Upsert(ctx, &qdrant.UpsertPoints{
+		CollectionName: collection,
+		Wait:           &wait,
+		Points: []*qdrant.PointStruct{{
+			Id:      qdrant.NewIDUUID(pointID),
+			Vectors: qdrant.NewVectors(vector...),
+			Payload: values,
+		}},
+	})
+	return err
 }
 
-// DeleteCollection removes a collection.
-func (p *QdrantProvider) DeleteCollection(ctx context.Context, collection string) error {
-	err := p.client.DeleteCollection(ctx, collection)
+func (t *qdrantGRPC) search(ctx context.Context, collection string, vector []float32, limit int, filter map[string]any, threshold float32) ([]Result, error) {
+	req := &qdrant.SearchPoints{
+		CollectionName: collection,
+		Vector:         vector,
+		Limit:          uint64(limit),
+		WithPayload:    qdrant.NewWithPayload(true),
+		WithVectors:    qdrant.NewWithVectors(true),
+	}
+	if len(filter) > 0 {
+		req.Filter = buildQdrantFilter(filter)
+	}
+	if threshold > 0 {
+		req.ScoreThreshold = &threshold
+	}
+
+	resp, err := t.client.GetPointsClient().Search(ctx, req)
 	if err != nil {
-		return fmt.Errorf("failed to delete collection: %w", err)
+		return nil, err
 	}
-	return nil
+	return convertQdrantResults(resp.Result), nil
 }
 
-// Close closes the Qdrant client.
-func (p *QdrantProvider) Close() error {
-	return p.client.Close()
+func (t *qdrantGRPC) deletePoint(ctx context.Context, collection, pointID string) error {
+	_, err := t.client.Delete(ctx, &qdrant.DeletePoints{
+		CollectionName: collection,
+		Points:         qdrant.NewPointsSelector(qdrant.NewIDUUID(pointID)),
+	})
+	return err
 }
 
-// buildQdrantFilter converts a filter map to Qdrant filter.
+func (t *qdrantGRPC) deleteByFilter(ctx context.Context, collection string, filter map[string]any) error {
+	_, err := t.client.Delete(ctx, &qdrant.DeletePoints{
+		CollectionName: collection,
+		Points:         qdrant.NewPointsSelectorFilter(buildQdrantFilter(filter)),
+	})
+	return err
+}
+
+func (t *qdrantGRPC) close() error {
+	return t.client.Close()
+}
+
+func qdrantGRPCDistance(distance string) qdrant.Distance {
+	switch distance {
+	case "dot":
+		return qdrant.Distance_Dot
+	case "euclid":
+		return qdrant.Distance_Euclid
+	case "manhattan":
+		return qdrant.Distance_Manhattan
+	default:
+		return qdrant.Distance_Cosine
+	}
+}
+
+// buildQdrantFilter converts a filter map to a Qdrant filter matching all
+// fields exactly. Values other than strings, integers and booleans are
+// skipped.
 func buildQdrantFilter(filter map[string]any) *qdrant.Filter {
 	conditions := make([]*qdrant.Condition, 0, len(filter))
-
 	for key, value := range filter {
-		val, err := qdrant.NewValue(value)
-		if err != nil {
-			continue
-		}
-
-		condition := &qdrant.Condition{
-			ConditionOneOf: &qdrant.Condition_Field{
-				Field: &qdrant.FieldCondition{
-					Key: key,
-					Match: &qdrant.Match{
-						MatchValue: &qdrant.Match_Keyword{
-							Keyword: val.GetStringValue(),
-						},
-					},
-				},
-			},
+		switch v := qdrantFilterValue(value).(type) {
+		case string:
+			conditions = append(conditions, qdrant.NewMatchKeyword(key, v))
+		case int64:
+			conditions = append(conditions, qdrant.NewMatchInt(key, v))
+		case bool:
+			conditions = append(conditions, qdrant.NewMatchBool(key, v))
 		}
-		conditions = append(conditions, condition)
 	}
+	return &qdrant.Filter{Must: conditions}
+}
 
-	return &qdrant.Filter{
-		Must: conditions,
+// qdrantFilterValue normalizes a filter value to string, int64 or bool,
+// or returns nil if it cannot be matched exactly.
+func qdrantFilterValue(value any) any {
+	switch v := value.(type) {
+	case string, bool, int64:
+		return v
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case float64:
+		if v == float64(int64(v)) {
+			return int64(v)
+		}
 	}
+	return nil
 }
 
-// convertQdrantResults converts Qdrant results to our Result type.
+// convertQdrantResults converts Qdrant results to our Result type. IDs are
+// point IDs; the provider maps them back to document IDs.
 func convertQdrantResults(points []*qdrant.ScoredPoint) []Result {
 	results := make([]Result, 0, len(points))
 
@@ -295,53 +472,13 @@ func convertQdrantResults(points []*qdrant.ScoredPoint) []Result {
 			}
 		}
 
-		metadata := make(map[string]any)
-		if point.Payload != nil {
-			for key, value := range point.Payload {
-				switch v := value.Kind.(type) {
-				case *qdrant.Value_StringValue:
-					metadata[key] = v.StringValue
-				case *qdrant.Value_IntegerValue:
-					metadata[key] = v.IntegerValue
-				case *qdrant.Value_DoubleValue:
-					metadata[key] = v.DoubleValue
-				case *qdrant.Value_BoolValue:
-					metadata[key] = v.BoolValue
-				case *qdrant.Value_ListValue:
-					if v.ListValue != nil {
-						list := make([]any, len(v.ListValue.Values))
-						for i, item := range v.ListValue.Values {
-							switch itemVal := item.Kind.(type) {
-							case *qdrant.Value_StringValue:
-								list[i] = itemVal.StringValue
-							case *qdrant.Value_IntegerValue:
-								list[i] = itemVal.IntegerValue
-							case *qdrant.Value_DoubleValue:
-								list[i] = itemVal.DoubleValue
-							case *qdrant.Value_BoolValue:
-								list[i] = itemVal.BoolValue
-							default:
-								list[i] = item
-							}
-						}
-						metadata[key] = list
-					}
-				default:
-					metadata[key] = value
-				}
-			}
-		}
-
-		content := ""
-		if contentValue, exists := metadata["content"]; exists {
-			if contentStr, ok := contentValue.(string); ok {
-				content = contentStr
-			}
+		metadata := make(map[string]any, len(point.Payload))
+		for key, value := range point.Payload {
+			metadata[key] = qdrantValue(value)
 		}
 
 		results = append(results, Result{
 			ID:       id,
-			Content:  content,
 			Vector:   vector,
 			Metadata: metadata,
 			Score:    point.Score,
@@ -351,5 +488,36 @@ func convertQdrantResults(points []*qdrant.ScoredPoint) []Result {
 	return results
 }
 
-// Ensure QdrantProvider implements Provider.
-var _ Provider = (*QdrantProvider)(nil)
+// qdrantValue converts a payload value to a plain Go value.
+func qdrantValue(value *qdrant.Value) any {
+	switch v := value.GetKind().(type) {
+	case *qdrant.Value_StringValue:
+		return v.StringValue
+	case *qdrant.Value_IntegerValue:
+		return v.IntegerValue
+	case *qdrant.Value_DoubleValue:
+		return v.DoubleValue
+	case *qdrant.Value_BoolValue:
+		return v.BoolValue
+	case *qdrant.Value_ListValue:
+		list := make([]any, len(v.ListValue.GetValues()))
+		for i, item := range v.ListValue.GetValues() {
+			list[i] = qdrantValue(item)
+		}
+		return list
+	case *qdrant.Value_StructValue:
+		fields := make(map[string]any, len(v.StructValue.GetFields()))
+		for key, item := range v.StructValue.GetFields() {
+			fields[key] = qdrantValue(item)
+		}
+		return fields
+	default:
+		return nil
+	}
+}
+
+// Ensure QdrantProvider implements Provider and ThresholdSearcher.
+var (
+	_ Provider          = (*QdrantProvider)(nil)
+	_ ThresholdSearcher = (*QdrantProvider)(nil)
+)
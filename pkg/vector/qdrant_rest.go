@@ -0,0 +1,211 @@
+// SPDX-License-Identifier: AGPL-3.0
+// Copyright 2025 Kadir Pekel
+//
+// Licensed under the GNU Affero General Public License v3.0 (AGPL-3.0) (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     https://www.gnu.org/licenses/agpl-3.0.en.html
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package vector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// qdrantREST is the REST transport, for deployments that only expose
+// Qdrant's HTTP API.
+type qdrantREST struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+func newQdrantREST(cfg QdrantConfig) *qdrantREST {
+	scheme := "http"
+	if cfg.UseTLS {
+		scheme = "https"
+	}
+	return &qdrantREST{
+		baseURL:    fmt.Sprintf("%s://%s:%d", scheme, cfg.Host, cfg.Port),
+		apiKey:     cfg.APIKey,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// qdrantRESTDistances maps distance names to the REST API's spelling.
+var qdrantRESTDistances = map[string]string{
+	"cosine":    "Cosine",
+	"dot":       "Dot",
+	"euclid":    "Euclid",
+	"manhattan": "Manhattan",
+}
+
+// qdrantRESTPoint is a scored point in a search response.
+type qdrantRESTPoint struct {
+	ID      any            `json:"id"`
+	Score   float32        `json:"score"`
+	Payload map[string]any `json:"payload"`
+	Vector  []float32      `json:"vector"`
+}
+
+func (t *qdrantREST) collectionExists(ctx context.Context, collection string) (bool, error) {
+	var resp struct {
+		Result struct {
+			Exists bool `json:"exists"`
+		} `json:"result"`
+	}
+	if err := t.do(ctx, http.MethodGet, collectionPath(collection, "/exists"), nil, &resp); err != nil {
+		return false, err
+	}
+	return resp.Result.Exists, nil
+}
+
+func (t *qdrantREST) createCollection(ctx context.Context, collection string, size int, distance string) error {
+	body := map[string]any{
+		"vectors": map[string]any{
+			"size":     size,
+			"distance": qdrantRESTDistances[distance],
+		},
+	}
+	return t.do(ctx, http.MethodPut, collectionPath(collection, ""), body, nil)
+}
+
+func (t *qdrantREST) deleteCollection(ctx context.Context, collection string) error {
+	return t.do(ctx, http.MethodDelete, collectionPath(collection, ""), nil, nil)
+}
+
+func (t *qdrantREST) upsert(ctx context.Context, collection, pointID string, vector []float32, payload map[string]any) error {
+	body := map[string]any{
+		"points": []map[string]any{{
+			"id":      pointID,
+			"vector":  vector,
+			"payload": payload,
+		}},
+	}
+	return t.do(ctx, http.MethodPut, collectionPath(collection, "/points?wait=true"), body, nil)
+}
+
+func (t *qdrantREST) search(ctx context.Context, collection string, vector []float32, limit int, filter map[string]any, threshold float32) ([]Result, error) {
+	body := map[string]any{
+		"vector":       vector,
+		"limit":        limit,
+		"with_payload": true,
+		"with_vector":  true,
+	}
+	if len(filter) > 0 {
+		body["filter"] = buildQdrantRESTFilter(filter)
+	}
+	if threshold > 0 {
+		body["score_threshold"] = threshold
+	}
+
+	var resp struct {
+		Result []qdrantRESTPoint `json:"result"`
+	}
+	if err := t.do(ctx, http.MethodPost, collectionPath(collection, "/points/search"), body, &resp); err != nil {
+		return nil, err
+	}
+
+	results := make([]Result, 0, len(resp.Result))
+	for _, point := range resp.Result {
+		metadata := point.Payload
+		if metadata == nil {
+			metadata = make(map[string]any)
+		}
+		results = append(results, Result{
+			ID:       fmt.Sprint(point.ID),
+			Score:    point.Score,
+			Vector:   point.Vector,
+			Metadata: metadata,
+		})
+	}
+	return results, nil
+}
+
+func (t *qdrantREST) deletePoint(ctx context.Context, collection, pointID string) error {
+	body := map[string]any{"points": []string{pointID}}
+	return t.do(ctx, http.MethodPost, collectionPath(collection, "/points/delete?wait=true"), body, nil)
+}
+
+func (t *qdrantREST) deleteByFilter(ctx context.Context, collection string, filter map[string]any) error {
+	body := map[string]any{"filter": buildQdrantRESTFilter(filter)}
+	return t.do(ctx, http.MethodPost, collectionPath(collection, "/points/delete?wait=true"), body, nil)
+}
+
+func (t *qdrantREST) close() error {
+	t.httpClient.CloseIdleConnections()
+	return nil
+}
+
+// buildQdrantRESTFilter is buildQdrantFilter for the REST API.
+func buildQdrantRESTFilter(filter map[string]any) map[string]any {
+	conditions := make([]map[string]any, 0, len(filter))
+	for key, value := range filter {
+		if v := qdrantFilterValue(value); v != nil {
+			conditions = append(conditions, map[string]any{
+				"key":   key,
+				"match": map[string]any{"value": v},
+			})
+		}
+	}
+	return map[string]any{"must": conditions}
+}
+
+func collectionPath(collection, suffix string) string {
+	return "/collections/" + url.PathEscape(collection) + suffix
+}
+
+// do sends a request to the REST API and decodes the JSON response into
+// out, if given.
+func (t *qdrantREST) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(jsonData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, t.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if t.apiKey != "" {
+		req.Header.Set("api-key", t.apiKey)
+	}
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("status %d, body: %s", resp.StatusCode, string(respBody))
+	}
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return nil
+}
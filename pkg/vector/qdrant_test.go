@@ -0,0 +1,138 @@
+package vector
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+)
+
+// fakeQdrant records REST requests and answers searches with one point.
+type fakeQdrant struct {
+	collections map[string]map[string]any
+	points      map[string]map[string]any
+	searches    []map[string]any
+	deletes     []map[string]any
+	apiKeys     []string
+}
+
+func (f *fakeQdrant) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.apiKeys = append(f.apiKeys, r.Header.Get("api-key"))
+	var body map[string]any
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	var result any = true
+	switch path := r.URL.Path; {
+	case r.Method == http.MethodGet && path == "/collections/docs/exists":
+		result = map[string]any{"exists": f.collections["docs"] != nil}
+	case r.Method == http.MethodPut && path == "/collections/docs":
+		f.collections["docs"] = body
+	case r.Method == http.MethodPut && path == "/collections/docs/points":
+		for _, p := range body["points"].([]any) {
+			point := p.(map[string]any)
+			f.points[point["id"].(string)] = point
+		}
+	case r.Method == http.MethodPost && path == "/collections/docs/points/search":
+		f.searches = append(f.searches, body)
+		var hits []map[string]any
+		for id, point := range f.points {
+			hits = append(hits, map[string]any{"id": id, "score": 0.9, "payload": point["payload"], "vector": point["vector"]})
+		}
+		result = hits
+	case r.Method == http.MethodPost && path == "/collections/docs/points/delete":
+		f.deletes = append(f.deletes, body)
+	default:
+		http.Error(w, `{"status":{"error":"not found"}}`, http.StatusNotFound)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"result": result, "status": "ok"})
+}
+
+func newRESTQdrant(t *testing.T) (*QdrantProvider, *fakeQdrant) {
+	t.Helper()
+	fake := &fakeQdrant{collections: map[string]map[string]any{}, points: map[string]map[string]any{}}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	u, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(u.Port())
+	p, err := NewQdrantProvider(QdrantConfig{
+		Host:     u.Hostname(),
+		Port:     port,
+		APIKey:   "secret",
+		Protocol: QdrantProtocolREST,
+		Distance: "dot",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p, fake
+}
+
+func TestQdrantRESTRoundTrip(t *testing.T) {
+	p, fake := newRESTQdrant(t)
+	ctx := context.Background()
+
+	meta := map[string]any{"content": "hello", "page": 3, "tags": []string{"a"}}
+	if err := p.Upsert(ctx, "docs", "doc-1#chunk-0", []float32{1, 0, 0}, meta); err != nil {
+		t.Fatalf("Upsert() error = %v", err)
+	}
+	vectors := fake.collections["docs"]["vectors"].(map[string]any)
+	if vectors["size"] != float64(3) || vectors["distance"] != "Dot" {
+		t.Errorf("collection vectors = %v, want size 3 and Dot distance", vectors)
+	}
+	pointID := qdrantPointID("doc-1#chunk-0")
+	if fake.points[pointID] == nil {
+		t.Fatalf("point %s not stored, have %v", pointID, fake.points)
+	}
+
+	results, err := p.SearchWithThreshold(ctx, "docs", []float32{1, 0, 0}, 5, map[string]any{"page": 3, "lang": "en"}, 0.5)
+	if err != nil {
+		t.Fatalf("SearchWithThreshold() error = %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "doc-1#chunk-0" || results[0].Content != "hello" {
+		t.Fatalf("results = %+v", results)
+	}
+	if _, ok := results[0].Metadata[qdrantIDField]; ok {
+		t.Errorf("metadata leaks %s: %v", qdrantIDField, results[0].Metadata)
+	}
+	search := fake.searches[0]
+	if search["score_threshold"] != 0.5 || len(search["filter"].(map[string]any)["must"].([]any)) != 2 {
+		t.Errorf("search request = %v", search)
+	}
+
+	if err := p.Delete(ctx, "docs", "doc-1#chunk-0"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if ids := fake.deletes[0]["points"].([]any); len(ids) != 1 || ids[0] != pointID {
+		t.Errorf("deleted points = %v, want [%s]", ids, pointID)
+	}
+
+	for _, key := range fake.apiKeys {
+		if key != "secret" {
+			t.Fatalf("request sent api-key %q", key)
+		}
+	}
+}
+
+func TestNewQdrantProviderValidates(t *testing.T) {
+	if _, err := NewQdrantProvider(QdrantConfig{Protocol: "http"}); err == nil {
+		t.Error("expected error for unknown protocol")
+	}
+	if _, err := NewQdrantProvider(QdrantConfig{Protocol: QdrantProtocolREST, Distance: "hamming"}); err == nil {
+		t.Error("expected error for unknown distance")
+	}
+}
+
+func TestQdrantPointID(t *testing.T) {
+	const id = "3f2b6a9e-5c1d-4e8f-9a7b-1c2d3e4f5a6b"
+	if got := qdrantPointID(id); got != id {
+		t.Errorf("qdrantPointID(uuid) = %s, want it unchanged", got)
+	}
+	if qdrantPointID("a") != qdrantPointID("a") || qdrantPointID("a") == qdrantPointID("b") {
+		t.Error("derived point IDs must be stable and distinct")
+	}
+}
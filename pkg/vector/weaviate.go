@@ -22,8 +22,14 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 // WeaviateConfig configures the Weaviate vector provider.
@@ -33,24 +39,40 @@ type WeaviateConfig struct {
 	// Host is the Weaviate server hostname.
 	Host string `yaml:"host"`
 
-	// Port is the Weaviate HTTP port (default: 8080).
+	// Port is the Weaviate HTTP port (default: 8080, or 443 with TLS).
 	Port int `yaml:"port,omitempty"`
 
-	// APIKey for authenticated access (optional).
+	// APIKey for authenticated access (optional, anonymous access without).
 	APIKey string `yaml:"api_key,omitempty"`
 
 	// UseTLS enables HTTPS connections.
 	UseTLS bool `yaml:"use_tls,omitempty"`
 }
 
+// weaviateIDProperty stores the document ID given by the caller. Weaviate
+// object IDs must be UUIDs, so objects are keyed by a UUID derived from it.
+const weaviateIDProperty = "hector_id"
+
+// weaviateIDNamespace namespaces the UUIDs derived from document IDs.
+var weaviateIDNamespace = uuid.MustParse("b9db127f-8b62-4640-9af8-9593e0634398")
+
 // WeaviateProvider implements Provider using Weaviate vector database.
 //
-// Direct port from legacy pkg/databases/weaviate.go
+// Each collection maps to a class, created on first use without a
+// vectorizer since vectors come from the embedder. Metadata fields become
+// class properties, added as new fields appear: strings, booleans,
+// integers, floats, string lists and times keep their type, other values
+// are stored as JSON text. Collection and field names are adapted to
+// Weaviate's naming rules (e.g. "documents" becomes class "Documents").
 type WeaviateProvider struct {
 	baseURL    string
 	apiKey     string
 	httpClient *http.Client
 	config     WeaviateConfig
+
+	// classes caches the properties and data types of existing classes
+	mu      sync.Mutex
+	classes map[string]map[string]string
 }
 
 // NewWeaviateProvider creates a new Weaviate provider.
@@ -67,6 +89,9 @@ func NewWeaviateProvider(cfg WeaviateConfig) (*WeaviateProvider, error) {
 	port := cfg.Port
 	if port == 0 {
 		port = 8080
+		if cfg.UseTLS {
+			port = 443
+		}
 	}
 
 	baseURL := fmt.Sprintf("%s://%s:%d", scheme, cfg.Host, port)
@@ -80,6 +105,7 @@ func NewWeaviateProvider(cfg WeaviateConfig) (*WeaviateProvider, error) {
 		apiKey:     cfg.APIKey,
 		httpClient: httpClient,
 		config:     cfg,
+		classes:    make(map[string]map[string]string),
 	}, nil
 }
 
@@ -90,50 +116,44 @@ func (p *WeaviateProvider) Name() string {
 
 // Upsert adds or updates a document with its vector.
 func (p *WeaviateProvider) Upsert(ctx context.Context, collection string, id string, vector []float32, metadata map[string]any) error {
-	// Prepare properties (metadata)
-	properties := make(map[string]any)
-	for k, v := range metadata {
-		properties[k] = v
-	}
-
-	// Convert vector to []float64 for JSON
-	vector64 := make([]float64, len(vector))
-	for i, v := range vector {
-		vector64[i] = float64(v)
-	}
-
-	payload := map[string]any{
-		"id":         id,
-		"class":      collection,
-		"properties": properties,
-		"vector":     vector64,
-	}
-
-	jsonData, err := json.Marshal(payload)
+	class := weaviateClassName(collection)
+	props, err := p.ensureClass(ctx, class, metadata)
 	if err != nil {
-		return fmt.Errorf("failed to marshal payload: %w", err)
+		return err
 	}
 
-	url := fmt.Sprintf("%s/v1/objects", p.baseURL)
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+	properties := map[string]any{weaviateIDProperty: id}
+	for key, value := range metadata {
+		name := weaviatePropertyName(key)
+		properties[name] = weaviateValue(value, props[name])
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	if p.apiKey != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.apiKey))
-	}
-
-	resp, err := p.httpClient.Do(req)
-	if err != nil {
+	// The batch endpoint replaces existing objects, unlike POST /v1/objects
+	payload := map[string]any{
+		"objects": []map[string]any{{
+			"class":      class,
+			"id":         weaviateObjectID(id),
+			"properties": properties,
+			"vector":     vector,
+		}},
+	}
+
+	var results []struct {
+		Result struct {
+			Errors *struct {
+				Error []struct {
+					Message string `json:"message"`
+				} `json:"error"`
+			} `json:"errors"`
+		} `json:"result"`
+	}
+	if _, err := p.do(ctx, http.MethodPost, "/v1/batch/objects", payload, &results); err != nil {
 		return fmt.Errorf("failed to upsert object: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to upsert object: status %d, body: %s", resp.StatusCode, string(body))
+	for _, r := range results {
+		if r.Result.Errors != nil && len(r.Result.Errors.Error) > 0 {
+			return fmt.Errorf("failed to upsert object: %s", r.Result.Errors.Error[0].Message)
+		}
 	}
 
 	return nil
@@ -146,324 +166,495 @@ func (p *WeaviateProvider) Search(ctx context.Context, collection string, vector
 
 // SearchWithFilter combines vector similarity with metadata filtering.
 func (p *WeaviateProvider) SearchWithFilter(ctx context.Context, collection string, vector []float32, topK int, filter map[string]any) ([]Result, error) {
-	vector64 := make([]float64, len(vector))
-	for i, v := range vector {
-		vector64[i] = float64(v)
-	}
-
-	query := map[string]any{
-		"query": fmt.Sprintf(`
-		{
-			Get {
-				%s {
-					_additional {
-						id
-						certainty
-						distance
-					}
-					content
-				}
-			}
-		}`, collection),
-		"nearVector": map[string]any{
-			"vector": vector64,
-		},
-		"limit": topK,
+	class := weaviateClassName(collection)
+	props, exists, err := p.lookupClass(ctx, class)
+	if err != nil {
+		return nil, err
 	}
-
-	if len(filter) > 0 {
-		query["where"] = buildWeaviateWhereClause(filter)
+	// Nothing was indexed yet, or nothing can match a field never indexed
+	if !exists || !hasWeaviateProperties(props, filter) {
+		return []Result{}, nil
 	}
 
-	jsonData, err := json.Marshal(query)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal query: %w", err)
+	var fields []string
+	for name, dataType := range props {
+		if weaviateScalarTypes[strings.TrimSuffix(dataType, "[]")] {
+			fields = append(fields, name)
+		}
 	}
+	sort.Strings(fields)
 
-	url := fmt.Sprintf("%s/v1/graphql", p.baseURL)
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	vectorLiterals := make([]string, len(vector))
+	for i, v := range vector {
+		vectorLiterals[i] = strconv.FormatFloat(float64(v), 'g', -1, 32)
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	if p.apiKey != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.apiKey))
+	args := fmt.Sprintf("nearVector: {vector: [%s]}, limit: %d", strings.Join(vectorLiterals, ", "), topK)
+	if len(filter) > 0 {
+		args += ", where: " + weaviateWhereGraphQL(filter)
 	}
+	query := fmt.Sprintf("{ Get { %s(%s) { %s _additional { id distance } } } }", class, args, strings.Join(fields, " "))
 
-	resp, err := p.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to search: %w", err)
+	var response struct {
+		Data struct {
+			Get map[string][]map[string]any `json:"Get"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("search failed: status %d, body: %s", resp.StatusCode, string(body))
+	if _, err := p.do(ctx, http.MethodPost, "/v1/graphql", map[string]any{"query": query}, &response); err != nil {
+		return nil, fmt.Errorf("failed to search: %w", err)
 	}
-
-	var result map[string]any
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if len(response.Errors) > 0 {
+		return nil, fmt.Errorf("search failed: %s", response.Errors[0].Message)
 	}
 
-	return convertWeaviateResults(result, collection), nil
+	return convertWeaviateResults(response.Data.Get[class]), nil
 }
 
 // Delete removes a document by ID.
 func (p *WeaviateProvider) Delete(ctx context.Context, collection string, id string) error {
-	url := fmt.Sprintf("%s/v1/objects/%s/%s", p.baseURL, collection, id)
-	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
-
-	if p.apiKey != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.apiKey))
-	}
-
-	resp, err := p.httpClient.Do(req)
-	if err != nil {
+	path := fmt.Sprintf("/v1/objects/%s/%s", url.PathEscape(weaviateClassName(collection)), weaviateObjectID(id))
+	if _, err := p.do(ctx, http.MethodDelete, path, nil, nil); err != nil {
 		return fmt.Errorf("failed to delete object: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to delete object: status %d, body: %s", resp.StatusCode, string(body))
-	}
-
 	return nil
 }
 
 // DeleteByFilter removes all documents matching the filter.
 func (p *WeaviateProvider) DeleteByFilter(ctx context.Context, collection string, filter map[string]any) error {
-	whereClause := buildWeaviateWhereClause(filter)
-	if whereClause == nil {
+	if len(filter) == 0 {
 		return fmt.Errorf("filter is required for delete by filter")
 	}
 
+	class := weaviateClassName(collection)
+	props, exists, err := p.lookupClass(ctx, class)
+	if err != nil {
+		return err
+	}
+	if !exists || !hasWeaviateProperties(props, filter) {
+		return nil
+	}
+
 	payload := map[string]any{
 		"match": map[string]any{
-			"class": collection,
-			"where": whereClause,
+			"class": class,
+			"where": buildWeaviateWhereClause(filter),
 		},
 	}
 
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("failed to marshal payload: %w", err)
+	// Each call deletes up to the server's query limit
+	for {
+		var response struct {
+			Results struct {
+				Matches    int `json:"matches"`
+				Limit      int `json:"limit"`
+				Successful int `json:"successful"`
+				Failed     int `json:"failed"`
+			} `json:"results"`
+		}
+		if _, err := p.do(ctx, http.MethodDelete, "/v1/batch/objects", payload, &response); err != nil {
+			return fmt.Errorf("failed to delete by filter: %w", err)
+		}
+		if response.Results.Failed > 0 {
+			return fmt.Errorf("failed to delete by filter: %d objects failed", response.Results.Failed)
+		}
+		if response.Results.Successful == 0 || response.Results.Matches < response.Results.Limit {
+			return nil
+		}
 	}
+}
 
-	url := fmt.Sprintf("%s/v1/batch/objects", p.baseURL)
-	req, err := http.NewRequestWithContext(ctx, "DELETE", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
+// CreateCollection creates a new class in Weaviate.
+//
+// The vector dimension is set by the first vector indexed.
+func (p *WeaviateProvider) CreateCollection(ctx context.Context, collection string, vectorDimension int) error {
+	_, err := p.ensureClass(ctx, weaviateClassName(collection), nil)
+	return err
+}
 
-	req.Header.Set("Content-Type", "application/json")
-	if p.apiKey != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.apiKey))
-	}
+// DeleteCollection removes a class from Weaviate.
+func (p *WeaviateProvider) DeleteCollection(ctx context.Context, collection string) error {
+	class := weaviateClassName(collection)
 
-	resp, err := p.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to delete by filter: %w", err)
-	}
-	defer resp.Body.Close()
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to delete by filter: status %d, body: %s", resp.StatusCode, string(body))
+	if _, err := p.do(ctx, http.MethodDelete, "/v1/schema/"+url.PathEscape(class), nil, nil); err != nil {
+		return fmt.Errorf("failed to delete class: %w", err)
 	}
+	delete(p.classes, class)
+	return nil
+}
 
+// Close closes idle HTTP connections.
+func (p *WeaviateProvider) Close() error {
+	p.httpClient.CloseIdleConnections()
 	return nil
 }
 
-// CreateCollection creates a new class in Weaviate.
-func (p *WeaviateProvider) CreateCollection(ctx context.Context, collection string, vectorDimension int) error {
-	// Check if class exists
-	url := fmt.Sprintf("%s/v1/schema/%s", p.baseURL, collection)
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+// ensureClass creates the class if it doesn't exist and adds properties
+// for metadata fields it lacks. It returns the class properties.
+func (p *WeaviateProvider) ensureClass(ctx context.Context, class string, metadata map[string]any) (map[string]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	props, ok := p.classes[class]
+	if !ok {
+		var exists bool
+		var err error
+		props, exists, err = p.fetchClass(ctx, class)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			if props, err = p.createClass(ctx, class, metadata); err != nil {
+				return nil, err
+			}
+		}
+		p.classes[class] = props
 	}
 
-	if p.apiKey != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.apiKey))
+	for key, value := range metadata {
+		name := weaviatePropertyName(key)
+		if _, ok := props[name]; ok {
+			continue
+		}
+		property := map[string]any{"name": name, "dataType": []string{weaviateDataType(value)}}
+		if _, err := p.do(ctx, http.MethodPost, "/v1/schema/"+url.PathEscape(class)+"/properties", property, nil); err != nil {
+			// Another process may have added it meanwhile
+			current, _, fetchErr := p.fetchClass(ctx, class)
+			if fetchErr != nil || current[name] == "" {
+				return nil, fmt.Errorf("failed to add property %q to class %s: %w", name, class, err)
+			}
+			p.classes[class] = current
+			props = current
+			continue
+		}
+		props[name] = weaviateDataType(value)
 	}
 
-	resp, err := p.httpClient.Do(req)
-	if err == nil && resp.StatusCode == http.StatusOK {
-		resp.Body.Close()
-		return nil // Class already exists
+	return props, nil
+}
+
+// createClass creates the class with properties for the metadata fields.
+// Must be called with the lock held.
+func (p *WeaviateProvider) createClass(ctx context.Context, class string, metadata map[string]any) (map[string]string, error) {
+	props := map[string]string{weaviateIDProperty: "text"}
+	for key, value := range metadata {
+		props[weaviatePropertyName(key)] = weaviateDataType(value)
+	}
+
+	properties := make([]map[string]any, 0, len(props))
+	for name, dataType := range props {
+		properties = append(properties, map[string]any{"name": name, "dataType": []string{dataType}})
 	}
 
-	// Create class schema
-	classSchema := map[string]any{
-		"class":      collection,
+	schema := map[string]any{
+		"class":      class,
 		"vectorizer": "none", // We provide vectors ourselves
-		"properties": []map[string]any{
-			{
-				"name":     "content",
-				"dataType": []string{"text"},
-			},
+		"vectorIndexConfig": map[string]any{
+			"distance": "cosine",
 		},
+		"properties": properties,
 	}
 
-	jsonData, err := json.Marshal(classSchema)
-	if err != nil {
-		return fmt.Errorf("failed to marshal schema: %w", err)
+	if _, err := p.do(ctx, http.MethodPost, "/v1/schema", schema, nil); err != nil {
+		// Another process may have created it meanwhile
+		current, exists, fetchErr := p.fetchClass(ctx, class)
+		if fetchErr != nil || !exists {
+			return nil, fmt.Errorf("failed to create class %s: %w", class, err)
+		}
+		return current, nil
 	}
+	return props, nil
+}
 
-	url = fmt.Sprintf("%s/v1/schema", p.baseURL)
-	req, err = http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
+// lookupClass returns the class properties, and whether the class exists.
+func (p *WeaviateProvider) lookupClass(ctx context.Context, class string) (map[string]string, bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-	req.Header.Set("Content-Type", "application/json")
-	if p.apiKey != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.apiKey))
+	if props, ok := p.classes[class]; ok {
+		return props, true, nil
 	}
+	props, exists, err := p.fetchClass(ctx, class)
+	if err != nil || !exists {
+		return nil, false, err
+	}
+	p.classes[class] = props
+	return props, true, nil
+}
 
-	resp, err = p.httpClient.Do(req)
+// fetchClass reads the class properties from the schema.
+func (p *WeaviateProvider) fetchClass(ctx context.Context, class string) (map[string]string, bool, error) {
+	var schema struct {
+		Properties []struct {
+			Name     string   `json:"name"`
+			DataType []string `json:"dataType"`
+		} `json:"properties"`
+	}
+	status, err := p.do(ctx, http.MethodGet, "/v1/schema/"+url.PathEscape(class), nil, &schema)
 	if err != nil {
-		return fmt.Errorf("failed to create class: %w", err)
+		return nil, false, fmt.Errorf("failed to get class %s: %w", class, err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to create class: status %d, body: %s", resp.StatusCode, string(body))
+	if status == http.StatusNotFound {
+		return nil, false, nil
 	}
 
-	return nil
+	props := make(map[string]string, len(schema.Properties))
+	for _, prop := range schema.Properties {
+		if len(prop.DataType) > 0 {
+			props[prop.Name] = prop.DataType[0]
+		}
+	}
+	return props, true, nil
 }
 
-// DeleteCollection removes a class from Weaviate.
-func (p *WeaviateProvider) DeleteCollection(ctx context.Context, collection string) error {
-	url := fmt.Sprintf("%s/v1/schema/%s", p.baseURL, collection)
-	req, err := http.NewRequestWithContext(ctx, "DELETE", url, nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+// do sends a request to the REST API and decodes the JSON response into
+// out, if given. Not found is returned as a status rather than an error.
+func (p *WeaviateProvider) do(ctx context.Context, method, path string, body, out any) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(jsonData)
 	}
 
+	req, err := http.NewRequestWithContext(ctx, method, p.baseURL+path, reader)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
 	if p.apiKey != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.apiKey))
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
 	}
 
 	resp, err := p.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to delete class: %w", err)
+		return 0, err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("failed to delete class: status %d, body: %s", resp.StatusCode, string(body))
+	if resp.StatusCode == http.StatusNotFound {
+		return resp.StatusCode, nil
 	}
-
-	return nil
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, fmt.Errorf("status %d, body: %s", resp.StatusCode, string(respBody))
+	}
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, fmt.Errorf("failed to decode response: %w", err)
+		}
+	}
+	return resp.StatusCode, nil
 }
 
-// Close closes the HTTP client.
-func (p *WeaviateProvider) Close() error {
-	return nil
+// weaviateObjectID returns the object UUID for a document ID. IDs that
+// are UUIDs already are used as-is.
+func weaviateObjectID(id string) string {
+	if parsed, err := uuid.Parse(id); err == nil {
+		return parsed.String()
+	}
+	return uuid.NewSHA1(weaviateIDNamespace, []byte(id)).String()
 }
 
-// buildWeaviateWhereClause converts a filter map to Weaviate where clause.
-func buildWeaviateWhereClause(filter map[string]any) map[string]any {
-	if len(filter) == 0 {
-		return nil
+// weaviateClassName maps a collection name to a valid class name: an upper
+// case letter followed by letters, digits and underscores.
+func weaviateClassName(collection string) string {
+	name := weaviateName(collection)
+	if c := name[0]; c < 'A' || c > 'z' || (c > 'Z' && c < 'a') {
+		name = "Hector" + name
 	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
 
-	var conditions []map[string]any
-	for key, value := range filter {
-		conditions = append(conditions, map[string]any{
-			"path":        []string{key},
-			"operator":    "Equal",
-			"valueString": fmt.Sprintf("%v", value),
-		})
+// weaviatePropertyName maps a metadata key to a valid property name.
+func weaviatePropertyName(key string) string {
+	name := weaviateName(key)
+	if name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
 	}
+	return name
+}
 
-	if len(conditions) == 1 {
-		return conditions[0]
+// weaviateName replaces characters Weaviate names can't hold with
+// underscores.
+func weaviateName(s string) string {
+	if s == "" {
+		return "_"
+	}
+	b := []byte(s)
+	for i, c := range b {
+		if !(c == '_' || (c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')) {
+			b[i] = '_'
+		}
 	}
+	return string(b)
+}
 
-	return map[string]any{
-		"operator": "And",
-		"operands": conditions,
+// weaviateScalarTypes are the property types selectable without a
+// sub-selection, with or without the array suffix.
+var weaviateScalarTypes = map[string]bool{
+	"text":    true,
+	"string":  true,
+	"int":     true,
+	"number":  true,
+	"boolean": true,
+	"date":    true,
+	"uuid":    true,
+}
+
+// weaviateDataType returns the property type for a metadata value.
+func weaviateDataType(value any) string {
+	switch value.(type) {
+	case string:
+		return "text"
+	case bool:
+		return "boolean"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return "int"
+	case float32, float64:
+		return "number"
+	case []string:
+		return "text[]"
+	case time.Time:
+		return "date"
+	default:
+		return "text"
 	}
 }
 
-// convertWeaviateResults converts Weaviate GraphQL response to our Result type.
-func convertWeaviateResults(result map[string]any, collection string) []Result {
-	if result == nil {
-		return []Result{}
+// weaviateValue converts a metadata value for a property of the data type.
+// Values without a matching type are stored as JSON text.
+func weaviateValue(value any, dataType string) any {
+	if _, ok := value.(string); ok || dataType != "text" || value == nil {
+		return value
 	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
 
-	data, ok := result["data"].(map[string]any)
-	if !ok {
-		return []Result{}
+// hasWeaviateProperties reports whether the class has every filter field.
+func hasWeaviateProperties(props map[string]string, filter map[string]any) bool {
+	for key := range filter {
+		if _, ok := props[weaviatePropertyName(key)]; !ok {
+			return false
+		}
 	}
+	return true
+}
 
-	get, ok := data["Get"].(map[string]any)
-	if !ok {
-		return []Result{}
+// weaviateCondition is an equality condition on a property.
+type weaviateCondition struct {
+	path       string
+	valueField string
+	value      any
+}
+
+// weaviateConditions converts a filter map to conditions, in key order.
+func weaviateConditions(filter map[string]any) []weaviateCondition {
+	keys := make([]string, 0, len(filter))
+	for key := range filter {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	conditions := make([]weaviateCondition, 0, len(keys))
+	for _, key := range keys {
+		cond := weaviateCondition{path: weaviatePropertyName(key), value: filter[key]}
+		switch v := filter[key].(type) {
+		case string:
+			cond.valueField = "valueText"
+		case bool:
+			cond.valueField = "valueBoolean"
+		case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+			cond.valueField = "valueInt"
+		case float32, float64:
+			cond.valueField = "valueNumber"
+		default:
+			cond.valueField = "valueText"
+			cond.value = fmt.Sprint(v)
+		}
+		conditions = append(conditions, cond)
 	}
+	return conditions
+}
 
-	classData, ok := get[collection].([]any)
-	if !ok {
-		return []Result{}
+// buildWeaviateWhereClause converts a filter map to a REST where clause.
+func buildWeaviateWhereClause(filter map[string]any) map[string]any {
+	var operands []map[string]any
+	for _, cond := range weaviateConditions(filter) {
+		operands = append(operands, map[string]any{
+			"path":          []string{cond.path},
+			"operator":      "Equal",
+			cond.valueField: cond.value,
+		})
 	}
 
-	results := make([]Result, 0, len(classData))
-	for _, obj := range classData {
-		objMap, ok := obj.(map[string]any)
-		if !ok {
-			continue
-		}
+	if len(operands) == 1 {
+		return operands[0]
+	}
+	return map[string]any{
+		"operator": "And",
+		"operands": operands,
+	}
+}
 
-		additional, _ := objMap["_additional"].(map[string]any)
-		id := ""
-		if idVal, ok := additional["id"].(string); ok {
-			id = idVal
-		}
+// weaviateWhereGraphQL converts a filter map to a GraphQL where argument.
+func weaviateWhereGraphQL(filter map[string]any) string {
+	var operands []string
+	for _, cond := range weaviateConditions(filter) {
+		// JSON literals are valid GraphQL literals
+		path, _ := json.Marshal(cond.path)
+		value, _ := json.Marshal(cond.value)
+		operands = append(operands, fmt.Sprintf("{path: [%s], operator: Equal, %s: %s}", path, cond.valueField, value))
+	}
 
-		var score float32
-		if certainty, ok := additional["certainty"].(float64); ok {
-			score = float32(certainty)
-		} else if distance, ok := additional["distance"].(float64); ok {
-			score = float32(1.0 - distance)
-		} else if scoreVal, ok := additional["score"].(float64); ok {
-			score = float32(scoreVal)
-		}
+	if len(operands) == 1 {
+		return operands[0]
+	}
+	return fmt.Sprintf("{operator: And, operands: [%s]}", strings.Join(operands, ", "))
+}
 
-		content := ""
-		if contentVal, ok := objMap["content"].(string); ok {
-			content = contentVal
+// convertWeaviateResults converts Weaviate GraphQL objects to our Result
+// type, keeping the order of increasing distance.
+func convertWeaviateResults(objects []map[string]any) []Result {
+	results := make([]Result, 0, len(objects))
+	for _, obj := range objects {
+		additional, _ := obj["_additional"].(map[string]any)
+
+		result := Result{Metadata: make(map[string]any)}
+		result.ID, _ = additional["id"].(string)
+		if distance, ok := additional["distance"].(float64); ok {
+			// Cosine distance to cosine similarity
+			result.Score = float32(1.0 - distance)
 		}
 
-		metadata := make(map[string]any)
-		for k, v := range objMap {
-			if k != "_additional" {
-				metadata[k] = v
+		for key, value := range obj {
+			switch {
+			case key == "_additional" || value == nil:
+			case key == weaviateIDProperty:
+				if id, ok := value.(string); ok && id != "" {
+					result.ID = id
+				}
+			default:
+				result.Metadata[key] = value
 			}
 		}
+		result.Content, _ = result.Metadata["content"].(string)
 
-		results = append(results, Result{
-			ID:       id,
-			Content:  content,
-			Score:    score,
-			Metadata: metadata,
-		})
+		results = append(results, result)
 	}
-
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].Score > results[j].Score
-	})
-
 	return results
 }
 